@@ -0,0 +1,115 @@
+// Package audit records security-relevant actions (such as changes to the site configuration or
+// promotions to site admin) in the audit log, and exports them to the destinations configured in
+// the site configuration.
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/inconshreveable/log15"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/httputil"
+)
+
+// Actions recorded in the audit log.
+const (
+	ActionSiteConfigUpdate      = "site_config.update"
+	ActionUserSiteAdminUpdate   = "user.site_admin.update"
+	ActionUserSudo              = "user.sudo"
+	ActionUserLock              = "user.lock"
+	ActionUserTOTPEnable        = "user.totp.enable"
+	ActionUserTOTPDisable       = "user.totp.disable"
+	ActionUserSecurityKeyCreate = "user.security_key.create"
+	ActionUserSecurityKeyDelete = "user.security_key.delete"
+	ActionUserRecoveryCodesNew  = "user.recovery_codes.new"
+	ActionUserSessionRevoke     = "user.session.revoke"
+	ActionUserSessionRevokeAll  = "user.session.revoke_all"
+	ActionAccessTokenCreate     = "access_token.create"
+	ActionAccessTokenDelete     = "access_token.delete"
+	ActionExternalServiceCreate = "external_service.create"
+	ActionExternalServiceUpdate = "external_service.update"
+	ActionExternalServiceDelete = "external_service.delete"
+)
+
+// Types of the entities that actions are performed on.
+const (
+	TargetSiteConfig      = "site_config"
+	TargetUser            = "user"
+	TargetAccessToken     = "access_token"
+	TargetExternalService = "external_service"
+)
+
+type clientKey struct{}
+
+// client describes the HTTP client that performed an action.
+type client struct {
+	ip        string
+	userAgent string
+}
+
+// WithClient returns a copy of the context that records the IP address and user agent of the HTTP
+// client performing actions with it.
+func WithClient(ctx context.Context, ip, userAgent string) context.Context {
+	return context.WithValue(ctx, clientKey{}, &client{ip: ip, userAgent: userAgent})
+}
+
+// Middleware records the IP address and user agent of the client of each request in the request
+// context, so that they are included in the audit log entries of actions performed while handling
+// the request.
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, r.WithContext(WithClient(r.Context(), httputil.ClientIP(r), r.UserAgent())))
+	})
+}
+
+// Log records in the audit log an action performed by the actor of the context on the entity with
+// the given type and ID.
+//
+// The states of the entity before and after the action are recorded as JSON, with the values of
+// secrets (such as tokens and passwords) redacted. Either state may be nil.
+//
+// Errors are logged instead of being returned because the action has already been performed when
+// it's recorded.
+func Log(ctx context.Context, action, targetType, targetID string, before, after interface{}) {
+	e := &db.AuditLogEntry{
+		Action:      action,
+		ActorUserID: actor.FromContext(ctx).UID,
+		TargetType:  targetType,
+		TargetID:    targetID,
+	}
+	if c, ok := ctx.Value(clientKey{}).(*client); ok {
+		e.ActorIP = c.ip
+		e.ActorUserAgent = c.userAgent
+	}
+
+	var err error
+	if e.Before, err = redactedJSON(before); err != nil {
+		log15.Error("Failed to marshal state for the audit log.", "action", action, "error", err)
+	}
+	if e.After, err = redactedJSON(after); err != nil {
+		log15.Error("Failed to marshal state for the audit log.", "action", action, "error", err)
+	}
+
+	if err := db.AuditLogs.Create(ctx, e); err != nil {
+		log15.Error("Failed to record action in the audit log.", "action", action, "targetType", targetType, "targetID", targetID, "error", err)
+	}
+}
+
+// redactedJSON marshals v to JSON with the values of secrets redacted. It returns nil if v is nil.
+func redactedJSON(v interface{}) (json.RawMessage, error) {
+	if v == nil {
+		return nil, nil
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var doc interface{}
+	if err := json.Unmarshal(b, &doc); err != nil {
+		return nil, err
+	}
+	return json.Marshal(redact(doc))
+}
@@ -0,0 +1,191 @@
+package audit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/inconshreveable/log15"
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+// exportedEntry is the JSON representation of an exported audit log entry.
+type exportedEntry struct {
+	ID             int64           `json:"id"`
+	Action         string          `json:"action"`
+	ActorUserID    int32           `json:"actorUserID,omitempty"`
+	ActorIP        string          `json:"actorIP,omitempty"`
+	ActorUserAgent string          `json:"actorUserAgent,omitempty"`
+	TargetType     string          `json:"targetType"`
+	TargetID       string          `json:"targetID"`
+	Before         json.RawMessage `json:"before,omitempty"`
+	After          json.RawMessage `json:"after,omitempty"`
+	CreatedAt      time.Time       `json:"createdAt"`
+}
+
+const (
+	// maxExportBatchSize is the maximum number of entries exported at once.
+	maxExportBatchSize = 100
+	// exportInterval is how often entries are exported.
+	exportInterval = 10 * time.Second
+)
+
+// exportConfig returns the export configuration of the audit log, or nil if entries aren't
+// exported.
+func exportConfig() *schema.AuditLogExport {
+	cfg := conf.Get().AuditLog
+	if cfg == nil || cfg.Export == nil || (cfg.Export.File == "" && cfg.Export.Url == "") {
+		return nil
+	}
+	return cfg.Export
+}
+
+// sink is a destination that audit log entries are exported to.
+type sink struct {
+	// name identifies the sink's cursor in the database. It includes the destination, so that
+	// pointing the export to a new destination starts with new entries.
+	name  string
+	write func(ctx context.Context, body []byte) error
+}
+
+// sinks returns the destinations configured in cfg.
+func sinks(cli httpcli.Doer, cfg *schema.AuditLogExport) []sink {
+	var sinks []sink
+	if cfg.File != "" {
+		sinks = append(sinks, sink{
+			name:  "file:" + cfg.File,
+			write: func(_ context.Context, body []byte) error { return writeFile(cfg.File, body) },
+		})
+	}
+	if cfg.Url != "" {
+		sinks = append(sinks, sink{
+			name: "url:" + cfg.Url,
+			write: func(ctx context.Context, body []byte) error {
+				return post(ctx, cli, cfg.Url, cfg.AuthorizationHeader, body)
+			},
+		})
+	}
+	return sinks
+}
+
+// Export exports the entries of the audit log to the destinations configured in the site
+// configuration, until ctx is canceled.
+//
+// Entries are read from the database, and each destination keeps track of the last entry exported
+// to it, so entries aren't lost if a destination is unavailable or the process restarts, and a
+// failing destination doesn't hold up (or cause duplicates in) the others. It's safe to run Export
+// in several processes: only one exports to a given destination at a time.
+func Export(ctx context.Context) {
+	cli, err := httpcli.NewExternalHTTPClientFactory().Doer()
+	if err != nil {
+		log15.Error("Failed to create HTTP client for audit log export.", "error", err)
+		return
+	}
+
+	for {
+		if cfg := exportConfig(); cfg != nil {
+			for _, s := range sinks(cli, cfg) {
+				if err := exportToSink(ctx, s); err != nil {
+					log15.Error("Failed to export audit log entries, will retry.", "sink", s.name, "error", err)
+				}
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(exportInterval):
+		}
+	}
+}
+
+// exportToSink exports the entries that haven't been exported to the sink yet, in batches.
+func exportToSink(ctx context.Context, s sink) error {
+	for {
+		var n int
+		err := db.AuditLogs.Export(ctx, s.name, maxExportBatchSize, func(entries []*db.AuditLogEntry) error {
+			n = len(entries)
+			body, err := marshalEntries(entries)
+			if err != nil {
+				return err
+			}
+			return s.write(ctx, body)
+		})
+		if err != nil || n < maxExportBatchSize {
+			return err
+		}
+	}
+}
+
+// marshalEntries returns the JSON lines of the entries.
+func marshalEntries(entries []*db.AuditLogEntry) ([]byte, error) {
+	var body bytes.Buffer
+	for _, e := range entries {
+		line, err := json.Marshal(&exportedEntry{
+			ID:             e.ID,
+			Action:         e.Action,
+			ActorUserID:    e.ActorUserID,
+			ActorIP:        e.ActorIP,
+			ActorUserAgent: e.ActorUserAgent,
+			TargetType:     e.TargetType,
+			TargetID:       e.TargetID,
+			Before:         e.Before,
+			After:          e.After,
+			CreatedAt:      e.CreatedAt,
+		})
+		if err != nil {
+			return nil, errors.Wrapf(err, "marshaling audit log entry %d", e.ID)
+		}
+		body.Write(line)
+		body.WriteByte('\n')
+	}
+	return body.Bytes(), nil
+}
+
+// writeFile appends the JSON lines to the file.
+func writeFile(name string, body []byte) error {
+	f, err := os.OpenFile(name, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		return errors.Wrap(err, "opening audit log export file")
+	}
+	_, err = f.Write(body)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return errors.Wrap(err, "writing audit log export file")
+	}
+	return nil
+}
+
+// post sends the JSON lines to the URL.
+func post(ctx context.Context, cli httpcli.Doer, url, authorizationHeader string, body []byte) error {
+	req, err := http.NewRequest("POST", url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-ndjson")
+	if authorizationHeader != "" {
+		req.Header.Set("Authorization", authorizationHeader)
+	}
+
+	resp, err := cli.Do(req.WithContext(ctx))
+	if err != nil {
+		return errors.Wrap(err, "posting audit log entries")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		b, _ := ioutil.ReadAll(resp.Body)
+		return errors.Errorf("audit log export endpoint returned status %d: %s", resp.StatusCode, bytes.TrimSpace(b))
+	}
+	return nil
+}
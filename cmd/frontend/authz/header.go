@@ -11,14 +11,15 @@ import (
 const (
 	SchemeToken     = "token"      // Scheme for Authorization header with only an access token
 	SchemeTokenSudo = "token-sudo" // Scheme for Authorization header with access token and sudo user
+	SchemeBearer    = "Bearer"     // Scheme for Authorization header with only an access token, as sent by e.g. SCIM clients
 )
 
 // errUnrecognizedScheme occurs when the Authorization header scheme (the first token) is not
 // recognized.
-var errUnrecognizedScheme = fmt.Errorf("unrecognized HTTP Authorization request header scheme (supported values: %q, %q)", SchemeToken, SchemeTokenSudo)
+var errUnrecognizedScheme = fmt.Errorf("unrecognized HTTP Authorization request header scheme (supported values: %q, %q, %q)", SchemeToken, SchemeTokenSudo, SchemeBearer)
 
 // IsUnrecognizedScheme reports whether err indicates that the request's Authorization header scheme
-// is unrecognized or unparseable (i.e., is neither "token" nor "token-sudo").
+// is unrecognized or unparseable (i.e., is neither "token", "token-sudo" nor "Bearer").
 func IsUnrecognizedScheme(err error) bool {
 	return err == errUnrecognizedScheme || err == errHTTPAuthParamsDuplicateKey || err == errHTTPAuthParamsNoEquals
 }
@@ -29,7 +30,8 @@ func IsUnrecognizedScheme(err error) bool {
 // Two forms of the Authorization header's "credentials" token are supported (see [RFC 7235,
 // Appendix C](https://tools.ietf.org/html/rfc7235#appendix-C):
 //
-// - With only an access token: "token" 1*SP token68
+// - With only an access token: "token" 1*SP token68 (or "Bearer" 1*SP token68, see [RFC 6750,
+//   Section 2.1](https://tools.ietf.org/html/rfc6750#section-2.1))
 // - With a token as params:
 //   "token" 1*SP "token" BWS "=" BWS quoted-string
 //
@@ -41,6 +43,12 @@ func ParseAuthorizationHeader(headerValue string) (token, sudoUser string, err e
 		return "", "", err
 	}
 
+	if strings.EqualFold(scheme, SchemeBearer) {
+		if token68 == "" {
+			return "", "", errors.New("no token value in the HTTP Authorization request header")
+		}
+		return token68, "", nil
+	}
 	if scheme != SchemeToken && scheme != SchemeTokenSudo {
 		return "", "", errUnrecognizedScheme
 	}
@@ -18,6 +18,9 @@ func TestParseAuthorizationHeader(t *testing.T) {
 		`token-sudo token="tok==", user="alice"`: {token: "tok==", sudoUser: "alice"},
 		`token-sudo token=tok, user="alice"`:     {token: "tok", sudoUser: "alice"},
 		`token-sudo token="tok==", user=alice`:   {token: "tok==", sudoUser: "alice"},
+		"Bearer tok==":                           {token: "tok=="},
+		"bearer tok":                             {token: "tok"},
+		"Bearer":                                 {err: true},
 		"xyz tok":                                {err: true},
 		`token-sudo user="alice"`:                {err: true},
 		`token-sudo token="",user="alice"`:       {err: true},
@@ -220,3 +220,39 @@ func (p *UserPendingPermissions) TracingFields() []otlog.Field {
 
 	return fs
 }
+
+// UserGroupPermissions defines the organization memberships and repository
+// permissions that a user has through their groups on an authentication
+// provider (e.g. SAML or OpenID Connect), as mapped by the provider's
+// "groupMappings". They are re-evaluated each time the user signs in with the
+// provider.
+type UserGroupPermissions struct {
+	// The user ID.
+	UserID int32
+	// The type of the authentication provider as if it would be used as
+	// extsvc.AccountSpec.ServiceType, e.g. "saml" and "openidconnect".
+	ServiceType string
+	// The ID of the authentication provider as if it would be used as
+	// extsvc.AccountSpec.ServiceID.
+	ServiceID string
+	// The groups of the user that are mapped by the authentication provider.
+	Groups []string
+	// The IDs of the organizations that the user is a member of through the groups.
+	OrgIDs []int32
+	// The regular expressions matching the names of the repositories that the
+	// user can read through the groups.
+	RepoPatterns []string
+	// The last updated time.
+	UpdatedAt time.Time
+}
+
+// TracingFields returns tracing fields for the opentracing log.
+func (p *UserGroupPermissions) TracingFields() []otlog.Field {
+	return []otlog.Field{
+		otlog.Int32("UserGroupPermissions.UserID", p.UserID),
+		otlog.String("UserGroupPermissions.ServiceType", p.ServiceType),
+		otlog.String("UserGroupPermissions.ServiceID", p.ServiceID),
+		otlog.Int("UserGroupPermissions.Groups.Count", len(p.Groups)),
+		otlog.Int("UserGroupPermissions.RepoPatterns.Count", len(p.RepoPatterns)),
+	}
+}
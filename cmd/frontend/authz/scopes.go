@@ -2,12 +2,29 @@ package authz
 
 const (
 	// Access token scopes.
-	ScopeUserAll       = "user:all"        // Full control of all resources accessible to the user account.
-	ScopeSiteAdminSudo = "site-admin:sudo" // Ability to perform any action as any other user.
+	ScopeUserAll         = "user:all"         // Full control of all resources accessible to the user account.
+	ScopeSiteAdminSudo   = "site-admin:sudo"  // Ability to perform any action as any other user.
+	ScopeSearchRead      = "search:read"      // Ability to run searches.
+	ScopeRepoRead        = "repo:read"        // Ability to read repositories and their contents.
+	ScopeCampaignsWrite  = "campaigns:write"  // Ability to view, create and update campaigns.
+	ScopeCodeIntelUpload = "codeintel:upload" // Ability to upload LSIF data.
 )
 
 // AllScopes is a list of all known access token scopes.
 var AllScopes = []string{
 	ScopeUserAll,
 	ScopeSiteAdminSudo,
+	ScopeSearchRead,
+	ScopeRepoRead,
+	ScopeCampaignsWrite,
+	ScopeCodeIntelUpload,
+}
+
+// FineGrainedScopes is the list of access token scopes that grant access to a subset of the
+// user's privileges. An access token must have at least one of them or ScopeUserAll.
+var FineGrainedScopes = []string{
+	ScopeSearchRead,
+	ScopeRepoRead,
+	ScopeCampaignsWrite,
+	ScopeCodeIntelUpload,
 }
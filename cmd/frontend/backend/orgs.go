@@ -4,21 +4,32 @@ import (
 	"context"
 	"errors"
 
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/authz"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
 	"github.com/sourcegraph/sourcegraph/internal/errcode"
 )
 
 var ErrNotAuthenticated = errors.New("not authenticated")
 
 // CheckOrgAccess returns an error if the user is NEITHER (1) a site admin NOR (2) a
-// member of the organization with the specified ID.
+// member of the organization with the specified ID whose role includes the given role.
 //
 // It is used when an action on a user can be performed by site admins and the organization's
 // members, but nobody else.
-func CheckOrgAccess(ctx context.Context, orgID int32) error {
+func CheckOrgAccess(ctx context.Context, orgID int32, role types.OrgRole) error {
+	return CheckOrgAccessWithScope(ctx, orgID, role, authz.ScopeUserAll)
+}
+
+// CheckOrgAccessWithScope is like CheckOrgAccess, but also accepts access tokens that have the
+// given scope instead of the "user:all" scope.
+func CheckOrgAccessWithScope(ctx context.Context, orgID int32, role types.OrgRole, scope string) error {
 	if hasAuthzBypass(ctx) {
 		return nil
 	}
+	if err := CheckAccessTokenScope(ctx, scope); err != nil {
+		return err
+	}
 	currentUser, err := CurrentUser(ctx)
 	if err != nil {
 		return err
@@ -29,12 +40,14 @@ func CheckOrgAccess(ctx context.Context, orgID int32) error {
 	if currentUser.SiteAdmin {
 		return nil
 	}
-	return checkUserIsOrgMember(ctx, currentUser.ID, orgID)
+	return checkUserOrgRole(ctx, currentUser.ID, orgID, role)
 }
 
 var ErrNotAnOrgMember = errors.New("current user is not an org member")
 
-func checkUserIsOrgMember(ctx context.Context, userID, orgID int32) error {
+var ErrInsufficientOrgRole = errors.New("current user's organization role is insufficient")
+
+func checkUserOrgRole(ctx context.Context, userID, orgID int32, role types.OrgRole) error {
 	resp, err := db.OrgMembers.GetByOrgIDAndUserID(ctx, orgID, userID)
 	if err != nil {
 		if errcode.IsNotFound(err) {
@@ -47,5 +60,8 @@ func checkUserIsOrgMember(ctx context.Context, userID, orgID int32) error {
 	if resp == nil {
 		return ErrNotAnOrgMember
 	}
+	if !resp.Role.Includes(role) {
+		return ErrInsufficientOrgRole
+	}
 	return nil
 }
@@ -0,0 +1,95 @@
+package backend
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/authz"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+)
+
+func TestCheckOrgAccess(t *testing.T) {
+	tests := []struct {
+		name      string
+		siteAdmin bool
+		member    *types.OrgMembership
+		role      types.OrgRole
+		wantErr   error
+	}{
+		{
+			name:      "site admin",
+			siteAdmin: true,
+			role:      types.OrgRoleOwner,
+		},
+		{
+			name:    "not a member",
+			role:    types.OrgRoleReadOnly,
+			wantErr: ErrNotAnOrgMember,
+		},
+		{
+			name:   "owner",
+			member: &types.OrgMembership{OrgID: 1, UserID: 1, Role: types.OrgRoleOwner},
+			role:   types.OrgRoleAdmin,
+		},
+		{
+			name:   "admin",
+			member: &types.OrgMembership{OrgID: 1, UserID: 1, Role: types.OrgRoleAdmin},
+			role:   types.OrgRoleAdmin,
+		},
+		{
+			name:    "member requiring admin",
+			member:  &types.OrgMembership{OrgID: 1, UserID: 1, Role: types.OrgRoleMember},
+			role:    types.OrgRoleAdmin,
+			wantErr: ErrInsufficientOrgRole,
+		},
+		{
+			name:    "read-only requiring member",
+			member:  &types.OrgMembership{OrgID: 1, UserID: 1, Role: types.OrgRoleReadOnly},
+			role:    types.OrgRoleMember,
+			wantErr: ErrInsufficientOrgRole,
+		},
+		{
+			name:   "read-only requiring read-only",
+			member: &types.OrgMembership{OrgID: 1, UserID: 1, Role: types.OrgRoleReadOnly},
+			role:   types.OrgRoleReadOnly,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := testContext()
+			db.Mocks.Users.GetByCurrentAuthUser = func(context.Context) (*types.User, error) {
+				return &types.User{ID: 1, SiteAdmin: test.siteAdmin}, nil
+			}
+			db.Mocks.OrgMembers.GetByOrgIDAndUserID = func(ctx context.Context, orgID, userID int32) (*types.OrgMembership, error) {
+				if test.member == nil {
+					return nil, &db.ErrOrgMemberNotFound{}
+				}
+				return test.member, nil
+			}
+
+			if err := CheckOrgAccess(ctx, 1, test.role); err != test.wantErr {
+				t.Errorf("got err %v, want %v", err, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestCheckOrgAccessWithScope(t *testing.T) {
+	ctx := testContext()
+	db.Mocks.Users.GetByCurrentAuthUser = func(context.Context) (*types.User, error) {
+		return &types.User{ID: 1}, nil
+	}
+	db.Mocks.OrgMembers.GetByOrgIDAndUserID = func(ctx context.Context, orgID, userID int32) (*types.OrgMembership, error) {
+		return &types.OrgMembership{OrgID: orgID, UserID: userID, Role: types.OrgRoleAdmin}, nil
+	}
+	ctx = actor.WithActor(ctx, &actor.Actor{UID: 1, Scopes: []string{authz.ScopeCampaignsWrite}})
+
+	if err := CheckOrgAccessWithScope(ctx, 1, types.OrgRoleAdmin, authz.ScopeCampaignsWrite); err != nil {
+		t.Errorf("got err %v, want nil", err)
+	}
+	if err := CheckOrgAccess(ctx, 1, types.OrgRoleAdmin); !IsAccessTokenScopeError(err) {
+		t.Errorf("got err %v, want access token scope error", err)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/authz"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
 	"github.com/sourcegraph/sourcegraph/internal/actor"
@@ -13,11 +14,22 @@ import (
 
 var ErrMustBeSiteAdmin = errors.New("must be site admin")
 
-// CheckCurrentUserIsSiteAdmin returns an error if the current user is NOT a site admin.
+// CheckCurrentUserIsSiteAdmin returns an error if the current user is NOT a site admin, or if the
+// current user was authenticated with an access token that doesn't have the "user:all" scope.
 func CheckCurrentUserIsSiteAdmin(ctx context.Context) error {
+	return CheckCurrentUserIsSiteAdminWithScope(ctx, authz.ScopeUserAll)
+}
+
+// CheckCurrentUserIsSiteAdminWithScope returns an error if the current user is NOT a site admin, or
+// if the current user was authenticated with an access token that has neither the given scope nor
+// the "user:all" scope.
+func CheckCurrentUserIsSiteAdminWithScope(ctx context.Context, scope string) error {
 	if hasAuthzBypass(ctx) {
 		return nil
 	}
+	if err := CheckAccessTokenScope(ctx, scope); err != nil {
+		return err
+	}
 	user, err := CurrentUser(ctx)
 	if err != nil {
 		return err
@@ -58,6 +70,34 @@ type InsufficientAuthorizationError struct {
 
 func (e *InsufficientAuthorizationError) Error() string { return e.Message }
 
+// CheckAccessTokenScope returns an error if the current user was authenticated with an access token
+// that has neither the given scope nor the "user:all" scope.
+func CheckAccessTokenScope(ctx context.Context, scope string) error {
+	if hasAuthzBypass(ctx) {
+		return nil
+	}
+	if !actor.FromContext(ctx).HasScope(scope) {
+		return &AccessTokenScopeError{Scope: scope}
+	}
+	return nil
+}
+
+// AccessTokenScopeError is an error that occurs when the current user was authenticated with an
+// access token that lacks the scope required to perform a certain action.
+type AccessTokenScopeError struct {
+	Scope string
+}
+
+func (e *AccessTokenScopeError) Error() string {
+	return fmt.Sprintf("access token must have scope %q", e.Scope)
+}
+
+// IsAccessTokenScopeError reports whether err is an *AccessTokenScopeError.
+func IsAccessTokenScopeError(err error) bool {
+	_, ok := err.(*AccessTokenScopeError)
+	return ok
+}
+
 // CheckSiteAdminOrSameUser returns an error if the user is NEITHER (1) a
 // site admin NOR (2) the user specified by subjectUserID.
 //
@@ -69,6 +109,9 @@ func CheckSiteAdminOrSameUser(ctx context.Context, subjectUserID int32) error {
 	if hasAuthzBypass(ctx) {
 		return nil
 	}
+	if err := CheckAccessTokenScope(ctx, authz.ScopeUserAll); err != nil {
+		return err
+	}
 	actor := actor.FromContext(ctx)
 	if actor.IsAuthenticated() && actor.UID == subjectUserID {
 		return nil
@@ -6,6 +6,9 @@ import (
 	"crypto/sha256"
 	"database/sql"
 	"encoding/hex"
+	"net"
+	"regexp"
+	"strings"
 	"time"
 
 	"github.com/keegancsmith/sqlf"
@@ -24,6 +27,76 @@ type AccessToken struct {
 	CreatorUserID int32
 	CreatedAt     time.Time
 	LastUsedAt    *time.Time
+
+	AccessTokenRestrictions
+}
+
+// AccessTokenRestrictions restricts when, from where and for which repositories an access token
+// can be used. The zero value doesn't restrict the access token.
+type AccessTokenRestrictions struct {
+	ExpiresAt    *time.Time // the token can't be used after this time
+	AllowedIPs   []string   // IP addresses and CIDR ranges the token can be used from (any if empty)
+	RepoPatterns []string   // regular expressions matching the whole names of the repositories the token can access (all if empty)
+}
+
+// AllowsIP reports whether the access token can be used from the given IP address.
+func (r *AccessTokenRestrictions) AllowsIP(ip net.IP) bool {
+	if len(r.AllowedIPs) == 0 {
+		return true
+	}
+	if ip == nil {
+		return false
+	}
+	for _, allowed := range r.AllowedIPs {
+		if strings.Contains(allowed, "/") {
+			if _, ipNet, err := net.ParseCIDR(allowed); err == nil && ipNet.Contains(ip) {
+				return true
+			}
+		} else if allowedIP := net.ParseIP(allowed); allowedIP != nil && allowedIP.Equal(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// RepoPattern returns a regular expression matching the names of the repositories the access
+// token can access, or nil if the access token is not restricted to some repositories.
+//
+// 🚨 SECURITY: Each repository pattern must match the whole name of a repository, so that e.g.
+// "github.com/acme/api" doesn't match "github.com/acme/api-secrets". The patterns are compiled on
+// their own first, so that patterns such as "a)|(?:b" can't escape the anchors.
+func (r *AccessTokenRestrictions) RepoPattern() (*regexp.Regexp, error) {
+	if len(r.RepoPatterns) == 0 {
+		return nil, nil
+	}
+	anchored := make([]string, 0, len(r.RepoPatterns))
+	for _, pattern := range r.RepoPatterns {
+		if _, err := regexp.Compile(pattern); err != nil {
+			return nil, errors.Wrapf(err, "invalid repository pattern %q", pattern)
+		}
+		anchored = append(anchored, "^(?:"+pattern+")$")
+	}
+	return regexp.Compile(strings.Join(anchored, "|"))
+}
+
+// Validate returns an error if the restrictions contain malformed IP addresses, CIDR ranges or
+// regular expressions.
+func (r *AccessTokenRestrictions) Validate() error {
+	for _, allowed := range r.AllowedIPs {
+		if strings.Contains(allowed, "/") {
+			if _, _, err := net.ParseCIDR(allowed); err != nil {
+				return errors.Errorf("invalid CIDR range %q", allowed)
+			}
+		} else if net.ParseIP(allowed) == nil {
+			return errors.Errorf("invalid IP address %q", allowed)
+		}
+	}
+	for _, pattern := range r.RepoPatterns {
+		if _, err := regexp.Compile(pattern); err != nil {
+			return errors.Wrapf(err, "invalid repository pattern %q", pattern)
+		}
+	}
+	return nil
 }
 
 // ErrAccessTokenNotFound occurs when a database operation expects a specific access token to exist
@@ -46,11 +119,13 @@ type accessTokens struct{}
 // space; also bcrypt is slow and would add noticeable latency to each request that supplied a
 // token.
 //
+// The restrictions limit when, from where and for which repositories the token can be used.
+//
 // 🚨 SECURITY: The caller must ensure that the actor is permitted to create tokens for the
 // specified user (i.e., that the actor is either the user or a site admin).
-func (s *accessTokens) Create(ctx context.Context, subjectUserID int32, scopes []string, note string, creatorUserID int32) (id int64, token string, err error) {
+func (s *accessTokens) Create(ctx context.Context, subjectUserID int32, scopes []string, note string, creatorUserID int32, restrictions AccessTokenRestrictions) (id int64, token string, err error) {
 	if Mocks.AccessTokens.Create != nil {
-		return Mocks.AccessTokens.Create(subjectUserID, scopes, note, creatorUserID)
+		return Mocks.AccessTokens.Create(subjectUserID, scopes, note, creatorUserID, restrictions)
 	}
 
 	var b [20]byte
@@ -65,6 +140,16 @@ func (s *accessTokens) Create(ctx context.Context, subjectUserID int32, scopes [
 		return 0, "", errors.New("access tokens without scopes are not supported")
 	}
 
+	if err := restrictions.Validate(); err != nil {
+		return 0, "", err
+	}
+	if restrictions.AllowedIPs == nil {
+		restrictions.AllowedIPs = []string{}
+	}
+	if restrictions.RepoPatterns == nil {
+		restrictions.RepoPatterns = []string{}
+	}
+
 	if err := dbconn.Global.QueryRowContext(ctx,
 		// Include users table query (with "FOR UPDATE") to ensure that subject/creator users have
 		// not been deleted. If they were deleted, the query will return an error.
@@ -76,60 +161,60 @@ creator_user AS (
   SELECT id FROM users WHERE id=$5 AND deleted_at IS NULL FOR UPDATE
 ),
 insert_values AS (
-  SELECT subject_user.id AS subject_user_id, $2::text[] AS scopes, $3::bytea AS value_sha256, $4::text AS note, creator_user.id AS creator_user_id
+  SELECT subject_user.id AS subject_user_id, $2::text[] AS scopes, $3::bytea AS value_sha256, $4::text AS note, creator_user.id AS creator_user_id,
+    $6::timestamptz AS expires_at, $7::text[] AS allowed_ips, $8::text[] AS repo_patterns
   FROM subject_user, creator_user
 )
-INSERT INTO access_tokens(subject_user_id, scopes, value_sha256, note, creator_user_id) SELECT * FROM insert_values RETURNING id
+INSERT INTO access_tokens(subject_user_id, scopes, value_sha256, note, creator_user_id, expires_at, allowed_ips, repo_patterns) SELECT * FROM insert_values RETURNING id
 `,
 		subjectUserID, pq.Array(scopes), toSHA256Bytes(b[:]), note, creatorUserID,
+		restrictions.ExpiresAt, pq.Array(restrictions.AllowedIPs), pq.Array(restrictions.RepoPatterns),
 	).Scan(&id); err != nil {
 		return 0, "", err
 	}
 	return id, token, nil
 }
 
-// Lookup looks up the access token. If it's valid and contains the required scope, it returns the
-// subject's user ID. Otherwise ErrAccessTokenNotFound is returned.
+// Lookup looks up the access token. If it's valid and has not expired, it returns the access
+// token, including its scopes and restrictions. Otherwise ErrAccessTokenNotFound is returned.
 //
 // Calling Lookup also updates the access token's last-used-at date.
 //
-// 🚨 SECURITY: This returns a user ID if and only if the tokenHexEncoded corresponds to a valid,
-// non-deleted access token.
-func (s *accessTokens) Lookup(ctx context.Context, tokenHexEncoded string, requiredScope string) (subjectUserID int32, err error) {
+// 🚨 SECURITY: This returns an access token if and only if the tokenHexEncoded corresponds to a
+// valid, non-deleted, non-expired access token. The caller must check that the access token has
+// the scopes required for the request and that its restrictions allow it.
+func (s *accessTokens) Lookup(ctx context.Context, tokenHexEncoded string) (*AccessToken, error) {
 	if Mocks.AccessTokens.Lookup != nil {
-		return Mocks.AccessTokens.Lookup(tokenHexEncoded, requiredScope)
-	}
-
-	if requiredScope == "" {
-		return 0, errors.New("no scope provided in access token lookup")
+		return Mocks.AccessTokens.Lookup(tokenHexEncoded)
 	}
 
 	token, err := hex.DecodeString(tokenHexEncoded)
 	if err != nil {
-		return 0, errors.Wrap(err, "AccessTokens.Lookup")
+		return nil, errors.Wrap(err, "AccessTokens.Lookup")
 	}
 
+	var t AccessToken
 	if err := dbconn.Global.QueryRowContext(ctx,
-		// Ensure that subject and creator users still exist.
+		// Ensure that subject and creator users still exist, and that the subject isn't deactivated.
 		`
 UPDATE access_tokens t SET last_used_at=now()
 WHERE t.id IN (
 	SELECT t2.id FROM access_tokens t2
-	JOIN users subject_user ON t2.subject_user_id=subject_user.id AND subject_user.deleted_at IS NULL
+	JOIN users subject_user ON t2.subject_user_id=subject_user.id AND subject_user.deleted_at IS NULL AND subject_user.deactivated_at IS NULL
 	JOIN users creator_user ON t2.creator_user_id=creator_user.id AND creator_user.deleted_at IS NULL
 	WHERE t2.value_sha256=$1 AND t2.deleted_at IS NULL AND
-	$2 = ANY (t2.scopes)
+	(t2.expires_at IS NULL OR t2.expires_at > now())
 )
-RETURNING t.subject_user_id
+RETURNING `+accessTokenColumns+`
 `,
-		toSHA256Bytes(token), requiredScope,
-	).Scan(&subjectUserID); err != nil {
+		toSHA256Bytes(token),
+	).Scan(accessTokenScanDest(&t)...); err != nil {
 		if err == sql.ErrNoRows {
-			return 0, ErrAccessTokenNotFound
+			return nil, ErrAccessTokenNotFound
 		}
-		return 0, err
+		return nil, err
 	}
-	return subjectUserID, nil
+	return &t, nil
 }
 
 // GetByID retrieves the access token (if any) given its ID.
@@ -182,7 +267,7 @@ func (s *accessTokens) List(ctx context.Context, opt AccessTokensListOptions) ([
 
 func (s *accessTokens) list(ctx context.Context, conds []*sqlf.Query, limitOffset *LimitOffset) ([]*AccessToken, error) {
 	q := sqlf.Sprintf(`
-SELECT id, subject_user_id, scopes, note, creator_user_id, created_at, last_used_at FROM access_tokens
+SELECT `+accessTokenColumns+` FROM access_tokens
 WHERE (%s)
 ORDER BY now() - created_at < interval '5 minutes' DESC, -- show recently created tokens first
 last_used_at DESC NULLS FIRST, -- ensure newly created tokens show first
@@ -201,7 +286,7 @@ created_at DESC
 	var results []*AccessToken
 	for rows.Next() {
 		var t AccessToken
-		if err := rows.Scan(&t.ID, &t.SubjectUserID, pq.Array(&t.Scopes), &t.Note, &t.CreatorUserID, &t.CreatedAt, &t.LastUsedAt); err != nil {
+		if err := rows.Scan(accessTokenScanDest(&t)...); err != nil {
 			return nil, err
 		}
 		results = append(results, &t)
@@ -209,6 +294,15 @@ created_at DESC
 	return results, nil
 }
 
+const accessTokenColumns = "id, subject_user_id, scopes, note, creator_user_id, created_at, last_used_at, expires_at, allowed_ips, repo_patterns"
+
+func accessTokenScanDest(t *AccessToken) []interface{} {
+	return []interface{}{
+		&t.ID, &t.SubjectUserID, pq.Array(&t.Scopes), &t.Note, &t.CreatorUserID, &t.CreatedAt, &t.LastUsedAt,
+		&t.ExpiresAt, pq.Array(&t.AllowedIPs), pq.Array(&t.RepoPatterns),
+	}
+}
+
 // Count counts all access tokens that satisfy the options (ignoring limit and offset).
 //
 // 🚨 SECURITY: The caller must ensure that the actor is permitted to count the tokens.
@@ -228,35 +322,32 @@ func (s *accessTokens) DeleteByID(ctx context.Context, id int64, subjectUserID i
 	if Mocks.AccessTokens.DeleteByID != nil {
 		return Mocks.AccessTokens.DeleteByID(id, subjectUserID)
 	}
-	return s.delete(ctx, sqlf.Sprintf("id=%d AND subject_user_id=%d", id, subjectUserID))
+	_, err := s.delete(ctx, sqlf.Sprintf("id=%d AND subject_user_id=%d", id, subjectUserID))
+	return err
 }
 
 // DeleteByToken deletes an access token given the secret token value itself (i.e., the same value
-// that an API client would use to authenticate).
-func (s *accessTokens) DeleteByToken(ctx context.Context, tokenHexEncoded string) error {
+// that an API client would use to authenticate), and returns the deleted access token.
+func (s *accessTokens) DeleteByToken(ctx context.Context, tokenHexEncoded string) (*AccessToken, error) {
 	token, err := hex.DecodeString(tokenHexEncoded)
 	if err != nil {
-		return errors.Wrap(err, "AccessTokens.DeleteByToken")
+		return nil, errors.Wrap(err, "AccessTokens.DeleteByToken")
 	}
 	return s.delete(ctx, sqlf.Sprintf("value_sha256=%s", toSHA256Bytes(token)))
 }
 
-func (s *accessTokens) delete(ctx context.Context, cond *sqlf.Query) error {
+func (s *accessTokens) delete(ctx context.Context, cond *sqlf.Query) (*AccessToken, error) {
 	conds := []*sqlf.Query{cond, sqlf.Sprintf("deleted_at IS NULL")}
-	q := sqlf.Sprintf("UPDATE access_tokens SET deleted_at=now() WHERE (%s)", sqlf.Join(conds, ") AND ("))
+	q := sqlf.Sprintf("UPDATE access_tokens SET deleted_at=now() WHERE (%s) RETURNING "+accessTokenColumns, sqlf.Join(conds, ") AND ("))
 
-	res, err := dbconn.Global.ExecContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...)
-	if err != nil {
-		return err
-	}
-	nrows, err := res.RowsAffected()
-	if err != nil {
-		return err
-	}
-	if nrows == 0 {
-		return ErrAccessTokenNotFound
+	var t AccessToken
+	if err := dbconn.Global.QueryRowContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...).Scan(accessTokenScanDest(&t)...); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrAccessTokenNotFound
+		}
+		return nil, err
 	}
-	return nil
+	return &t, nil
 }
 
 func toSHA256Bytes(input []byte) []byte {
@@ -265,8 +356,8 @@ func toSHA256Bytes(input []byte) []byte {
 }
 
 type MockAccessTokens struct {
-	Create     func(subjectUserID int32, scopes []string, note string, creatorUserID int32) (id int64, token string, err error)
+	Create     func(subjectUserID int32, scopes []string, note string, creatorUserID int32, restrictions AccessTokenRestrictions) (id int64, token string, err error)
 	DeleteByID func(id int64, subjectUserID int32) error
-	Lookup     func(tokenHexEncoded, requiredScope string) (subjectUserID int32, err error)
+	Lookup     func(tokenHexEncoded string) (*AccessToken, error)
 	GetByID    func(id int64) (*AccessToken, error)
 }
@@ -2,8 +2,10 @@ package db
 
 import (
 	"context"
+	"net"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/sourcegraph/sourcegraph/internal/db/dbtesting"
 )
@@ -34,7 +36,7 @@ func TestAccessTokens_Create(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	tid0, tv0, err := AccessTokens.Create(ctx, subject.ID, []string{"a", "b"}, "n0", creator.ID)
+	tid0, tv0, err := AccessTokens.Create(ctx, subject.ID, []string{"a", "b"}, "n0", creator.ID, AccessTokenRestrictions{})
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -53,12 +55,12 @@ func TestAccessTokens_Create(t *testing.T) {
 		t.Errorf("got %q, want %q", got.Note, want)
 	}
 
-	gotSubjectUserID, err := AccessTokens.Lookup(ctx, tv0, "a")
+	gotToken, err := AccessTokens.Lookup(ctx, tv0)
 	if err != nil {
 		t.Fatal(err)
 	}
-	if want := subject.ID; gotSubjectUserID != want {
-		t.Errorf("got %v, want %v", gotSubjectUserID, want)
+	if want := subject.ID; gotToken.SubjectUserID != want {
+		t.Errorf("got %v, want %v", gotToken.SubjectUserID, want)
 	}
 
 	ts, err := AccessTokens.List(ctx, AccessTokensListOptions{SubjectUserID: subject.ID})
@@ -108,11 +110,11 @@ func TestAccessTokens_List(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	_, _, err = AccessTokens.Create(ctx, subject1.ID, []string{"a", "b"}, "n0", subject1.ID)
+	_, _, err = AccessTokens.Create(ctx, subject1.ID, []string{"a", "b"}, "n0", subject1.ID, AccessTokenRestrictions{})
 	if err != nil {
 		t.Fatal(err)
 	}
-	_, _, err = AccessTokens.Create(ctx, subject1.ID, []string{"a", "b"}, "n1", subject1.ID)
+	_, _, err = AccessTokens.Create(ctx, subject1.ID, []string{"a", "b"}, "n1", subject1.ID, AccessTokenRestrictions{})
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -187,43 +189,69 @@ func TestAccessTokens_Lookup(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	tid0, tv0, err := AccessTokens.Create(ctx, subject.ID, []string{"a", "b"}, "n0", creator.ID)
+	tid0, tv0, err := AccessTokens.Create(ctx, subject.ID, []string{"a", "b"}, "n0", creator.ID, AccessTokenRestrictions{})
 	if err != nil {
 		t.Fatal(err)
 	}
 
-	for _, scope := range []string{"a", "b"} {
-		gotSubjectUserID, err := AccessTokens.Lookup(ctx, tv0, scope)
-		if err != nil {
-			t.Fatal(err)
-		}
-		if want := subject.ID; gotSubjectUserID != want {
-			t.Errorf("got %v, want %v", gotSubjectUserID, want)
-		}
+	gotToken, err := AccessTokens.Lookup(ctx, tv0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := subject.ID; gotToken.SubjectUserID != want {
+		t.Errorf("got %v, want %v", gotToken.SubjectUserID, want)
+	}
+	if want := []string{"a", "b"}; !reflect.DeepEqual(gotToken.Scopes, want) {
+		t.Errorf("got token scopes %q, want %q", gotToken.Scopes, want)
 	}
 
-	// Lookup with a nonexistent scope and ensure it fails.
-	if _, err := AccessTokens.Lookup(ctx, tv0, "x"); err == nil {
+	// Delete a token and ensure Lookup fails on it.
+	if err := AccessTokens.DeleteByID(ctx, tid0, subject.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := AccessTokens.Lookup(ctx, tv0); err == nil {
 		t.Fatal(err)
 	}
 
-	// Lookup with an empty scope and ensure it fails.
-	if _, err := AccessTokens.Lookup(ctx, tv0, ""); err == nil {
+	// Try to Lookup a token that was never created.
+	if _, err := AccessTokens.Lookup(ctx, "abcdefg" /* this token value was never created */); err == nil {
 		t.Fatal(err)
 	}
 
-	// Delete a token and ensure Lookup fails on it.
-	if err := AccessTokens.DeleteByID(ctx, tid0, subject.ID); err != nil {
+	// Create a token with restrictions and ensure Lookup returns them.
+	expiresAt := time.Now().Add(time.Hour).Truncate(time.Microsecond)
+	restrictions := AccessTokenRestrictions{
+		ExpiresAt:    &expiresAt,
+		AllowedIPs:   []string{"10.0.0.0/8"},
+		RepoPatterns: []string{"^github\\.com/foo/"},
+	}
+	_, tv1, err := AccessTokens.Create(ctx, subject.ID, []string{"a"}, "n1", creator.ID, restrictions)
+	if err != nil {
 		t.Fatal(err)
 	}
-	if _, err := AccessTokens.Lookup(ctx, tv0, "a"); err == nil {
+	gotToken, err = AccessTokens.Lookup(ctx, tv1)
+	if err != nil {
 		t.Fatal(err)
 	}
+	if !gotToken.ExpiresAt.Equal(expiresAt) {
+		t.Errorf("got expiry %v, want %v", gotToken.ExpiresAt, expiresAt)
+	}
+	if !reflect.DeepEqual(gotToken.AllowedIPs, restrictions.AllowedIPs) {
+		t.Errorf("got allowed IPs %q, want %q", gotToken.AllowedIPs, restrictions.AllowedIPs)
+	}
+	if !reflect.DeepEqual(gotToken.RepoPatterns, restrictions.RepoPatterns) {
+		t.Errorf("got repository patterns %q, want %q", gotToken.RepoPatterns, restrictions.RepoPatterns)
+	}
 
-	// Try to Lookup a token that was never created.
-	if _, err := AccessTokens.Lookup(ctx, "abcdefg" /* this token value was never created */, "a"); err == nil {
+	// Create an expired token and ensure Lookup fails on it.
+	expiredAt := time.Now().Add(-time.Hour)
+	_, tv2, err := AccessTokens.Create(ctx, subject.ID, []string{"a"}, "n2", creator.ID, AccessTokenRestrictions{ExpiresAt: &expiredAt})
+	if err != nil {
 		t.Fatal(err)
 	}
+	if _, err := AccessTokens.Lookup(ctx, tv2); err != ErrAccessTokenNotFound {
+		t.Fatalf("got error %v, want %v", err, ErrAccessTokenNotFound)
+	}
 }
 
 // 🚨 SECURITY: This tests that deleting the subject or creator user of an access token invalidates
@@ -255,18 +283,18 @@ func TestAccessTokens_Lookup_deletedUser(t *testing.T) {
 			t.Fatal(err)
 		}
 
-		_, tv0, err := AccessTokens.Create(ctx, subject.ID, []string{"a"}, "n0", creator.ID)
+		_, tv0, err := AccessTokens.Create(ctx, subject.ID, []string{"a"}, "n0", creator.ID, AccessTokenRestrictions{})
 		if err != nil {
 			t.Fatal(err)
 		}
 		if err := Users.Delete(ctx, subject.ID); err != nil {
 			t.Fatal(err)
 		}
-		if _, err := AccessTokens.Lookup(ctx, tv0, "a"); err == nil {
+		if _, err := AccessTokens.Lookup(ctx, tv0); err == nil {
 			t.Fatal("Lookup: want error looking up token for deleted subject user")
 		}
 
-		if _, _, err := AccessTokens.Create(ctx, subject.ID, nil, "n0", creator.ID); err == nil {
+		if _, _, err := AccessTokens.Create(ctx, subject.ID, nil, "n0", creator.ID, AccessTokenRestrictions{}); err == nil {
 			t.Fatal("Create: want error creating token for deleted subject user")
 		}
 	})
@@ -291,19 +319,80 @@ func TestAccessTokens_Lookup_deletedUser(t *testing.T) {
 			t.Fatal(err)
 		}
 
-		_, tv0, err := AccessTokens.Create(ctx, subject.ID, []string{"a"}, "n0", creator.ID)
+		_, tv0, err := AccessTokens.Create(ctx, subject.ID, []string{"a"}, "n0", creator.ID, AccessTokenRestrictions{})
 		if err != nil {
 			t.Fatal(err)
 		}
 		if err := Users.Delete(ctx, creator.ID); err != nil {
 			t.Fatal(err)
 		}
-		if _, err := AccessTokens.Lookup(ctx, tv0, "a"); err == nil {
+		if _, err := AccessTokens.Lookup(ctx, tv0); err == nil {
 			t.Fatal("Lookup: want error looking up token for deleted creator user")
 		}
 
-		if _, _, err := AccessTokens.Create(ctx, subject.ID, nil, "n0", creator.ID); err == nil {
+		if _, _, err := AccessTokens.Create(ctx, subject.ID, nil, "n0", creator.ID, AccessTokenRestrictions{}); err == nil {
 			t.Fatal("Create: want error creating token for deleted creator user")
 		}
 	})
 }
+
+func TestAccessTokenRestrictions(t *testing.T) {
+	r := AccessTokenRestrictions{
+		AllowedIPs:   []string{"10.0.0.0/8", "192.168.1.1", "2001:db8::/32"},
+		RepoPatterns: []string{"github\\.com/foo/.*", "gitlab\\.com/bar"},
+	}
+	if err := r.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	for ip, want := range map[string]bool{
+		"10.1.2.3":     true,
+		"192.168.1.1":  true,
+		"192.168.1.2":  false,
+		"2001:db8::1":  true,
+		"2001:db9::1":  false,
+		"11.0.0.1":     false,
+		"not-an-ip":    false,
+		"127.0.0.1":    false,
+		"::ffff:a00:1": true,
+	} {
+		if got := r.AllowsIP(net.ParseIP(ip)); got != want {
+			t.Errorf("AllowsIP(%q): got %v, want %v", ip, got, want)
+		}
+	}
+	if (&AccessTokenRestrictions{}).AllowsIP(nil) != true {
+		t.Error("AllowsIP: want unrestricted token to be allowed from any IP")
+	}
+
+	pattern, err := r.RepoPattern()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for repo, want := range map[string]bool{
+		"github.com/foo/baz":               true,
+		"github.com/foobar/baz":            false,
+		"gitlab.com/bar":                   true,
+		"gitlab.com/bar/baz":               false,
+		"gitlab.com/bar-secrets":           false,
+		"github.com/evil/gitlab.com/bar":   false,
+		"github.com/evil/github.com/foo/x": false,
+	} {
+		if got := pattern.MatchString(repo); got != want {
+			t.Errorf("RepoPattern().MatchString(%q): got %v, want %v", repo, got, want)
+		}
+	}
+	if pattern, err := (&AccessTokenRestrictions{}).RepoPattern(); pattern != nil || err != nil {
+		t.Errorf("RepoPattern: got %v, %v, want nil, nil for unrestricted token", pattern, err)
+	}
+
+	for _, invalid := range []AccessTokenRestrictions{
+		{AllowedIPs: []string{"10.0.0.0/33"}},
+		{AllowedIPs: []string{"10.0.0"}},
+		{RepoPatterns: []string{"("}},
+		{RepoPatterns: []string{"a)|(?:b"}},
+	} {
+		if err := invalid.Validate(); err == nil {
+			t.Errorf("Validate(%+v): want error", invalid)
+		}
+	}
+}
@@ -0,0 +1,253 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	multierror "github.com/hashicorp/go-multierror"
+	"github.com/keegancsmith/sqlf"
+	"github.com/sourcegraph/sourcegraph/internal/db/dbconn"
+	"github.com/sourcegraph/sourcegraph/internal/db/dbutil"
+)
+
+// AuditLogEntry describes a security-relevant action performed on the site, such as changing the
+// site configuration or promoting a user to site admin.
+type AuditLogEntry struct {
+	ID             int64
+	Action         string          // the action performed (e.g., "site_config.update")
+	ActorUserID    int32           // the user who performed the action (0 if not performed by a user)
+	ActorIP        string          // the IP address of the client that performed the action, if known
+	ActorUserAgent string          // the user agent of the client that performed the action, if known
+	TargetType     string          // the type of the entity the action was performed on (e.g., "user")
+	TargetID       string          // the ID of the entity the action was performed on
+	Before         json.RawMessage // the state of the target before the action (nil if none)
+	After          json.RawMessage // the state of the target after the action (nil if none)
+	CreatedAt      time.Time
+}
+
+type auditLogs struct{}
+
+// Create appends an entry to the audit log. The ID and CreatedAt fields of the given entry are set
+// upon success.
+//
+// Entries of the audit log can't be modified after they're created.
+func (*auditLogs) Create(ctx context.Context, e *AuditLogEntry) error {
+	if Mocks.AuditLogs.Create != nil {
+		return Mocks.AuditLogs.Create(e)
+	}
+
+	var actorUserID *int32
+	if e.ActorUserID != 0 {
+		actorUserID = &e.ActorUserID
+	}
+	return dbconn.Global.QueryRowContext(ctx,
+		"INSERT INTO audit_logs(action, actor_user_id, actor_ip, actor_user_agent, target_type, target_id, before, after) VALUES($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id, created_at",
+		e.Action, dbutil.NullInt32{N: actorUserID}, e.ActorIP, e.ActorUserAgent, e.TargetType, e.TargetID, nullJSON(e.Before), nullJSON(e.After),
+	).Scan(&e.ID, &e.CreatedAt)
+}
+
+// nullJSON returns the value to store in a nullable jsonb column.
+func nullJSON(v json.RawMessage) interface{} {
+	if len(v) == 0 {
+		return nil
+	}
+	return string(v)
+}
+
+// AuditLogsListOptions contains options for listing entries of the audit log.
+type AuditLogsListOptions struct {
+	ActorUserID int32  // only list entries of actions performed by this user
+	Action      string // only list entries of this action
+	TargetType  string // only list entries of actions performed on entities of this type
+	TargetID    string // only list entries of actions performed on the entity with this ID (requires TargetType)
+	After       *time.Time
+	Before      *time.Time
+	*LimitOffset
+}
+
+func (o AuditLogsListOptions) sqlConditions() []*sqlf.Query {
+	conds := []*sqlf.Query{sqlf.Sprintf("TRUE")}
+	if o.ActorUserID != 0 {
+		conds = append(conds, sqlf.Sprintf("actor_user_id=%d", o.ActorUserID))
+	}
+	if o.Action != "" {
+		conds = append(conds, sqlf.Sprintf("action=%s", o.Action))
+	}
+	if o.TargetType != "" {
+		conds = append(conds, sqlf.Sprintf("target_type=%s", o.TargetType))
+		if o.TargetID != "" {
+			conds = append(conds, sqlf.Sprintf("target_id=%s", o.TargetID))
+		}
+	}
+	if o.After != nil {
+		conds = append(conds, sqlf.Sprintf("created_at>%s", *o.After))
+	}
+	if o.Before != nil {
+		conds = append(conds, sqlf.Sprintf("created_at<%s", *o.Before))
+	}
+	return conds
+}
+
+// List lists entries of the audit log that satisfy the options, most recent first.
+//
+// 🚨 SECURITY: The caller must ensure that the actor is a site admin.
+func (*auditLogs) List(ctx context.Context, opt AuditLogsListOptions) ([]*AuditLogEntry, error) {
+	if Mocks.AuditLogs.List != nil {
+		return Mocks.AuditLogs.List(opt)
+	}
+
+	q := sqlf.Sprintf(`
+SELECT id, action, actor_user_id, actor_ip, actor_user_agent, target_type, target_id, before, after, created_at
+FROM audit_logs
+WHERE (%s)
+ORDER BY id DESC
+%s`,
+		sqlf.Join(opt.sqlConditions(), ") AND ("),
+		opt.LimitOffset.SQL(),
+	)
+
+	rows, err := dbconn.Global.QueryContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []*AuditLogEntry
+	for rows.Next() {
+		var e AuditLogEntry
+		if err := rows.Scan(
+			&e.ID, &e.Action, &dbutil.NullInt32{N: &e.ActorUserID}, &e.ActorIP, &e.ActorUserAgent,
+			&e.TargetType, &e.TargetID, (*[]byte)(&e.Before), (*[]byte)(&e.After), &e.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		entries = append(entries, &e)
+	}
+	return entries, rows.Err()
+}
+
+// Count counts entries of the audit log that satisfy the options (ignoring limit and offset).
+//
+// 🚨 SECURITY: The caller must ensure that the actor is a site admin.
+func (*auditLogs) Count(ctx context.Context, opt AuditLogsListOptions) (int, error) {
+	if Mocks.AuditLogs.Count != nil {
+		return Mocks.AuditLogs.Count(opt)
+	}
+
+	q := sqlf.Sprintf("SELECT COUNT(*) FROM audit_logs WHERE (%s)", sqlf.Join(opt.sqlConditions(), ") AND ("))
+	var count int
+	if err := dbconn.Global.QueryRowContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...).Scan(&count); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// DeleteOlderThan deletes entries of the audit log created before the given time, and returns
+// the number of deleted entries. It is used to enforce the retention policy of the audit log.
+func (*auditLogs) DeleteOlderThan(ctx context.Context, t time.Time) (int64, error) {
+	res, err := dbconn.Global.ExecContext(ctx, "DELETE FROM audit_logs WHERE created_at<$1", t)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+// Export passes the entries of the audit log that haven't been exported to the given sink yet to
+// export, oldest first and at most limit at a time. The sink's cursor (the ID of the last exported
+// entry) is only advanced if export returns nil, so entries are passed again until they're
+// exported successfully.
+//
+// A sink that is seen for the first time starts with the entries created after it was seen. Only
+// one caller exports to a sink at a time: if another one (e.g. in another frontend replica) holds
+// the sink's cursor, Export returns without calling export. Entries created in the last few
+// seconds are left for a later call, so that entries of transactions that commit out of order of
+// their IDs aren't skipped.
+func (*auditLogs) Export(ctx context.Context, sink string, limit int, export func([]*AuditLogEntry) error) (err error) {
+	if Mocks.AuditLogs.Export != nil {
+		return Mocks.AuditLogs.Export(sink, limit, export)
+	}
+
+	if _, err := dbconn.Global.ExecContext(ctx,
+		"INSERT INTO audit_log_export_cursors(sink, last_exported_id) SELECT $1, COALESCE(MAX(id), 0) FROM audit_logs ON CONFLICT (sink) DO NOTHING",
+		sink,
+	); err != nil {
+		return err
+	}
+
+	tx, err := dbconn.Global.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			rollErr := tx.Rollback()
+			if rollErr != nil {
+				err = multierror.Append(err, rollErr)
+			}
+			return
+		}
+		err = tx.Commit()
+	}()
+
+	var lastExportedID int64
+	err = tx.QueryRowContext(ctx, "SELECT last_exported_id FROM audit_log_export_cursors WHERE sink=$1 FOR UPDATE SKIP LOCKED", sink).Scan(&lastExportedID)
+	if err == sql.ErrNoRows {
+		// Another caller is exporting to the sink.
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	rows, err := tx.QueryContext(ctx, `
+SELECT id, action, actor_user_id, actor_ip, actor_user_agent, target_type, target_id, before, after, created_at
+FROM audit_logs
+WHERE id>$1 AND created_at<now()-$2::interval
+ORDER BY id
+LIMIT $3`,
+		lastExportedID, fmt.Sprintf("%d seconds", auditLogExportDelay/time.Second), limit,
+	)
+	if err != nil {
+		return err
+	}
+	var entries []*AuditLogEntry
+	for rows.Next() {
+		var e AuditLogEntry
+		if err := rows.Scan(
+			&e.ID, &e.Action, &dbutil.NullInt32{N: &e.ActorUserID}, &e.ActorIP, &e.ActorUserAgent,
+			&e.TargetType, &e.TargetID, (*[]byte)(&e.Before), (*[]byte)(&e.After), &e.CreatedAt,
+		); err != nil {
+			rows.Close()
+			return err
+		}
+		entries = append(entries, &e)
+	}
+	if err := rows.Close(); err != nil {
+		return err
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	if len(entries) == 0 {
+		return nil
+	}
+
+	if err := export(entries); err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, "UPDATE audit_log_export_cursors SET last_exported_id=$1, updated_at=now() WHERE sink=$2", entries[len(entries)-1].ID, sink)
+	return err
+}
+
+// auditLogExportDelay is how old entries of the audit log must be to be exported.
+var auditLogExportDelay = 5 * time.Second
+
+type MockAuditLogs struct {
+	Create func(e *AuditLogEntry) error
+	List   func(opt AuditLogsListOptions) ([]*AuditLogEntry, error)
+	Count  func(opt AuditLogsListOptions) (int, error)
+	Export func(sink string, limit int, export func([]*AuditLogEntry) error) error
+}
@@ -0,0 +1,178 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/internal/db/dbconn"
+	"github.com/sourcegraph/sourcegraph/internal/db/dbtesting"
+)
+
+func TestAuditLogs(t *testing.T) {
+	dbtesting.SetupGlobalTestDB(t)
+	ctx := context.Background()
+
+	entries := []*AuditLogEntry{
+		{
+			Action:         "site_config.update",
+			ActorUserID:    1,
+			ActorIP:        "127.0.0.1",
+			ActorUserAgent: "curl/7.64.1",
+			TargetType:     "site_config",
+			TargetID:       "1",
+			Before:         json.RawMessage(`{"a":1}`),
+			After:          json.RawMessage(`{"a":2}`),
+		},
+		{
+			Action:      "user.site_admin.update",
+			ActorUserID: 2,
+			TargetType:  "user",
+			TargetID:    "3",
+			After:       json.RawMessage(`{"siteAdmin":true}`),
+		},
+		{
+			Action:     "user.sudo",
+			TargetType: "user",
+			TargetID:   "3",
+		},
+	}
+	for _, e := range entries {
+		if err := AuditLogs.Create(ctx, e); err != nil {
+			t.Fatal(err)
+		}
+		if e.ID == 0 || e.CreatedAt.IsZero() {
+			t.Fatalf("entry not populated after creation: %+v", e)
+		}
+	}
+
+	t.Run("List", func(t *testing.T) {
+		tests := []struct {
+			name    string
+			opt     AuditLogsListOptions
+			wantIDs []int64
+		}{
+			{name: "all", wantIDs: []int64{entries[2].ID, entries[1].ID, entries[0].ID}},
+			{name: "by actor", opt: AuditLogsListOptions{ActorUserID: 1}, wantIDs: []int64{entries[0].ID}},
+			{name: "by action", opt: AuditLogsListOptions{Action: "user.sudo"}, wantIDs: []int64{entries[2].ID}},
+			{name: "by target", opt: AuditLogsListOptions{TargetType: "user", TargetID: "3"}, wantIDs: []int64{entries[2].ID, entries[1].ID}},
+			{name: "limit", opt: AuditLogsListOptions{LimitOffset: &LimitOffset{Limit: 1}}, wantIDs: []int64{entries[2].ID}},
+		}
+		for _, test := range tests {
+			t.Run(test.name, func(t *testing.T) {
+				got, err := AuditLogs.List(ctx, test.opt)
+				if err != nil {
+					t.Fatal(err)
+				}
+				var gotIDs []int64
+				for _, e := range got {
+					gotIDs = append(gotIDs, e.ID)
+				}
+				if len(gotIDs) != len(test.wantIDs) {
+					t.Fatalf("got IDs %v, want %v", gotIDs, test.wantIDs)
+				}
+				for i := range gotIDs {
+					if gotIDs[i] != test.wantIDs[i] {
+						t.Fatalf("got IDs %v, want %v", gotIDs, test.wantIDs)
+					}
+				}
+
+				count, err := AuditLogs.Count(ctx, AuditLogsListOptions{ActorUserID: test.opt.ActorUserID, Action: test.opt.Action, TargetType: test.opt.TargetType, TargetID: test.opt.TargetID})
+				if err != nil {
+					t.Fatal(err)
+				}
+				if test.opt.LimitOffset == nil && count != len(test.wantIDs) {
+					t.Errorf("got count %d, want %d", count, len(test.wantIDs))
+				}
+			})
+		}
+
+		got, err := AuditLogs.List(ctx, AuditLogsListOptions{ActorUserID: 1})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if e := got[0]; e.ActorIP != "127.0.0.1" || e.ActorUserAgent != "curl/7.64.1" || string(e.Before) != `{"a": 1}` || string(e.After) != `{"a": 2}` {
+			t.Errorf("unexpected entry %+v", e)
+		}
+		got, err = AuditLogs.List(ctx, AuditLogsListOptions{Action: "user.sudo"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if e := got[0]; e.ActorUserID != 0 || e.Before != nil || e.After != nil {
+			t.Errorf("unexpected entry %+v", e)
+		}
+	})
+
+	t.Run("append-only", func(t *testing.T) {
+		if _, err := dbconn.Global.ExecContext(ctx, "UPDATE audit_logs SET action='x' WHERE id=$1", entries[0].ID); err == nil {
+			t.Fatal("expected an error when updating an audit log entry")
+		}
+	})
+
+	t.Run("Export", func(t *testing.T) {
+		defer func(d time.Duration) { auditLogExportDelay = d }(auditLogExportDelay)
+		auditLogExportDelay = 0
+
+		// A new sink starts after the existing entries.
+		exportIDs := func(sink string, fail bool) (ids []int64, err error) {
+			err = AuditLogs.Export(ctx, sink, 2, func(entries []*AuditLogEntry) error {
+				for _, e := range entries {
+					ids = append(ids, e.ID)
+				}
+				if fail {
+					return errors.New("fail")
+				}
+				return nil
+			})
+			return ids, err
+		}
+		if ids, err := exportIDs("a", false); err != nil || len(ids) != 0 {
+			t.Fatalf("got IDs %v, error %v; want none", ids, err)
+		}
+
+		var newEntries []*AuditLogEntry
+		for i := 0; i < 3; i++ {
+			e := &AuditLogEntry{Action: "user.sudo", TargetType: "user", TargetID: "3"}
+			if err := AuditLogs.Create(ctx, e); err != nil {
+				t.Fatal(err)
+			}
+			newEntries = append(newEntries, e)
+		}
+		entries = append(entries, newEntries...)
+
+		// The cursor isn't advanced if the export fails.
+		if _, err := exportIDs("a", true); err == nil {
+			t.Fatal("expected an error")
+		}
+		for _, want := range [][]int64{{newEntries[0].ID, newEntries[1].ID}, {newEntries[2].ID}, nil} {
+			ids, err := exportIDs("a", false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(ids, want) {
+				t.Fatalf("got IDs %v, want %v", ids, want)
+			}
+		}
+	})
+
+	t.Run("DeleteOlderThan", func(t *testing.T) {
+		n, err := AuditLogs.DeleteOlderThan(ctx, time.Now().Add(-time.Hour))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n != 0 {
+			t.Fatalf("got %d deleted entries, want 0", n)
+		}
+
+		n, err = AuditLogs.DeleteOlderThan(ctx, time.Now().Add(time.Hour))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n != int64(len(entries)) {
+			t.Fatalf("got %d deleted entries, want %d", n, len(entries))
+		}
+	})
+}
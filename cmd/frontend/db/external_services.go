@@ -30,6 +30,7 @@ type ExternalServicesStore struct {
 	GitHubValidators          []func(*schema.GitHubConnection) error
 	GitLabValidators          []func(*schema.GitLabConnection, []schema.AuthProviders) error
 	BitbucketServerValidators []func(*schema.BitbucketServerConnection) error
+	GitoliteValidators        []func(*schema.GitoliteConnection, []schema.AuthProviders) error
 }
 
 // ExternalServiceKinds contains a map of all supported kinds of
@@ -41,6 +42,8 @@ var ExternalServiceKinds = map[string]ExternalServiceKind{
 	"GITHUB":          {CodeHost: true, JSONSchema: schema.GitHubSchemaJSON},
 	"GITLAB":          {CodeHost: true, JSONSchema: schema.GitLabSchemaJSON},
 	"GITOLITE":        {CodeHost: true, JSONSchema: schema.GitoliteSchemaJSON},
+	"MANIFEST":        {CodeHost: true, JSONSchema: schema.ManifestSchemaJSON},
+	"PERFORCE":        {CodeHost: true, JSONSchema: schema.PerforceSchemaJSON},
 	"PHABRICATOR":     {CodeHost: true, JSONSchema: schema.PhabricatorSchemaJSON},
 	"OTHER":           {CodeHost: true, JSONSchema: schema.OtherExternalServiceSchemaJSON},
 }
@@ -130,6 +133,13 @@ func (e *ExternalServicesStore) ValidateConfig(kind, config string, ps []schema.
 		}
 		err = e.validateBitbucketServerConnection(&c)
 
+	case "GITOLITE":
+		var c schema.GitoliteConnection
+		if err = json.Unmarshal(normalized, &c); err != nil {
+			return err
+		}
+		err = e.validateGitoliteConnection(&c, ps)
+
 	case "OTHER":
 		var c schema.OtherExternalServiceConnection
 		if err = json.Unmarshal(normalized, &c); err != nil {
@@ -204,6 +214,14 @@ func (e *ExternalServicesStore) validateBitbucketServerConnection(c *schema.Bitb
 	return err.ErrorOrNil()
 }
 
+func (e *ExternalServicesStore) validateGitoliteConnection(c *schema.GitoliteConnection, ps []schema.AuthProviders) error {
+	err := new(multierror.Error)
+	for _, validate := range e.GitoliteValidators {
+		err = multierror.Append(err, validate(c, ps))
+	}
+	return err.ErrorOrNil()
+}
+
 // Create creates a external service.
 //
 // Since this method is used before the configuration server has started
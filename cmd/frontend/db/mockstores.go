@@ -5,6 +5,7 @@ var Mocks MockStores
 // MockStores has a field for each store interface with the concrete mock type (to obviate the need for tedious type assertions in test code).
 type MockStores struct {
 	AccessTokens MockAccessTokens
+	AuditLogs    MockAuditLogs
 
 	DiscussionThreads         MockDiscussionThreads
 	DiscussionComments        MockDiscussionComments
@@ -17,6 +18,8 @@ type MockStores struct {
 	Settings      MockSettings
 	Users         MockUsers
 	UserEmails    MockUserEmails
+	UserMFA       MockUserMFA
+	UserSessions  MockUserSessions
 
 	Phabricator MockPhabricator
 
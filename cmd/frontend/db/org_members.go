@@ -16,15 +16,23 @@ import (
 
 type orgMembers struct{}
 
-func (*orgMembers) Create(ctx context.Context, orgID, userID int32) (*types.OrgMembership, error) {
+// Create adds the user to the organization with the given role.
+func (*orgMembers) Create(ctx context.Context, orgID, userID int32, role types.OrgRole) (*types.OrgMembership, error) {
+	if Mocks.OrgMembers.Create != nil {
+		return Mocks.OrgMembers.Create(ctx, orgID, userID, role)
+	}
+	if !role.Valid() {
+		return nil, fmt.Errorf("invalid organization role %q", role)
+	}
 	m := types.OrgMembership{
 		OrgID:  orgID,
 		UserID: userID,
+		Role:   role,
 	}
 	err := dbconn.Global.QueryRowContext(
 		ctx,
-		"INSERT INTO org_members(org_id, user_id) VALUES($1, $2) RETURNING id, created_at, updated_at",
-		m.OrgID, m.UserID).Scan(&m.ID, &m.CreatedAt, &m.UpdatedAt)
+		"INSERT INTO org_members(org_id, user_id, role) VALUES($1, $2, $3) RETURNING id, created_at, updated_at",
+		m.OrgID, m.UserID, m.Role).Scan(&m.ID, &m.CreatedAt, &m.UpdatedAt)
 	if err != nil {
 		if pqErr, ok := err.(*pq.Error); ok {
 			if pqErr.Constraint == "org_members_org_id_user_id_key" {
@@ -47,13 +55,41 @@ func (m *orgMembers) GetByOrgIDAndUserID(ctx context.Context, orgID, userID int3
 	return m.getOneBySQL(ctx, "INNER JOIN users ON org_members.user_id=users.id WHERE org_id=$1 AND user_id=$2 AND users.deleted_at IS NULL LIMIT 1", orgID, userID)
 }
 
+// UpdateRole changes the role of the user in the organization.
+func (*orgMembers) UpdateRole(ctx context.Context, orgID, userID int32, role types.OrgRole) error {
+	if Mocks.OrgMembers.UpdateRole != nil {
+		return Mocks.OrgMembers.UpdateRole(ctx, orgID, userID, role)
+	}
+	if !role.Valid() {
+		return fmt.Errorf("invalid organization role %q", role)
+	}
+	res, err := dbconn.Global.ExecContext(ctx, "UPDATE org_members SET role=$3, updated_at=now() WHERE org_id=$1 AND user_id=$2", orgID, userID, role)
+	if err != nil {
+		return err
+	}
+	nrows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if nrows == 0 {
+		return &ErrOrgMemberNotFound{[]interface{}{orgID, userID}}
+	}
+	return nil
+}
+
 func (*orgMembers) Remove(ctx context.Context, orgID, userID int32) error {
+	if Mocks.OrgMembers.Remove != nil {
+		return Mocks.OrgMembers.Remove(ctx, orgID, userID)
+	}
 	_, err := dbconn.Global.ExecContext(ctx, "DELETE FROM org_members WHERE (org_id=$1 AND user_id=$2)", orgID, userID)
 	return err
 }
 
 // GetByOrgID returns a list of all members of a given organization.
 func (*orgMembers) GetByOrgID(ctx context.Context, orgID int32) ([]*types.OrgMembership, error) {
+	if Mocks.OrgMembers.GetByOrgID != nil {
+		return Mocks.OrgMembers.GetByOrgID(ctx, orgID)
+	}
 	org, err := Orgs.GetByID(ctx, orgID)
 	if err != nil {
 		return nil, err
@@ -85,7 +121,7 @@ func (m *orgMembers) getOneBySQL(ctx context.Context, query string, args ...inte
 }
 
 func (*orgMembers) getBySQL(ctx context.Context, query string, args ...interface{}) ([]*types.OrgMembership, error) {
-	rows, err := dbconn.Global.QueryContext(ctx, "SELECT org_members.id, org_members.org_id, org_members.user_id, org_members.created_at, org_members.updated_at FROM org_members "+query, args...)
+	rows, err := dbconn.Global.QueryContext(ctx, "SELECT org_members.id, org_members.org_id, org_members.user_id, org_members.role, org_members.created_at, org_members.updated_at FROM org_members "+query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -94,7 +130,7 @@ func (*orgMembers) getBySQL(ctx context.Context, query string, args ...interface
 	defer rows.Close()
 	for rows.Next() {
 		m := types.OrgMembership{}
-		err := rows.Scan(&m.ID, &m.OrgID, &m.UserID, &m.CreatedAt, &m.UpdatedAt)
+		err := rows.Scan(&m.ID, &m.OrgID, &m.UserID, &m.Role, &m.CreatedAt, &m.UpdatedAt)
 		if err != nil {
 			return nil, err
 		}
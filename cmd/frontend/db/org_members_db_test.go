@@ -49,7 +49,7 @@ func TestOrgMembers_CreateMembershipInOrgsForAllUsers(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
-	if _, err := OrgMembers.Create(ctx, org1.ID, user1.ID); err != nil {
+	if _, err := OrgMembers.Create(ctx, org1.ID, user1.ID, types.OrgRoleMember); err != nil {
 		t.Fatal(err)
 	}
 
@@ -3,6 +3,7 @@ package db
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	regexpsyntax "regexp/syntax"
 	"strings"
@@ -154,6 +155,7 @@ var getBySQLColumns = []string{
 	"language",
 	"fork",
 	"archived",
+	"key_value_pairs",
 }
 
 func (s *repos) getBySQL(ctx context.Context, querySuffix *sqlf.Query) ([]*types.Repo, error) {
@@ -210,7 +212,8 @@ func scanRepo(rows *sql.Rows, r *types.Repo) (err error) {
 		)
 	}
 
-	return rows.Scan(
+	var kvps json.RawMessage
+	err = rows.Scan(
 		&r.ID,
 		&r.Name,
 		&r.Private,
@@ -222,7 +225,13 @@ func scanRepo(rows *sql.Rows, r *types.Repo) (err error) {
 		&r.Language,
 		&r.Fork,
 		&r.Archived,
+		&kvps,
 	)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(kvps, &r.KeyValuePairs)
 }
 
 // ReposListOptions specifies the options for listing repositories.
@@ -263,6 +272,10 @@ type ReposListOptions struct {
 	// OnlyPrivate excludes non-private repositories from the list.
 	OnlyPrivate bool
 
+	// KeyValuePairs is a list of key/value tag filters, all of which must
+	// match all repositories returned in the list.
+	KeyValuePairs []RepoKVPFilter
+
 	// OnlyRepoIDs skips fetching of RepoFields in each Repo.
 	OnlyRepoIDs bool
 
@@ -278,6 +291,29 @@ type ReposListOptions struct {
 	*LimitOffset
 }
 
+// RepoKVPFilter matches repositories by one of their key/value tags.
+type RepoKVPFilter struct {
+	// Key is the key of the tag.
+	Key string
+	// Value, if non-nil, must be equal to the value of the tag. If nil, any
+	// repository that has a tag with the given key matches.
+	Value *string
+	// Negated excludes the matching repositories instead.
+	Negated bool
+}
+
+// SQL returns the condition of the filter.
+func (f RepoKVPFilter) SQL() *sqlf.Query {
+	cond := sqlf.Sprintf("key_value_pairs ? %s", f.Key)
+	if f.Value != nil {
+		cond = sqlf.Sprintf("key_value_pairs @> jsonb_build_object(%s::text, %s::text)", f.Key, *f.Value)
+	}
+	if f.Negated {
+		return sqlf.Sprintf("NOT (%s)", cond)
+	}
+	return cond
+}
+
 type RepoListOrderBy []RepoListSort
 
 func (r RepoListOrderBy) SQL() *sqlf.Query {
@@ -368,6 +404,53 @@ func (s *repos) ListEnabledNames(ctx context.Context) ([]string, error) {
 	return names, nil
 }
 
+// SetKeyValuePair sets the value of the key/value tag with the given key on
+// the repository, adding the tag if it doesn't exist yet. A nil value stores
+// a tag that is a key only.
+func (s *repos) SetKeyValuePair(ctx context.Context, repo api.RepoID, key string, value *string) error {
+	if Mocks.Repos.SetKeyValuePair != nil {
+		return Mocks.Repos.SetKeyValuePair(ctx, repo, key, value)
+	}
+
+	if key == "" {
+		return errors.New("key/value tag key must not be empty")
+	}
+
+	q := sqlf.Sprintf(
+		"UPDATE repo SET key_value_pairs = key_value_pairs || jsonb_build_object(%s::text, %s::text) WHERE id=%d AND deleted_at IS NULL",
+		key, value, repo,
+	)
+	return s.execOnRepo(ctx, repo, q)
+}
+
+// DeleteKeyValuePair removes the key/value tag with the given key from the
+// repository. It is not an error if the repository has no such tag.
+func (s *repos) DeleteKeyValuePair(ctx context.Context, repo api.RepoID, key string) error {
+	if Mocks.Repos.DeleteKeyValuePair != nil {
+		return Mocks.Repos.DeleteKeyValuePair(ctx, repo, key)
+	}
+
+	q := sqlf.Sprintf("UPDATE repo SET key_value_pairs = key_value_pairs - %s WHERE id=%d AND deleted_at IS NULL", key, repo)
+	return s.execOnRepo(ctx, repo, q)
+}
+
+// execOnRepo executes q, which must update the row of the given repository,
+// and returns a not found error if no row was affected.
+func (*repos) execOnRepo(ctx context.Context, repo api.RepoID, q *sqlf.Query) error {
+	res, err := dbconn.Global.ExecContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return &repoNotFoundErr{ID: repo}
+	}
+	return nil
+}
+
 func parsePattern(p string) ([]*sqlf.Query, error) {
 	exact, like, pattern, err := parseIncludePattern(p)
 	if err != nil {
@@ -457,6 +540,9 @@ func (*repos) listSQL(opt ReposListOptions) (conds []*sqlf.Query, err error) {
 	if opt.OnlyPrivate {
 		conds = append(conds, sqlf.Sprintf("private"))
 	}
+	for _, f := range opt.KeyValuePairs {
+		conds = append(conds, f.SQL())
+	}
 
 	if opt.Index != nil {
 		// We don't currently have an index column, but when we want the
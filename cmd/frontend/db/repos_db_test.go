@@ -15,6 +15,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/db/dbconn"
 	"github.com/sourcegraph/sourcegraph/internal/db/dbtesting"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
 )
 
 /*
@@ -580,6 +581,89 @@ func TestRepos_List_patterns(t *testing.T) {
 	}
 }
 
+// TestRepos_List_keyValuePairs tests the behavior of Repos.List when called
+// with KeyValuePairs filters.
+func TestRepos_List_keyValuePairs(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	MockAuthzFilter = func(ctx context.Context, repos []*types.Repo, p authz.Perms) ([]*types.Repo, error) {
+		return repos, nil
+	}
+	defer func() { MockAuthzFilter = nil }()
+	dbtesting.SetupGlobalTestDB(t)
+	ctx := context.Background()
+	ctx = actor.WithActor(ctx, &actor.Actor{})
+
+	str := func(s string) *string { return &s }
+
+	repos := mustCreate(ctx, t,
+		&types.Repo{Name: "a/b"},
+		&types.Repo{Name: "c/d"},
+		&types.Repo{Name: "e/f"},
+	)
+	for _, kvp := range []struct {
+		repo  *types.Repo
+		key   string
+		value *string
+	}{
+		{repos[0], "tier", str("1")},
+		{repos[0], "go", nil},
+		{repos[1], "tier", str("2")},
+	} {
+		if err := Repos.SetKeyValuePair(ctx, kvp.repo.ID, kvp.key, kvp.value); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		filters []RepoKVPFilter
+		want    []api.RepoName
+	}{
+		{
+			filters: []RepoKVPFilter{{Key: "tier"}},
+			want:    []api.RepoName{"a/b", "c/d"},
+		},
+		{
+			filters: []RepoKVPFilter{{Key: "tier", Value: str("1")}},
+			want:    []api.RepoName{"a/b"},
+		},
+		{
+			filters: []RepoKVPFilter{{Key: "tier", Value: str("1"), Negated: true}},
+			want:    []api.RepoName{"c/d", "e/f"},
+		},
+		{
+			filters: []RepoKVPFilter{{Key: "tier"}, {Key: "go", Negated: true}},
+			want:    []api.RepoName{"c/d"},
+		},
+	}
+	for _, test := range tests {
+		repos, err := Repos.List(ctx, ReposListOptions{KeyValuePairs: test.filters})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := repoNames(repos); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("filters %+v: got repos %q, want %q", test.filters, got, test.want)
+		}
+	}
+
+	if err := Repos.DeleteKeyValuePair(ctx, repos[0].ID, "tier"); err != nil {
+		t.Fatal(err)
+	}
+	repo, err := Repos.Get(ctx, repos[0].ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := map[string]*string{"go": nil}; !reflect.DeepEqual(repo.KeyValuePairs, want) {
+		t.Errorf("got key/value pairs %v, want %v", repo.KeyValuePairs, want)
+	}
+
+	if err := Repos.SetKeyValuePair(ctx, 1234, "tier", nil); !errcode.IsNotFound(err) {
+		t.Errorf("got error %v, want not found", err)
+	}
+}
+
 // TestRepos_List_patterns tests the behavior of Repos.List when called with
 // a QueryPattern.
 func TestRepos_List_queryPattern(t *testing.T) {
@@ -15,6 +15,9 @@ type MockRepos struct {
 	GetByIDs  func(ctx context.Context, ids ...api.RepoID) ([]*types.Repo, error)
 	List      func(v0 context.Context, v1 ReposListOptions) ([]*types.Repo, error)
 	Count     func(ctx context.Context, opt ReposListOptions) (int, error)
+
+	SetKeyValuePair    func(ctx context.Context, repo api.RepoID, key string, value *string) error
+	DeleteKeyValuePair func(ctx context.Context, repo api.RepoID, key string) error
 }
 
 func (s *MockRepos) MockGet(t *testing.T, wantRepo api.RepoID) (called *bool) {
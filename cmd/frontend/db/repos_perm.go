@@ -2,6 +2,7 @@ package db
 
 import (
 	"context"
+	"fmt"
 	"strconv"
 	"sync"
 	"time"
@@ -16,6 +17,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/globals"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
 	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc"
 	"github.com/sourcegraph/sourcegraph/internal/trace"
 )
@@ -40,7 +42,8 @@ var MockAuthzFilter func(ctx context.Context, repos []*types.Repo, p authz.Perms
 //
 // The enforcement policy:
 //
-// - If permissions user mapping is enabled, directly check permissions against local Postgres.
+// - If permissions user mapping is enabled, check permissions of repositories whose code host has no
+//   authz provider directly against local Postgres. The other repositories are checked as below.
 //
 // - If there are no authz providers and `authzAllowByDefault` is true, then the repository is
 //   accessible to everyone.
@@ -93,15 +96,66 @@ func authzFilter(ctx context.Context, repos []*types.Repo, p authz.Perms) (filte
 		return repos, nil
 	}
 
+	// 🚨 SECURITY: Actors authenticated with a repository-restricted access token can only access
+	// the repositories whose names match its pattern, even if they are site admins.
+	if pattern := actor.FromContext(ctx).RepoPattern; pattern != nil {
+		matching := make([]*types.Repo, 0, len(repos))
+		for _, r := range repos {
+			if pattern.MatchString(string(r.Name)) {
+				matching = append(matching, r)
+			}
+		}
+		repos = matching
+	}
+
 	if actor.FromContext(ctx).IsAuthenticated() {
 		var err error
 		currentUser, err = Users.GetByCurrentAuthUser(ctx)
 		if err != nil {
 			return nil, err
 		}
-		if currentUser.SiteAdmin {
-			return repos, nil
+	}
+
+	return authzFilterForUser(ctx, tr, currentUser, repos, p, nil)
+}
+
+// ExplainRepoAccess explains whether the user has the permission on the repository by returning
+// the decisions the enforcement policy of authzFilter makes for it, in order. The last decision is
+// the one that decides. Unlike authzFilter, it never fetches or saves external accounts of the
+// user, and it ignores the actor of the context.
+//
+// 🚨 SECURITY: It is the caller's responsibility to ensure the current authenticated user is a
+// site admin.
+func ExplainRepoAccess(ctx context.Context, user *types.User, repo *types.Repo, p authz.Perms) (decisions []AuthzDecision, err error) {
+	tr, ctx := trace.New(ctx, "ExplainRepoAccess", "")
+	defer func() {
+		if err != nil {
+			tr.SetError(err)
 		}
+		tr.Finish()
+	}()
+
+	e := &authzExplanation{repo: repo}
+	if _, err = authzFilterForUser(ctx, tr, user, []*types.Repo{repo}, p, e); err != nil {
+		return nil, err
+	}
+	return e.decisions, nil
+}
+
+// authzFilterForUser filters the repositories for the user (nil if anonymous) with the enforcement
+// policy described by authzFilter. If e is not nil, the decisions made for the repository it
+// explains are recorded in it. NOTE: The repos slice is filtered in place and returned.
+func authzFilterForUser(
+	ctx context.Context,
+	tr *trace.Trace,
+	currentUser *types.User,
+	repos []*types.Repo,
+	p authz.Perms,
+	e *authzExplanation,
+) (filtered []*types.Repo, err error) {
+	if currentUser != nil && currentUser.SiteAdmin {
+		e.add(AuthzRuleSiteAdmin, true, "", repos, "The user is a site admin, who can access all repositories.")
+		return repos, nil
 	}
 
 	authzAllowByDefault, authzProviders := authz.GetProviders()
@@ -110,23 +164,82 @@ func authzFilter(ctx context.Context, repos []*types.Repo, p authz.Perms) (filte
 		otlog.Int("authzProviders.count", len(authzProviders)),
 	)
 
-	// 🚨 SECURITY: Blocking access to all repositories if both code host authz provider(s) and permissions user mapping
-	// are configured.
+	// 🚨 SECURITY: Explicit permissions set through the permissions user mapping apply to the
+	// repositories whose code host has no authz provider (e.g. Gitolite, Phabricator, AWS CodeCommit
+	// and other Git hosts). Repositories of code hosts with an authz provider are authorized by it.
 	if globals.PermissionsUserMapping().Enabled {
-		if len(authzProviders) > 0 {
-			return nil, errors.New("The permissions user mapping (site configuration `permissions.userMapping`) cannot be enabled when other authorization providers are in use, please contact site admin to resolve it.")
-		} else if currentUser == nil {
+		if currentUser == nil {
 			return nil, errors.New("Anonymous access is not allow when permissions user mapping is enabled.")
 		}
 
-		return Authz.AuthorizedRepos(ctx, &AuthorizedReposArgs{
-			Repos:  repos,
+		hasProvider := make(map[string]bool, len(authzProviders))
+		for _, provider := range authzProviders {
+			hasProvider[provider.ServiceID()] = true
+		}
+		explicit := make([]*types.Repo, 0, len(repos))
+		var others []*types.Repo
+		for _, r := range repos {
+			if hasProvider[r.ExternalRepo.ServiceID] {
+				others = append(others, r)
+			} else {
+				explicit = append(explicit, r)
+			}
+		}
+
+		e.add(AuthzRuleNoProvider, false, "", explicit,
+			`The code host of the repository has no authorization provider, so the permissions set explicitly through "permissions.userMapping" apply.`)
+		verified, err := Authz.AuthorizedRepos(ctx, &AuthorizedReposArgs{
+			Repos:  explicit,
 			UserID: currentUser.ID,
 			Perm:   p,
 			Type:   authz.PermRepos,
 		})
+		if err != nil {
+			return nil, err
+		}
+		e.addStoredPermissions(AuthzRuleExplicitPermissions, explicit, verified)
+		if len(others) == 0 {
+			return verified, nil
+		}
+
+		verifiedOthers, err := authzFilterByProviders(ctx, tr, currentUser, others, p, authzAllowByDefault, authzProviders, e)
+		if err != nil {
+			return nil, err
+		}
+
+		authorized := make(map[api.RepoID]bool, len(verified)+len(verifiedOthers))
+		for _, r := range verified {
+			authorized[r.ID] = true
+		}
+		for _, r := range verifiedOthers {
+			authorized[r.ID] = true
+		}
+		filtered = repos[:0]
+		for _, r := range repos {
+			if authorized[r.ID] {
+				filtered = append(filtered, r) // In-place filtering
+			}
+		}
+		clear(repos[len(filtered):])
+		return filtered, nil
 	}
 
+	return authzFilterByProviders(ctx, tr, currentUser, repos, p, authzAllowByDefault, authzProviders, e)
+}
+
+// authzFilterByProviders filters the repositories with the authz providers, for the current user
+// (nil if anonymous). If e is not nil, the decisions made for the repository it explains are
+// recorded in it. NOTE: The repos slice is filtered in place and returned.
+func authzFilterByProviders(
+	ctx context.Context,
+	tr *trace.Trace,
+	currentUser *types.User,
+	repos []*types.Repo,
+	p authz.Perms,
+	authzAllowByDefault bool,
+	authzProviders []authz.Provider,
+	e *authzExplanation,
+) (filtered []*types.Repo, err error) {
 	// In case there is no repos to be checked, return here to avoid more expensive calls.
 	// 🚨 SECURITY: This "smart" check must happen after checking globals.PermissionsUserMapping().Enabled.
 	// Otherwise, we could leak the existence of repositories that a user has no access to by returning an
@@ -137,6 +250,8 @@ func authzFilter(ctx context.Context, repos []*types.Repo, p authz.Perms) (filte
 
 	// Permissions are not enforced by authz providers and everyone can see all repositories.
 	if authzAllowByDefault && len(authzProviders) == 0 {
+		e.add(AuthzRuleAllowedByDefault, true, "", repos,
+			`There are no authorization providers and "authz.allowByDefault" is enabled, so everyone can access all repositories.`)
 		return repos, nil
 	}
 
@@ -154,12 +269,15 @@ func authzFilter(ctx context.Context, repos []*types.Repo, p authz.Perms) (filte
 
 			filtered = append(filtered, r)
 		}
+		e.add(AuthzRulePublicRepository, true, "", filtered, "The repository is public.")
 
 		// At this point, only show public repositories when:
 		//   1. The user is unauthenticated.
 		//   2. Permissions are not enforced by authz providers but NOT everyone can see all repositories.
 		//      Wouldn't reach this far when "authzAllowByDefault" is true and no authz providers.
 		if currentUser == nil || len(authzProviders) == 0 {
+			e.add(AuthzRuleNoProvider, false, "", toVerify,
+				"There are no authorization providers, so private repositories cannot be accessed.")
 			return filtered, nil
 		}
 
@@ -182,6 +300,13 @@ func authzFilter(ctx context.Context, repos []*types.Repo, p authz.Perms) (filte
 				continue
 			}
 
+			// Explaining the access of the user never changes their external accounts.
+			if e != nil {
+				e.add(AuthzRuleNoExternalAccount, false, provider.ServiceID(), toVerify,
+					fmt.Sprintf("The user has no external account for %s, so no permissions are synced for the user from it.", provider.ServiceID()))
+				continue
+			}
+
 			acct, err := provider.FetchAccount(ctx, currentUser, extAccounts)
 			if err != nil {
 				tr.LogFields(
@@ -239,6 +364,7 @@ func authzFilter(ctx context.Context, repos []*types.Repo, p authz.Perms) (filte
 		if err != nil {
 			return nil, errors.Wrap(err, "authorize repositories")
 		}
+		e.addStoredPermissions(AuthzRuleSyncedPermissions, toVerify, verified)
 
 		return append(filtered, verified...), nil
 	}
@@ -278,7 +404,11 @@ func authzFilter(ctx context.Context, repos []*types.Repo, p authz.Perms) (filte
 			}
 		}
 
-		if providerAcct == nil && currentUser != nil { // no existing external account for authz provider
+		if providerAcct == nil && e != nil {
+			// Explaining the access of the user never changes their external accounts.
+			e.add(AuthzRuleNoExternalAccount, false, authzProvider.ServiceID(), repos,
+				fmt.Sprintf("The user has no external account for %s, so only the repositories that are public on it can be accessed.", authzProvider.ServiceID()))
+		} else if providerAcct == nil && currentUser != nil { // no existing external account for authz provider
 			if pr, err := authzProvider.FetchAccount(ctx, currentUser, accts); err == nil {
 				providerAcct = pr
 				if providerAcct != nil {
@@ -315,21 +445,38 @@ func authzFilter(ctx context.Context, repos []*types.Repo, p authz.Perms) (filte
 				verified.Add(uint32(r.Repo.ID))
 			}
 		}
+		if e != nil {
+			if verified.Contains(uint32(e.repo.ID)) {
+				e.add(AuthzRuleCodeHostPermissions, true, serviceID, *ours,
+					fmt.Sprintf("The user can access the repository on %s.", serviceID))
+			} else {
+				e.add(AuthzRuleCodeHostPermissions, false, serviceID, *ours,
+					fmt.Sprintf("The user cannot access the repository on %s.", serviceID))
+			}
+		}
 
 		delete(toverify, serviceID)
 	}
 
-	if authzAllowByDefault {
-		for serviceID, rs := range toverify {
-			// 🚨 SECURITY: Defensively bar access to repos with no external repo spec (we don't know
-			// where they came from, so can't reliably enforce permissions).
-			if serviceID == "" {
-				continue
-			}
+	for serviceID, rs := range toverify {
+		// 🚨 SECURITY: Defensively bar access to repos with no external repo spec (we don't know
+		// where they came from, so can't reliably enforce permissions).
+		if serviceID == "" {
+			e.add(AuthzRuleNoProvider, false, "", *rs,
+				"The repository has no external repository spec, so its permissions cannot be enforced.")
+			continue
+		}
 
-			for _, r := range *rs {
-				verified.Add(uint32(r.ID))
-			}
+		if !authzAllowByDefault {
+			e.add(AuthzRuleNoProvider, false, "", *rs,
+				`The code host of the repository has no authorization provider and "authz.allowByDefault" is disabled.`)
+			continue
+		}
+
+		e.add(AuthzRuleAllowedByDefault, true, "", *rs,
+			`The code host of the repository has no authorization provider and "authz.allowByDefault" is enabled.`)
+		for _, r := range *rs {
+			verified.Add(uint32(r.ID))
 		}
 	}
 
@@ -345,6 +492,80 @@ func authzFilter(ctx context.Context, repos []*types.Repo, p authz.Perms) (filte
 	return filtered, nil
 }
 
+// AuthzRule is a rule of the enforcement policy of authzFilter.
+type AuthzRule string
+
+// The rules of the enforcement policy of authzFilter.
+const (
+	AuthzRuleSiteAdmin           AuthzRule = "SITE_ADMIN"
+	AuthzRulePublicRepository    AuthzRule = "PUBLIC_REPOSITORY"
+	AuthzRuleAllowedByDefault    AuthzRule = "ALLOWED_BY_DEFAULT"
+	AuthzRuleNoProvider          AuthzRule = "NO_PROVIDER"
+	AuthzRuleExplicitPermissions AuthzRule = "EXPLICIT_PERMISSIONS"
+	AuthzRuleSyncedPermissions   AuthzRule = "SYNCED_PERMISSIONS"
+	AuthzRuleCodeHostPermissions AuthzRule = "CODE_HOST_PERMISSIONS"
+	AuthzRuleNoExternalAccount   AuthzRule = "NO_EXTERNAL_ACCOUNT"
+)
+
+// AuthzDecision is a decision the enforcement policy of authzFilter makes for a repository.
+type AuthzDecision struct {
+	// The rule that makes the decision.
+	Rule AuthzRule
+	// Whether the decision grants access to the repository.
+	Allowed bool
+	// The service ID of the authz provider that the decision is about, if any.
+	Provider string
+	// A human-readable description of the decision.
+	Description string
+}
+
+// authzExplanation records the decisions the enforcement policy of authzFilter makes for a
+// repository. All of its methods are no-ops on a nil explanation.
+type authzExplanation struct {
+	repo      *types.Repo
+	decisions []AuthzDecision
+}
+
+// add records a decision made for the repositories, if it is about the explained repository, i.e.
+// the repository is one of them and belongs to the code host of the authz provider (if any).
+func (e *authzExplanation) add(rule AuthzRule, allowed bool, provider string, repos []*types.Repo, description string) {
+	if e == nil || !e.includes(repos) {
+		return
+	}
+	if provider != "" && provider != e.repo.ExternalRepo.ServiceID {
+		return
+	}
+	e.decisions = append(e.decisions, AuthzDecision{
+		Rule:        rule,
+		Allowed:     allowed,
+		Provider:    provider,
+		Description: description,
+	})
+}
+
+// addStoredPermissions records the decision made by checking the repositories against the
+// permissions stored in the database, which authorize the verified repositories.
+func (e *authzExplanation) addStoredPermissions(rule AuthzRule, repos, verified []*types.Repo) {
+	if e.includes(verified) {
+		e.add(rule, true, "", repos, "The permissions of the user include the repository.")
+	} else {
+		e.add(rule, false, "", repos, "Neither the permissions nor the groups of the user include the repository.")
+	}
+}
+
+// includes returns true if the explained repository is one of the repositories.
+func (e *authzExplanation) includes(repos []*types.Repo) bool {
+	if e == nil {
+		return false
+	}
+	for _, r := range repos {
+		if r.ID == e.repo.ID {
+			return true
+		}
+	}
+	return false
+}
+
 // isInternalActor returns true if the actor represents an internal agent (i.e., non-user-bound
 // request that originates from within Sourcegraph itself).
 //
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"net/url"
 	"reflect"
+	"regexp"
 	"strconv"
 	"testing"
 
@@ -793,31 +794,6 @@ func Test_authzFilter_permissionsUserMapping(t *testing.T) {
 		repos     []*types.Repo
 		expectErr string
 	}{
-		// 🚨 SECURITY: We need to make sure the behavior is the same for both "has repos" and "no repos".
-		// This is to ensure we always check conflict as the first step.
-		{
-			name: "site configuration conflict with code host authz providers: has repos",
-			providers: []authz.Provider{
-				&MockAuthzProvider{
-					serviceID:   "https://gitlab.mine/",
-					serviceType: "gitlab",
-				},
-			},
-			repos:     makeRepos("gitlab.mine/u1/r0"),
-			expectErr: "The permissions user mapping (site configuration `permissions.userMapping`) cannot be enabled when other authorization providers are in use, please contact site admin to resolve it.",
-		},
-		{
-			name: "site configuration conflict with code host authz providers: no repos",
-			providers: []authz.Provider{
-				&MockAuthzProvider{
-					serviceID:   "https://gitlab.mine/",
-					serviceType: "gitlab",
-				},
-			},
-			repos:     []*types.Repo{},
-			expectErr: "The permissions user mapping (site configuration `permissions.userMapping`) cannot be enabled when other authorization providers are in use, please contact site admin to resolve it.",
-		},
-
 		{
 			name:      "does not allow anonymous access when permissions user mapping is enabled",
 			repos:     []*types.Repo{},
@@ -862,6 +838,51 @@ func Test_authzFilter_permissionsUserMapping(t *testing.T) {
 			t.Fatal("!calledAuthorizedRepos")
 		}
 	})
+
+	t.Run("explicit permissions apply to repositories without authz provider", func(t *testing.T) {
+		authz.SetProviders(false, []authz.Provider{
+			&MockAuthzProvider{
+				serviceID:   "https://gitlab.mine/",
+				serviceType: "gitlab",
+				perms: map[extsvc.Account]map[api.RepoName]authz.Perms{
+					{}: {"gitlab.mine/u1/r0": authz.Read},
+				},
+			},
+		})
+		defer authz.SetProviders(true, nil)
+
+		user := &types.User{ID: 1}
+		Mocks.Users.GetByCurrentAuthUser = func(context.Context) (*types.User, error) {
+			return user, nil
+		}
+		Mocks.ExternalAccounts.List = func(ExternalAccountsListOptions) ([]*extsvc.Account, error) {
+			return nil, nil
+		}
+		defer func() { Mocks = MockStores{} }()
+		ctx := actor.WithActor(context.Background(), &actor.Actor{UID: user.ID})
+
+		gitlabRepo := makeRepo("gitlab.mine/u1/r0", 1, true)
+		gitlabHidden := makeRepo("gitlab.mine/u1/r1", 2, true)
+		gitoliteRepo := makeRepo("gitolite.mine/r0", 3, false)
+		gitoliteHidden := makeRepo("gitolite.mine/r1", 4, false)
+
+		Mocks.Authz.AuthorizedRepos = func(_ context.Context, args *AuthorizedReposArgs) ([]*types.Repo, error) {
+			for _, r := range args.Repos {
+				if r.ExternalRepo.ServiceID == "https://gitlab.mine/" {
+					return nil, fmt.Errorf("repository %q has an authz provider", r.Name)
+				}
+			}
+			return []*types.Repo{gitoliteRepo}, nil
+		}
+
+		repos, err := authzFilter(ctx, []*types.Repo{gitoliteRepo, gitlabHidden, gitlabRepo, gitoliteHidden}, authz.Read)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff([]*types.Repo{gitoliteRepo, gitlabRepo}, repos); diff != "" {
+			t.Fatal(diff)
+		}
+	})
 }
 
 func Test_authzFilter_permissionsBackgroudSync(t *testing.T) {
@@ -1174,3 +1195,27 @@ func makeReposFromIDs(ids ...api.RepoID) []*types.Repo {
 	}
 	return repos
 }
+
+func Test_authzFilter_repoPattern(t *testing.T) {
+	authz.SetProviders(true, nil)
+	defer authz.SetProviders(true, nil)
+
+	Mocks.Users.GetByCurrentAuthUser = func(ctx context.Context) (*types.User, error) {
+		return &types.User{ID: 1, SiteAdmin: true}, nil
+	}
+	defer func() { Mocks = MockStores{} }()
+
+	repos := makeRepos("github.com/foo/a", "github.com/foo/b", "github.com/bar/c")
+
+	ctx := actor.WithActor(context.Background(), &actor.Actor{
+		UID:         1,
+		RepoPattern: regexp.MustCompile(`^github\.com/foo/`),
+	})
+	filtered, err := authzFilter(ctx, repos, authz.Read)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]string{"github.com/foo/a", "github.com/foo/b"}, getNames(filtered)); diff != "" {
+		t.Fatalf("unexpected repos (-want +got):\n%s", diff)
+	}
+}
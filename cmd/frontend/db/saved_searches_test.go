@@ -344,11 +344,11 @@ func TestListSavedSearchesByUserID(t *testing.T) {
 		t.Fatalf("no saved search returned, org2 saved search create failed")
 	}
 
-	_, err = OrgMembers.Create(ctx, org1.ID, userID)
+	_, err = OrgMembers.Create(ctx, org1.ID, userID, types.OrgRoleMember)
 	if err != nil {
 		t.Fatal(err)
 	}
-	_, err = OrgMembers.Create(ctx, org2.ID, userID)
+	_, err = OrgMembers.Create(ctx, org2.ID, userID, types.OrgRoleMember)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -2,6 +2,7 @@ package db
 
 var (
 	AccessTokens              = &accessTokens{}
+	AuditLogs                 = &auditLogs{}
 	ExternalServices          = &ExternalServicesStore{}
 	DefaultRepos              = &defaultRepos{}
 	DiscussionThreads         = &discussionThreads{}
@@ -16,6 +17,8 @@ var (
 	Settings                  = &settings{}
 	Users                     = &users{}
 	UserEmails                = &userEmails{}
+	UserMFA                   = &userMFA{}
+	UserSessions              = &userSessions{}
 	EventLogs                 = &eventLogs{}
 
 	SurveyResponses = &surveyResponses{}
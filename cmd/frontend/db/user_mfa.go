@@ -0,0 +1,331 @@
+package db
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"errors"
+	"time"
+
+	multierror "github.com/hashicorp/go-multierror"
+	"github.com/sourcegraph/sourcegraph/internal/db/dbconn"
+)
+
+// UserTOTP is a user's enrollment of an authenticator app that generates time-based one-time
+// passwords (TOTP).
+type UserTOTP struct {
+	UserID          int32
+	EncryptedSecret string     // the base32-encoded shared secret, encrypted with package secret
+	EnabledAt       *time.Time // nil until the user confirms the enrollment with a valid code
+	LastUsedStep    int64      // the time step of the last code used
+	CreatedAt       time.Time
+}
+
+// Enabled reports whether the user has confirmed the enrollment.
+func (t *UserTOTP) Enabled() bool { return t.EnabledAt != nil }
+
+// WebAuthnCredential is a security key that a user registered as a second authentication factor.
+type WebAuthnCredential struct {
+	ID           int32
+	UserID       int32
+	Name         string // the name the user gave the security key
+	CredentialID []byte
+	PublicKey    []byte // the COSE_Key-encoded public key
+	SignCount    uint32 // the last signature counter reported by the security key
+	CreatedAt    time.Time
+	LastUsedAt   *time.Time
+}
+
+var (
+	// ErrTOTPAlreadyEnabled occurs when a user who already has TOTP enabled starts another
+	// enrollment.
+	ErrTOTPAlreadyEnabled = errors.New("two-factor authentication with an authenticator app is already enabled")
+
+	// ErrNoPendingTOTP occurs when a user confirms a TOTP enrollment that wasn't started.
+	ErrNoPendingTOTP = errors.New("no pending enrollment of an authenticator app")
+
+	// ErrWebAuthnCredentialNotFound occurs when a security key that a database operation expects
+	// to exist doesn't exist.
+	ErrWebAuthnCredentialNotFound = errors.New("security key not found")
+)
+
+// userMFA provides access to the multi-factor authentication (MFA) settings of users: TOTP
+// enrollments, security keys, and recovery codes.
+//
+// 🚨 SECURITY: The secrets stored here are second authentication factors. Callers must ensure that
+// the actor is allowed to manage the user's MFA settings.
+type userMFA struct{}
+
+// IsEnabled reports whether the user has a second authentication factor (a confirmed TOTP
+// enrollment or a security key).
+func (*userMFA) IsEnabled(ctx context.Context, userID int32) (bool, error) {
+	if Mocks.UserMFA.IsEnabled != nil {
+		return Mocks.UserMFA.IsEnabled(userID)
+	}
+
+	var enabled bool
+	err := dbconn.Global.QueryRowContext(ctx, `
+SELECT EXISTS (SELECT 1 FROM user_totp WHERE user_id=$1 AND enabled_at IS NOT NULL)
+	OR EXISTS (SELECT 1 FROM user_webauthn_credentials WHERE user_id=$1)
+`, userID).Scan(&enabled)
+	return enabled, err
+}
+
+// GetTOTP returns the user's TOTP enrollment, or nil if there is none.
+func (*userMFA) GetTOTP(ctx context.Context, userID int32) (*UserTOTP, error) {
+	if Mocks.UserMFA.GetTOTP != nil {
+		return Mocks.UserMFA.GetTOTP(userID)
+	}
+
+	t := UserTOTP{UserID: userID}
+	err := dbconn.Global.QueryRowContext(ctx,
+		"SELECT encrypted_secret, enabled_at, last_used_step, created_at FROM user_totp WHERE user_id=$1",
+		userID,
+	).Scan(&t.EncryptedSecret, &t.EnabledAt, &t.LastUsedStep, &t.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// CreatePendingTOTP starts a TOTP enrollment of the user with the given encrypted secret, replacing
+// any pending enrollment. It returns ErrTOTPAlreadyEnabled if the user has already enabled TOTP.
+func (*userMFA) CreatePendingTOTP(ctx context.Context, userID int32, encryptedSecret string) error {
+	if Mocks.UserMFA.CreatePendingTOTP != nil {
+		return Mocks.UserMFA.CreatePendingTOTP(userID, encryptedSecret)
+	}
+
+	res, err := dbconn.Global.ExecContext(ctx, `
+INSERT INTO user_totp(user_id, encrypted_secret) VALUES($1, $2)
+ON CONFLICT (user_id) DO UPDATE SET encrypted_secret=excluded.encrypted_secret, last_used_step=0, created_at=now()
+WHERE user_totp.enabled_at IS NULL
+`, userID, encryptedSecret)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return ErrTOTPAlreadyEnabled
+	}
+	return nil
+}
+
+// EnableTOTP confirms the user's pending TOTP enrollment, after the user entered a valid code for
+// the given time step.
+func (*userMFA) EnableTOTP(ctx context.Context, userID int32, step int64) error {
+	if Mocks.UserMFA.EnableTOTP != nil {
+		return Mocks.UserMFA.EnableTOTP(userID, step)
+	}
+
+	res, err := dbconn.Global.ExecContext(ctx,
+		"UPDATE user_totp SET enabled_at=now(), last_used_step=$2 WHERE user_id=$1 AND enabled_at IS NULL",
+		userID, step,
+	)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return ErrNoPendingTOTP
+	}
+	return nil
+}
+
+// UseTOTPStep records that the user signed in with a TOTP code of the given time step. It returns
+// false if a code of the same or a later time step was already used.
+//
+// 🚨 SECURITY: This prevents a code from being used more than once (e.g., by someone who watched
+// the user enter it). Callers must check the result before accepting the code.
+func (*userMFA) UseTOTPStep(ctx context.Context, userID int32, step int64) (bool, error) {
+	if Mocks.UserMFA.UseTOTPStep != nil {
+		return Mocks.UserMFA.UseTOTPStep(userID, step)
+	}
+
+	res, err := dbconn.Global.ExecContext(ctx,
+		"UPDATE user_totp SET last_used_step=$2 WHERE user_id=$1 AND enabled_at IS NOT NULL AND last_used_step<$2",
+		userID, step,
+	)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n == 1, err
+}
+
+// DeleteTOTP deletes the user's TOTP enrollment (pending or enabled).
+func (*userMFA) DeleteTOTP(ctx context.Context, userID int32) error {
+	if Mocks.UserMFA.DeleteTOTP != nil {
+		return Mocks.UserMFA.DeleteTOTP(userID)
+	}
+
+	_, err := dbconn.Global.ExecContext(ctx, "DELETE FROM user_totp WHERE user_id=$1", userID)
+	return err
+}
+
+// SetRecoveryCodes replaces the user's recovery codes with the given codes. Only hashes of the
+// codes are stored.
+func (*userMFA) SetRecoveryCodes(ctx context.Context, userID int32, codes []string) (err error) {
+	if Mocks.UserMFA.SetRecoveryCodes != nil {
+		return Mocks.UserMFA.SetRecoveryCodes(userID, codes)
+	}
+
+	tx, err := dbconn.Global.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			if rollErr := tx.Rollback(); rollErr != nil {
+				err = multierror.Append(err, rollErr)
+			}
+			return
+		}
+		err = tx.Commit()
+	}()
+
+	if _, err = tx.ExecContext(ctx, "DELETE FROM user_recovery_codes WHERE user_id=$1", userID); err != nil {
+		return err
+	}
+	for _, code := range codes {
+		if _, err = tx.ExecContext(ctx, "INSERT INTO user_recovery_codes(user_id, code_sha256) VALUES($1, $2)", userID, hashRecoveryCode(code)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// UseRecoveryCode marks the user's unused recovery code as used. It returns false if the user has
+// no such unused recovery code.
+func (*userMFA) UseRecoveryCode(ctx context.Context, userID int32, code string) (bool, error) {
+	if Mocks.UserMFA.UseRecoveryCode != nil {
+		return Mocks.UserMFA.UseRecoveryCode(userID, code)
+	}
+
+	res, err := dbconn.Global.ExecContext(ctx,
+		"UPDATE user_recovery_codes SET used_at=now() WHERE user_id=$1 AND code_sha256=$2 AND used_at IS NULL",
+		userID, hashRecoveryCode(code),
+	)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+// CountRecoveryCodes returns the number of the user's unused recovery codes.
+func (*userMFA) CountRecoveryCodes(ctx context.Context, userID int32) (int, error) {
+	if Mocks.UserMFA.CountRecoveryCodes != nil {
+		return Mocks.UserMFA.CountRecoveryCodes(userID)
+	}
+
+	var count int
+	err := dbconn.Global.QueryRowContext(ctx, "SELECT COUNT(*) FROM user_recovery_codes WHERE user_id=$1 AND used_at IS NULL", userID).Scan(&count)
+	return count, err
+}
+
+func hashRecoveryCode(code string) []byte {
+	h := sha256.Sum256([]byte(code))
+	return h[:]
+}
+
+// ListWebAuthnCredentials lists the user's security keys, oldest first.
+func (*userMFA) ListWebAuthnCredentials(ctx context.Context, userID int32) ([]*WebAuthnCredential, error) {
+	if Mocks.UserMFA.ListWebAuthnCredentials != nil {
+		return Mocks.UserMFA.ListWebAuthnCredentials(userID)
+	}
+
+	rows, err := dbconn.Global.QueryContext(ctx, `
+SELECT id, user_id, name, credential_id, public_key, sign_count, created_at, last_used_at
+FROM user_webauthn_credentials
+WHERE user_id=$1
+ORDER BY id ASC
+`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var creds []*WebAuthnCredential
+	for rows.Next() {
+		var c WebAuthnCredential
+		var signCount int64
+		if err := rows.Scan(&c.ID, &c.UserID, &c.Name, &c.CredentialID, &c.PublicKey, &signCount, &c.CreatedAt, &c.LastUsedAt); err != nil {
+			return nil, err
+		}
+		c.SignCount = uint32(signCount)
+		creds = append(creds, &c)
+	}
+	return creds, rows.Err()
+}
+
+// CreateWebAuthnCredential registers a security key. The ID and CreatedAt fields of the given
+// credential are set upon success.
+func (*userMFA) CreateWebAuthnCredential(ctx context.Context, c *WebAuthnCredential) error {
+	if Mocks.UserMFA.CreateWebAuthnCredential != nil {
+		return Mocks.UserMFA.CreateWebAuthnCredential(c)
+	}
+
+	return dbconn.Global.QueryRowContext(ctx,
+		"INSERT INTO user_webauthn_credentials(user_id, name, credential_id, public_key, sign_count) VALUES($1, $2, $3, $4, $5) RETURNING id, created_at",
+		c.UserID, c.Name, c.CredentialID, c.PublicKey, int64(c.SignCount),
+	).Scan(&c.ID, &c.CreatedAt)
+}
+
+// UpdateWebAuthnCredentialSignCount records that the security key was used to sign in and stores
+// the signature counter it reported. It returns false if the stored signature counter is no longer
+// oldSignCount (because the security key was used concurrently).
+func (*userMFA) UpdateWebAuthnCredentialSignCount(ctx context.Context, id int32, oldSignCount, newSignCount uint32) (bool, error) {
+	if Mocks.UserMFA.UpdateWebAuthnCredentialSignCount != nil {
+		return Mocks.UserMFA.UpdateWebAuthnCredentialSignCount(id, oldSignCount, newSignCount)
+	}
+
+	res, err := dbconn.Global.ExecContext(ctx,
+		"UPDATE user_webauthn_credentials SET sign_count=$3, last_used_at=now() WHERE id=$1 AND sign_count=$2",
+		id, int64(oldSignCount), int64(newSignCount),
+	)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n == 1, err
+}
+
+// DeleteWebAuthnCredential deletes the user's security key with the given ID. It returns
+// ErrWebAuthnCredentialNotFound if the user has no such security key.
+func (*userMFA) DeleteWebAuthnCredential(ctx context.Context, userID, id int32) error {
+	if Mocks.UserMFA.DeleteWebAuthnCredential != nil {
+		return Mocks.UserMFA.DeleteWebAuthnCredential(userID, id)
+	}
+
+	res, err := dbconn.Global.ExecContext(ctx, "DELETE FROM user_webauthn_credentials WHERE user_id=$1 AND id=$2", userID, id)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return ErrWebAuthnCredentialNotFound
+	}
+	return nil
+}
+
+type MockUserMFA struct {
+	IsEnabled                         func(userID int32) (bool, error)
+	GetTOTP                           func(userID int32) (*UserTOTP, error)
+	CreatePendingTOTP                 func(userID int32, encryptedSecret string) error
+	EnableTOTP                        func(userID int32, step int64) error
+	UseTOTPStep                       func(userID int32, step int64) (bool, error)
+	DeleteTOTP                        func(userID int32) error
+	SetRecoveryCodes                  func(userID int32, codes []string) error
+	UseRecoveryCode                   func(userID int32, code string) (bool, error)
+	CountRecoveryCodes                func(userID int32) (int, error)
+	ListWebAuthnCredentials           func(userID int32) ([]*WebAuthnCredential, error)
+	CreateWebAuthnCredential          func(c *WebAuthnCredential) error
+	UpdateWebAuthnCredentialSignCount func(id int32, oldSignCount, newSignCount uint32) (bool, error)
+	DeleteWebAuthnCredential          func(userID, id int32) error
+}
@@ -0,0 +1,172 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/internal/db/dbtesting"
+)
+
+func TestUserMFA_TOTP(t *testing.T) {
+	dbtesting.SetupGlobalTestDB(t)
+	ctx := context.Background()
+
+	user, err := Users.Create(ctx, NewUser{Username: "u"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if totp, err := UserMFA.GetTOTP(ctx, user.ID); err != nil || totp != nil {
+		t.Fatalf("got %+v, %v, want no TOTP enrollment", totp, err)
+	}
+	if err := UserMFA.EnableTOTP(ctx, user.ID, 10); err != ErrNoPendingTOTP {
+		t.Fatalf("got error %v, want %v", err, ErrNoPendingTOTP)
+	}
+
+	// A pending enrollment can be replaced.
+	for _, encryptedSecret := range []string{"S1", "S2"} {
+		if err := UserMFA.CreatePendingTOTP(ctx, user.ID, encryptedSecret); err != nil {
+			t.Fatal(err)
+		}
+	}
+	totp, err := UserMFA.GetTOTP(ctx, user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if totp.EncryptedSecret != "S2" || totp.Enabled() {
+		t.Fatalf("got %+v, want pending enrollment with secret S2", totp)
+	}
+	if enabled, err := UserMFA.IsEnabled(ctx, user.ID); err != nil || enabled {
+		t.Fatalf("got enabled %v, %v, want not enabled", enabled, err)
+	}
+
+	if err := UserMFA.EnableTOTP(ctx, user.ID, 10); err != nil {
+		t.Fatal(err)
+	}
+	if enabled, err := UserMFA.IsEnabled(ctx, user.ID); err != nil || !enabled {
+		t.Fatalf("got enabled %v, %v, want enabled", enabled, err)
+	}
+	if err := UserMFA.CreatePendingTOTP(ctx, user.ID, "S3"); err != ErrTOTPAlreadyEnabled {
+		t.Fatalf("got error %v, want %v", err, ErrTOTPAlreadyEnabled)
+	}
+
+	// Codes of the time step used to confirm the enrollment (or earlier) can't be reused.
+	for step, want := range map[int64]bool{9: false, 10: false, 11: true} {
+		if ok, err := UserMFA.UseTOTPStep(ctx, user.ID, step); err != nil || ok != want {
+			t.Errorf("step %d: got %v, %v, want %v", step, ok, err, want)
+		}
+	}
+
+	if err := UserMFA.DeleteTOTP(ctx, user.ID); err != nil {
+		t.Fatal(err)
+	}
+	if totp, err := UserMFA.GetTOTP(ctx, user.ID); err != nil || totp != nil {
+		t.Fatalf("got %+v, %v, want no TOTP enrollment", totp, err)
+	}
+}
+
+func TestUserMFA_RecoveryCodes(t *testing.T) {
+	dbtesting.SetupGlobalTestDB(t)
+	ctx := context.Background()
+
+	user, err := Users.Create(ctx, NewUser{Username: "u"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := UserMFA.SetRecoveryCodes(ctx, user.ID, []string{"a", "b"}); err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := UserMFA.UseRecoveryCode(ctx, user.ID, "a"); err != nil || !ok {
+		t.Fatalf("got %v, %v, want recovery code to be accepted", ok, err)
+	}
+	if ok, err := UserMFA.UseRecoveryCode(ctx, user.ID, "a"); err != nil || ok {
+		t.Fatalf("got %v, %v, want used recovery code to be rejected", ok, err)
+	}
+	if count, err := UserMFA.CountRecoveryCodes(ctx, user.ID); err != nil || count != 1 {
+		t.Fatalf("got %d, %v, want 1 unused recovery code", count, err)
+	}
+
+	if err := UserMFA.SetRecoveryCodes(ctx, user.ID, []string{"c"}); err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := UserMFA.UseRecoveryCode(ctx, user.ID, "b"); err != nil || ok {
+		t.Fatalf("got %v, %v, want replaced recovery code to be rejected", ok, err)
+	}
+}
+
+func TestUserMFA_WebAuthnCredentials(t *testing.T) {
+	dbtesting.SetupGlobalTestDB(t)
+	ctx := context.Background()
+
+	user, err := Users.Create(ctx, NewUser{Username: "u"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := &WebAuthnCredential{UserID: user.ID, Name: "key", CredentialID: []byte("id"), PublicKey: []byte("pk"), SignCount: 1}
+	if err := UserMFA.CreateWebAuthnCredential(ctx, c); err != nil {
+		t.Fatal(err)
+	}
+	if c.ID == 0 || c.CreatedAt.IsZero() {
+		t.Fatalf("credential not populated after creation: %+v", c)
+	}
+	if err := UserMFA.CreateWebAuthnCredential(ctx, &WebAuthnCredential{UserID: user.ID, Name: "dup", CredentialID: []byte("id"), PublicKey: []byte("pk")}); err == nil {
+		t.Fatal("expected an error for a duplicate credential ID")
+	}
+	if enabled, err := UserMFA.IsEnabled(ctx, user.ID); err != nil || !enabled {
+		t.Fatalf("got enabled %v, %v, want enabled", enabled, err)
+	}
+
+	if ok, err := UserMFA.UpdateWebAuthnCredentialSignCount(ctx, c.ID, 1, 2); err != nil || !ok {
+		t.Fatalf("got %v, %v, want sign count to be updated", ok, err)
+	}
+	if ok, err := UserMFA.UpdateWebAuthnCredentialSignCount(ctx, c.ID, 1, 3); err != nil || ok {
+		t.Fatalf("got %v, %v, want stale sign count update to be rejected", ok, err)
+	}
+	creds, err := UserMFA.ListWebAuthnCredentials(ctx, user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(creds) != 1 || creds[0].SignCount != 2 || creds[0].LastUsedAt == nil {
+		t.Fatalf("unexpected credentials %+v", creds)
+	}
+
+	if err := UserMFA.DeleteWebAuthnCredential(ctx, user.ID+1, c.ID); err != ErrWebAuthnCredentialNotFound {
+		t.Fatalf("got error %v, want %v", err, ErrWebAuthnCredentialNotFound)
+	}
+	if err := UserMFA.DeleteWebAuthnCredential(ctx, user.ID, c.ID); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestUsers_RecordFailedSignIn(t *testing.T) {
+	dbtesting.SetupGlobalTestDB(t)
+	ctx := context.Background()
+
+	user, err := Users.Create(ctx, NewUser{Username: "u"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 1; i <= 3; i++ {
+		lockedUntil, err := Users.RecordFailedSignIn(ctx, user.ID, 3, time.Hour)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if locked := lockedUntil != nil; locked != (i == 3) {
+			t.Fatalf("attempt %d: got locked %v", i, locked)
+		}
+	}
+	if lockedUntil, err := Users.SignInLockedUntil(ctx, user.ID); err != nil || lockedUntil == nil {
+		t.Fatalf("got %v, %v, want account to be locked", lockedUntil, err)
+	}
+
+	if err := Users.ResetFailedSignIns(ctx, user.ID); err != nil {
+		t.Fatal(err)
+	}
+	if lockedUntil, err := Users.SignInLockedUntil(ctx, user.ID); err != nil || lockedUntil != nil {
+		t.Fatalf("got %v, %v, want account to be unlocked", lockedUntil, err)
+	}
+}
@@ -0,0 +1,183 @@
+package db
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/internal/db/dbconn"
+)
+
+// UserSession is a session of a user who signed in with a session cookie (e.g. in a web browser).
+type UserSession struct {
+	ID           int64
+	UserID       int32
+	IP           string // the IP address of the client the session was last active from
+	UserAgent    string // the user agent of the client the session was last active from
+	CreatedAt    time.Time
+	LastActiveAt time.Time
+	ExpiresAt    time.Time
+}
+
+// ErrUserSessionNotFound occurs when a database operation expects a specific session to exist but
+// it does not exist (or has expired).
+var ErrUserSessionNotFound = errors.New("session not found")
+
+// userSessions records the sessions of users, so that they can be listed and revoked. The session
+// data itself (including the actor) is stored in the session store, keyed by the session cookie;
+// it refers to the session recorded here with a random key.
+//
+// 🚨 SECURITY: Callers must ensure that the actor is allowed to view or revoke the user's
+// sessions.
+type userSessions struct{}
+
+// Create records a new session of the user with the given key. It sets s.ID, s.CreatedAt and
+// s.LastActiveAt. It also deletes the expired sessions of the user.
+func (*userSessions) Create(ctx context.Context, key string, s *UserSession) error {
+	if Mocks.UserSessions.Create != nil {
+		return Mocks.UserSessions.Create(key, s)
+	}
+
+	if _, err := dbconn.Global.ExecContext(ctx, "DELETE FROM user_sessions WHERE user_id=$1 AND expires_at < now()", s.UserID); err != nil {
+		return err
+	}
+	return dbconn.Global.QueryRowContext(ctx, `
+INSERT INTO user_sessions(user_id, key_sha256, ip, user_agent, expires_at)
+VALUES($1, $2, $3, $4, $5)
+RETURNING id, created_at, last_active_at
+`, s.UserID, hashSessionKey(key), s.IP, s.UserAgent, s.ExpiresAt).Scan(&s.ID, &s.CreatedAt, &s.LastActiveAt)
+}
+
+// GetByKey returns the unexpired session with the given key. If there is none (e.g. because the
+// session was revoked), ErrUserSessionNotFound is returned.
+func (*userSessions) GetByKey(ctx context.Context, key string) (*UserSession, error) {
+	if Mocks.UserSessions.GetByKey != nil {
+		return Mocks.UserSessions.GetByKey(key)
+	}
+
+	return getUserSession(ctx, "key_sha256=$1 AND expires_at > now()", hashSessionKey(key))
+}
+
+// GetByID returns the unexpired session with the given ID. If there is none,
+// ErrUserSessionNotFound is returned.
+func (*userSessions) GetByID(ctx context.Context, id int64) (*UserSession, error) {
+	if Mocks.UserSessions.GetByID != nil {
+		return Mocks.UserSessions.GetByID(id)
+	}
+
+	return getUserSession(ctx, "id=$1 AND expires_at > now()", id)
+}
+
+func getUserSession(ctx context.Context, cond string, arg interface{}) (*UserSession, error) {
+	var s UserSession
+	err := dbconn.Global.QueryRowContext(ctx,
+		"SELECT id, user_id, ip, user_agent, created_at, last_active_at, expires_at FROM user_sessions WHERE "+cond,
+		arg,
+	).Scan(&s.ID, &s.UserID, &s.IP, &s.UserAgent, &s.CreatedAt, &s.LastActiveAt, &s.ExpiresAt)
+	if err == sql.ErrNoRows {
+		return nil, ErrUserSessionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// ListByUser returns the unexpired sessions of the user, most recently active first.
+func (*userSessions) ListByUser(ctx context.Context, userID int32) ([]*UserSession, error) {
+	if Mocks.UserSessions.ListByUser != nil {
+		return Mocks.UserSessions.ListByUser(userID)
+	}
+
+	rows, err := dbconn.Global.QueryContext(ctx, `
+SELECT id, user_id, ip, user_agent, created_at, last_active_at, expires_at FROM user_sessions
+WHERE user_id=$1 AND expires_at > now()
+ORDER BY last_active_at DESC, id DESC
+`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var sessions []*UserSession
+	for rows.Next() {
+		var s UserSession
+		if err := rows.Scan(&s.ID, &s.UserID, &s.IP, &s.UserAgent, &s.CreatedAt, &s.LastActiveAt, &s.ExpiresAt); err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, &s)
+	}
+	return sessions, rows.Err()
+}
+
+// Touch records that the session was active now from the client with the given IP address and
+// user agent, and extends the session until expiresAt.
+func (*userSessions) Touch(ctx context.Context, id int64, ip, userAgent string, expiresAt time.Time) error {
+	if Mocks.UserSessions.Touch != nil {
+		return Mocks.UserSessions.Touch(id, ip, userAgent, expiresAt)
+	}
+
+	_, err := dbconn.Global.ExecContext(ctx,
+		"UPDATE user_sessions SET ip=$2, user_agent=$3, last_active_at=now(), expires_at=$4 WHERE id=$1",
+		id, ip, userAgent, expiresAt,
+	)
+	return err
+}
+
+// Delete revokes the session of the user with the given ID. If there is none,
+// ErrUserSessionNotFound is returned.
+func (*userSessions) Delete(ctx context.Context, userID int32, id int64) error {
+	if Mocks.UserSessions.Delete != nil {
+		return Mocks.UserSessions.Delete(userID, id)
+	}
+
+	res, err := dbconn.Global.ExecContext(ctx, "DELETE FROM user_sessions WHERE user_id=$1 AND id=$2", userID, id)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return ErrUserSessionNotFound
+	}
+	return nil
+}
+
+// DeleteByKey revokes the session with the given key, if any. It is used when the user signs out.
+func (*userSessions) DeleteByKey(ctx context.Context, key string) error {
+	if Mocks.UserSessions.DeleteByKey != nil {
+		return Mocks.UserSessions.DeleteByKey(key)
+	}
+
+	_, err := dbconn.Global.ExecContext(ctx, "DELETE FROM user_sessions WHERE key_sha256=$1", hashSessionKey(key))
+	return err
+}
+
+// DeleteAllByUser revokes all sessions of the user, except the session with the ID exceptID (if
+// not zero).
+func (*userSessions) DeleteAllByUser(ctx context.Context, userID int32, exceptID int64) error {
+	if Mocks.UserSessions.DeleteAllByUser != nil {
+		return Mocks.UserSessions.DeleteAllByUser(userID, exceptID)
+	}
+
+	_, err := dbconn.Global.ExecContext(ctx, "DELETE FROM user_sessions WHERE user_id=$1 AND id<>$2", userID, exceptID)
+	return err
+}
+
+func hashSessionKey(key string) []byte {
+	h := sha256.Sum256([]byte(key))
+	return h[:]
+}
+
+type MockUserSessions struct {
+	Create          func(key string, s *UserSession) error
+	GetByKey        func(key string) (*UserSession, error)
+	GetByID         func(id int64) (*UserSession, error)
+	ListByUser      func(userID int32) ([]*UserSession, error)
+	Touch           func(id int64, ip, userAgent string, expiresAt time.Time) error
+	Delete          func(userID int32, id int64) error
+	DeleteByKey     func(key string) error
+	DeleteAllByUser func(userID int32, exceptID int64) error
+}
@@ -0,0 +1,128 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/internal/db/dbtesting"
+)
+
+func TestUserSessions(t *testing.T) {
+	dbtesting.SetupGlobalTestDB(t)
+	ctx := context.Background()
+
+	user, err := Users.Create(ctx, NewUser{Username: "u"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expiresAt := time.Now().Add(time.Hour)
+	s1 := &UserSession{UserID: user.ID, IP: "192.0.2.1", UserAgent: "Firefox", ExpiresAt: expiresAt}
+	if err := UserSessions.Create(ctx, "k1", s1); err != nil {
+		t.Fatal(err)
+	}
+	s2 := &UserSession{UserID: user.ID, IP: "192.0.2.2", UserAgent: "Chrome", ExpiresAt: expiresAt}
+	if err := UserSessions.Create(ctx, "k2", s2); err != nil {
+		t.Fatal(err)
+	}
+	expired := &UserSession{UserID: user.ID, ExpiresAt: time.Now().Add(-time.Hour)}
+	if err := UserSessions.Create(ctx, "k3", expired); err != nil {
+		t.Fatal(err)
+	}
+
+	if s, err := UserSessions.GetByKey(ctx, "k1"); err != nil || s.ID != s1.ID || s.UserAgent != "Firefox" {
+		t.Fatalf("got %+v, %v, want session %d", s, err, s1.ID)
+	}
+	if _, err := UserSessions.GetByKey(ctx, "k3"); err != ErrUserSessionNotFound {
+		t.Fatalf("expired session: got error %v, want %v", err, ErrUserSessionNotFound)
+	}
+
+	if err := UserSessions.Touch(ctx, s1.ID, "192.0.2.3", "Firefox", expiresAt); err != nil {
+		t.Fatal(err)
+	}
+	sessions, err := UserSessions.ListByUser(ctx, user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sessions) != 2 || sessions[0].ID != s1.ID || sessions[0].IP != "192.0.2.3" || sessions[1].ID != s2.ID {
+		t.Fatalf("unexpected sessions %+v", sessions)
+	}
+
+	if err := UserSessions.Delete(ctx, user.ID+1, s1.ID); err != ErrUserSessionNotFound {
+		t.Fatalf("got error %v, want %v", err, ErrUserSessionNotFound)
+	}
+	if err := UserSessions.Delete(ctx, user.ID, s1.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := UserSessions.GetByKey(ctx, "k1"); err != ErrUserSessionNotFound {
+		t.Fatalf("revoked session: got error %v, want %v", err, ErrUserSessionNotFound)
+	}
+
+	s4 := &UserSession{UserID: user.ID, ExpiresAt: expiresAt}
+	if err := UserSessions.Create(ctx, "k4", s4); err != nil {
+		t.Fatal(err)
+	}
+	if err := UserSessions.DeleteAllByUser(ctx, user.ID, s4.ID); err != nil {
+		t.Fatal(err)
+	}
+	sessions, err = UserSessions.ListByUser(ctx, user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sessions) != 1 || sessions[0].ID != s4.ID {
+		t.Fatalf("got sessions %+v, want only session %d", sessions, s4.ID)
+	}
+
+	if err := UserSessions.DeleteByKey(ctx, "k4"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := UserSessions.GetByID(ctx, s4.ID); err != ErrUserSessionNotFound {
+		t.Fatalf("got error %v, want %v", err, ErrUserSessionNotFound)
+	}
+}
+
+func TestUserSessions_RevokedWithUser(t *testing.T) {
+	dbtesting.SetupGlobalTestDB(t)
+	ctx := context.Background()
+
+	user, err := Users.Create(ctx, NewUser{Username: "u"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherUser, err := Users.Create(ctx, NewUser{Username: "other"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expiresAt := time.Now().Add(time.Hour)
+	for key, userID := range map[string]int32{"k1": user.ID, "k2": user.ID, "k3": otherUser.ID} {
+		if err := UserSessions.Create(ctx, key, &UserSession{UserID: userID, ExpiresAt: expiresAt}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Deactivating the user revokes their sessions.
+	if err := Users.SetIsDeactivated(ctx, user.ID, true); err != nil {
+		t.Fatal(err)
+	}
+	if sessions, err := UserSessions.ListByUser(ctx, user.ID); err != nil || len(sessions) != 0 {
+		t.Fatalf("got sessions %+v, %v, want none after deactivation", sessions, err)
+	}
+	if _, err := UserSessions.GetByKey(ctx, "k3"); err != nil {
+		t.Fatalf("session of other user: got error %v, want nil", err)
+	}
+
+	// Deleting the user revokes their sessions.
+	if err := Users.SetIsDeactivated(ctx, user.ID, false); err != nil {
+		t.Fatal(err)
+	}
+	if err := UserSessions.Create(ctx, "k4", &UserSession{UserID: user.ID, ExpiresAt: expiresAt}); err != nil {
+		t.Fatal(err)
+	}
+	if err := Users.Delete(ctx, user.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := UserSessions.GetByKey(ctx, "k4"); err != ErrUserSessionNotFound {
+		t.Fatalf("got error %v, want %v after deletion", err, ErrUserSessionNotFound)
+	}
+}
@@ -425,6 +425,9 @@ func (u *users) Delete(ctx context.Context, id int32) error {
 	if _, err := tx.ExecContext(ctx, "UPDATE access_tokens SET deleted_at=now() WHERE subject_user_id=$1 OR creator_user_id=$1", id); err != nil {
 		return err
 	}
+	if _, err := tx.ExecContext(ctx, "DELETE FROM user_sessions WHERE user_id=$1", id); err != nil {
+		return err
+	}
 	if _, err := tx.ExecContext(ctx, "DELETE FROM user_emails WHERE user_id=$1", id); err != nil {
 		return err
 	}
@@ -550,6 +553,57 @@ func (u *users) SetIsSiteAdmin(ctx context.Context, id int32, isSiteAdmin bool)
 	return err
 }
 
+// SetIsDeactivated deactivates or reactivates the user. Deactivating a user also deletes the access
+// tokens they're the subject of and revokes their sessions, so that reactivating the user doesn't
+// make them valid again.
+func (u *users) SetIsDeactivated(ctx context.Context, id int32, isDeactivated bool) (err error) {
+	if Mocks.Users.SetIsDeactivated != nil {
+		return Mocks.Users.SetIsDeactivated(id, isDeactivated)
+	}
+
+	tx, err := dbconn.Global.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			rollErr := tx.Rollback()
+			if rollErr != nil {
+				err = multierror.Append(err, rollErr)
+			}
+			return
+		}
+		err = tx.Commit()
+	}()
+
+	var res sql.Result
+	if isDeactivated {
+		res, err = tx.ExecContext(ctx, "UPDATE users SET deactivated_at=COALESCE(deactivated_at, now()), updated_at=now() WHERE id=$1 AND deleted_at IS NULL", id)
+	} else {
+		res, err = tx.ExecContext(ctx, "UPDATE users SET deactivated_at=NULL, updated_at=now() WHERE id=$1 AND deleted_at IS NULL", id)
+	}
+	if err != nil {
+		return err
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return userNotFoundErr{args: []interface{}{id}}
+	}
+
+	if isDeactivated {
+		if _, err := tx.ExecContext(ctx, "UPDATE access_tokens SET deleted_at=now() WHERE subject_user_id=$1 AND deleted_at IS NULL", id); err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, "DELETE FROM user_sessions WHERE user_id=$1", id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // CheckAndDecrementInviteQuota should be called before the user (identified
 // by userID) is allowed to invite any other user. If ok is false, then the
 // user is not allowed to invite any other user (either because they've
@@ -726,7 +780,7 @@ func (u *users) getOneBySQL(ctx context.Context, query string, args ...interface
 
 // getBySQL returns users matching the SQL query, if any exist.
 func (*users) getBySQL(ctx context.Context, query string, args ...interface{}) ([]*types.User, error) {
-	rows, err := dbconn.Global.QueryContext(ctx, "SELECT u.id, u.username, u.display_name, u.avatar_url, u.created_at, u.updated_at, u.site_admin, u.passwd IS NOT NULL, u.tags FROM users u "+query, args...)
+	rows, err := dbconn.Global.QueryContext(ctx, "SELECT u.id, u.username, u.display_name, u.avatar_url, u.created_at, u.updated_at, u.site_admin, u.passwd IS NOT NULL, u.tags, u.deactivated_at FROM users u "+query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -736,7 +790,7 @@ func (*users) getBySQL(ctx context.Context, query string, args ...interface{}) (
 	for rows.Next() {
 		var u types.User
 		var displayName, avatarURL sql.NullString
-		err := rows.Scan(&u.ID, &u.Username, &displayName, &avatarURL, &u.CreatedAt, &u.UpdatedAt, &u.SiteAdmin, &u.BuiltinAuth, pq.Array(&u.Tags))
+		err := rows.Scan(&u.ID, &u.Username, &displayName, &avatarURL, &u.CreatedAt, &u.UpdatedAt, &u.SiteAdmin, &u.BuiltinAuth, pq.Array(&u.Tags), &u.DeactivatedAt)
 		if err != nil {
 			return nil, err
 		}
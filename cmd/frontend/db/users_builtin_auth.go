@@ -7,6 +7,7 @@ import (
 	"encoding/base64"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/sourcegraph/sourcegraph/internal/db/dbconn"
 	"github.com/sourcegraph/sourcegraph/internal/db/dbtesting"
@@ -15,6 +16,10 @@ import (
 )
 
 func (u *users) IsPassword(ctx context.Context, id int32, password string) (bool, error) {
+	if Mocks.Users.IsPassword != nil {
+		return Mocks.Users.IsPassword(ctx, id, password)
+	}
+
 	var passwd sql.NullString
 	if err := dbconn.Global.QueryRowContext(ctx, "SELECT passwd FROM users WHERE deleted_at IS NULL AND id=$1", id).Scan(&passwd); err != nil {
 		return false, err
@@ -76,7 +81,8 @@ func (u *users) SetPassword(ctx context.Context, id int32, resetCode string, new
 		return false, err
 	}
 	// 🚨 SECURITY: set the new password and clear the reset code and expiry so the same code can't be reused.
-	if _, err := dbconn.Global.ExecContext(ctx, "UPDATE users SET passwd_reset_code=NULL, passwd_reset_time=NULL, passwd=$1 WHERE id=$2", passwd, id); err != nil {
+	// Resetting the password also unlocks the account, because it proves the user controls their email.
+	if _, err := dbconn.Global.ExecContext(ctx, "UPDATE users SET passwd_reset_code=NULL, passwd_reset_time=NULL, passwd=$1, failed_sign_in_attempts=0, locked_until=NULL WHERE id=$2", passwd, id); err != nil {
 		return false, err
 	}
 	return true, nil
@@ -136,6 +142,52 @@ func (u *users) RandomizePasswordAndClearPasswordResetRateLimit(ctx context.Cont
 	return err
 }
 
+// RecordFailedSignIn records a failed attempt to sign in to the user's account (with a wrong
+// password or second authentication factor). After maxAttempts consecutive failed attempts, the
+// account is locked for the given duration and the time until which it's locked is returned.
+// Otherwise nil is returned.
+func (u *users) RecordFailedSignIn(ctx context.Context, id int32, maxAttempts int, lockout time.Duration) (*time.Time, error) {
+	if Mocks.Users.RecordFailedSignIn != nil {
+		return Mocks.Users.RecordFailedSignIn(ctx, id, maxAttempts, lockout)
+	}
+
+	var lockedUntil *time.Time
+	err := dbconn.Global.QueryRowContext(ctx, `
+UPDATE users SET
+	failed_sign_in_attempts=CASE WHEN failed_sign_in_attempts+1>=$2 THEN 0 ELSE failed_sign_in_attempts+1 END,
+	locked_until=CASE WHEN failed_sign_in_attempts+1>=$2 THEN now() + $3 * interval '1 second' ELSE locked_until END
+WHERE id=$1
+RETURNING CASE WHEN locked_until>now() THEN locked_until END
+`, id, maxAttempts, lockout.Seconds()).Scan(&lockedUntil)
+	return lockedUntil, err
+}
+
+// ResetFailedSignIns clears the user's failed sign-in attempts (after a successful sign-in) and
+// unlocks the account.
+func (u *users) ResetFailedSignIns(ctx context.Context, id int32) error {
+	if Mocks.Users.ResetFailedSignIns != nil {
+		return Mocks.Users.ResetFailedSignIns(ctx, id)
+	}
+
+	_, err := dbconn.Global.ExecContext(ctx, "UPDATE users SET failed_sign_in_attempts=0, locked_until=NULL WHERE id=$1", id)
+	return err
+}
+
+// SignInLockedUntil returns the time until which the user's account is locked due to too many failed
+// sign-in attempts, or nil if it isn't locked.
+func (u *users) SignInLockedUntil(ctx context.Context, id int32) (*time.Time, error) {
+	if Mocks.Users.SignInLockedUntil != nil {
+		return Mocks.Users.SignInLockedUntil(ctx, id)
+	}
+
+	var lockedUntil *time.Time
+	err := dbconn.Global.QueryRowContext(ctx, "SELECT locked_until FROM users WHERE id=$1 AND locked_until>now()", id).Scan(&lockedUntil)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return lockedUntil, err
+}
+
 func hashPassword(password string) (sql.NullString, error) {
 	if dbtesting.MockHashPassword != nil {
 		return dbtesting.MockHashPassword(password)
@@ -3,6 +3,7 @@ package db
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
 )
@@ -13,6 +14,7 @@ type MockUsers struct {
 	Delete                       func(ctx context.Context, id int32) error
 	HardDelete                   func(ctx context.Context, id int32) error
 	SetIsSiteAdmin               func(id int32, isSiteAdmin bool) error
+	SetIsDeactivated             func(id int32, isDeactivated bool) error
 	CheckAndDecrementInviteQuota func(ctx context.Context, userID int32) (bool, error)
 	GetByID                      func(ctx context.Context, id int32) (*types.User, error)
 	GetByUsername                func(ctx context.Context, username string) (*types.User, error)
@@ -21,6 +23,10 @@ type MockUsers struct {
 	GetByVerifiedEmail           func(ctx context.Context, email string) (*types.User, error)
 	Count                        func(ctx context.Context, opt *UsersListOptions) (int, error)
 	List                         func(ctx context.Context, opt *UsersListOptions) ([]*types.User, error)
+	IsPassword                   func(ctx context.Context, id int32, password string) (bool, error)
+	RecordFailedSignIn           func(ctx context.Context, id int32, maxAttempts int, lockout time.Duration) (*time.Time, error)
+	ResetFailedSignIns           func(ctx context.Context, id int32) error
+	SignInLockedUntil            func(ctx context.Context, id int32) (*time.Time, error)
 }
 
 func (s *MockUsers) MockGetByID_Return(t *testing.T, returns *types.User, returnsErr error) (called *bool) {
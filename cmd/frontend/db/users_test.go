@@ -436,6 +436,51 @@ func TestUsers_Update(t *testing.T) {
 	}
 }
 
+func TestUsers_SetIsDeactivated(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	dbtesting.SetupGlobalTestDB(t)
+	ctx := context.Background()
+
+	user, err := Users.Create(ctx, NewUser{Username: "u"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := UserSessions.Create(ctx, "k1", &UserSession{UserID: user.ID, ExpiresAt: time.Now().Add(time.Hour)}); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := AccessTokens.Create(ctx, user.ID, []string{"a"}, "n", user.ID, AccessTokenRestrictions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := Users.SetIsDeactivated(ctx, user.ID, true); err != nil {
+		t.Fatal(err)
+	}
+	if u, err := Users.GetByID(ctx, user.ID); err != nil || u.DeactivatedAt == nil {
+		t.Fatalf("got %+v, %v, want deactivated user", u, err)
+	}
+
+	// Reactivating the user doesn't make the sessions and access tokens from before the
+	// deactivation valid again.
+	if err := Users.SetIsDeactivated(ctx, user.ID, false); err != nil {
+		t.Fatal(err)
+	}
+	if u, err := Users.GetByID(ctx, user.ID); err != nil || u.DeactivatedAt != nil {
+		t.Fatalf("got %+v, %v, want reactivated user", u, err)
+	}
+	if _, err := UserSessions.GetByKey(ctx, "k1"); err != ErrUserSessionNotFound {
+		t.Errorf("session: got error %v, want %v", err, ErrUserSessionNotFound)
+	}
+	if tokens, err := AccessTokens.List(ctx, AccessTokensListOptions{SubjectUserID: user.ID}); err != nil || len(tokens) != 0 {
+		t.Errorf("got access tokens %+v, %v, want none", tokens, err)
+	}
+
+	if err := Users.SetIsDeactivated(ctx, user.ID+1, true); !errcode.IsNotFound(err) {
+		t.Errorf("nonexistent user: got error %v, want not found", err)
+	}
+}
+
 func TestUsers_GetByVerifiedEmail(t *testing.T) {
 	if testing.Short() {
 		t.Skip()
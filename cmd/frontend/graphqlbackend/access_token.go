@@ -64,3 +64,11 @@ func (r *accessTokenResolver) CreatedAt() DateTime { return DateTime{Time: r.acc
 func (r *accessTokenResolver) LastUsedAt() *DateTime {
 	return DateTimeOrNil(r.accessToken.LastUsedAt)
 }
+
+func (r *accessTokenResolver) ExpiresAt() *DateTime {
+	return DateTimeOrNil(r.accessToken.ExpiresAt)
+}
+
+func (r *accessTokenResolver) AllowedIPs() []string { return r.accessToken.AllowedIPs }
+
+func (r *accessTokenResolver) RepositoryPatterns() []string { return r.accessToken.RepoPatterns }
@@ -5,9 +5,12 @@ import (
 	"errors"
 	"fmt"
 	"sort"
+	"strconv"
 	"sync"
+	"time"
 
 	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/audit"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/authz"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
@@ -17,9 +20,12 @@ import (
 )
 
 type createAccessTokenInput struct {
-	User   graphql.ID
-	Scopes []string
-	Note   string
+	User               graphql.ID
+	Scopes             []string
+	Note               string
+	ExpiresAt          *DateTime
+	AllowedIPs         *[]string
+	RepositoryPatterns *[]string
 }
 
 func (r *schemaResolver) CreateAccessToken(ctx context.Context, args *createAccessTokenInput) (*createAccessTokenResult, error) {
@@ -46,7 +52,7 @@ func (r *schemaResolver) CreateAccessToken(ctx context.Context, args *createAcce
 	}
 
 	// Validate scopes.
-	var hasUserAllScope bool
+	var hasUserAllScope, hasSudoScope, hasFineGrainedScope bool
 	seenScope := map[string]struct{}{}
 	sort.Strings(args.Scopes)
 	for _, scope := range args.Scopes {
@@ -58,6 +64,9 @@ func (r *schemaResolver) CreateAccessToken(ctx context.Context, args *createAcce
 			if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
 				return nil, err
 			}
+			hasSudoScope = true
+		case authz.ScopeSearchRead, authz.ScopeRepoRead, authz.ScopeCampaignsWrite, authz.ScopeCodeIntelUpload:
+			hasFineGrainedScope = true
 		default:
 			return nil, fmt.Errorf("unknown access token scope %q (valid scopes: %q)", scope, authz.AllScopes)
 		}
@@ -67,12 +76,50 @@ func (r *schemaResolver) CreateAccessToken(ctx context.Context, args *createAcce
 		}
 		seenScope[scope] = struct{}{}
 	}
-	if !hasUserAllScope {
-		return nil, fmt.Errorf("all access tokens must have scope %q", authz.ScopeUserAll)
+	if !hasUserAllScope && !hasFineGrainedScope {
+		return nil, fmt.Errorf("access tokens must have scope %q or at least one of the scopes %q", authz.ScopeUserAll, authz.FineGrainedScopes)
+	}
+	if hasSudoScope && !hasUserAllScope {
+		return nil, fmt.Errorf("access tokens with scope %q must also have scope %q", authz.ScopeSiteAdminSudo, authz.ScopeUserAll)
+	}
+
+	// Validate restrictions.
+	var restrictions db.AccessTokenRestrictions
+	if args.ExpiresAt != nil {
+		if !args.ExpiresAt.Time.After(time.Now()) {
+			return nil, errors.New("access token expiry date must be in the future")
+		}
+		restrictions.ExpiresAt = &args.ExpiresAt.Time
+	}
+	if args.AllowedIPs != nil {
+		restrictions.AllowedIPs = *args.AllowedIPs
+	}
+	if args.RepositoryPatterns != nil {
+		restrictions.RepoPatterns = *args.RepositoryPatterns
+	}
+	if err := restrictions.Validate(); err != nil {
+		return nil, err
 	}
 
-	id, token, err := db.AccessTokens.Create(ctx, userID, args.Scopes, args.Note, actor.FromContext(ctx).UID)
-	return &createAccessTokenResult{id: marshalAccessTokenID(id), token: token}, err
+	id, token, err := db.AccessTokens.Create(ctx, userID, args.Scopes, args.Note, actor.FromContext(ctx).UID, restrictions)
+	if err != nil {
+		return nil, err
+	}
+	audit.Log(ctx, audit.ActionAccessTokenCreate, audit.TargetAccessToken, strconv.FormatInt(id, 10), nil, accessTokenAuditState(userID, args.Scopes, args.Note, restrictions))
+	return &createAccessTokenResult{id: marshalAccessTokenID(id), token: token}, nil
+}
+
+// accessTokenAuditState returns the state of an access token recorded in the audit log. It never
+// includes the token's secret value.
+func accessTokenAuditState(subjectUserID int32, scopes []string, note string, restrictions db.AccessTokenRestrictions) interface{} {
+	return struct {
+		SubjectUserID int32      `json:"subjectUserID"`
+		Scopes        []string   `json:"scopes"`
+		Note          string     `json:"note"`
+		ExpiresAt     *time.Time `json:"expiresAt,omitempty"`
+		AllowedIPs    []string   `json:"allowedIPs,omitempty"`
+		RepoPatterns  []string   `json:"repositoryPatterns,omitempty"`
+	}{subjectUserID, scopes, note, restrictions.ExpiresAt, restrictions.AllowedIPs, restrictions.RepoPatterns}
 }
 
 type createAccessTokenResult struct {
@@ -115,13 +162,16 @@ func (r *schemaResolver) DeleteAccessToken(ctx context.Context, args *deleteAcce
 		if err := db.AccessTokens.DeleteByID(ctx, token.ID, token.SubjectUserID); err != nil {
 			return nil, err
 		}
+		audit.Log(ctx, audit.ActionAccessTokenDelete, audit.TargetAccessToken, strconv.FormatInt(token.ID, 10), accessTokenAuditState(token.SubjectUserID, token.Scopes, token.Note, token.AccessTokenRestrictions), nil)
 
 	case args.ByToken != nil:
 		// 🚨 SECURITY: This is easier than the ByID case because anyone holding the access token's
 		// secret value is assumed to be allowed to delete it.
-		if err := db.AccessTokens.DeleteByToken(ctx, *args.ByToken); err != nil {
+		token, err := db.AccessTokens.DeleteByToken(ctx, *args.ByToken)
+		if err != nil {
 			return nil, err
 		}
+		audit.Log(ctx, audit.ActionAccessTokenDelete, audit.TargetAccessToken, strconv.FormatInt(token.ID, 10), accessTokenAuditState(token.SubjectUserID, token.Scopes, token.Note, token.AccessTokenRestrictions), nil)
 	}
 
 	return &EmptyResponse{}, nil
@@ -3,10 +3,13 @@ package graphqlbackend
 import (
 	"context"
 	"reflect"
+	"strings"
 	"testing"
+	"time"
 
 	graphql "github.com/graph-gophers/graphql-go"
 	"github.com/graph-gophers/graphql-go/gqltesting"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/audit"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/authz"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
@@ -17,7 +20,7 @@ import (
 // 🚨 SECURITY: This tests that users can't create tokens for users they aren't allowed to do so for.
 func TestMutation_CreateAccessToken(t *testing.T) {
 	mockAccessTokensCreate := func(t *testing.T, wantCreatorUserID int32, wantScopes []string) {
-		db.Mocks.AccessTokens.Create = func(subjectUserID int32, scopes []string, note string, creatorUserID int32) (int64, string, error) {
+		db.Mocks.AccessTokens.Create = func(subjectUserID int32, scopes []string, note string, creatorUserID int32, restrictions db.AccessTokenRestrictions) (int64, string, error) {
 			if want := int32(1); subjectUserID != want {
 				t.Errorf("got %v, want %v", subjectUserID, want)
 			}
@@ -32,6 +35,15 @@ func TestMutation_CreateAccessToken(t *testing.T) {
 			}
 			return 1, "t", nil
 		}
+		db.Mocks.AuditLogs.Create = func(e *db.AuditLogEntry) error {
+			if e.Action != audit.ActionAccessTokenCreate || e.TargetID != "1" {
+				t.Errorf("unexpected audit log entry %+v", e)
+			}
+			if strings.Contains(string(e.After), `"t"`) {
+				t.Errorf("audit log entry contains the access token: %s", e.After)
+			}
+			return nil
+		}
 	}
 
 	const uid1GQLID = "VXNlcjox"
@@ -97,6 +109,104 @@ func TestMutation_CreateAccessToken(t *testing.T) {
 		}
 	})
 
+	t.Run("authenticated as user, using fine-grained scopes and restrictions", func(t *testing.T) {
+		resetMocks()
+		var gotRestrictions db.AccessTokenRestrictions
+		db.Mocks.AccessTokens.Create = func(subjectUserID int32, scopes []string, note string, creatorUserID int32, restrictions db.AccessTokenRestrictions) (int64, string, error) {
+			if want := []string{authz.ScopeRepoRead, authz.ScopeSearchRead}; !reflect.DeepEqual(scopes, want) {
+				t.Errorf("got %q, want %q", scopes, want)
+			}
+			gotRestrictions = restrictions
+			return 1, "t", nil
+		}
+		var gotAuditLogEntry *db.AuditLogEntry
+		db.Mocks.AuditLogs.Create = func(e *db.AuditLogEntry) error {
+			gotAuditLogEntry = e
+			return nil
+		}
+
+		gqltesting.RunTests(t, []*gqltesting.Test{
+			{
+				Context: actor.WithActor(context.Background(), &actor.Actor{UID: 1}),
+				Schema:  mustParseGraphQLSchema(t),
+				Query: `
+				mutation {
+					createAccessToken(user: "` + uid1GQLID + `", scopes: ["search:read", "repo:read"], note: "n", expiresAt: "2100-01-01T00:00:00Z", allowedIPs: ["10.0.0.0/8"], repositoryPatterns: ["^github\\.com/foo/"]) {
+						id
+						token
+					}
+				}
+			`,
+				ExpectedResult: `
+				{
+					"createAccessToken": {
+						"id": "QWNjZXNzVG9rZW46MQ==",
+						"token": "t"
+					}
+				}
+			`,
+			},
+		})
+
+		if gotRestrictions.ExpiresAt == nil || gotRestrictions.ExpiresAt.Year() != 2100 {
+			t.Errorf("got expiry %v, want 2100-01-01", gotRestrictions.ExpiresAt)
+		}
+		if want := []string{"10.0.0.0/8"}; !reflect.DeepEqual(gotRestrictions.AllowedIPs, want) {
+			t.Errorf("got allowed IPs %q, want %q", gotRestrictions.AllowedIPs, want)
+		}
+		if want := []string{`^github\.com/foo/`}; !reflect.DeepEqual(gotRestrictions.RepoPatterns, want) {
+			t.Errorf("got repository patterns %q, want %q", gotRestrictions.RepoPatterns, want)
+		}
+		if gotAuditLogEntry == nil {
+			t.Fatal("access token creation was not recorded in the audit log")
+		}
+		if want := `"allowedIPs":["10.0.0.0/8"]`; !strings.Contains(string(gotAuditLogEntry.After), want) {
+			t.Errorf("got audit log state %s, want it to contain %s", gotAuditLogEntry.After, want)
+		}
+	})
+
+	t.Run("authenticated as user, using invalid restrictions", func(t *testing.T) {
+		past := DateTime{Time: time.Now().Add(-time.Hour)}
+		for name, args := range map[string]*createAccessTokenInput{
+			"expired":         {ExpiresAt: &past},
+			"invalid IP":      {AllowedIPs: &[]string{"10.0.0.0/33"}},
+			"invalid pattern": {RepositoryPatterns: &[]string{"("}},
+		} {
+			t.Run(name, func(t *testing.T) {
+				resetMocks()
+				args.User = uid1GQLID
+				args.Scopes = []string{authz.ScopeSearchRead}
+				args.Note = "n"
+
+				ctx := actor.WithActor(context.Background(), &actor.Actor{UID: 1})
+				result, err := (&schemaResolver{}).CreateAccessToken(ctx, args)
+				if err == nil {
+					t.Error("err == nil")
+				}
+				if result != nil {
+					t.Errorf("got result %v, want nil", result)
+				}
+			})
+		}
+	})
+
+	t.Run("authenticated with restricted access token", func(t *testing.T) {
+		resetMocks()
+
+		ctx := actor.WithActor(context.Background(), &actor.Actor{UID: 1, Scopes: []string{authz.ScopeSearchRead}})
+		result, err := (&schemaResolver{}).CreateAccessToken(ctx, &createAccessTokenInput{
+			User:   uid1GQLID,
+			Scopes: []string{authz.ScopeUserAll},
+			Note:   "n",
+		})
+		if !backend.IsAccessTokenScopeError(err) {
+			t.Errorf("got err %v, want access token scope error", err)
+		}
+		if result != nil {
+			t.Errorf("got result %v, want nil", result)
+		}
+	})
+
 	t.Run("authenticated as site admin, using site-admin-only scopes", func(t *testing.T) {
 		resetMocks()
 		mockAccessTokensCreate(t, 1, []string{authz.ScopeSiteAdminSudo, authz.ScopeUserAll})
@@ -129,6 +239,27 @@ func TestMutation_CreateAccessToken(t *testing.T) {
 		})
 	})
 
+	t.Run("authenticated as site admin, using site-admin-only scopes without user:all", func(t *testing.T) {
+		resetMocks()
+		db.Mocks.Users.GetByCurrentAuthUser = func(ctx context.Context) (*types.User, error) {
+			return &types.User{ID: 1, SiteAdmin: true}, nil
+		}
+		defer func() { db.Mocks.Users.GetByCurrentAuthUser = nil }()
+
+		ctx := actor.WithActor(context.Background(), &actor.Actor{UID: 1})
+		result, err := (&schemaResolver{}).CreateAccessToken(ctx, &createAccessTokenInput{
+			User:   uid1GQLID,
+			Scopes: []string{authz.ScopeSearchRead, authz.ScopeSiteAdminSudo},
+			Note:   "n",
+		})
+		if err == nil {
+			t.Error("err == nil")
+		}
+		if result != nil {
+			t.Errorf("got result %v, want nil", result)
+		}
+	})
+
 	t.Run("authenticated as different user who is a site-admin", func(t *testing.T) {
 		resetMocks()
 		const differentSiteAdminUID = 234
@@ -220,6 +351,12 @@ func TestMutation_DeleteAccessToken(t *testing.T) {
 			}
 			return &db.AccessToken{ID: 1, SubjectUserID: 2}, nil
 		}
+		db.Mocks.AuditLogs.Create = func(e *db.AuditLogEntry) error {
+			if e.Action != audit.ActionAccessTokenDelete || e.TargetID != "1" || e.Before == nil {
+				t.Errorf("unexpected audit log entry %+v", e)
+			}
+			return nil
+		}
 	}
 
 	token1GQLID := graphql.ID("QWNjZXNzVG9rZW46MQ==")
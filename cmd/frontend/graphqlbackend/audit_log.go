@@ -0,0 +1,176 @@
+package graphqlbackend
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+
+	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/graph-gophers/graphql-go/relay"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend/graphqlutil"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+)
+
+func (r *siteResolver) AuditLog(ctx context.Context, args *struct {
+	graphqlutil.ConnectionArgs
+	Actor      *graphql.ID
+	Action     *string
+	TargetType *string
+	TargetID   *string
+	Since      *DateTime
+	Until      *DateTime
+}) (*auditLogEntryConnectionResolver, error) {
+	// 🚨 SECURITY: Only site admins can view the audit log.
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	var opt db.AuditLogsListOptions
+	if args.Actor != nil {
+		userID, err := UnmarshalUserID(*args.Actor)
+		if err != nil {
+			return nil, err
+		}
+		opt.ActorUserID = userID
+	}
+	if args.Action != nil {
+		opt.Action = *args.Action
+	}
+	if args.TargetType != nil {
+		opt.TargetType = *args.TargetType
+	}
+	if args.TargetID != nil {
+		if opt.TargetType == "" {
+			return nil, errors.New("targetID requires targetType")
+		}
+		opt.TargetID = *args.TargetID
+	}
+	if args.Since != nil {
+		opt.After = &args.Since.Time
+	}
+	if args.Until != nil {
+		opt.Before = &args.Until.Time
+	}
+	args.ConnectionArgs.Set(&opt.LimitOffset)
+	return &auditLogEntryConnectionResolver{opt: opt}, nil
+}
+
+// auditLogEntryConnectionResolver resolves a list of audit log entries.
+//
+// 🚨 SECURITY: When instantiating an auditLogEntryConnectionResolver value, the caller MUST check
+// that the actor is a site admin.
+type auditLogEntryConnectionResolver struct {
+	opt db.AuditLogsListOptions
+
+	// cache results because they are used by multiple fields
+	once    sync.Once
+	entries []*db.AuditLogEntry
+	err     error
+}
+
+func (r *auditLogEntryConnectionResolver) compute(ctx context.Context) ([]*db.AuditLogEntry, error) {
+	r.once.Do(func() {
+		opt2 := r.opt
+		if opt2.LimitOffset != nil {
+			tmp := *opt2.LimitOffset
+			opt2.LimitOffset = &tmp
+			opt2.Limit++ // so we can detect if there is a next page
+		}
+
+		r.entries, r.err = db.AuditLogs.List(ctx, opt2)
+	})
+	return r.entries, r.err
+}
+
+func (r *auditLogEntryConnectionResolver) Nodes(ctx context.Context) ([]*auditLogEntryResolver, error) {
+	entries, err := r.compute(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if r.opt.LimitOffset != nil && len(entries) > r.opt.LimitOffset.Limit {
+		entries = entries[:r.opt.LimitOffset.Limit]
+	}
+
+	l := make([]*auditLogEntryResolver, 0, len(entries))
+	for _, entry := range entries {
+		l = append(l, &auditLogEntryResolver{entry: entry})
+	}
+	return l, nil
+}
+
+func (r *auditLogEntryConnectionResolver) TotalCount(ctx context.Context) (int32, error) {
+	count, err := db.AuditLogs.Count(ctx, r.opt)
+	return int32(count), err
+}
+
+func (r *auditLogEntryConnectionResolver) PageInfo(ctx context.Context) (*graphqlutil.PageInfo, error) {
+	entries, err := r.compute(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return graphqlutil.HasNextPage(r.opt.LimitOffset != nil && len(entries) > r.opt.Limit), nil
+}
+
+type auditLogEntryResolver struct {
+	entry *db.AuditLogEntry
+}
+
+func (r *auditLogEntryResolver) ID() graphql.ID {
+	return relay.MarshalID("AuditLogEntry", r.entry.ID)
+}
+
+func (r *auditLogEntryResolver) Action() string { return r.entry.Action }
+
+func (r *auditLogEntryResolver) Actor(ctx context.Context) (*UserResolver, error) {
+	if r.entry.ActorUserID == 0 {
+		return nil, nil
+	}
+	user, err := UserByIDInt32(ctx, r.entry.ActorUserID)
+	if errcode.IsNotFound(err) {
+		// The actor has been deleted since the action was performed.
+		return nil, nil
+	}
+	return user, err
+}
+
+func (r *auditLogEntryResolver) ActorDatabaseID() *int32 {
+	if r.entry.ActorUserID == 0 {
+		return nil
+	}
+	return &r.entry.ActorUserID
+}
+
+func (r *auditLogEntryResolver) ActorIP() *string { return nullString(r.entry.ActorIP) }
+
+func (r *auditLogEntryResolver) ActorUserAgent() *string { return nullString(r.entry.ActorUserAgent) }
+
+func (r *auditLogEntryResolver) TargetType() string { return r.entry.TargetType }
+
+func (r *auditLogEntryResolver) TargetID() string { return r.entry.TargetID }
+
+func (r *auditLogEntryResolver) Before() (*JSONValue, error) { return jsonValue(r.entry.Before) }
+
+func (r *auditLogEntryResolver) After() (*JSONValue, error) { return jsonValue(r.entry.After) }
+
+func (r *auditLogEntryResolver) CreatedAt() DateTime { return DateTime{Time: r.entry.CreatedAt} }
+
+func nullString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+func jsonValue(raw json.RawMessage) (*JSONValue, error) {
+	if raw == nil {
+		return nil, nil
+	}
+	var v JSONValue
+	if err := json.Unmarshal(raw, &v.Value); err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
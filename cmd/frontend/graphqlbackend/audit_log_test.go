@@ -0,0 +1,133 @@
+package graphqlbackend
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/graph-gophers/graphql-go/gqltesting"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend/graphqlutil"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+)
+
+func TestSite_AuditLog(t *testing.T) {
+	t.Run("non-admin", func(t *testing.T) {
+		resetMocks()
+		db.Mocks.Users.GetByCurrentAuthUser = func(ctx context.Context) (*types.User, error) {
+			return &types.User{ID: 2}, nil
+		}
+
+		ctx := actor.WithActor(context.Background(), &actor.Actor{UID: 2})
+		_, err := (&siteResolver{}).AuditLog(ctx, &struct {
+			graphqlutil.ConnectionArgs
+			Actor      *graphql.ID
+			Action     *string
+			TargetType *string
+			TargetID   *string
+			Since      *DateTime
+			Until      *DateTime
+		}{})
+		if err != backend.ErrMustBeSiteAdmin {
+			t.Errorf("got error %v, want %v", err, backend.ErrMustBeSiteAdmin)
+		}
+	})
+
+	t.Run("site admin", func(t *testing.T) {
+		resetMocks()
+		db.Mocks.Users.GetByCurrentAuthUser = func(ctx context.Context) (*types.User, error) {
+			return &types.User{ID: 1, SiteAdmin: true}, nil
+		}
+		db.Mocks.Users.GetByID = func(ctx context.Context, id int32) (*types.User, error) {
+			return &types.User{ID: id, Username: "alice"}, nil
+		}
+		var gotOpt db.AuditLogsListOptions
+		db.Mocks.AuditLogs.List = func(opt db.AuditLogsListOptions) ([]*db.AuditLogEntry, error) {
+			gotOpt = opt
+			return []*db.AuditLogEntry{
+				{
+					ID:          2,
+					Action:      "user.site_admin.update",
+					ActorUserID: 1,
+					ActorIP:     "127.0.0.1",
+					TargetType:  "user",
+					TargetID:    "3",
+					Before:      json.RawMessage(`{"siteAdmin":false}`),
+					After:       json.RawMessage(`{"siteAdmin":true}`),
+					CreatedAt:   time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+				},
+				{ID: 1, Action: "user.site_admin.update", TargetType: "user", TargetID: "4"},
+			}, nil
+		}
+		db.Mocks.AuditLogs.Count = func(opt db.AuditLogsListOptions) (int, error) {
+			return 2, nil
+		}
+
+		gqltesting.RunTests(t, []*gqltesting.Test{
+			{
+				Context: actor.WithActor(context.Background(), &actor.Actor{UID: 1}),
+				Schema:  mustParseGraphQLSchema(t),
+				Query: `
+				{
+					site {
+						auditLog(first: 1, actor: "VXNlcjox", targetType: "user", targetID: "3") {
+							nodes {
+								action
+								actor {
+									username
+								}
+								actorIP
+								actorUserAgent
+								targetType
+								targetID
+								before
+								after
+								createdAt
+							}
+							totalCount
+							pageInfo {
+								hasNextPage
+							}
+						}
+					}
+				}
+			`,
+				ExpectedResult: `
+				{
+					"site": {
+						"auditLog": {
+							"nodes": [
+								{
+									"action": "user.site_admin.update",
+									"actor": {
+										"username": "alice"
+									},
+									"actorIP": "127.0.0.1",
+									"actorUserAgent": null,
+									"targetType": "user",
+									"targetID": "3",
+									"before": {"siteAdmin": false},
+									"after": {"siteAdmin": true},
+									"createdAt": "2020-01-01T00:00:00Z"
+								}
+							],
+							"totalCount": 2,
+							"pageInfo": {
+								"hasNextPage": true
+							}
+						}
+					}
+				}
+			`,
+			},
+		})
+
+		if gotOpt.ActorUserID != 1 || gotOpt.TargetType != "user" || gotOpt.TargetID != "3" {
+			t.Errorf("unexpected list options %+v", gotOpt)
+		}
+	})
+}
@@ -12,9 +12,15 @@ var NewAuthzResolver func() AuthzResolver
 
 type AuthzResolver interface {
 	SetRepositoryPermissionsForUsers(ctx context.Context, args *RepoPermsArgs) (*EmptyResponse, error)
+	SetRepositoryPermissionsForBulkOperation(ctx context.Context, args *RepoPermsBulkArgs) (*EmptyResponse, error)
 	AuthorizedUserRepositories(ctx context.Context, args *AuthorizedRepoArgs) (RepositoryConnectionResolver, error)
 	UsersWithPendingPermissions(ctx context.Context) ([]string, error)
 	AuthorizedUsers(ctx context.Context, args *RepoAuthorizedUserArgs) (UserConnectionResolver, error)
+	ScheduleUserPermissionsSync(ctx context.Context, args *UserPermissionsSyncArgs) (*EmptyResponse, error)
+	ScheduleRepositoryPermissionsSync(ctx context.Context, args *RepositoryPermissionsSyncArgs) (*EmptyResponse, error)
+	ExplainRepositoryAccess(ctx context.Context, args *ExplainRepositoryAccessArgs) (RepositoryAccessExplanationResolver, error)
+	UserPermissionsSyncHistory(ctx context.Context, args *UserPermissionsSyncHistoryArgs) ([]PermissionsSyncHistoryEntryResolver, error)
+	RepositoryPermissionsSyncHistory(ctx context.Context, args *RepoPermissionsSyncHistoryArgs) ([]PermissionsSyncHistoryEntryResolver, error)
 }
 
 var authzInEnterprise = errors.New("authorization mutations and queries are only available in enterprise")
@@ -25,6 +31,10 @@ func (defaultAuthzResolver) SetRepositoryPermissionsForUsers(ctx context.Context
 	return nil, authzInEnterprise
 }
 
+func (defaultAuthzResolver) SetRepositoryPermissionsForBulkOperation(ctx context.Context, args *RepoPermsBulkArgs) (*EmptyResponse, error) {
+	return nil, authzInEnterprise
+}
+
 func (defaultAuthzResolver) AuthorizedUserRepositories(ctx context.Context, args *AuthorizedRepoArgs) (RepositoryConnectionResolver, error) {
 	return nil, authzInEnterprise
 }
@@ -37,12 +47,42 @@ func (defaultAuthzResolver) AuthorizedUsers(ctx context.Context, args *RepoAutho
 	return nil, authzInEnterprise
 }
 
+func (defaultAuthzResolver) ScheduleUserPermissionsSync(ctx context.Context, args *UserPermissionsSyncArgs) (*EmptyResponse, error) {
+	return nil, authzInEnterprise
+}
+
+func (defaultAuthzResolver) ScheduleRepositoryPermissionsSync(ctx context.Context, args *RepositoryPermissionsSyncArgs) (*EmptyResponse, error) {
+	return nil, authzInEnterprise
+}
+
+func (defaultAuthzResolver) ExplainRepositoryAccess(ctx context.Context, args *ExplainRepositoryAccessArgs) (RepositoryAccessExplanationResolver, error) {
+	return nil, authzInEnterprise
+}
+
+func (defaultAuthzResolver) UserPermissionsSyncHistory(ctx context.Context, args *UserPermissionsSyncHistoryArgs) ([]PermissionsSyncHistoryEntryResolver, error) {
+	return nil, authzInEnterprise
+}
+
+func (defaultAuthzResolver) RepositoryPermissionsSyncHistory(ctx context.Context, args *RepoPermissionsSyncHistoryArgs) ([]PermissionsSyncHistoryEntryResolver, error) {
+	return nil, authzInEnterprise
+}
+
 type RepoPermsArgs struct {
 	Repository graphql.ID
 	BindIDs    []string
 	Perm       string
 }
 
+type RepoPermsBulkArgs struct {
+	Permissions []*RepositoryPermissionsInput
+	Perm        string
+}
+
+type RepositoryPermissionsInput struct {
+	Repository graphql.ID
+	BindIDs    []string
+}
+
 type AuthorizedRepoArgs struct {
 	Username *string
 	Email    *string
@@ -50,3 +90,54 @@ type AuthorizedRepoArgs struct {
 	First    int32
 	After    *string
 }
+
+type UserPermissionsSyncArgs struct {
+	User graphql.ID
+}
+
+type RepositoryPermissionsSyncArgs struct {
+	Repository graphql.ID
+}
+
+type ExplainRepositoryAccessArgs struct {
+	User       graphql.ID
+	Repository graphql.ID
+}
+
+type PermissionsSyncHistoryArgs struct {
+	First int32
+}
+
+type UserPermissionsSyncHistoryArgs struct {
+	UserID graphql.ID
+	*PermissionsSyncHistoryArgs
+}
+
+type RepoPermissionsSyncHistoryArgs struct {
+	RepositoryID graphql.ID
+	*PermissionsSyncHistoryArgs
+}
+
+type RepositoryAccessExplanationResolver interface {
+	HasAccess() bool
+	Reasons() []RepositoryAccessReasonResolver
+}
+
+type RepositoryAccessReasonResolver interface {
+	Kind() string
+	GrantsAccess() bool
+	Provider() *string
+	UpdatedAt() *DateTime
+	Description() string
+}
+
+type PermissionsSyncHistoryEntryResolver interface {
+	StartedAt() DateTime
+	FinishedAt() DateTime
+	Providers() []string
+	RepositoriesCount() int32
+	UsersCount() int32
+	PendingAccountsCount() int32
+	PartialResults() bool
+	Error() *string
+}
@@ -20,31 +20,106 @@ type AddChangesetsToCampaignArgs struct {
 	Changesets []graphql.ID
 }
 
+type ImportChangesetsArgs struct {
+	Campaign        graphql.ID
+	ExternalService graphql.ID
+	Query           string
+}
+
+// ExternalServiceID returns the ID of the ExternalService to import from.
+func (a *ImportChangesetsArgs) ExternalServiceID() (int64, error) {
+	return unmarshalExternalServiceID(a.ExternalService)
+}
+
+type DeleteChangesetImportQueryArgs struct {
+	ChangesetImportQuery graphql.ID
+}
+
+type PerformChangesetActionArgs struct {
+	Campaign graphql.ID
+	Input    struct {
+		Type      string
+		Body      *string
+		Labels    *[]string
+		Reviewers *[]string
+	}
+}
+
+type CreateCampaignNotificationSubscriptionArgs struct {
+	Campaign graphql.ID
+	Input    struct {
+		Channel     string
+		URL         *string
+		Secret      *string
+		Transitions *[]string
+	}
+}
+
+type DeleteCampaignNotificationSubscriptionArgs struct {
+	Subscription graphql.ID
+}
+
 type CreateCampaignArgs struct {
 	Input struct {
-		Namespace   graphql.ID
-		Name        string
-		Description *string
-		Branch      *string
-		PatchSet    *graphql.ID
-		Draft       *bool
+		Namespace     graphql.ID
+		Name          string
+		Description   *string
+		Branch        *string
+		PatchSet      *graphql.ID
+		Draft         *bool
+		AutoMerge     *bool
+		MergeMethod   *string
+		MergesPerHour *int32
+
+		ChangesetOptions *ChangesetOptionsInput
 	}
 }
 
 type UpdateCampaignArgs struct {
 	Input struct {
-		ID          graphql.ID
-		Name        *string
-		Description *string
-		Branch      *string
-		PatchSet    *graphql.ID
+		ID            graphql.ID
+		Name          *string
+		Description   *string
+		Branch        *string
+		PatchSet      *graphql.ID
+		AutoMerge     *bool
+		MergeMethod   *string
+		MergesPerHour *int32
+
+		ChangesetOptions *ChangesetOptionsInput
 	}
 }
 
+type ChangesetOptionsInput struct {
+	TitleTemplate *string
+	BodyTemplate  *string
+	Labels        *[]string
+	Reviewers     *ChangesetUsersRuleInput
+	Assignees     *ChangesetUsersRuleInput
+	Draft         *bool
+}
+
+type ChangesetUsersRuleInput struct {
+	Users          *[]string
+	FromCodeOwners *bool
+}
+
 type CreatePatchSetFromPatchesArgs struct {
 	Patches []PatchInput
 }
 
+type CreatePatchSetFromReplacementArgs struct {
+	Replacement ReplacementInput
+}
+
+type ReplacementInput struct {
+	Query         string
+	Kind          campaigns.ReplacementKind
+	Match         string
+	Rewrite       string
+	FileExtension *string
+}
+
 type PatchInput struct {
 	Repository   graphql.ID
 	BaseRevision api.CommitID
@@ -91,11 +166,79 @@ type SyncChangesetArgs struct {
 	Changeset graphql.ID
 }
 
+type CampaignsReportArgs struct {
+	Campaigns      *[]graphql.ID
+	StaleAfterDays int32
+}
+
+type CampaignTemplateSpecInput struct {
+	Action        string
+	ScopeQuery    string
+	BranchPattern string
+	TitleTemplate string
+	BodyTemplate  *string
+	Parameters    *[]CampaignTemplateParameterInput
+}
+
+type CampaignTemplateParameterInput struct {
+	Name        string
+	Description *string
+	Default     *string
+}
+
+type CreateCampaignTemplateArgs struct {
+	Input struct {
+		Namespace   graphql.ID
+		Name        string
+		Description *string
+		Spec        CampaignTemplateSpecInput
+	}
+}
+
+type UpdateCampaignTemplateArgs struct {
+	Input struct {
+		ID          graphql.ID
+		Name        *string
+		Description *string
+		Spec        *CampaignTemplateSpecInput
+	}
+}
+
+type DeleteCampaignTemplateArgs struct {
+	CampaignTemplate graphql.ID
+}
+
+type InstantiateCampaignTemplateArgs struct {
+	Input struct {
+		Template    graphql.ID
+		Version     *int32
+		Namespace   graphql.ID
+		Name        *string
+		Description *string
+		Parameters  *[]struct {
+			Name  string
+			Value string
+		}
+		PatchSet *graphql.ID
+		Draft    *bool
+	}
+}
+
+type ListCampaignTemplatesArgs struct {
+	First     *int32
+	Namespace *graphql.ID
+}
+
+type EncryptCampaignsSigningKeyArgs struct {
+	Key string
+}
+
 type CampaignsResolver interface {
 	CreateCampaign(ctx context.Context, args *CreateCampaignArgs) (CampaignResolver, error)
 	UpdateCampaign(ctx context.Context, args *UpdateCampaignArgs) (CampaignResolver, error)
 	CampaignByID(ctx context.Context, id graphql.ID) (CampaignResolver, error)
 	Campaigns(ctx context.Context, args *ListCampaignArgs) (CampaignsConnectionResolver, error)
+	CampaignsReport(ctx context.Context, args *CampaignsReportArgs) (CampaignsReportResolver, error)
 	DeleteCampaign(ctx context.Context, args *DeleteCampaignArgs) (*EmptyResponse, error)
 	RetryCampaign(ctx context.Context, args *RetryCampaignArgs) (CampaignResolver, error)
 	CloseCampaign(ctx context.Context, args *CloseCampaignArgs) (CampaignResolver, error)
@@ -108,11 +251,26 @@ type CampaignsResolver interface {
 	Changesets(ctx context.Context, args *ListChangesetsArgs) (ExternalChangesetsConnectionResolver, error)
 
 	AddChangesetsToCampaign(ctx context.Context, args *AddChangesetsToCampaignArgs) (CampaignResolver, error)
+	ImportChangesets(ctx context.Context, args *ImportChangesetsArgs) (ChangesetImportQueryResolver, error)
+	DeleteChangesetImportQuery(ctx context.Context, args *DeleteChangesetImportQueryArgs) (*EmptyResponse, error)
+	PerformChangesetAction(ctx context.Context, args *PerformChangesetActionArgs) (ChangesetActionResolver, error)
+	CreateCampaignNotificationSubscription(ctx context.Context, args *CreateCampaignNotificationSubscriptionArgs) (CampaignNotificationSubscriptionResolver, error)
+	DeleteCampaignNotificationSubscription(ctx context.Context, args *DeleteCampaignNotificationSubscriptionArgs) (*EmptyResponse, error)
 
 	CreatePatchSetFromPatches(ctx context.Context, args CreatePatchSetFromPatchesArgs) (PatchSetResolver, error)
+	CreatePatchSetFromReplacement(ctx context.Context, args *CreatePatchSetFromReplacementArgs) (PatchSetResolver, error)
 	PatchSetByID(ctx context.Context, id graphql.ID) (PatchSetResolver, error)
 
 	PatchByID(ctx context.Context, id graphql.ID) (PatchResolver, error)
+
+	CreateCampaignTemplate(ctx context.Context, args *CreateCampaignTemplateArgs) (CampaignTemplateResolver, error)
+	UpdateCampaignTemplate(ctx context.Context, args *UpdateCampaignTemplateArgs) (CampaignTemplateResolver, error)
+	DeleteCampaignTemplate(ctx context.Context, args *DeleteCampaignTemplateArgs) (*EmptyResponse, error)
+	InstantiateCampaignTemplate(ctx context.Context, args *InstantiateCampaignTemplateArgs) (CampaignResolver, error)
+	CampaignTemplateByID(ctx context.Context, id graphql.ID) (CampaignTemplateResolver, error)
+	CampaignTemplates(ctx context.Context, args *ListCampaignTemplatesArgs) (CampaignTemplatesConnectionResolver, error)
+
+	EncryptCampaignsSigningKey(ctx context.Context, args *EncryptCampaignsSigningKeyArgs) (string, error)
 }
 
 var campaignsOnlyInEnterprise = errors.New("campaigns and changesets are only available in enterprise")
@@ -135,6 +293,10 @@ func (defaultCampaignsResolver) Campaigns(ctx context.Context, args *ListCampaig
 	return nil, campaignsOnlyInEnterprise
 }
 
+func (defaultCampaignsResolver) CampaignsReport(ctx context.Context, args *CampaignsReportArgs) (CampaignsReportResolver, error) {
+	return nil, campaignsOnlyInEnterprise
+}
+
 func (defaultCampaignsResolver) DeleteCampaign(ctx context.Context, args *DeleteCampaignArgs) (*EmptyResponse, error) {
 	return nil, campaignsOnlyInEnterprise
 }
@@ -175,10 +337,34 @@ func (defaultCampaignsResolver) AddChangesetsToCampaign(ctx context.Context, arg
 	return nil, campaignsOnlyInEnterprise
 }
 
+func (defaultCampaignsResolver) ImportChangesets(ctx context.Context, args *ImportChangesetsArgs) (ChangesetImportQueryResolver, error) {
+	return nil, campaignsOnlyInEnterprise
+}
+
+func (defaultCampaignsResolver) DeleteChangesetImportQuery(ctx context.Context, args *DeleteChangesetImportQueryArgs) (*EmptyResponse, error) {
+	return nil, campaignsOnlyInEnterprise
+}
+
+func (defaultCampaignsResolver) PerformChangesetAction(ctx context.Context, args *PerformChangesetActionArgs) (ChangesetActionResolver, error) {
+	return nil, campaignsOnlyInEnterprise
+}
+
+func (defaultCampaignsResolver) CreateCampaignNotificationSubscription(ctx context.Context, args *CreateCampaignNotificationSubscriptionArgs) (CampaignNotificationSubscriptionResolver, error) {
+	return nil, campaignsOnlyInEnterprise
+}
+
+func (defaultCampaignsResolver) DeleteCampaignNotificationSubscription(ctx context.Context, args *DeleteCampaignNotificationSubscriptionArgs) (*EmptyResponse, error) {
+	return nil, campaignsOnlyInEnterprise
+}
+
 func (defaultCampaignsResolver) CreatePatchSetFromPatches(ctx context.Context, args CreatePatchSetFromPatchesArgs) (PatchSetResolver, error) {
 	return nil, campaignsOnlyInEnterprise
 }
 
+func (defaultCampaignsResolver) CreatePatchSetFromReplacement(ctx context.Context, args *CreatePatchSetFromReplacementArgs) (PatchSetResolver, error) {
+	return nil, campaignsOnlyInEnterprise
+}
+
 func (defaultCampaignsResolver) PatchSetByID(ctx context.Context, id graphql.ID) (PatchSetResolver, error) {
 	return nil, campaignsOnlyInEnterprise
 }
@@ -187,6 +373,34 @@ func (defaultCampaignsResolver) PatchByID(ctx context.Context, id graphql.ID) (P
 	return nil, campaignsOnlyInEnterprise
 }
 
+func (defaultCampaignsResolver) CreateCampaignTemplate(ctx context.Context, args *CreateCampaignTemplateArgs) (CampaignTemplateResolver, error) {
+	return nil, campaignsOnlyInEnterprise
+}
+
+func (defaultCampaignsResolver) UpdateCampaignTemplate(ctx context.Context, args *UpdateCampaignTemplateArgs) (CampaignTemplateResolver, error) {
+	return nil, campaignsOnlyInEnterprise
+}
+
+func (defaultCampaignsResolver) DeleteCampaignTemplate(ctx context.Context, args *DeleteCampaignTemplateArgs) (*EmptyResponse, error) {
+	return nil, campaignsOnlyInEnterprise
+}
+
+func (defaultCampaignsResolver) InstantiateCampaignTemplate(ctx context.Context, args *InstantiateCampaignTemplateArgs) (CampaignResolver, error) {
+	return nil, campaignsOnlyInEnterprise
+}
+
+func (defaultCampaignsResolver) CampaignTemplateByID(ctx context.Context, id graphql.ID) (CampaignTemplateResolver, error) {
+	return nil, campaignsOnlyInEnterprise
+}
+
+func (defaultCampaignsResolver) CampaignTemplates(ctx context.Context, args *ListCampaignTemplatesArgs) (CampaignTemplatesConnectionResolver, error) {
+	return nil, campaignsOnlyInEnterprise
+}
+
+func (defaultCampaignsResolver) EncryptCampaignsSigningKey(ctx context.Context, args *EncryptCampaignsSigningKeyArgs) (string, error) {
+	return "", campaignsOnlyInEnterprise
+}
+
 type ChangesetCountsArgs struct {
 	From *DateTime
 	To   *DateTime
@@ -216,8 +430,67 @@ type CampaignResolver interface {
 	PatchSet(ctx context.Context) (PatchSetResolver, error)
 	Status(context.Context) (BackgroundProcessStatus, error)
 	ClosedAt() *DateTime
+	AutoMerge() bool
+	MergeMethod() campaigns.ChangesetMergeMethod
+	MergesPerHour() int32
+	TemplateVersion(ctx context.Context) (CampaignTemplateVersionResolver, error)
+	TemplateParameters() []CampaignTemplateParameterValueResolver
+	ChangesetOptions() ChangesetOptionsResolver
 	PublishedAt(ctx context.Context) (*DateTime, error)
 	Patches(ctx context.Context, args *graphqlutil.ConnectionArgs) PatchConnectionResolver
+	ChangesetImportQueries(ctx context.Context) ([]ChangesetImportQueryResolver, error)
+	ChangesetActions(ctx context.Context) ([]ChangesetActionResolver, error)
+	NotificationSubscriptions(ctx context.Context) ([]CampaignNotificationSubscriptionResolver, error)
+}
+
+type ChangesetImportQueryResolver interface {
+	ID() graphql.ID
+	ExternalService(ctx context.Context) (*externalServiceResolver, error)
+	Query() string
+	LastImportedAt() *DateTime
+	LastError() *string
+	CreatedAt() DateTime
+}
+
+type ChangesetActionResolver interface {
+	ID() graphql.ID
+	Type() campaigns.ChangesetActionType
+	Body() *string
+	Labels() []string
+	Reviewers() []string
+	Author(ctx context.Context) (*UserResolver, error)
+	CreatedAt() DateTime
+	Status(ctx context.Context) (BackgroundProcessStatus, error)
+	Failures(ctx context.Context) ([]ChangesetActionFailureResolver, error)
+}
+
+type ChangesetActionFailureResolver interface {
+	Changeset(ctx context.Context) (ExternalChangesetResolver, error)
+	Error() string
+}
+
+type CampaignNotificationSubscriptionResolver interface {
+	ID() graphql.ID
+	Channel() campaigns.CampaignNotificationChannel
+	URL() *string
+	HasSecret() bool
+	Transitions() []campaigns.ChangesetTransition
+	User(ctx context.Context) (*UserResolver, error)
+	CreatedAt() DateTime
+}
+
+type ChangesetOptionsResolver interface {
+	TitleTemplate() *string
+	BodyTemplate() *string
+	Labels() []string
+	Reviewers() ChangesetUsersRuleResolver
+	Assignees() ChangesetUsersRuleResolver
+	Draft() bool
+}
+
+type ChangesetUsersRuleResolver interface {
+	Users() []string
+	FromCodeOwners() bool
 }
 
 type CampaignsConnectionResolver interface {
@@ -249,6 +522,7 @@ type ExternalChangesetResolver interface {
 	ExternalURL() (*externallink.Resolver, error)
 	ReviewState(context.Context) campaigns.ChangesetReviewState
 	CheckState(context.Context) (*campaigns.ChangesetCheckState, error)
+	RebaseState() campaigns.ChangesetRebaseState
 	Repository(ctx context.Context) (*RepositoryResolver, error)
 	Campaigns(ctx context.Context, args *ListCampaignArgs) (CampaignsConnectionResolver, error)
 	Events(ctx context.Context, args *struct{ graphqlutil.ConnectionArgs }) (ChangesetEventsConnectionResolver, error)
@@ -296,6 +570,80 @@ type ChangesetCountsResolver interface {
 	OpenPending() int32
 }
 
+type CampaignsReportResolver interface {
+	TotalCount() int32
+	TimeToFirstReview() DurationDistributionResolver
+	TimeToMerge() DurationDistributionResolver
+	StaleChangesets(ctx context.Context) ([]ExternalChangesetResolver, error)
+	RepositoryOwners() []CampaignsReportRepositoryOwnerResolver
+	CSV() (string, error)
+}
+
+type DurationDistributionResolver interface {
+	Count() int32
+	Mean() *int32
+	P50() *int32
+	P90() *int32
+	Max() *int32
+}
+
+type CampaignsReportRepositoryOwnerResolver interface {
+	Owner() string
+	Total() int32
+	Open() int32
+	Merged() int32
+	Closed() int32
+	Stale() int32
+}
+
+type CampaignTemplateResolver interface {
+	ID() graphql.ID
+	Namespace(ctx context.Context) (NamespaceResolver, error)
+	Name() string
+	Description() *string
+	Author(ctx context.Context) (*UserResolver, error)
+	CreatedAt() DateTime
+	UpdatedAt() DateTime
+	LatestVersion(ctx context.Context) (CampaignTemplateVersionResolver, error)
+	Versions(ctx context.Context, args *graphqlutil.ConnectionArgs) CampaignTemplateVersionsConnectionResolver
+}
+
+type CampaignTemplateVersionResolver interface {
+	Template(ctx context.Context) (CampaignTemplateResolver, error)
+	Version() int32
+	Action() string
+	ScopeQuery() string
+	BranchPattern() string
+	TitleTemplate() string
+	BodyTemplate() string
+	Parameters() []CampaignTemplateParameterResolver
+	Author(ctx context.Context) (*UserResolver, error)
+	CreatedAt() DateTime
+}
+
+type CampaignTemplateParameterResolver interface {
+	Name() string
+	Description() *string
+	Default() *string
+}
+
+type CampaignTemplateParameterValueResolver interface {
+	Name() string
+	Value() string
+}
+
+type CampaignTemplatesConnectionResolver interface {
+	Nodes(ctx context.Context) ([]CampaignTemplateResolver, error)
+	TotalCount(ctx context.Context) (int32, error)
+	PageInfo(ctx context.Context) (*graphqlutil.PageInfo, error)
+}
+
+type CampaignTemplateVersionsConnectionResolver interface {
+	Nodes(ctx context.Context) ([]CampaignTemplateVersionResolver, error)
+	TotalCount(ctx context.Context) (int32, error)
+	PageInfo(ctx context.Context) (*graphqlutil.PageInfo, error)
+}
+
 type BackgroundProcessStatus interface {
 	CompletedCount() int32
 	PendingCount() int32
@@ -311,6 +659,8 @@ type PatchSetResolver interface {
 	Patches(ctx context.Context, args *graphqlutil.ConnectionArgs) PatchConnectionResolver
 
 	PreviewURL() string
+
+	Status(ctx context.Context) (BackgroundProcessStatus, error)
 }
 
 type PreviewFileDiff interface {
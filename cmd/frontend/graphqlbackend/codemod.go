@@ -17,7 +17,6 @@ import (
 	"github.com/pkg/errors"
 	"github.com/sourcegraph/go-diff/diff"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/goroutine"
-	"github.com/sourcegraph/sourcegraph/internal/env"
 	"github.com/sourcegraph/sourcegraph/internal/errcode"
 	"github.com/sourcegraph/sourcegraph/internal/lazyregexp"
 	"github.com/sourcegraph/sourcegraph/internal/search"
@@ -208,8 +207,6 @@ func performCodemod(ctx context.Context, args *search.TextParameters) ([]SearchR
 	return results, common, nil
 }
 
-var ReplacerURL = env.Get("REPLACER_URL", "http://replacer:3185", "replacer server URL")
-
 func toMatchResolver(fileURL string, raw *rawCodemodResult) ([]*searchResultMatchResolver, error) {
 	if !strings.Contains(raw.Diff, "@@") {
 		return nil, errors.Errorf("Invalid diff does not contain expected @@: %v", raw.Diff)
@@ -240,7 +237,7 @@ func callCodemodInRepo(ctx context.Context, repoRevs *search.RepositoryRevisions
 		return nil, errors.Wrap(err, "codemod repo lookup failed: it's possible that the repo is not cloned in gitserver. Try force a repo update another way.")
 	}
 
-	u, err := url.Parse(ReplacerURL)
+	u, err := url.Parse(search.ReplacerURL())
 	if err != nil {
 		return nil, err
 	}
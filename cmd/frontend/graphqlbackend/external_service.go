@@ -37,6 +37,18 @@ func externalServiceByID(ctx context.Context, id graphql.ID) (*externalServiceRe
 	return &externalServiceResolver{externalService: externalService}, nil
 }
 
+// ExternalServiceField implements the externalService field of the resolvers
+// of types referring to an external service, e.g. ChangesetImportQuery, which
+// embed it.
+type ExternalServiceField struct {
+	ExternalServiceID int64
+}
+
+// ExternalService returns the external service with the ID ExternalServiceID.
+func (f ExternalServiceField) ExternalService(ctx context.Context) (*externalServiceResolver, error) {
+	return externalServiceByID(ctx, marshalExternalServiceID(f.ExternalServiceID))
+}
+
 func marshalExternalServiceID(id int64) graphql.ID {
 	return relay.MarshalID(externalServiceIDKind, id)
 }
@@ -11,6 +11,7 @@ import (
 
 	graphql "github.com/graph-gophers/graphql-go"
 	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/audit"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend/graphqlutil"
@@ -19,6 +20,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/conf"
 	"github.com/sourcegraph/sourcegraph/internal/env"
 	"github.com/sourcegraph/sourcegraph/internal/repoupdater"
+	"github.com/sourcegraph/sourcegraph/internal/repoupdater/protocol"
 )
 
 var extsvcConfigAllowEdits, _ = strconv.ParseBool(env.Get("EXTSVC_CONFIG_ALLOW_EDITS", "false", "When EXTSVC_CONFIG_FILE is in use, allow edits in the application to be made which will be overwritten on next process restart"))
@@ -47,6 +49,7 @@ func (r *schemaResolver) AddExternalService(ctx context.Context, args *struct {
 	if err := db.ExternalServices.Create(ctx, conf.Get, externalService); err != nil {
 		return nil, err
 	}
+	audit.Log(ctx, audit.ActionExternalServiceCreate, audit.TargetExternalService, strconv.FormatInt(externalService.ID, 10), nil, externalServiceAuditState(externalService))
 
 	res := &externalServiceResolver{externalService: externalService}
 	if err := syncExternalService(ctx, externalService); err != nil {
@@ -80,6 +83,11 @@ func (*schemaResolver) UpdateExternalService(ctx context.Context, args *struct {
 		return nil, fmt.Errorf("blank external service configuration is invalid (must be valid JSONC)")
 	}
 
+	before, err := db.ExternalServices.GetByID(ctx, externalServiceID)
+	if err != nil {
+		return nil, err
+	}
+
 	ps := conf.Get().AuthProviders
 	update := &db.ExternalServiceUpdate{
 		DisplayName: args.Input.DisplayName,
@@ -93,6 +101,7 @@ func (*schemaResolver) UpdateExternalService(ctx context.Context, args *struct {
 	if err != nil {
 		return nil, err
 	}
+	audit.Log(ctx, audit.ActionExternalServiceUpdate, audit.TargetExternalService, strconv.FormatInt(externalServiceID, 10), externalServiceAuditState(before), externalServiceAuditState(externalService))
 
 	res := &externalServiceResolver{externalService: externalService}
 	if err = syncExternalService(ctx, externalService); err != nil {
@@ -102,6 +111,15 @@ func (*schemaResolver) UpdateExternalService(ctx context.Context, args *struct {
 	return res, nil
 }
 
+// externalServiceAuditState returns the state of an external service recorded in the audit log.
+func externalServiceAuditState(svc *types.ExternalService) interface{} {
+	return struct {
+		Kind        string      `json:"kind"`
+		DisplayName string      `json:"displayName"`
+		Config      audit.JSONC `json:"config"`
+	}{svc.Kind, svc.DisplayName, audit.JSONC(svc.Config)}
+}
+
 // Eagerly trigger a repo-updater sync.
 func syncExternalService(ctx context.Context, svc *types.ExternalService) error {
 	// Only give 5s to validate external service sync. Usually if there is a
@@ -125,6 +143,89 @@ func syncExternalService(ctx context.Context, svc *types.ExternalService) error
 	return nil
 }
 
+func (*schemaResolver) PreviewExternalServiceSync(ctx context.Context, args *struct {
+	Input *struct {
+		ID     *graphql.ID
+		Kind   *string
+		Config string
+	}
+}) (*externalServiceSyncPreviewResolver, error) {
+	// 🚨 SECURITY: Only site admins may preview external services, because the
+	// preview lists repositories using the external service's credentials.
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	svc := api.ExternalService{Config: args.Input.Config}
+	switch {
+	case args.Input.ID != nil:
+		id, err := unmarshalExternalServiceID(*args.Input.ID)
+		if err != nil {
+			return nil, err
+		}
+		existing, err := db.ExternalServices.GetByID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if args.Input.Kind != nil && *args.Input.Kind != existing.Kind {
+			return nil, errors.Errorf("kind %q does not match the kind %q of the external service", *args.Input.Kind, existing.Kind)
+		}
+		svc.ID = existing.ID
+		svc.Kind = existing.Kind
+		svc.DisplayName = existing.DisplayName
+	case args.Input.Kind != nil:
+		svc.Kind = *args.Input.Kind
+	default:
+		return nil, errors.New("either id or kind must be provided")
+	}
+
+	if err := db.ExternalServices.ValidateConfig(svc.Kind, svc.Config, conf.Get().AuthProviders); err != nil {
+		return nil, err
+	}
+
+	res, err := repoupdater.DefaultClient.PreviewExternalServiceSync(ctx, svc)
+	if err != nil {
+		return nil, err
+	}
+	return &externalServiceSyncPreviewResolver{result: res}, nil
+}
+
+type externalServiceSyncPreviewResolver struct {
+	result *protocol.ExternalServiceSyncPreviewResult
+}
+
+func (r *externalServiceSyncPreviewResolver) Added() *externalServiceSyncPreviewRepositoriesResolver {
+	return &externalServiceSyncPreviewRepositoriesResolver{summary: r.result.Added}
+}
+
+func (r *externalServiceSyncPreviewResolver) Modified() *externalServiceSyncPreviewRepositoriesResolver {
+	return &externalServiceSyncPreviewRepositoriesResolver{summary: r.result.Modified}
+}
+
+func (r *externalServiceSyncPreviewResolver) Deleted() *externalServiceSyncPreviewRepositoriesResolver {
+	return &externalServiceSyncPreviewRepositoriesResolver{summary: r.result.Deleted}
+}
+
+func (r *externalServiceSyncPreviewResolver) UnmodifiedCount() int32 {
+	return int32(r.result.Unmodified.Count)
+}
+
+type externalServiceSyncPreviewRepositoriesResolver struct {
+	summary protocol.RepoDiffSummary
+}
+
+func (r *externalServiceSyncPreviewRepositoriesResolver) TotalCount() int32 {
+	return int32(r.summary.Count)
+}
+
+func (r *externalServiceSyncPreviewRepositoriesResolver) Sample() []string {
+	names := make([]string, len(r.summary.Sample))
+	for i, name := range r.summary.Sample {
+		names[i] = string(name)
+	}
+	return names
+}
+
 func (*schemaResolver) DeleteExternalService(ctx context.Context, args *struct {
 	ExternalService graphql.ID
 }) (*EmptyResponse, error) {
@@ -149,6 +250,7 @@ func (*schemaResolver) DeleteExternalService(ctx context.Context, args *struct {
 	if err := db.ExternalServices.Delete(ctx, id); err != nil {
 		return nil, err
 	}
+	audit.Log(ctx, audit.ActionExternalServiceDelete, audit.TargetExternalService, strconv.FormatInt(id, 10), externalServiceAuditState(externalService), nil)
 	now := time.Now()
 	externalService.DeletedAt = &now
 
@@ -10,6 +10,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend/externallink"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend/graphqlutil"
 	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/perforce"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver"
 	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
 
@@ -153,6 +154,19 @@ func (r *GitCommitResolver) Body(ctx context.Context) (*string, error) {
 	return &body, nil
 }
 
+func (r *GitCommitResolver) PerforceChangelistID(ctx context.Context) (*string, error) {
+	r.resolveCommit(ctx)
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	id, ok := perforce.ChangelistID(r.message)
+	if !ok {
+		return nil, nil
+	}
+	return &id, nil
+}
+
 func (r *GitCommitResolver) Parents(ctx context.Context) ([]*GitCommitResolver, error) {
 	r.resolveCommit(ctx)
 	if r.err != nil {
@@ -15,6 +15,7 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 
 	"github.com/inconshreveable/log15"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/authz"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
 	"github.com/sourcegraph/sourcegraph/internal/api"
@@ -98,6 +99,15 @@ func (prometheusTracer) TraceField(ctx context.Context, label, typeName, fieldNa
 		ctx, finish = trace.OpenTracingTracer{}.TraceField(ctx, label, typeName, fieldName, trivial, args)
 	}
 
+	// 🚨 SECURITY: Restricted access tokens may only run the mutations their scopes allow. This
+	// checks the top-level fields of the operation as parsed by the GraphQL library, so aliases and
+	// fragments can't hide mutations, and the resolvers of denied mutations are not called.
+	if typeName == "Mutation" && fieldName != "__typename" {
+		if err := checkMutationScope(ctx, fieldName); err != nil {
+			ctx = &deniedContext{Context: ctx, err: err}
+		}
+	}
+
 	start := time.Now()
 	return ctx, func(err *gqlerrors.QueryError) {
 		isErrStr := strconv.FormatBool(err != nil)
@@ -167,6 +177,11 @@ func (r *NodeResolver) ToCampaign() (CampaignResolver, bool) {
 	return n, ok
 }
 
+func (r *NodeResolver) ToCampaignTemplate() (CampaignTemplateResolver, bool) {
+	n, ok := r.Node.(CampaignTemplateResolver)
+	return n, ok
+}
+
 func (r *NodeResolver) ToPatchSet() (PatchSetResolver, bool) {
 	n, ok := r.Node.(PatchSetResolver)
 	return n, ok
@@ -310,6 +325,8 @@ func (r *schemaResolver) nodeByID(ctx context.Context, id graphql.ID) (Node, err
 		return accessTokenByID(ctx, id)
 	case "Campaign":
 		return r.CampaignByID(ctx, id)
+	case "CampaignTemplate":
+		return r.CampaignTemplateByID(ctx, id)
 	case "PatchSet":
 		return r.PatchSetByID(ctx, id)
 	case "ExternalChangeset":
@@ -407,6 +424,11 @@ func (r *schemaResolver) RepositoryRedirect(ctx context.Context, args *struct {
 	Name     *string
 	CloneURL *string
 }) (*repositoryRedirect, error) {
+	// 🚨 SECURITY: Restricted access tokens need the repo:read scope to look up repositories.
+	if err := backend.CheckAccessTokenScope(ctx, authz.ScopeRepoRead); err != nil {
+		return nil, err
+	}
+
 	var name api.RepoName
 	if args.Name != nil {
 		// Query by name
@@ -0,0 +1,79 @@
+package graphqlbackend
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/authz"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+)
+
+// mutationScopes maps the mutations that actors authenticated with a restricted access token
+// may run to the access token scope they require. Mutations that are not listed here require the
+// "user:all" scope.
+//
+// 🚨 SECURITY: Only add a mutation here if it can't be used to do more than the scope allows,
+// e.g. to change the user's credentials or settings.
+var mutationScopes = map[string]string{
+	"createChangesets":                       authz.ScopeCampaignsWrite,
+	"addChangesetsToCampaign":                authz.ScopeCampaignsWrite,
+	"importChangesets":                       authz.ScopeCampaignsWrite,
+	"deleteChangesetImportQuery":             authz.ScopeCampaignsWrite,
+	"performChangesetAction":                 authz.ScopeCampaignsWrite,
+	"createCampaignNotificationSubscription": authz.ScopeCampaignsWrite,
+	"deleteCampaignNotificationSubscription": authz.ScopeCampaignsWrite,
+	"createCampaign":                         authz.ScopeCampaignsWrite,
+	"createPatchSetFromPatches":              authz.ScopeCampaignsWrite,
+	"createPatchSetFromReplacement":          authz.ScopeCampaignsWrite,
+	"updateCampaign":                         authz.ScopeCampaignsWrite,
+	"retryCampaign":                          authz.ScopeCampaignsWrite,
+	"deleteCampaign":                         authz.ScopeCampaignsWrite,
+	"closeCampaign":                          authz.ScopeCampaignsWrite,
+	"publishCampaign":                        authz.ScopeCampaignsWrite,
+	"publishChangeset":                       authz.ScopeCampaignsWrite,
+	"syncChangeset":                          authz.ScopeCampaignsWrite,
+	"createCampaignTemplate":                 authz.ScopeCampaignsWrite,
+	"updateCampaignTemplate":                 authz.ScopeCampaignsWrite,
+	"deleteCampaignTemplate":                 authz.ScopeCampaignsWrite,
+	"instantiateCampaignTemplate":            authz.ScopeCampaignsWrite,
+	"encryptCampaignsSigningKey":             authz.ScopeCampaignsWrite,
+}
+
+// checkMutationScope returns an error if the current actor was authenticated with a restricted
+// access token whose scopes don't allow the mutation.
+//
+// 🚨 SECURITY: Restricted access tokens are denied all mutations by default, so that mutations
+// that don't check the access token scope themselves can't be run with them.
+func checkMutationScope(ctx context.Context, mutation string) error {
+	a := actor.FromContext(ctx)
+	if a.Scopes == nil || a.HasScope(authz.ScopeUserAll) {
+		return nil
+	}
+
+	scope, ok := mutationScopes[mutation]
+	if !ok {
+		scope = authz.ScopeUserAll
+	}
+	if err := backend.CheckAccessTokenScope(ctx, scope); err != nil {
+		return fmt.Errorf("mutation %q: %s", mutation, err)
+	}
+	return nil
+}
+
+// deniedContext is the context of a GraphQL field that must not be resolved. The GraphQL library
+// checks the context of each field before calling its resolver, and returns the error of a done
+// context instead of calling it.
+type deniedContext struct {
+	context.Context
+	err error
+}
+
+var closedChan = func() chan struct{} {
+	c := make(chan struct{})
+	close(c)
+	return c
+}()
+
+func (c *deniedContext) Done() <-chan struct{} { return closedChan }
+func (c *deniedContext) Err() error            { return c.err }
@@ -0,0 +1,112 @@
+package graphqlbackend
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/authz"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+)
+
+func TestMutationScopes(t *testing.T) {
+	defer resetMocks()
+	var resolved []string
+	db.Mocks.AccessTokens.GetByID = func(id int64) (*db.AccessToken, error) {
+		resolved = append(resolved, "deleteAccessToken")
+		return nil, errors.New("access token not found")
+	}
+	const (
+		campaignsErr = "campaigns and changesets are only available in enterprise"
+		tokenErr     = "access token not found"
+		deniedErr    = `mutation "deleteAccessToken": access token must have scope "user:all"`
+	)
+
+	for _, tc := range []struct {
+		name         string
+		scopes       []string
+		query        string
+		wantErrs     []string
+		wantResolved bool
+	}{
+		{
+			name:         "not restricted",
+			query:        `mutation { deleteAccessToken(byID: "QWNjZXNzVG9rZW46MQ==") { alwaysNil } }`,
+			wantErrs:     []string{tokenErr},
+			wantResolved: true,
+		},
+		{
+			name:         "user:all",
+			scopes:       []string{authz.ScopeUserAll},
+			query:        `mutation { deleteAccessToken(byID: "QWNjZXNzVG9rZW46MQ==") { alwaysNil } }`,
+			wantErrs:     []string{tokenErr},
+			wantResolved: true,
+		},
+		{
+			name:   "query",
+			scopes: []string{authz.ScopeSearchRead},
+			query:  `query { __typename }`,
+		},
+		{
+			name:     "mutation allowed by scope",
+			scopes:   []string{authz.ScopeSearchRead, authz.ScopeCampaignsWrite},
+			query:    `mutation { createCampaign(input: {namespace: "n", name: "c"}) { id } }`,
+			wantErrs: []string{campaignsErr},
+		},
+		{
+			name:     "mutation not allowed by scope",
+			scopes:   []string{authz.ScopeSearchRead},
+			query:    `mutation { createCampaign(input: {namespace: "n", name: "c"}) { id } }`,
+			wantErrs: []string{`mutation "createCampaign": access token must have scope "campaigns:write"`},
+		},
+		{
+			name:     "unmapped mutation is denied",
+			scopes:   []string{authz.ScopeCampaignsWrite},
+			query:    `mutation { deleteAccessToken(byID: "QWNjZXNzVG9rZW46MQ==") { alwaysNil } }`,
+			wantErrs: []string{deniedErr},
+		},
+		{
+			name:     "unmapped mutation after allowed one is denied",
+			scopes:   []string{authz.ScopeCampaignsWrite},
+			query:    `mutation { createCampaign(input: {namespace: "n", name: "c"}) { id } deleteAccessToken(byID: "QWNjZXNzVG9rZW46MQ==") { alwaysNil } }`,
+			wantErrs: []string{campaignsErr, deniedErr},
+		},
+		{
+			name:     "alias",
+			scopes:   []string{authz.ScopeCampaignsWrite},
+			query:    `mutation { createCampaign: deleteAccessToken(byID: "QWNjZXNzVG9rZW46MQ==") { alwaysNil } }`,
+			wantErrs: []string{deniedErr},
+		},
+		{
+			name:     "fragment spread",
+			scopes:   []string{authz.ScopeCampaignsWrite},
+			query:    `mutation { ...F } fragment F on Mutation { deleteAccessToken(byID: "QWNjZXNzVG9rZW46MQ==") { alwaysNil } }`,
+			wantErrs: []string{deniedErr},
+		},
+		{
+			name:     "inline fragment",
+			scopes:   []string{authz.ScopeCampaignsWrite},
+			query:    `mutation { ... on Mutation { deleteAccessToken(byID: "QWNjZXNzVG9rZW46MQ==") { alwaysNil } } }`,
+			wantErrs: []string{deniedErr},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			resolved = nil
+			ctx := actor.WithActor(context.Background(), &actor.Actor{UID: 1, Scopes: tc.scopes})
+			resp := mustParseGraphQLSchema(t).Exec(ctx, tc.query, "", nil)
+			var errs []string
+			for _, err := range resp.Errors {
+				errs = append(errs, err.Message)
+			}
+			if !reflect.DeepEqual(errs, tc.wantErrs) {
+				t.Errorf("have errors %q, want %q", errs, tc.wantErrs)
+			}
+			// 🚨 SECURITY: The resolvers of denied mutations must not be called.
+			if have := len(resolved) > 0; have != tc.wantResolved {
+				t.Errorf("have resolved %v, want %v", have, tc.wantResolved)
+			}
+		})
+	}
+}
@@ -83,7 +83,7 @@ func (o *OrgResolver) CreatedAt() DateTime { return DateTime{Time: o.org.Created
 
 func (o *OrgResolver) Members(ctx context.Context) (*staticUserConnectionResolver, error) {
 	// 🚨 SECURITY: Only org members can list the org members.
-	if err := backend.CheckOrgAccess(ctx, o.org.ID); err != nil {
+	if err := backend.CheckOrgAccess(ctx, o.org.ID, types.OrgRoleReadOnly); err != nil {
 		if err == backend.ErrNotAnOrgMember {
 			return nil, errors.New("must be a member of this organization to view members")
 		}
@@ -112,7 +112,7 @@ func (o *OrgResolver) settingsSubject() api.SettingsSubject {
 func (o *OrgResolver) LatestSettings(ctx context.Context) (*settingsResolver, error) {
 	// 🚨 SECURITY: Only organization members and site admins may access the settings, because they
 	// may contains secrets or other sensitive data.
-	if err := backend.CheckOrgAccess(ctx, o.org.ID); err != nil {
+	if err := backend.CheckOrgAccess(ctx, o.org.ID, types.OrgRoleReadOnly); err != nil {
 		return nil, err
 	}
 
@@ -147,7 +147,7 @@ func (o *OrgResolver) ViewerPendingInvitation(ctx context.Context) (*organizatio
 }
 
 func (o *OrgResolver) ViewerCanAdminister(ctx context.Context) (bool, error) {
-	if err := backend.CheckOrgAccess(ctx, o.org.ID); err == backend.ErrNotAuthenticated || err == backend.ErrNotAnOrgMember {
+	if err := backend.CheckOrgAccess(ctx, o.org.ID, types.OrgRoleAdmin); err == backend.ErrNotAuthenticated || err == backend.ErrNotAnOrgMember || err == backend.ErrInsufficientOrgRole || backend.IsAccessTokenScopeError(err) {
 		return false, nil
 	} else if err != nil {
 		return false, err
@@ -169,6 +169,21 @@ func (o *OrgResolver) ViewerIsMember(ctx context.Context) (bool, error) {
 	return true, nil
 }
 
+func (o *OrgResolver) ViewerRole(ctx context.Context) (*types.OrgRole, error) {
+	actor := actor.FromContext(ctx)
+	if !actor.IsAuthenticated() {
+		return nil, nil
+	}
+	membership, err := db.OrgMembers.GetByOrgIDAndUserID(ctx, o.org.ID, actor.UID)
+	if err != nil {
+		if errcode.IsNotFound(err) {
+			err = nil
+		}
+		return nil, err
+	}
+	return &membership.Role, nil
+}
+
 func (o *OrgResolver) NamespaceName() string { return o.org.Name }
 
 func (*schemaResolver) CreateOrganization(ctx context.Context, args *struct {
@@ -191,8 +206,8 @@ func (*schemaResolver) CreateOrganization(ctx context.Context, args *struct {
 		return nil, err
 	}
 
-	// Add the current user as the first member of the new org.
-	_, err = db.OrgMembers.Create(ctx, newOrg.ID, currentUser.user.ID)
+	// Add the current user as the first member, and owner, of the new org.
+	_, err = db.OrgMembers.Create(ctx, newOrg.ID, currentUser.user.ID, types.OrgRoleOwner)
 	if err != nil {
 		return nil, err
 	}
@@ -209,9 +224,9 @@ func (*schemaResolver) UpdateOrganization(ctx context.Context, args *struct {
 		return nil, err
 	}
 
-	// 🚨 SECURITY: Check that the current user is a member
+	// 🚨 SECURITY: Check that the current user is an admin
 	// of the org that is being modified.
-	if err := backend.CheckOrgAccess(ctx, orgID); err != nil {
+	if err := backend.CheckOrgAccess(ctx, orgID, types.OrgRoleAdmin); err != nil {
 		return nil, err
 	}
 
@@ -236,11 +251,27 @@ func (*schemaResolver) RemoveUserFromOrganization(ctx context.Context, args *str
 		return nil, err
 	}
 
-	// 🚨 SECURITY: Check that the current user is a member of the org that is being modified, or a
-	// site admin.
-	if err := backend.CheckOrgAccess(ctx, orgID); err != nil {
+	membership, err := db.OrgMembers.GetByOrgIDAndUserID(ctx, orgID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 🚨 SECURITY: Members may remove themselves. Removing other members requires being an admin of
+	// the org, and removing an owner requires being an owner (or a site admin).
+	required := types.OrgRoleAdmin
+	if membership.Role == types.OrgRoleOwner {
+		required = types.OrgRoleOwner
+	} else if actor.FromContext(ctx).UID == userID {
+		required = types.OrgRoleReadOnly
+	}
+	if err := backend.CheckOrgAccess(ctx, orgID, required); err != nil {
 		return nil, err
 	}
+	if membership.Role == types.OrgRoleOwner {
+		if err := checkOrgKeepsOwner(ctx, orgID, userID); err != nil {
+			return nil, err
+		}
+	}
 
 	log15.Info("removing user from org", "user", userID, "org", orgID)
 	return nil, db.OrgMembers.Remove(ctx, orgID, userID)
@@ -264,8 +295,68 @@ func (*schemaResolver) AddUserToOrganization(ctx context.Context, args *struct {
 	if err != nil {
 		return nil, err
 	}
-	if _, err := db.OrgMembers.Create(ctx, orgID, userToInvite.ID); err != nil {
+	if _, err := db.OrgMembers.Create(ctx, orgID, userToInvite.ID, types.OrgRoleMember); err != nil {
 		return nil, err
 	}
 	return &EmptyResponse{}, nil
 }
+
+func (*schemaResolver) UpdateOrganizationMemberRole(ctx context.Context, args *struct {
+	Organization graphql.ID
+	User         graphql.ID
+	Role         string
+}) (*EmptyResponse, error) {
+	orgID, err := UnmarshalOrgID(args.Organization)
+	if err != nil {
+		return nil, err
+	}
+	userID, err := UnmarshalUserID(args.User)
+	if err != nil {
+		return nil, err
+	}
+	role := types.OrgRole(args.Role)
+	if !role.Valid() {
+		return nil, errors.Errorf("invalid organization role %q", args.Role)
+	}
+
+	membership, err := db.OrgMembers.GetByOrgIDAndUserID(ctx, orgID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 🚨 SECURITY: Changing roles requires being an admin of the org. Granting or revoking the owner
+	// and admin roles requires being an owner (or a site admin).
+	required := types.OrgRoleAdmin
+	if role.Includes(types.OrgRoleAdmin) || membership.Role.Includes(types.OrgRoleAdmin) {
+		required = types.OrgRoleOwner
+	}
+	if err := backend.CheckOrgAccess(ctx, orgID, required); err != nil {
+		return nil, err
+	}
+	if membership.Role == types.OrgRoleOwner && role != types.OrgRoleOwner {
+		if err := checkOrgKeepsOwner(ctx, orgID, userID); err != nil {
+			return nil, err
+		}
+	}
+
+	log15.Info("updating org member role", "user", userID, "org", orgID, "role", role)
+	if err := db.OrgMembers.UpdateRole(ctx, orgID, userID, role); err != nil {
+		return nil, err
+	}
+	return &EmptyResponse{}, nil
+}
+
+// checkOrgKeepsOwner returns an error if the user is the only owner of the organization, so that
+// removing or demoting them would leave nobody able to manage the organization's admins.
+func checkOrgKeepsOwner(ctx context.Context, orgID, userID int32) error {
+	memberships, err := db.OrgMembers.GetByOrgID(ctx, orgID)
+	if err != nil {
+		return err
+	}
+	for _, m := range memberships {
+		if m.UserID != userID && m.Role == types.OrgRoleOwner {
+			return nil
+		}
+	}
+	return errors.New("an organization must have at least one owner")
+}
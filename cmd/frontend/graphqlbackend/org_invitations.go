@@ -61,9 +61,9 @@ func (*schemaResolver) InviteUserToOrganization(ctx context.Context, args *struc
 	if err := relay.UnmarshalSpec(args.Organization, &orgID); err != nil {
 		return nil, err
 	}
-	// 🚨 SECURITY: Check that the current user is a member of the org that the user is being
+	// 🚨 SECURITY: Check that the current user is an admin of the org that the user is being
 	// invited to.
-	if err := backend.CheckOrgAccess(ctx, orgID); err != nil {
+	if err := backend.CheckOrgAccess(ctx, orgID, types.OrgRoleAdmin); err != nil {
 		return nil, err
 	}
 
@@ -136,7 +136,7 @@ func (*schemaResolver) RespondToOrganizationInvitation(ctx context.Context, args
 
 	if accept {
 		// The recipient accepted the invitation.
-		if _, err := db.OrgMembers.Create(ctx, orgID, currentUser.user.ID); err != nil {
+		if _, err := db.OrgMembers.Create(ctx, orgID, currentUser.user.ID, types.OrgRoleMember); err != nil {
 			return nil, err
 		}
 	}
@@ -151,8 +151,8 @@ func (*schemaResolver) ResendOrganizationInvitationNotification(ctx context.Cont
 		return nil, err
 	}
 
-	// 🚨 SECURITY: Check that the current user is a member of the org that the invite is for.
-	if err := backend.CheckOrgAccess(ctx, orgInvitation.v.OrgID); err != nil {
+	// 🚨 SECURITY: Check that the current user is an admin of the org that the invite is for.
+	if err := backend.CheckOrgAccess(ctx, orgInvitation.v.OrgID, types.OrgRoleAdmin); err != nil {
 		return nil, err
 	}
 
@@ -198,8 +198,8 @@ func (*schemaResolver) RevokeOrganizationInvitation(ctx context.Context, args *s
 		return nil, err
 	}
 
-	// 🚨 SECURITY: Check that the current user is a member of the org that the invite is for.
-	if err := backend.CheckOrgAccess(ctx, orgInvitation.v.OrgID); err != nil {
+	// 🚨 SECURITY: Check that the current user is an admin of the org that the invite is for.
+	if err := backend.CheckOrgAccess(ctx, orgInvitation.v.OrgID, types.OrgRoleAdmin); err != nil {
 		return nil, err
 	}
 
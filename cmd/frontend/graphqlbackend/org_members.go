@@ -44,6 +44,10 @@ func (r *organizationMembershipResolver) User(ctx context.Context) (*UserResolve
 	return UserByIDInt32(ctx, r.membership.UserID)
 }
 
+func (r *organizationMembershipResolver) Role() types.OrgRole {
+	return r.membership.Role
+}
+
 func (r *organizationMembershipResolver) CreatedAt() DateTime {
 	return DateTime{Time: r.membership.CreatedAt}
 }
@@ -4,9 +4,12 @@ import (
 	"context"
 	"testing"
 
+	graphql "github.com/graph-gophers/graphql-go"
 	"github.com/graph-gophers/graphql-go/gqltesting"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
 )
 
 func TestOrganization(t *testing.T) {
@@ -64,3 +67,197 @@ func TestNode_Org(t *testing.T) {
 		},
 	})
 }
+
+// mockOrgMembers mocks the members of the organization with ID 1. The current
+// user is the one with ID 1.
+func mockOrgMembers(members ...*types.OrgMembership) {
+	db.Mocks.Users.GetByCurrentAuthUser = func(context.Context) (*types.User, error) {
+		return &types.User{ID: 1}, nil
+	}
+	db.Mocks.OrgMembers.GetByOrgIDAndUserID = func(ctx context.Context, orgID, userID int32) (*types.OrgMembership, error) {
+		for _, m := range members {
+			if m.OrgID == orgID && m.UserID == userID {
+				return m, nil
+			}
+		}
+		return nil, &db.ErrOrgMemberNotFound{}
+	}
+	db.Mocks.OrgMembers.GetByOrgID = func(ctx context.Context, orgID int32) ([]*types.OrgMembership, error) {
+		return members, nil
+	}
+}
+
+func TestUpdateOrganizationMemberRole(t *testing.T) {
+	ctx := actor.WithActor(context.Background(), &actor.Actor{UID: 1})
+
+	tests := []struct {
+		name    string
+		members []*types.OrgMembership
+		user    int32
+		role    types.OrgRole
+		wantErr string
+	}{
+		{
+			name: "admin changes member to read-only",
+			members: []*types.OrgMembership{
+				{OrgID: 1, UserID: 1, Role: types.OrgRoleAdmin},
+				{OrgID: 1, UserID: 2, Role: types.OrgRoleMember},
+			},
+			user: 2,
+			role: types.OrgRoleReadOnly,
+		},
+		{
+			name: "admin may not grant admin",
+			members: []*types.OrgMembership{
+				{OrgID: 1, UserID: 1, Role: types.OrgRoleAdmin},
+				{OrgID: 1, UserID: 2, Role: types.OrgRoleMember},
+			},
+			user:    2,
+			role:    types.OrgRoleAdmin,
+			wantErr: backend.ErrInsufficientOrgRole.Error(),
+		},
+		{
+			name: "admin may not demote admin",
+			members: []*types.OrgMembership{
+				{OrgID: 1, UserID: 1, Role: types.OrgRoleAdmin},
+				{OrgID: 1, UserID: 2, Role: types.OrgRoleAdmin},
+			},
+			user:    2,
+			role:    types.OrgRoleMember,
+			wantErr: backend.ErrInsufficientOrgRole.Error(),
+		},
+		{
+			name: "member may not change roles",
+			members: []*types.OrgMembership{
+				{OrgID: 1, UserID: 1, Role: types.OrgRoleMember},
+				{OrgID: 1, UserID: 2, Role: types.OrgRoleReadOnly},
+			},
+			user:    2,
+			role:    types.OrgRoleMember,
+			wantErr: backend.ErrInsufficientOrgRole.Error(),
+		},
+		{
+			name: "owner grants owner",
+			members: []*types.OrgMembership{
+				{OrgID: 1, UserID: 1, Role: types.OrgRoleOwner},
+				{OrgID: 1, UserID: 2, Role: types.OrgRoleAdmin},
+			},
+			user: 2,
+			role: types.OrgRoleOwner,
+		},
+		{
+			name: "last owner may not demote themselves",
+			members: []*types.OrgMembership{
+				{OrgID: 1, UserID: 1, Role: types.OrgRoleOwner},
+				{OrgID: 1, UserID: 2, Role: types.OrgRoleAdmin},
+			},
+			user:    1,
+			role:    types.OrgRoleAdmin,
+			wantErr: "an organization must have at least one owner",
+		},
+		{
+			name: "invalid role",
+			members: []*types.OrgMembership{
+				{OrgID: 1, UserID: 1, Role: types.OrgRoleOwner},
+			},
+			user:    1,
+			role:    "SUPREME_LEADER",
+			wantErr: `invalid organization role "SUPREME_LEADER"`,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			resetMocks()
+			mockOrgMembers(test.members...)
+			var updated bool
+			db.Mocks.OrgMembers.UpdateRole = func(ctx context.Context, orgID, userID int32, role types.OrgRole) error {
+				if userID != test.user || role != test.role {
+					t.Errorf("got user %d role %q, want user %d role %q", userID, role, test.user, test.role)
+				}
+				updated = true
+				return nil
+			}
+
+			_, err := (&schemaResolver{}).UpdateOrganizationMemberRole(ctx, &struct {
+				Organization graphql.ID
+				User         graphql.ID
+				Role         string
+			}{
+				Organization: marshalOrgID(1),
+				User:         MarshalUserID(test.user),
+				Role:         string(test.role),
+			})
+			if test.wantErr == "" {
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !updated {
+					t.Error("role was not updated")
+				}
+				return
+			}
+			if err == nil || err.Error() != test.wantErr {
+				t.Errorf("got err %v, want %q", err, test.wantErr)
+			}
+			if updated {
+				t.Error("role was updated")
+			}
+		})
+	}
+}
+
+func TestRemoveUserFromOrganization_permissions(t *testing.T) {
+	ctx := actor.WithActor(context.Background(), &actor.Actor{UID: 1})
+
+	tests := []struct {
+		name    string
+		members []*types.OrgMembership
+		user    int32
+		wantErr string
+	}{
+		{
+			name: "member may not remove others",
+			members: []*types.OrgMembership{
+				{OrgID: 1, UserID: 1, Role: types.OrgRoleMember},
+				{OrgID: 1, UserID: 2, Role: types.OrgRoleMember},
+			},
+			user:    2,
+			wantErr: backend.ErrInsufficientOrgRole.Error(),
+		},
+		{
+			name: "admin may not remove owner",
+			members: []*types.OrgMembership{
+				{OrgID: 1, UserID: 1, Role: types.OrgRoleAdmin},
+				{OrgID: 1, UserID: 2, Role: types.OrgRoleOwner},
+			},
+			user:    2,
+			wantErr: backend.ErrInsufficientOrgRole.Error(),
+		},
+		{
+			name: "last owner may not remove themselves",
+			members: []*types.OrgMembership{
+				{OrgID: 1, UserID: 1, Role: types.OrgRoleOwner},
+				{OrgID: 1, UserID: 2, Role: types.OrgRoleAdmin},
+			},
+			user:    1,
+			wantErr: "an organization must have at least one owner",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			resetMocks()
+			mockOrgMembers(test.members...)
+
+			_, err := (&schemaResolver{}).RemoveUserFromOrganization(ctx, &struct {
+				User         graphql.ID
+				Organization graphql.ID
+			}{
+				User:         MarshalUserID(test.user),
+				Organization: marshalOrgID(1),
+			})
+			if err == nil || err.Error() != test.wantErr {
+				t.Errorf("got err %v, want %q", err, test.wantErr)
+			}
+		})
+	}
+}
@@ -119,7 +119,7 @@ func (r *RepositoryResolver) RedirectURL() *string {
 
 func (r *RepositoryResolver) ViewerCanAdminister(ctx context.Context) (bool, error) {
 	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
-		if err == backend.ErrMustBeSiteAdmin || err == backend.ErrNotAuthenticated {
+		if err == backend.ErrMustBeSiteAdmin || err == backend.ErrNotAuthenticated || backend.IsAccessTokenScopeError(err) {
 			return false, nil // not an error
 		}
 		return false, err
@@ -307,6 +307,13 @@ func (r *RepositoryResolver) AuthorizedUsers(ctx context.Context, args *Authoriz
 	})
 }
 
+func (r *RepositoryResolver) PermissionsSyncHistory(ctx context.Context, args *PermissionsSyncHistoryArgs) ([]PermissionsSyncHistoryEntryResolver, error) {
+	return EnterpriseResolvers.authzResolver.RepositoryPermissionsSyncHistory(ctx, &RepoPermissionsSyncHistoryArgs{
+		RepositoryID:               r.ID(),
+		PermissionsSyncHistoryArgs: args,
+	})
+}
+
 func (*schemaResolver) AddPhabricatorRepo(ctx context.Context, args *struct {
 	Callsign string
 	Name     *string
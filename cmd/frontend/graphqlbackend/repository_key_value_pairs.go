@@ -0,0 +1,74 @@
+package graphqlbackend
+
+import (
+	"context"
+	"sort"
+
+	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+)
+
+type keyValuePairResolver struct {
+	key   string
+	value *string
+}
+
+func (r *keyValuePairResolver) Key() string    { return r.key }
+func (r *keyValuePairResolver) Value() *string { return r.value }
+
+func (r *RepositoryResolver) KeyValuePairs(ctx context.Context) ([]*keyValuePairResolver, error) {
+	if err := r.hydrate(ctx); err != nil {
+		return nil, err
+	}
+
+	kvps := make([]*keyValuePairResolver, 0, len(r.repo.KeyValuePairs))
+	for k, v := range r.repo.KeyValuePairs {
+		kvps = append(kvps, &keyValuePairResolver{key: k, value: v})
+	}
+	sort.Slice(kvps, func(i, j int) bool { return kvps[i].key < kvps[j].key })
+	return kvps, nil
+}
+
+func (r *schemaResolver) SetRepositoryKeyValuePair(ctx context.Context, args *struct {
+	Repository graphql.ID
+	Key        string
+	Value      *string
+}) (*EmptyResponse, error) {
+	// 🚨 SECURITY: Only site admins can tag repositories, because tags are
+	// visible to and used in the searches of all users.
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	repoID, err := UnmarshalRepositoryID(args.Repository)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := db.Repos.SetKeyValuePair(ctx, repoID, args.Key, args.Value); err != nil {
+		return nil, err
+	}
+	return &EmptyResponse{}, nil
+}
+
+func (r *schemaResolver) DeleteRepositoryKeyValuePair(ctx context.Context, args *struct {
+	Repository graphql.ID
+	Key        string
+}) (*EmptyResponse, error) {
+	// 🚨 SECURITY: Only site admins can untag repositories, because tags are
+	// visible to and used in the searches of all users.
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	repoID, err := UnmarshalRepositoryID(args.Repository)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := db.Repos.DeleteKeyValuePair(ctx, repoID, args.Key); err != nil {
+		return nil, err
+	}
+	return &EmptyResponse{}, nil
+}
@@ -42,7 +42,7 @@ func savedSearchByID(ctx context.Context, id graphql.ID) (*savedSearchResolver,
 			return nil, err
 		}
 	} else if ss.Config.OrgID != nil {
-		if err := backend.CheckOrgAccess(ctx, *ss.Config.OrgID); err != nil {
+		if err := backend.CheckOrgAccess(ctx, *ss.Config.OrgID, types.OrgRoleReadOnly); err != nil {
 			return nil, err
 		}
 	} else {
@@ -166,7 +166,7 @@ func (r *schemaResolver) CreateSavedSearch(ctx context.Context, args *struct {
 			return nil, err
 		}
 		orgID = &o
-		if err := backend.CheckOrgAccess(ctx, o); err != nil {
+		if err := backend.CheckOrgAccess(ctx, o, types.OrgRoleMember); err != nil {
 			return nil, err
 		}
 	} else {
@@ -218,7 +218,7 @@ func (r *schemaResolver) UpdateSavedSearch(ctx context.Context, args *struct {
 			return nil, err
 		}
 		orgID = &o
-		if err := backend.CheckOrgAccess(ctx, o); err != nil {
+		if err := backend.CheckOrgAccess(ctx, o, types.OrgRoleMember); err != nil {
 			return nil, err
 		}
 	} else {
@@ -267,7 +267,7 @@ func (r *schemaResolver) DeleteSavedSearch(ctx context.Context, args *struct {
 			return nil, err
 		}
 	} else if ss.Config.OrgID != nil {
-		if err := backend.CheckOrgAccess(ctx, *ss.Config.OrgID); err != nil {
+		if err := backend.CheckOrgAccess(ctx, *ss.Config.OrgID, types.OrgRoleMember); err != nil {
 			return nil, err
 		}
 	} else {
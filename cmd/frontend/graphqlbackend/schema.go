@@ -37,6 +37,44 @@ type Mutation {
     # Adds a list of Changesets to a Campaign.
     # The campaign must not have a PatchSet.
     addChangesetsToCampaign(campaign: ID!, changesets: [ID!]!): Campaign!
+    # Searches the code host of the external service for existing changesets
+    # matching the query and adds them to the campaign. The query is saved and
+    # re-run periodically, so that new matching changesets are added to the
+    # campaign automatically.
+    #
+    # The query uses the syntax of the code host: GitHub search qualifiers (e.g.
+    # "author:renovate label:security") or, for Bitbucket Server, "repo:PROJECT/slug"
+    # terms combined with state:, author:, reviewer:, base: and head: filters.
+    #
+    # The campaign must not have a PatchSet.
+    #
+    # Only site admins may perform this mutation.
+    importChangesets(campaign: ID!, externalService: ID!, query: String!): ChangesetImportQuery!
+    # Deletes a changeset import query. The changesets it imported stay in the
+    # campaign.
+    #
+    # Only site admins may perform this mutation.
+    deleteChangesetImportQuery(changesetImportQuery: ID!): EmptyResponse
+    # Performs an action, e.g. posting a comment, on all open changesets of the
+    # campaign. The action is performed in the background and its progress is
+    # reported by the status of the returned ChangesetAction.
+    #
+    # Only site admins may perform this mutation.
+    performChangesetAction(campaign: ID!, input: ChangesetActionInput!): ChangesetAction!
+    # Subscribes to transitions of the changesets of a campaign, e.g. when they
+    # are merged or their checks fail.
+    #
+    # Only site admins may create WEBHOOK and SLACK subscriptions. Users who can
+    # view the campaign may subscribe themselves to EMAIL notifications.
+    createCampaignNotificationSubscription(
+        campaign: ID!
+        input: CampaignNotificationSubscriptionInput!
+    ): CampaignNotificationSubscription!
+    # Deletes a campaign notification subscription.
+    #
+    # Only site admins and the user who created the subscription may perform
+    # this mutation.
+    deleteCampaignNotificationSubscription(subscription: ID!): EmptyResponse
     # Create a campaign in a namespace. The newly created campaign is returned.
     createCampaign(input: CreateCampaignInput!): Campaign!
     # Create a patch set from patches (in unified diff format) that are computed by the caller.
@@ -48,6 +86,12 @@ type Mutation {
         # created from this PatchSet.
         patches: [PatchInput!]!
     ): PatchSet!
+    # Create a patch set whose patches are generated on the server by applying a replacement to
+    # the default branch of every repository that matches the replacement's search query. The
+    # patches are generated in the background and PatchSet.status reports the progress.
+    #
+    # Only site admins may perform this mutation.
+    createPatchSetFromReplacement(replacement: ReplacementInput!): PatchSet!
     # Updates a campaign.
     # Note, updating is not allowed when:
     # The campaign has already been closed.
@@ -91,6 +135,35 @@ type Mutation {
     publishChangeset(patch: ID!): EmptyResponse!
     # Enqueues the given changeset for high-priority syncing.
     syncChangeset(changeset: ID!): EmptyResponse!
+    # Creates a campaign template in a namespace. The given spec is stored as
+    # the first version of the template.
+    #
+    # Only site admins may perform this mutation.
+    createCampaignTemplate(input: CreateCampaignTemplateInput!): CampaignTemplate!
+    # Updates a campaign template. If a spec is given, it is stored as a new
+    # version of the template. Campaigns created from previous versions are not
+    # changed.
+    #
+    # Only site admins may perform this mutation.
+    updateCampaignTemplate(input: UpdateCampaignTemplateInput!): CampaignTemplate!
+    # Deletes a campaign template. Campaigns created from the template keep
+    # referring to the template version they were created from.
+    #
+    # Only site admins may perform this mutation.
+    deleteCampaignTemplate(campaignTemplate: ID!): EmptyResponse
+    # Creates a campaign from a version of a campaign template. The branch of
+    # the campaign is rendered from the template's branch pattern and the
+    # titles and bodies of its changesets from the template's title and body
+    # templates.
+    #
+    # Only site admins may perform this mutation.
+    instantiateCampaignTemplate(input: InstantiateCampaignTemplateInput!): Campaign!
+    # Encrypts a GPG or SSH private key with the SRC_SECRET_KEY of the
+    # Sourcegraph instance. The returned value can be used as the encryptedKey
+    # of the campaigns.signingKey site configuration setting.
+    #
+    # Only site admins may perform this mutation.
+    encryptCampaignsSigningKey(key: String!): String!
 
     # Updates the user profile information for the user with the given ID.
     #
@@ -112,6 +185,11 @@ type Mutation {
     updateExternalService(input: UpdateExternalServiceInput!): ExternalService!
     # Delete an external service. Only site admins may perform this mutation.
     deleteExternalService(externalService: ID!): EmptyResponse!
+    # Previews the changes to the set of repositories that syncing an external
+    # service with the given configuration would make, without saving the
+    # configuration or changing any repositories. Only site admins may perform
+    # this mutation.
+    previewExternalServiceSync(input: PreviewExternalServiceSyncInput!): ExternalServiceSyncPreview!
     # DEPRECATED: All repositories are accessible or deleted. To prevent a
     # repository from being accessed on Sourcegraph add it to the external
     # service exclude configuration. This mutation will be removed in 3.6.
@@ -141,6 +219,27 @@ type Mutation {
         # The mirror repository to update.
         repository: ID!
     ): EmptyResponse!
+    # Sets the value of a key/value tag on a repository, adding the tag if it doesn't exist yet. Tags can be
+    # used to filter search results with repo:has.meta(key) and repo:has.meta(key:value).
+    #
+    # Only site admins may perform this mutation.
+    setRepositoryKeyValuePair(
+        # The repository to tag.
+        repository: ID!
+        # The key of the tag.
+        key: String!
+        # The value of the tag, or null for a tag that is a key only.
+        value: String
+    ): EmptyResponse!
+    # Removes a key/value tag from a repository.
+    #
+    # Only site admins may perform this mutation.
+    deleteRepositoryKeyValuePair(
+        # The repository to remove the tag from.
+        repository: ID!
+        # The key of the tag.
+        key: String!
+    ): EmptyResponse!
     # DEPRECATED: All repositories are scheduled for updates periodically. This
     # mutation will be removed in 3.6.
     #
@@ -195,6 +294,49 @@ type Mutation {
     deleteUser(user: ID!, hard: Boolean): EmptyResponse
     # Updates the current user's password. The oldPassword arg must match the user's current password.
     updatePassword(oldPassword: String!, newPassword: String!): EmptyResponse
+    # Starts setting up an authenticator app for two-factor authentication of the current user. The result is
+    # the secret to add to the app. The setup is completed by confirmTOTPEnrollment.
+    #
+    # Only the currently authenticated user (signed in with a session, not with an access token) may perform
+    # this mutation.
+    beginTOTPEnrollment: BeginTOTPEnrollmentResult!
+    # Completes setting up an authenticator app for two-factor authentication of the current user, given a code
+    # from the app. If the user had no other second factor, the result contains the user's new recovery codes.
+    #
+    # Only the currently authenticated user (signed in with a session, not with an access token) may perform
+    # this mutation.
+    confirmTOTPEnrollment(code: String!): RecoveryCodesResult!
+    # Removes the authenticator app of the user, so that it can no longer be used for two-factor
+    # authentication.
+    #
+    # Only the user and site admins may perform this mutation. The user must provide their password.
+    disableTOTP(user: ID!, password: String): EmptyResponse!
+    # Replaces the recovery codes of the current user (which can each be used once instead of a second factor)
+    # with new ones.
+    #
+    # Only the currently authenticated user may perform this mutation.
+    generateRecoveryCodes(password: String!): RecoveryCodesResult!
+    # Starts registering a security key for two-factor authentication of the current user. The result is the
+    # publicKey options for navigator.credentials.create, with binary values encoded as base64url strings. The
+    # registration is completed by finishSecurityKeyRegistration.
+    #
+    # Only the currently authenticated user (signed in with a session, not with an access token) may perform
+    # this mutation.
+    beginSecurityKeyRegistration: JSONValue!
+    # Completes registering a security key for two-factor authentication of the current user, given the
+    # credential returned by navigator.credentials.create (with binary values encoded as base64url strings).
+    #
+    # Only the currently authenticated user (signed in with a session, not with an access token) may perform
+    # this mutation.
+    finishSecurityKeyRegistration(
+        # A name for the security key, to tell it apart from the user's other security keys.
+        name: String!
+        response: JSONValue!
+    ): FinishSecurityKeyRegistrationResult!
+    # Removes a security key of the user, so that it can no longer be used for two-factor authentication.
+    #
+    # Only the user and site admins may perform this mutation. The user must provide their password.
+    deleteSecurityKey(user: ID!, securityKey: ID!, password: String): EmptyResponse!
     # Creates an access token that grants the privileges of the specified user (referred to as the access token's
     # "subject" user after token creation). The result is the access token value, which the caller is responsible
     # for storing (it is not accessible by Sourcegraph after creation).
@@ -203,15 +345,42 @@ type Mutation {
     #
     # - "user:all": Full control of all resources accessible to the user account.
     # - "site-admin:sudo": Ability to perform any action as any other user. (Only site admins may create tokens
-    #   with this scope.)
+    #   with this scope, and only together with "user:all".)
+    # - "search:read": Ability to run searches.
+    # - "repo:read": Ability to read repositories and their contents.
+    # - "campaigns:write": Ability to view, create and update campaigns.
+    # - "codeintel:upload": Ability to upload LSIF data.
+    #
+    # An access token must have the "user:all" scope or at least one of the other scopes. Tokens without the
+    # "user:all" scope can't be used to change settings, user accounts or organizations.
+    #
+    # The optional expiresAt, allowedIPs and repositoryPatterns restrict until when, from which IP addresses
+    # (or CIDR ranges) and for which repositories (whose whole names match one of the regular expressions,
+    # e.g. "github\.com/my-org/.*") the access token can be used.
     #
     # Only the user or site admins may perform this mutation.
-    createAccessToken(user: ID!, scopes: [String!]!, note: String!): CreateAccessTokenResult!
+    createAccessToken(
+        user: ID!
+        scopes: [String!]!
+        note: String!
+        expiresAt: DateTime
+        allowedIPs: [String!]
+        repositoryPatterns: [String!]
+    ): CreateAccessTokenResult!
     # Deletes and immediately revokes the specified access token, specified by either its ID or by the token
     # itself.
     #
     # Only site admins or the user who owns the token may perform this mutation.
     deleteAccessToken(byID: ID, byToken: String): EmptyResponse!
+    # Revokes a session of a user, which signs the user out of the browser (or other client) of the session.
+    #
+    # Only site admins or the user who owns the session may perform this mutation.
+    revokeSession(session: ID!): EmptyResponse!
+    # Revokes all sessions of a user, which signs the user out everywhere. The viewer's current session is not
+    # revoked, so users can use it to sign out of all their other sessions.
+    #
+    # Only site admins or the user may perform this mutation.
+    revokeAllSessions(user: ID!): EmptyResponse!
     # Deletes the association between an external account and its Sourcegraph user. It does NOT delete the external
     # account on the external service where it resides.
     #
@@ -220,7 +389,7 @@ type Mutation {
     # Invite the user with the given username to join the organization. The invited user account must already
     # exist.
     #
-    # Only site admins and any organization member may perform this mutation.
+    # Only site admins and the organization's admins and owners may perform this mutation.
     inviteUserToOrganization(organization: ID!, username: String!): InviteUserToOrganizationResult!
     # Accept or reject an existing organization invitation.
     #
@@ -233,7 +402,7 @@ type Mutation {
     ): EmptyResponse!
     # Resend the notification about an organization invitation to the recipient.
     #
-    # Only site admins and any member of the organization may perform this mutation.
+    # Only site admins and the organization's admins and owners may perform this mutation.
     resendOrganizationInvitationNotification(
         # The organization invitation.
         organizationInvitation: ID!
@@ -243,20 +412,35 @@ type Mutation {
     # If the invitation has been accepted or rejected, it may no longer be revoked. After an
     # invitation is revoked, the recipient may not accept or reject it. Both cases yield an error.
     #
-    # Only site admins and any member of the organization may perform this mutation.
+    # Only site admins and the organization's admins and owners may perform this mutation.
     revokeOrganizationInvitation(
         # The organization invitation.
         organizationInvitation: ID!
     ): EmptyResponse!
     # Immediately add a user as a member to the organization, without sending an invitation email.
     #
-    # Only site admins may perform this mutation. Organization members may use the inviteUserToOrganization
-    # mutation to invite users.
+    # Only site admins may perform this mutation. Organization admins and owners may use the
+    # inviteUserToOrganization mutation to invite users.
     addUserToOrganization(organization: ID!, username: String!): EmptyResponse!
     # Removes a user as a member from an organization.
     #
-    # Only site admins and any member of the organization may perform this mutation.
+    # Members may remove themselves. Only site admins and the organization's admins and owners may remove
+    # other members, and only site admins and owners may remove an owner. The last owner of an organization
+    # may not be removed.
     removeUserFromOrganization(user: ID!, organization: ID!): EmptyResponse
+    # Changes the role of a member of an organization.
+    #
+    # Only site admins and the organization's admins and owners may perform this mutation. Only site admins
+    # and owners may grant or revoke the OWNER or ADMIN roles. The last owner of an organization may not be
+    # demoted.
+    updateOrganizationMemberRole(
+        # The organization.
+        organization: ID!
+        # The member whose role to change.
+        user: ID!
+        # The new role.
+        role: OrgRole!
+    ): EmptyResponse!
     # Adds or removes a tag on a user.
     #
     # Tags are used internally by Sourcegraph as feature flags for experimental features.
@@ -413,6 +597,69 @@ type Mutation {
         # The level of repository permission.
         perm: RepositoryPermission = READ
     ): EmptyResponse!
+    # Set permissions of many repositories at once, each with a full set of users by their usernames or
+    # emails. Either all or none of the permissions are set. Users who do not exist yet (or whose email
+    # address is not verified yet) are granted the permissions when they do.
+    #
+    # Explicit permissions only apply to repositories whose code host has no authorization provider, and
+    # require "permissions.userMapping" to be enabled in site configuration.
+    setRepositoryPermissionsForBulkOperation(
+        # The permissions to set, at most one per repository.
+        permissions: [RepositoryPermissionsInput!]!
+        # The level of repository permission.
+        perm: RepositoryPermission = READ
+    ): EmptyResponse!
+    # Schedule a permissions sync of the user with a high priority, ahead of the regularly scheduled syncs.
+    # Requires "permissions.backgroundSync" to be enabled in site configuration.
+    #
+    # Only site admins may perform this mutation.
+    scheduleUserPermissionsSync(
+        # The user to sync permissions for.
+        user: ID!
+    ): EmptyResponse!
+    # Schedule a permissions sync of the repository with a high priority, ahead of the regularly scheduled
+    # syncs. Requires "permissions.backgroundSync" to be enabled in site configuration.
+    #
+    # Only site admins may perform this mutation.
+    scheduleRepositoryPermissionsSync(
+        # The repository to sync permissions for.
+        repository: ID!
+    ): EmptyResponse!
+}
+
+# The users who have permission on a repository, used to set permissions of many repositories at once.
+input RepositoryPermissionsInput {
+    # The repository that the permissions are applied to.
+    repository: ID!
+    # A list of usernames or email addresses according to site configuration.
+    bindIDs: [String!]!
+}
+
+# The kind of a replacement.
+enum ReplacementKind {
+    # The match is a regular expression and the rewrite a replacement string that can refer
+    # to submatches with $1.
+    REGEXP
+    # The match and rewrite are comby templates (see https://comby.dev).
+    COMBY
+}
+
+# A replacement used to generate the patches of a patch set on the server.
+input ReplacementInput {
+    # The search query whose matching repositories the replacement is applied to.
+    query: String!
+
+    # The kind of the replacement.
+    kind: ReplacementKind!
+
+    # What to replace: a regular expression or a comby match template.
+    match: String!
+
+    # What to replace the match with.
+    rewrite: String!
+
+    # If set, only files with this extension are changed. Example: ".go"
+    fileExtension: String
 }
 
 # A patch to apply to a repository (in a new branch) when a campaign is created
@@ -464,6 +711,20 @@ input CreateCampaignInput {
     # When a Campaign is created in draft mode, its patches are not
     # created on the codehost, but only when publishing the Campaign.
     draft: Boolean
+
+    # Whether or not Sourcegraph merges the changesets of the campaign once
+    # they are approved and their checks passed. Default is false.
+    autoMerge: Boolean
+
+    # The method used to merge changesets when autoMerge is enabled. Default is MERGE.
+    mergeMethod: ChangesetMergeMethod
+
+    # The maximum number of changesets merged per hour when autoMerge is
+    # enabled. Default is 0, which means no limit.
+    mergesPerHour: Int
+
+    # Options that customize the changesets the campaign creates.
+    changesetOptions: ChangesetOptionsInput
 }
 
 # Input arguments for updating a campaign.
@@ -486,6 +747,286 @@ input UpdateCampaignInput {
     # The Campaign's status will be updated accordingly while possibly
     # new ExternalChangesets are created/updated/closed on the codehosts.
     patchSet: ID
+
+    # Whether or not Sourcegraph merges the changesets of the campaign once
+    # they are approved and their checks passed (if non-null).
+    autoMerge: Boolean
+
+    # The updated method used to merge changesets when autoMerge is enabled (if non-null).
+    mergeMethod: ChangesetMergeMethod
+
+    # The updated maximum number of changesets merged per hour when autoMerge
+    # is enabled (if non-null). 0 means no limit.
+    mergesPerHour: Int
+
+    # The updated options that customize the changesets the campaign creates
+    # (if non-null). Changesets that have already been published are updated
+    # accordingly.
+    changesetOptions: ChangesetOptionsInput
+}
+
+# Options that customize the changesets a campaign creates.
+#
+# titleTemplate and bodyTemplate are Go text/template templates that are
+# rendered for every repository. They can refer to {{.Repository}} (e.g.
+# "github.com/sourcegraph/sourcegraph"), {{.RepositoryName}} (e.g.
+# "sourcegraph"), {{.BaseRef}} (e.g. "master") and, for campaigns created
+# from a campaign template, the template's parameters with {{.Params.name}}.
+input ChangesetOptionsInput {
+    # The template of the title of each changeset. Defaults to the title
+    # template of the campaign template the campaign was created from, or the
+    # name of the campaign.
+    titleTemplate: String
+
+    # The template of the body of each changeset. Requires titleTemplate.
+    bodyTemplate: String
+
+    # The labels added to each changeset. Not supported on Bitbucket Server.
+    labels: [String!]
+
+    # The users requested to review each changeset.
+    reviewers: ChangesetUsersRuleInput
+
+    # The users assigned to each changeset. Not supported on Bitbucket Server.
+    assignees: ChangesetUsersRuleInput
+
+    # Whether or not changesets are drafts. Only supported on GitHub.
+    # Default is false.
+    draft: Boolean
+}
+
+# A rule that determines the users of a changeset.
+input ChangesetUsersRuleInput {
+    # Code host usernames. On GitHub, "org/team" names a team.
+    users: [String!]
+
+    # Whether or not the owners of the files changed by a changeset, as
+    # defined by the CODEOWNERS file of the repository, are added. Default is
+    # false.
+    fromCodeOwners: Boolean
+}
+
+# The spec of a campaign template.
+#
+# branchPattern, titleTemplate and bodyTemplate are Go text/template templates
+# that can refer to the values of the template's parameters with
+# {{.Params.name}}. titleTemplate and bodyTemplate are rendered for every
+# repository and can additionally refer to {{.Repository}} (e.g.
+# "github.com/sourcegraph/sourcegraph"), {{.RepositoryName}} (e.g.
+# "sourcegraph") and {{.BaseRef}} (e.g. "master").
+input CampaignTemplateSpecInput {
+    # The src-cli action definition that produces the patches of campaigns
+    # created from the template.
+    action: String!
+
+    # The search query that selects the repositories campaigns created from
+    # the template apply to.
+    scopeQuery: String!
+
+    # The template of the name of the branch created for each changeset.
+    branchPattern: String!
+
+    # The template of the title of each changeset.
+    titleTemplate: String!
+
+    # The template of the body of each changeset.
+    bodyTemplate: String
+
+    # The parameters of the template.
+    parameters: [CampaignTemplateParameterInput!]
+}
+
+# A parameter of a campaign template.
+input CampaignTemplateParameterInput {
+    # The name of the parameter. It must be a valid Go identifier.
+    name: String!
+
+    # The description of the parameter.
+    description: String
+
+    # The value of the parameter if none is given when instantiating the
+    # template. If null, a value is required.
+    default: String
+}
+
+# Input arguments for creating a campaign template.
+input CreateCampaignTemplateInput {
+    # The ID of the namespace where this campaign template is defined.
+    namespace: ID!
+
+    # The name of the campaign template.
+    name: String!
+
+    # The description of the campaign template as Markdown.
+    description: String
+
+    # The spec of the first version of the campaign template.
+    spec: CampaignTemplateSpecInput!
+}
+
+# Input arguments for updating a campaign template.
+input UpdateCampaignTemplateInput {
+    # The ID of the campaign template to update.
+    id: ID!
+
+    # The updated name of the campaign template (if non-null).
+    name: String
+
+    # The updated description of the campaign template as Markdown (if non-null).
+    description: String
+
+    # The spec of a new version of the campaign template (if non-null).
+    spec: CampaignTemplateSpecInput
+}
+
+# The value of a campaign template parameter.
+input CampaignTemplateParameterValueInput {
+    # The name of the parameter.
+    name: String!
+
+    # The value of the parameter.
+    value: String!
+}
+
+# Input arguments for creating a campaign from a campaign template.
+input InstantiateCampaignTemplateInput {
+    # The ID of the campaign template.
+    template: ID!
+
+    # The version of the campaign template. Defaults to its latest version.
+    version: Int
+
+    # The ID of the namespace where the campaign is defined.
+    namespace: ID!
+
+    # The name of the campaign. Defaults to the name of the campaign template.
+    name: String
+
+    # The description of the campaign as Markdown. Defaults to the
+    # description of the campaign template.
+    description: String
+
+    # The values of the template's parameters. Parameters without a value use
+    # their default.
+    parameters: [CampaignTemplateParameterValueInput!]
+
+    # An optional reference to a PatchSet, created by running the template's
+    # action, that was created before this mutation.
+    # See CreateCampaignInput.patchSet.
+    patchSet: ID
+
+    # Whether or not to create the Campaign in draft mode. Default is false.
+    draft: Boolean
+}
+
+# A reusable, versioned description of a campaign.
+type CampaignTemplate implements Node {
+    # The unique ID for the campaign template.
+    id: ID!
+
+    # The namespace where this campaign template is defined.
+    namespace: Namespace!
+
+    # The name of the campaign template.
+    name: String!
+
+    # The description as Markdown.
+    description: String
+
+    # The user who authored the campaign template.
+    author: User!
+
+    # The date and time when the campaign template was created.
+    createdAt: DateTime!
+
+    # The date and time when the campaign template was updated.
+    updatedAt: DateTime!
+
+    # The latest version of the campaign template.
+    latestVersion: CampaignTemplateVersion!
+
+    # The versions of the campaign template, oldest first.
+    versions(first: Int): CampaignTemplateVersionConnection!
+}
+
+# An immutable version of the spec of a campaign template.
+type CampaignTemplateVersion {
+    # The campaign template. Null if the campaign template has been deleted.
+    template: CampaignTemplate
+
+    # The version number, starting at 1.
+    version: Int!
+
+    # The src-cli action definition that produces the patches of campaigns
+    # created from the template.
+    action: String!
+
+    # The search query that selects the repositories campaigns created from
+    # the template apply to.
+    scopeQuery: String!
+
+    # The template of the name of the branch created for each changeset.
+    branchPattern: String!
+
+    # The template of the title of each changeset.
+    titleTemplate: String!
+
+    # The template of the body of each changeset.
+    bodyTemplate: String!
+
+    # The parameters of the template.
+    parameters: [CampaignTemplateParameter!]!
+
+    # The user who authored this version.
+    author: User!
+
+    # The date and time when this version was created.
+    createdAt: DateTime!
+}
+
+# A parameter of a campaign template.
+type CampaignTemplateParameter {
+    # The name of the parameter.
+    name: String!
+
+    # The description of the parameter.
+    description: String
+
+    # The default value of the parameter. If null, a value is required.
+    default: String
+}
+
+# The value of a campaign template parameter a campaign was created with.
+type CampaignTemplateParameterValue {
+    # The name of the parameter.
+    name: String!
+
+    # The value of the parameter.
+    value: String!
+}
+
+# A list of campaign templates.
+type CampaignTemplateConnection {
+    # A list of campaign templates.
+    nodes: [CampaignTemplate!]!
+
+    # The total number of campaign templates in the connection.
+    totalCount: Int!
+
+    # Pagination information.
+    pageInfo: PageInfo!
+}
+
+# A list of campaign template versions.
+type CampaignTemplateVersionConnection {
+    # A list of campaign template versions.
+    nodes: [CampaignTemplateVersion!]!
+
+    # The total number of campaign template versions in the connection.
+    totalCount: Int!
+
+    # Pagination information.
+    pageInfo: PageInfo!
 }
 
 # A set of Patches that will be turned into changesets by a campaign.
@@ -499,6 +1040,10 @@ type PatchSet implements Node {
 
     # The URL where the PatchSet can be previewed and a campaign can be created from it.
     previewURL: String!
+
+    # The progress of generating the patches of a PatchSet created with
+    # createPatchSetFromReplacement. It is always completed for other PatchSets.
+    status: BackgroundProcessStatus!
 }
 
 # A paginated list of repository diffs committed to git.
@@ -607,6 +1152,28 @@ type Campaign implements Node {
     # The date and time when the campaign was closed.
     closedAt: DateTime
 
+    # Whether or not Sourcegraph merges the changesets of the campaign once
+    # they are approved and their checks passed.
+    autoMerge: Boolean!
+
+    # The method used to merge changesets when autoMerge is enabled.
+    mergeMethod: ChangesetMergeMethod!
+
+    # The maximum number of changesets merged per hour when autoMerge is
+    # enabled. 0 means no limit.
+    mergesPerHour: Int!
+
+    # Options that customize the changesets the campaign creates.
+    changesetOptions: ChangesetOptions!
+
+    # The version of the campaign template the campaign was created from, if
+    # any.
+    templateVersion: CampaignTemplateVersion
+
+    # The values of the campaign template parameters the campaign was created
+    # with. Empty if the campaign was not created from a campaign template.
+    templateParameters: [CampaignTemplateParameterValue!]!
+
     # The date and time when the Campaign changed from draft mode to published.
     # If the Campaign has not been published yet (is still in draft mode) this
     # is null.
@@ -622,6 +1189,202 @@ type Campaign implements Node {
     # Campaign.status increments with every Patch turned into an
     # ExternalChangeset.
     patches(first: Int): PatchConnection!
+
+    # The queries that existing changesets are imported into the campaign with.
+    changesetImportQueries: [ChangesetImportQuery!]!
+
+    # The actions performed on the open changesets of the campaign, oldest
+    # first.
+    changesetActions: [ChangesetAction!]!
+
+    # The notification subscriptions of the campaign. Site admins see all
+    # subscriptions, other users only their own.
+    notificationSubscriptions: [CampaignNotificationSubscription!]!
+}
+
+# A transition of the state of a changeset that campaign notification
+# subscriptions are notified about.
+enum ChangesetTransition {
+    # The changeset was merged.
+    MERGED
+    # The changeset was closed without being merged.
+    CLOSED
+    # The checks of the changeset started failing.
+    CHECKS_FAILED
+    # A reviewer requested changes to the changeset.
+    CHANGES_REQUESTED
+}
+
+# How a CampaignNotificationSubscription is notified.
+enum CampaignNotificationChannel {
+    # A JSON payload is posted to a URL. If a secret is set, the payload is
+    # signed with it in the X-Sourcegraph-Signature header.
+    WEBHOOK
+    # A message is posted to a Slack incoming webhook URL.
+    SLACK
+    # The user who created the subscription is emailed.
+    EMAIL
+}
+
+# The input to the createCampaignNotificationSubscription mutation.
+input CampaignNotificationSubscriptionInput {
+    # How the subscription is notified.
+    channel: CampaignNotificationChannel!
+    # The URL to post to. Required for WEBHOOK and SLACK.
+    url: String
+    # The secret WEBHOOK payloads are signed with, as the hex encoded
+    # HMAC-SHA256 of the request body prefixed with "sha256=".
+    secret: String
+    # The transitions to notify about. All transitions if omitted or empty.
+    transitions: [ChangesetTransition!]
+}
+
+# A subscription to transitions of the changesets of a campaign.
+type CampaignNotificationSubscription {
+    # The unique ID for the subscription.
+    id: ID!
+
+    # How the subscription is notified.
+    channel: CampaignNotificationChannel!
+
+    # The URL WEBHOOK and SLACK subscriptions post to.
+    url: String
+
+    # Whether WEBHOOK payloads are signed. The secret itself is never exposed.
+    hasSecret: Boolean!
+
+    # The transitions the subscription is notified about. Empty if it's
+    # notified about all transitions.
+    transitions: [ChangesetTransition!]!
+
+    # The user who created the subscription. EMAIL subscriptions email this
+    # user.
+    user: User
+
+    # The date and time when the subscription was created.
+    createdAt: DateTime!
+}
+
+# The type of a ChangesetAction.
+enum ChangesetActionType {
+    # Post a comment on each changeset.
+    COMMENT
+    # Add labels to each changeset. Not supported by Bitbucket Server.
+    ADD_LABELS
+    # Remove labels from each changeset. Not supported by Bitbucket Server.
+    REMOVE_LABELS
+    # Request reviews of each changeset.
+    REQUEST_REVIEWERS
+    # Close each changeset, after posting a comment if a body is given.
+    CLOSE
+    # Re-run the failed checks of each changeset. Not supported by Bitbucket
+    # Server.
+    RERUN_FAILED_CHECKS
+}
+
+# The input to the performChangesetAction mutation.
+input ChangesetActionInput {
+    # The action to perform.
+    type: ChangesetActionType!
+    # The comment to post. Required for COMMENT, optional for CLOSE.
+    body: String
+    # The labels to add or remove. Required for ADD_LABELS and REMOVE_LABELS.
+    labels: [String!]
+    # The users, or teams as "org/team-slug" on GitHub, to request reviews
+    # from. Required for REQUEST_REVIEWERS.
+    reviewers: [String!]
+}
+
+# An action performed on all open changesets of a campaign.
+type ChangesetAction {
+    # The unique ID for the changeset action.
+    id: ID!
+
+    # The type of the action.
+    type: ChangesetActionType!
+
+    # The comment posted by the action, if any.
+    body: String
+
+    # The labels added or removed by the action.
+    labels: [String!]!
+
+    # The users or teams whose reviews were requested by the action.
+    reviewers: [String!]!
+
+    # The user who performed the action.
+    author: User
+
+    # The date and time when the action was performed.
+    createdAt: DateTime!
+
+    # The progress of performing the action on the changesets.
+    status: BackgroundProcessStatus!
+
+    # The changesets the action failed on, with the error.
+    failures: [ChangesetActionFailure!]!
+}
+
+# A changeset a ChangesetAction failed on.
+type ChangesetActionFailure {
+    # The changeset.
+    changeset: ExternalChangeset!
+
+    # The error the action failed with.
+    error: String!
+}
+
+# A query that finds existing changesets on a code host, which are imported
+# into a campaign. It is re-run periodically.
+type ChangesetImportQuery {
+    # The unique ID for the changeset import query.
+    id: ID!
+
+    # The external service whose code host is searched.
+    externalService: ExternalService!
+
+    # The query, in the syntax of the code host.
+    query: String!
+
+    # The date and time when the query was last run, if ever.
+    lastImportedAt: DateTime
+
+    # The error of the last run of the query, if it failed.
+    lastError: String
+
+    # The date and time when the changeset import query was created.
+    createdAt: DateTime!
+}
+
+# Options that customize the changesets a campaign creates.
+type ChangesetOptions {
+    # The template of the title of each changeset, if any.
+    titleTemplate: String
+
+    # The template of the body of each changeset, if any.
+    bodyTemplate: String
+
+    # The labels added to each changeset.
+    labels: [String!]!
+
+    # The users requested to review each changeset.
+    reviewers: ChangesetUsersRule!
+
+    # The users assigned to each changeset.
+    assignees: ChangesetUsersRule!
+
+    # Whether or not changesets are created as drafts.
+    draft: Boolean!
+}
+
+# A rule that determines the users of a changeset.
+type ChangesetUsersRule {
+    # Code host usernames. On GitHub, "org/team" names a team.
+    users: [String!]!
+
+    # Whether or not the owners of the files changed by a changeset, as
+    # defined by the CODEOWNERS file of the repository, are added.
+    fromCodeOwners: Boolean!
 }
 
 # The counts of changesets in certain states at a specific point in time.
@@ -680,6 +1443,26 @@ enum ChangesetCheckState {
     FAILED
 }
 
+# The state of rebasing a changeset's branch after its base branch moved.
+enum ChangesetRebaseState {
+    # The changeset's patch was applied to the latest commit of its base branch.
+    UP_TO_DATE
+    # The changeset's patch doesn't apply to the latest commit of its base
+    # branch anymore, or its branch contains commits that weren't pushed by
+    # Sourcegraph, and the changeset needs to be rebased manually.
+    NEEDS_MANUAL_REBASE
+}
+
+# The method used to merge a changeset on the code host.
+enum ChangesetMergeMethod {
+    # Merge with a merge commit.
+    MERGE
+    # Squash all commits into a single commit.
+    SQUASH
+    # Rebase the commits onto the base branch.
+    REBASE
+}
+
 # The input to the createChangesets mutation.
 input CreateChangesetInput {
     # The repository ID that this Changeset belongs to.
@@ -779,6 +1562,11 @@ type ExternalChangeset implements Node {
     # The state of the continuous integration checks on this changeset.
     # It can be null if no checks have been configured.
     checkState: ChangesetCheckState
+
+    # The state of rebasing the changeset's branch after its base branch
+    # moved. Only changesets created from a patch are rebased by Sourcegraph,
+    # for all others this is UP_TO_DATE.
+    rebaseState: ChangesetRebaseState!
 }
 
 # A list of changesets.
@@ -849,6 +1637,38 @@ input UpdateExternalServiceInput {
     config: String
 }
 
+# A proposed configuration of a new or existing external service to preview.
+input PreviewExternalServiceSyncInput {
+    # The id of the existing external service whose configuration would change. If omitted,
+    # the configuration is previewed as a new external service.
+    id: ID
+    # The kind of the new external service. Required if and only if id is omitted.
+    kind: ExternalServiceKind
+    # The proposed JSON configuration of the external service.
+    config: String!
+}
+
+# The changes to the set of repositories that syncing an external service with a proposed
+# configuration would make.
+type ExternalServiceSyncPreview {
+    # The repositories that would be added.
+    added: ExternalServiceSyncPreviewRepositories!
+    # The existing repositories whose metadata would be updated.
+    modified: ExternalServiceSyncPreviewRepositories!
+    # The existing repositories that would be removed.
+    deleted: ExternalServiceSyncPreviewRepositories!
+    # The number of existing repositories that would remain unchanged.
+    unmodifiedCount: Int!
+}
+
+# A set of repositories that a sync of an external service would change in the same way.
+type ExternalServiceSyncPreviewRepositories {
+    # The total number of repositories in the set.
+    totalCount: Int!
+    # The names of up to 100 of the repositories in the set, in alphabetical order.
+    sample: [String!]!
+}
+
 # A selection within a file.
 input DiscussionThreadTargetRepoSelectionInput {
     # The line that the selection started on (zero-based, inclusive).
@@ -1135,6 +1955,30 @@ type RandomizeUserPasswordResult {
     resetPasswordURL: String
 }
 
+# The result for Mutation.beginTOTPEnrollment.
+type BeginTOTPEnrollmentResult {
+    # The base32-encoded secret to enter in the authenticator app.
+    secret: String!
+    # The otpauth:// URL of the secret, to display as a QR code that the authenticator app can scan.
+    url: String!
+}
+
+# The result for mutations that may generate new recovery codes.
+type RecoveryCodesResult {
+    # The user's new recovery codes, if any were generated. They are only shown once, so the user must store
+    # them.
+    recoveryCodes: [String!]
+}
+
+# The result for Mutation.finishSecurityKeyRegistration.
+type FinishSecurityKeyRegistrationResult {
+    # The registered security key.
+    securityKey: SecurityKey!
+    # The user's new recovery codes, if the user had no other second factor. They are only shown once, so the
+    # user must store them.
+    recoveryCodes: [String!]
+}
+
 # Input for a user satisfaction (NPS) survey submission.
 input SurveySubmissionInput {
     # User-provided email address, if there is no currently authenticated user. If there is, this value
@@ -1148,6 +1992,62 @@ input SurveySubmissionInput {
     better: String
 }
 
+# A report about the review and merge progress of campaign changesets.
+type CampaignsReport {
+    # The number of changesets in the report.
+    totalCount: Int!
+
+    # The time between a changeset being opened and its first review, for all
+    # reviewed changesets.
+    timeToFirstReview: DurationDistribution!
+
+    # The time between a changeset being opened and merged, for all merged
+    # changesets.
+    timeToMerge: DurationDistribution!
+
+    # The open changesets without any activity for staleAfterDays, the ones
+    # with the least recent activity first.
+    staleChangesets: [ExternalChangeset!]!
+
+    # The changeset counts per repository owner, which is the name of a
+    # repository without its last path component, e.g. "github.com/my-org".
+    repositoryOwners: [CampaignsReportRepositoryOwner!]!
+
+    # The report as CSV, with a header row and one row per changeset.
+    csv: String!
+}
+
+# A summary of a set of durations. All durations are in seconds and null if
+# the set is empty.
+type DurationDistribution {
+    # The number of durations.
+    count: Int!
+    # The mean duration.
+    mean: Int
+    # The median duration.
+    p50: Int
+    # The 90th percentile duration.
+    p90: Int
+    # The longest duration.
+    max: Int
+}
+
+# The changeset counts of the repositories of a single owner in a campaigns report.
+type CampaignsReportRepositoryOwner {
+    # The owner, e.g. "github.com/my-org".
+    owner: String!
+    # The total number of changesets.
+    total: Int!
+    # The number of open changesets.
+    open: Int!
+    # The number of merged changesets.
+    merged: Int!
+    # The number of closed or deleted changesets.
+    closed: Int!
+    # The number of stale open changesets.
+    stale: Int!
+}
+
 # The state of the campaign
 enum CampaignState {
     OPEN
@@ -1170,6 +2070,25 @@ type Query {
         hasPatchSet: Boolean
     ): CampaignConnection!
 
+    # A list of campaign templates.
+    campaignTemplates(
+        # Returns the first n campaign templates from the list.
+        first: Int
+        # Only return campaign templates defined in the given namespace.
+        namespace: ID
+    ): CampaignTemplateConnection!
+
+    # A report about the review and merge progress of the changesets of
+    # campaigns, computed from their events.
+    campaignsReport(
+        # The campaigns whose changesets are included in the report. If null,
+        # the changesets of all campaigns are included.
+        campaigns: [ID!]
+        # The number of days without any activity after which an open
+        # changeset is considered stale.
+        staleAfterDays: Int = 14
+    ): CampaignsReport!
+
     # Looks up a repository by either name or cloneURL.
     repository(
         # Query the repository by name, for example "github.com/gorilla/mux".
@@ -1388,6 +2307,83 @@ type Query {
     # Returns a list of usernames or emails that have associated pending permissions.
     # The returned list can be used to query authorizedUserRepositories for pending permissions.
     usersWithPendingPermissions: [String!]!
+
+    # Explains whether a user can read a repository, i.e. which authorization provider or permissions
+    # grant or deny the user access to it. Only site admins may perform this query.
+    explainRepositoryAccess(
+        # The user.
+        user: ID!
+        # The repository.
+        repository: ID!
+    ): RepositoryAccessExplanation!
+}
+
+# The explanation of whether a user can read a repository.
+type RepositoryAccessExplanation {
+    # Whether the user can read the repository.
+    hasAccess: Boolean!
+    # The reasons that determined the access, in the order they were checked. The last reason is the
+    # one that decided it.
+    reasons: [RepositoryAccessReason!]!
+}
+
+# A reason that determines whether a user can read a repository.
+type RepositoryAccessReason {
+    # The kind of reason.
+    kind: RepositoryAccessReasonKind!
+    # Whether the reason grants access to the repository.
+    grantsAccess: Boolean!
+    # The service ID of the authorization provider that the reason is about, if any.
+    provider: String
+    # When the permissions that the reason is about were last updated, if known.
+    updatedAt: DateTime
+    # A human-readable description of the reason.
+    description: String!
+}
+
+# The kind of a reason that determines whether a user can read a repository.
+enum RepositoryAccessReasonKind {
+    # The user is a site admin, who can read all repositories.
+    SITE_ADMIN
+    # The repository is public.
+    PUBLIC_REPOSITORY
+    # There are no authorization providers and "authz.allowByDefault" is enabled (or the code host of the
+    # repository has no authorization provider).
+    ALLOWED_BY_DEFAULT
+    # The code host of the repository has no authorization provider.
+    NO_PROVIDER
+    # Permissions set explicitly through "permissions.userMapping".
+    EXPLICIT_PERMISSIONS
+    # Permissions synced from the code host in the background.
+    SYNCED_PERMISSIONS
+    # Permissions granted through the groups of the user on authentication providers.
+    GROUP_PERMISSIONS
+    # Permissions checked on the code host when the user requests the repository.
+    CODE_HOST_PERMISSIONS
+    # The user has no external account for the authorization provider of the code host.
+    NO_EXTERNAL_ACCOUNT
+}
+
+# A permissions sync of a user or a repository.
+type PermissionsSyncHistoryEntry {
+    # When the sync started.
+    startedAt: DateTime!
+    # When the sync finished.
+    finishedAt: DateTime!
+    # The service IDs of the authorization providers that permissions were synced from.
+    providers: [String!]!
+    # The number of repositories the user was granted access to (for syncs of a user).
+    repositoriesCount: Int!
+    # The number of users who were granted access to the repository (for syncs of a repository).
+    usersCount: Int!
+    # The number of code host accounts who were granted access to the repository but do not belong to any
+    # user yet (for syncs of a repository).
+    pendingAccountsCount: Int!
+    # Whether the code host returned partial results, in which case the existing permissions were kept
+    # in addition to the synced ones.
+    partialResults: Boolean!
+    # The error that the sync failed with, if any.
+    error: String
 }
 
 # The version of the search syntax.
@@ -1736,6 +2732,8 @@ enum ExternalServiceKind {
     GITHUB
     GITLAB
     GITOLITE
+    MANIFEST
+    PERFORCE
     PHABRICATOR
     OTHER
 }
@@ -1777,6 +2775,14 @@ type RepositoryConnection {
     pageInfo: PageInfo!
 }
 
+# A key/value tag attached to a repository.
+type KeyValuePair {
+    # The key of the tag.
+    key: String!
+    # The value of the tag, or null if the tag is a key only.
+    value: String
+}
+
 # A repository is a Git source control repository that is mirrored from some origin code host.
 type Repository implements Node & GenericSearchResultInterface {
     # The repository's unique ID.
@@ -1824,6 +2830,8 @@ type Repository implements Node & GenericSearchResultInterface {
     isFork: Boolean!
     # Whether the repository has been archived.
     isArchived: Boolean!
+    # The key/value tags attached to the repository, ordered by key.
+    keyValuePairs: [KeyValuePair!]!
     # Lists all external services which yield this repository.
     externalServices(
         # Returns the first n external services from the list.
@@ -1939,7 +2947,8 @@ type Repository implements Node & GenericSearchResultInterface {
 
     # A list of authorized users to access this repository with the given permission.
     # This API currently only returns permissions from the Sourcegraph provider, i.e.
-    # "permissions.userMapping" in site configuration.
+    # "permissions.userMapping" in site configuration, and from the group mappings of
+    # authentication providers.
     authorizedUsers(
         # Permission that the user has on this repository.
         perm: RepositoryPermission = READ
@@ -1948,6 +2957,13 @@ type Repository implements Node & GenericSearchResultInterface {
         # Opaque pagination cursor.
         after: String
     ): UserConnection!
+    # The most recent permissions syncs of this repository, newest first.
+    #
+    # Only site admins may query this field.
+    permissionsSyncHistory(
+        # Returns the first n syncs from the list.
+        first: Int = 10
+    ): [PermissionsSyncHistoryEntry!]!
 }
 
 # A reference to another Sourcegraph instance.
@@ -2511,6 +3527,9 @@ type GitCommit implements Node {
     subject: String!
     # The contents of the commit message after the first line.
     body: String
+    # The number of the Perforce changelist that this commit was imported from, if the repository is a
+    # Perforce depot imported with git p4.
+    perforceChangelistID: String
     # Parent commits of this commit.
     parents: [GitCommit!]!
     # The URL to this commit (using the input revision specifier, which may not be immutable).
@@ -3028,11 +4047,27 @@ type User implements Node & SettingsSubject & Namespace {
         # Returns the first n external accounts from the list.
         first: Int
     ): ExternalAccountConnection!
+    # The most recent permissions syncs of this user, newest first.
+    #
+    # Only site admins may query this field.
+    permissionsSyncHistory(
+        # Returns the first n syncs from the list.
+        first: Int = 10
+    ): [PermissionsSyncHistoryEntry!]!
+    # The user's two-factor authentication settings.
+    #
+    # Only the user and site admins can access this field.
+    mfa: UserMFA!
     # The user's currently active session.
     #
     # Only the currently authenticated user can access this field. Site admins are not able to access sessions for
     # other users.
     session: Session!
+    # The user's active sessions, i.e. the browsers (and other clients) the user is signed in with using a session
+    # cookie, most recently active first.
+    #
+    # Only the user and site admins can access this field.
+    sessions: [UserSession!]!
     # Whether the viewer has admin privileges on this user. The user has admin privileges on their own user, and
     # site admins have admin privileges on all users.
     viewerCanAdminister: Boolean!
@@ -3060,6 +4095,34 @@ type User implements Node & SettingsSubject & Namespace {
     namespaceName: String!
 }
 
+# A user's two-factor authentication settings. Users who sign in with a password (with the builtin
+# username-password authentication provider) can use an authenticator app or a security key as a second factor.
+type UserMFA {
+    # Whether users can set up two-factor authentication (which requires the builtin username-password
+    # authentication provider).
+    available: Boolean!
+    # Whether the site configuration requires the user to use two-factor authentication.
+    required: Boolean!
+    # Whether the user has set up an authenticator app.
+    totpEnabled: Boolean!
+    # The user's registered security keys.
+    securityKeys: [SecurityKey!]!
+    # The number of the user's unused recovery codes.
+    recoveryCodesRemaining: Int!
+}
+
+# A security key (WebAuthn credential) that a user registered for two-factor authentication.
+type SecurityKey {
+    # The unique ID for the security key.
+    id: ID!
+    # The name the user gave the security key.
+    name: String!
+    # The date when the security key was registered.
+    createdAt: DateTime!
+    # The date when the security key was last used to sign in, if ever.
+    lastUsedAt: DateTime
+}
+
 # An access token that grants to the holder the privileges of the user who created it.
 type AccessToken implements Node {
     # The unique ID for the access token.
@@ -3077,6 +4140,56 @@ type AccessToken implements Node {
     createdAt: DateTime!
     # The date when the access token was last used to authenticate a request.
     lastUsedAt: DateTime
+    # The date after which the access token can no longer be used, if any.
+    expiresAt: DateTime
+    # The IP addresses and CIDR ranges the access token can be used from. Empty if the access token can be
+    # used from any IP address.
+    allowedIPs: [String!]!
+    # The regular expressions matching the whole names of the repositories the access token can access.
+    # Empty if the access token can access all repositories accessible to its subject user.
+    repositoryPatterns: [String!]!
+}
+
+# An entry of the audit log, which records a security-relevant action performed on the site.
+type AuditLogEntry {
+    # The unique ID for the entry.
+    id: ID!
+    # The action performed (e.g., "site_config.update", "user.site_admin.update" or "user.sudo").
+    action: String!
+    # The user who performed the action. Null if the action was not performed by a user or if the user
+    # has been deleted since.
+    actor: User
+    # The database ID of the user who performed the action. Null if the action was not performed by a user.
+    actorDatabaseID: Int
+    # The IP address of the client that performed the action, if known.
+    actorIP: String
+    # The user agent of the client that performed the action, if known.
+    actorUserAgent: String
+    # The type of the entity the action was performed on (e.g., "site_config", "user", "access_token" or
+    # "external_service").
+    targetType: String!
+    # The ID of the entity the action was performed on. For users, access tokens and external services, this is
+    # their database ID.
+    targetID: String!
+    # The state of the entity before the action, with secrets redacted. Null if the entity did not exist before
+    # the action or if its state is not recorded for this action.
+    before: JSONValue
+    # The state of the entity after the action, with secrets redacted. Null if the entity does not exist after
+    # the action or if its state is not recorded for this action.
+    after: JSONValue
+    # The date when the action was performed.
+    createdAt: DateTime!
+}
+
+# A list of audit log entries.
+type AuditLogEntryConnection {
+    # A list of audit log entries.
+    nodes: [AuditLogEntry!]!
+    # The total count of audit log entries in the connection. This total count may be larger than the number of
+    # nodes in this object when the result is paginated.
+    totalCount: Int!
+    # Pagination information.
+    pageInfo: PageInfo!
 }
 
 # A list of access tokens.
@@ -3162,12 +4275,33 @@ type Session {
     canSignOut: Boolean!
 }
 
+# An active session of a user, i.e. a browser (or other client) the user is signed in with using a session
+# cookie.
+type UserSession {
+    # The unique ID for the session.
+    id: ID!
+    # The date when the user signed in.
+    createdAt: DateTime!
+    # The date when the session was last active. It is updated at most every few minutes.
+    lastActiveAt: DateTime!
+    # The date when the session expires, unless it is active again before then.
+    expiresAt: DateTime!
+    # The IP address of the client the session was last active from, if known.
+    ipAddress: String
+    # The user agent of the client the session was last active from, if known.
+    userAgent: String
+    # Whether this is the viewer's current session.
+    current: Boolean!
+}
+
 # An organization membership.
 type OrganizationMembership {
     # The organization.
     organization: Org!
     # The user.
     user: User!
+    # The user's role in the organization.
+    role: OrgRole!
     # The time when this was created.
     createdAt: DateTime!
     # The time when this was updated.
@@ -3183,6 +4317,18 @@ type OrganizationMembershipConnection {
     totalCount: Int!
 }
 
+# The role of a member of an organization. Each role grants the privileges of the roles below it.
+enum OrgRole {
+    # Can do everything admins can, and also manage the roles of other owners and admins.
+    OWNER
+    # Can manage the organization's members, invitations, settings and campaigns.
+    ADMIN
+    # Can use the organization's resources, such as saved searches and extensions.
+    MEMBER
+    # Can view the organization, its members and its settings.
+    READ_ONLY
+}
+
 # A user's email address.
 type UserEmail {
     # The email address.
@@ -3238,11 +4384,13 @@ type Org implements Node & SettingsSubject & Namespace {
         )
     # A pending invitation for the viewer to join this organization, if any.
     viewerPendingInvitation: OrganizationInvitation
-    # Whether the viewer has admin privileges on this organization. Currently, all of an organization's members
-    # have admin privileges on the organization.
+    # Whether the viewer has admin privileges on this organization, i.e. whether the viewer is a site admin or
+    # one of the organization's admins or owners.
     viewerCanAdminister: Boolean!
     # Whether the viewer is a member of this organization.
     viewerIsMember: Boolean!
+    # The viewer's role in this organization, or null if the viewer is not a member.
+    viewerRole: OrgRole
     # The URL to the organization.
     url: String!
     # The URL to the organization's settings.
@@ -3597,6 +4745,27 @@ type Site implements SettingsSubject {
         # Returns the first n access tokens from the list.
         first: Int
     ): AccessTokenConnection!
+    # The entries of the audit log, which records security-relevant actions such as site configuration changes,
+    # site admin promotions, access token and external service changes and requests made with sudo access tokens.
+    # The most recent entries are listed first.
+    #
+    # Only site admins may query this field.
+    auditLog(
+        # Returns the first n entries from the list.
+        first: Int
+        # Only include actions performed by this user.
+        actor: ID
+        # Only include this action (e.g., "site_config.update").
+        action: String
+        # Only include actions performed on entities of this type (e.g., "user").
+        targetType: String
+        # Only include actions performed on the entity with this ID. Requires targetType.
+        targetID: String
+        # Only include actions performed after this date.
+        since: DateTime
+        # Only include actions performed before this date.
+        until: DateTime
+    ): AuditLogEntryConnection!
     # A list of all authentication providers. This information is visible to all viewers and does not contain any
     # secret information.
     authProviders: AuthProviderConnection!
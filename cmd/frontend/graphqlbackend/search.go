@@ -17,6 +17,8 @@ import (
 	"github.com/neelance/parallel"
 	"github.com/pkg/errors"
 
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/authz"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/envvar"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/goroutine"
@@ -126,7 +128,11 @@ func NewSearchImplementer(args *SearchArgs) (SearchImplementer, error) {
 	}, nil
 }
 
-func (r *schemaResolver) Search(args *SearchArgs) (SearchImplementer, error) {
+func (r *schemaResolver) Search(ctx context.Context, args *SearchArgs) (SearchImplementer, error) {
+	// 🚨 SECURITY: Restricted access tokens need the search:read scope to run searches.
+	if err := backend.CheckAccessTokenScope(ctx, authz.ScopeSearchRead); err != nil {
+		return nil, err
+	}
 	return NewSearchImplementer(args)
 }
 
@@ -334,6 +340,30 @@ func exactlyOneRepo(repoFilters []string) bool {
 	return false
 }
 
+// keyValuePairFilterPattern matches repo: field values of the form
+// has.meta(key) and has.meta(key:value).
+var keyValuePairFilterPattern = lazyregexp.New(`^has\.meta\(([^:()]+)(?::(.*))?\)$`)
+
+// findKeyValuePairFilters separates the repo: field values that filter
+// repositories by their key/value tags from the repository name patterns. If
+// negated is true, the returned filters exclude the matching repositories.
+func findKeyValuePairFilters(patterns []string, negated bool) (rest []string, filters []db.RepoKVPFilter) {
+	for _, p := range patterns {
+		m := keyValuePairFilterPattern.FindStringSubmatch(p)
+		if m == nil {
+			rest = append(rest, p)
+			continue
+		}
+
+		f := db.RepoKVPFilter{Key: m[1], Negated: negated}
+		if strings.Contains(p, ":") {
+			f.Value = &m[2]
+		}
+		filters = append(filters, f)
+	}
+	return rest, filters
+}
+
 // resolveRepositories calls doResolveRepositories, caching the result for the common
 // case where effectiveRepoFieldValues == nil.
 func (r *searchResolver) resolveRepositories(ctx context.Context, effectiveRepoFieldValues []string) (repoRevs, missingRepoRevs []*search.RepositoryRevisions, overLimit bool, err error) {
@@ -522,6 +552,10 @@ func resolveRepositories(ctx context.Context, op resolveRepoOp) (repoRevisions,
 
 	excludePatterns := op.minusRepoFilters
 
+	includePatterns, kvpFilters := findKeyValuePairFilters(includePatterns, false)
+	excludePatterns, minusKVPFilters := findKeyValuePairFilters(excludePatterns, true)
+	kvpFilters = append(kvpFilters, minusKVPFilters...)
+
 	maxRepoListSize := maxReposToSearch()
 
 	// If any repo groups are specified, take the intersection of the repo
@@ -554,7 +588,7 @@ func resolveRepositories(ctx context.Context, op resolveRepoOp) (repoRevisions,
 	}
 
 	var defaultRepos []*types.Repo
-	if envvar.SourcegraphDotComMode() && len(includePatterns) == 0 {
+	if envvar.SourcegraphDotComMode() && len(includePatterns) == 0 && len(kvpFilters) == 0 {
 		getIndexedRepos := func(ctx context.Context, revs []*search.RepositoryRevisions) (indexed, unindexed []*search.RepositoryRevisions, err error) {
 			return zoektIndexedRepos(ctx, search.Indexed(), revs, nil)
 		}
@@ -577,13 +611,14 @@ func resolveRepositories(ctx context.Context, op resolveRepoOp) (repoRevisions,
 			IncludePatterns: includePatterns,
 			ExcludePattern:  unionRegExps(excludePatterns),
 			// List N+1 repos so we can see if there are repos omitted due to our repo limit.
-			LimitOffset:  &db.LimitOffset{Limit: maxRepoListSize + 1},
-			NoForks:      op.noForks,
-			OnlyForks:    op.onlyForks,
-			NoArchived:   op.noArchived,
-			OnlyArchived: op.onlyArchived,
-			NoPrivate:    op.onlyPublic,
-			OnlyPrivate:  op.onlyPrivate,
+			LimitOffset:   &db.LimitOffset{Limit: maxRepoListSize + 1},
+			NoForks:       op.noForks,
+			OnlyForks:     op.onlyForks,
+			NoArchived:    op.noArchived,
+			OnlyArchived:  op.onlyArchived,
+			NoPrivate:     op.onlyPublic,
+			OnlyPrivate:   op.onlyPrivate,
+			KeyValuePairs: kvpFilters,
 		})
 		tr.LazyPrintf("Repos.List - done")
 		if err != nil {
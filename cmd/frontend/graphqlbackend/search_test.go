@@ -379,6 +379,32 @@ func Test_exactlyOneRepo(t *testing.T) {
 	}
 }
 
+func Test_findKeyValuePairFilters(t *testing.T) {
+	str := func(s string) *string { return &s }
+
+	q, err := query.ParseAndCheck(`repo:has.meta(tier:1) -repo:has.meta(deprecated) repo:^github\.com/ foo`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	repoFilters, minusRepoFilters := q.RegexpPatterns(query.FieldRepo)
+
+	rest, filters := findKeyValuePairFilters(repoFilters, false)
+	if diff := cmp.Diff([]string{`^github\.com/`}, rest); diff != "" {
+		t.Errorf("rest: %s", diff)
+	}
+	if diff := cmp.Diff([]db.RepoKVPFilter{{Key: "tier", Value: str("1")}}, filters); diff != "" {
+		t.Errorf("filters: %s", diff)
+	}
+
+	rest, filters = findKeyValuePairFilters(minusRepoFilters, true)
+	if len(rest) != 0 {
+		t.Errorf("got rest %q, want none", rest)
+	}
+	if diff := cmp.Diff([]db.RepoKVPFilter{{Key: "deprecated", Negated: true}}, filters); diff != "" {
+		t.Errorf("minus filters: %s", diff)
+	}
+}
+
 func Test_QuoteSuggestions(t *testing.T) {
 	t.Run("regex error", func(t *testing.T) {
 		raw := "*"
@@ -10,6 +10,7 @@ import (
 
 	graphql "github.com/graph-gophers/graphql-go"
 	"github.com/graph-gophers/graphql-go/relay"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/audit"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/pkg/siteid"
@@ -78,7 +79,7 @@ func (r *siteResolver) CriticalConfiguration(ctx context.Context) (*criticalConf
 }
 
 func (r *siteResolver) ViewerCanAdminister(ctx context.Context) (bool, error) {
-	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err == backend.ErrMustBeSiteAdmin || err == backend.ErrNotAuthenticated {
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err == backend.ErrMustBeSiteAdmin || err == backend.ErrNotAuthenticated || backend.IsAccessTokenScopeError(err) {
 		return false, nil
 	} else if err != nil {
 		return false, err
@@ -174,11 +175,13 @@ func (r *schemaResolver) UpdateSiteConfiguration(ctx context.Context, args *stru
 		return false, fmt.Errorf("blank site configuration is invalid (you can clear the site configuration by entering an empty JSON object: {})")
 	}
 	prev := globals.ConfigurationServerFrontendOnly.Raw()
+	before := prev.Site
 	prev.Site = args.Input
 	// TODO(slimsag): future: actually pass lastID through to prevent race conditions
 	if err := globals.ConfigurationServerFrontendOnly.Write(ctx, prev); err != nil {
 		return false, err
 	}
+	audit.Log(ctx, audit.ActionSiteConfigUpdate, audit.TargetSiteConfig, "", audit.JSONC(before), audit.JSONC(args.Input))
 	return globals.ConfigurationServerFrontendOnly.NeedServerRestart(), nil
 }
 
@@ -2,9 +2,11 @@ package graphqlbackend
 
 import (
 	"context"
+	"strconv"
 
 	"github.com/graph-gophers/graphql-go"
 	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/audit"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/authz"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
@@ -137,8 +139,16 @@ func (*schemaResolver) SetUserIsSiteAdmin(ctx context.Context, args *struct {
 		return nil, err
 	}
 
+	target, err := db.Users.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
 	if err := db.Users.SetIsSiteAdmin(ctx, userID, args.SiteAdmin); err != nil {
 		return nil, err
 	}
+	audit.Log(ctx, audit.ActionUserSiteAdminUpdate, audit.TargetUser, strconv.Itoa(int(userID)),
+		map[string]bool{"siteAdmin": target.SiteAdmin},
+		map[string]bool{"siteAdmin": args.SiteAdmin},
+	)
 	return &EmptyResponse{}, nil
 }
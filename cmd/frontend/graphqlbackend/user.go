@@ -266,7 +266,7 @@ func (r *UserResolver) SurveyResponses(ctx context.Context) ([]*surveyResponseRe
 }
 
 func (r *UserResolver) ViewerCanAdminister(ctx context.Context) (bool, error) {
-	if err := backend.CheckSiteAdminOrSameUser(ctx, r.user.ID); err == backend.ErrNotAuthenticated || err == backend.ErrMustBeSiteAdmin {
+	if err := backend.CheckSiteAdminOrSameUser(ctx, r.user.ID); err == backend.ErrNotAuthenticated || err == backend.ErrMustBeSiteAdmin || backend.IsAccessTokenScopeError(err) {
 		return false, nil
 	} else if err != nil {
 		return false, err
@@ -287,6 +287,13 @@ func (r *UserResolver) URLForSiteAdminBilling(ctx context.Context) (*string, err
 
 func (r *UserResolver) NamespaceName() string { return r.user.Username }
 
+func (r *UserResolver) PermissionsSyncHistory(ctx context.Context, args *PermissionsSyncHistoryArgs) ([]PermissionsSyncHistoryEntryResolver, error) {
+	return EnterpriseResolvers.authzResolver.UserPermissionsSyncHistory(ctx, &UserPermissionsSyncHistoryArgs{
+		UserID:                     r.ID(),
+		PermissionsSyncHistoryArgs: args,
+	})
+}
+
 func (r *schemaResolver) UpdatePassword(ctx context.Context, args *struct {
 	OldPassword string
 	NewPassword string
@@ -0,0 +1,337 @@
+package graphqlbackend
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/graph-gophers/graphql-go/relay"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/audit"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/auth/userpasswd"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+)
+
+func (r *UserResolver) MFA(ctx context.Context) (*userMFAResolver, error) {
+	// 🚨 SECURITY: Only the user and site admins may view the user's two-factor authentication
+	// settings.
+	if err := backend.CheckSiteAdminOrSameUser(ctx, r.user.ID); err != nil {
+		return nil, err
+	}
+	return &userMFAResolver{user: r}, nil
+}
+
+type userMFAResolver struct {
+	user *UserResolver
+}
+
+func (r *userMFAResolver) Available() bool { return userpasswd.MFAAvailable() }
+
+func (r *userMFAResolver) Required() bool { return userpasswd.MFARequired(r.user.user) }
+
+func (r *userMFAResolver) TOTPEnabled(ctx context.Context) (bool, error) {
+	t, err := db.UserMFA.GetTOTP(ctx, r.user.user.ID)
+	if err != nil {
+		return false, err
+	}
+	return t != nil && t.Enabled(), nil
+}
+
+func (r *userMFAResolver) SecurityKeys(ctx context.Context) ([]*securityKeyResolver, error) {
+	creds, err := db.UserMFA.ListWebAuthnCredentials(ctx, r.user.user.ID)
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]*securityKeyResolver, len(creds))
+	for i, c := range creds {
+		keys[i] = &securityKeyResolver{cred: c}
+	}
+	return keys, nil
+}
+
+func (r *userMFAResolver) RecoveryCodesRemaining(ctx context.Context) (int32, error) {
+	count, err := db.UserMFA.CountRecoveryCodes(ctx, r.user.user.ID)
+	return int32(count), err
+}
+
+type securityKeyResolver struct {
+	cred *db.WebAuthnCredential
+}
+
+func marshalSecurityKeyID(id int32) graphql.ID { return relay.MarshalID("SecurityKey", id) }
+
+func unmarshalSecurityKeyID(id graphql.ID) (securityKeyID int32, err error) {
+	err = relay.UnmarshalSpec(id, &securityKeyID)
+	return
+}
+
+func (r *securityKeyResolver) ID() graphql.ID { return marshalSecurityKeyID(r.cred.ID) }
+
+func (r *securityKeyResolver) Name() string { return r.cred.Name }
+
+func (r *securityKeyResolver) CreatedAt() DateTime { return DateTime{Time: r.cred.CreatedAt} }
+
+func (r *securityKeyResolver) LastUsedAt() *DateTime { return DateTimeOrNil(r.cred.LastUsedAt) }
+
+// currentUserForMFASetup returns the current user, who is setting up a second authentication
+// factor.
+//
+// 🚨 SECURITY: Second factors can only be set up by the user, with a session cookie (not an access
+// token), so that a leaked access token can't be used to take over the account's second factor.
+func currentUserForMFASetup(ctx context.Context) (*UserResolver, error) {
+	if !userpasswd.MFAAvailable() {
+		return nil, errors.New("two-factor authentication requires the builtin username-password authentication provider")
+	}
+	a := actor.FromContext(ctx)
+	if !a.IsAuthenticated() || !a.FromSessionCookie {
+		return nil, errors.New("two-factor authentication can only be set up by the signed-in user")
+	}
+	return CurrentUser(ctx)
+}
+
+// checkMFAChangeAllowed checks that the current user may remove a second factor of (or generate
+// new recovery codes for) the user.
+//
+// 🚨 SECURITY: Site admins may do so for any user (e.g., a user who lost their authenticator app).
+// Users must confirm their password, so that someone with access to a signed-in session can't
+// remove the account's second factor.
+func checkMFAChangeAllowed(ctx context.Context, userID int32, password *string) error {
+	if err := backend.CheckSiteAdminOrSameUser(ctx, userID); err != nil {
+		return err
+	}
+	if a := actor.FromContext(ctx); a.UID != userID {
+		return nil // site admin
+	}
+	if password == nil {
+		return errors.New("password is required")
+	}
+	ok, err := db.Users.IsPassword(ctx, userID, *password)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errors.New("wrong password")
+	}
+	return nil
+}
+
+type beginTOTPEnrollmentResult struct {
+	secret, url string
+}
+
+func (r *beginTOTPEnrollmentResult) Secret() string { return r.secret }
+
+func (r *beginTOTPEnrollmentResult) URL() string { return r.url }
+
+func (*schemaResolver) BeginTOTPEnrollment(ctx context.Context) (*beginTOTPEnrollmentResult, error) {
+	user, err := currentUserForMFASetup(ctx)
+	if err != nil {
+		return nil, err
+	}
+	secret, url, err := userpasswd.BeginTOTPEnrollment(ctx, user.user)
+	if err != nil {
+		return nil, err
+	}
+	return &beginTOTPEnrollmentResult{secret: secret, url: url}, nil
+}
+
+// recoveryCodesResult is the result of mutations that may generate new recovery codes.
+type recoveryCodesResult struct {
+	codes []string
+}
+
+func (r *recoveryCodesResult) RecoveryCodes() *[]string {
+	if r.codes == nil {
+		return nil
+	}
+	return &r.codes
+}
+
+// newRecoveryCodesIfFirstFactor generates recovery codes for the user if the user just set up
+// their first second factor (when wasEnabled is false).
+func newRecoveryCodesIfFirstFactor(ctx context.Context, userID int32, wasEnabled bool) (*recoveryCodesResult, error) {
+	if wasEnabled {
+		return &recoveryCodesResult{}, nil
+	}
+	codes, err := userpasswd.NewRecoveryCodes(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	audit.Log(ctx, audit.ActionUserRecoveryCodesNew, audit.TargetUser, strconv.Itoa(int(userID)), nil, nil)
+	return &recoveryCodesResult{codes: codes}, nil
+}
+
+func (*schemaResolver) ConfirmTOTPEnrollment(ctx context.Context, args *struct {
+	Code string
+}) (*recoveryCodesResult, error) {
+	user, err := currentUserForMFASetup(ctx)
+	if err != nil {
+		return nil, err
+	}
+	userID := user.user.ID
+
+	t, err := db.UserMFA.GetTOTP(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if t == nil || t.Enabled() {
+		return nil, db.ErrNoPendingTOTP
+	}
+	step, ok, err := userpasswd.ValidateTOTP(t, args.Code, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, errors.New("invalid code (check that your device's clock is correct)")
+	}
+
+	wasEnabled, err := db.UserMFA.IsEnabled(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := db.UserMFA.EnableTOTP(ctx, userID, step); err != nil {
+		return nil, err
+	}
+	audit.Log(ctx, audit.ActionUserTOTPEnable, audit.TargetUser, strconv.Itoa(int(userID)), nil, nil)
+	return newRecoveryCodesIfFirstFactor(ctx, userID, wasEnabled)
+}
+
+func (*schemaResolver) DisableTOTP(ctx context.Context, args *struct {
+	User     graphql.ID
+	Password *string
+}) (*EmptyResponse, error) {
+	userID, err := UnmarshalUserID(args.User)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkMFAChangeAllowed(ctx, userID, args.Password); err != nil {
+		return nil, err
+	}
+
+	if err := db.UserMFA.DeleteTOTP(ctx, userID); err != nil {
+		return nil, err
+	}
+	if err := userpasswd.DeleteMFAIfUnused(ctx, userID); err != nil {
+		return nil, err
+	}
+	audit.Log(ctx, audit.ActionUserTOTPDisable, audit.TargetUser, strconv.Itoa(int(userID)), nil, nil)
+	return &EmptyResponse{}, nil
+}
+
+func (*schemaResolver) GenerateRecoveryCodes(ctx context.Context, args *struct {
+	Password string
+}) (*recoveryCodesResult, error) {
+	user, err := CurrentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, errors.New("no authenticated user")
+	}
+	userID := user.user.ID
+	if err := checkMFAChangeAllowed(ctx, userID, &args.Password); err != nil {
+		return nil, err
+	}
+
+	enabled, err := db.UserMFA.IsEnabled(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !enabled {
+		return nil, errors.New("two-factor authentication is not enabled")
+	}
+	return newRecoveryCodesIfFirstFactor(ctx, userID, false)
+}
+
+func (*schemaResolver) BeginSecurityKeyRegistration(ctx context.Context) (JSONValue, error) {
+	user, err := currentUserForMFASetup(ctx)
+	if err != nil {
+		return JSONValue{}, err
+	}
+	options, err := userpasswd.BeginSecurityKeyRegistration(ctx, user.user)
+	if err != nil {
+		return JSONValue{}, err
+	}
+	return JSONValue{Value: options}, nil
+}
+
+type finishSecurityKeyRegistrationResult struct {
+	recoveryCodesResult
+	securityKey *securityKeyResolver
+}
+
+func (r *finishSecurityKeyRegistrationResult) SecurityKey() *securityKeyResolver {
+	return r.securityKey
+}
+
+func (*schemaResolver) FinishSecurityKeyRegistration(ctx context.Context, args *struct {
+	Name     string
+	Response JSONValue
+}) (*finishSecurityKeyRegistrationResult, error) {
+	user, err := currentUserForMFASetup(ctx)
+	if err != nil {
+		return nil, err
+	}
+	userID := user.user.ID
+
+	name := strings.TrimSpace(args.Name)
+	if name == "" {
+		return nil, errors.New("a name for the security key is required")
+	}
+	b, err := json.Marshal(args.Response.Value)
+	if err != nil {
+		return nil, err
+	}
+	c, err := userpasswd.FinishSecurityKeyRegistration(ctx, user.user, b)
+	if err != nil {
+		return nil, err
+	}
+	c.Name = name
+
+	wasEnabled, err := db.UserMFA.IsEnabled(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := db.UserMFA.CreateWebAuthnCredential(ctx, c); err != nil {
+		return nil, err
+	}
+	audit.Log(ctx, audit.ActionUserSecurityKeyCreate, audit.TargetUser, strconv.Itoa(int(userID)), nil, map[string]string{"name": name})
+
+	codes, err := newRecoveryCodesIfFirstFactor(ctx, userID, wasEnabled)
+	if err != nil {
+		return nil, err
+	}
+	return &finishSecurityKeyRegistrationResult{recoveryCodesResult: *codes, securityKey: &securityKeyResolver{cred: c}}, nil
+}
+
+func (*schemaResolver) DeleteSecurityKey(ctx context.Context, args *struct {
+	User        graphql.ID
+	SecurityKey graphql.ID
+	Password    *string
+}) (*EmptyResponse, error) {
+	userID, err := UnmarshalUserID(args.User)
+	if err != nil {
+		return nil, err
+	}
+	id, err := unmarshalSecurityKeyID(args.SecurityKey)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkMFAChangeAllowed(ctx, userID, args.Password); err != nil {
+		return nil, err
+	}
+
+	if err := db.UserMFA.DeleteWebAuthnCredential(ctx, userID, id); err != nil {
+		return nil, err
+	}
+	if err := userpasswd.DeleteMFAIfUnused(ctx, userID); err != nil {
+		return nil, err
+	}
+	audit.Log(ctx, audit.ActionUserSecurityKeyDelete, audit.TargetUser, strconv.Itoa(int(userID)), nil, nil)
+	return &EmptyResponse{}, nil
+}
@@ -0,0 +1,114 @@
+package graphqlbackend
+
+import (
+	"context"
+	"testing"
+
+	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/audit"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/secret"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+// 🚨 SECURITY: This tests that only the user (with their password) and site admins can remove a
+// user's second factor.
+func TestMutation_DisableTOTP(t *testing.T) {
+	const uid1GQLID = "VXNlcjox"
+	password := func(s string) *string { return &s }
+
+	setup := func(t *testing.T) (deleted *bool) {
+		resetMocks()
+		deleted = new(bool)
+		db.Mocks.Users.GetByID = func(ctx context.Context, id int32) (*types.User, error) {
+			return &types.User{ID: id, Username: "u", SiteAdmin: id == 2}, nil
+		}
+		db.Mocks.Users.GetByCurrentAuthUser = func(ctx context.Context) (*types.User, error) {
+			a := actor.FromContext(ctx)
+			return &types.User{ID: a.UID, SiteAdmin: a.UID == 2}, nil
+		}
+		db.Mocks.Users.IsPassword = func(ctx context.Context, id int32, password string) (bool, error) {
+			return password == "p", nil
+		}
+		db.Mocks.UserMFA.DeleteTOTP = func(userID int32) error {
+			if userID != 1 {
+				t.Errorf("got user ID %d, want 1", userID)
+			}
+			*deleted = true
+			return nil
+		}
+		db.Mocks.UserMFA.IsEnabled = func(userID int32) (bool, error) { return true, nil }
+		db.Mocks.AuditLogs.Create = func(e *db.AuditLogEntry) error {
+			if e.Action != audit.ActionUserTOTPDisable || e.TargetID != "1" {
+				t.Errorf("unexpected audit log entry %+v", e)
+			}
+			return nil
+		}
+		return deleted
+	}
+
+	tests := map[string]struct {
+		actor       *actor.Actor
+		password    *string
+		wantAllowed bool
+	}{
+		"user with password":          {&actor.Actor{UID: 1}, password("p"), true},
+		"user with wrong password":    {&actor.Actor{UID: 1}, password("x"), false},
+		"user without password":       {&actor.Actor{UID: 1}, nil, false},
+		"site admin without password": {&actor.Actor{UID: 2}, nil, true},
+		"other user with password":    {&actor.Actor{UID: 3}, password("p"), false},
+		"anonymous":                   {&actor.Actor{}, password("p"), false},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			deleted := setup(t)
+			ctx := actor.WithActor(context.Background(), test.actor)
+			_, err := (&schemaResolver{}).DisableTOTP(ctx, &struct {
+				User     graphql.ID
+				Password *string
+			}{User: uid1GQLID, Password: test.password})
+			if allowed := err == nil; allowed != test.wantAllowed {
+				t.Errorf("got error %v, want allowed %v", err, test.wantAllowed)
+			}
+			if *deleted != test.wantAllowed {
+				t.Errorf("got deleted %v, want %v", *deleted, test.wantAllowed)
+			}
+		})
+	}
+}
+
+// 🚨 SECURITY: This tests that second factors can't be set up with an access token.
+func TestMutation_BeginTOTPEnrollment(t *testing.T) {
+	resetMocks()
+	conf.Mock(&conf.Unified{SiteConfiguration: schema.SiteConfiguration{
+		AuthProviders: []schema.AuthProviders{{Builtin: &schema.BuiltinAuthProvider{Type: "builtin"}}},
+	}})
+	defer conf.Mock(nil)
+	db.Mocks.Users.GetByCurrentAuthUser = func(ctx context.Context) (*types.User, error) {
+		return &types.User{ID: actor.FromContext(ctx).UID, Username: "u"}, nil
+	}
+	var created bool
+	db.Mocks.UserMFA.CreatePendingTOTP = func(userID int32, encryptedSecret string) error {
+		created = true
+		return nil
+	}
+	secret.MockKey("test secret key")
+	defer secret.MockKey("")
+
+	ctx := actor.WithActor(context.Background(), &actor.Actor{UID: 1})
+	if _, err := (&schemaResolver{}).BeginTOTPEnrollment(ctx); err == nil || created {
+		t.Error("enrollment with an access token: want error")
+	}
+
+	ctx = actor.WithActor(context.Background(), &actor.Actor{UID: 1, FromSessionCookie: true})
+	result, err := (&schemaResolver{}).BeginTOTPEnrollment(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !created || result.Secret() == "" || result.URL() == "" {
+		t.Errorf("got result %+v (created %v), want a pending enrollment", result, created)
+	}
+}
@@ -3,7 +3,13 @@ package graphqlbackend
 import (
 	"context"
 	"errors"
+	"strconv"
 
+	"github.com/graph-gophers/graphql-go"
+	"github.com/graph-gophers/graphql-go/relay"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/audit"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
 	"github.com/sourcegraph/sourcegraph/internal/actor"
 	"github.com/sourcegraph/sourcegraph/internal/conf"
 )
@@ -34,3 +40,89 @@ type sessionResolver struct {
 }
 
 func (r *sessionResolver) CanSignOut() bool { return r.canSignOut }
+
+func (r *UserResolver) Sessions(ctx context.Context) ([]*userSessionResolver, error) {
+	// 🚨 SECURITY: Only the user and site admins can view the user's sessions.
+	if err := backend.CheckSiteAdminOrSameUser(ctx, r.user.ID); err != nil {
+		return nil, err
+	}
+
+	sessions, err := db.UserSessions.ListByUser(ctx, r.user.ID)
+	if err != nil {
+		return nil, err
+	}
+	currentID := actor.FromContext(ctx).SessionID
+	resolvers := make([]*userSessionResolver, len(sessions))
+	for i, s := range sessions {
+		resolvers[i] = &userSessionResolver{session: s, current: s.ID == currentID}
+	}
+	return resolvers, nil
+}
+
+type userSessionResolver struct {
+	session *db.UserSession
+	current bool
+}
+
+func marshalUserSessionID(id int64) graphql.ID { return relay.MarshalID("UserSession", id) }
+
+func unmarshalUserSessionID(id graphql.ID) (sessionID int64, err error) {
+	err = relay.UnmarshalSpec(id, &sessionID)
+	return
+}
+
+func (r *userSessionResolver) ID() graphql.ID { return marshalUserSessionID(r.session.ID) }
+
+func (r *userSessionResolver) CreatedAt() DateTime { return DateTime{Time: r.session.CreatedAt} }
+
+func (r *userSessionResolver) LastActiveAt() DateTime { return DateTime{Time: r.session.LastActiveAt} }
+
+func (r *userSessionResolver) ExpiresAt() DateTime { return DateTime{Time: r.session.ExpiresAt} }
+
+func (r *userSessionResolver) IPAddress() *string { return nullString(r.session.IP) }
+
+func (r *userSessionResolver) UserAgent() *string { return nullString(r.session.UserAgent) }
+
+func (r *userSessionResolver) Current() bool { return r.current }
+
+func (r *schemaResolver) RevokeSession(ctx context.Context, args *struct {
+	Session graphql.ID
+}) (*EmptyResponse, error) {
+	sessionID, err := unmarshalUserSessionID(args.Session)
+	if err != nil {
+		return nil, err
+	}
+	session, err := db.UserSessions.GetByID(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 🚨 SECURITY: Only site admins and the user can revoke a user's session.
+	if err := backend.CheckSiteAdminOrSameUser(ctx, session.UserID); err != nil {
+		return nil, err
+	}
+	if err := db.UserSessions.Delete(ctx, session.UserID, session.ID); err != nil {
+		return nil, err
+	}
+	audit.Log(ctx, audit.ActionUserSessionRevoke, audit.TargetUser, strconv.Itoa(int(session.UserID)), nil, nil)
+	return &EmptyResponse{}, nil
+}
+
+func (r *schemaResolver) RevokeAllSessions(ctx context.Context, args *struct {
+	User graphql.ID
+}) (*EmptyResponse, error) {
+	userID, err := UnmarshalUserID(args.User)
+	if err != nil {
+		return nil, err
+	}
+
+	// 🚨 SECURITY: Only site admins and the user can revoke a user's sessions.
+	if err := backend.CheckSiteAdminOrSameUser(ctx, userID); err != nil {
+		return nil, err
+	}
+	if err := db.UserSessions.DeleteAllByUser(ctx, userID, actor.FromContext(ctx).SessionID); err != nil {
+		return nil, err
+	}
+	audit.Log(ctx, audit.ActionUserSessionRevokeAll, audit.TargetUser, strconv.Itoa(int(userID)), nil, nil)
+	return &EmptyResponse{}, nil
+}
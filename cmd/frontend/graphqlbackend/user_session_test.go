@@ -0,0 +1,153 @@
+package graphqlbackend
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+)
+
+func TestUser_Sessions(t *testing.T) {
+	resetMocks()
+	db.Mocks.Users.GetByID = func(ctx context.Context, id int32) (*types.User, error) {
+		return &types.User{ID: id}, nil
+	}
+	db.Mocks.Users.GetByCurrentAuthUser = func(ctx context.Context) (*types.User, error) {
+		a := actor.FromContext(ctx)
+		return &types.User{ID: a.UID, SiteAdmin: a.UID == 2}, nil
+	}
+	db.Mocks.UserSessions.ListByUser = func(userID int32) ([]*db.UserSession, error) {
+		now := time.Now()
+		return []*db.UserSession{
+			{ID: 10, UserID: userID, IP: "192.0.2.1", UserAgent: "Firefox", CreatedAt: now, LastActiveAt: now, ExpiresAt: now},
+			{ID: 11, UserID: userID, CreatedAt: now, LastActiveAt: now, ExpiresAt: now},
+		}, nil
+	}
+
+	user := &UserResolver{user: &types.User{ID: 1}}
+
+	// 🚨 SECURITY: Other users can't view the user's sessions.
+	ctx := actor.WithActor(context.Background(), &actor.Actor{UID: 3})
+	if _, err := user.Sessions(ctx); err == nil {
+		t.Error("other user: want error")
+	}
+
+	ctx = actor.WithActor(context.Background(), &actor.Actor{UID: 1, FromSessionCookie: true, SessionID: 11})
+	sessions, err := user.Sessions(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sessions) != 2 {
+		t.Fatalf("got %d sessions, want 2", len(sessions))
+	}
+	if sessions[0].Current() || !sessions[1].Current() {
+		t.Error("want only the second session to be the current session")
+	}
+	if ip := sessions[0].IPAddress(); ip == nil || *ip != "192.0.2.1" {
+		t.Errorf("got IP address %v, want 192.0.2.1", ip)
+	}
+	if ua := sessions[1].UserAgent(); ua != nil {
+		t.Errorf("got user agent %q, want nil", *ua)
+	}
+
+	ctx = actor.WithActor(context.Background(), &actor.Actor{UID: 2})
+	if _, err := user.Sessions(ctx); err != nil {
+		t.Errorf("site admin: %v", err)
+	}
+}
+
+// 🚨 SECURITY: This tests that only the user and site admins can revoke a user's sessions.
+func TestMutation_RevokeSession(t *testing.T) {
+	setup := func() (revoked *bool) {
+		resetMocks()
+		revoked = new(bool)
+		db.Mocks.Users.GetByID = func(ctx context.Context, id int32) (*types.User, error) {
+			return &types.User{ID: id}, nil
+		}
+		db.Mocks.Users.GetByCurrentAuthUser = func(ctx context.Context) (*types.User, error) {
+			a := actor.FromContext(ctx)
+			return &types.User{ID: a.UID, SiteAdmin: a.UID == 2}, nil
+		}
+		db.Mocks.UserSessions.GetByID = func(id int64) (*db.UserSession, error) {
+			return &db.UserSession{ID: id, UserID: 1}, nil
+		}
+		db.Mocks.UserSessions.Delete = func(userID int32, id int64) error {
+			if userID != 1 || id != 10 {
+				t.Errorf("got user ID %d and session ID %d, want 1 and 10", userID, id)
+			}
+			*revoked = true
+			return nil
+		}
+		db.Mocks.AuditLogs.Create = func(e *db.AuditLogEntry) error { return nil }
+		return revoked
+	}
+
+	tests := map[string]struct {
+		actor       *actor.Actor
+		wantAllowed bool
+	}{
+		"user":       {&actor.Actor{UID: 1}, true},
+		"site admin": {&actor.Actor{UID: 2}, true},
+		"other user": {&actor.Actor{UID: 3}, false},
+		"anonymous":  {&actor.Actor{}, false},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			revoked := setup()
+			ctx := actor.WithActor(context.Background(), test.actor)
+			_, err := (&schemaResolver{}).RevokeSession(ctx, &struct {
+				Session graphql.ID
+			}{Session: marshalUserSessionID(10)})
+			if allowed := err == nil; allowed != test.wantAllowed {
+				t.Errorf("got error %v, want allowed %v", err, test.wantAllowed)
+			}
+			if *revoked != test.wantAllowed {
+				t.Errorf("got revoked %v, want %v", *revoked, test.wantAllowed)
+			}
+		})
+	}
+}
+
+func TestMutation_RevokeAllSessions(t *testing.T) {
+	resetMocks()
+	db.Mocks.Users.GetByID = func(ctx context.Context, id int32) (*types.User, error) {
+		return &types.User{ID: id}, nil
+	}
+	db.Mocks.Users.GetByCurrentAuthUser = func(ctx context.Context) (*types.User, error) {
+		a := actor.FromContext(ctx)
+		return &types.User{ID: a.UID, SiteAdmin: a.UID == 2}, nil
+	}
+	var exceptIDs []int64
+	db.Mocks.UserSessions.DeleteAllByUser = func(userID int32, exceptID int64) error {
+		exceptIDs = append(exceptIDs, exceptID)
+		return nil
+	}
+	db.Mocks.AuditLogs.Create = func(e *db.AuditLogEntry) error { return nil }
+
+	args := &struct {
+		User graphql.ID
+	}{User: MarshalUserID(1)}
+
+	// 🚨 SECURITY: Other users can't revoke the user's sessions.
+	ctx := actor.WithActor(context.Background(), &actor.Actor{UID: 3})
+	if _, err := (&schemaResolver{}).RevokeAllSessions(ctx, args); err == nil {
+		t.Error("other user: want error")
+	}
+
+	// The viewer's current session is kept.
+	ctx = actor.WithActor(context.Background(), &actor.Actor{UID: 1, FromSessionCookie: true, SessionID: 10})
+	if _, err := (&schemaResolver{}).RevokeAllSessions(ctx, args); err != nil {
+		t.Fatal(err)
+	}
+	ctx = actor.WithActor(context.Background(), &actor.Actor{UID: 2})
+	if _, err := (&schemaResolver{}).RevokeAllSessions(ctx, args); err != nil {
+		t.Fatal(err)
+	}
+	if len(exceptIDs) != 2 || exceptIDs[0] != 10 || exceptIDs[1] != 0 {
+		t.Errorf("got revoked sessions except %v, want [10 0]", exceptIDs)
+	}
+}
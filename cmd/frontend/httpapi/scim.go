@@ -0,0 +1,11 @@
+package httpapi
+
+import (
+	"net/http"
+)
+
+// SCIMHandler serves the SCIM provisioning API under /.api/scim/v2. Requests are routed to it with
+// paths relative to that prefix, e.g. "/Users".
+//
+// Set by enterprise frontend
+var SCIMHandler http.Handler
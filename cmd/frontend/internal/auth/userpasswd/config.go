@@ -1,7 +1,9 @@
 package userpasswd
 
 import (
+	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/inconshreveable/log15"
 	"github.com/sourcegraph/sourcegraph/internal/conf"
@@ -52,6 +54,11 @@ func validateConfig(c conf.Unified) (problems conf.Problems) {
 	for _, p := range c.AuthProviders {
 		if p.Builtin != nil {
 			builtinAuthProviders++
+			if l := p.Builtin.Lockout; l != nil && l.Duration != "" {
+				if d, err := time.ParseDuration(l.Duration); err != nil || d <= 0 {
+					problems = append(problems, conf.NewSiteProblem(fmt.Sprintf("builtin auth provider lockout duration %q is not a valid positive duration", l.Duration)))
+				}
+			}
 		}
 	}
 	if builtinAuthProviders >= 2 {
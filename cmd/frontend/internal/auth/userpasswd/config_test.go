@@ -29,6 +29,22 @@ func TestValidateCustom(t *testing.T) {
 			}},
 			wantProblems: conf.NewSiteProblems("at most 1"),
 		},
+		"valid lockout duration": {
+			input: conf.Unified{SiteConfiguration: schema.SiteConfiguration{
+				AuthProviders: []schema.AuthProviders{
+					{Builtin: &schema.BuiltinAuthProvider{Type: "builtin", Lockout: &schema.BuiltinAuthLockout{Duration: "1h"}}},
+				},
+			}},
+			wantProblems: nil,
+		},
+		"invalid lockout duration": {
+			input: conf.Unified{SiteConfiguration: schema.SiteConfiguration{
+				AuthProviders: []schema.AuthProviders{
+					{Builtin: &schema.BuiltinAuthProvider{Type: "builtin", Lockout: &schema.BuiltinAuthLockout{Duration: "1 hour"}}},
+				},
+			}},
+			wantProblems: conf.NewSiteProblems("not a valid positive duration"),
+		},
 	}
 	for name, test := range tests {
 		t.Run(name, func(t *testing.T) {
@@ -5,9 +5,12 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/inconshreveable/log15"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/audit"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/authz"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
@@ -18,6 +21,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/actor"
 	"github.com/sourcegraph/sourcegraph/internal/conf"
 	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	"github.com/sourcegraph/sourcegraph/internal/httputil"
 	"github.com/sourcegraph/sourcegraph/internal/hubspot/hubspotutil"
 )
 
@@ -175,11 +179,8 @@ func handleSignUp(w http.ResponseWriter, r *http.Request, failIfNewUserIsNotInit
 		}
 	}
 
-	// Write the session cookie
-	actor := &actor.Actor{UID: usr.ID}
-	if err := session.SetActor(w, r, actor, 0); err != nil {
-		httpLogAndError(w, "Could not create new user session", http.StatusInternalServerError)
-	}
+	// 🚨 SECURITY: The new user may have to set up a second factor before signing in.
+	completeSignIn(w, r, usr, secondFactor{})
 
 	// Track user data
 	if r.UserAgent() != "Sourcegraph e2etest-bot" {
@@ -194,8 +195,38 @@ func getByEmailOrUsername(ctx context.Context, emailOrUsername string) (*types.U
 	return db.Users.GetByUsername(ctx, emailOrUsername)
 }
 
-// HandleSignIn accepts a POST containing username-password credentials and authenticates the
-// current session if the credentials are valid.
+// signInRequest is the body of a sign-in request. When the user has a second authentication factor,
+// the client first sends only the credentials, and then sends them again along with the second
+// factor (whose options are in the first response).
+type signInRequest struct {
+	credentials
+	secondFactor
+}
+
+// signInResponse is the body of the response to a sign-in request that succeeded or that requires
+// another step.
+type signInResponse struct {
+	// MFARequired is set if the user must also provide a second factor.
+	MFARequired bool `json:"mfaRequired,omitempty"`
+	// TOTP is set if the user can provide a code from their authenticator app.
+	TOTP bool `json:"totp,omitempty"`
+	// WebAuthn is the options for signing in with one of the user's security keys, if any.
+	WebAuthn interface{} `json:"webAuthn,omitempty"`
+
+	// MFAEnrollmentRequired is set if the user must set up an authenticator app (because the site
+	// requires a second factor) and then provide a code from it.
+	MFAEnrollmentRequired bool   `json:"mfaEnrollmentRequired,omitempty"`
+	TOTPSecret            string `json:"totpSecret,omitempty"`
+	TOTPURL               string `json:"totpURL,omitempty"`
+
+	// RecoveryCodes are the user's new recovery codes, after the user set up an authenticator app
+	// while signing in.
+	RecoveryCodes []string `json:"recoveryCodes,omitempty"`
+}
+
+// HandleSignIn accepts a POST containing username-password credentials (and a second
+// authentication factor, if the user has one) and authenticates the current session if they are
+// valid.
 func HandleSignIn(w http.ResponseWriter, r *http.Request) {
 	if handleEnabledCheck(w) {
 		return
@@ -207,20 +238,28 @@ func HandleSignIn(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, fmt.Sprintf("Unsupported method %s", r.Method), http.StatusBadRequest)
 		return
 	}
-	var creds credentials
-	if err := json.NewDecoder(r.Body).Decode(&creds); err != nil {
+	var req signInRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		http.Error(w, "Could not decode request body", http.StatusBadRequest)
 		return
 	}
 
+	// 🚨 SECURITY: Limit the rate of attempts to guess passwords and codes.
+	if !signInRateLimiter.Allow(r) {
+		httpLogAndError(w, "Too many sign-in attempts. Try again later.", http.StatusTooManyRequests, "clientIP", httputil.ClientIP(r))
+		return
+	}
+
 	// Validate user. Allow login by both email and username (for convenience).
-	usr, err := getByEmailOrUsername(ctx, creds.Email)
+	usr, err := getByEmailOrUsername(ctx, req.Email)
 	if err != nil {
 		httpLogAndError(w, "Authentication failed", http.StatusUnauthorized, "err", err)
 		return
 	}
-	// 🚨 SECURITY: check password
-	correct, err := db.Users.IsPassword(ctx, usr.ID, creds.Password)
+
+	// 🚨 SECURITY: check password. Wrong passwords are only rate limited for each IP address and
+	// don't count toward the lockout of the account, so that nobody can lock other users out.
+	correct, err := db.Users.IsPassword(ctx, usr.ID, req.Password)
 	if err != nil {
 		httpLogAndError(w, "Error checking password", http.StatusInternalServerError, "err", err)
 		return
@@ -229,6 +268,82 @@ func HandleSignIn(w http.ResponseWriter, r *http.Request) {
 		httpLogAndError(w, "Authentication failed", http.StatusUnauthorized)
 		return
 	}
+
+	// 🚨 SECURITY: Refuse to sign in to locked accounts, even with the correct password and second
+	// factor.
+	lockedUntil, err := db.Users.SignInLockedUntil(ctx, usr.ID)
+	if err != nil {
+		httpLogAndError(w, "Error checking account lockout", http.StatusInternalServerError, "err", err)
+		return
+	}
+	if lockedUntil != nil {
+		httpLogAndError(w, accountLockedMessage, http.StatusTooManyRequests, "userID", usr.ID, "lockedUntil", *lockedUntil)
+		return
+	}
+
+	completeSignIn(w, r, usr, req.secondFactor)
+}
+
+// completeSignIn creates a session for the user, who has just proven that they know their password
+// (or has just set it), if the user doesn't need a second factor or f is valid. Otherwise it
+// responds with the second factors the user can sign in with (or with a new authenticator app
+// secret, if the user must set one up first), or refuses the invalid second factor.
+//
+// 🚨 SECURITY: Every path that creates a session for a user of the builtin auth provider must go
+// through completeSignIn, so that second factors can't be bypassed.
+func completeSignIn(w http.ResponseWriter, r *http.Request, usr *types.User, f secondFactor) {
+	ctx := r.Context()
+
+	// 🚨 SECURITY: check second factor
+	pc, _ := getProviderConfig()
+	var resp *signInResponse
+	mfaEnabled, err := db.UserMFA.IsEnabled(ctx, usr.ID)
+	if err != nil {
+		httpLogAndError(w, "Error checking two-factor authentication", http.StatusInternalServerError, "err", err)
+		return
+	}
+	switch {
+	case mfaEnabled && f.empty():
+		resp, err := mfaChallenge(ctx, usr)
+		if err != nil {
+			httpLogAndError(w, "Error starting two-factor authentication", http.StatusInternalServerError, "err", err)
+			return
+		}
+		writeSignInResponse(w, resp)
+		return
+
+	case mfaEnabled:
+		ok, err := verifySecondFactor(ctx, usr, f)
+		if err != nil {
+			httpLogAndError(w, "Error checking two-factor authentication", http.StatusInternalServerError, "err", err)
+			return
+		}
+		if !ok {
+			handleFailedSignIn(w, r, usr)
+			return
+		}
+
+	case mfaRequired(pc, usr):
+		// The user must set up an authenticator app before signing in.
+		resp, err = enrollTOTP(ctx, usr, f.TOTPCode)
+		if err != nil {
+			httpLogAndError(w, "Error setting up two-factor authentication", http.StatusInternalServerError, "err", err)
+			return
+		}
+		if resp == nil {
+			handleFailedSignIn(w, r, usr)
+			return
+		}
+		if resp.MFAEnrollmentRequired {
+			writeSignInResponse(w, resp)
+			return
+		}
+	}
+
+	if err := db.Users.ResetFailedSignIns(ctx, usr.ID); err != nil {
+		log15.Warn("Failed to reset failed sign-in attempts.", "userID", usr.ID, "error", err)
+	}
+
 	actor := &actor.Actor{UID: usr.ID}
 
 	// Write the session cookie
@@ -236,6 +351,73 @@ func HandleSignIn(w http.ResponseWriter, r *http.Request) {
 		httpLogAndError(w, "Could not create new user session", http.StatusInternalServerError)
 		return
 	}
+	if resp != nil {
+		writeSignInResponse(w, resp)
+	}
+}
+
+const accountLockedMessage = "Your account is locked because of too many failed sign-in attempts. Try again later or reset your password."
+
+// handleFailedSignIn responds to a sign-in attempt with the correct password but a wrong second
+// factor, and locks the user's account if there were too many consecutive failed attempts.
+func handleFailedSignIn(w http.ResponseWriter, r *http.Request, usr *types.User) {
+	pc, _ := getProviderConfig()
+	if failedAttempts, duration := lockoutConfig(pc); failedAttempts > 0 {
+		lockedUntil, err := db.Users.RecordFailedSignIn(r.Context(), usr.ID, failedAttempts, duration)
+		if err != nil {
+			httpLogAndError(w, "Error recording failed sign-in attempt", http.StatusInternalServerError, "err", err)
+			return
+		}
+		if lockedUntil != nil {
+			audit.Log(r.Context(), audit.ActionUserLock, audit.TargetUser, strconv.Itoa(int(usr.ID)), nil, map[string]time.Time{"lockedUntil": *lockedUntil})
+			httpLogAndError(w, accountLockedMessage, http.StatusTooManyRequests, "userID", usr.ID, "lockedUntil", *lockedUntil)
+			return
+		}
+	}
+	httpLogAndError(w, "Authentication failed", http.StatusUnauthorized)
+}
+
+// enrollTOTP sets up an authenticator app for a user who must use a second factor but has none.
+// If code is empty, it starts the enrollment and returns the secret to add to the app. Otherwise,
+// it completes the enrollment if the code from the app is valid and returns the user's new
+// recovery codes, or returns nil if the code is invalid.
+func enrollTOTP(ctx context.Context, usr *types.User, code string) (*signInResponse, error) {
+	if code == "" {
+		secret, url, err := BeginTOTPEnrollment(ctx, usr)
+		if err != nil {
+			return nil, err
+		}
+		return &signInResponse{
+			MFAEnrollmentRequired: true,
+			TOTPSecret:            secret,
+			TOTPURL:               url,
+		}, nil
+	}
+
+	t, err := db.UserMFA.GetTOTP(ctx, usr.ID)
+	if err != nil || t == nil || t.Enabled() {
+		return nil, err
+	}
+	step, ok, err := ValidateTOTP(t, code, time.Now())
+	if err != nil || !ok {
+		return nil, err
+	}
+	if err := db.UserMFA.EnableTOTP(ctx, usr.ID, step); err != nil {
+		return nil, err
+	}
+	audit.Log(ctx, audit.ActionUserTOTPEnable, audit.TargetUser, strconv.Itoa(int(usr.ID)), nil, nil)
+	codes, err := NewRecoveryCodes(ctx, usr.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &signInResponse{RecoveryCodes: codes}, nil
+}
+
+func writeSignInResponse(w http.ResponseWriter, resp *signInResponse) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		log15.Error("Failed to write sign-in response.", "error", err)
+	}
 }
 
 func httpLogAndError(w http.ResponseWriter, msg string, code int, errArgs ...interface{}) {
@@ -0,0 +1,216 @@
+package userpasswd
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang/groupcache/lru"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/httputil"
+	"github.com/sourcegraph/sourcegraph/schema"
+	"golang.org/x/time/rate"
+)
+
+// MFAAvailable reports whether users can set up two-factor authentication. Second factors are only
+// used when signing in with a password, so this requires the builtin auth provider.
+func MFAAvailable() bool {
+	pc, multiple := getProviderConfig()
+	return pc != nil && !multiple
+}
+
+// MFARequired reports whether the site configuration requires the user to sign in with a second
+// authentication factor.
+func MFARequired(user *types.User) bool {
+	pc, _ := getProviderConfig()
+	return mfaRequired(pc, user)
+}
+
+func mfaRequired(pc *schema.BuiltinAuthProvider, user *types.User) bool {
+	if pc == nil {
+		return false
+	}
+	switch pc.RequireMFA {
+	case "everyone":
+		return true
+	case "siteAdmins":
+		return user.SiteAdmin
+	}
+	return false
+}
+
+// numRecoveryCodes is the number of recovery codes generated for a user.
+const numRecoveryCodes = 10
+
+var recoveryCodeEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// NewRecoveryCodes generates new recovery codes for the user, replacing any previous ones, and
+// returns them. They are only stored hashed, so they can't be shown to the user again.
+func NewRecoveryCodes(ctx context.Context, userID int32) ([]string, error) {
+	codes := make([]string, numRecoveryCodes)
+	for i := range codes {
+		b := make([]byte, 10)
+		if _, err := rand.Read(b); err != nil {
+			return nil, err
+		}
+		s := strings.ToLower(recoveryCodeEncoding.EncodeToString(b))
+		codes[i] = s[:8] + "-" + s[8:]
+	}
+	stored := make([]string, len(codes))
+	for i, code := range codes {
+		stored[i] = normalizeRecoveryCode(code)
+	}
+	if err := db.UserMFA.SetRecoveryCodes(ctx, userID, stored); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// normalizeRecoveryCode removes the formatting of a recovery code that users may (or may not) type.
+func normalizeRecoveryCode(code string) string {
+	return strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+}
+
+// DeleteMFAIfUnused deletes the user's recovery codes if the user has no second authentication
+// factor left (after removing one).
+func DeleteMFAIfUnused(ctx context.Context, userID int32) error {
+	enabled, err := db.UserMFA.IsEnabled(ctx, userID)
+	if err != nil || enabled {
+		return err
+	}
+	return db.UserMFA.SetRecoveryCodes(ctx, userID, nil)
+}
+
+// secondFactor is the second authentication factor a user provides when signing in. At most one
+// of its fields is set.
+type secondFactor struct {
+	TOTPCode     string `json:"totpCode"`
+	RecoveryCode string `json:"recoveryCode"`
+
+	// WebAuthn is the PublicKeyCredential returned by navigator.credentials.get.
+	WebAuthn *json.RawMessage `json:"webAuthn"`
+}
+
+func (f secondFactor) empty() bool {
+	return f.TOTPCode == "" && f.RecoveryCode == "" && f.WebAuthn == nil
+}
+
+// verifySecondFactor reports whether the second factor is valid for the user, who has MFA
+// enabled. Valid codes and assertions are recorded so that they can't be reused.
+//
+// 🚨 SECURITY: Any change to this function could allow users to sign in without their second
+// factor. Be careful.
+func verifySecondFactor(ctx context.Context, user *types.User, f secondFactor) (bool, error) {
+	switch {
+	case f.TOTPCode != "":
+		t, err := db.UserMFA.GetTOTP(ctx, user.ID)
+		if err != nil || t == nil || !t.Enabled() {
+			return false, err
+		}
+		step, ok, err := ValidateTOTP(t, f.TOTPCode, time.Now())
+		if err != nil || !ok {
+			return false, err
+		}
+		// 🚨 SECURITY: Each code may only be used once.
+		return db.UserMFA.UseTOTPStep(ctx, user.ID, step)
+
+	case f.RecoveryCode != "":
+		return db.UserMFA.UseRecoveryCode(ctx, user.ID, normalizeRecoveryCode(f.RecoveryCode))
+
+	case f.WebAuthn != nil:
+		return verifySecurityKey(ctx, user, *f.WebAuthn)
+	}
+	return false, nil
+}
+
+// mfaChallenge describes the second factors the user can sign in with.
+func mfaChallenge(ctx context.Context, user *types.User) (*signInResponse, error) {
+	resp := &signInResponse{MFARequired: true}
+	t, err := db.UserMFA.GetTOTP(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	resp.TOTP = t != nil && t.Enabled()
+
+	creds, err := db.UserMFA.ListWebAuthnCredentials(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if len(creds) > 0 {
+		resp.WebAuthn, err = beginSecurityKeySignIn(user, creds)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return resp, nil
+}
+
+const (
+	defaultLockoutFailedAttempts = 10
+	defaultLockoutDuration       = 15 * time.Minute
+)
+
+// lockoutConfig returns the number of consecutive failed sign-in attempts after which accounts are
+// locked (or 0 if accounts are never locked) and how long they stay locked.
+func lockoutConfig(pc *schema.BuiltinAuthProvider) (failedAttempts int, duration time.Duration) {
+	failedAttempts, duration = defaultLockoutFailedAttempts, defaultLockoutDuration
+	if pc == nil || pc.Lockout == nil {
+		return failedAttempts, duration
+	}
+	if pc.Lockout.FailedAttempts < 0 {
+		return 0, 0
+	}
+	if pc.Lockout.FailedAttempts > 0 {
+		failedAttempts = pc.Lockout.FailedAttempts
+	}
+	if d, err := time.ParseDuration(pc.Lockout.Duration); err == nil && d > 0 {
+		duration = d
+	}
+	return failedAttempts, duration
+}
+
+// Limits of the rate of sign-in attempts from each IP address.
+const (
+	signInRateLimit = rate.Limit(1.0 / 6) // 10 per minute
+	signInRateBurst = 20
+)
+
+// maxRateLimitedIPs bounds the memory used by an ipRateLimiter. When it is reached, the limiter of
+// the least recently seen IP address is discarded.
+const maxRateLimitedIPs = 10000
+
+// ipRateLimiter limits the rate of requests from each IP address. It is in-memory, so each frontend
+// replica enforces the limit separately.
+type ipRateLimiter struct {
+	limit rate.Limit
+	burst int
+
+	mu       sync.Mutex
+	limiters *lru.Cache // IP address -> *rate.Limiter
+}
+
+func newIPRateLimiter(limit rate.Limit, burst int) *ipRateLimiter {
+	return &ipRateLimiter{limit: limit, burst: burst, limiters: lru.New(maxRateLimitedIPs)}
+}
+
+// Allow reports whether a request from the client of r may be handled now.
+func (l *ipRateLimiter) Allow(r *http.Request) bool {
+	ip := httputil.ClientIP(r)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if v, ok := l.limiters.Get(ip); ok {
+		return v.(*rate.Limiter).Allow()
+	}
+	limiter := rate.NewLimiter(l.limit, l.burst)
+	l.limiters.Add(ip, limiter)
+	return limiter.Allow()
+}
+
+var signInRateLimiter = newIPRateLimiter(signInRateLimit, signInRateBurst)
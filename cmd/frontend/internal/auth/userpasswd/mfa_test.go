@@ -0,0 +1,407 @@
+package userpasswd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pquerna/otp/totp"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/session"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/secret"
+	"github.com/sourcegraph/sourcegraph/schema"
+	"golang.org/x/time/rate"
+)
+
+func TestHandleSignIn_MFA(t *testing.T) {
+	cleanup := session.ResetMockSessionStore(t)
+	defer cleanup()
+	defer func() { db.Mocks = db.MockStores{} }()
+
+	mockBuiltinAuthProvider := func(p schema.BuiltinAuthProvider) {
+		p.Type = "builtin"
+		conf.Mock(&conf.Unified{SiteConfiguration: schema.SiteConfiguration{
+			AuthProviders: []schema.AuthProviders{{Builtin: &p}},
+		}})
+	}
+	defer conf.Mock(nil)
+
+	secret.MockKey("test secret key")
+	defer secret.MockKey("")
+	const totpSecret = "JBSWY3DPEHPK3PXP"
+	encryptedSecret, err := secret.Encrypt([]byte(totpSecret))
+	if err != nil {
+		t.Fatal(err)
+	}
+	validCode := func() string {
+		code, err := totp.GenerateCode(totpSecret, time.Now())
+		if err != nil {
+			t.Fatal(err)
+		}
+		return code
+	}
+
+	var (
+		userTOTP       *db.UserTOTP
+		failedAttempts int
+		lockedUntil    *time.Time
+		recoveryCodes  []string
+	)
+	reset := func() {
+		db.Mocks = db.MockStores{}
+		mockBuiltinAuthProvider(schema.BuiltinAuthProvider{})
+		signInRateLimiter = newIPRateLimiter(signInRateLimit, signInRateBurst)
+		userTOTP, failedAttempts, lockedUntil, recoveryCodes = nil, 0, nil, nil
+
+		db.Mocks.Users.GetByUsername = func(ctx context.Context, username string) (*types.User, error) {
+			return &types.User{ID: 1, Username: username}, nil
+		}
+		db.Mocks.Users.IsPassword = func(ctx context.Context, id int32, password string) (bool, error) {
+			return password == "right-password", nil
+		}
+		db.Mocks.Users.SignInLockedUntil = func(ctx context.Context, id int32) (*time.Time, error) {
+			return lockedUntil, nil
+		}
+		db.Mocks.Users.RecordFailedSignIn = func(ctx context.Context, id int32, maxAttempts int, lockout time.Duration) (*time.Time, error) {
+			failedAttempts++
+			if failedAttempts >= maxAttempts {
+				t := time.Now().Add(lockout)
+				lockedUntil = &t
+				return lockedUntil, nil
+			}
+			return nil, nil
+		}
+		db.Mocks.Users.ResetFailedSignIns = func(ctx context.Context, id int32) error {
+			failedAttempts = 0
+			return nil
+		}
+		db.Mocks.AuditLogs.Create = func(e *db.AuditLogEntry) error { return nil }
+
+		db.Mocks.UserMFA.IsEnabled = func(userID int32) (bool, error) {
+			return userTOTP != nil && userTOTP.Enabled(), nil
+		}
+		db.Mocks.UserMFA.GetTOTP = func(userID int32) (*db.UserTOTP, error) {
+			return userTOTP, nil
+		}
+		db.Mocks.UserMFA.CreatePendingTOTP = func(userID int32, encryptedSecret string) error {
+			userTOTP = &db.UserTOTP{UserID: userID, EncryptedSecret: encryptedSecret}
+			return nil
+		}
+		db.Mocks.UserMFA.EnableTOTP = func(userID int32, step int64) error {
+			now := time.Now()
+			userTOTP.EnabledAt, userTOTP.LastUsedStep = &now, step
+			return nil
+		}
+		db.Mocks.UserMFA.UseTOTPStep = func(userID int32, step int64) (bool, error) {
+			if step <= userTOTP.LastUsedStep {
+				return false, nil
+			}
+			userTOTP.LastUsedStep = step
+			return true, nil
+		}
+		db.Mocks.UserMFA.ListWebAuthnCredentials = func(userID int32) ([]*db.WebAuthnCredential, error) {
+			return nil, nil
+		}
+		db.Mocks.UserMFA.SetRecoveryCodes = func(userID int32, codes []string) error {
+			recoveryCodes = codes
+			return nil
+		}
+		db.Mocks.UserMFA.UseRecoveryCode = func(userID int32, code string) (bool, error) {
+			for i, c := range recoveryCodes {
+				if c == code {
+					recoveryCodes = append(recoveryCodes[:i], recoveryCodes[i+1:]...)
+					return true, nil
+				}
+			}
+			return false, nil
+		}
+	}
+
+	signIn := func(body map[string]interface{}) (*httptest.ResponseRecorder, signInResponse) {
+		t.Helper()
+		b, err := json.Marshal(body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req := httptest.NewRequest("POST", "/-/sign-in", strings.NewReader(string(b)))
+		rr := httptest.NewRecorder()
+		HandleSignIn(rr, req)
+		var resp signInResponse
+		if rr.Code == http.StatusOK && rr.Body.Len() > 0 {
+			if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return rr, resp
+	}
+	signedIn := func(rr *httptest.ResponseRecorder) bool {
+		return rr.Code == http.StatusOK && rr.Header().Get("Set-Cookie") != ""
+	}
+
+	t.Run("no MFA", func(t *testing.T) {
+		reset()
+		if rr, _ := signIn(map[string]interface{}{"email": "u", "password": "wrong-password"}); rr.Code != http.StatusUnauthorized {
+			t.Errorf("got status %d, want %d", rr.Code, http.StatusUnauthorized)
+		}
+		if rr, _ := signIn(map[string]interface{}{"email": "u", "password": "right-password"}); !signedIn(rr) {
+			t.Errorf("not signed in: status %d, body %q", rr.Code, rr.Body)
+		}
+	})
+
+	t.Run("lockout", func(t *testing.T) {
+		reset()
+		mockBuiltinAuthProvider(schema.BuiltinAuthProvider{Lockout: &schema.BuiltinAuthLockout{FailedAttempts: 2}})
+		now := time.Now()
+		userTOTP = &db.UserTOTP{UserID: 1, EncryptedSecret: encryptedSecret, EnabledAt: &now}
+
+		// Wrong passwords don't count, so that nobody can lock other users out.
+		for i := 0; i < 3; i++ {
+			if rr, _ := signIn(map[string]interface{}{"email": "u", "password": "wrong-password", "totpCode": "000000"}); rr.Code != http.StatusUnauthorized {
+				t.Errorf("got status %d, want %d", rr.Code, http.StatusUnauthorized)
+			}
+		}
+		if failedAttempts != 0 || lockedUntil != nil {
+			t.Fatalf("got %d failed attempts, want 0", failedAttempts)
+		}
+
+		signIn(map[string]interface{}{"email": "u", "password": "right-password", "totpCode": "000000"})
+		if rr, _ := signIn(map[string]interface{}{"email": "u", "password": "right-password", "totpCode": "000000"}); rr.Code != http.StatusTooManyRequests {
+			t.Errorf("got status %d, want %d", rr.Code, http.StatusTooManyRequests)
+		}
+		// The correct second factor is refused while the account is locked.
+		if rr, _ := signIn(map[string]interface{}{"email": "u", "password": "right-password", "totpCode": validCode()}); rr.Code != http.StatusTooManyRequests || signedIn(rr) {
+			t.Errorf("got status %d, want %d", rr.Code, http.StatusTooManyRequests)
+		}
+		// Whether the account is locked is only disclosed to clients that know the password.
+		if rr, _ := signIn(map[string]interface{}{"email": "u", "password": "wrong-password"}); rr.Code != http.StatusUnauthorized {
+			t.Errorf("got status %d, want %d", rr.Code, http.StatusUnauthorized)
+		}
+	})
+
+	t.Run("TOTP", func(t *testing.T) {
+		reset()
+		now := time.Now()
+		userTOTP = &db.UserTOTP{UserID: 1, EncryptedSecret: encryptedSecret, EnabledAt: &now}
+		recoveryCodes = []string{"abcdefgh23456789"}
+
+		rr, resp := signIn(map[string]interface{}{"email": "u", "password": "right-password"})
+		if signedIn(rr) || !resp.MFARequired || !resp.TOTP || resp.WebAuthn != nil {
+			t.Fatalf("got status %d and response %+v, want TOTP to be required", rr.Code, resp)
+		}
+
+		// The second factor is only checked after the password.
+		if rr, _ := signIn(map[string]interface{}{"email": "u", "password": "wrong-password", "totpCode": validCode()}); rr.Code != http.StatusUnauthorized {
+			t.Errorf("got status %d, want %d", rr.Code, http.StatusUnauthorized)
+		}
+		if rr, _ := signIn(map[string]interface{}{"email": "u", "password": "right-password", "totpCode": "000000"}); rr.Code != http.StatusUnauthorized {
+			t.Errorf("got status %d, want %d", rr.Code, http.StatusUnauthorized)
+		}
+
+		code := validCode()
+		if rr, _ := signIn(map[string]interface{}{"email": "u", "password": "right-password", "totpCode": code}); !signedIn(rr) {
+			t.Errorf("not signed in: status %d, body %q", rr.Code, rr.Body)
+		}
+		// A code can't be reused.
+		if rr, _ := signIn(map[string]interface{}{"email": "u", "password": "right-password", "totpCode": code}); rr.Code != http.StatusUnauthorized {
+			t.Errorf("reused code: got status %d, want %d", rr.Code, http.StatusUnauthorized)
+		}
+
+		if rr, _ := signIn(map[string]interface{}{"email": "u", "password": "right-password", "recoveryCode": "ABCD-EFGH-2345-6789"}); !signedIn(rr) {
+			t.Errorf("not signed in with recovery code: status %d, body %q", rr.Code, rr.Body)
+		}
+		if rr, _ := signIn(map[string]interface{}{"email": "u", "password": "right-password", "recoveryCode": "abcdefgh23456789"}); rr.Code != http.StatusUnauthorized {
+			t.Errorf("reused recovery code: got status %d, want %d", rr.Code, http.StatusUnauthorized)
+		}
+	})
+
+	t.Run("enrollment required", func(t *testing.T) {
+		reset()
+		mockBuiltinAuthProvider(schema.BuiltinAuthProvider{RequireMFA: "everyone"})
+
+		rr, resp := signIn(map[string]interface{}{"email": "u", "password": "right-password"})
+		if signedIn(rr) || !resp.MFAEnrollmentRequired || resp.TOTPSecret == "" || resp.TOTPURL == "" {
+			t.Fatalf("got status %d and response %+v, want enrollment to be required", rr.Code, resp)
+		}
+		if userTOTP == nil || userTOTP.Enabled() {
+			t.Fatalf("unexpected TOTP enrollment %+v", userTOTP)
+		}
+		// The secret is only stored encrypted.
+		if stored, err := secret.Decrypt(userTOTP.EncryptedSecret); err != nil || string(stored) != resp.TOTPSecret {
+			t.Fatalf("got stored secret %q (error %v), want the encrypted %q", userTOTP.EncryptedSecret, err, resp.TOTPSecret)
+		}
+
+		if rr, _ := signIn(map[string]interface{}{"email": "u", "password": "right-password", "totpCode": "000000"}); rr.Code != http.StatusUnauthorized {
+			t.Errorf("got status %d, want %d", rr.Code, http.StatusUnauthorized)
+		}
+
+		code, err := totp.GenerateCode(resp.TOTPSecret, time.Now())
+		if err != nil {
+			t.Fatal(err)
+		}
+		rr, resp = signIn(map[string]interface{}{"email": "u", "password": "right-password", "totpCode": code})
+		if !signedIn(rr) {
+			t.Fatalf("not signed in: status %d, body %q", rr.Code, rr.Body)
+		}
+		if !userTOTP.Enabled() {
+			t.Error("TOTP not enabled")
+		}
+		if len(resp.RecoveryCodes) != numRecoveryCodes || len(recoveryCodes) != numRecoveryCodes {
+			t.Errorf("got %d recovery codes (%d stored), want %d", len(resp.RecoveryCodes), len(recoveryCodes), numRecoveryCodes)
+		}
+	})
+
+	t.Run("required only for site admins", func(t *testing.T) {
+		reset()
+		mockBuiltinAuthProvider(schema.BuiltinAuthProvider{RequireMFA: "siteAdmins"})
+		if rr, _ := signIn(map[string]interface{}{"email": "u", "password": "right-password"}); !signedIn(rr) {
+			t.Errorf("not signed in: status %d, body %q", rr.Code, rr.Body)
+		}
+	})
+}
+
+func TestHandleSignUp_MFA(t *testing.T) {
+	cleanup := session.ResetMockSessionStore(t)
+	defer cleanup()
+	defer func() { db.Mocks = db.MockStores{} }()
+	defer conf.Mock(nil)
+
+	db.Mocks.Users.Create = func(ctx context.Context, info db.NewUser) (*types.User, error) {
+		return &types.User{ID: 1, Username: info.Username, SiteAdmin: info.FailIfNotInitialUser}, nil
+	}
+	db.Mocks.Users.ResetFailedSignIns = func(ctx context.Context, id int32) error { return nil }
+	db.Mocks.Authz.GrantPendingPermissions = func(ctx context.Context, args *db.GrantPendingPermissionsArgs) error { return nil }
+	db.Mocks.UserMFA.IsEnabled = func(userID int32) (bool, error) { return false, nil }
+	db.Mocks.UserMFA.CreatePendingTOTP = func(userID int32, encryptedSecret string) error { return nil }
+	secret.MockKey("test secret key")
+	defer secret.MockKey("")
+
+	for _, test := range []struct {
+		name        string
+		requireMFA  string
+		siteInit    bool
+		wantSession bool
+	}{
+		{name: "sign-up", wantSession: true},
+		{name: "sign-up with MFA required", requireMFA: "everyone"},
+		{name: "sign-up with MFA required for site admins", requireMFA: "siteAdmins", wantSession: true},
+		{name: "site init with MFA required for site admins", requireMFA: "siteAdmins", siteInit: true},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			conf.Mock(&conf.Unified{SiteConfiguration: schema.SiteConfiguration{
+				AuthProviders: []schema.AuthProviders{{Builtin: &schema.BuiltinAuthProvider{Type: "builtin", AllowSignup: true, RequireMFA: test.requireMFA}}},
+			}})
+
+			req := httptest.NewRequest("POST", "/-/sign-up", strings.NewReader(`{"email":"u@example.com","username":"u","password":"right-password"}`))
+			req.Header.Set("User-Agent", "Sourcegraph e2etest-bot")
+			rr := httptest.NewRecorder()
+			if test.siteInit {
+				HandleSiteInit(rr, req)
+			} else {
+				HandleSignUp(rr, req)
+			}
+			if rr.Code != http.StatusOK {
+				t.Fatalf("got status %d, body %q", rr.Code, rr.Body)
+			}
+
+			if gotSession := rr.Header().Get("Set-Cookie") != ""; gotSession != test.wantSession {
+				t.Errorf("got session %v, want %v", gotSession, test.wantSession)
+			}
+			var resp signInResponse
+			if rr.Body.Len() > 0 {
+				if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if resp.MFAEnrollmentRequired == test.wantSession {
+				t.Errorf("got enrollment required %v, want %v", resp.MFAEnrollmentRequired, !test.wantSession)
+			}
+		})
+	}
+}
+
+func TestIPRateLimiter(t *testing.T) {
+	l := newIPRateLimiter(rate.Every(time.Hour), 2)
+	req := func(remoteAddr string) *http.Request {
+		r := httptest.NewRequest("POST", "/-/sign-in", nil)
+		r.RemoteAddr = remoteAddr
+		return r
+	}
+	for i, want := range []bool{true, true, false} {
+		if got := l.Allow(req("10.0.0.1:1234")); got != want {
+			t.Errorf("request %d: got %v, want %v", i, got, want)
+		}
+	}
+	// Requests from other ports of the same IP address share the limit.
+	if l.Allow(req("10.0.0.1:5678")) {
+		t.Error("got allowed, want rate limited")
+	}
+	if !l.Allow(req("10.0.0.2:1234")) {
+		t.Error("got rate limited, want allowed")
+	}
+
+	// Clients behind a trusted proxy are limited separately.
+	conf.Mock(&conf.Unified{SiteConfiguration: schema.SiteConfiguration{TrustedProxies: []string{"10.0.0.1"}}})
+	defer conf.Mock(nil)
+	forwarded := func(clientIP string) *http.Request {
+		r := req("10.0.0.1:1234")
+		r.Header.Set("X-Forwarded-For", clientIP)
+		return r
+	}
+	for i, want := range []bool{true, true, false} {
+		if got := l.Allow(forwarded("203.0.113.1")); got != want {
+			t.Errorf("forwarded request %d: got %v, want %v", i, got, want)
+		}
+	}
+	if !l.Allow(forwarded("203.0.113.2")) {
+		t.Error("got rate limited, want allowed")
+	}
+}
+
+func TestIPRateLimiter_Full(t *testing.T) {
+	l := newIPRateLimiter(rate.Every(time.Hour), 1)
+	req := func(ip string) *http.Request {
+		r := httptest.NewRequest("POST", "/-/sign-in", nil)
+		r.RemoteAddr = ip + ":1234"
+		return r
+	}
+	const attacker = "10.0.0.1"
+	if !l.Allow(req(attacker)) || l.Allow(req(attacker)) {
+		t.Fatal("want the first request to be allowed and the second to be rate limited")
+	}
+
+	// Requests from many other IP addresses (such as when an attacker rotates some of their
+	// addresses) don't reset the limit of an IP address that keeps making requests.
+	for i := 0; i < 2*maxRateLimitedIPs; i++ {
+		l.Allow(req(fmt.Sprintf("10.%d.%d.%d", 1+i>>16, i>>8&0xff, i&0xff)))
+		if i%100 == 0 && l.Allow(req(attacker)) {
+			t.Fatalf("after requests from %d other IP addresses: got allowed, want rate limited", i+1)
+		}
+	}
+	if got := l.limiters.Len(); got > maxRateLimitedIPs {
+		t.Errorf("got %d limiters, want at most %d", got, maxRateLimitedIPs)
+	}
+}
+
+func TestLockoutConfig(t *testing.T) {
+	for name, test := range map[string]struct {
+		lockout            *schema.BuiltinAuthLockout
+		wantFailedAttempts int
+		wantDuration       time.Duration
+	}{
+		"default":  {nil, defaultLockoutFailedAttempts, defaultLockoutDuration},
+		"custom":   {&schema.BuiltinAuthLockout{FailedAttempts: 3, Duration: "1h"}, 3, time.Hour},
+		"disabled": {&schema.BuiltinAuthLockout{FailedAttempts: -1}, 0, 0},
+	} {
+		failedAttempts, duration := lockoutConfig(&schema.BuiltinAuthProvider{Lockout: test.lockout})
+		if failedAttempts != test.wantFailedAttempts || duration != test.wantDuration {
+			t.Errorf("%s: got %d and %s, want %d and %s", name, failedAttempts, duration, test.wantFailedAttempts, test.wantDuration)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package userpasswd
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/pquerna/otp"
+	"github.com/pquerna/otp/hotp"
+	"github.com/pquerna/otp/totp"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/secret"
+)
+
+// TOTPIssuer is the issuer shown next to the account in users' authenticator apps.
+const TOTPIssuer = "Sourcegraph"
+
+const (
+	// totpPeriod is the length in seconds of a TOTP time step.
+	totpPeriod = 30
+
+	// totpSkew is the number of time steps before and after the current one whose codes are also
+	// accepted, to tolerate clock drift and the time it takes users to enter codes.
+	totpSkew = 1
+)
+
+// BeginTOTPEnrollment starts a TOTP enrollment of the user with a new random secret, replacing any
+// pending enrollment. It returns the base32-encoded secret and its otpauth:// URL, which
+// authenticator apps can import (usually by scanning a QR code of it).
+//
+// The secret is stored encrypted with the SRC_SECRET_KEY, so enrollment fails if it is not set.
+func BeginTOTPEnrollment(ctx context.Context, user *types.User) (totpSecret, url string, err error) {
+	key, err := totp.Generate(totp.GenerateOpts{
+		Issuer:      TOTPIssuer,
+		AccountName: user.Username,
+		Period:      totpPeriod,
+		Digits:      otp.DigitsSix,
+		Algorithm:   otp.AlgorithmSHA1,
+	})
+	if err != nil {
+		return "", "", err
+	}
+	encrypted, err := secret.Encrypt([]byte(key.Secret()))
+	if err != nil {
+		return "", "", err
+	}
+	if err := db.UserMFA.CreatePendingTOTP(ctx, user.ID, encrypted); err != nil {
+		return "", "", err
+	}
+	return key.Secret(), key.URL(), nil
+}
+
+// ValidateTOTP checks the code against the codes of the TOTP enrollment for the time steps around
+// t (see totpSkew). If the code is valid, it returns the time step it belongs to; otherwise it
+// returns ok == false.
+//
+// 🚨 SECURITY: To prevent the reuse of codes, callers must record the returned time step and
+// reject codes of the same or earlier time steps.
+func ValidateTOTP(enrollment *db.UserTOTP, code string, t time.Time) (step int64, ok bool, err error) {
+	plaintext, err := secret.Decrypt(enrollment.EncryptedSecret)
+	if err != nil {
+		return 0, false, err
+	}
+	return validateTOTP(string(plaintext), code, t)
+}
+
+// validateTOTP is like ValidateTOTP, for the base32-encoded secret of an enrollment.
+func validateTOTP(secret, code string, t time.Time) (step int64, ok bool, err error) {
+	code = strings.Replace(strings.TrimSpace(code), " ", "", -1)
+	if len(code) != otp.DigitsSix.Length() {
+		return 0, false, nil
+	}
+	now := t.Unix() / totpPeriod
+	for s := now - totpSkew; s <= now+totpSkew; s++ {
+		ok, err := hotp.ValidateCustom(code, uint64(s), secret, hotp.ValidateOpts{
+			Digits:    otp.DigitsSix,
+			Algorithm: otp.AlgorithmSHA1,
+		})
+		if err != nil {
+			return 0, false, err
+		}
+		if ok {
+			return s, true, nil
+		}
+	}
+	return 0, false, nil
+}
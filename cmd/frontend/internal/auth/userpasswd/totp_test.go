@@ -0,0 +1,101 @@
+package userpasswd
+
+import (
+	"context"
+	"encoding/base32"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/pquerna/otp/totp"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/secret"
+)
+
+func TestValidateTOTP(t *testing.T) {
+	secret.MockKey("test secret key")
+	defer secret.MockKey("")
+
+	// The test secret and a test vector of RFC 6238 appendix B for HMAC-SHA1 (whose 8-digit codes
+	// end with the 6-digit codes).
+	totpSecret := base32.StdEncoding.EncodeToString([]byte("12345678901234567890"))
+	encrypted, err := secret.Encrypt([]byte(totpSecret))
+	if err != nil {
+		t.Fatal(err)
+	}
+	enrollment := &db.UserTOTP{EncryptedSecret: encrypted}
+	now := time.Unix(1111111111, 0)
+	step := now.Unix() / totpPeriod
+
+	for code, wantStep := range map[string]int64{
+		"050471":  step,
+		"050 471": step,
+		"081804":  step - 1, // previous time step
+	} {
+		gotStep, ok, err := ValidateTOTP(enrollment, code, now)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !ok || gotStep != wantStep {
+			t.Errorf("%q: got step %d and ok %v, want step %d", code, gotStep, ok, wantStep)
+		}
+	}
+
+	for _, code := range []string{"", "000000", "05047", "0504710", "287082"} {
+		if _, ok, err := ValidateTOTP(enrollment, code, now); err != nil || ok {
+			t.Errorf("%q: got ok %v and error %v, want not ok", code, ok, err)
+		}
+	}
+
+	if _, _, err := validateTOTP("not base32!", "050471", now); err == nil {
+		t.Error("expected an error for an invalid secret")
+	}
+	// Unencrypted secrets are rejected.
+	if _, ok, err := ValidateTOTP(&db.UserTOTP{EncryptedSecret: totpSecret}, "050471", now); err == nil || ok {
+		t.Errorf("got ok %v and error %v for an unencrypted secret, want an error", ok, err)
+	}
+}
+
+func TestBeginTOTPEnrollment(t *testing.T) {
+	defer func() { db.Mocks = db.MockStores{} }()
+	var stored *db.UserTOTP
+	db.Mocks.UserMFA.CreatePendingTOTP = func(userID int32, encryptedSecret string) error {
+		stored = &db.UserTOTP{UserID: userID, EncryptedSecret: encryptedSecret}
+		return nil
+	}
+	ctx := context.Background()
+	user := &types.User{ID: 1, Username: "alice"}
+
+	if _, _, err := BeginTOTPEnrollment(ctx, user); err != secret.ErrNoKey || stored != nil {
+		t.Fatalf("got error %v without SRC_SECRET_KEY, want %v", err, secret.ErrNoKey)
+	}
+
+	secret.MockKey("test secret key")
+	defer secret.MockKey("")
+	totpSecret, rawURL, err := BeginTOTPEnrollment(ctx, user)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored == nil || stored.UserID != user.ID || stored.EncryptedSecret == totpSecret {
+		t.Fatalf("got stored enrollment %+v, want an encrypted secret", stored)
+	}
+	code, err := totp.GenerateCode(totpSecret, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok, err := ValidateTOTP(stored, code, time.Now()); err != nil || !ok {
+		t.Errorf("got ok %v and error %v for a current code, want ok", ok, err)
+	}
+
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.Scheme != "otpauth" || u.Host != "totp" || u.Path != "/Sourcegraph:alice" {
+		t.Errorf("unexpected URL %q", u)
+	}
+	if got := u.Query().Get("secret"); got != totpSecret {
+		t.Errorf("got secret %q, want %q", got, totpSecret)
+	}
+}
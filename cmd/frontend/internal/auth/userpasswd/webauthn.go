@@ -0,0 +1,232 @@
+package userpasswd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+
+	"github.com/duo-labs/webauthn/protocol"
+	"github.com/duo-labs/webauthn/webauthn"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/globals"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/rcache"
+)
+
+// webAuthnTimeout is the number of milliseconds that clients wait for the user to use their
+// security key.
+const webAuthnTimeout = 120000
+
+// relyingParty returns the WebAuthn relying party of the site, which is derived from its external
+// URL. Security keys registered with it can only be used on the same hostname.
+//
+// No attestation is requested, so any security key can be registered.
+func relyingParty() (*webauthn.WebAuthn, error) {
+	u := globals.ExternalURL()
+	return webauthn.New(&webauthn.Config{
+		RPDisplayName:         "Sourcegraph",
+		RPID:                  u.Hostname(),
+		RPOrigin:              u.Scheme + "://" + u.Host,
+		AttestationPreference: protocol.PreferNoAttestation,
+		AuthenticatorSelection: protocol.AuthenticatorSelection{
+			UserVerification: protocol.VerificationDiscouraged,
+		},
+		Timeout: webAuthnTimeout,
+	})
+}
+
+// webAuthnUser is a user and their registered security keys.
+type webAuthnUser struct {
+	user  *types.User
+	creds []*db.WebAuthnCredential
+}
+
+var _ webauthn.User = (*webAuthnUser)(nil)
+
+func (u *webAuthnUser) WebAuthnID() []byte   { return []byte(strconv.Itoa(int(u.user.ID))) }
+func (u *webAuthnUser) WebAuthnName() string { return u.user.Username }
+func (u *webAuthnUser) WebAuthnIcon() string { return "" }
+
+func (u *webAuthnUser) WebAuthnDisplayName() string {
+	if u.user.DisplayName != "" {
+		return u.user.DisplayName
+	}
+	return u.user.Username
+}
+
+func (u *webAuthnUser) WebAuthnCredentials() []webauthn.Credential {
+	creds := make([]webauthn.Credential, len(u.creds))
+	for i, c := range u.creds {
+		creds[i] = webauthn.Credential{
+			ID:            c.CredentialID,
+			PublicKey:     c.PublicKey,
+			Authenticator: webauthn.Authenticator{SignCount: c.SignCount},
+		}
+	}
+	return creds
+}
+
+// challengeTTLSeconds is how long a user has to complete a WebAuthn ceremony.
+const challengeTTLSeconds = 5 * 60
+
+// cache is the subset of *rcache.Cache used to store pending WebAuthn ceremonies.
+type cache interface {
+	Get(key string) ([]byte, bool)
+	Set(key string, b []byte)
+	Delete(key string)
+}
+
+var webAuthnSessions cache = rcache.NewWithTTL("webauthn_challenge", challengeTTLSeconds)
+
+// Kinds of WebAuthn ceremonies.
+const (
+	webAuthnRegistration   = "registration"
+	webAuthnAuthentication = "authentication"
+)
+
+// saveWebAuthnSession stores the user's pending WebAuthn ceremony of the given kind, replacing any
+// previous ceremony of the same kind.
+func saveWebAuthnSession(userID int32, kind string, s *webauthn.SessionData) error {
+	b, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	webAuthnSessions.Set(kind+":"+strconv.Itoa(int(userID)), b)
+	return nil
+}
+
+// consumeWebAuthnSession returns the user's pending WebAuthn ceremony of the given kind (or nil if
+// there is none) and removes it, so that each challenge is used at most once.
+func consumeWebAuthnSession(userID int32, kind string) *webauthn.SessionData {
+	key := kind + ":" + strconv.Itoa(int(userID))
+	b, ok := webAuthnSessions.Get(key)
+	if !ok {
+		return nil
+	}
+	webAuthnSessions.Delete(key)
+	var s webauthn.SessionData
+	if err := json.Unmarshal(b, &s); err != nil {
+		return nil
+	}
+	return &s
+}
+
+// BeginSecurityKeyRegistration starts registering a new security key for the user. It returns the
+// options to pass to navigator.credentials.create (as its publicKey member). The user's existing
+// security keys are excluded so that they aren't registered twice.
+func BeginSecurityKeyRegistration(ctx context.Context, user *types.User) (interface{}, error) {
+	creds, err := db.UserMFA.ListWebAuthnCredentials(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	rp, err := relyingParty()
+	if err != nil {
+		return nil, err
+	}
+	exclusions := make([]protocol.CredentialDescriptor, len(creds))
+	for i, c := range creds {
+		exclusions[i] = protocol.CredentialDescriptor{Type: protocol.PublicKeyCredentialType, CredentialID: c.CredentialID}
+	}
+	creation, s, err := rp.BeginRegistration(&webAuthnUser{user: user, creds: creds},
+		webauthn.WithExclusions(exclusions),
+		webauthn.WithAuthenticatorSelection(rp.Config.AuthenticatorSelection),
+	)
+	if err != nil {
+		return nil, err
+	}
+	if err := saveWebAuthnSession(user.ID, webAuthnRegistration, s); err != nil {
+		return nil, err
+	}
+	return creation.Response, nil
+}
+
+// FinishSecurityKeyRegistration verifies the response of navigator.credentials.create (a
+// PublicKeyCredential in JSON, with binary values base64url-encoded) to the user's pending
+// registration. It returns the new security key, which the caller must name and store.
+func FinishSecurityKeyRegistration(ctx context.Context, user *types.User, response []byte) (*db.WebAuthnCredential, error) {
+	// 🚨 SECURITY: The challenge must have been issued to this user for registering a security key.
+	s := consumeWebAuthnSession(user.ID, webAuthnRegistration)
+	if s == nil {
+		return nil, errors.New("security key registration expired (try again)")
+	}
+	parsed, err := protocol.ParseCredentialCreationResponseBody(bytes.NewReader(response))
+	if err != nil {
+		return nil, err
+	}
+	rp, err := relyingParty()
+	if err != nil {
+		return nil, err
+	}
+	cred, err := rp.CreateCredential(&webAuthnUser{user: user}, *s, parsed)
+	if err != nil {
+		return nil, err
+	}
+	return &db.WebAuthnCredential{
+		UserID:       user.ID,
+		CredentialID: cred.ID,
+		PublicKey:    cred.PublicKey,
+		SignCount:    cred.Authenticator.SignCount,
+	}, nil
+}
+
+// beginSecurityKeySignIn starts signing in the user with one of their security keys. It returns the
+// options to pass to navigator.credentials.get (as its publicKey member).
+func beginSecurityKeySignIn(user *types.User, creds []*db.WebAuthnCredential) (interface{}, error) {
+	rp, err := relyingParty()
+	if err != nil {
+		return nil, err
+	}
+	assertion, s, err := rp.BeginLogin(&webAuthnUser{user: user, creds: creds})
+	if err != nil {
+		return nil, err
+	}
+	if err := saveWebAuthnSession(user.ID, webAuthnAuthentication, s); err != nil {
+		return nil, err
+	}
+	return assertion.Response, nil
+}
+
+// verifySecurityKey reports whether the response of navigator.credentials.get (a
+// PublicKeyCredential in JSON, with binary values base64url-encoded) is a valid assertion of one of
+// the user's security keys for their pending sign-in. The new signature counter of the security key
+// is recorded.
+//
+// 🚨 SECURITY: Any change to this function could allow users to sign in without their second
+// factor. Be careful.
+func verifySecurityKey(ctx context.Context, user *types.User, response []byte) (bool, error) {
+	// 🚨 SECURITY: The challenge must have been issued to this user for signing in.
+	s := consumeWebAuthnSession(user.ID, webAuthnAuthentication)
+	if s == nil {
+		return false, nil
+	}
+	parsed, err := protocol.ParseCredentialRequestResponseBody(bytes.NewReader(response))
+	if err != nil {
+		return false, nil
+	}
+	creds, err := db.UserMFA.ListWebAuthnCredentials(ctx, user.ID)
+	if err != nil {
+		return false, err
+	}
+	rp, err := relyingParty()
+	if err != nil {
+		return false, err
+	}
+	cred, err := rp.ValidateLogin(&webAuthnUser{user: user, creds: creds}, *s, parsed)
+	if err != nil {
+		return false, nil
+	}
+	// 🚨 SECURITY: A signature counter that didn't increase means that the assertion was replayed
+	// or that the security key was cloned. The library only flags this, so reject it here.
+	if cred.Authenticator.CloneWarning {
+		return false, nil
+	}
+	for _, c := range creds {
+		if bytes.Equal(c.CredentialID, cred.ID) {
+			// 🚨 SECURITY: Store the new signature counter to detect cloned security keys.
+			return db.UserMFA.UpdateWebAuthnCredentialSignCount(ctx, c.ID, c.SignCount, cred.Authenticator.SignCount)
+		}
+	}
+	return false, nil
+}
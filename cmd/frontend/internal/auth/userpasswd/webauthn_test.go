@@ -0,0 +1,262 @@
+package userpasswd
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"math/big"
+	"net/url"
+	"testing"
+
+	"github.com/duo-labs/webauthn/protocol"
+	"github.com/fxamacker/cbor/v2"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/globals"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+)
+
+type mapCache map[string][]byte
+
+func (c mapCache) Get(key string) ([]byte, bool) { b, ok := c[key]; return b, ok }
+func (c mapCache) Set(key string, b []byte)      { c[key] = b }
+func (c mapCache) Delete(key string)             { delete(c, key) }
+
+const (
+	testOrigin = "https://sourcegraph.example.com"
+	testRPID   = "sourcegraph.example.com"
+)
+
+// authenticator is a fake security key with an ES256 credential.
+type authenticator struct {
+	t         *testing.T
+	key       *ecdsa.PrivateKey
+	id        []byte
+	signCount uint32
+}
+
+func newAuthenticator(t *testing.T, id string) *authenticator {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &authenticator{t: t, key: key, id: []byte(id)}
+}
+
+func (a *authenticator) authData(rpID string, attested bool) []byte {
+	rpIDHash := sha256.Sum256([]byte(rpID))
+	b := append([]byte(nil), rpIDHash[:]...)
+	flags := byte(protocol.FlagUserPresent)
+	if attested {
+		flags |= byte(protocol.FlagAttestedCredentialData)
+	}
+	b = append(b, flags, 0, 0, 0, 0)
+	binary.BigEndian.PutUint32(b[33:], a.signCount)
+	if attested {
+		b = append(b, make([]byte, 16)...) // AAGUID
+		b = append(b, byte(len(a.id)>>8), byte(len(a.id)))
+		b = append(b, a.id...)
+		b = append(b, a.marshalCBOR(map[int]interface{}{
+			1:  2,  // key type: EC2
+			3:  -7, // algorithm: ES256
+			-1: 1,  // curve: P-256
+			-2: pad32(a.key.X.Bytes()),
+			-3: pad32(a.key.Y.Bytes()),
+		})...)
+	}
+	return b
+}
+
+func (a *authenticator) marshalCBOR(v interface{}) []byte {
+	b, err := cbor.Marshal(v)
+	if err != nil {
+		a.t.Fatal(err)
+	}
+	return b
+}
+
+func pad32(b []byte) []byte {
+	return append(make([]byte, 32-len(b)), b...)
+}
+
+func (a *authenticator) clientData(typ string, challenge []byte, origin string) []byte {
+	b, err := json.Marshal(map[string]string{
+		"type":      typ,
+		"challenge": base64.RawURLEncoding.EncodeToString(challenge),
+		"origin":    origin,
+	})
+	if err != nil {
+		a.t.Fatal(err)
+	}
+	return b
+}
+
+func (a *authenticator) credential(response map[string][]byte) []byte {
+	encoded := map[string]string{}
+	for k, v := range response {
+		encoded[k] = base64.RawURLEncoding.EncodeToString(v)
+	}
+	id := base64.RawURLEncoding.EncodeToString(a.id)
+	b, err := json.Marshal(map[string]interface{}{"id": id, "rawId": id, "type": "public-key", "response": encoded})
+	if err != nil {
+		a.t.Fatal(err)
+	}
+	return b
+}
+
+func (a *authenticator) register(challenge []byte, origin, rpID string) []byte {
+	return a.credential(map[string][]byte{
+		"clientDataJSON": a.clientData("webauthn.create", challenge, origin),
+		"attestationObject": a.marshalCBOR(map[string]interface{}{
+			"fmt":      "none",
+			"attStmt":  map[string]interface{}{},
+			"authData": a.authData(rpID, true),
+		}),
+	})
+}
+
+func (a *authenticator) assert(challenge []byte, origin, rpID string) []byte {
+	a.signCount++
+	return a.signedAssertion(challenge, origin, rpID, false)
+}
+
+func (a *authenticator) signedAssertion(challenge []byte, origin, rpID string, tamper bool) []byte {
+	clientData := a.clientData("webauthn.get", challenge, origin)
+	authData := a.authData(rpID, false)
+	clientDataHash := sha256.Sum256(clientData)
+	digest := sha256.Sum256(append(append([]byte(nil), authData...), clientDataHash[:]...))
+	r, s, err := ecdsa.Sign(rand.Reader, a.key, digest[:])
+	if err != nil {
+		a.t.Fatal(err)
+	}
+	sig, err := asn1.Marshal(struct{ R, S *big.Int }{r, s})
+	if err != nil {
+		a.t.Fatal(err)
+	}
+	if tamper {
+		authData[len(authData)-1]++
+	}
+	return a.credential(map[string][]byte{
+		"clientDataJSON":    clientData,
+		"authenticatorData": authData,
+		"signature":         sig,
+	})
+}
+
+func TestSecurityKeys(t *testing.T) {
+	defer func(c cache) { webAuthnSessions = c }(webAuthnSessions)
+	webAuthnSessions = mapCache{}
+	defer func(u *url.URL) { globals.SetExternalURL(u) }(globals.ExternalURL())
+	globals.SetExternalURL(&url.URL{Scheme: "https", Host: testRPID})
+	defer func() { db.Mocks = db.MockStores{} }()
+
+	ctx := context.Background()
+	user := &types.User{ID: 1, Username: "alice"}
+	var stored []*db.WebAuthnCredential
+	db.Mocks.UserMFA.ListWebAuthnCredentials = func(userID int32) ([]*db.WebAuthnCredential, error) {
+		return stored, nil
+	}
+	db.Mocks.UserMFA.UpdateWebAuthnCredentialSignCount = func(id int32, oldSignCount, newSignCount uint32) (bool, error) {
+		for _, c := range stored {
+			if c.ID == id && c.SignCount == oldSignCount {
+				c.SignCount = newSignCount
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+
+	a := newAuthenticator(t, "credential-1")
+
+	// Registration.
+	beginRegistration := func() []byte {
+		t.Helper()
+		options, err := BeginSecurityKeyRegistration(ctx, user)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return options.(protocol.PublicKeyCredentialCreationOptions).Challenge
+	}
+	for name, register := range map[string]func(challenge []byte) []byte{
+		"wrong challenge": func([]byte) []byte { return a.register([]byte("other"), testOrigin, testRPID) },
+		"wrong origin":    func(c []byte) []byte { return a.register(c, "https://evil.example.com", testRPID) },
+		"wrong RP ID":     func(c []byte) []byte { return a.register(c, testOrigin, "evil.example.com") },
+	} {
+		if _, err := FinishSecurityKeyRegistration(ctx, user, register(beginRegistration())); err == nil {
+			t.Errorf("registration with %s: expected an error", name)
+		}
+	}
+	if _, err := FinishSecurityKeyRegistration(ctx, user, a.register([]byte("x"), testOrigin, testRPID)); err == nil {
+		t.Error("registration without a pending challenge: expected an error")
+	}
+	cred, err := FinishSecurityKeyRegistration(ctx, user, a.register(beginRegistration(), testOrigin, testRPID))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(cred.CredentialID, a.id) || cred.UserID != user.ID {
+		t.Errorf("got credential %q of user %d, want %q of user %d", cred.CredentialID, cred.UserID, a.id, user.ID)
+	}
+	cred.ID = 1
+	stored = []*db.WebAuthnCredential{cred}
+
+	// Sign-in.
+	beginSignIn := func() []byte {
+		t.Helper()
+		options, err := beginSecurityKeySignIn(user, stored)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return options.(protocol.PublicKeyCredentialRequestOptions).Challenge
+	}
+	verify := func(response []byte) bool {
+		t.Helper()
+		ok, err := verifySecurityKey(ctx, user, response)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return ok
+	}
+	other := newAuthenticator(t, "credential-2")
+	for name, assert := range map[string]func(challenge []byte) []byte{
+		"wrong challenge":    func([]byte) []byte { return a.assert([]byte("other"), testOrigin, testRPID) },
+		"wrong origin":       func(c []byte) []byte { return a.assert(c, "https://evil.example.com", testRPID) },
+		"wrong RP ID":        func(c []byte) []byte { return a.assert(c, testOrigin, "evil.example.com") },
+		"tampered signature": func(c []byte) []byte { return a.signedAssertion(c, testOrigin, testRPID, true) },
+		"unknown credential": func(c []byte) []byte { return other.assert(c, testOrigin, testRPID) },
+		"malformed response": func([]byte) []byte { return []byte(`{}`) },
+	} {
+		if verify(assert(beginSignIn())) {
+			t.Errorf("sign-in with %s: expected it to fail", name)
+		}
+	}
+	if verify(a.assert([]byte("x"), testOrigin, testRPID)) {
+		t.Error("sign-in without a pending challenge: expected it to fail")
+	}
+
+	challenge := beginSignIn()
+	response := a.assert(challenge, testOrigin, testRPID)
+	if !verify(response) {
+		t.Fatal("valid sign-in failed")
+	}
+	if stored[0].SignCount != a.signCount {
+		t.Errorf("got sign count %d, want %d", stored[0].SignCount, a.signCount)
+	}
+
+	// The challenge can only be used once.
+	if verify(response) {
+		t.Error("replayed sign-in: expected it to fail")
+	}
+
+	// An assertion from a cloned security key doesn't increase the counter.
+	clone := *a
+	clone.signCount--
+	if verify(clone.assert(beginSignIn(), testOrigin, testRPID)) {
+		t.Error("sign-in with a cloned security key: expected it to fail")
+	}
+}
@@ -0,0 +1,25 @@
+package bg
+
+import (
+	"context"
+	"time"
+
+	"github.com/inconshreveable/log15"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+)
+
+// DeleteOldAuditLogsInPostgres deletes the audit log entries that are older than the retention
+// period configured in the site configuration. Entries are kept forever if no retention period is
+// configured.
+func DeleteOldAuditLogsInPostgres(ctx context.Context) {
+	for {
+		if cfg := conf.Get().AuditLog; cfg != nil && cfg.RetentionDays > 0 {
+			_, err := db.AuditLogs.DeleteOlderThan(ctx, time.Now().AddDate(0, 0, -cfg.RetentionDays))
+			if err != nil {
+				log15.Error("deleting expired rows from audit_logs table", "error", err)
+			}
+		}
+		time.Sleep(time.Hour)
+	}
+}
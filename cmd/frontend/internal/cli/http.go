@@ -8,7 +8,9 @@ import (
 	gcontext "github.com/gorilla/context"
 	"github.com/gorilla/mux"
 	"github.com/graph-gophers/graphql-go"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/audit"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/auth"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/authz"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/globals"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/hooks"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/httpapi"
@@ -53,8 +55,10 @@ func newExternalHTTPHandler(schema *graphql.Schema, githubWebhook, bitbucketServ
 	appHandler = handlerutil.CSRFMiddleware(appHandler, func() bool {
 		return globals.ExternalURL().Scheme == "https"
 	}) // after appAuthMiddleware because SAML IdP posts data to us w/o a CSRF token
-	appHandler = authMiddlewares.App(appHandler)                       // 🚨 SECURITY: auth middleware
-	appHandler = session.CookieMiddleware(appHandler)                  // app accepts cookies
+	appHandler = authMiddlewares.App(appHandler)      // 🚨 SECURITY: auth middleware
+	appHandler = session.CookieMiddleware(appHandler) // app accepts cookies
+	// 🚨 SECURITY: Restricted access tokens can only view pages if they have the repo:read scope.
+	appHandler = internalhttpapi.AccessTokenScopeMiddleware(appHandler, authz.ScopeRepoRead)
 	appHandler = internalhttpapi.AccessTokenAuthMiddleware(appHandler) // app accepts access tokens
 	if hooks.PostAuthMiddleware != nil {
 		// 🚨 SECURITY: These all run after the auth handler so the client is authenticated.
@@ -74,6 +78,7 @@ func newExternalHTTPHandler(schema *graphql.Schema, githubWebhook, bitbucketServ
 	// 🚨 SECURITY: Auth middleware that must run before other auth middlewares.
 	h = internalauth.OverrideAuthMiddleware(h)
 	h = internalauth.ForbidAllRequestsMiddleware(h)
+	h = audit.Middleware(h)
 	h = tracepkg.HTTPTraceMiddleware(h)
 	h = ot.Middleware(h)
 	h = middleware.SourcegraphComGoGetHandler(h)
@@ -1,15 +1,20 @@
 package httpapi
 
 import (
+	"net"
 	"net/http"
+	"strconv"
+	"strings"
 
 	"github.com/inconshreveable/log15"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/audit"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/authz"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
 	"github.com/sourcegraph/sourcegraph/internal/actor"
 	"github.com/sourcegraph/sourcegraph/internal/conf"
 	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	"github.com/sourcegraph/sourcegraph/internal/httputil"
 )
 
 // AccessTokenAuthMiddleware authenticates the user based on the
@@ -61,18 +66,31 @@ func AccessTokenAuthMiddleware(next http.Handler) http.Handler {
 			//
 			// 🚨 SECURITY: It's important we check for the correct scopes to know what this token
 			// is allowed to do.
-			var requiredScope string
+			t, err := db.AccessTokens.Lookup(r.Context(), token)
+			if err != nil {
+				log15.Error("Invalid access token.", "token", token, "err", err)
+				http.Error(w, "Invalid access token.", http.StatusUnauthorized)
+				return
+			}
+			var requiredScopes []string
 			if sudoUser == "" {
-				requiredScope = authz.ScopeUserAll
+				requiredScopes = append([]string{authz.ScopeUserAll}, authz.FineGrainedScopes...)
 			} else {
-				requiredScope = authz.ScopeSiteAdminSudo
+				requiredScopes = []string{authz.ScopeSiteAdminSudo}
 			}
-			subjectUserID, err := db.AccessTokens.Lookup(r.Context(), token, requiredScope)
-			if err != nil {
-				log15.Error("Invalid access token.", "token", token, "err", err)
+			if !hasAnyScope(t.Scopes, requiredScopes...) {
+				log15.Error("Access token is missing required scope.", "tokenID", t.ID, "requiredScopes", requiredScopes)
 				http.Error(w, "Invalid access token.", http.StatusUnauthorized)
 				return
 			}
+			subjectUserID := t.SubjectUserID
+
+			// 🚨 SECURITY: Access tokens with an IP allowlist may only be used from those IPs.
+			if clientIP := httputil.ClientIP(r); !t.AllowsIP(net.ParseIP(clientIP)) {
+				log15.Error("Access token used from a disallowed IP address.", "tokenID", t.ID, "clientIP", clientIP)
+				http.Error(w, "Access token is not allowed from this IP address.", http.StatusForbidden)
+				return
+			}
 
 			// Determine the actor's user ID.
 			var actorUserID int32
@@ -103,11 +121,55 @@ func AccessTokenAuthMiddleware(next http.Handler) http.Handler {
 				}
 				actorUserID = user.ID
 				log15.Debug("HTTP request used sudo token.", "requestURI", r.URL.RequestURI(), "tokenSubjectUserID", subjectUserID, "actorUserID", actorUserID, "actorUsername", user.Username)
+
+				// Record that the token's subject impersonated the user (not the other way around).
+				sudoCtx := actor.WithActor(r.Context(), &actor.Actor{UID: subjectUserID})
+				audit.Log(sudoCtx, audit.ActionUserSudo, audit.TargetUser, strconv.Itoa(int(user.ID)), nil, nil)
 			}
 
-			r = r.WithContext(actor.WithActor(r.Context(), &actor.Actor{UID: actorUserID}))
+			a := &actor.Actor{UID: actorUserID}
+
+			// 🚨 SECURITY: Restrict the actor to the scopes and repositories of the token, unless
+			// it grants full control of the user account.
+			if !hasAnyScope(t.Scopes, authz.ScopeUserAll) {
+				a.Scopes = t.Scopes
+			}
+			a.RepoPattern, err = t.RepoPattern()
+			if err != nil {
+				log15.Error("Invalid repository pattern in access token.", "tokenID", t.ID, "err", err)
+				http.Error(w, "Invalid access token.", http.StatusUnauthorized)
+				return
+			}
+
+			r = r.WithContext(actor.WithActor(r.Context(), a))
 		}
 
 		next.ServeHTTP(w, r)
 	})
 }
+
+// AccessTokenScopeMiddleware responds with an HTTP 403 error if the request was authenticated with
+// an access token that is restricted to scopes other than the given ones.
+func AccessTokenScopeMiddleware(next http.Handler, scopes ...string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		a := actor.FromContext(r.Context())
+		for _, scope := range scopes {
+			if a.HasScope(scope) {
+				next.ServeHTTP(w, r)
+				return
+			}
+		}
+		http.Error(w, "Access token must have one of the scopes "+strings.Join(scopes, ", ")+".", http.StatusForbidden)
+	})
+}
+
+func hasAnyScope(have []string, scopes ...string) bool {
+	for _, h := range have {
+		for _, s := range scopes {
+			if h == s {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -9,11 +9,14 @@ import (
 	"net/url"
 	"testing"
 
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/audit"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/authz"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
 	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
 	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	"github.com/sourcegraph/sourcegraph/schema"
 )
 
 func TestAccessTokenAuthMiddleware(t *testing.T) {
@@ -69,9 +72,9 @@ func TestAccessTokenAuthMiddleware(t *testing.T) {
 		req, _ := http.NewRequest("GET", "/", nil)
 		req.Header.Set("Authorization", "token badbad")
 		var calledAccessTokensLookup bool
-		db.Mocks.AccessTokens.Lookup = func(tokenHexEncoded, requiredScope string) (subjectUserID int32, err error) {
+		db.Mocks.AccessTokens.Lookup = func(tokenHexEncoded string) (*db.AccessToken, error) {
 			calledAccessTokensLookup = true
-			return 0, errors.New("x")
+			return nil, errors.New("x")
 		}
 		defer func() { db.Mocks = db.MockStores{} }()
 		checkHTTPResponse(t, req, http.StatusUnauthorized, "Invalid access token.\n")
@@ -85,15 +88,12 @@ func TestAccessTokenAuthMiddleware(t *testing.T) {
 			req, _ := http.NewRequest("GET", "/", nil)
 			req.Header.Set("Authorization", headerValue)
 			var calledAccessTokensLookup bool
-			db.Mocks.AccessTokens.Lookup = func(tokenHexEncoded, requiredScope string) (subjectUserID int32, err error) {
+			db.Mocks.AccessTokens.Lookup = func(tokenHexEncoded string) (*db.AccessToken, error) {
 				calledAccessTokensLookup = true
 				if want := "abcdef"; tokenHexEncoded != want {
 					t.Errorf("got %q, want %q", tokenHexEncoded, want)
 				}
-				if want := authz.ScopeUserAll; requiredScope != want {
-					t.Errorf("got %q, want %q", requiredScope, want)
-				}
-				return 123, nil
+				return &db.AccessToken{SubjectUserID: 123, Scopes: []string{authz.ScopeUserAll}}, nil
 			}
 			defer func() { db.Mocks = db.MockStores{} }()
 			checkHTTPResponse(t, req, http.StatusOK, "user 123")
@@ -109,15 +109,12 @@ func TestAccessTokenAuthMiddleware(t *testing.T) {
 		req.Header.Set("Authorization", "token abcdef")
 		req = req.WithContext(actor.WithActor(context.Background(), &actor.Actor{UID: 456}))
 		var calledAccessTokensLookup bool
-		db.Mocks.AccessTokens.Lookup = func(tokenHexEncoded, requiredScope string) (subjectUserID int32, err error) {
+		db.Mocks.AccessTokens.Lookup = func(tokenHexEncoded string) (*db.AccessToken, error) {
 			calledAccessTokensLookup = true
 			if want := "abcdef"; tokenHexEncoded != want {
 				t.Errorf("got %q, want %q", tokenHexEncoded, want)
 			}
-			if want := authz.ScopeUserAll; requiredScope != want {
-				t.Errorf("got %q, want %q", requiredScope, want)
-			}
-			return 123, nil
+			return &db.AccessToken{SubjectUserID: 123, Scopes: []string{authz.ScopeUserAll}}, nil
 		}
 		defer func() { db.Mocks = db.MockStores{} }()
 		checkHTTPResponse(t, req, http.StatusOK, "user 123")
@@ -143,15 +140,12 @@ func TestAccessTokenAuthMiddleware(t *testing.T) {
 			}
 			req = req.WithContext(actor.WithActor(context.Background(), &actor.Actor{UID: 456}))
 			var calledAccessTokensLookup bool
-			db.Mocks.AccessTokens.Lookup = func(tokenHexEncoded, requiredScope string) (subjectUserID int32, err error) {
+			db.Mocks.AccessTokens.Lookup = func(tokenHexEncoded string) (*db.AccessToken, error) {
 				calledAccessTokensLookup = true
 				if want := "abcdef"; tokenHexEncoded != want {
 					t.Errorf("got %q, want %q", tokenHexEncoded, want)
 				}
-				if want := authz.ScopeUserAll; requiredScope != want {
-					t.Errorf("got %q, want %q", requiredScope, want)
-				}
-				return 123, nil
+				return &db.AccessToken{SubjectUserID: 123, Scopes: []string{authz.ScopeUserAll}}, nil
 			}
 			defer func() { db.Mocks = db.MockStores{} }()
 			checkHTTPResponse(t, req, http.StatusOK, "user 123")
@@ -165,15 +159,12 @@ func TestAccessTokenAuthMiddleware(t *testing.T) {
 		req, _ := http.NewRequest("GET", "/", nil)
 		req.Header.Set("Authorization", `token-sudo token="abcdef",user="alice"`)
 		var calledAccessTokensLookup bool
-		db.Mocks.AccessTokens.Lookup = func(tokenHexEncoded, requiredScope string) (subjectUserID int32, err error) {
+		db.Mocks.AccessTokens.Lookup = func(tokenHexEncoded string) (*db.AccessToken, error) {
 			calledAccessTokensLookup = true
 			if want := "abcdef"; tokenHexEncoded != want {
 				t.Errorf("got %q, want %q", tokenHexEncoded, want)
 			}
-			if want := authz.ScopeSiteAdminSudo; requiredScope != want {
-				t.Errorf("got %q, want %q", requiredScope, want)
-			}
-			return 123, nil
+			return &db.AccessToken{SubjectUserID: 123, Scopes: []string{authz.ScopeUserAll, authz.ScopeSiteAdminSudo}}, nil
 		}
 		var calledUsersGetByID bool
 		db.Mocks.Users.GetByID = func(ctx context.Context, userID int32) (*types.User, error) {
@@ -191,6 +182,11 @@ func TestAccessTokenAuthMiddleware(t *testing.T) {
 			}
 			return &types.User{ID: 456, SiteAdmin: true}, nil
 		}
+		var auditLogEntry *db.AuditLogEntry
+		db.Mocks.AuditLogs.Create = func(e *db.AuditLogEntry) error {
+			auditLogEntry = e
+			return nil
+		}
 		defer func() { db.Mocks = db.MockStores{} }()
 		checkHTTPResponse(t, req, http.StatusOK, "user 456")
 		if !calledAccessTokensLookup {
@@ -202,6 +198,12 @@ func TestAccessTokenAuthMiddleware(t *testing.T) {
 		if !calledUsersGetByUsername {
 			t.Error("!calledUsersGetByUsername")
 		}
+		if auditLogEntry == nil {
+			t.Fatal("sudo was not recorded in the audit log")
+		}
+		if auditLogEntry.Action != audit.ActionUserSudo || auditLogEntry.ActorUserID != 123 || auditLogEntry.TargetID != "456" {
+			t.Errorf("unexpected audit log entry %+v", auditLogEntry)
+		}
 	})
 
 	// Test that if a sudo token's subject user is not a site admin (which means they were demoted
@@ -210,15 +212,12 @@ func TestAccessTokenAuthMiddleware(t *testing.T) {
 		req, _ := http.NewRequest("GET", "/", nil)
 		req.Header.Set("Authorization", `token-sudo token="abcdef",user="alice"`)
 		var calledAccessTokensLookup bool
-		db.Mocks.AccessTokens.Lookup = func(tokenHexEncoded, requiredScope string) (subjectUserID int32, err error) {
+		db.Mocks.AccessTokens.Lookup = func(tokenHexEncoded string) (*db.AccessToken, error) {
 			calledAccessTokensLookup = true
 			if want := "abcdef"; tokenHexEncoded != want {
 				t.Errorf("got %q, want %q", tokenHexEncoded, want)
 			}
-			if want := authz.ScopeSiteAdminSudo; requiredScope != want {
-				t.Errorf("got %q, want %q", requiredScope, want)
-			}
-			return 123, nil
+			return &db.AccessToken{SubjectUserID: 123, Scopes: []string{authz.ScopeUserAll, authz.ScopeSiteAdminSudo}}, nil
 		}
 		var calledUsersGetByID bool
 		db.Mocks.Users.GetByID = func(ctx context.Context, userID int32) (*types.User, error) {
@@ -242,15 +241,12 @@ func TestAccessTokenAuthMiddleware(t *testing.T) {
 		req, _ := http.NewRequest("GET", "/", nil)
 		req.Header.Set("Authorization", `token-sudo token="abcdef",user="doesntexist"`)
 		var calledAccessTokensLookup bool
-		db.Mocks.AccessTokens.Lookup = func(tokenHexEncoded, requiredScope string) (subjectUserID int32, err error) {
+		db.Mocks.AccessTokens.Lookup = func(tokenHexEncoded string) (*db.AccessToken, error) {
 			calledAccessTokensLookup = true
 			if want := "abcdef"; tokenHexEncoded != want {
 				t.Errorf("got %q, want %q", tokenHexEncoded, want)
 			}
-			if want := authz.ScopeSiteAdminSudo; requiredScope != want {
-				t.Errorf("got %q, want %q", requiredScope, want)
-			}
-			return 123, nil
+			return &db.AccessToken{SubjectUserID: 123, Scopes: []string{authz.ScopeUserAll, authz.ScopeSiteAdminSudo}}, nil
 		}
 		var calledUsersGetByID bool
 		db.Mocks.Users.GetByID = func(ctx context.Context, userID int32) (*types.User, error) {
@@ -281,3 +277,135 @@ func TestAccessTokenAuthMiddleware(t *testing.T) {
 		}
 	})
 }
+
+func TestAccessTokenAuthMiddleware_restrictions(t *testing.T) {
+	handler := AccessTokenAuthMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		actor := actor.FromContext(r.Context())
+		fmt.Fprintf(w, "user %v, scopes %q, repo pattern %v", actor.UID, actor.Scopes, actor.RepoPattern)
+	}))
+	checkHTTPResponse := func(t *testing.T, req *http.Request, wantStatusCode int, wantBody string) {
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		if rr.Code != wantStatusCode {
+			t.Errorf("got response status %d, want %d", rr.Code, wantStatusCode)
+		}
+		if got := rr.Body.String(); got != wantBody {
+			t.Errorf("got response body %q, want %q", got, wantBody)
+		}
+	}
+	mockAccessTokensLookup := func(token *db.AccessToken) {
+		db.Mocks.AccessTokens.Lookup = func(tokenHexEncoded string) (*db.AccessToken, error) {
+			return token, nil
+		}
+	}
+
+	t.Run("user:all token is not restricted", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/", nil)
+		req.Header.Set("Authorization", "token abcdef")
+		mockAccessTokensLookup(&db.AccessToken{SubjectUserID: 123, Scopes: []string{authz.ScopeUserAll}})
+		defer func() { db.Mocks = db.MockStores{} }()
+		checkHTTPResponse(t, req, http.StatusOK, `user 123, scopes [], repo pattern <nil>`)
+	})
+
+	t.Run("fine-grained token is restricted to its scopes and repositories", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/", nil)
+		req.Header.Set("Authorization", "token abcdef")
+		mockAccessTokensLookup(&db.AccessToken{
+			SubjectUserID:           123,
+			Scopes:                  []string{authz.ScopeSearchRead},
+			AccessTokenRestrictions: db.AccessTokenRestrictions{RepoPatterns: []string{"a", "b"}},
+		})
+		defer func() { db.Mocks = db.MockStores{} }()
+		checkHTTPResponse(t, req, http.StatusOK, `user 123, scopes ["search:read"], repo pattern ^(?:a)$|^(?:b)$`)
+	})
+
+	t.Run("token without known scopes", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/", nil)
+		req.Header.Set("Authorization", "token abcdef")
+		mockAccessTokensLookup(&db.AccessToken{SubjectUserID: 123, Scopes: []string{"x"}})
+		defer func() { db.Mocks = db.MockStores{} }()
+		checkHTTPResponse(t, req, http.StatusUnauthorized, "Invalid access token.\n")
+	})
+
+	t.Run("sudo with token without sudo scope", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/", nil)
+		req.Header.Set("Authorization", `token-sudo token="abcdef",user="alice"`)
+		mockAccessTokensLookup(&db.AccessToken{SubjectUserID: 123, Scopes: []string{authz.ScopeUserAll}})
+		defer func() { db.Mocks = db.MockStores{} }()
+		checkHTTPResponse(t, req, http.StatusUnauthorized, "Invalid access token.\n")
+	})
+
+	for remoteAddr, wantStatusCode := range map[string]int{
+		"10.1.2.3:1234":  http.StatusOK,
+		"[::1]:1234":     http.StatusForbidden,
+		"192.168.0.1:80": http.StatusForbidden,
+	} {
+		t.Run("IP allowlist "+remoteAddr, func(t *testing.T) {
+			req, _ := http.NewRequest("GET", "/", nil)
+			req.RemoteAddr = remoteAddr
+			req.Header.Set("Authorization", "token abcdef")
+			mockAccessTokensLookup(&db.AccessToken{
+				SubjectUserID:           123,
+				Scopes:                  []string{authz.ScopeUserAll},
+				AccessTokenRestrictions: db.AccessTokenRestrictions{AllowedIPs: []string{"10.0.0.0/8"}},
+			})
+			defer func() { db.Mocks = db.MockStores{} }()
+			wantBody := "Access token is not allowed from this IP address.\n"
+			if wantStatusCode == http.StatusOK {
+				wantBody = `user 123, scopes [], repo pattern <nil>`
+			}
+			checkHTTPResponse(t, req, wantStatusCode, wantBody)
+		})
+	}
+
+	t.Run("IP allowlist behind a trusted proxy", func(t *testing.T) {
+		conf.Mock(&conf.Unified{SiteConfiguration: schema.SiteConfiguration{TrustedProxies: []string{"192.168.0.1"}}})
+		defer conf.Mock(nil)
+
+		mockAccessTokensLookup(&db.AccessToken{
+			SubjectUserID:           123,
+			Scopes:                  []string{authz.ScopeUserAll},
+			AccessTokenRestrictions: db.AccessTokenRestrictions{AllowedIPs: []string{"10.0.0.0/8"}},
+		})
+		defer func() { db.Mocks = db.MockStores{} }()
+
+		req, _ := http.NewRequest("GET", "/", nil)
+		req.RemoteAddr = "192.168.0.1:80"
+		req.Header.Set("X-Forwarded-For", "10.1.2.3")
+		req.Header.Set("Authorization", "token abcdef")
+		checkHTTPResponse(t, req, http.StatusOK, `user 123, scopes [], repo pattern <nil>`)
+
+		req, _ = http.NewRequest("GET", "/", nil)
+		req.RemoteAddr = "192.168.0.2:80"
+		req.Header.Set("X-Forwarded-For", "10.1.2.3")
+		req.Header.Set("Authorization", "token abcdef")
+		checkHTTPResponse(t, req, http.StatusForbidden, "Access token is not allowed from this IP address.\n")
+	})
+}
+
+func TestAccessTokenScopeMiddleware(t *testing.T) {
+	handler := AccessTokenScopeMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "ok")
+	}), authz.ScopeSearchRead, authz.ScopeRepoRead)
+
+	for _, tc := range []struct {
+		name           string
+		actor          *actor.Actor
+		wantStatusCode int
+	}{
+		{name: "anonymous", actor: &actor.Actor{}, wantStatusCode: http.StatusOK},
+		{name: "unrestricted", actor: &actor.Actor{UID: 1}, wantStatusCode: http.StatusOK},
+		{name: "restricted with scope", actor: &actor.Actor{UID: 1, Scopes: []string{authz.ScopeRepoRead}}, wantStatusCode: http.StatusOK},
+		{name: "restricted without scope", actor: &actor.Actor{UID: 1, Scopes: []string{authz.ScopeCodeIntelUpload}}, wantStatusCode: http.StatusForbidden},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			req, _ := http.NewRequest("GET", "/", nil)
+			req = req.WithContext(actor.WithActor(context.Background(), tc.actor))
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+			if rr.Code != tc.wantStatusCode {
+				t.Errorf("got response status %d, want %d", rr.Code, tc.wantStatusCode)
+			}
+		})
+	}
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/graph-gophers/graphql-go"
 	"github.com/inconshreveable/log15"
 	opentracing "github.com/opentracing/opentracing-go"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/authz"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/envvar"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/httpapi"
@@ -44,9 +45,12 @@ func NewHandler(m *mux.Router, schema *graphql.Schema, githubWebhook, bitbucketS
 	})
 
 	// Set handlers for the installed routes.
-	m.Get(apirouter.RepoShield).Handler(trace.TraceRoute(handler(serveRepoShield)))
+	//
+	// 🚨 SECURITY: Routes that require specific scopes of restricted access tokens are wrapped in
+	// AccessTokenScopeMiddleware.
+	m.Get(apirouter.RepoShield).Handler(trace.TraceRoute(AccessTokenScopeMiddleware(handler(serveRepoShield), authz.ScopeRepoRead)))
 
-	m.Get(apirouter.RepoRefresh).Handler(trace.TraceRoute(handler(serveRepoRefresh)))
+	m.Get(apirouter.RepoRefresh).Handler(trace.TraceRoute(AccessTokenScopeMiddleware(handler(serveRepoRefresh), authz.ScopeRepoRead)))
 
 	if githubWebhook != nil {
 		m.Get(apirouter.GitHubWebhooks).Handler(trace.TraceRoute(githubWebhook))
@@ -60,10 +64,11 @@ func NewHandler(m *mux.Router, schema *graphql.Schema, githubWebhook, bitbucketS
 		m.Path("/updates").Methods("GET", "POST").Name("updatecheck").Handler(trace.TraceRoute(http.HandlerFunc(updatecheck.Handler)))
 	}
 
-	m.Get(apirouter.GraphQL).Handler(trace.TraceRoute(handler(serveGraphQL(schema))))
+	// Individual resolvers check the scopes they require.
+	m.Get(apirouter.GraphQL).Handler(trace.TraceRoute(AccessTokenScopeMiddleware(handler(serveGraphQL(schema)), authz.ScopeSearchRead, authz.ScopeRepoRead, authz.ScopeCampaignsWrite)))
 
 	if lsifServerProxy != nil {
-		m.Get(apirouter.LSIFUpload).Handler(trace.TraceRoute(lsifServerProxy.UploadHandler))
+		m.Get(apirouter.LSIFUpload).Handler(trace.TraceRoute(AccessTokenScopeMiddleware(lsifServerProxy.UploadHandler, authz.ScopeCodeIntelUpload)))
 	} else {
 		m.Get(apirouter.LSIFUpload).Handler(trace.TraceRoute(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			w.WriteHeader(http.StatusNotFound)
@@ -71,6 +76,12 @@ func NewHandler(m *mux.Router, schema *graphql.Schema, githubWebhook, bitbucketS
 		})))
 	}
 
+	if httpapi.SCIMHandler != nil {
+		// 🚨 SECURITY: The SCIM handler only allows site admins. Restricted access tokens can't
+		// provision users.
+		m.Get(apirouter.SCIM).Handler(trace.TraceRoute(AccessTokenScopeMiddleware(http.StripPrefix("/.api/scim/v2", httpapi.SCIMHandler), authz.ScopeUserAll)))
+	}
+
 	// Return the minimum src-cli version that's compatible with this instance
 	m.Get(apirouter.SrcCliVersion).Handler(trace.TraceRoute(handler(srcCliVersionServe)))
 	m.Get(apirouter.SrcCliDownload).Handler(trace.TraceRoute(handler(srcCliDownloadServe)))
@@ -19,6 +19,8 @@ const (
 	RepoRefresh = "repo.refresh"
 	Telemetry   = "telemetry"
 
+	SCIM = "scim"
+
 	GitHubWebhooks          = "github.webhooks"
 	BitbucketServerWebhooks = "bitbucketServer.webhooks"
 
@@ -65,6 +67,7 @@ func New(base *mux.Router) *mux.Router {
 	base.Path("/github-webhooks").Methods("POST").Name(GitHubWebhooks)
 	base.Path("/bitbucket-server-webhooks").Methods("POST").Name(BitbucketServerWebhooks)
 	base.Path("/lsif/upload").Methods("POST").Name(LSIFUpload)
+	base.PathPrefix("/scim/v2/").Name(SCIM)
 	base.Path("/src-cli/version").Methods("GET").Name(SrcCliVersion)
 	base.Path("/src-cli/{rest:.*}").Methods("GET").Name(SrcCliDownload)
 
@@ -3,6 +3,8 @@ package session
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -16,6 +18,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/conf"
 	"github.com/sourcegraph/sourcegraph/internal/env"
 	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	"github.com/sourcegraph/sourcegraph/internal/httputil"
 	"github.com/sourcegraph/sourcegraph/internal/redispool"
 
 	"github.com/inconshreveable/log15"
@@ -33,6 +36,14 @@ const defaultExpiryPeriod = 90 * 24 * time.Hour
 // cookieName is the name of the HTTP cookie that stores the session ID.
 const cookieName = "sgs"
 
+// userSessions records sessions in the database. It is replaced by ResetMockSessionStore in tests.
+var userSessions interface {
+	Create(ctx context.Context, key string, s *db.UserSession) error
+	GetByKey(ctx context.Context, key string) (*db.UserSession, error)
+	Touch(ctx context.Context, id int64, ip, userAgent string, expiresAt time.Time) error
+	DeleteByKey(ctx context.Context, key string) error
+} = db.UserSessions
+
 func init() {
 	conf.ContributeValidator(func(c conf.Unified) (problems conf.Problems) {
 		if c.AuthSessionExpiry == "" {
@@ -48,6 +59,20 @@ func init() {
 		}
 		return nil
 	})
+	conf.ContributeValidator(func(c conf.Unified) (problems conf.Problems) {
+		if c.AuthSessionAbsoluteExpiry == "" {
+			return nil
+		}
+
+		d, err := time.ParseDuration(c.AuthSessionAbsoluteExpiry)
+		if err != nil {
+			return conf.NewSiteProblems("auth.sessionAbsoluteExpiry does not conform to the Go time.Duration format (https://golang.org/pkg/time/#ParseDuration). Sessions will only expire after a period of inactivity.")
+		}
+		if d <= 0 {
+			return conf.NewSiteProblems("auth.sessionAbsoluteExpiry should be greater than zero. Sessions will only expire after a period of inactivity.")
+		}
+		return nil
+	})
 }
 
 // sessionInfo is the information we store in the session. The gorilla/sessions library doesn't appear to
@@ -56,6 +81,24 @@ type sessionInfo struct {
 	Actor        *actor.Actor  `json:"actor"`
 	LastActive   time.Time     `json:"lastActive"`
 	ExpiryPeriod time.Duration `json:"expiryPeriod"`
+
+	// Key identifies the session recorded in the database (see db.UserSessions), which users and
+	// site admins can list and revoke. It is empty for sessions that were created before sessions
+	// were recorded.
+	Key       string    `json:"key,omitempty"`
+	CreatedAt time.Time `json:"createdAt,omitempty"`
+}
+
+// expiresAt returns when the session expires, either after the expiry period of inactivity or after
+// the absolute session expiry (auth.sessionAbsoluteExpiry) since it was created, whichever is first.
+func (info *sessionInfo) expiresAt() time.Time {
+	expiresAt := info.LastActive.Add(info.ExpiryPeriod)
+	if d, err := time.ParseDuration(conf.Get().AuthSessionAbsoluteExpiry); err == nil && d > 0 && !info.CreatedAt.IsZero() {
+		if absolute := info.CreatedAt.Add(d); absolute.Before(expiresAt) {
+			expiresAt = absolute
+		}
+	}
+	return expiresAt
 }
 
 // SetSessionStore sets the backing store used for storing sessions on the server. It should be called exactly once.
@@ -222,6 +265,16 @@ func GetData(r *http.Request, key string, value interface{}) error {
 //
 // If expiryPeriod is 0, the default expiry period is used.
 func SetActor(w http.ResponseWriter, r *http.Request, actor *actor.Actor, expiryPeriod time.Duration) error {
+	// Revoke the recorded session (if any) that the actor is replacing, e.g. when signing out.
+	if hasSessionCookie(r) {
+		var current *sessionInfo
+		if err := GetData(r, "actor", &current); err == nil && current != nil && current.Key != "" {
+			if err := userSessions.DeleteByKey(r.Context(), current.Key); err != nil {
+				return errors.WithMessage(err, "revoking session")
+			}
+		}
+	}
+
 	var value *sessionInfo
 	if actor != nil {
 		if expiryPeriod == 0 {
@@ -231,11 +284,36 @@ func SetActor(w http.ResponseWriter, r *http.Request, actor *actor.Actor, expiry
 				expiryPeriod = defaultExpiryPeriod
 			}
 		}
-		value = &sessionInfo{Actor: actor, ExpiryPeriod: expiryPeriod, LastActive: time.Now()}
+		now := time.Now()
+		value = &sessionInfo{Actor: actor, ExpiryPeriod: expiryPeriod, LastActive: now, CreatedAt: now}
+		if _, err := recordSession(r, value); err != nil {
+			return err
+		}
 	}
 	return SetData(w, r, "actor", value)
 }
 
+// recordSession records the session in the database with a new random key, which it sets in info.
+func recordSession(r *http.Request, info *sessionInfo) (*db.UserSession, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return nil, errors.WithMessage(err, "generating session key")
+	}
+	key := hex.EncodeToString(b)
+
+	s := &db.UserSession{
+		UserID:    info.Actor.UID,
+		IP:        httputil.ClientIP(r),
+		UserAgent: r.UserAgent(),
+		ExpiresAt: info.expiresAt(),
+	}
+	if err := userSessions.Create(r.Context(), key, s); err != nil {
+		return nil, errors.WithMessage(err, "recording session")
+	}
+	info.Key = key
+	return s, nil
+}
+
 func hasSessionCookie(r *http.Request) bool {
 	c, _ := r.Cookie(cookieName)
 	return c != nil
@@ -338,13 +416,14 @@ func authenticateByCookie(r *http.Request, w http.ResponseWriter) context.Contex
 	}
 	if info != nil {
 		// Check expiry
-		if info.LastActive.Add(info.ExpiryPeriod).Before(time.Now()) {
+		if info.expiresAt().Before(time.Now()) {
 			_ = deleteSession(w, r) // clear the bad value
 			return actor.WithActor(r.Context(), &actor.Actor{})
 		}
 
 		// Check that user still exists.
-		if _, err := db.Users.GetByID(r.Context(), info.Actor.UID); err != nil {
+		user, err := db.Users.GetByID(r.Context(), info.Actor.UID)
+		if err != nil {
 			if errcode.IsNotFound(err) {
 				_ = deleteSession(w, r) // clear the bad value
 			} else {
@@ -355,9 +434,44 @@ func authenticateByCookie(r *http.Request, w http.ResponseWriter) context.Contex
 			return r.Context() // not authenticated
 		}
 
+		// 🚨 SECURITY: Deactivating a user revokes all of their sessions.
+		if user.DeactivatedAt != nil {
+			_ = deleteSession(w, r)
+			return r.Context() // not authenticated
+		}
+
+		// 🚨 SECURITY: Revoked sessions are no longer recorded.
+		var (
+			userSession *db.UserSession
+			renew       = time.Since(info.LastActive) > 5*time.Minute
+		)
+		if info.Key == "" {
+			// Record sessions that were created before sessions were recorded, so that they can be
+			// listed and revoked too.
+			info.CreatedAt = time.Now()
+			if userSession, err = recordSession(r, info); err != nil {
+				log15.Error("Error recording session.", "uid", info.Actor.UID, "error", err)
+				return r.Context() // not authenticated
+			}
+			renew = true
+		} else {
+			userSession, err = userSessions.GetByKey(r.Context(), info.Key)
+			if err == db.ErrUserSessionNotFound {
+				_ = deleteSession(w, r)
+				return r.Context() // not authenticated
+			} else if err != nil {
+				// Don't delete session, for the same reason as above.
+				log15.Error("Error looking up session.", "uid", info.Actor.UID, "error", err)
+				return r.Context() // not authenticated
+			}
+		}
+
 		// Renew session
-		if time.Since(info.LastActive) > 5*time.Minute {
+		if renew {
 			info.LastActive = time.Now()
+			if err := userSessions.Touch(r.Context(), userSession.ID, httputil.ClientIP(r), r.UserAgent(), info.expiresAt()); err != nil {
+				log15.Error("error renewing session", "error", err)
+			}
 			if err := SetData(w, r, "actor", info); err != nil {
 				log15.Error("error renewing session", "error", err)
 				return r.Context()
@@ -365,6 +479,7 @@ func authenticateByCookie(r *http.Request, w http.ResponseWriter) context.Contex
 		}
 
 		info.Actor.FromSessionCookie = true
+		info.Actor.SessionID = userSession.ID
 		return actor.WithActor(r.Context(), info.Actor)
 	}
 
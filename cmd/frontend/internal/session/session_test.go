@@ -13,7 +13,9 @@ import (
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
 	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
 	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	"github.com/sourcegraph/sourcegraph/schema"
 )
 
 func TestSetActorDeleteSession(t *testing.T) {
@@ -27,7 +29,7 @@ func TestSetActorDeleteSession(t *testing.T) {
 
 	// Start new session
 	w := httptest.NewRecorder()
-	actr := &actor.Actor{UID: 123, FromSessionCookie: true}
+	actr := &actor.Actor{UID: 123, FromSessionCookie: true, SessionID: 1}
 	if err := SetActor(w, httptest.NewRequest("GET", "/", nil), actr, 24*time.Hour); err != nil {
 		t.Fatal(err)
 	}
@@ -141,7 +143,7 @@ func TestSessionExpiry(t *testing.T) {
 
 	// Start new session
 	w := httptest.NewRecorder()
-	actr := &actor.Actor{UID: 123, FromSessionCookie: true}
+	actr := &actor.Actor{UID: 123, FromSessionCookie: true, SessionID: 1}
 	if err := SetActor(w, httptest.NewRequest("GET", "/", nil), actr, time.Second); err != nil {
 		t.Fatal(err)
 	}
@@ -174,7 +176,7 @@ func TestCookieMiddleware(t *testing.T) {
 	cleanup := ResetMockSessionStore(t)
 	defer cleanup()
 
-	actors := []*actor.Actor{{UID: 123, FromSessionCookie: true}, {UID: 456}, {UID: 789}}
+	actors := []*actor.Actor{{UID: 123, FromSessionCookie: true, SessionID: 1}, {UID: 456}, {UID: 789}}
 
 	db.Mocks.Users.GetByID = func(ctx context.Context, id int32) (*types.User, error) {
 		if id == actors[0].UID {
@@ -289,3 +291,132 @@ func TestRecoverFromInvalidCookieValue(t *testing.T) {
 		t.Errorf("got cookies %+v, want %+v", cookies, want)
 	}
 }
+
+func TestSessionRevocation(t *testing.T) {
+	cleanup := ResetMockSessionStore(t)
+	defer cleanup()
+
+	db.Mocks.Users.GetByID = func(ctx context.Context, id int32) (*types.User, error) {
+		return &types.User{ID: id}, nil
+	}
+	defer func() { db.Mocks = db.MockStores{} }()
+
+	newAuthedReq := func() *http.Request {
+		w := httptest.NewRecorder()
+		if err := SetActor(w, httptest.NewRequest("GET", "/", nil), &actor.Actor{UID: 123}, time.Hour); err != nil {
+			t.Fatal(err)
+		}
+		req := httptest.NewRequest("GET", "/", nil)
+		for _, cookie := range w.Result().Cookies() {
+			req.AddCookie(cookie)
+		}
+		return req
+	}
+	sessions := userSessions.(*mockUserSessions).sessions
+
+	// Revoking the recorded session signs the user out.
+	req := newAuthedReq()
+	if got := actor.FromContext(authenticateByCookie(req, httptest.NewRecorder())); got.UID != 123 || got.SessionID == 0 {
+		t.Fatalf("got actor %+v, want authenticated actor with session", got)
+	}
+	for key := range sessions {
+		delete(sessions, key)
+	}
+	w := httptest.NewRecorder()
+	if got := actor.FromContext(authenticateByCookie(req, w)); got.IsAuthenticated() {
+		t.Fatalf("got actor %+v, want unauthenticated actor after the session was revoked", got)
+	}
+	checkCookieDeleted(t, w.Result())
+
+	// Signing out revokes the recorded session.
+	req = newAuthedReq()
+	if len(sessions) != 1 {
+		t.Fatalf("got %d recorded sessions, want 1", len(sessions))
+	}
+	if err := SetActor(httptest.NewRecorder(), req, nil, 0); err != nil {
+		t.Fatal(err)
+	}
+	if len(sessions) != 0 {
+		t.Fatalf("got %d recorded sessions after signing out, want 0", len(sessions))
+	}
+}
+
+func TestSessionRecordsExistingSessions(t *testing.T) {
+	cleanup := ResetMockSessionStore(t)
+	defer cleanup()
+
+	db.Mocks.Users.GetByID = func(ctx context.Context, id int32) (*types.User, error) {
+		return &types.User{ID: id}, nil
+	}
+	defer func() { db.Mocks = db.MockStores{} }()
+
+	// A session created before sessions were recorded has no key.
+	w := httptest.NewRecorder()
+	info := &sessionInfo{Actor: &actor.Actor{UID: 123}, LastActive: time.Now(), ExpiryPeriod: time.Hour}
+	if err := SetData(w, httptest.NewRequest("GET", "/", nil), "actor", info); err != nil {
+		t.Fatal(err)
+	}
+	req := httptest.NewRequest("GET", "/", nil)
+	for _, cookie := range w.Result().Cookies() {
+		req.AddCookie(cookie)
+	}
+
+	if got := actor.FromContext(authenticateByCookie(req, httptest.NewRecorder())); got.UID != 123 || got.SessionID != 1 {
+		t.Fatalf("got actor %+v, want authenticated actor with session 1", got)
+	}
+	sessions := userSessions.(*mockUserSessions).sessions
+	if len(sessions) != 1 {
+		t.Fatalf("got %d recorded sessions, want 1", len(sessions))
+	}
+}
+
+func TestSessionRecordsClientIP(t *testing.T) {
+	cleanup := ResetMockSessionStore(t)
+	defer cleanup()
+
+	conf.Mock(&conf.Unified{SiteConfiguration: schema.SiteConfiguration{TrustedProxies: []string{"192.0.2.1"}}})
+	defer conf.Mock(nil)
+
+	// The request is forwarded by a trusted proxy, so the client IP is taken from X-Forwarded-For.
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("X-Forwarded-For", "198.51.100.7")
+	if err := SetActor(httptest.NewRecorder(), req, &actor.Actor{UID: 123}, time.Hour); err != nil {
+		t.Fatal(err)
+	}
+
+	sessions := userSessions.(*mockUserSessions).sessions
+	if len(sessions) != 1 {
+		t.Fatalf("got %d recorded sessions, want 1", len(sessions))
+	}
+	for _, s := range sessions {
+		if want := "198.51.100.7"; s.IP != want {
+			t.Errorf("got IP %q, want %q", s.IP, want)
+		}
+	}
+}
+
+func TestSessionInfoExpiresAt(t *testing.T) {
+	createdAt := time.Date(2020, 5, 1, 0, 0, 0, 0, time.UTC)
+	info := &sessionInfo{
+		LastActive:   createdAt.Add(48 * time.Hour),
+		ExpiryPeriod: 24 * time.Hour,
+		CreatedAt:    createdAt,
+	}
+	defer conf.Mock(nil)
+
+	tests := []struct {
+		absoluteExpiry string
+		want           time.Time
+	}{
+		{absoluteExpiry: "", want: createdAt.Add(72 * time.Hour)},
+		{absoluteExpiry: "invalid", want: createdAt.Add(72 * time.Hour)},
+		{absoluteExpiry: "720h", want: createdAt.Add(72 * time.Hour)},
+		{absoluteExpiry: "60h", want: createdAt.Add(60 * time.Hour)},
+	}
+	for _, test := range tests {
+		conf.Mock(&conf.Unified{SiteConfiguration: schema.SiteConfiguration{AuthSessionAbsoluteExpiry: test.absoluteExpiry}})
+		if got := info.expiresAt(); !got.Equal(test.want) {
+			t.Errorf("auth.sessionAbsoluteExpiry %q: got %s, want %s", test.absoluteExpiry, got, test.want)
+		}
+	}
+}
@@ -1,12 +1,16 @@
 package session
 
 import (
+	"context"
 	"io/ioutil"
 	"os"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/gorilla/securecookie"
 	"github.com/gorilla/sessions"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
 )
 
 func ResetMockSessionStore(t *testing.T) (cleanup func()) {
@@ -23,7 +27,55 @@ func ResetMockSessionStore(t *testing.T) (cleanup func()) {
 	}()
 
 	SetSessionStore(sessions.NewFilesystemStore(tempdir, securecookie.GenerateRandomKey(2048)))
+	userSessions = &mockUserSessions{sessions: map[string]*db.UserSession{}}
 	return func() {
+		userSessions = db.UserSessions
 		os.RemoveAll(tempdir)
 	}
 }
+
+// mockUserSessions records sessions in memory.
+type mockUserSessions struct {
+	mu       sync.Mutex
+	sessions map[string]*db.UserSession
+	nextID   int64
+}
+
+func (m *mockUserSessions) Create(_ context.Context, key string, s *db.UserSession) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.nextID++
+	s.ID = m.nextID
+	s.CreatedAt = time.Now()
+	s.LastActiveAt = s.CreatedAt
+	m.sessions[key] = s
+	return nil
+}
+
+func (m *mockUserSessions) GetByKey(_ context.Context, key string) (*db.UserSession, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s, ok := m.sessions[key]
+	if !ok || s.ExpiresAt.Before(time.Now()) {
+		return nil, db.ErrUserSessionNotFound
+	}
+	return s, nil
+}
+
+func (m *mockUserSessions) Touch(_ context.Context, id int64, ip, userAgent string, expiresAt time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, s := range m.sessions {
+		if s.ID == id {
+			s.IP, s.UserAgent, s.LastActiveAt, s.ExpiresAt = ip, userAgent, time.Now(), expiresAt
+		}
+	}
+	return nil
+}
+
+func (m *mockUserSessions) DeleteByKey(_ context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.sessions, key)
+	return nil
+}
@@ -27,6 +27,11 @@ type RepoFields struct {
 
 	// Archived is whether this repository has been archived.
 	Archived bool
+
+	// KeyValuePairs are the key/value tags attached to this repository by
+	// site admins or imported from the code host (e.g. GitHub topics). A nil
+	// value denotes a tag that is a key only.
+	KeyValuePairs map[string]*string
 }
 
 // Repo represents a source code repository.
@@ -85,6 +90,9 @@ type User struct {
 	SiteAdmin   bool
 	BuiltinAuth bool
 	Tags        []string
+	// DeactivatedAt is when the user was deactivated, if they were. Deactivated users can't sign
+	// in or use access tokens.
+	DeactivatedAt *time.Time
 }
 
 type Org struct {
@@ -99,10 +107,45 @@ type OrgMembership struct {
 	ID        int32
 	OrgID     int32
 	UserID    int32
+	Role      OrgRole
 	CreatedAt time.Time
 	UpdatedAt time.Time
 }
 
+// OrgRole is the role of a member of an organization. Each role grants the privileges of the roles
+// below it.
+type OrgRole string
+
+const (
+	// OrgRoleOwner members can do everything admins can, and also manage the roles of other owners
+	// and admins.
+	OrgRoleOwner OrgRole = "OWNER"
+	// OrgRoleAdmin members can manage the organization's members, settings and campaigns.
+	OrgRoleAdmin OrgRole = "ADMIN"
+	// OrgRoleMember members can use the organization's resources, such as saved searches.
+	OrgRoleMember OrgRole = "MEMBER"
+	// OrgRoleReadOnly members can view the organization, its members and its settings.
+	OrgRoleReadOnly OrgRole = "READ_ONLY"
+)
+
+var orgRoleRanks = map[OrgRole]int{
+	OrgRoleReadOnly: 1,
+	OrgRoleMember:   2,
+	OrgRoleAdmin:    3,
+	OrgRoleOwner:    4,
+}
+
+// Valid returns true if the given OrgRole is valid.
+func (r OrgRole) Valid() bool {
+	_, ok := orgRoleRanks[r]
+	return ok
+}
+
+// Includes returns true if the role grants the privileges of the given role.
+func (r OrgRole) Includes(other OrgRole) bool {
+	return r.Valid() && other.Valid() && orgRoleRanks[r] >= orgRoleRanks[other]
+}
+
 type PhabricatorRepo struct {
 	ID       int32
 	Name     api.RepoName
@@ -9,6 +9,7 @@ import (
 
 	"github.com/inconshreveable/log15"
 	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/perforce"
 	"github.com/sourcegraph/sourcegraph/schema"
 )
 
@@ -38,20 +39,46 @@ func extractDomainPath(cloneURL string) (string, error) {
 	return path.Join(gitURL.Host, gitURL.Path), nil
 }
 
-func customFetchCmd(ctx context.Context, urlVal string) *exec.Cmd {
+// customCloneCmd returns the command that clones the repository at the clone
+// URL into a new bare Git repository at tmpPath instead of git clone, or nil if
+// git clone is used. Perforce depots are cloned with git p4.
+func customCloneCmd(ctx context.Context, urlVal, tmpPath string) (*exec.Cmd, error) {
+	if perforce.IsCloneURL(urlVal) {
+		return perforceCloneCmd(ctx, urlVal, tmpPath)
+	}
+	return nil, nil
+}
+
+// customFetchCmd returns the command that fetches the repository at the clone
+// URL instead of git fetch, or nil if git fetch is used. Perforce depots are
+// fetched with git p4, other repositories with the command configured for
+// their domain and path in experimentalFeatures.customGitFetch (if any).
+func customFetchCmd(ctx context.Context, urlVal string) (*exec.Cmd, error) {
+	if perforce.IsCloneURL(urlVal) {
+		return perforceFetchCmd(ctx, urlVal)
+	}
+
 	cgm := customGitFetch().(map[string][]string)
 	if len(cgm) == 0 {
-		return nil
+		return nil, nil
 	}
 
 	dp, err := extractDomainPath(urlVal)
 	if err != nil {
 		log15.Error("failed to extract domain and path", "url", urlVal, "err", err)
-		return nil
+		return nil, nil
 	}
 	cmdParts := cgm[dp]
 	if len(cmdParts) == 0 {
-		return nil
+		return nil, nil
 	}
-	return exec.CommandContext(ctx, cmdParts[0], cmdParts[1:]...)
+	return exec.CommandContext(ctx, cmdParts[0], cmdParts[1:]...), nil
+}
+
+// customFetchSetsHead reports whether the custom fetch command of the clone
+// URL also points HEAD at the fetched default branch. This is the case for
+// remotes that aren't Git repositories (such as Perforce depots), whose HEAD
+// can't be determined with git remote show.
+func customFetchSetsHead(urlVal string) bool {
+	return perforce.IsCloneURL(urlVal)
 }
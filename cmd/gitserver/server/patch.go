@@ -15,6 +15,7 @@ import (
 
 	"github.com/inconshreveable/log15"
 	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
 )
 
@@ -183,9 +184,23 @@ func (s *Server) createCommitFromPatch(ctx context.Context, req protocol.CreateC
 		committerEmail = authorEmail
 	}
 
-	cmd = exec.CommandContext(ctx, "git", "commit", "-m", message)
+	commitArgs := []string{"commit", "-m", message}
+	var signingEnv []string
+	if key := conf.Get().CampaignsSigningKey; key != nil {
+		signer, err := s.newCommitSigner(ctx, key)
+		if err != nil {
+			resp.SetError(repo, "", "", errors.Wrap(err, "gitserver: preparing commit signing"))
+			return http.StatusInternalServerError, resp
+		}
+		defer signer.Close()
+
+		commitArgs = append(signer.configArgs, commitArgs...)
+		signingEnv = signer.env
+	}
+
+	cmd = exec.CommandContext(ctx, "git", commitArgs...)
 	cmd.Dir = tmpRepoDir
-	cmd.Env = append(cmd.Env, []string{
+	cmd.Env = append(signingEnv, []string{
 		tmpGitPathEnv,
 		altObjectsEnv,
 		fmt.Sprintf("GIT_COMMITTER_NAME=%s", committerName),
@@ -242,7 +257,12 @@ func (s *Server) createCommitFromPatch(ctx context.Context, req protocol.CreateC
 	}
 
 	if req.Push {
-		cmd = exec.CommandContext(ctx, "git", "push", "--force", remoteURL, fmt.Sprintf("%s:%s", cmtHash, ref))
+		force := "--force"
+		if req.ExpectedRemoteCommit != "" {
+			force = fmt.Sprintf("--force-with-lease=%s:%s", ref, req.ExpectedRemoteCommit)
+		}
+
+		cmd = exec.CommandContext(ctx, "git", "push", force, remoteURL, fmt.Sprintf("%s:%s", cmtHash, ref))
 		cmd.Dir = repoGitDir
 
 		if out, err = run(cmd, "pushing ref"); err != nil {
@@ -0,0 +1,55 @@
+package server
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/perforce"
+)
+
+// perforceCloneCmd returns the command that imports the full history of the
+// Perforce depot referred to by the clone URL into a new bare Git repository
+// at tmpPath. Each Perforce changelist becomes a commit whose message records
+// the changelist number.
+func perforceCloneCmd(ctx context.Context, url, tmpPath string) (*exec.Cmd, error) {
+	remote, err := perforce.ParseCloneURL(url)
+	if err != nil {
+		return nil, err
+	}
+
+	cmd := exec.CommandContext(ctx, "git", "p4", "clone", "--bare", strings.TrimSuffix(remote.DepotPath, "/")+"@all", tmpPath)
+	cmd.Env = append(os.Environ(), remote.Env()...)
+	return cmd, nil
+}
+
+// perforceFetchCmd returns the command that incrementally imports the
+// changelists submitted to the Perforce depot referred to by the clone URL
+// since the last import. git p4 determines the last imported changelist from
+// the commit at refs/remotes/p4/master, which is the only ref it updates, so
+// the command then points master and HEAD at it (see perforceUpdateHead).
+func perforceFetchCmd(ctx context.Context, url string) (*exec.Cmd, error) {
+	remote, err := perforce.ParseCloneURL(url)
+	if err != nil {
+		return nil, err
+	}
+
+	cmd := exec.CommandContext(ctx, "sh", "-c", "git p4 sync && "+perforceUpdateHead)
+	cmd.Env = append(os.Environ(), remote.Env()...)
+	return cmd, nil
+}
+
+// perforceUpdateHead is the shell command that points master and HEAD at the
+// changelists imported by git p4 sync.
+const perforceUpdateHead = "git update-ref refs/heads/master refs/remotes/p4/master && git symbolic-ref HEAD refs/heads/master"
+
+// isPerforceCloneable returns an error if the Perforce depot referred to by
+// the clone URL does not exist or is not accessible.
+func isPerforceCloneable(ctx context.Context, url string) error {
+	remote, err := perforce.ParseCloneURL(url)
+	if err != nil {
+		return err
+	}
+	return perforce.NewClient(remote.Port, remote.User, remote.Password).CheckDepot(ctx, remote.DepotPath)
+}
@@ -33,6 +33,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/conf"
 	"github.com/sourcegraph/sourcegraph/internal/env"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/perforce"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
 	"github.com/sourcegraph/sourcegraph/internal/honey"
 	"github.com/sourcegraph/sourcegraph/internal/lazyregexp"
@@ -807,13 +808,16 @@ func (s *Server) cloneRepo(ctx context.Context, repo api.RepoName, url string, o
 		tmpPath = filepath.Join(tmpPath, ".git")
 		tmp := GitDir(tmpPath)
 
-		var cmd *exec.Cmd
-		if useRefspecOverrides() {
+		cmd, err := customCloneCmd(ctx, url, tmpPath)
+		if err != nil {
+			return err
+		}
+		if cmd == nil && useRefspecOverrides() {
 			cmd, err = refspecOverridesCloneCmd(ctx, url, tmpPath)
 			if err != nil {
 				return err
 			}
-		} else {
+		} else if cmd == nil {
 			cmd = exec.CommandContext(ctx, "git", "clone", "--mirror", "--progress", url, tmpPath)
 		}
 		// see issue #7322: skip LFS content in repositories with Git LFS configured
@@ -988,6 +992,9 @@ func (s *Server) isCloneable(ctx context.Context, url string) error {
 	if testRepoExists != nil {
 		return testRepoExists(ctx, url)
 	}
+	if perforce.IsCloneURL(url) {
+		return isPerforceCloneable(ctx, url)
+	}
 
 	cmd := exec.CommandContext(ctx, "git", args...)
 	out, err := runWithRemoteOpts(ctx, cmd, nil)
@@ -1303,9 +1310,11 @@ func (s *Server) doRepoUpdate2(repo api.RepoName, url string) error {
 	}
 
 	configRemoteOpts := true
-	var cmd *exec.Cmd
-	if customCmd := customFetchCmd(ctx, url); customCmd != nil {
-		cmd = customCmd
+	cmd, err := customFetchCmd(ctx, url)
+	if err != nil {
+		return errors.Wrap(err, "failed to update")
+	}
+	if cmd != nil {
 		configRemoteOpts = false
 	} else if useRefspecOverrides() {
 		cmd = refspecOverridesFetchCmd(ctx, url)
@@ -1332,6 +1341,10 @@ func (s *Server) doRepoUpdate2(repo api.RepoName, url string) error {
 		log15.Warn("Failed to update last changed time", "repo", repo, "error", err)
 	}
 
+	if customFetchSetsHead(url) {
+		return nil
+	}
+
 	headBranch := "master"
 
 	// try to fetch HEAD from origin
@@ -0,0 +1,144 @@
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/inconshreveable/log15"
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/internal/secret"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+// commitSigner signs the commits gitserver creates from patches with the
+// campaigns.signingKey of the site configuration.
+type commitSigner struct {
+	// dir holds the key or, for GPG keys, the keyring it was imported into.
+	dir string
+	// configArgs are passed to git before the commit subcommand.
+	configArgs []string
+	// env is added to the environment of the commit command.
+	env []string
+}
+
+// newCommitSigner returns a commitSigner for the given key. The caller must
+// call Close when done.
+func (s *Server) newCommitSigner(ctx context.Context, key *schema.CampaignsSigningKey) (_ *commitSigner, err error) {
+	plaintext, err := secret.Decrypt(key.EncryptedKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "decrypting signing key")
+	}
+
+	dir, err := s.tempDir("signing-")
+	if err != nil {
+		return nil, errors.Wrap(err, "creating signing key dir")
+	}
+
+	signer := &commitSigner{dir: dir}
+	defer func() {
+		if err != nil {
+			signer.Close()
+		}
+	}()
+
+	switch key.Type {
+	case "gpg":
+		err = signer.importGPGKey(ctx, plaintext)
+	case "ssh":
+		err = signer.writeSSHKey(plaintext)
+	default:
+		err = errors.Errorf("unknown signing key type %q", key.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return signer, nil
+}
+
+// importGPGKey imports the ASCII-armored GPG secret key into a keyring in
+// the signer's dir.
+func (cs *commitSigner) importGPGKey(ctx context.Context, key []byte) error {
+	if err := os.Chmod(cs.dir, 0700); err != nil {
+		return err
+	}
+	cs.env = []string{"GNUPGHOME=" + cs.dir}
+
+	cmd := exec.CommandContext(ctx, "gpg", "--batch", "--import")
+	cmd.Env = append(os.Environ(), cs.env...)
+	cmd.Stdin = bytes.NewReader(key)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return errors.Wrapf(err, "importing GPG signing key: %s", out)
+	}
+
+	cmd = exec.CommandContext(ctx, "gpg", "--batch", "--with-colons", "--list-secret-keys")
+	cmd.Env = append(os.Environ(), cs.env...)
+	out, err := cmd.Output()
+	if err != nil {
+		return errors.Wrap(err, "listing GPG signing keys")
+	}
+
+	fingerprint := gpgFingerprint(out)
+	if fingerprint == "" {
+		return errors.New("GPG signing key has no fingerprint")
+	}
+
+	cs.configArgs = []string{
+		"-c", "gpg.format=openpgp",
+		"-c", "user.signingkey=" + fingerprint,
+		"-c", "commit.gpgsign=true",
+	}
+	return nil
+}
+
+// gpgFingerprint returns the fingerprint of the first key listed in the
+// given output of `gpg --with-colons --list-secret-keys`.
+func gpgFingerprint(listing []byte) string {
+	s := bufio.NewScanner(bytes.NewReader(listing))
+	for s.Scan() {
+		fields := strings.Split(s.Text(), ":")
+		if len(fields) > 9 && fields[0] == "fpr" {
+			return fields[9]
+		}
+	}
+	return ""
+}
+
+// writeSSHKey writes the OpenSSH private key to the signer's dir. Signing
+// commits with SSH keys requires git 2.34 or later.
+func (cs *commitSigner) writeSSHKey(key []byte) error {
+	path := filepath.Join(cs.dir, "signing_key")
+	// ssh-keygen requires private keys to end with a newline.
+	key = append(bytes.TrimSpace(key), '\n')
+	if err := ioutil.WriteFile(path, key, 0600); err != nil {
+		return errors.Wrap(err, "writing SSH signing key")
+	}
+
+	cs.configArgs = []string{
+		"-c", "gpg.format=ssh",
+		"-c", "user.signingkey=" + path,
+		"-c", "commit.gpgsign=true",
+	}
+	return nil
+}
+
+// Close stops the GPG agent started for the keyring, if any, and removes
+// the key.
+func (cs *commitSigner) Close() {
+	if len(cs.env) > 0 {
+		cmd := exec.Command("gpgconf", "--kill", "gpg-agent")
+		cmd.Env = append(os.Environ(), cs.env...)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			log15.Info("unable to stop gpg-agent", "err", err, "output", string(out))
+		}
+	}
+
+	if err := os.RemoveAll(cs.dir); err != nil {
+		log15.Info("unable to clean up signing key", "path", cs.dir, "err", err)
+	}
+}
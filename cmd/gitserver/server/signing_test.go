@@ -0,0 +1,174 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
+	"github.com/sourcegraph/sourcegraph/internal/secret"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+func TestCreateCommitFromPatch_Signed(t *testing.T) {
+	secret.MockKey("test secret key")
+	defer secret.MockKey("")
+
+	origRepoRemoteURL := repoRemoteURL
+	repoRemoteURL = func(context.Context, GitDir) (string, error) { return "https://example.com/foo/bar", nil }
+	defer func() { repoRemoteURL = origRepoRemoteURL }()
+
+	// Every test uses a throwaway keyring or key that is only used to sign
+	// and verify a single commit.
+	for _, tc := range []struct {
+		name string
+		// keygen creates a key in dir and returns the private key and the
+		// arguments and environment that make git verify-commit trust it.
+		keygen func(t *testing.T, dir string) (key string, verifyArgs, verifyEnv []string)
+		typ    string
+	}{
+		{
+			name:   "gpg",
+			typ:    "gpg",
+			keygen: gpgKeygen,
+		},
+		{
+			name:   "ssh",
+			typ:    "ssh",
+			keygen: sshKeygen,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			keyDir, cleanup1 := signingTmpDir(t)
+			defer cleanup1()
+			key, verifyArgs, verifyEnv := tc.keygen(t, keyDir)
+
+			encryptedKey, err := secret.Encrypt([]byte(key))
+			if err != nil {
+				t.Fatal(err)
+			}
+			conf.Mock(&conf.Unified{SiteConfiguration: schema.SiteConfiguration{
+				CampaignsSigningKey: &schema.CampaignsSigningKey{Type: tc.typ, EncryptedKey: encryptedKey},
+			}})
+			defer conf.Mock(nil)
+
+			reposDir, cleanup2 := signingTmpDir(t)
+			defer cleanup2()
+
+			repoDir := filepath.Join(reposDir, "example.com/foo/bar")
+			if err := os.MkdirAll(repoDir, 0700); err != nil {
+				t.Fatal(err)
+			}
+			runGit(t, repoDir, nil, "init", ".")
+			runGit(t, repoDir, nil, "commit", "--allow-empty", "-m", "initial")
+			baseCommit := strings.TrimSpace(runGit(t, repoDir, nil, "rev-parse", "HEAD"))
+
+			s := &Server{ReposDir: reposDir}
+			status, resp := s.createCommitFromPatch(context.Background(), protocol.CreateCommitFromPatchRequest{
+				Repo:       "example.com/foo/bar",
+				BaseCommit: api.CommitID(baseCommit),
+				Patch: `diff --git a/README.md b/README.md
+new file mode 100644
+--- /dev/null
++++ b/README.md
+@@ -0,0 +1 @@
++# Signed
+`,
+				TargetRef: "refs/heads/signed",
+				CommitInfo: protocol.PatchCommitInfo{
+					Message:        "Add README",
+					AuthorName:     "Sourcegraph Bot",
+					AuthorEmail:    "campaigns@sourcegraph.com",
+					CommitterName:  "Campaigns Bot",
+					CommitterEmail: "campaigns@example.com",
+					Date:           time.Now(),
+				},
+			})
+			if status != http.StatusOK {
+				t.Fatalf("creating commit failed with status %d: %+v", status, resp.Error)
+			}
+
+			runGit(t, repoDir, verifyEnv, append(verifyArgs, "verify-commit", "refs/heads/signed")...)
+		})
+	}
+}
+
+func gpgKeygen(t *testing.T, dir string) (string, []string, []string) {
+	if _, err := exec.LookPath("gpg"); err != nil {
+		t.Skip("gpg is not installed")
+	}
+
+	env := []string{"GNUPGHOME=" + dir}
+	defer run(t, "", env, "gpgconf", "--kill", "gpg-agent")
+
+	run(t, "", env, "gpg", "--batch", "--passphrase", "", "--quick-generate-key", "Campaigns Bot <campaigns@example.com>", "ed25519", "sign", "never")
+	key := run(t, "", env, "gpg", "--batch", "--armor", "--export-secret-keys")
+	return key, nil, env
+}
+
+func sshKeygen(t *testing.T, dir string) (string, []string, []string) {
+	if _, err := exec.LookPath("ssh-keygen"); err != nil {
+		t.Skip("ssh-keygen is not installed")
+	}
+
+	path := filepath.Join(dir, "id_ed25519")
+	run(t, "", nil, "ssh-keygen", "-q", "-t", "ed25519", "-N", "", "-C", "campaigns@example.com", "-f", path)
+
+	key, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pub, err := ioutil.ReadFile(path + ".pub")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	allowedSigners := filepath.Join(dir, "allowed_signers")
+	writeFile(t, allowedSigners, []byte("campaigns@example.com "+string(pub)))
+
+	return string(key), []string{"-c", "gpg.ssh.allowedSignersFile=" + allowedSigners}, nil
+}
+
+// signingTmpDir is like tmpDir, but can be used in subtests.
+func signingTmpDir(t *testing.T) (string, func()) {
+	t.Helper()
+	dir, err := ioutil.TempDir("", "gitserver-signing-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dir, func() { os.RemoveAll(dir) }
+}
+
+func runGit(t *testing.T, dir string, env []string, args ...string) string {
+	t.Helper()
+	env = append([]string{
+		"GIT_COMMITTER_NAME=a",
+		"GIT_COMMITTER_EMAIL=a@a.com",
+		"GIT_AUTHOR_NAME=a",
+		"GIT_AUTHOR_EMAIL=a@a.com",
+	}, env...)
+	return run(t, dir, env, "git", args...)
+}
+
+func run(t *testing.T, dir string, env []string, name string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command(name, args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), env...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("%s %s failed: %s\n%s%s", name, strings.Join(args, " "), err, out, stderr.Bytes())
+	}
+	return string(out)
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/inconshreveable/log15"
 	"github.com/pkg/errors"
 	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/campaigns"
 	"github.com/sourcegraph/sourcegraph/internal/conf/reposource"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketserver"
@@ -22,6 +23,10 @@ import (
 
 // A BitbucketServerSource yields repositories from a single BitbucketServer connection configured
 // in Sourcegraph via the external services configuration.
+//
+// It isn't an IncrementalSource: the Bitbucket Server REST API neither records when repositories
+// were last updated nor offers a change stream of them, so all repositories are listed on every
+// sync.
 type BitbucketServerSource struct {
 	svc     *ExternalService
 	config  *schema.BitbucketServerConnection
@@ -91,7 +96,13 @@ func (s BitbucketServerSource) CreateChangeset(ctx context.Context, c *Changeset
 
 	repo := c.Repo.Metadata.(*bitbucketserver.Repo)
 
-	pr := &bitbucketserver.PullRequest{Title: c.Title, Description: c.Body}
+	// Bitbucket Server doesn't support labels, assignees or drafts, so we
+	// only add the reviewers.
+	pr := &bitbucketserver.PullRequest{
+		Title:       c.Title,
+		Description: c.Body,
+		Reviewers:   bitbucketServerReviewers(nil, c.Reviewers),
+	}
 
 	pr.ToRef.Repository.Slug = repo.Slug
 	pr.ToRef.Repository.Project.Key = repo.Project.Key
@@ -143,6 +154,98 @@ func (s BitbucketServerSource) CloseChangeset(ctx context.Context, c *Changeset)
 	return nil
 }
 
+// bitbucketServerMergeStrategies maps merge methods to the IDs of the
+// Bitbucket Server merge strategies implementing them.
+var bitbucketServerMergeStrategies = map[campaigns.ChangesetMergeMethod]string{
+	campaigns.ChangesetMergeMethodMerge:  "no-ff",
+	campaigns.ChangesetMergeMethodSquash: "squash",
+	campaigns.ChangesetMergeMethodRebase: "rebase-no-ff",
+}
+
+// MergeChangeset merges the given *Changeset on the code host and updates the
+// Metadata column in the *campaigns.Changeset to the newly merged pull request.
+func (s BitbucketServerSource) MergeChangeset(ctx context.Context, c *Changeset, method campaigns.ChangesetMergeMethod) error {
+	pr, ok := c.Changeset.Metadata.(*bitbucketserver.PullRequest)
+	if !ok {
+		return errors.New("Changeset is not a Bitbucket Server pull request")
+	}
+
+	strategy, ok := bitbucketServerMergeStrategies[method]
+	if !ok {
+		return errors.Errorf("unsupported merge method %q", method)
+	}
+
+	err := s.client.MergePullRequest(ctx, pr, strategy)
+	if err != nil {
+		return err
+	}
+
+	c.Changeset.Metadata = pr
+
+	return nil
+}
+
+// CreateComment posts a comment with the given body on the pull request of
+// the given *Changeset.
+func (s BitbucketServerSource) CreateComment(ctx context.Context, c *Changeset, body string) error {
+	pr, ok := c.Changeset.Metadata.(*bitbucketserver.PullRequest)
+	if !ok {
+		return errors.New("Changeset is not a Bitbucket Server pull request")
+	}
+
+	return s.client.CreatePullRequestComment(ctx, pr, body)
+}
+
+// AddLabels is not supported, since Bitbucket Server pull requests have no
+// labels.
+func (s BitbucketServerSource) AddLabels(context.Context, *Changeset, []string) error {
+	return ErrChangesetActionNotSupported
+}
+
+// RemoveLabels is not supported, since Bitbucket Server pull requests have no
+// labels.
+func (s BitbucketServerSource) RemoveLabels(context.Context, *Changeset, []string) error {
+	return ErrChangesetActionNotSupported
+}
+
+// RequestReviewers adds the users with the given names as reviewers of the
+// pull request of the given *Changeset and updates its Metadata.
+func (s BitbucketServerSource) RequestReviewers(ctx context.Context, c *Changeset, reviewers []string) error {
+	pr, ok := c.Changeset.Metadata.(*bitbucketserver.PullRequest)
+	if !ok {
+		return errors.New("Changeset is not a Bitbucket Server pull request")
+	}
+
+	if len(reviewers) == 0 {
+		return nil
+	}
+
+	update := &bitbucketserver.UpdatePullRequestInput{
+		PullRequestID: strconv.Itoa(pr.ID),
+		Title:         pr.Title,
+		Description:   pr.Description,
+		Version:       pr.Version,
+		Reviewers:     bitbucketServerReviewers(pr.Reviewers, reviewers),
+	}
+	update.ToRef.ID = pr.ToRef.ID
+	update.ToRef.Repository.Slug = pr.ToRef.Repository.Slug
+	update.ToRef.Repository.Project.Key = pr.ToRef.Repository.Project.Key
+
+	updated, err := s.client.UpdatePullRequest(ctx, update)
+	if err != nil {
+		return err
+	}
+
+	c.Changeset.Metadata = updated
+	return nil
+}
+
+// RerunFailedChecks is not supported, since builds report their status to
+// Bitbucket Server but aren't triggered by it.
+func (s BitbucketServerSource) RerunFailedChecks(context.Context, *Changeset) error {
+	return ErrChangesetActionNotSupported
+}
+
 // LoadChangesets loads the latest state of the given Changesets from the codehost.
 func (s BitbucketServerSource) LoadChangesets(ctx context.Context, cs ...*Changeset) error {
 	var notFound []*Changeset
@@ -203,6 +306,167 @@ func (s BitbucketServerSource) loadPullRequestData(ctx context.Context, pr *bitb
 	return nil
 }
 
+var _ ChangesetSearcher = BitbucketServerSource{}
+
+// SearchChangesets returns the pull requests matching the given query. See
+// parseBitbucketServerChangesetQuery for its syntax.
+func (s BitbucketServerSource) SearchChangesets(ctx context.Context, query string) ([]*Changeset, error) {
+	q, err := parseBitbucketServerChangesetQuery(query)
+	if err != nil {
+		return nil, err
+	}
+
+	host, err := url.Parse(s.config.Url)
+	if err != nil {
+		return nil, err
+	}
+	host = extsvc.NormalizeBaseURL(host)
+
+	var cs []*Changeset
+	for _, r := range q.repos {
+		next := &bitbucketserver.PageToken{Limit: 1000}
+		for next.HasMore() {
+			prs, page, err := s.client.PullRequests(ctx, r.projectKey, r.slug, q.filter, next)
+			if err != nil {
+				return nil, errors.Wrapf(err, "listing pull requests of %s/%s", r.projectKey, r.slug)
+			}
+
+			for _, pr := range prs {
+				if !q.matches(pr) {
+					continue
+				}
+				cs = append(cs, &Changeset{
+					Changeset: &campaigns.Changeset{
+						ExternalID:          strconv.Itoa(pr.ID),
+						ExternalServiceType: bitbucketserver.ServiceType,
+					},
+					Repo: &Repo{
+						Name: r.projectKey + "/" + r.slug,
+						ExternalRepo: api.ExternalRepoSpec{
+							ID:          strconv.Itoa(pr.ToRef.Repository.ID),
+							ServiceType: bitbucketserver.ServiceType,
+							ServiceID:   host.String(),
+						},
+					},
+				})
+			}
+
+			next = page
+		}
+	}
+
+	return cs, nil
+}
+
+// bitbucketServerChangesetQuery is a parsed Bitbucket Server changeset query.
+type bitbucketServerChangesetQuery struct {
+	repos []struct{ projectKey, slug string }
+	// filter is applied by the Bitbucket Server API, the remaining fields
+	// are matched by matches.
+	filter   bitbucketserver.PullRequestFilter
+	author   string
+	reviewer string
+	head     string
+	text     []string
+}
+
+// parseBitbucketServerChangesetQuery parses a query of space separated
+// terms, since Bitbucket Server has no API to search pull requests across
+// repositories. The supported terms are:
+//
+//	repo:PROJECT/slug  the repository to list pull requests of, required and repeatable
+//	state:STATE        one of open (default), merged, declined or all
+//	author:USERNAME    the author of the pull request
+//	reviewer:USERNAME  a reviewer of the pull request
+//	base:BRANCH        the branch the pull request is merged into
+//	head:BRANCH        the branch the pull request is created from
+//	TEXT               a word contained in the title of the pull request
+func parseBitbucketServerChangesetQuery(query string) (q bitbucketServerChangesetQuery, err error) {
+	for _, term := range strings.Fields(query) {
+		i := strings.Index(term, ":")
+		if i < 0 {
+			q.text = append(q.text, strings.ToLower(term))
+			continue
+		}
+
+		key, value := term[:i], term[i+1:]
+		if value == "" {
+			return q, errors.Errorf("empty value in query term %q", term)
+		}
+
+		switch key {
+		case "repo":
+			ps := strings.SplitN(value, "/", 2)
+			if len(ps) != 2 || ps[0] == "" || ps[1] == "" {
+				return q, errors.Errorf("invalid repository %q, want PROJECT/slug", value)
+			}
+			q.repos = append(q.repos, struct{ projectKey, slug string }{ps[0], ps[1]})
+		case "state":
+			switch state := strings.ToUpper(value); state {
+			case "OPEN", "MERGED", "DECLINED", "ALL":
+				q.filter.State = state
+			default:
+				return q, errors.Errorf("invalid state %q, want one of open, merged, declined or all", value)
+			}
+		case "author":
+			q.author = value
+		case "reviewer":
+			q.reviewer = value
+		case "base":
+			q.filter.At = git.EnsureRefPrefix(value)
+			q.filter.Direction = "INCOMING"
+		case "head":
+			q.head = git.EnsureRefPrefix(value)
+		default:
+			return q, errors.Errorf("unsupported query term %q", term)
+		}
+	}
+
+	if len(q.repos) == 0 {
+		return q, errors.New("query must contain at least one repo:PROJECT/slug term")
+	}
+
+	return q, nil
+}
+
+// matches returns true if the pull request matches the terms of the query
+// that are not applied by the API.
+func (q bitbucketServerChangesetQuery) matches(pr *bitbucketserver.PullRequest) bool {
+	if q.author != "" && !bitbucketServerUserIs(pr.Author.User, q.author) {
+		return false
+	}
+
+	if q.reviewer != "" {
+		found := false
+		for _, r := range pr.Reviewers {
+			if bitbucketServerUserIs(r.User, q.reviewer) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	if q.head != "" && pr.FromRef.ID != q.head {
+		return false
+	}
+
+	title := strings.ToLower(pr.Title)
+	for _, t := range q.text {
+		if !strings.Contains(title, t) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func bitbucketServerUserIs(u *bitbucketserver.User, username string) bool {
+	return u != nil && (strings.EqualFold(u.Name, username) || strings.EqualFold(u.Slug, username))
+}
+
 func (s BitbucketServerSource) UpdateChangeset(ctx context.Context, c *Changeset) error {
 	pr, ok := c.Changeset.Metadata.(*bitbucketserver.PullRequest)
 	if !ok {
@@ -214,6 +478,7 @@ func (s BitbucketServerSource) UpdateChangeset(ctx context.Context, c *Changeset
 		Title:         c.Title,
 		Description:   c.Body,
 		Version:       pr.Version,
+		Reviewers:     bitbucketServerReviewers(pr.Reviewers, c.Reviewers),
 	}
 	update.ToRef.ID = c.BaseRef
 	update.ToRef.Repository.Slug = pr.ToRef.Repository.Slug
@@ -228,6 +493,36 @@ func (s BitbucketServerSource) UpdateChangeset(ctx context.Context, c *Changeset
 	return nil
 }
 
+// bitbucketServerReviewers returns the existing reviewers with the users
+// with the given names added, unless they're reviewers already. It returns
+// nil if no names are given, which leaves the reviewers of an existing pull
+// request untouched.
+func bitbucketServerReviewers(existing []bitbucketserver.Reviewer, names []string) []bitbucketserver.Reviewer {
+	if len(names) == 0 {
+		return nil
+	}
+
+	reviewers := append([]bitbucketserver.Reviewer(nil), existing...)
+	seen := make(map[string]bool, len(existing))
+	for _, r := range existing {
+		if r.User != nil {
+			seen[r.User.Name] = true
+		}
+	}
+
+	for _, name := range names {
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		reviewers = append(reviewers, bitbucketserver.Reviewer{
+			User: &bitbucketserver.User{Name: name},
+		})
+	}
+
+	return reviewers
+}
+
 // ExternalServices returns a singleton slice containing the external service.
 func (s BitbucketServerSource) ExternalServices() ExternalServices {
 	return ExternalServices{s.svc}
@@ -5,11 +5,14 @@ import (
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
 	"github.com/inconshreveable/log15"
 	"github.com/sourcegraph/sourcegraph/internal/campaigns"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketserver"
@@ -495,3 +498,154 @@ func TestBitbucketServerSource_UpdateChangeset(t *testing.T) {
 		})
 	}
 }
+
+func TestBitbucketServerReviewers(t *testing.T) {
+	reviewer := func(name string) bitbucketserver.Reviewer {
+		return bitbucketserver.Reviewer{User: &bitbucketserver.User{Name: name}}
+	}
+	existing := []bitbucketserver.Reviewer{reviewer("alice")}
+	existing[0].Approved = true
+
+	for _, tc := range []struct {
+		name     string
+		existing []bitbucketserver.Reviewer
+		names    []string
+		want     []bitbucketserver.Reviewer
+	}{
+		{
+			name:     "no names",
+			existing: existing,
+		},
+		{
+			name:  "new pull request",
+			names: []string{"alice", "bob", "alice"},
+			want:  []bitbucketserver.Reviewer{reviewer("alice"), reviewer("bob")},
+		},
+		{
+			name:     "existing reviewers",
+			existing: existing,
+			names:    []string{"alice", "bob"},
+			want:     []bitbucketserver.Reviewer{existing[0], reviewer("bob")},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			have := bitbucketServerReviewers(tc.existing, tc.names)
+			if diff := cmp.Diff(tc.want, have); diff != "" {
+				t.Fatal(diff)
+			}
+		})
+	}
+}
+
+func TestBitbucketServerSource_SearchChangesets(t *testing.T) {
+	pr := func(id int, title, author, from string, reviewers ...string) map[string]interface{} {
+		var rs []map[string]interface{}
+		for _, r := range reviewers {
+			rs = append(rs, map[string]interface{}{"user": map[string]string{"name": r}})
+		}
+		return map[string]interface{}{
+			"id":        id,
+			"title":     title,
+			"author":    map[string]interface{}{"user": map[string]string{"name": author}},
+			"reviewers": rs,
+			"fromRef":   map[string]interface{}{"id": from},
+			"toRef": map[string]interface{}{
+				"id":         "refs/heads/master",
+				"repository": map[string]interface{}{"id": 42, "slug": "vegeta", "project": map[string]string{"key": "SOUR"}},
+			},
+		}
+	}
+
+	var queries []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rest/api/1.0/projects/SOUR/repos/vegeta/pull-requests" {
+			http.Error(w, "not found", http.StatusNotFound)
+			return
+		}
+		queries = append(queries, r.URL.RawQuery)
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"isLastPage": true,
+			"values": []interface{}{
+				pr(1, "Update dependency lodash [SECURITY]", "renovate", "refs/heads/renovate/lodash", "alice"),
+				pr(2, "Update dependency react", "renovate", "refs/heads/renovate/react"),
+				pr(3, "Fix security issue", "bob", "refs/heads/fix", "alice"),
+			},
+		})
+	}))
+	defer srv.Close()
+
+	svc := &ExternalService{
+		Kind:   "BITBUCKETSERVER",
+		Config: fmt.Sprintf(`{"url": %q, "token": "secret", "repos": ["SOUR/vegeta"]}`, srv.URL),
+	}
+	src, err := NewBitbucketServerSource(svc, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		name      string
+		query     string
+		wantQuery string
+		want      []string
+		err       string
+	}{
+		{
+			name:      "author and text",
+			query:     "repo:SOUR/vegeta author:renovate security state:all",
+			wantQuery: "limit=1000&state=ALL",
+			want:      []string{"1"},
+		},
+		{
+			name:      "reviewer and base",
+			query:     "repo:SOUR/vegeta reviewer:alice base:master",
+			wantQuery: "at=refs%2Fheads%2Fmaster&direction=INCOMING&limit=1000",
+			want:      []string{"1", "3"},
+		},
+		{
+			name:      "head",
+			query:     "repo:SOUR/vegeta head:renovate/react",
+			wantQuery: "limit=1000",
+			want:      []string{"2"},
+		},
+		{
+			name:  "no repo",
+			query: "author:renovate",
+			err:   "query must contain at least one repo:PROJECT/slug term",
+		},
+		{
+			name:  "unsupported term",
+			query: "repo:SOUR/vegeta label:security",
+			err:   `unsupported query term "label:security"`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			queries = nil
+
+			cs, err := src.SearchChangesets(context.Background(), tc.query)
+			if have, want := fmt.Sprint(err), fmt.Sprint(tc.err); tc.err != "" && have != want {
+				t.Fatalf("error: want %q, have %q", want, have)
+			} else if tc.err == "" && err != nil {
+				t.Fatal(err)
+			}
+			if tc.err != "" {
+				return
+			}
+
+			if diff := cmp.Diff([]string{tc.wantQuery}, queries); diff != "" {
+				t.Errorf("queries: %s", diff)
+			}
+
+			var have []string
+			for _, c := range cs {
+				have = append(have, c.ExternalID)
+				if c.Repo.ExternalRepo.ID != "42" || c.Repo.ExternalRepo.ServiceID != srv.URL+"/" {
+					t.Errorf("unexpected external repo: %+v", c.Repo.ExternalRepo)
+				}
+			}
+			if diff := cmp.Diff(tc.want, have); diff != "" {
+				t.Errorf("changesets: %s", diff)
+			}
+		})
+	}
+}
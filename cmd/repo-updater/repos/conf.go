@@ -13,3 +13,9 @@ func GetUpdateInterval() time.Duration {
 	}
 	return time.Duration(v) * time.Minute
 }
+
+// GetFullSyncInterval returns the minimum interval between full syncs. If
+// zero, every sync is a full sync.
+func GetFullSyncInterval() time.Duration {
+	return time.Duration(conf.Get().RepoListFullSyncInterval) * time.Minute
+}
@@ -12,6 +12,7 @@ import (
 
 	"github.com/inconshreveable/log15"
 	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/internal/campaigns"
 	"github.com/sourcegraph/sourcegraph/internal/conf/reposource"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/github"
@@ -127,9 +128,21 @@ type githubResult struct {
 // ListRepos returns all Github repositories accessible to all connections configured
 // in Sourcegraph via the external services configuration.
 func (s GithubSource) ListRepos(ctx context.Context, results chan SourceResult) {
+	s.listReposSince(ctx, time.Time{}, results)
+}
+
+// ListReposSince returns the Github repositories that ListRepos returns and
+// that were updated after since. Only the repositories of the `orgs` config
+// option and the `affiliated` and `org:<org-name>` repository queries are
+// filtered by when they were updated. All others are returned regardless.
+func (s GithubSource) ListReposSince(ctx context.Context, since time.Time, results chan SourceResult) {
+	s.listReposSince(ctx, since, results)
+}
+
+func (s GithubSource) listReposSince(ctx context.Context, since time.Time, results chan SourceResult) {
 	unfiltered := make(chan *githubResult)
 	go func() {
-		s.listAllRepositories(ctx, unfiltered)
+		s.listAllRepositories(ctx, since, unfiltered)
 		close(unfiltered)
 	}()
 
@@ -164,6 +177,7 @@ func (s GithubSource) CreateChangeset(ctx context.Context, c *Changeset) (bool,
 		Body:         c.Body,
 		HeadRefName:  git.AbbreviateRef(c.HeadRef),
 		BaseRefName:  git.AbbreviateRef(c.BaseRef),
+		Draft:        c.Draft,
 	})
 
 	if err != nil {
@@ -181,6 +195,14 @@ func (s GithubSource) CreateChangeset(ctx context.Context, c *Changeset) (bool,
 		exists = true
 	}
 
+	// Existing pull requests are brought up to date by UpdateChangeset.
+	if !exists {
+		pr.RepoWithOwner = repo.NameWithOwner
+		if err := s.addLabelsAndParticipants(ctx, c, pr); err != nil {
+			return false, err
+		}
+	}
+
 	if err := c.SetMetadata(pr); err != nil {
 		return false, errors.Wrap(err, "setting changeset metadata")
 	}
@@ -188,6 +210,57 @@ func (s GithubSource) CreateChangeset(ctx context.Context, c *Changeset) (bool,
 	return exists, nil
 }
 
+// addLabelsAndParticipants adds the labels, reviewers and assignees of the
+// given *Changeset to the pull request and reloads it if it changed.
+func (s GithubSource) addLabelsAndParticipants(ctx context.Context, c *Changeset, pr *github.PullRequest) error {
+	if len(c.Labels) == 0 && len(c.Reviewers) == 0 && len(c.Assignees) == 0 {
+		return nil
+	}
+
+	if len(c.Labels) > 0 {
+		if err := s.client.AddLabelsToPullRequest(ctx, pr, c.Labels); err != nil {
+			return errors.Wrap(err, "adding labels")
+		}
+	}
+
+	if len(c.Reviewers) > 0 {
+		users, teams := githubReviewers(c.Reviewers)
+		if err := s.client.RequestPullRequestReviewers(ctx, pr, users, teams); err != nil {
+			return errors.Wrap(err, "requesting reviewers")
+		}
+	}
+
+	if len(c.Assignees) > 0 {
+		if err := s.client.AddAssigneesToPullRequest(ctx, pr, c.Assignees); err != nil {
+			return errors.Wrap(err, "adding assignees")
+		}
+	}
+
+	return errors.Wrap(s.client.LoadPullRequests(ctx, pr), "reloading pull request")
+}
+
+// githubReviewers splits the given reviewers into users and teams. Teams are
+// given as "org/team-slug" and requested by their slug, without the
+// organization.
+func githubReviewers(reviewers []string) (users, teams []string) {
+	for _, r := range reviewers {
+		if i := strings.IndexByte(r, '/'); i >= 0 {
+			teams = append(teams, r[i+1:])
+		} else {
+			users = append(users, r)
+		}
+	}
+	return users, teams
+}
+
+func labelNames(pr *github.PullRequest) []string {
+	names := make([]string, 0, len(pr.Labels.Nodes))
+	for _, l := range pr.Labels.Nodes {
+		names = append(names, l.Name)
+	}
+	return names
+}
+
 // CloseChangeset closes the given *Changeset on the code host and updates the
 // Metadata column in the *campaigns.Changeset to the newly closed pull request.
 func (s GithubSource) CloseChangeset(ctx context.Context, c *Changeset) error {
@@ -206,6 +279,82 @@ func (s GithubSource) CloseChangeset(ctx context.Context, c *Changeset) error {
 	return nil
 }
 
+// MergeChangeset merges the given *Changeset on the code host and updates the
+// Metadata column in the *campaigns.Changeset to the newly merged pull request.
+func (s GithubSource) MergeChangeset(ctx context.Context, c *Changeset, method campaigns.ChangesetMergeMethod) error {
+	pr, ok := c.Changeset.Metadata.(*github.PullRequest)
+	if !ok {
+		return errors.New("Changeset is not a GitHub pull request")
+	}
+
+	// GitHub's merge methods are named like ours.
+	err := s.client.MergePullRequest(ctx, pr, string(method))
+	if err != nil {
+		return err
+	}
+
+	c.Changeset.Metadata = pr
+
+	return nil
+}
+
+// CreateComment posts a comment with the given body on the pull request of
+// the given *Changeset.
+func (s GithubSource) CreateComment(ctx context.Context, c *Changeset, body string) error {
+	pr, ok := c.Changeset.Metadata.(*github.PullRequest)
+	if !ok {
+		return errors.New("Changeset is not a GitHub pull request")
+	}
+
+	return s.client.CreatePullRequestComment(ctx, pr, body)
+}
+
+// AddLabels adds the given labels to the pull request of the given
+// *Changeset.
+func (s GithubSource) AddLabels(ctx context.Context, c *Changeset, labels []string) error {
+	pr, ok := c.Changeset.Metadata.(*github.PullRequest)
+	if !ok {
+		return errors.New("Changeset is not a GitHub pull request")
+	}
+
+	return s.client.AddLabelsToPullRequest(ctx, pr, labels)
+}
+
+// RemoveLabels removes the given labels from the pull request of the given
+// *Changeset.
+func (s GithubSource) RemoveLabels(ctx context.Context, c *Changeset, labels []string) error {
+	pr, ok := c.Changeset.Metadata.(*github.PullRequest)
+	if !ok {
+		return errors.New("Changeset is not a GitHub pull request")
+	}
+
+	return s.client.RemoveLabelsFromPullRequest(ctx, pr, labels)
+}
+
+// RequestReviewers requests reviews of the pull request of the given
+// *Changeset from the given users and "org/team-slug" teams.
+func (s GithubSource) RequestReviewers(ctx context.Context, c *Changeset, reviewers []string) error {
+	pr, ok := c.Changeset.Metadata.(*github.PullRequest)
+	if !ok {
+		return errors.New("Changeset is not a GitHub pull request")
+	}
+
+	users, teams := githubReviewers(reviewers)
+	return s.client.RequestPullRequestReviewers(ctx, pr, users, teams)
+}
+
+// RerunFailedChecks re-runs the failed and timed out check suites of the
+// head commit of the pull request of the given *Changeset.
+func (s GithubSource) RerunFailedChecks(ctx context.Context, c *Changeset) error {
+	pr, ok := c.Changeset.Metadata.(*github.PullRequest)
+	if !ok {
+		return errors.New("Changeset is not a GitHub pull request")
+	}
+
+	_, err := s.client.RerequestFailedCheckSuites(ctx, pr)
+	return err
+}
+
 // LoadChangesets loads the latest state of the given Changesets from the codehost.
 func (s GithubSource) LoadChangesets(ctx context.Context, cs ...*Changeset) error {
 	prs := make([]*github.PullRequest, len(cs))
@@ -236,6 +385,46 @@ func (s GithubSource) LoadChangesets(ctx context.Context, cs ...*Changeset) erro
 	return nil
 }
 
+var _ ChangesetSearcher = GithubSource{}
+
+// SearchChangesets returns the pull requests matching the given GitHub
+// search query, e.g. "author:app/renovate label:security".
+func (s GithubSource) SearchChangesets(ctx context.Context, query string) ([]*Changeset, error) {
+	var (
+		cs    []*Changeset
+		after string
+	)
+
+	for {
+		page, err := s.client.SearchPullRequests(ctx, query, after)
+		if err != nil {
+			return nil, errors.Wrapf(err, "searching GitHub pull requests: %q", query)
+		}
+
+		for _, pr := range page.PullRequests {
+			cs = append(cs, &Changeset{
+				Changeset: &campaigns.Changeset{
+					ExternalID:          strconv.FormatInt(pr.Number, 10),
+					ExternalServiceType: github.ServiceType,
+				},
+				Repo: &Repo{
+					Name: pr.Repository.NameWithOwner,
+					ExternalRepo: github.ExternalRepoSpec(&github.Repository{
+						ID: pr.Repository.ID,
+					}, *s.baseURL),
+				},
+			})
+		}
+
+		// GitHub's search API returns at most 1000 results, after which
+		// HasNextPage is false.
+		if !page.HasNextPage {
+			return cs, nil
+		}
+		after = page.EndCursor
+	}
+}
+
 // UpdateChangeset updates the given *Changeset in the code host.
 func (s GithubSource) UpdateChangeset(ctx context.Context, c *Changeset) error {
 	pr, ok := c.Changeset.Metadata.(*github.PullRequest)
@@ -254,6 +443,31 @@ func (s GithubSource) UpdateChangeset(ctx context.Context, c *Changeset) error {
 		return err
 	}
 
+	if updated.IsDraft != c.Draft {
+		if c.Draft {
+			err = s.client.ConvertPullRequestToDraft(ctx, updated)
+		} else {
+			err = s.client.MarkPullRequestReadyForReview(ctx, updated)
+		}
+		if err != nil {
+			return errors.Wrap(err, "updating draft state")
+		}
+	}
+
+	// Only the labels, reviewers and assignees that the pull request doesn't
+	// have yet are added, so that reviewers who already reviewed it aren't
+	// requested to review it again.
+	existing := &campaigns.Changeset{Metadata: updated}
+	missing := *c
+	missing.Labels = campaigns.MissingNames(labelNames(updated), c.Labels)
+	missing.Reviewers = campaigns.MissingReviewers(existing.ReviewerNames(), c.Reviewers)
+	missing.Assignees = campaigns.MissingNames(existing.AssigneeNames(), c.Assignees)
+
+	updated.RepoWithOwner = c.Repo.Metadata.(*github.Repository).NameWithOwner
+	if err := s.addLabelsAndParticipants(ctx, &missing, updated); err != nil {
+		return err
+	}
+
 	c.Changeset.Metadata = updated
 
 	return nil
@@ -282,11 +496,12 @@ func (s GithubSource) makeRepo(r *github.Repository) *Repo {
 			s.originalHostname,
 			r.NameWithOwner,
 		)),
-		ExternalRepo: github.ExternalRepoSpec(r, *s.baseURL),
-		Description:  r.Description,
-		Fork:         r.IsFork,
-		Archived:     r.IsArchived,
-		Private:      r.IsPrivate,
+		ExternalRepo:  github.ExternalRepoSpec(r, *s.baseURL),
+		Description:   r.Description,
+		Fork:          r.IsFork,
+		Archived:      r.IsArchived,
+		Private:       r.IsPrivate,
+		KeyValuePairs: importedKeyValuePairs(r),
 		Sources: map[string]*SourceInfo{
 			urn: {
 				ID:       urn,
@@ -374,8 +589,10 @@ func (s *GithubSource) paginate(ctx context.Context, results chan *githubResult,
 // It returns all the repositories belonging to the given organization
 // by hitting the /orgs/:org/repos endpoint.
 //
+// If since is non-zero, it only returns the repositories updated after since.
+//
 // It returns an error if the request fails on the first page.
-func (s *GithubSource) listOrg(ctx context.Context, org string, results chan *githubResult) {
+func (s *GithubSource) listOrg(ctx context.Context, org string, since time.Time, results chan *githubResult) {
 	var oerr error
 	s.paginate(ctx, results, func(page int) (repos []*github.Repository, hasNext bool, cost int, err error) {
 		defer func() {
@@ -397,11 +614,14 @@ func (s *GithubSource) listOrg(ctx context.Context, org string, results chan *gi
 				"retryAfter", retry,
 			)
 		}()
+		if !since.IsZero() {
+			return s.client.ListOrgRepositoriesUpdatedSince(ctx, org, since, page)
+		}
 		return s.client.ListOrgRepositories(ctx, org, page)
 	})
 
 	// Handle 404 from org repos endpoint by trying user repos endpoint
-	if oerr != nil && s.listUser(ctx, org, results) != nil {
+	if oerr != nil && s.listUser(ctx, org, since, results) != nil {
 		results <- &githubResult{
 			err: oerr,
 		}
@@ -410,9 +630,10 @@ func (s *GithubSource) listOrg(ctx context.Context, org string, results chan *gi
 
 // listUser returns all the repositories belonging to the given user
 // by hitting the /users/:user/repos endpoint.
+// If since is non-zero, it only returns the repositories updated after since.
 //
 // It returns an error if the request fails on the first page.
-func (s *GithubSource) listUser(ctx context.Context, user string, results chan *githubResult) (fail error) {
+func (s *GithubSource) listUser(ctx context.Context, user string, since time.Time, results chan *githubResult) (fail error) {
 	s.paginate(ctx, results, func(page int) (repos []*github.Repository, hasNext bool, cost int, err error) {
 		defer func() {
 			if err != nil && page == 1 {
@@ -429,6 +650,9 @@ func (s *GithubSource) listUser(ctx context.Context, user string, results chan *
 				"retryAfter", retry,
 			)
 		}()
+		if !since.IsZero() {
+			return s.client.ListUserRepositoriesUpdatedSince(ctx, user, since, page)
+		}
 		return s.client.ListUserRepositories(ctx, user, page)
 	})
 	return
@@ -523,7 +747,9 @@ func (s *GithubSource) listPublic(ctx context.Context, results chan *githubResul
 //
 // Affiliation is present if the user: (1) owns the repo, (2) is apart of an org that
 // the repo belongs to, or (3) is a collaborator.
-func (s *GithubSource) listAffiliated(ctx context.Context, results chan *githubResult) {
+//
+// If since is non-zero, it only returns the repositories updated after since.
+func (s *GithubSource) listAffiliated(ctx context.Context, since time.Time, results chan *githubResult) {
 	s.paginate(ctx, results, func(page int) (repos []*github.Repository, hasNext bool, cost int, err error) {
 		defer func() {
 			remaining, reset, retry, _ := s.client.RateLimit.Get()
@@ -536,6 +762,9 @@ func (s *GithubSource) listAffiliated(ctx context.Context, results chan *githubR
 				"retryAfter", retry,
 			)
 		}()
+		if !since.IsZero() {
+			return s.client.ListAffiliatedRepositoriesUpdatedSince(ctx, since, page)
+		}
 		return s.client.ListAffiliatedRepositories(ctx, page)
 	})
 }
@@ -609,13 +838,13 @@ func matchOrg(q string) string {
 // - `none`: disables `repositoryQuery`
 // Inputs other than these three keywords will be queried using
 // GitHub advanced repository search (endpoint: /search/repositories)
-func (s *GithubSource) listRepositoryQuery(ctx context.Context, query string, results chan *githubResult) {
+func (s *GithubSource) listRepositoryQuery(ctx context.Context, query string, since time.Time, results chan *githubResult) {
 	switch query {
 	case "public":
 		s.listPublic(ctx, results)
 		return
 	case "affiliated":
-		s.listAffiliated(ctx, results)
+		s.listAffiliated(ctx, since, results)
 		return
 	case "none":
 		// nothing
@@ -630,7 +859,7 @@ func (s *GithubSource) listRepositoryQuery(ctx context.Context, query string, re
 	// If the org repo list API fails, we
 	// try the user repo list API.
 	if org := matchOrg(query); org != "" {
-		s.listOrg(ctx, org, results)
+		s.listOrg(ctx, org, since, results)
 		return
 	}
 
@@ -641,17 +870,19 @@ func (s *GithubSource) listRepositoryQuery(ctx context.Context, query string, re
 
 // listAllRepositories returns the repositories from the given `orgs`, `repos`, and
 // `repositoryQuery` config options excluding the ones specified by `exclude`.
-func (s *GithubSource) listAllRepositories(ctx context.Context, results chan *githubResult) {
+// If since is non-zero, the ones that can be filtered by when they were updated
+// are only returned if they were updated after since.
+func (s *GithubSource) listAllRepositories(ctx context.Context, since time.Time, results chan *githubResult) {
 	s.listRepos(ctx, s.config.Repos, results)
 
 	// Admins normally add to end of lists, so end of list most likely has new
 	// repos => stream them first.
 	for i := len(s.config.RepositoryQuery) - 1; i >= 0; i-- {
-		s.listRepositoryQuery(ctx, s.config.RepositoryQuery[i], results)
+		s.listRepositoryQuery(ctx, s.config.RepositoryQuery[i], since, results)
 	}
 
 	for i := len(s.config.Orgs) - 1; i >= 0; i-- {
-		s.listOrg(ctx, s.config.Orgs[i], results)
+		s.listOrg(ctx, s.config.Orgs[i], since, results)
 	}
 }
 
@@ -89,7 +89,14 @@ func newGitLabSource(svc *ExternalService, c *schema.GitLabConnection, cf *httpc
 // ListRepos returns all GitLab repositories accessible to all connections configured
 // in Sourcegraph via the external services configuration.
 func (s GitLabSource) ListRepos(ctx context.Context, results chan SourceResult) {
-	s.listAllProjects(ctx, results)
+	s.listAllProjects(ctx, time.Time{}, results)
+}
+
+// ListReposSince returns the GitLab repositories that ListRepos returns and
+// that had activity after since. Only the projects of the `projectQuery` config
+// option are filtered by their last activity.
+func (s GitLabSource) ListReposSince(ctx context.Context, since time.Time, results chan SourceResult) {
+	s.listAllProjects(ctx, since, results)
 }
 
 // GetRepo returns the GitLab repository with the given pathWithNamespace.
@@ -126,11 +133,12 @@ func (s GitLabSource) makeRepo(proj *gitlab.Project) *Repo {
 			proj.PathWithNamespace,
 			s.nameTransformations,
 		)),
-		ExternalRepo: gitlab.ExternalRepoSpec(proj, *s.baseURL),
-		Description:  proj.Description,
-		Fork:         proj.ForkedFromProject != nil,
-		Archived:     proj.Archived,
-		Private:      proj.Visibility == "private",
+		ExternalRepo:  gitlab.ExternalRepoSpec(proj, *s.baseURL),
+		Description:   proj.Description,
+		Fork:          proj.ForkedFromProject != nil,
+		Archived:      proj.Archived,
+		Private:       proj.Visibility == "private",
+		KeyValuePairs: importedKeyValuePairs(proj),
 		Sources: map[string]*SourceInfo{
 			urn: {
 				ID:       urn,
@@ -164,7 +172,7 @@ func (s *GitLabSource) excludes(p *gitlab.Project) bool {
 	return s.exclude(p.PathWithNamespace) || s.exclude(strconv.Itoa(p.ID))
 }
 
-func (s *GitLabSource) listAllProjects(ctx context.Context, results chan SourceResult) {
+func (s *GitLabSource) listAllProjects(ctx context.Context, since time.Time, results chan SourceResult) {
 	type batch struct {
 		projs []*gitlab.Project
 		err   error
@@ -228,7 +236,7 @@ func (s *GitLabSource) listAllProjects(ctx context.Context, results chan SourceR
 		go func(projectQuery string) {
 			defer wg.Done()
 
-			url, err := projectQueryToURL(projectQuery, perPage) // first page URL
+			url, err := projectQueryToURL(projectQuery, perPage, since) // first page URL
 			if err != nil {
 				ch <- batch{err: errors.Wrapf(err, "invalid GitLab projectQuery=%q", projectQuery)}
 				return
@@ -279,7 +287,10 @@ func (s *GitLabSource) listAllProjects(ctx context.Context, results chan SourceR
 
 var schemeOrHostNotEmptyErr = errors.New("scheme and host should be empty")
 
-func projectQueryToURL(projectQuery string, perPage int) (string, error) {
+// projectQueryToURL returns the URL of the first page of the given project
+// query. If since is non-zero, the query only matches projects with activity
+// after since.
+func projectQueryToURL(projectQuery string, perPage int, since time.Time) (string, error) {
 	// If all we have is the URL query, prepend "projects"
 	if strings.HasPrefix(projectQuery, "?") {
 		projectQuery = "projects" + projectQuery
@@ -296,6 +307,9 @@ func projectQueryToURL(projectQuery string, perPage int) (string, error) {
 	}
 	q := u.Query()
 	q.Set("per_page", strconv.Itoa(perPage))
+	if !since.IsZero() {
+		q.Set("last_activity_after", since.UTC().Format(time.RFC3339))
+	}
 	u.RawQuery = q.Encode()
 
 	return u.String(), nil
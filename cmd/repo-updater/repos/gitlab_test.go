@@ -8,6 +8,7 @@ import (
 	"path/filepath"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/inconshreveable/log15"
@@ -22,6 +23,7 @@ func Test_projectQueryToURL(t *testing.T) {
 	tests := []struct {
 		projectQuery string
 		perPage      int
+		since        time.Time
 		expURL       string
 		expErr       error
 	}{{
@@ -44,6 +46,11 @@ func Test_projectQueryToURL(t *testing.T) {
 		projectQuery: "",
 		perPage:      100,
 		expURL:       "projects?per_page=100",
+	}, {
+		projectQuery: "?membership=true",
+		perPage:      100,
+		since:        time.Date(2020, 3, 1, 12, 0, 0, 0, time.UTC),
+		expURL:       "projects?last_activity_after=2020-03-01T12%3A00%3A00Z&membership=true&per_page=100",
 	}, {
 		projectQuery: "https://somethingelse.com/foo/bar",
 		perPage:      100,
@@ -52,7 +59,7 @@ func Test_projectQueryToURL(t *testing.T) {
 
 	for _, test := range tests {
 		t.Logf("Test case %+v", test)
-		url, err := projectQueryToURL(test.projectQuery, test.perPage)
+		url, err := projectQueryToURL(test.projectQuery, test.perPage, test.since)
 		if url != test.expURL {
 			t.Errorf("expected %v, got %v", test.expURL, url)
 		}
@@ -107,6 +107,8 @@ func (s GitoliteSource) makeRepo(repo *gitolite.Repo) *Repo {
 		Name:         name,
 		URI:          name,
 		ExternalRepo: gitolite.ExternalRepoSpec(repo, gitolite.ServiceID(s.conn.Host)),
+		// Repository permissions are only enforced for private repositories.
+		Private: s.conn.Authorization != nil,
 		Sources: map[string]*SourceInfo{
 			urn: {
 				ID:       urn,
@@ -0,0 +1,131 @@
+package repos
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/manifest"
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
+	"github.com/sourcegraph/sourcegraph/internal/jsonc"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+// A ManifestSource yields the repositories listed in a manifest configured in
+// Sourcegraph via the external services configuration. The manifest is
+// fetched again on every call to ListRepos, so that changes to it are picked
+// up by the next sync.
+type ManifestSource struct {
+	svc     *ExternalService
+	conn    *schema.ManifestConnection
+	client  httpcli.Doer
+	exclude excludeFunc
+}
+
+// NewManifestSource returns a new ManifestSource from the given external service.
+func NewManifestSource(svc *ExternalService, cf *httpcli.Factory) (*ManifestSource, error) {
+	var c schema.ManifestConnection
+	if err := jsonc.Unmarshal(svc.Config, &c); err != nil {
+		return nil, errors.Wrapf(err, "external service id=%d config error", svc.ID)
+	}
+
+	if cf == nil {
+		cf = httpcli.NewExternalHTTPClientFactory()
+	}
+
+	cli, err := cf.Doer()
+	if err != nil {
+		return nil, err
+	}
+
+	var eb excludeBuilder
+	for _, r := range c.Exclude {
+		eb.Exact(r.Name)
+	}
+	exclude, err := eb.Build()
+	if err != nil {
+		return nil, err
+	}
+
+	return &ManifestSource{svc: svc, conn: &c, client: cli, exclude: exclude}, nil
+}
+
+// ListRepos returns all repositories listed in the manifest.
+func (s *ManifestSource) ListRepos(ctx context.Context, results chan SourceResult) {
+	data, err := s.fetch(ctx)
+	if err != nil {
+		results <- SourceResult{Source: s, Err: err}
+		return
+	}
+
+	m, err := manifest.Parse(data)
+	if err != nil {
+		results <- SourceResult{Source: s, Err: err}
+		return
+	}
+
+	for _, r := range m.Repos {
+		if !s.exclude(r.Name) {
+			results <- SourceResult{Source: s, Repo: s.makeRepo(r)}
+		}
+	}
+}
+
+// ExternalServices returns a singleton slice containing the external service.
+func (s *ManifestSource) ExternalServices() ExternalServices {
+	return ExternalServices{s.svc}
+}
+
+// fetch reads the manifest from a local file or over HTTP(S).
+func (s *ManifestSource) fetch(ctx context.Context) ([]byte, error) {
+	if !strings.HasPrefix(s.conn.Url, "http://") && !strings.HasPrefix(s.conn.Url, "https://") {
+		data, err := ioutil.ReadFile(strings.TrimPrefix(s.conn.Url, "file://"))
+		return data, errors.Wrap(err, "failed to read manifest")
+	}
+
+	req, err := http.NewRequest("GET", s.conn.Url, nil)
+	if err != nil {
+		return nil, err
+	}
+	if s.conn.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+s.conn.Token)
+	}
+
+	resp, err := s.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to fetch manifest")
+	}
+	defer resp.Body.Close()
+
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read manifest")
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("failed to fetch manifest: unexpected status %d: %s", resp.StatusCode, string(data))
+	}
+	return data, nil
+}
+
+func (s *ManifestSource) makeRepo(r *manifest.Repo) *Repo {
+	urn := s.svc.URN()
+	return &Repo{
+		Name:          r.Name,
+		URI:           r.Name,
+		Description:   r.Description,
+		Fork:          r.Fork,
+		Archived:      r.Archived,
+		Private:       r.Private(),
+		KeyValuePairs: importedKeyValuePairs(r),
+		ExternalRepo:  manifest.ExternalRepoSpec(r, s.conn.Url),
+		Sources: map[string]*SourceInfo{
+			urn: {
+				ID:       urn,
+				CloneURL: r.CloneURL,
+			},
+		},
+		Metadata: r,
+	}
+}
@@ -0,0 +1,183 @@
+package repos
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/manifest"
+)
+
+func TestManifestSource_ListRepos(t *testing.T) {
+	const body = `
+repos:
+  - name: services/billing
+    cloneURL: https://git.example.com/services/billing.git
+    description: Billing service
+    visibility: private
+    metadata:
+      owner: payments
+  - name: tools/legacy
+    cloneURL: https://git.example.com/tools/legacy.git
+    archived: true
+`
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte(body))
+	}))
+	defer srv.Close()
+
+	dir, err := ioutil.TempDir("", "manifest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "manifest.yaml")
+	if err := ioutil.WriteFile(path, []byte(body), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	owner := "payments"
+	billing := func(serviceID string) *Repo {
+		return &Repo{
+			Name:          "services/billing",
+			URI:           "services/billing",
+			Description:   "Billing service",
+			Private:       true,
+			KeyValuePairs: map[string]*string{"owner": &owner},
+			ExternalRepo: api.ExternalRepoSpec{
+				ID:          "services/billing",
+				ServiceType: "manifest",
+				ServiceID:   serviceID,
+			},
+			Sources: map[string]*SourceInfo{
+				"extsvc:manifest:1": {
+					ID:       "extsvc:manifest:1",
+					CloneURL: "https://git.example.com/services/billing.git",
+				},
+			},
+			Metadata: &manifest.Repo{
+				Name:        "services/billing",
+				CloneURL:    "https://git.example.com/services/billing.git",
+				Description: "Billing service",
+				Visibility:  "private",
+				Metadata:    map[string]string{"owner": "payments"},
+			},
+		}
+	}
+
+	legacy := func(serviceID string) *Repo {
+		return &Repo{
+			Name:     "tools/legacy",
+			URI:      "tools/legacy",
+			Archived: true,
+			ExternalRepo: api.ExternalRepoSpec{
+				ID:          "tools/legacy",
+				ServiceType: "manifest",
+				ServiceID:   serviceID,
+			},
+			Sources: map[string]*SourceInfo{
+				"extsvc:manifest:1": {
+					ID:       "extsvc:manifest:1",
+					CloneURL: "https://git.example.com/tools/legacy.git",
+				},
+			},
+			Metadata: &manifest.Repo{
+				Name:     "tools/legacy",
+				CloneURL: "https://git.example.com/tools/legacy.git",
+				Archived: true,
+			},
+		}
+	}
+
+	for _, tc := range []struct {
+		name   string
+		config string
+		want   []*Repo
+		err    string
+	}{
+		{
+			name:   "http",
+			config: fmt.Sprintf(`{"url": %q, "token": "secret"}`, srv.URL),
+			want:   []*Repo{billing(srv.URL), legacy(srv.URL)},
+		},
+		{
+			name:   "http unauthorized",
+			config: fmt.Sprintf(`{"url": %q}`, srv.URL),
+			err:    "failed to fetch manifest: unexpected status 401",
+		},
+		{
+			name:   "file",
+			config: fmt.Sprintf(`{"url": %q, "exclude": [{"name": "tools/legacy"}]}`, "file://"+path),
+			want:   []*Repo{billing("file://" + path)},
+		},
+		{
+			name:   "missing file",
+			config: fmt.Sprintf(`{"url": %q}`, filepath.Join(dir, "nope.yaml")),
+			err:    "failed to read manifest",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			src, err := NewManifestSource(&ExternalService{
+				ID:     1,
+				Kind:   "MANIFEST",
+				Config: tc.config,
+			}, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			repos, err := listAll(context.Background(), src)
+			if got := fmt.Sprintf("%v", err); !strings.Contains(got, tc.err) {
+				t.Fatalf("got error %v, want %v", got, tc.err)
+			}
+
+			if diff := cmp.Diff(tc.want, repos); diff != "" {
+				t.Fatalf("repos: %s", diff)
+			}
+		})
+	}
+}
+
+func TestManifestSource_Diff(t *testing.T) {
+	stored := &Repo{
+		ID:   1,
+		Name: "services/billing",
+		ExternalRepo: api.ExternalRepoSpec{
+			ID:          "services/billing",
+			ServiceType: "manifest",
+			ServiceID:   "https://catalog.example.com/manifest.json",
+		},
+		Metadata: &manifest.Repo{
+			Name:     "services/billing",
+			CloneURL: "https://git.example.com/services/billing.git",
+			Metadata: map[string]string{"owner": "payments"},
+		},
+	}
+
+	sourced := stored.Clone()
+	sourced.ID = 0
+	sourced.Metadata = &manifest.Repo{
+		Name:     "services/billing",
+		CloneURL: "https://git.example.com/services/billing.git",
+		Metadata: map[string]string{"owner": "billing"},
+	}
+
+	diff := NewDiff([]*Repo{sourced}, []*Repo{stored})
+	if len(diff.Modified) != 1 || len(diff.Added) != 0 || len(diff.Deleted) != 0 {
+		t.Fatalf("expected a changed metadata key to modify the repo, got %+v", diff)
+	}
+}
@@ -100,6 +100,20 @@ func NewSourceMetrics() SourceMetrics {
 
 // ListRepos calls into the inner Source registers the observed results.
 func (o *observedSource) ListRepos(ctx context.Context, results chan SourceResult) {
+	o.observe("source.list-repos", results, func(uncounted chan SourceResult) {
+		o.Source.ListRepos(ctx, uncounted)
+	})
+}
+
+// ListReposSince calls into the inner Source registers the observed results.
+// If the inner Source isn't an IncrementalSource, it lists all repos.
+func (o *observedSource) ListReposSince(ctx context.Context, since time.Time, results chan SourceResult) {
+	o.observe("source.list-repos-since", results, func(uncounted chan SourceResult) {
+		listReposSince(ctx, o.Source, since, uncounted)
+	})
+}
+
+func (o *observedSource) observe(msg string, results chan SourceResult, list func(chan SourceResult)) {
 	var (
 		err   error
 		count float64
@@ -108,12 +122,12 @@ func (o *observedSource) ListRepos(ctx context.Context, results chan SourceResul
 	defer func(began time.Time) {
 		secs := time.Since(began).Seconds()
 		o.metrics.ListRepos.Observe(secs, count, &err)
-		log(o.log, "source.list-repos", &err)
+		log(o.log, msg, &err)
 	}(time.Now())
 
 	uncounted := make(chan SourceResult)
 	go func() {
-		o.Source.ListRepos(ctx, uncounted)
+		list(uncounted)
 		close(uncounted)
 	}()
 
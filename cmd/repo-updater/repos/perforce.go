@@ -0,0 +1,141 @@
+package repos
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/internal/conf/reposource"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/perforce"
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
+	"github.com/sourcegraph/sourcegraph/internal/jsonc"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+// A PerforceSource yields depots and streams from a single Perforce Server
+// configured in Sourcegraph via the external services configuration. Each of
+// them is imported by gitserver as a Git repository with git p4.
+type PerforceSource struct {
+	svc     *ExternalService
+	conn    *schema.PerforceConnection
+	client  *perforce.Client
+	exclude excludeFunc
+}
+
+// NewPerforceSource returns a new PerforceSource from the given external service.
+func NewPerforceSource(svc *ExternalService, _ *httpcli.Factory) (*PerforceSource, error) {
+	var c schema.PerforceConnection
+	if err := jsonc.Unmarshal(svc.Config, &c); err != nil {
+		return nil, errors.Wrapf(err, "external service id=%d config error", svc.ID)
+	}
+	return newPerforceSource(svc, &c)
+}
+
+func newPerforceSource(svc *ExternalService, c *schema.PerforceConnection) (*PerforceSource, error) {
+	var eb excludeBuilder
+	for _, r := range c.Exclude {
+		eb.Exact(r.Path)
+	}
+	exclude, err := eb.Build()
+	if err != nil {
+		return nil, err
+	}
+
+	return &PerforceSource{
+		svc:     svc,
+		conn:    c,
+		client:  perforce.NewClient(c.P4port, c.P4user, c.P4passwd),
+		exclude: exclude,
+	}, nil
+}
+
+// ListRepos returns all Perforce depots and streams configured in the
+// external service.
+func (s *PerforceSource) ListRepos(ctx context.Context, results chan SourceResult) {
+	depots, err := s.listDepots(ctx)
+	if err != nil {
+		results <- SourceResult{Source: s, Err: err}
+		return
+	}
+
+	for _, d := range depots {
+		if !s.exclude(d.Path) {
+			results <- SourceResult{Source: s, Repo: s.makeRepo(d)}
+		}
+	}
+}
+
+// ExternalServices returns a singleton slice containing the external service.
+func (s *PerforceSource) ExternalServices() ExternalServices {
+	return ExternalServices{s.svc}
+}
+
+// listDepots resolves the configured depot paths into the depots and streams
+// to import. Stream depots are expanded into their streams.
+func (s *PerforceSource) listDepots(ctx context.Context) ([]*perforce.Depot, error) {
+	all, err := s.client.ListDepots(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	byPath := make(map[string]*perforce.Depot, len(all))
+	for _, d := range all {
+		byPath[d.Path] = d
+	}
+
+	paths := s.conn.Depots
+	if len(paths) == 0 {
+		for _, d := range all {
+			if d.Type == "local" || d.Type == "stream" {
+				paths = append(paths, d.Path)
+			}
+		}
+	}
+
+	var depots []*perforce.Depot
+	for _, p := range paths {
+		root := "//" + strings.SplitN(strings.TrimPrefix(p, "//"), "/", 2)[0] + "/"
+		d, ok := byPath[root]
+		if !ok {
+			return nil, errors.Errorf("perforce depot %q not found", root)
+		}
+
+		switch {
+		case d.Type == "stream" && p == root:
+			streams, err := s.client.ListStreams(ctx, root)
+			if err != nil {
+				return nil, err
+			}
+			depots = append(depots, streams...)
+		case p == root:
+			depots = append(depots, d)
+		default:
+			depots = append(depots, &perforce.Depot{
+				Path:   p,
+				Type:   d.Type,
+				Stream: d.Type == "stream",
+			})
+		}
+	}
+
+	return depots, nil
+}
+
+func (s *PerforceSource) makeRepo(d *perforce.Depot) *Repo {
+	urn := s.svc.URN()
+	name := string(reposource.PerforceRepoName(s.conn.RepositoryPathPattern, s.conn.P4port, d.Path))
+
+	return &Repo{
+		Name:         name,
+		URI:          name,
+		Description:  d.Description,
+		ExternalRepo: perforce.ExternalRepoSpec(d, perforce.ServiceID(s.conn.P4port)),
+		Sources: map[string]*SourceInfo{
+			urn: {
+				ID:       urn,
+				CloneURL: perforce.CloneURL(s.conn.P4port, s.conn.P4user, s.conn.P4passwd, d.Path),
+			},
+		},
+		Metadata: d,
+	}
+}
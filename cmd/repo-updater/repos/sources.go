@@ -9,6 +9,7 @@ import (
 
 	multierror "github.com/hashicorp/go-multierror"
 	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/internal/campaigns"
 	"github.com/sourcegraph/sourcegraph/internal/httpcli"
 )
 
@@ -67,6 +68,10 @@ func NewSource(svc *ExternalService, cf *httpcli.Factory) (Source, error) {
 		return NewPhabricatorSource(svc, cf)
 	case "awscodecommit":
 		return NewAWSCodeCommitSource(svc, cf)
+	case "perforce":
+		return NewPerforceSource(svc, cf)
+	case "manifest":
+		return NewManifestSource(svc, cf)
 	case "other":
 		return NewOtherSource(svc, cf)
 	default:
@@ -87,6 +92,27 @@ type Source interface {
 	ExternalServices() ExternalServices
 }
 
+// An IncrementalSource is a Source that can list only the repos that were
+// created or updated since a given time. Repos deleted on the code host can't
+// be detected this way, so they're only detected by ListRepos.
+type IncrementalSource interface {
+	Source
+	// ListReposSince sends the repos a source yields that were created or
+	// updated after since over the passed in channel as SourceResults. It may
+	// send repos that weren't updated, too.
+	ListReposSince(ctx context.Context, since time.Time, results chan SourceResult)
+}
+
+// listReposSince calls ListReposSince on the given Source if it's an
+// IncrementalSource and ListRepos otherwise.
+func listReposSince(ctx context.Context, src Source, since time.Time, results chan SourceResult) {
+	if is, ok := src.(IncrementalSource); ok {
+		is.ListReposSince(ctx, since, results)
+	} else {
+		src.ListRepos(ctx, results)
+	}
+}
+
 // A ChangesetSource can load the latest state of a list of Changesets.
 type ChangesetSource interface {
 	// LoadChangesets loads the given Changesets from the sources and updates
@@ -103,6 +129,34 @@ type ChangesetSource interface {
 	CloseChangeset(context.Context, *Changeset) error
 	// UpdateChangeset can update Changesets.
 	UpdateChangeset(context.Context, *Changeset) error
+	// MergeChangeset will merge the Changeset on the source with the given
+	// merge method.
+	MergeChangeset(context.Context, *Changeset, campaigns.ChangesetMergeMethod) error
+	// CreateComment posts a comment with the given body on the Changeset.
+	CreateComment(ctx context.Context, c *Changeset, body string) error
+	// AddLabels adds the given labels to the Changeset.
+	AddLabels(ctx context.Context, c *Changeset, labels []string) error
+	// RemoveLabels removes the given labels from the Changeset, ignoring the
+	// ones it doesn't have.
+	RemoveLabels(ctx context.Context, c *Changeset, labels []string) error
+	// RequestReviewers requests reviews of the Changeset from the given
+	// users or teams.
+	RequestReviewers(ctx context.Context, c *Changeset, reviewers []string) error
+	// RerunFailedChecks re-runs the failed checks of the Changeset.
+	RerunFailedChecks(context.Context, *Changeset) error
+}
+
+// ErrChangesetActionNotSupported is returned by the methods of a
+// ChangesetSource that perform an action the code host doesn't support.
+var ErrChangesetActionNotSupported = errors.New("action not supported by code host")
+
+// A ChangesetSearcher can search for existing changesets on a code host.
+type ChangesetSearcher interface {
+	// SearchChangesets returns the changesets matching the given query, in
+	// the query syntax of the code host. The returned Changesets only have
+	// their ExternalID and ExternalServiceType set and a Repo with only its
+	// Name and ExternalRepo set.
+	SearchChangesets(ctx context.Context, query string) ([]*Changeset, error)
 }
 
 // ChangesetsNotFoundError is returned by LoadChangesets if any of the passed
@@ -176,6 +230,18 @@ type Sources []Source
 // ListRepos lists all the repos of all the sources and returns the
 // aggregate result.
 func (srcs Sources) ListRepos(ctx context.Context, results chan SourceResult) {
+	srcs.each(func(src Source) { src.ListRepos(ctx, results) })
+}
+
+// ListReposSince lists the repos of all the sources that were created or
+// updated after since and returns the aggregate result. Sources that aren't
+// IncrementalSources list all their repos.
+func (srcs Sources) ListReposSince(ctx context.Context, since time.Time, results chan SourceResult) {
+	srcs.each(func(src Source) { listReposSince(ctx, src, since, results) })
+}
+
+// each calls f with each of the sources and waits for all calls to return.
+func (srcs Sources) each(f func(Source)) {
 	if len(srcs) == 0 {
 		return
 	}
@@ -191,7 +257,7 @@ func (srcs Sources) ListRepos(ctx context.Context, results chan SourceResult) {
 		go func(sources Sources) {
 			defer wg.Done()
 			for _, src := range sources {
-				src.ListRepos(ctx, results)
+				f(src)
 			}
 		}(sources)
 	}
@@ -238,10 +304,22 @@ func group(srcs []Source) map[string]Sources {
 // listAll calls ListRepos on the given Source and collects the SourceResults
 // the Source sends over a channel into a slice of *Repo and a single error
 func listAll(ctx context.Context, src Source, observe ...func(*Repo)) ([]*Repo, error) {
+	return collect(func(results chan SourceResult) { src.ListRepos(ctx, results) }, observe...)
+}
+
+// listAllSince is like listAll, but only collects the repos that were created
+// or updated after since from IncrementalSources.
+func listAllSince(ctx context.Context, src Source, since time.Time, observe ...func(*Repo)) ([]*Repo, error) {
+	return collect(func(results chan SourceResult) { listReposSince(ctx, src, since, results) }, observe...)
+}
+
+// collect collects the SourceResults sent by list into a slice of *Repo and a
+// single error.
+func collect(list func(chan SourceResult), observe ...func(*Repo)) ([]*Repo, error) {
 	results := make(chan SourceResult)
 
 	go func() {
-		src.ListRepos(ctx, results)
+		list(results)
 		close(results)
 	}()
 
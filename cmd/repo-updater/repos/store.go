@@ -18,6 +18,8 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/github"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitlab"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitolite"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/manifest"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/perforce"
 )
 
 // A Store exposes methods to read and write repos and external services.
@@ -48,9 +50,17 @@ type StoreListReposArgs struct {
 	PerPage int64
 	// Only include private repositories.
 	PrivateOnly bool
+	// ExternalServiceURN of the external service whose repos to list (see ExternalService.URN).
+	// When zero-valued, this is omitted from the predicate set.
+	ExternalServiceURN string
 
 	// UseOr decides between ANDing or ORing the predicates together.
 	UseOr bool
+
+	// ForUpdate locks the listed repos until the end of the current transaction, so that
+	// they can't be changed concurrently (e.g. their key/value pairs) before they're
+	// written back with UpsertRepos.
+	ForUpdate bool
 }
 
 // StoreListExternalServicesArgs is a query arguments type used by
@@ -329,6 +339,7 @@ SELECT
   archived,
   fork,
   private,
+  key_value_pairs,
   sources,
   metadata
 FROM repo
@@ -336,6 +347,7 @@ WHERE id > %s
 AND %s
 AND deleted_at IS NULL
 ORDER BY id ASC LIMIT %s
+%s
 `
 
 func listReposQuery(args StoreListReposArgs) paginatedQuery {
@@ -380,6 +392,10 @@ func listReposQuery(args StoreListReposArgs) paginatedQuery {
 		preds = append(preds, sqlf.Sprintf("private = TRUE"))
 	}
 
+	if args.ExternalServiceURN != "" {
+		preds = append(preds, sqlf.Sprintf("sources ? %s", args.ExternalServiceURN))
+	}
+
 	if len(preds) == 0 {
 		preds = append(preds, sqlf.Sprintf("TRUE"))
 	}
@@ -391,12 +407,18 @@ func listReposQuery(args StoreListReposArgs) paginatedQuery {
 		predQ = sqlf.Join(preds, "\n AND ")
 	}
 
+	lock := sqlf.Sprintf("")
+	if args.ForUpdate {
+		lock = sqlf.Sprintf("FOR UPDATE")
+	}
+
 	return func(cursor, limit int64) *sqlf.Query {
 		return sqlf.Sprintf(
 			listReposQueryFmtstr,
 			cursor,
 			sqlf.Sprintf("(%s)", predQ),
 			limit,
+			lock,
 		)
 	}
 }
@@ -575,6 +597,7 @@ func batchReposQuery(fmtstr string, repos []*Repo) (_ *sqlf.Query, err error) {
 		Archived            bool            `json:"archived"`
 		Fork                bool            `json:"fork"`
 		Private             bool            `json:"private"`
+		KeyValuePairs       json.RawMessage `json:"key_value_pairs"`
 		Sources             json.RawMessage `json:"sources"`
 		Metadata            json.RawMessage `json:"metadata"`
 	}
@@ -591,6 +614,11 @@ func batchReposQuery(fmtstr string, repos []*Repo) (_ *sqlf.Query, err error) {
 			return nil, errors.Wrapf(err, "batchReposQuery: metadata marshalling failed")
 		}
 
+		kvps, err := keyValuePairsColumn(r.KeyValuePairs)
+		if err != nil {
+			return nil, errors.Wrapf(err, "batchReposQuery: key_value_pairs marshalling failed")
+		}
+
 		records = append(records, record{
 			ID:                  r.ID,
 			Name:                r.Name,
@@ -606,6 +634,7 @@ func batchReposQuery(fmtstr string, repos []*Repo) (_ *sqlf.Query, err error) {
 			Archived:            r.Archived,
 			Fork:                r.Fork,
 			Private:             r.Private,
+			KeyValuePairs:       kvps,
 			Sources:             sources,
 			Metadata:            metadata,
 		})
@@ -650,6 +679,7 @@ WITH batch AS (
       archived              boolean,
       fork                  boolean,
       private               boolean,
+      key_value_pairs       jsonb,
       sources               jsonb,
       metadata              jsonb
     )
@@ -673,6 +703,7 @@ SET
   archived              = batch.archived,
   fork                  = batch.fork,
   private               = batch.private,
+  key_value_pairs       = batch.key_value_pairs,
   sources               = batch.sources,
   metadata              = batch.metadata
 FROM batch
@@ -710,6 +741,7 @@ INSERT INTO repo (
   archived,
   fork,
   private,
+  key_value_pairs,
   sources,
   metadata
 )
@@ -727,6 +759,7 @@ SELECT
   archived,
   fork,
   private,
+  key_value_pairs,
   sources,
   metadata
 FROM batch
@@ -769,6 +802,13 @@ func metadataColumn(metadata interface{}) (msg json.RawMessage, err error) {
 	return
 }
 
+func keyValuePairsColumn(kvps map[string]*string) (json.RawMessage, error) {
+	if len(kvps) == 0 {
+		return json.RawMessage("{}"), nil
+	}
+	return json.Marshal(kvps)
+}
+
 // scanner captures the Scan method of sql.Rows and sql.Row
 type scanner interface {
 	Scan(dst ...interface{}) error
@@ -812,7 +852,7 @@ func scanExternalService(svc *ExternalService, s scanner) error {
 }
 
 func scanRepo(r *Repo, s scanner) error {
-	var sources, metadata json.RawMessage
+	var kvps, sources, metadata json.RawMessage
 	err := s.Scan(
 		&r.ID,
 		&r.Name,
@@ -828,6 +868,7 @@ func scanRepo(r *Repo, s scanner) error {
 		&r.Archived,
 		&r.Fork,
 		&r.Private,
+		&kvps,
 		&sources,
 		&metadata,
 	)
@@ -835,6 +876,15 @@ func scanRepo(r *Repo, s scanner) error {
 		return err
 	}
 
+	// Repos without key/value pairs are scanned with a nil map, like the ones
+	// yielded by sources.
+	r.KeyValuePairs = nil
+	if string(kvps) != "{}" {
+		if err = json.Unmarshal(kvps, &r.KeyValuePairs); err != nil {
+			return errors.Wrap(err, "scanRepo: failed to unmarshal key_value_pairs")
+		}
+	}
+
 	if err = json.Unmarshal(sources, &r.Sources); err != nil {
 		return errors.Wrap(err, "scanRepo: failed to unmarshal sources")
 	}
@@ -853,6 +903,10 @@ func scanRepo(r *Repo, s scanner) error {
 		r.Metadata = new(awscodecommit.Repository)
 	case "gitolite":
 		r.Metadata = new(gitolite.Repo)
+	case "perforce":
+		r.Metadata = new(perforce.Depot)
+	case "manifest":
+		r.Metadata = new(manifest.Repo)
 	default:
 		return nil
 	}
@@ -2,6 +2,7 @@ package repos_test
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
 	"reflect"
 	"sort"
@@ -975,6 +976,17 @@ func testStoreListRepos(store repos.Store) func(*testing.T) {
 		repos:  repos.Assert.ReposEqual(&gitlab),
 	})
 
+	testCases = append(testCases, testCase{
+		name:   "limits repos to the given external service",
+		stored: repositories,
+		args: func(repos.Repos) repos.StoreListReposArgs {
+			return repos.StoreListReposArgs{
+				ExternalServiceURN: "extsvc:4",
+			}
+		},
+		repos: repos.Assert.ReposEqual(&awsCodeCommit, &otherRepo),
+	})
+
 	testCases = append(testCases, testCase{
 		name:   "use or",
 		stored: repositories,
@@ -1095,6 +1107,75 @@ func testStoreListReposPagination(store repos.Store) func(*testing.T) {
 	}
 }
 
+func testDBStoreListReposForUpdate(db *sql.DB, store *repos.DBStore) func(*testing.T) {
+	return func(t *testing.T) {
+		ctx := context.Background()
+
+		r := &repos.Repo{
+			Name: "github.com/foo/locked",
+			ExternalRepo: api.ExternalRepoSpec{
+				ID:          "locked",
+				ServiceType: "github",
+				ServiceID:   "https://github.com/",
+			},
+			Sources:  map[string]*repos.SourceInfo{},
+			Metadata: new(github.Repository),
+		}
+		if err := store.UpsertRepos(ctx, r); err != nil {
+			t.Fatal(err)
+		}
+		defer func() {
+			if _, err := db.ExecContext(ctx, "DELETE FROM repo WHERE id = $1", r.ID); err != nil {
+				t.Fatal(err)
+			}
+		}()
+
+		txstore, err := store.Transact(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		listed, err := txstore.ListRepos(ctx, repos.StoreListReposArgs{IDs: []api.RepoID{r.ID}, ForUpdate: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// A concurrent change of the key/value pairs (like the ones made by site admins) waits
+		// until the locked repo is written back, instead of being overwritten by it.
+		done := make(chan error, 1)
+		go func() {
+			_, err := db.ExecContext(ctx, `UPDATE repo SET key_value_pairs = key_value_pairs || '{"tier": "1"}' WHERE id = $1`, r.ID)
+			done <- err
+		}()
+		select {
+		case err := <-done:
+			t.Fatalf("concurrent update did not wait for the lock (error: %v)", err)
+		case <-time.After(100 * time.Millisecond):
+		}
+
+		listed[0].KeyValuePairs = map[string]*string{"go": nil}
+		if err = txstore.UpsertRepos(ctx, listed...); err != nil {
+			t.Fatal(err)
+		}
+		txstore.Done(&err)
+		if err := <-done; err != nil {
+			t.Fatal(err)
+		}
+
+		have, err := store.ListRepos(ctx, repos.StoreListReposArgs{IDs: []api.RepoID{r.ID}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		tier := "1"
+		want := map[string]*string{"go": nil, "tier": &tier}
+		if len(have) != 1 {
+			t.Fatalf("got %d repos, want 1", len(have))
+		}
+		if diff := cmp.Diff(want, have[0].KeyValuePairs); diff != "" {
+			t.Fatalf("key/value pairs: %s", diff)
+		}
+	}
+}
+
 func testDBStoreTransact(store *repos.DBStore) func(*testing.T) {
 	return func(t *testing.T) {
 		ctx := context.Background()
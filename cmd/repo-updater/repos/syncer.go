@@ -6,6 +6,7 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/inconshreveable/log15"
@@ -29,6 +30,12 @@ type Syncer struct {
 	// Sourcegraph.com
 	FailFullSync bool
 
+	// FullSyncInterval is the minimum interval between full syncs done by
+	// Run. In between, Run does incremental syncs, which only source the repos
+	// updated since the previous sync from IncrementalSources. If zero, Run
+	// only does full syncs.
+	FullSyncInterval time.Duration
+
 	// Synced is sent a collection of Repos that were synced by Sync (only if Synced is non-nil)
 	Synced chan Diff
 
@@ -48,15 +55,46 @@ type Syncer struct {
 	lastSyncErrMu sync.Mutex
 
 	syncSignal signal
+
+	// fullSyncRequested is set by TriggerSync so that the next sync of Run is
+	// a full sync.
+	fullSyncRequested int32
 }
 
-// Run runs the Sync at the specified interval.
+// incrementalSyncOverlap is how much earlier than the start of the previous
+// sync an incremental sync sources repos from, to tolerate clock skew between
+// Sourcegraph and the code hosts.
+const incrementalSyncOverlap = 5 * time.Minute
+
+// Run runs the Sync at the specified interval. If FullSyncInterval is set, the
+// syncs in between full syncs are incremental syncs.
 func (s *Syncer) Run(pctx context.Context, interval time.Duration) error {
+	// lastSync and lastFullSync are when the last successful sync and full
+	// sync began. Restarts always begin with a full sync.
+	var lastSync, lastFullSync time.Time
+
 	for pctx.Err() == nil {
 		ctx, cancel := contextWithSignalCancel(pctx, s.syncSignal.Watch())
 
-		if err := s.Sync(ctx); err != nil && s.Logger != nil {
-			s.Logger.Error("Syncer", "error", err)
+		began := s.Now()
+		full := atomic.SwapInt32(&s.fullSyncRequested, 0) == 1 ||
+			s.FullSyncInterval <= 0 ||
+			lastFullSync.IsZero() ||
+			began.Sub(lastFullSync) >= s.FullSyncInterval
+
+		var err error
+		if full {
+			if err = s.Sync(ctx); err == nil {
+				lastFullSync = began
+			}
+		} else {
+			err = s.IncrementalSync(ctx, lastSync.Add(-incrementalSyncOverlap))
+		}
+
+		if err == nil {
+			lastSync = began
+		} else if s.Logger != nil {
+			s.Logger.Error("Syncer", "full", full, "error", err)
 		}
 
 		sleep(ctx, interval)
@@ -92,8 +130,10 @@ func sleep(ctx context.Context, d time.Duration) {
 }
 
 // TriggerSync will run Sync now. If a sync is currently running it is
-// cancelled.
+// cancelled. The triggered sync is always a full sync, since the external
+// services may have changed to yield repos that weren't updated recently.
 func (s *Syncer) TriggerSync() {
+	atomic.StoreInt32(&s.fullSyncRequested, 1)
 	s.syncSignal.Trigger()
 }
 
@@ -134,8 +174,10 @@ func (s *Syncer) Sync(ctx context.Context) (err error) {
 		store = txs
 	}
 
+	// The stored repos are locked until they're written back, because their key/value
+	// pairs can be changed concurrently by site admins and are merged in NewDiff.
 	var stored Repos
-	if stored, err = store.ListRepos(ctx, StoreListReposArgs{}); err != nil {
+	if stored, err = store.ListRepos(ctx, StoreListReposArgs{ForUpdate: true}); err != nil {
 		return errors.Wrap(err, "syncer.sync.store.list-repos")
 	}
 
@@ -153,6 +195,98 @@ func (s *Syncer) Sync(ctx context.Context) (err error) {
 	return nil
 }
 
+// IncrementalSync synchronizes the repositories that were created or updated
+// after since. IncrementalSources only source those repositories, all other
+// Sources source all of theirs. Since only some of the repositories are
+// sourced, no repositories are deleted. That is left to Sync.
+func (s *Syncer) IncrementalSync(ctx context.Context, since time.Time) (err error) {
+	var diff Diff
+
+	ctx, save := s.observe(ctx, "Syncer.IncrementalSync", since.Format(time.RFC3339))
+	defer save(&diff, &err)
+	defer s.setOrResetLastSyncErr(&err)
+
+	var sourced Repos
+	if sourced, err = s.sourcedSince(ctx, since); err != nil {
+		return errors.Wrap(err, "syncer.incremental-sync.sourced")
+	}
+
+	if len(sourced) == 0 {
+		return nil
+	}
+
+	store := s.Store
+	if tr, ok := s.Store.(Transactor); ok {
+		var txs TxStore
+		if txs, err = tr.Transact(ctx); err != nil {
+			return errors.Wrap(err, "syncer.incremental-sync.transact")
+		}
+		defer txs.Done(&err)
+		store = txs
+	}
+
+	var stored Repos
+	args := StoreListReposArgs{
+		Names:         sourced.Names(),
+		ExternalRepos: sourced.ExternalRepos(),
+		UseOr:         true,
+		ForUpdate:     true,
+	}
+	if stored, err = store.ListRepos(ctx, args); err != nil {
+		return errors.Wrap(err, "syncer.incremental-sync.store.list-repos")
+	}
+
+	storedByID := make(map[api.ExternalRepoSpec]*Repo, len(stored))
+	storedByName := make(map[string]*Repo, len(stored))
+	for _, r := range stored {
+		storedByID[r.ExternalRepo] = r
+		storedByName[strings.ToLower(r.Name)] = r
+	}
+
+	subset := make(Repos, 0, len(sourced))
+	subsetIDs := make(map[api.ExternalRepoSpec]bool, len(sourced))
+	for _, r := range sourced {
+		// A sourced repo taking the name of a different stored repo requires
+		// deleting the stored one, so it's left to the next full sync.
+		if old := storedByName[strings.ToLower(r.Name)]; old != nil && old.ExternalRepo != r.ExternalRepo {
+			continue
+		}
+
+		// The repo is also still yielded by the sources that didn't source it
+		// in this sync, since they would have only if it was updated.
+		if old := storedByID[r.ExternalRepo]; old != nil {
+			for urn, info := range old.Sources {
+				if _, ok := r.Sources[urn]; !ok {
+					r.Sources[urn] = info
+				}
+			}
+		}
+
+		subset = append(subset, r)
+		subsetIDs[r.ExternalRepo] = true
+	}
+
+	related := make(Repos, 0, len(stored))
+	for _, r := range stored {
+		if subsetIDs[r.ExternalRepo] {
+			related = append(related, r)
+		}
+	}
+
+	diff = NewDiff(subset, related)
+	upserts := s.upserts(diff)
+
+	if err = store.UpsertRepos(ctx, upserts...); err != nil {
+		return errors.Wrap(err, "syncer.incremental-sync.store.upsert-repos")
+	}
+
+	if s.SubsetSynced != nil {
+		s.SubsetSynced <- diff
+	}
+
+	return nil
+}
+
 // SyncSubset runs the syncer on a subset of the stored repositories. It will
 // only sync the repositories with the same name or external service spec as
 // sourcedSubset repositories.
@@ -202,6 +336,7 @@ func (s *Syncer) syncSubset(ctx context.Context, insertOnly bool, sourcedSubset
 		Names:         Repos(sourcedSubset).Names(),
 		ExternalRepos: Repos(sourcedSubset).ExternalRepos(),
 		UseOr:         true,
+		ForUpdate:     true,
 	}
 	if storedSubset, err = store.ListRepos(ctx, args); err != nil {
 		return Diff{}, errors.Wrap(err, "syncer.syncsubset.store.list-repos")
@@ -225,6 +360,85 @@ func (s *Syncer) syncSubset(ctx context.Context, insertOnly bool, sourcedSubset
 	return diff, nil
 }
 
+// Preview returns the Diff that Sync would produce if the given external
+// service had its (proposed) configuration, without persisting anything. The
+// external service is matched to the stored one by its ID, so a new external
+// service must have a zero ID. Repositories yielded by other external services
+// are assumed to stay unchanged, so that only the effect of the given external
+// service is previewed.
+func (s *Syncer) Preview(ctx context.Context, svc *ExternalService) (diff Diff, err error) {
+	tr, ctx := trace.New(ctx, "Syncer.Preview", svc.URN())
+	defer func() {
+		tr.LogFields(
+			otlog.Int("added.count", len(diff.Added)),
+			otlog.Int("modified.count", len(diff.Modified)),
+			otlog.Int("deleted.count", len(diff.Deleted)),
+		)
+		tr.SetError(err)
+		tr.Finish()
+	}()
+
+	srcs, err := s.Sourcer(svc)
+	if err != nil {
+		return Diff{}, errors.Wrap(err, "syncer.preview.sourcer")
+	}
+
+	listCtx, cancel := context.WithTimeout(ctx, sourceTimeout)
+	defer cancel()
+
+	var sourced Repos
+	if sourced, err = listAll(listCtx, srcs); err != nil {
+		return Diff{}, errors.Wrap(err, "syncer.preview.sourced")
+	}
+
+	// Only the stored repos that the external service yields now or would
+	// yield with its new configuration are relevant. The sources of other
+	// external services are kept as they are.
+	urn := svc.URN()
+	args := StoreListReposArgs{
+		ExternalRepos: sourced.ExternalRepos(),
+		UseOr:         true,
+	}
+	if svc.ID != 0 {
+		args.ExternalServiceURN = urn
+	}
+
+	var stored Repos
+	if len(args.ExternalRepos) > 0 || args.ExternalServiceURN != "" {
+		if stored, err = s.Store.ListRepos(ctx, args); err != nil {
+			return Diff{}, errors.Wrap(err, "syncer.preview.store.list-repos")
+		}
+	}
+
+	byID := make(map[api.ExternalRepoSpec]*Repo, len(sourced))
+	for _, r := range sourced {
+		byID[r.ExternalRepo] = r
+	}
+
+	var related Repos
+	for _, r := range stored {
+		src := byID[r.ExternalRepo]
+		if _, ok := r.Sources[urn]; !ok && src == nil {
+			continue
+		}
+		// NewDiff updates the stored repos in place, so we diff clones.
+		related = append(related, r.Clone())
+
+		other := r.Clone()
+		delete(other.Sources, urn)
+		switch {
+		case src != nil:
+			for id, info := range other.Sources {
+				src.Sources[id] = info
+			}
+		case len(other.Sources) > 0:
+			sourced = append(sourced, other)
+		}
+	}
+
+	return NewDiff(sourced, related), nil
+}
+
 func (s *Syncer) upserts(diff Diff) []*Repo {
 	now := s.Now()
 	upserts := make([]*Repo, 0, len(diff.Added)+len(diff.Deleted)+len(diff.Modified))
@@ -368,6 +582,23 @@ func (s *Syncer) sourced(ctx context.Context, observe ...func(*Repo)) ([]*Repo,
 	return listAll(ctx, srcs, observe...)
 }
 
+func (s *Syncer) sourcedSince(ctx context.Context, since time.Time) ([]*Repo, error) {
+	svcs, err := s.Store.ListExternalServices(ctx, StoreListExternalServicesArgs{})
+	if err != nil {
+		return nil, err
+	}
+
+	srcs, err := s.Sourcer(svcs...)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, sourceTimeout)
+	defer cancel()
+
+	return listAllSince(ctx, srcs, since)
+}
+
 func (s *Syncer) makeNewRepoInserter(ctx context.Context) (func(*Repo), error) {
 	// syncSubset requires querying the store for related repositories, and
 	// will do nothing if `insertOnly` is set and there are any related repositories. Most
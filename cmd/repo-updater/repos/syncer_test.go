@@ -707,6 +707,167 @@ func testSyncSubset(s repos.Store) func(*testing.T) {
 	}
 }
 
+func TestSyncer_Preview(t *testing.T) {
+	t.Parallel()
+
+	svc1 := &repos.ExternalService{ID: 1, Kind: "GITHUB"}
+	svc2 := &repos.ExternalService{ID: 2, Kind: "GITHUB"}
+
+	repo := func(name string, urns ...string) *repos.Repo {
+		return (&repos.Repo{
+			Name: name,
+			ExternalRepo: api.ExternalRepoSpec{
+				ID:          name,
+				ServiceType: "github",
+				ServiceID:   "https://github.com/",
+			},
+		}).With(repos.Opt.RepoSources(urns...))
+	}
+
+	ctx := context.Background()
+	store := new(repos.FakeStore)
+	err := store.UpsertRepos(ctx,
+		repo("unchanged", svc1.URN()),
+		repo("shared", svc1.URN(), svc2.URN()),
+		repo("described", svc1.URN()),
+		repo("excluded", svc1.URN()),
+		repo("other", svc2.URN()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	described := repo("described")
+	described.Description = "new description"
+
+	syncer := &repos.Syncer{
+		Store: store,
+		Sourcer: repos.NewFakeSourcer(nil, repos.NewFakeSource(svc1, nil,
+			repo("unchanged"),
+			described,
+			repo("added"),
+		)),
+		Now: time.Now,
+	}
+
+	diff, err := syncer.Preview(ctx, svc1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	have := map[string][]string{
+		"added":      diff.Added.Names(),
+		"modified":   diff.Modified.Names(),
+		"deleted":    diff.Deleted.Names(),
+		"unmodified": diff.Unmodified.Names(),
+	}
+	for _, names := range have {
+		sort.Strings(names)
+	}
+	want := map[string][]string{
+		"added":      {"added"},
+		"modified":   {"described", "shared"},
+		"deleted":    {"excluded"},
+		"unmodified": {"unchanged"},
+	}
+	if d := cmp.Diff(want, have); d != "" {
+		t.Fatalf("diff:\n%s", d)
+	}
+
+	// Nothing is persisted.
+	stored, err := store.ListRepos(ctx, repos.StoreListReposArgs{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := cmp.Diff([]string{"unchanged", "shared", "described", "excluded", "other"}, repos.Repos(stored).Names()); d != "" {
+		t.Fatalf("stored repos:\n%s", d)
+	}
+	for _, r := range stored {
+		if r.Description != "" || r.DeletedAt != (time.Time{}) {
+			t.Fatalf("stored repo %q was modified", r.Name)
+		}
+	}
+}
+
+func TestSyncer_IncrementalSync(t *testing.T) {
+	t.Parallel()
+
+	svc1 := &repos.ExternalService{ID: 1, Kind: "GITHUB"}
+	svc2 := &repos.ExternalService{ID: 2, Kind: "GITHUB"}
+
+	repo := func(name, id string, urns ...string) *repos.Repo {
+		return (&repos.Repo{
+			Name: name,
+			ExternalRepo: api.ExternalRepoSpec{
+				ID:          id,
+				ServiceType: "github",
+				ServiceID:   "https://github.com/",
+			},
+		}).With(repos.Opt.RepoSources(urns...))
+	}
+
+	ctx := context.Background()
+	store := new(repos.FakeStore)
+	err := store.UpsertRepos(ctx,
+		repo("shared", "1", svc1.URN(), svc2.URN()),
+		repo("not-updated", "2", svc1.URN()),
+		repo("taken", "3", svc1.URN()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	shared := repo("shared", "1")
+	shared.Description = "new description"
+
+	syncer := &repos.Syncer{
+		Store: store,
+		Sourcer: repos.NewFakeSourcer(nil, repos.NewFakeSource(svc1, nil,
+			shared,
+			repo("added", "4"),
+			repo("taken", "5"),
+		)),
+		Now: time.Now,
+	}
+
+	if err := syncer.IncrementalSync(ctx, time.Now().Add(-time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+
+	stored, err := store.ListRepos(ctx, repos.StoreListReposArgs{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type repoState struct {
+		ID          string
+		Description string
+		Sources     []string
+	}
+	have := make(map[string]repoState, len(stored))
+	for _, r := range stored {
+		var srcs []string
+		for urn := range r.Sources {
+			srcs = append(srcs, urn)
+		}
+		sort.Strings(srcs)
+		have[r.Name] = repoState{r.ExternalRepo.ID, r.Description, srcs}
+	}
+
+	// The repo that wasn't sourced isn't deleted, the updated repo keeps the
+	// source that didn't source it and the repo that would replace a stored
+	// repo of the same name is left to the next full sync.
+	want := map[string]repoState{
+		"shared":      {"1", "new description", []string{svc1.URN(), svc2.URN()}},
+		"not-updated": {"2", "", []string{svc1.URN()}},
+		"taken":       {"3", "", []string{svc1.URN()}},
+		"added":       {"4", "", []string{svc1.URN()}},
+	}
+	if d := cmp.Diff(want, have); d != "" {
+		t.Fatalf("stored repos:\n%s", d)
+	}
+}
+
 func TestDiff(t *testing.T) {
 	t.Parallel()
 
@@ -718,6 +879,7 @@ func TestDiff(t *testing.T) {
 		}
 	}
 	now := time.Now()
+	tier := "1"
 
 	type testCase struct {
 		name   string
@@ -793,6 +955,37 @@ func TestDiff(t *testing.T) {
 				{Name: "2", ExternalRepo: eid("1"), Description: "foo"},
 			}},
 		},
+		{
+			name: "imported key/value pairs are replaced and others are kept",
+			store: repos.Repos{
+				{ExternalRepo: eid("1"), Metadata: &github.Repository{Topics: []string{"go", "old"}}, KeyValuePairs: map[string]*string{
+					"go":   nil,
+					"old":  nil,
+					"tier": &tier,
+				}},
+			},
+			source: repos.Repos{
+				{ExternalRepo: eid("1"), Metadata: &github.Repository{Topics: []string{"go", "new"}}, KeyValuePairs: map[string]*string{
+					"go":  nil,
+					"new": nil,
+				}},
+			},
+			diff: repos.Diff{Modified: repos.Repos{
+				{ExternalRepo: eid("1"), Metadata: &github.Repository{Topics: []string{"go", "new"}}, KeyValuePairs: map[string]*string{
+					"go":   nil,
+					"new":  nil,
+					"tier": &tier,
+				}},
+			}},
+		},
+		{
+			name:   "empty key/value pairs are unmodified",
+			store:  repos.Repos{{ExternalRepo: eid("1"), KeyValuePairs: map[string]*string{}}},
+			source: repos.Repos{{ExternalRepo: eid("1")}},
+			diff: repos.Diff{Unmodified: repos.Repos{
+				{ExternalRepo: eid("1"), KeyValuePairs: map[string]*string{}},
+			}},
+		},
 		{
 			name: "unmodified preserves stored repo",
 			store: repos.Repos{
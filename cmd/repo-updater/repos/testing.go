@@ -261,6 +261,10 @@ func (s FakeStore) ListRepos(ctx context.Context, args StoreListReposArgs) ([]*R
 		if args.PrivateOnly {
 			preds = append(preds, r.Private)
 		}
+		if args.ExternalServiceURN != "" {
+			_, ok := r.Sources[args.ExternalServiceURN]
+			preds = append(preds, ok)
+		}
 
 		if (args.UseOr && evalOr(preds...)) || (!args.UseOr && evalAnd(preds...)) {
 			repos = append(repos, r)
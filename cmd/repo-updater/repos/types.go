@@ -22,6 +22,8 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/github"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitlab"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitolite"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/manifest"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/perforce"
 	"github.com/sourcegraph/sourcegraph/internal/jsonc"
 	"github.com/sourcegraph/sourcegraph/schema"
 	"github.com/xeipuuv/gojsonschema"
@@ -35,6 +37,16 @@ type Changeset struct {
 	HeadRef string
 	BaseRef string
 
+	// Labels are added to the changeset, Reviewers are requested to review it
+	// and Assignees are assigned to it, on code hosts that support them.
+	// Reviewers of the form "org/team" are teams.
+	Labels    []string
+	Reviewers []string
+	Assignees []string
+	// Draft, if true, creates the changeset as a draft on code hosts that
+	// support them.
+	Draft bool
+
 	*campaigns.Changeset
 	*Repo
 }
@@ -102,6 +114,10 @@ func (e ExternalService) Configuration() (cfg interface{}, _ error) {
 		cfg = &schema.GitLabConnection{}
 	case "gitolite":
 		cfg = &schema.GitoliteConnection{}
+	case "perforce":
+		cfg = &schema.PerforceConnection{}
+	case "manifest":
+		cfg = &schema.ManifestConnection{}
 	case "phabricator":
 		cfg = &schema.PhabricatorConnection{}
 	case "other":
@@ -126,6 +142,10 @@ func (e *ExternalService) Exclude(rs ...*Repo) error {
 		return e.excludeAWSCodeCommitRepos(rs...)
 	case "gitolite":
 		return e.excludeGitoliteRepos(rs...)
+	case "perforce":
+		return e.excludePerforceDepots(rs...)
+	case "manifest":
+		return e.excludeManifestRepos(rs...)
 	case "other":
 		return e.excludeOtherRepos(rs...)
 	default:
@@ -328,6 +348,62 @@ func (e *ExternalService) excludeGitoliteRepos(rs ...*Repo) error {
 	})
 }
 
+// excludePerforceDepots changes the configuration of a Perforce external
+// service to exclude the given depots and streams from being synced.
+func (e *ExternalService) excludePerforceDepots(rs ...*Repo) error {
+	if len(rs) == 0 {
+		return nil
+	}
+
+	return e.config("perforce", func(v interface{}) (string, interface{}, error) {
+		c := v.(*schema.PerforceConnection)
+		set := make(map[string]bool, len(c.Exclude))
+		for _, ex := range c.Exclude {
+			if ex.Path != "" {
+				set[ex.Path] = true
+			}
+		}
+
+		for _, r := range rs {
+			depot, ok := r.Metadata.(*perforce.Depot)
+			if ok && depot.Path != "" && !set[depot.Path] {
+				c.Exclude = append(c.Exclude, &schema.ExcludedPerforceDepot{Path: depot.Path})
+				set[depot.Path] = true
+			}
+		}
+
+		return "exclude", c.Exclude, nil
+	})
+}
+
+// excludeManifestRepos changes the configuration of a manifest external
+// service to exclude the given repos from being synced.
+func (e *ExternalService) excludeManifestRepos(rs ...*Repo) error {
+	if len(rs) == 0 {
+		return nil
+	}
+
+	return e.config("manifest", func(v interface{}) (string, interface{}, error) {
+		c := v.(*schema.ManifestConnection)
+		set := make(map[string]bool, len(c.Exclude))
+		for _, ex := range c.Exclude {
+			if ex.Name != "" {
+				set[ex.Name] = true
+			}
+		}
+
+		for _, r := range rs {
+			repo, ok := r.Metadata.(*manifest.Repo)
+			if ok && repo.Name != "" && !set[repo.Name] {
+				c.Exclude = append(c.Exclude, &schema.ExcludedManifestRepo{Name: repo.Name})
+				set[repo.Name] = true
+			}
+		}
+
+		return "exclude", c.Exclude, nil
+	})
+}
+
 // excludeGithubRepos changes the configuration of a Github external service to exclude the
 // given repos from being synced.
 func (e *ExternalService) excludeGithubRepos(rs ...*Repo) error {
@@ -472,6 +548,10 @@ func (e ExternalService) schema() string {
 		return schema.GitLabSchemaJSON
 	case "gitolite":
 		return schema.GitoliteSchemaJSON
+	case "perforce":
+		return schema.PerforceSchemaJSON
+	case "manifest":
+		return schema.ManifestSchemaJSON
 	case "phabricator":
 		return schema.PhabricatorSchemaJSON
 	case "other":
@@ -555,6 +635,10 @@ type Repo struct {
 	Archived bool
 	// Private is whether the repository is private.
 	Private bool
+	// KeyValuePairs are the key/value tags of the repository. They are set by
+	// site admins or imported from the code host metadata (see
+	// importedKeyValuePairs). A nil value denotes a tag that is a key only.
+	KeyValuePairs map[string]*string
 	// CreatedAt is when this repository was created on Sourcegraph.
 	CreatedAt time.Time
 	// UpdatedAt is when this repository's metadata was last updated on Sourcegraph.
@@ -656,6 +740,23 @@ func (r *Repo) Update(n *Repo) (modified bool) {
 		r.Sources, modified = n.Sources, true
 	}
 
+	// Tags imported from the old metadata are replaced by the ones imported
+	// from the new metadata, so that tags removed on the code host are
+	// removed here too. All other tags were set by site admins and are kept.
+	kvps := make(map[string]*string, len(r.KeyValuePairs)+len(n.KeyValuePairs))
+	imported := importedKeyValuePairs(r.Metadata)
+	for k, v := range r.KeyValuePairs {
+		if _, ok := imported[k]; !ok {
+			kvps[k] = v
+		}
+	}
+	for k, v := range n.KeyValuePairs {
+		kvps[k] = v
+	}
+	if (len(r.KeyValuePairs) > 0 || len(kvps) > 0) && !reflect.DeepEqual(r.KeyValuePairs, kvps) {
+		r.KeyValuePairs, modified = kvps, true
+	}
+
 	if !reflect.DeepEqual(r.Metadata, n.Metadata) {
 		r.Metadata, modified = n.Metadata, true
 	}
@@ -675,9 +776,43 @@ func (r *Repo) Clone() *Repo {
 			clone.Sources[k] = v
 		}
 	}
+	if r.KeyValuePairs != nil {
+		clone.KeyValuePairs = make(map[string]*string, len(r.KeyValuePairs))
+		for k, v := range r.KeyValuePairs {
+			clone.KeyValuePairs[k] = v
+		}
+	}
 	return &clone
 }
 
+// importedKeyValuePairs returns the key/value tags that are imported from the
+// given code host metadata of a repository: the topics of GitHub repositories
+// and the tags of GitLab projects, which are imported as keys only, and the
+// metadata of manifest repositories.
+func importedKeyValuePairs(metadata interface{}) map[string]*string {
+	kvps := map[string]*string{}
+	switch m := metadata.(type) {
+	case *github.Repository:
+		for _, k := range m.Topics {
+			kvps[k] = nil
+		}
+	case *gitlab.Project:
+		for _, k := range m.TagList {
+			kvps[k] = nil
+		}
+	case *manifest.Repo:
+		for k, v := range m.Metadata {
+			v := v
+			kvps[k] = &v
+		}
+	}
+
+	if len(kvps) == 0 {
+		return nil
+	}
+	return kvps
+}
+
 // Apply applies the given functional options to the Repo.
 func (r *Repo) Apply(opts ...func(*Repo)) {
 	if r == nil {
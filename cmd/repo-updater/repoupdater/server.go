@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -51,6 +52,12 @@ type Server struct {
 		// the registry can start or stop the syncer associated with the service
 		HandleExternalServiceSync(es api.ExternalService)
 	}
+	PermsSyncer interface {
+		// ScheduleUsers schedules new permissions syncing requests for given users.
+		ScheduleUsers(ctx context.Context, userIDs ...int32)
+		// ScheduleRepos schedules new permissions syncing requests for given repositories.
+		ScheduleRepos(ctx context.Context, repoIDs ...api.RepoID)
+	}
 	RateLimiterRegistry interface {
 		// HandleExternalServiceSync should be called when an external service changes so that
 		// our internal rate limiter are kept in sync
@@ -71,8 +78,10 @@ func (s *Server) Handler() http.Handler {
 	mux.HandleFunc("/enqueue-repo-update", s.handleEnqueueRepoUpdate)
 	mux.HandleFunc("/exclude-repo", s.handleExcludeRepo)
 	mux.HandleFunc("/sync-external-service", s.handleExternalServiceSync)
+	mux.HandleFunc("/preview-external-service-sync", s.handleExternalServiceSyncPreview)
 	mux.HandleFunc("/status-messages", s.handleStatusMessages)
 	mux.HandleFunc("/enqueue-changeset-sync", s.handleEnqueueChangesetSync)
+	mux.HandleFunc("/schedule-perms-sync", s.handleSchedulePermsSync)
 	return mux
 }
 
@@ -397,6 +406,51 @@ func externalServiceValidate(ctx context.Context, req *protocol.ExternalServiceS
 	return nil
 }
 
+// syncPreviewSampleSize is the maximum number of repository names returned
+// for each state of a sync preview.
+const syncPreviewSampleSize = 100
+
+func (s *Server) handleExternalServiceSyncPreview(w http.ResponseWriter, r *http.Request) {
+	var req protocol.ExternalServiceSyncPreviewRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	diff, err := s.Syncer.Preview(r.Context(), &repos.ExternalService{
+		ID:          req.ExternalService.ID,
+		Kind:        req.ExternalService.Kind,
+		DisplayName: req.ExternalService.DisplayName,
+		Config:      req.ExternalService.Config,
+	})
+	if err != nil {
+		log15.Error("server.external-service-sync-preview", "kind", req.ExternalService.Kind, "error", err)
+		respond(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	respond(w, http.StatusOK, &protocol.ExternalServiceSyncPreviewResult{
+		Added:      newRepoDiffSummary(diff.Added),
+		Modified:   newRepoDiffSummary(diff.Modified),
+		Deleted:    newRepoDiffSummary(diff.Deleted),
+		Unmodified: newRepoDiffSummary(diff.Unmodified),
+	})
+}
+
+func newRepoDiffSummary(rs repos.Repos) protocol.RepoDiffSummary {
+	names := rs.Names()
+	sort.Strings(names)
+	if len(names) > syncPreviewSampleSize {
+		names = names[:syncPreviewSampleSize]
+	}
+
+	sample := make([]api.RepoName, len(names))
+	for i, name := range names {
+		sample[i] = api.RepoName(name)
+	}
+	return protocol.RepoDiffSummary{Count: len(rs), Sample: sample}
+}
+
 var mockRepoLookup func(protocol.RepoLookupArgs) (*protocol.RepoLookupResult, error)
 
 func (s *Server) repoLookup(ctx context.Context, args protocol.RepoLookupArgs) (result *protocol.RepoLookupResult, err error) {
@@ -612,6 +666,29 @@ func (s *Server) handleEnqueueChangesetSync(w http.ResponseWriter, r *http.Reque
 	respond(w, http.StatusOK, nil)
 }
 
+func (s *Server) handleSchedulePermsSync(w http.ResponseWriter, r *http.Request) {
+	if s.PermsSyncer == nil {
+		log15.Warn("PermsSyncer is nil")
+		respond(w, http.StatusForbidden, nil)
+		return
+	}
+
+	var req protocol.PermsSyncRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respond(w, http.StatusBadRequest, err)
+		return
+	}
+	if len(req.UserIDs) == 0 && len(req.RepoIDs) == 0 {
+		respond(w, http.StatusBadRequest, errors.New("neither user IDs nor repo IDs was provided in request (must provide at least one)"))
+		return
+	}
+
+	s.PermsSyncer.ScheduleUsers(r.Context(), req.UserIDs...)
+	s.PermsSyncer.ScheduleRepos(r.Context(), req.RepoIDs...)
+
+	respond(w, http.StatusOK, nil)
+}
+
 func newRepoInfo(r *repos.Repo) (*protocol.RepoInfo, error) {
 	urls := r.CloneURLs()
 	if len(urls) == 0 {
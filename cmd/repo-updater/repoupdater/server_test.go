@@ -541,6 +541,114 @@ func TestServer_RepoExternalServices(t *testing.T) {
 	}
 }
 
+func TestServer_PreviewExternalServiceSync(t *testing.T) {
+	svc := &repos.ExternalService{
+		ID:     1,
+		Kind:   "GITHUB",
+		Config: `{"url": "https://github.com", "token": "secret", "exclude": [{"name": "foo/old"}]}`,
+	}
+
+	repo := func(name string) *repos.Repo {
+		return (&repos.Repo{
+			Name: "github.com/" + name,
+			ExternalRepo: api.ExternalRepoSpec{
+				ID:          name,
+				ServiceType: "github",
+				ServiceID:   "https://github.com/",
+			},
+		}).With(repos.Opt.RepoSources(svc.URN()))
+	}
+
+	ctx := context.Background()
+	store := new(repos.FakeStore)
+	must(store.UpsertExternalServices(ctx, svc))
+	must(store.UpsertRepos(ctx, repo("foo/old"), repo("foo/kept")))
+
+	s := &Server{
+		Store: store,
+		Syncer: &repos.Syncer{
+			Store:   store,
+			Sourcer: repos.NewFakeSourcer(nil, repos.NewFakeSource(svc, nil, repo("foo/kept"), repo("foo/new"))),
+			Now:     time.Now,
+		},
+	}
+	srv := httptest.NewServer(s.Handler())
+	defer srv.Close()
+	cli := repoupdater.Client{URL: srv.URL}
+
+	have, err := cli.PreviewExternalServiceSync(ctx, api.ExternalService{
+		ID:     svc.ID,
+		Kind:   svc.Kind,
+		Config: svc.Config,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := &protocol.ExternalServiceSyncPreviewResult{
+		Added:      protocol.RepoDiffSummary{Count: 1, Sample: []api.RepoName{"github.com/foo/new"}},
+		Modified:   protocol.RepoDiffSummary{Sample: []api.RepoName{}},
+		Deleted:    protocol.RepoDiffSummary{Count: 1, Sample: []api.RepoName{"github.com/foo/old"}},
+		Unmodified: protocol.RepoDiffSummary{Count: 1, Sample: []api.RepoName{"github.com/foo/kept"}},
+	}
+	if diff := cmp.Diff(want, have); diff != "" {
+		t.Fatalf("response:\n%s", diff)
+	}
+
+	// The preview must not have changed the stored repos.
+	stored, err := store.ListRepos(ctx, repos.StoreListReposArgs{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if names := repos.Repos(stored).Names(); len(names) != 2 {
+		t.Fatalf("stored repos changed: %v", names)
+	}
+}
+
+type fakePermsSyncer struct {
+	userIDs []int32
+	repoIDs []api.RepoID
+}
+
+func (s *fakePermsSyncer) ScheduleUsers(ctx context.Context, userIDs ...int32) {
+	s.userIDs = append(s.userIDs, userIDs...)
+}
+
+func (s *fakePermsSyncer) ScheduleRepos(ctx context.Context, repoIDs ...api.RepoID) {
+	s.repoIDs = append(s.repoIDs, repoIDs...)
+}
+
+func TestServer_SchedulePermsSync(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("no PermsSyncer", func(t *testing.T) {
+		srv := httptest.NewServer((&Server{}).Handler())
+		defer srv.Close()
+		cli := repoupdater.Client{URL: srv.URL}
+
+		if err := cli.SchedulePermsSync(ctx, protocol.PermsSyncRequest{UserIDs: []int32{1}}); err == nil {
+			t.Fatal("want error but got nil")
+		}
+	})
+
+	syncer := &fakePermsSyncer{}
+	srv := httptest.NewServer((&Server{PermsSyncer: syncer}).Handler())
+	defer srv.Close()
+	cli := repoupdater.Client{URL: srv.URL}
+
+	if err := cli.SchedulePermsSync(ctx, protocol.PermsSyncRequest{}); err == nil {
+		t.Fatal("empty request: want error but got nil")
+	}
+
+	err := cli.SchedulePermsSync(ctx, protocol.PermsSyncRequest{UserIDs: []int32{1}, RepoIDs: []api.RepoID{2, 3}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(&fakePermsSyncer{userIDs: []int32{1}, repoIDs: []api.RepoID{2, 3}}, syncer, cmp.AllowUnexported(fakePermsSyncer{})); diff != "" {
+		t.Fatalf("scheduled:\n%s", diff)
+	}
+}
+
 func TestServer_StatusMessages(t *testing.T) {
 	githubService := &repos.ExternalService{
 		ID:          1,
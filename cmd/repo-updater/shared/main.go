@@ -177,6 +177,7 @@ func Main(enterpriseInit EnterpriseInit) {
 		Store:            store,
 		Sourcer:          src,
 		DisableStreaming: !streamingSyncer,
+		FullSyncInterval: repos.GetFullSyncInterval(),
 		Logger:           log15.Root(),
 		Now:              clock,
 	}
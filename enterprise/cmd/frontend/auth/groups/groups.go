@@ -0,0 +1,182 @@
+// Package groups maps the groups that an authentication provider reports for a user (e.g. from a
+// SAML attribute or an OpenID Connect claim) to organization memberships and repository
+// permissions.
+package groups
+
+import (
+	"context"
+	"regexp"
+	"time"
+
+	"github.com/inconshreveable/log15"
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/authz"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	edb "github.com/sourcegraph/sourcegraph/enterprise/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/internal/db/dbconn"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+var MockApply func(ctx context.Context, userID int32, spec extsvc.AccountSpec, groups []string, mappings []*schema.AuthGroupMapping) error
+
+// Apply applies the group mappings of the authentication provider identified by spec to the user
+// who signed in as a member of the given groups.
+//
+// The user is added to the organizations of the matching mappings. Organization memberships that
+// were previously granted through this provider and are no longer matched are removed, while
+// memberships that were created by other means are never touched. The repository patterns of the
+// matching mappings replace the ones previously recorded for the user and this provider.
+func Apply(ctx context.Context, userID int32, spec extsvc.AccountSpec, groups []string, mappings []*schema.AuthGroupMapping) error {
+	if MockApply != nil {
+		return MockApply(ctx, userID, spec, groups, mappings)
+	}
+
+	store := edb.NewPermsStore(dbconn.Global, time.Now)
+
+	prev, err := previous(ctx, store, userID, spec)
+	if err != nil {
+		return errors.Wrap(err, "load previous group permissions")
+	}
+	if len(mappings) == 0 && prev == nil {
+		return nil
+	}
+
+	isMember := make(map[string]bool, len(groups))
+	for _, g := range groups {
+		isMember[g] = true
+	}
+
+	var (
+		matched  []string
+		orgNames []string
+		patterns []string
+		seen     = map[string]bool{}
+	)
+	for _, m := range mappings {
+		if !isMember[m.Group] {
+			continue
+		}
+		if !seen["group:"+m.Group] {
+			seen["group:"+m.Group] = true
+			matched = append(matched, m.Group)
+		}
+		for _, name := range m.Organizations {
+			if !seen["org:"+name] {
+				seen["org:"+name] = true
+				orgNames = append(orgNames, name)
+			}
+		}
+		for _, pattern := range m.Repositories {
+			if seen["repo:"+pattern] {
+				continue
+			}
+			seen["repo:"+pattern] = true
+			// 🚨 SECURITY: Patterns are matched against entire repository names by PostgreSQL,
+			// so they must be valid there, too. They're also validated on their own, so that
+			// they can't escape the anchors.
+			if err := validateRepoPattern(pattern); err != nil {
+				log15.Warn("Ignoring invalid repository pattern in auth provider group mapping.", "group", m.Group, "pattern", pattern, "error", err)
+				continue
+			}
+			if err := store.ValidateRepoPattern(ctx, pattern); err != nil {
+				log15.Warn("Ignoring invalid repository pattern in auth provider group mapping.", "group", m.Group, "pattern", pattern, "error", err)
+				continue
+			}
+			patterns = append(patterns, pattern)
+		}
+	}
+
+	if len(matched) == 0 && prev == nil {
+		return nil
+	}
+
+	wasGranted := map[int32]bool{}
+	if prev != nil {
+		for _, id := range prev.OrgIDs {
+			wasGranted[id] = true
+		}
+	}
+
+	// 🚨 SECURITY: Only record the memberships that are (or were previously) granted through the
+	// group mappings, so that removing the user from a group never revokes a membership that was
+	// granted by an organization admin.
+	var orgIDs []int32
+	want := map[int32]bool{}
+	for _, name := range orgNames {
+		org, err := db.Orgs.GetByName(ctx, name)
+		if errcode.IsNotFound(err) {
+			log15.Warn("Ignoring nonexistent organization in auth provider group mapping.", "org", name)
+			continue
+		} else if err != nil {
+			return errors.Wrapf(err, "get organization %q", name)
+		}
+		if want[org.ID] {
+			continue
+		}
+		want[org.ID] = true
+
+		_, err = db.OrgMembers.GetByOrgIDAndUserID(ctx, org.ID, userID)
+		switch {
+		case err == nil:
+			if wasGranted[org.ID] {
+				orgIDs = append(orgIDs, org.ID)
+			}
+		case errcode.IsNotFound(err):
+			if _, err := db.OrgMembers.Create(ctx, org.ID, userID, types.OrgRoleMember); err != nil {
+				return errors.Wrapf(err, "add user to organization %q", name)
+			}
+			orgIDs = append(orgIDs, org.ID)
+		default:
+			return errors.Wrapf(err, "get membership of organization %q", name)
+		}
+	}
+
+	if prev != nil {
+		for _, id := range prev.OrgIDs {
+			if want[id] {
+				continue
+			}
+			if err := db.OrgMembers.Remove(ctx, id, userID); err != nil {
+				return errors.Wrapf(err, "remove user from organization %d", id)
+			}
+		}
+	}
+
+	return store.SetUserGroupPermissions(ctx, &authz.UserGroupPermissions{
+		UserID:       userID,
+		ServiceType:  spec.ServiceType,
+		ServiceID:    spec.ServiceID,
+		Groups:       matched,
+		OrgIDs:       orgIDs,
+		RepoPatterns: patterns,
+		UpdatedAt:    time.Now(),
+	})
+}
+
+// previous returns the group permissions previously recorded for the user and the authentication
+// provider, or nil if there are none.
+func previous(ctx context.Context, store *edb.PermsStore, userID int32, spec extsvc.AccountSpec) (*authz.UserGroupPermissions, error) {
+	ps, err := store.LoadUserGroupPermissions(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range ps {
+		if p.ServiceType == spec.ServiceType && p.ServiceID == spec.ServiceID {
+			return p, nil
+		}
+	}
+	return nil, nil
+}
+
+// validateRepoPattern returns an error if the repository pattern is not a valid Go regular
+// expression, on its own or anchored.
+func validateRepoPattern(pattern string) error {
+	if _, err := regexp.Compile(pattern); err != nil {
+		return err
+	}
+	_, err := regexp.Compile(edb.AnchorRepoPattern(pattern))
+	return err
+}
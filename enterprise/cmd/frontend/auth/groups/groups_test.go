@@ -0,0 +1,207 @@
+package groups
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/authz"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	edb "github.com/sourcegraph/sourcegraph/enterprise/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+func TestApply(t *testing.T) {
+	spec := extsvc.AccountSpec{ServiceType: "saml", ServiceID: "https://idp.example.com"}
+	orgs := map[string]int32{"acme": 1, "eng": 2, "ops": 3}
+	mappings := []*schema.AuthGroupMapping{
+		{Group: "engineering", Organizations: []string{"acme", "eng", "missing"}, Repositories: []string{"github\\.com/acme/.*", "(", "a)|(?:b", "(?P<name>x)"}},
+		{Group: "operations", Organizations: []string{"acme", "ops"}, Repositories: []string{"github\\.com/acme/.*"}},
+		{Group: "sales", Organizations: []string{"acme"}},
+	}
+
+	tests := []struct {
+		name     string
+		groups   []string
+		mappings []*schema.AuthGroupMapping
+		members  map[int32]bool
+		prev     *authz.UserGroupPermissions
+
+		wantCreated []int32
+		wantRemoved []int32
+		wantSet     *authz.UserGroupPermissions
+	}{
+		{
+			name:   "no mappings and nothing recorded",
+			groups: []string{"engineering"},
+		},
+		{
+			name:     "no matching groups and nothing recorded",
+			groups:   []string{"marketing"},
+			mappings: mappings,
+		},
+		{
+			name:        "new member",
+			groups:      []string{"engineering", "operations"},
+			mappings:    mappings,
+			wantCreated: []int32{1, 2, 3},
+			wantSet: &authz.UserGroupPermissions{
+				Groups:       []string{"engineering", "operations"},
+				OrgIDs:       []int32{1, 2, 3},
+				RepoPatterns: []string{"github\\.com/acme/.*"},
+			},
+		},
+		{
+			name:        "existing memberships are not recorded",
+			groups:      []string{"engineering"},
+			mappings:    mappings,
+			members:     map[int32]bool{1: true},
+			wantCreated: []int32{2},
+			wantSet: &authz.UserGroupPermissions{
+				Groups:       []string{"engineering"},
+				OrgIDs:       []int32{2},
+				RepoPatterns: []string{"github\\.com/acme/.*"},
+			},
+		},
+		{
+			name:     "removed from group",
+			groups:   []string{"sales"},
+			mappings: mappings,
+			members:  map[int32]bool{1: true, 2: true, 3: true},
+			prev: &authz.UserGroupPermissions{
+				Groups:       []string{"engineering", "operations"},
+				OrgIDs:       []int32{1, 2, 3},
+				RepoPatterns: []string{"github\\.com/acme/.*"},
+			},
+			wantRemoved: []int32{2, 3},
+			wantSet: &authz.UserGroupPermissions{
+				Groups: []string{"sales"},
+				OrgIDs: []int32{1},
+			},
+		},
+		{
+			name:    "mappings removed from config",
+			groups:  []string{"engineering"},
+			members: map[int32]bool{2: true},
+			prev: &authz.UserGroupPermissions{
+				Groups:       []string{"engineering"},
+				OrgIDs:       []int32{2},
+				RepoPatterns: []string{"github\\.com/acme/.*"},
+			},
+			wantRemoved: []int32{2},
+			wantSet:     &authz.UserGroupPermissions{},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			defer func() {
+				db.Mocks = db.MockStores{}
+				edb.Mocks.Perms = edb.MockPerms{}
+			}()
+
+			db.Mocks.Orgs.GetByName = func(ctx context.Context, name string) (*types.Org, error) {
+				if id, ok := orgs[name]; ok {
+					return &types.Org{ID: id, Name: name}, nil
+				}
+				return nil, &db.OrgNotFoundError{Message: name}
+			}
+			db.Mocks.OrgMembers.GetByOrgIDAndUserID = func(ctx context.Context, orgID, userID int32) (*types.OrgMembership, error) {
+				if test.members[orgID] {
+					return &types.OrgMembership{OrgID: orgID, UserID: userID}, nil
+				}
+				return nil, &db.ErrOrgMemberNotFound{}
+			}
+			var created, removed []int32
+			db.Mocks.OrgMembers.Create = func(ctx context.Context, orgID, userID int32, role types.OrgRole) (*types.OrgMembership, error) {
+				if role != types.OrgRoleMember {
+					t.Errorf("got role %q, want %q", role, types.OrgRoleMember)
+				}
+				created = append(created, orgID)
+				return &types.OrgMembership{OrgID: orgID, UserID: userID, Role: role}, nil
+			}
+			db.Mocks.OrgMembers.Remove = func(ctx context.Context, orgID, userID int32) error {
+				removed = append(removed, orgID)
+				return nil
+			}
+			edb.Mocks.Perms.LoadUserGroupPermissions = func(ctx context.Context, userID int32) ([]*authz.UserGroupPermissions, error) {
+				if test.prev == nil {
+					return nil, nil
+				}
+				other := &authz.UserGroupPermissions{UserID: userID, ServiceType: "openidconnect", ServiceID: "https://oidc.example.com", OrgIDs: []int32{3}}
+				prev := *test.prev
+				prev.UserID, prev.ServiceType, prev.ServiceID = userID, spec.ServiceType, spec.ServiceID
+				return []*authz.UserGroupPermissions{other, &prev}, nil
+			}
+			edb.Mocks.Perms.ValidateRepoPattern = func(ctx context.Context, pattern string) error {
+				// PostgreSQL doesn't support named capture groups.
+				if strings.Contains(pattern, "(?P<") {
+					return errors.New("invalid regular expression")
+				}
+				return nil
+			}
+			var set *authz.UserGroupPermissions
+			edb.Mocks.Perms.SetUserGroupPermissions = func(ctx context.Context, p *authz.UserGroupPermissions) error {
+				if p.UpdatedAt.IsZero() {
+					t.Error("want UpdatedAt to be set")
+				}
+				set = p
+				return nil
+			}
+
+			if err := Apply(context.Background(), 42, spec, test.groups, test.mappings); err != nil {
+				t.Fatal(err)
+			}
+
+			sortIDs(created)
+			sortIDs(removed)
+			if !reflect.DeepEqual(created, test.wantCreated) {
+				t.Errorf("created memberships: got %v, want %v", created, test.wantCreated)
+			}
+			if !reflect.DeepEqual(removed, test.wantRemoved) {
+				t.Errorf("removed memberships: got %v, want %v", removed, test.wantRemoved)
+			}
+
+			if test.wantSet == nil {
+				if set != nil {
+					t.Errorf("unexpected group permissions %+v", set)
+				}
+				return
+			}
+			if set == nil {
+				t.Fatal("want group permissions to be set")
+			}
+			if set.UserID != 42 || set.ServiceType != spec.ServiceType || set.ServiceID != spec.ServiceID {
+				t.Errorf("got user %d and provider %s %s", set.UserID, set.ServiceType, set.ServiceID)
+			}
+			sortIDs(set.OrgIDs)
+			set.UserID, set.ServiceType, set.ServiceID = 0, "", ""
+			set.UpdatedAt = test.wantSet.UpdatedAt
+			if !reflect.DeepEqual(set, test.wantSet) {
+				t.Errorf("got group permissions %+v, want %+v", set, test.wantSet)
+			}
+		})
+	}
+}
+
+func sortIDs(ids []int32) {
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+}
+
+func TestValidateRepoPattern(t *testing.T) {
+	for pattern, valid := range map[string]bool{
+		"github\\.com/acme/.*": true,
+		"github\\.com/(a|b)":   true,
+		"(":                    false,
+		"a)|(?:b":              false,
+		"a)|(b":                false,
+	} {
+		if err := validateRepoPattern(pattern); (err == nil) != valid {
+			t.Errorf("validateRepoPattern(%q): got error %v, want valid %t", pattern, err, valid)
+		}
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/enterprise/cmd/frontend/auth/githuboauth"
 	"github.com/sourcegraph/sourcegraph/enterprise/cmd/frontend/auth/gitlaboauth"
 	"github.com/sourcegraph/sourcegraph/enterprise/cmd/frontend/auth/httpheader"
+	"github.com/sourcegraph/sourcegraph/enterprise/cmd/frontend/auth/ldap"
 	"github.com/sourcegraph/sourcegraph/enterprise/cmd/frontend/auth/openidconnect"
 	"github.com/sourcegraph/sourcegraph/enterprise/cmd/frontend/auth/saml"
 	"github.com/sourcegraph/sourcegraph/internal/conf"
@@ -21,6 +22,7 @@ func init() {
 		openidconnect.Middleware,
 		saml.Middleware,
 		httpheader.Middleware,
+		ldap.Middleware,
 		githuboauth.Middleware,
 		gitlaboauth.Middleware,
 	)
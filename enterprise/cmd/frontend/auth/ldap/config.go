@@ -0,0 +1,61 @@
+package ldap
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/auth/providers"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+var mockGetProviderValue *provider
+
+// getProvider looks up the registered LDAP auth provider with the given ID.
+func getProvider(id string) *provider {
+	if mockGetProviderValue != nil {
+		return mockGetProviderValue
+	}
+	p, _ := providers.GetProviderByConfigID(providers.ConfigID{Type: providerType, ID: id}).(*provider)
+	return p
+}
+
+func init() {
+	conf.ContributeValidator(validateConfig)
+}
+
+func validateConfig(c conf.Unified) (problems conf.Problems) {
+	seen := map[string]int{}
+	for i, p := range c.AuthProviders {
+		if p.Ldap == nil {
+			continue
+		}
+		if j, ok := seen[p.Ldap.Url]; ok {
+			problems = append(problems, conf.NewSiteProblem(fmt.Sprintf("LDAP auth provider at index %d has the same url as the one at index %d", i, j)))
+		} else {
+			seen[p.Ldap.Url] = i
+		}
+		if p.Ldap.UserSearchFilter != "" && !strings.Contains(p.Ldap.UserSearchFilter, "%s") {
+			problems = append(problems, conf.NewSiteProblem(fmt.Sprintf("LDAP auth provider at index %d has a userSearchFilter without a %%s placeholder for the username", i)))
+		}
+		if strings.HasPrefix(p.Ldap.Url, "ldap://") && !p.Ldap.StartTLS {
+			problems = append(problems, conf.NewSiteProblem(fmt.Sprintf("LDAP auth provider at index %d sends passwords unencrypted: use an ldaps:// url or set startTLS", i)))
+		}
+	}
+	return problems
+}
+
+// providerConfigID produces a semi-stable identifier for an LDAP auth provider config object. It
+// is used to distinguish between multiple auth providers of the same type when signing in. Its
+// value is never persisted, and it must be deterministic.
+func providerConfigID(pc *schema.LDAPAuthProvider) string {
+	data, err := json.Marshal(pc)
+	if err != nil {
+		panic(err)
+	}
+	b := sha256.Sum256(data)
+	return base64.RawURLEncoding.EncodeToString(b[:16])
+}
@@ -0,0 +1,62 @@
+package ldap
+
+import (
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+func TestValidateCustom(t *testing.T) {
+	tests := map[string]struct {
+		input        conf.Unified
+		wantProblems conf.Problems
+	}{
+		"single": {
+			input: conf.Unified{SiteConfiguration: schema.SiteConfiguration{
+				AuthProviders: []schema.AuthProviders{
+					{Ldap: &schema.LDAPAuthProvider{Type: "ldap", Url: "ldaps://ldap.example.com"}},
+				},
+			}},
+			wantProblems: nil,
+		},
+		"duplicate url": {
+			input: conf.Unified{SiteConfiguration: schema.SiteConfiguration{
+				AuthProviders: []schema.AuthProviders{
+					{Ldap: &schema.LDAPAuthProvider{Type: "ldap", Url: "ldaps://ldap.example.com"}},
+					{Ldap: &schema.LDAPAuthProvider{Type: "ldap", Url: "ldaps://ldap.example.com", DisplayName: "x"}},
+				},
+			}},
+			wantProblems: conf.NewSiteProblems("same url"),
+		},
+		"filter without placeholder": {
+			input: conf.Unified{SiteConfiguration: schema.SiteConfiguration{
+				AuthProviders: []schema.AuthProviders{
+					{Ldap: &schema.LDAPAuthProvider{Type: "ldap", Url: "ldaps://ldap.example.com", UserSearchFilter: "(uid=alice)"}},
+				},
+			}},
+			wantProblems: conf.NewSiteProblems("placeholder"),
+		},
+		"plain text": {
+			input: conf.Unified{SiteConfiguration: schema.SiteConfiguration{
+				AuthProviders: []schema.AuthProviders{
+					{Ldap: &schema.LDAPAuthProvider{Type: "ldap", Url: "ldap://ldap.example.com"}},
+				},
+			}},
+			wantProblems: conf.NewSiteProblems("unencrypted"),
+		},
+		"StartTLS": {
+			input: conf.Unified{SiteConfiguration: schema.SiteConfiguration{
+				AuthProviders: []schema.AuthProviders{
+					{Ldap: &schema.LDAPAuthProvider{Type: "ldap", Url: "ldap://ldap.example.com", StartTLS: true}},
+				},
+			}},
+			wantProblems: nil,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			conf.TestValidator(t, test.input, validateConfig, test.wantProblems)
+		})
+	}
+}
@@ -0,0 +1,81 @@
+// Package ldap implements authentication with the username and password of a user in an LDAP
+// directory.
+package ldap
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/inconshreveable/log15"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/auth"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/external/session"
+)
+
+// All LDAP endpoints are under this path prefix.
+const authPrefix = auth.AuthURLPrefix + "/ldap"
+
+// Middleware is middleware for LDAP authentication, adding the sign-in endpoint under the auth path
+// prefix. Users sign in with a form on the sign-in page that posts their credentials to it.
+//
+// 🚨 SECURITY
+var Middleware = &auth.Middleware{
+	API: func(next http.Handler) http.Handler { return next },
+	App: func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == authPrefix+"/sign-in" {
+				signInHandler(w, r)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	},
+}
+
+type credentials struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+func signInHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Error(w, "", http.StatusMethodNotAllowed)
+		return
+	}
+	// 🚨 SECURITY: The auth middleware runs before the CSRF middleware, so require a header that
+	// cross-origin requests can't set without a CORS preflight (which we don't allow) to prevent
+	// login CSRF.
+	if r.Header.Get("X-Requested-With") == "" {
+		http.Error(w, "Missing X-Requested-With header.", http.StatusBadRequest)
+		return
+	}
+
+	p := getProvider(r.URL.Query().Get("pc"))
+	if p == nil {
+		log15.Error("No LDAP auth provider found with ID.", "id", r.URL.Query().Get("pc"))
+		http.Error(w, "Misconfigured LDAP auth provider.", http.StatusInternalServerError)
+		return
+	}
+
+	var creds credentials
+	if err := json.NewDecoder(r.Body).Decode(&creds); err != nil {
+		http.Error(w, "Could not decode request body", http.StatusBadRequest)
+		return
+	}
+
+	actor, safeErrMsg, err := getOrCreateUser(r.Context(), p, creds.Username, creds.Password)
+	if err == errInvalidCredentials {
+		http.Error(w, "Authentication failed", http.StatusUnauthorized)
+		return
+	}
+	if err != nil {
+		log15.Error("Error authenticating with LDAP.", "username", creds.Username, "error", err, "userErr", safeErrMsg)
+		http.Error(w, safeErrMsg, http.StatusInternalServerError)
+		return
+	}
+
+	if err := session.SetActor(w, r, actor, 0); err != nil {
+		log15.Error("Error setting LDAP-authenticated actor in session.", "err", err)
+		http.Error(w, "Could not create new user session", http.StatusInternalServerError)
+		return
+	}
+}
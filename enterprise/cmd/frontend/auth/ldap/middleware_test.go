@@ -0,0 +1,190 @@
+package ldap
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/auth"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/external/session"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/ldap/ldaptest"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+func newTestServer() *ldaptest.Server {
+	s := ldaptest.NewUnstartedServer(
+		&ldaptest.Entry{
+			DN:       "cn=sourcegraph,ou=services,dc=example,dc=com",
+			Password: "servicepw",
+		},
+		&ldaptest.Entry{
+			DN: "uid=alice,ou=people,dc=example,dc=com",
+			Attributes: map[string][]string{
+				"objectClass": {"inetOrgPerson"},
+				"uid":         {"alice"},
+				"mail":        {"alice@example.com"},
+				"cn":          {"Alice Zhao"},
+			},
+			Password: "alicepw",
+		},
+		&ldaptest.Entry{
+			DN: "uid=bob,ou=contractors,dc=example,dc=com",
+			Attributes: map[string][]string{
+				"objectClass": {"inetOrgPerson"},
+				"uid":         {"bob"},
+				"displayName": {"Bob Contractor"},
+			},
+			Password: "bobpw",
+		},
+		&ldaptest.Entry{
+			DN: "uid=bob,ou=people,dc=example,dc=com",
+			Attributes: map[string][]string{
+				"objectClass": {"inetOrgPerson"},
+				"uid":         {"bob"},
+			},
+			Password: "bobpw2",
+		},
+	)
+	s.RequireTLS = true
+	s.Start()
+	return s
+}
+
+func TestMiddleware(t *testing.T) {
+	cleanup := session.ResetMockSessionStore(t)
+	defer cleanup()
+
+	s := newTestServer()
+	defer s.Close()
+
+	mockGetProviderValue = &provider{
+		config: schema.LDAPAuthProvider{
+			Type:             providerType,
+			Url:              s.URL,
+			StartTLS:         true,
+			Certificate:      s.Certificate,
+			BindDN:           "cn=sourcegraph,ou=services,dc=example,dc=com",
+			BindPassword:     "servicepw",
+			UserSearchBaseDN: "dc=example,dc=com",
+		},
+	}
+	defer func() { mockGetProviderValue = nil }()
+
+	var gotOp *auth.GetAndSaveUserOp
+	auth.MockGetAndSaveUser = func(ctx context.Context, op auth.GetAndSaveUserOp) (userID int32, safeErrMsg string, err error) {
+		gotOp = &op
+		return 123, "", nil
+	}
+	defer func() { auth.MockGetAndSaveUser = nil }()
+
+	h := http.NewServeMux()
+	h.Handle("/.api/", Middleware.API(http.NotFoundHandler()))
+	h.Handle("/", Middleware.App(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	})))
+
+	signIn := func(username, password string) *http.Response {
+		body, _ := json.Marshal(credentials{Username: username, Password: password})
+		req := httptest.NewRequest("POST", mockGetProviderValue.CachedInfo().AuthenticationURL, bytes.NewReader(body))
+		req.Header.Set("X-Requested-With", "Sourcegraph")
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec.Result()
+	}
+
+	t.Run("other paths are passed through", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest("GET", "/search", nil))
+		if rec.Code != http.StatusTeapot {
+			t.Errorf("got status %d, want %d", rec.Code, http.StatusTeapot)
+		}
+	})
+
+	t.Run("valid credentials", func(t *testing.T) {
+		gotOp = nil
+		resp := signIn("alice", "alicepw")
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("got status %d, want %d", resp.StatusCode, http.StatusOK)
+		}
+		if len(resp.Cookies()) == 0 {
+			t.Error("expected a session cookie")
+		}
+		if gotOp == nil {
+			t.Fatal("user was not saved")
+		}
+		want := auth.GetAndSaveUserOp{
+			UserProps: db.NewUser{
+				Username:        "alice",
+				Email:           "alice@example.com",
+				EmailIsVerified: true,
+				DisplayName:     "Alice Zhao",
+			},
+			ExternalAccount: extsvc.AccountSpec{
+				ServiceType: "ldap",
+				ServiceID:   s.URL,
+				AccountID:   "uid=alice,ou=people,dc=example,dc=com",
+			},
+			CreateIfNotExist: true,
+		}
+		gotOp.ExternalAccountData = extsvc.AccountData{}
+		if !reflect.DeepEqual(*gotOp, want) {
+			t.Errorf("got op %+v, want %+v", *gotOp, want)
+		}
+	})
+
+	t.Run("invalid credentials", func(t *testing.T) {
+		for _, creds := range [][2]string{{"alice", "wrong"}, {"alice", ""}, {"nobody", "x"}, {"*", "alicepw"}, {"", ""}} {
+			gotOp = nil
+			if resp := signIn(creds[0], creds[1]); resp.StatusCode != http.StatusUnauthorized {
+				t.Errorf("%q: got status %d, want %d", creds, resp.StatusCode, http.StatusUnauthorized)
+			}
+			if gotOp != nil {
+				t.Errorf("%q: user was saved", creds)
+			}
+		}
+	})
+
+	t.Run("ambiguous username", func(t *testing.T) {
+		if resp := signIn("bob", "bobpw"); resp.StatusCode != http.StatusInternalServerError {
+			t.Errorf("got status %d, want %d", resp.StatusCode, http.StatusInternalServerError)
+		}
+	})
+
+	t.Run("attribute mapping and search filter", func(t *testing.T) {
+		defer func(c schema.LDAPAuthProvider) { mockGetProviderValue.config = c }(mockGetProviderValue.config)
+		mockGetProviderValue.config.UserSearchFilter = "(&(objectClass=inetOrgPerson)(uid=%s))"
+		mockGetProviderValue.config.UserSearchBaseDN = "ou=contractors,dc=example,dc=com"
+		mockGetProviderValue.config.Attributes = &schema.LDAPAttributes{DisplayName: "displayName"}
+
+		gotOp = nil
+		if resp := signIn("bob", "bobpw"); resp.StatusCode != http.StatusOK {
+			t.Fatalf("got status %d, want %d", resp.StatusCode, http.StatusOK)
+		}
+		if gotOp == nil || gotOp.UserProps.DisplayName != "Bob Contractor" || gotOp.UserProps.EmailIsVerified {
+			t.Errorf("unexpected op %+v", gotOp)
+		}
+	})
+
+	t.Run("missing header", func(t *testing.T) {
+		req := httptest.NewRequest("POST", mockGetProviderValue.CachedInfo().AuthenticationURL, bytes.NewBufferString(`{}`))
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("got status %d, want %d", rec.Code, http.StatusBadRequest)
+		}
+	})
+
+	t.Run("wrong service account password", func(t *testing.T) {
+		defer func(c schema.LDAPAuthProvider) { mockGetProviderValue.config = c }(mockGetProviderValue.config)
+		mockGetProviderValue.config.BindPassword = "wrong"
+		if resp := signIn("alice", "alicepw"); resp.StatusCode != http.StatusInternalServerError {
+			t.Errorf("got status %d, want %d", resp.StatusCode, http.StatusInternalServerError)
+		}
+	})
+}
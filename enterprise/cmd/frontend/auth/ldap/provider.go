@@ -0,0 +1,133 @@
+package ldap
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/go-ldap/ldap/v3"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/auth"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/auth/providers"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+const providerType = "ldap"
+
+type provider struct {
+	config schema.LDAPAuthProvider
+}
+
+// ConfigID implements providers.Provider.
+func (p *provider) ConfigID() providers.ConfigID {
+	return providers.ConfigID{
+		Type: providerType,
+		ID:   providerConfigID(&p.config),
+	}
+}
+
+// Config implements providers.Provider.
+func (p *provider) Config() schema.AuthProviders {
+	return schema.AuthProviders{Ldap: &p.config}
+}
+
+// Refresh implements providers.Provider.
+func (p *provider) Refresh(context.Context) error { return nil }
+
+// CachedInfo implements providers.Provider.
+func (p *provider) CachedInfo() *providers.Info {
+	info := &providers.Info{
+		ServiceID:   p.config.Url,
+		DisplayName: p.config.DisplayName,
+		AuthenticationURL: (&url.URL{
+			Path:     path.Join(auth.AuthURLPrefix, "ldap", "sign-in"),
+			RawQuery: (url.Values{"pc": []string{providerConfigID(&p.config)}}).Encode(),
+		}).String(),
+	}
+	if info.DisplayName == "" {
+		info.DisplayName = "LDAP"
+	}
+	return info
+}
+
+// Connect connects to the LDAP server of the auth provider configuration (securing the connection
+// with TLS as configured) and binds as its service account. The deadline of ctx, if any, bounds the
+// connection and each operation performed with it.
+//
+// 🚨 SECURITY: The caller must close the returned connection.
+func Connect(ctx context.Context, pc *schema.LDAPAuthProvider) (*ldap.Conn, error) {
+	u, err := url.Parse(pc.Url)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme != "ldap" && u.Scheme != "ldaps" {
+		return nil, fmt.Errorf("unsupported LDAP URL scheme %q (must be ldap or ldaps)", u.Scheme)
+	}
+
+	tlsConfig := &tls.Config{ServerName: u.Hostname()}
+	if pc.Certificate != "" {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM([]byte(pc.Certificate)) {
+			return nil, errors.New("invalid LDAP server certificate")
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	timeout := ldap.DefaultTimeout
+	if deadline, ok := ctx.Deadline(); ok {
+		timeout = time.Until(deadline)
+	}
+	conn, err := ldap.DialURL(pc.Url, ldap.DialWithDialer(&net.Dialer{Timeout: timeout}), ldap.DialWithTLSConfig(tlsConfig))
+	if err != nil {
+		return nil, err
+	}
+	conn.SetTimeout(timeout)
+	if pc.StartTLS && u.Scheme == "ldap" {
+		if err := conn.StartTLS(tlsConfig); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+	if pc.BindDN != "" {
+		if err := conn.Bind(pc.BindDN, pc.BindPassword); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("bind as service account %q: %w", pc.BindDN, err)
+		}
+	}
+	return conn, nil
+}
+
+// userAttributes returns the names of the attributes that hold the username, email address, and
+// display name of users.
+func userAttributes(pc *schema.LDAPAuthProvider) (username, email, displayName string) {
+	username, email, displayName = "uid", "mail", "cn"
+	if a := pc.Attributes; a != nil {
+		if a.Username != "" {
+			username = a.Username
+		}
+		if a.Email != "" {
+			email = a.Email
+		}
+		if a.DisplayName != "" {
+			displayName = a.DisplayName
+		}
+	}
+	return username, email, displayName
+}
+
+// userSearchFilter returns the filter that matches the entry of the user with the given username.
+func userSearchFilter(pc *schema.LDAPAuthProvider, username string) string {
+	filter := pc.UserSearchFilter
+	if filter == "" {
+		filter = "(uid=%s)"
+	}
+	// 🚨 SECURITY: The username must be escaped so that users can't alter the filter.
+	return strings.Replace(filter, "%s", ldap.EscapeFilter(username), -1)
+}
@@ -0,0 +1,51 @@
+package ldap
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/internal/ldap/ldaptest"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+func TestConnect(t *testing.T) {
+	ctx := context.Background()
+
+	for _, ldaps := range []bool{false, true} {
+		s := ldaptest.NewUnstartedServer(&ldaptest.Entry{
+			DN:       "cn=sourcegraph,ou=services,dc=example,dc=com",
+			Password: "servicepw",
+		})
+		s.RequireTLS = true
+		if ldaps {
+			s.StartTLS()
+		} else {
+			s.Start()
+		}
+		defer s.Close()
+
+		pc := &schema.LDAPAuthProvider{
+			Url:          s.URL,
+			StartTLS:     !ldaps,
+			BindDN:       "cn=sourcegraph,ou=services,dc=example,dc=com",
+			BindPassword: "servicepw",
+		}
+		if conn, err := Connect(ctx, pc); err == nil {
+			conn.Close()
+			t.Fatalf("%s: expected the self-signed certificate to be rejected", s.URL)
+		}
+
+		pc.Certificate = s.Certificate
+		conn, err := Connect(ctx, pc)
+		if err != nil {
+			t.Fatalf("%s: %s", s.URL, err)
+		}
+		conn.Close()
+
+		pc.BindPassword = "wrong"
+		if conn, err := Connect(ctx, pc); err == nil {
+			conn.Close()
+			t.Fatalf("%s: expected the bind with the wrong password to fail", s.URL)
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package ldap
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-ldap/ldap/v3"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/auth"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+// errInvalidCredentials is returned when the username is unknown or the password is incorrect. The
+// two cases are not distinguished to avoid disclosing which usernames exist.
+var errInvalidCredentials = errors.New("invalid LDAP credentials")
+
+// accountData is the data of LDAP external accounts.
+type accountData struct {
+	DN          string `json:"dn"`
+	Username    string `json:"username"`
+	Email       string `json:"email,omitempty"`
+	DisplayName string `json:"displayName,omitempty"`
+}
+
+// authenticate checks the user's password by binding as their entry in the directory, and returns
+// the entry.
+func authenticate(ctx context.Context, pc *schema.LDAPAuthProvider, username, password string) (*ldap.Entry, error) {
+	// 🚨 SECURITY: An empty password would make the bind as the user an unauthenticated bind, which
+	// always succeeds.
+	if username == "" || password == "" {
+		return nil, errInvalidCredentials
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	conn, err := Connect(ctx, pc)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	usernameAttr, emailAttr, displayNameAttr := userAttributes(pc)
+	result, err := conn.Search(ldap.NewSearchRequest(
+		pc.UserSearchBaseDN, ldap.ScopeWholeSubtree, ldap.NeverDerefAliases, 2, 0, false,
+		userSearchFilter(pc, username),
+		[]string{usernameAttr, emailAttr, displayNameAttr},
+		nil,
+	))
+	if ldap.IsErrorWithCode(err, ldap.LDAPResultSizeLimitExceeded) || result != nil && len(result.Entries) > 1 {
+		// 🚨 SECURITY: Refuse to guess which user is signing in.
+		return nil, fmt.Errorf("more than one entry matches the user search filter for username %q", username)
+	} else if err != nil {
+		return nil, err
+	}
+	if len(result.Entries) == 0 {
+		return nil, errInvalidCredentials
+	}
+
+	// 🚨 SECURITY: Check the password.
+	if err := conn.Bind(result.Entries[0].DN, password); ldap.IsErrorWithCode(err, ldap.LDAPResultInvalidCredentials) {
+		return nil, errInvalidCredentials
+	} else if err != nil {
+		return nil, err
+	}
+	return result.Entries[0], nil
+}
+
+// getOrCreateUser authenticates the user with the LDAP directory and gets or creates the user
+// account of their entry. It returns the authenticated actor if successful; otherwise it returns an
+// friendly error message (safeErrMsg) that is safe to display to users, and a non-nil err with
+// lower-level error details. The err is errInvalidCredentials if the credentials are incorrect.
+func getOrCreateUser(ctx context.Context, p *provider, username, password string) (_ *actor.Actor, safeErrMsg string, err error) {
+	entry, err := authenticate(ctx, &p.config, username, password)
+	if err == errInvalidCredentials {
+		return nil, "", err
+	} else if err != nil {
+		return nil, "Unexpected error communicating with the LDAP server.", err
+	}
+
+	usernameAttr, emailAttr, displayNameAttr := userAttributes(&p.config)
+	data := accountData{
+		DN:          entry.DN,
+		Username:    entry.GetEqualFoldAttributeValue(usernameAttr),
+		Email:       entry.GetEqualFoldAttributeValue(emailAttr),
+		DisplayName: entry.GetEqualFoldAttributeValue(displayNameAttr),
+	}
+	if data.Username == "" {
+		return nil, fmt.Sprintf("The LDAP entry of the user has no %q attribute for the username.", usernameAttr), fmt.Errorf("no %q attribute in LDAP entry %q", usernameAttr, entry.DN)
+	}
+	login, err := auth.NormalizeUsername(data.Username)
+	if err != nil {
+		return nil, fmt.Sprintf("Error normalizing the username %q. See https://docs.sourcegraph.com/admin/auth/#username-normalization.", data.Username), err
+	}
+
+	var extData extsvc.AccountData
+	extData.SetAccountData(data)
+
+	userID, safeErrMsg, err := auth.GetAndSaveUser(ctx, auth.GetAndSaveUserOp{
+		UserProps: db.NewUser{
+			Username: login,
+			Email:    data.Email,
+			// The directory is the source of truth for the email addresses of its users.
+			EmailIsVerified: data.Email != "",
+			DisplayName:     data.DisplayName,
+		},
+		ExternalAccount: extsvc.AccountSpec{
+			ServiceType: providerType,
+			ServiceID:   p.config.Url,
+			AccountID:   entry.DN,
+		},
+		ExternalAccountData: extData,
+		CreateIfNotExist:    true,
+	})
+	if err != nil {
+		return nil, safeErrMsg, err
+	}
+	return actor.FromUser(userID), "", nil
+}
@@ -7,6 +7,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"reflect"
 
 	"github.com/inconshreveable/log15"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/auth/providers"
@@ -60,15 +61,25 @@ func validateConfig(c conf.Unified) (problems conf.Problems) {
 		}
 	}
 
-	seen := map[schema.OpenIDConnectAuthProvider]int{}
+	// The provider configs are not comparable (they contain slices), so look for duplicates with
+	// reflect.DeepEqual.
+	var seen []int
 	for i, p := range c.AuthProviders {
-		if p.Openidconnect != nil {
-			if j, ok := seen[*p.Openidconnect]; ok {
-				problems = append(problems, conf.NewSiteProblem(fmt.Sprintf("OpenID Connect auth provider at index %d is duplicate of index %d, ignoring", i, j)))
-			} else {
-				seen[*p.Openidconnect] = i
+		if p.Openidconnect == nil {
+			continue
+		}
+		dup := -1
+		for _, j := range seen {
+			if reflect.DeepEqual(*c.AuthProviders[j].Openidconnect, *p.Openidconnect) {
+				dup = j
+				break
 			}
 		}
+		if dup >= 0 {
+			problems = append(problems, conf.NewSiteProblem(fmt.Sprintf("OpenID Connect auth provider at index %d is duplicate of index %d, ignoring", i, dup)))
+		} else {
+			seen = append(seen, i)
+		}
 	}
 
 	return problems
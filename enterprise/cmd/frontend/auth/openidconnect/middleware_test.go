@@ -10,6 +10,7 @@ import (
 	"net/http/httptest"
 	"net/url"
 	"os"
+	"reflect"
 	"strings"
 	"testing"
 	"time"
@@ -18,9 +19,11 @@ import (
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/auth"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/auth/providers"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/external/session"
+	"github.com/sourcegraph/sourcegraph/enterprise/cmd/frontend/auth/groups"
 	"github.com/sourcegraph/sourcegraph/enterprise/cmd/frontend/internal/licensing"
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/license"
 	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
 	"github.com/sourcegraph/sourcegraph/schema"
 )
 
@@ -96,7 +99,8 @@ func newOIDCIDServer(t *testing.T, code string, oidcProvider *schema.OpenIDConne
 			"profile": "This is a profile",
 			"email": "`+email+`",
 			"email_verified": true,
-			"picture": "https://example.com/picture.png"
+			"picture": "https://example.com/picture.png",
+			"groups": ["engineering", "operations"]
 		}`, testOIDCUser)))
 	})
 
@@ -108,6 +112,12 @@ func newOIDCIDServer(t *testing.T, code string, oidcProvider *schema.OpenIDConne
 		}
 		return 0, "safeErr", fmt.Errorf("account %v not found in mock", op.ExternalAccount)
 	}
+	groups.MockApply = func(ctx context.Context, userID int32, spec extsvc.AccountSpec, groupNames []string, mappings []*schema.AuthGroupMapping) error {
+		if want := []string{"engineering", "operations"}; userID != 123 || !reflect.DeepEqual(groupNames, want) {
+			return fmt.Errorf("got user %d with groups %q, want user 123 with groups %q", userID, groupNames, want)
+		}
+		return nil
+	}
 
 	return srv, &email
 }
@@ -141,6 +151,7 @@ func TestMiddleware(t *testing.T) {
 	oidcIDServer, emailPtr := newOIDCIDServer(t, "THECODE", &mockGetProviderValue.config)
 	defer oidcIDServer.Close()
 	defer func() { auth.MockGetAndSaveUser = nil }()
+	defer func() { groups.MockApply = nil }()
 	mockGetProviderValue.config.Issuer = oidcIDServer.URL
 
 	if err := mockGetProviderValue.Refresh(context.Background()); err != nil {
@@ -320,6 +331,7 @@ func TestMiddleware_NoOpenRedirect(t *testing.T) {
 	oidcIDServer, _ := newOIDCIDServer(t, "THECODE", &mockGetProviderValue.config)
 	defer oidcIDServer.Close()
 	defer func() { auth.MockGetAndSaveUser = nil }()
+	defer func() { groups.MockApply = nil }()
 	mockGetProviderValue.config.Issuer = oidcIDServer.URL
 
 	if err := mockGetProviderValue.Refresh(context.Background()); err != nil {
@@ -2,12 +2,14 @@ package openidconnect
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 
 	oidc "github.com/coreos/go-oidc"
 	"github.com/pkg/errors"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/auth"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/enterprise/cmd/frontend/auth/groups"
 	"github.com/sourcegraph/sourcegraph/internal/actor"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc"
 )
@@ -75,5 +77,50 @@ func getOrCreateUser(ctx context.Context, p *provider, idToken *oidc.IDToken, us
 	if err != nil {
 		return nil, safeErrMsg, err
 	}
+
+	groupNames, err := groupsClaim(p.config.GroupsClaimName, idToken, userInfo)
+	if err != nil {
+		return nil, "Error reading the groups of the user from the OpenID Connect provider.", err
+	}
+	spec := extsvc.AccountSpec{ServiceType: providerType, ServiceID: pi.ServiceID}
+	if err := groups.Apply(ctx, userID, spec, groupNames, p.config.GroupMappings); err != nil {
+		return nil, "Unexpected error applying the group memberships from the OpenID Connect provider.", err
+	}
 	return actor.FromUser(userID), "", nil
 }
+
+// groupsClaim returns the groups of the user from the claim with the given name (or "groups" if
+// empty). The claim is read from the UserInfo response, falling back to the ID token, and may be
+// either a list of strings or a single string.
+func groupsClaim(name string, idToken *oidc.IDToken, userInfo *oidc.UserInfo) ([]string, error) {
+	if name == "" {
+		name = "groups"
+	}
+
+	var raw json.RawMessage
+	for _, src := range []interface{ Claims(interface{}) error }{userInfo, idToken} {
+		var claims map[string]json.RawMessage
+		if err := src.Claims(&claims); err != nil {
+			// The claims of a source are not always available (e.g. when the UserInfo response
+			// was not JSON), so just move on to the next one.
+			continue
+		}
+		if v, ok := claims[name]; ok && string(v) != "null" {
+			raw = v
+			break
+		}
+	}
+	if raw == nil {
+		return nil, nil
+	}
+
+	var groupNames []string
+	if err := json.Unmarshal(raw, &groupNames); err != nil {
+		var group string
+		if err := json.Unmarshal(raw, &group); err != nil {
+			return nil, errors.Errorf("claim %q is neither a string nor a list of strings", name)
+		}
+		groupNames = []string{group}
+	}
+	return groupNames, nil
+}
@@ -9,6 +9,7 @@ import (
 	"log"
 	"net/http"
 	"path"
+	"reflect"
 	"strconv"
 	"strings"
 
@@ -75,15 +76,25 @@ func validateConfig(c conf.Unified) (problems conf.Problems) {
 		}
 	}
 
-	seen := map[schema.SAMLAuthProvider]int{}
+	// The provider configs are not comparable (they contain slices), so look for duplicates with
+	// reflect.DeepEqual.
+	var seen []int
 	for i, p := range c.AuthProviders {
-		if p.Saml != nil {
-			if j, ok := seen[*p.Saml]; ok {
-				problems = append(problems, conf.NewSiteProblem(fmt.Sprintf("SAML auth provider at index %d is duplicate of index %d, ignoring", i, j)))
-			} else {
-				seen[*p.Saml] = i
+		if p.Saml == nil {
+			continue
+		}
+		dup := -1
+		for _, j := range seen {
+			if reflect.DeepEqual(*c.AuthProviders[j].Saml, *p.Saml) {
+				dup = j
+				break
 			}
 		}
+		if dup >= 0 {
+			problems = append(problems, conf.NewSiteProblem(fmt.Sprintf("SAML auth provider at index %d is duplicate of index %d, ignoring", i, dup)))
+		} else {
+			seen = append(seen, i)
+		}
 	}
 
 	return problems
@@ -24,10 +24,12 @@ import (
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/auth"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/auth/providers"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/external/session"
+	"github.com/sourcegraph/sourcegraph/enterprise/cmd/frontend/auth/groups"
 	"github.com/sourcegraph/sourcegraph/enterprise/cmd/frontend/internal/licensing"
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/license"
 	"github.com/sourcegraph/sourcegraph/internal/actor"
 	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
 	"github.com/sourcegraph/sourcegraph/schema"
 )
 
@@ -208,6 +210,13 @@ func TestMiddleware(t *testing.T) {
 		return 0, "safeErr", fmt.Errorf("account %v not found in mock", op.ExternalAccount)
 	}
 	defer func() { auth.MockGetAndSaveUser = nil }()
+	groups.MockApply = func(ctx context.Context, userID int32, spec extsvc.AccountSpec, groupNames []string, mappings []*schema.AuthGroupMapping) error {
+		if userID != mockedUserID {
+			return fmt.Errorf("got user %d, want %d", userID, mockedUserID)
+		}
+		return nil
+	}
+	defer func() { groups.MockApply = nil }()
 
 	// Set up the test handler.
 	authedHandler := http.NewServeMux()
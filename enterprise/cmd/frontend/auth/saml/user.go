@@ -10,6 +10,7 @@ import (
 	saml2 "github.com/russellhaering/gosaml2"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/auth"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/enterprise/cmd/frontend/auth/groups"
 	"github.com/sourcegraph/sourcegraph/internal/actor"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc"
 )
@@ -18,6 +19,7 @@ type authnResponseInfo struct {
 	spec                 extsvc.AccountSpec
 	email, displayName   string
 	unnormalizedUsername string
+	groups               []string
 	accountData          interface{}
 }
 
@@ -69,6 +71,15 @@ func readAuthnResponse(p *provider, encodedResp string) (*authnResponseInfo, err
 		displayName:          firstNonempty(attr.Get("displayName"), attr.Get("givenName")+" "+attr.Get("surname"), attr.Get("http://schemas.xmlsoap.org/claims/CommonName"), attr.Get("http://schemas.xmlsoap.org/ws/2005/05/identity/claims/givenname")),
 		accountData:          assertions,
 	}
+	groupsAttr := p.config.GroupsAttributeName
+	if groupsAttr == "" {
+		groupsAttr = "groups"
+	}
+	for _, g := range attr.GetAll(groupsAttr) {
+		if g = strings.TrimSpace(g); g != "" {
+			info.groups = append(info.groups, g)
+		}
+	}
 	if assertions.NameID == "" {
 		return nil, errors.New("the SAML response did not contain a valid NameID")
 	}
@@ -84,7 +95,7 @@ func readAuthnResponse(p *provider, encodedResp string) (*authnResponseInfo, err
 // getOrCreateUser gets or creates a user account based on the SAML claims. It returns the
 // authenticated actor if successful; otherwise it returns an friendly error message (safeErrMsg)
 // that is safe to display to users, and a non-nil err with lower-level error details.
-func getOrCreateUser(ctx context.Context, info *authnResponseInfo) (_ *actor.Actor, safeErrMsg string, err error) {
+func getOrCreateUser(ctx context.Context, p *provider, info *authnResponseInfo) (_ *actor.Actor, safeErrMsg string, err error) {
 	var data extsvc.AccountData
 	data.SetAccountData(info.accountData)
 
@@ -108,6 +119,9 @@ func getOrCreateUser(ctx context.Context, info *authnResponseInfo) (_ *actor.Act
 	if err != nil {
 		return nil, safeErrMsg, err
 	}
+	if err := groups.Apply(ctx, userID, info.spec, info.groups, p.config.GroupMappings); err != nil {
+		return nil, "Unexpected error applying the group memberships from the SAML response.", err
+	}
 	return actor.FromUser(userID), "", nil
 }
 
@@ -125,3 +139,16 @@ func (v samlAssertionValues) Get(key string) string {
 	}
 	return ""
 }
+
+// GetAll returns all values of the attributes with the given name or friendly name.
+func (v samlAssertionValues) GetAll(key string) []string {
+	var values []string
+	for _, a := range v {
+		if a.Name == key || a.FriendlyName == key {
+			for _, av := range a.Values {
+				values = append(values, av.Value)
+			}
+		}
+	}
+	return values
+}
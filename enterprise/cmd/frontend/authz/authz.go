@@ -4,7 +4,6 @@ import (
 	"context"
 	"fmt"
 	"net/http"
-	"strconv"
 	"strings"
 	"time"
 
@@ -18,6 +17,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/enterprise/cmd/frontend/internal/authz/bitbucketserver"
 	"github.com/sourcegraph/sourcegraph/enterprise/cmd/frontend/internal/authz/github"
 	"github.com/sourcegraph/sourcegraph/enterprise/cmd/frontend/internal/authz/gitlab"
+	"github.com/sourcegraph/sourcegraph/enterprise/cmd/frontend/internal/authz/gitolite"
 	"github.com/sourcegraph/sourcegraph/enterprise/cmd/frontend/internal/licensing"
 	"github.com/sourcegraph/sourcegraph/internal/conf"
 	"github.com/sourcegraph/sourcegraph/internal/db/dbconn"
@@ -71,6 +71,20 @@ func Init(d dbutil.DB, clock func() time.Time) {
 			}
 		}
 
+		gitolites, err := db.ExternalServices.ListGitoliteConnections(ctx)
+		if err != nil {
+			return []*graphqlbackend.Alert{{
+				TypeValue:    graphqlbackend.AlertTypeError,
+				MessageValue: fmt.Sprintf("Unable to fetch Gitolite external services: %s", err),
+			}}
+		}
+		for _, g := range gitolites {
+			if g.Authorization != nil {
+				authzTypes = append(authzTypes, "Gitolite")
+				break
+			}
+		}
+
 		if len(authzTypes) > 0 {
 			return []*graphqlbackend.Alert{{
 				TypeValue:    graphqlbackend.AlertTypeError,
@@ -136,6 +150,7 @@ type ExternalServicesStore interface {
 	ListGitLabConnections(context.Context) ([]*schema.GitLabConnection, error)
 	ListGitHubConnections(context.Context) ([]*schema.GitHubConnection, error)
 	ListBitbucketServerConnections(context.Context) ([]*schema.BitbucketServerConnection, error)
+	ListGitoliteConnections(context.Context) ([]*schema.GitoliteConnection, error)
 }
 
 // ProvidersFromConfig returns the set of permission-related providers derived from the site config.
@@ -146,7 +161,7 @@ func ProvidersFromConfig(
 	ctx context.Context,
 	cfg *conf.Unified,
 	s ExternalServicesStore,
-	db dbutil.DB, // Needed by Bitbucket Server authz provider
+	db dbutil.DB, // Needed by Bitbucket Server and Gitolite authz providers
 ) (
 	allowAccessByDefault bool,
 	providers []authz.Provider,
@@ -188,17 +203,13 @@ func ProvidersFromConfig(
 		warnings = append(warnings, bbsWarnings...)
 	}
 
-	// 🚨 SECURITY: Warn the admin when both code host authz provider and the permissions user mapping are configured.
-	if cfg.SiteConfiguration.PermissionsUserMapping != nil &&
-		cfg.SiteConfiguration.PermissionsUserMapping.Enabled && len(providers) > 0 {
-		serviceTypes := make([]string, len(providers))
-		for i := range providers {
-			serviceTypes[i] = strconv.Quote(providers[i].ServiceType())
-		}
-		msg := fmt.Sprintf(
-			"The permissions user mapping (site configuration `permissions.userMapping`) cannot be enabled when %s authorization providers are in use. Blocking access to all repositories until the conflict is resolved.",
-			strings.Join(serviceTypes, ", "))
-		seriousProblems = append(seriousProblems, msg)
+	if gitoliteConns, err := s.ListGitoliteConnections(ctx); err != nil {
+		seriousProblems = append(seriousProblems, fmt.Sprintf("Could not load Gitolite external service configs: %s", err))
+	} else {
+		gitoliteProviders, gitoliteProblems, gitoliteWarnings := gitolite.NewAuthzProviders(cfg, gitoliteConns, db)
+		providers = append(providers, gitoliteProviders...)
+		seriousProblems = append(seriousProblems, gitoliteProblems...)
+		warnings = append(warnings, gitoliteWarnings...)
 	}
 
 	return allowAccessByDefault, providers, seriousProblems, warnings
@@ -456,7 +456,7 @@ func Test_authzProvidersFromConfig(t *testing.T) {
 
 		// For Sourcegraph authz provider
 		{
-			description: "Conflicted configuration between Sourcegraph and GitLab authz provider",
+			description: "Sourcegraph and GitLab authz providers used together",
 			cfg: conf.Unified{
 				SiteConfiguration: schema.SiteConfiguration{
 					PermissionsUserMapping: &schema.PermissionsUserMapping{
@@ -484,11 +484,10 @@ func Test_authzProvidersFromConfig(t *testing.T) {
 					Token: "asdf",
 				},
 			},
-			expAuthzAllowAccessByDefault: false,
-			expSeriousProblems:           []string{"The permissions user mapping (site configuration `permissions.userMapping`) cannot be enabled when \"gitlab\" authorization providers are in use. Blocking access to all repositories until the conflict is resolved."},
+			expAuthzAllowAccessByDefault: true,
 		},
 		{
-			description: "Conflicted configuration between Sourcegraph and Bitbucket Server authz provider",
+			description: "Sourcegraph and Bitbucket Server authz providers used together",
 			cfg: conf.Unified{
 				SiteConfiguration: schema.SiteConfiguration{
 					PermissionsUserMapping: &schema.PermissionsUserMapping{
@@ -516,8 +515,7 @@ func Test_authzProvidersFromConfig(t *testing.T) {
 					Token:    "secret-token",
 				},
 			},
-			expAuthzAllowAccessByDefault: false,
-			expSeriousProblems:           []string{"The permissions user mapping (site configuration `permissions.userMapping`) cannot be enabled when \"bitbucketServer\" authorization providers are in use. Blocking access to all repositories until the conflict is resolved."},
+			expAuthzAllowAccessByDefault: true,
 		},
 	}
 
@@ -563,6 +561,7 @@ type fakeStore struct {
 	gitlabs          []*schema.GitLabConnection
 	githubs          []*schema.GitHubConnection
 	bitbucketServers []*schema.BitbucketServerConnection
+	gitolites        []*schema.GitoliteConnection
 }
 
 func (s fakeStore) ListGitHubConnections(context.Context) ([]*schema.GitHubConnection, error) {
@@ -576,3 +575,7 @@ func (s fakeStore) ListGitLabConnections(context.Context) ([]*schema.GitLabConne
 func (s fakeStore) ListBitbucketServerConnections(context.Context) ([]*schema.BitbucketServerConnection, error) {
 	return s.bitbucketServers, nil
 }
+
+func (s fakeStore) ListGitoliteConnections(context.Context) ([]*schema.GitoliteConnection, error) {
+	return s.gitolites, nil
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/globals"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/db/dbutil"
 )
 
@@ -122,17 +123,43 @@ func (s *authzStore) AuthorizedRepos(ctx context.Context, args *db.AuthorizedRep
 		Perm:   args.Perm,
 		Type:   args.Type,
 	}
-	if err := s.store.LoadUserPermissions(ctx, p); err != nil {
-		if err == authz.ErrPermsNotFound {
-			return []*types.Repo{}, nil
-		}
+	if err := s.store.LoadUserPermissions(ctx, p); err != nil && err != authz.ErrPermsNotFound {
 		return nil, err
 	}
 
-	perms := p.AuthorizedRepos(args.Repos)
-	filtered := make([]*types.Repo, len(perms))
-	for i, r := range perms {
-		filtered[i] = r.Repo
+	filtered := make([]*types.Repo, 0, len(args.Repos))
+	authorized := make(map[api.RepoID]bool, len(args.Repos))
+	for _, r := range p.AuthorizedRepos(args.Repos) {
+		filtered = append(filtered, r.Repo)
+		authorized[r.Repo.ID] = true
+	}
+
+	// Repositories can also be authorized through the groups of the user on authentication
+	// providers, which grant read access only.
+	if args.Perm != authz.Read || args.Type != authz.PermRepos || len(filtered) == len(args.Repos) {
+		return filtered, nil
+	}
+	repoIDs := make([]api.RepoID, 0, len(args.Repos)-len(filtered))
+	for _, r := range args.Repos {
+		if !authorized[r.ID] {
+			repoIDs = append(repoIDs, r.ID)
+		}
+	}
+	groupRepoIDs, err := s.store.GroupAuthorizedRepoIDs(ctx, args.UserID, repoIDs)
+	if err != nil {
+		return nil, errors.Wrap(err, "list group authorized repositories")
+	}
+	for _, id := range groupRepoIDs {
+		authorized[id] = true
+	}
+	if len(groupRepoIDs) > 0 {
+		// Keep the order of the given repositories.
+		filtered = filtered[:0]
+		for _, r := range args.Repos {
+			if authorized[r.ID] {
+				filtered = append(filtered, r)
+			}
+		}
 	}
 	return filtered, nil
 }
@@ -151,6 +178,10 @@ func (s *authzStore) RevokeUserPermissions(ctx context.Context, args *db.RevokeU
 		return errors.Wrap(err, "delete all user permissions")
 	}
 
+	if err = txs.DeleteUserGroupPermissions(ctx, args.UserID); err != nil {
+		return errors.Wrap(err, "delete user group permissions")
+	}
+
 	for _, accounts := range args.Accounts {
 		if err := txs.DeleteAllUserPendingPermissions(ctx, accounts); err != nil {
 			return errors.Wrap(err, "delete all user pending permissions")
@@ -5,6 +5,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/enterprise/cmd/frontend/internal/authz/bitbucketserver"
 	"github.com/sourcegraph/sourcegraph/enterprise/cmd/frontend/internal/authz/github"
 	"github.com/sourcegraph/sourcegraph/enterprise/cmd/frontend/internal/authz/gitlab"
+	"github.com/sourcegraph/sourcegraph/enterprise/cmd/frontend/internal/authz/gitolite"
 	"github.com/sourcegraph/sourcegraph/schema"
 )
 
@@ -21,5 +22,8 @@ func NewExternalServicesStore() *db.ExternalServicesStore {
 		BitbucketServerValidators: []func(*schema.BitbucketServerConnection) error{
 			bitbucketserver.ValidateAuthz,
 		},
+		GitoliteValidators: []func(*schema.GitoliteConnection, []schema.AuthProviders) error{
+			gitolite.ValidateAuthz,
+		},
 	}
 }
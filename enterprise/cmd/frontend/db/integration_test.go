@@ -32,6 +32,8 @@ func TestIntegration_PermsStore(t *testing.T) {
 		{"PermsStore/GrantPendingPermissions", testPermsStore_GrantPendingPermissions(db)},
 		{"PermsStore/DeleteAllUserPermissions", testPermsStore_DeleteAllUserPermissions(db)},
 		{"PermsStore/DeleteAllUserPendingPermissions", testPermsStore_DeleteAllUserPendingPermissions(db)},
+		{"PermsStore/UserGroupPermissions", testPermsStore_UserGroupPermissions(db)},
+		{"PermsStore/SyncHistory", testPermsStore_SyncHistory(db)},
 		{"PermsStore/DatabaseDeadlocks", testPermsStore_DatabaseDeadlocks(db)},
 
 		{"PermsStore/ListExternalAccounts", testPermsStore_ListExternalAccounts(db)},
@@ -8,6 +8,7 @@ import (
 
 	"github.com/RoaringBitmap/roaring"
 	"github.com/keegancsmith/sqlf"
+	"github.com/lib/pq"
 	otlog "github.com/opentracing/opentracing-go/log"
 	"github.com/pkg/errors"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/authz"
@@ -1082,6 +1083,255 @@ AND service_id = %s
 	return bindIDs, nil
 }
 
+// LoadUserGroupPermissions returns the group permissions of the user on every authentication
+// provider they signed in with.
+func (s *PermsStore) LoadUserGroupPermissions(ctx context.Context, userID int32) (ps []*authz.UserGroupPermissions, err error) {
+	if Mocks.Perms.LoadUserGroupPermissions != nil {
+		return Mocks.Perms.LoadUserGroupPermissions(ctx, userID)
+	}
+
+	ctx, save := s.observe(ctx, "LoadUserGroupPermissions", "")
+	defer func() { save(&err, otlog.Int32("userID", userID)) }()
+
+	q := sqlf.Sprintf(`
+-- source: enterprise/cmd/frontend/db/perms_store.go:PermsStore.LoadUserGroupPermissions
+SELECT user_id, service_type, service_id, groups, org_ids, repo_patterns, updated_at
+FROM user_group_permissions
+WHERE user_id = %s
+ORDER BY service_type, service_id
+`, userID)
+	rows, err := s.db.QueryContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			p      authz.UserGroupPermissions
+			orgIDs []int64
+		)
+		if err = rows.Scan(
+			&p.UserID, &p.ServiceType, &p.ServiceID,
+			pq.Array(&p.Groups), pq.Array(&orgIDs), pq.Array(&p.RepoPatterns),
+			&p.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		for _, id := range orgIDs {
+			p.OrgIDs = append(p.OrgIDs, int32(id))
+		}
+		ps = append(ps, &p)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return ps, nil
+}
+
+// SetUserGroupPermissions replaces the group permissions of the user on the authentication
+// provider of p. Repositories matching the repository patterns of p are authorized for the user
+// in addition to the ones in "user_permissions".
+func (s *PermsStore) SetUserGroupPermissions(ctx context.Context, p *authz.UserGroupPermissions) (err error) {
+	if Mocks.Perms.SetUserGroupPermissions != nil {
+		return Mocks.Perms.SetUserGroupPermissions(ctx, p)
+	}
+
+	ctx, save := s.observe(ctx, "SetUserGroupPermissions", "")
+	defer func() { save(&err, p.TracingFields()...) }()
+
+	if p.UpdatedAt.IsZero() {
+		return ErrPermsUpdatedAtNotSet
+	}
+
+	for _, pattern := range p.RepoPatterns {
+		if err = s.ValidateRepoPattern(ctx, pattern); err != nil {
+			return err
+		}
+	}
+
+	q := sqlf.Sprintf(`
+-- source: enterprise/cmd/frontend/db/perms_store.go:PermsStore.SetUserGroupPermissions
+INSERT INTO user_group_permissions
+  (user_id, service_type, service_id, groups, org_ids, repo_patterns, updated_at)
+VALUES
+  (%s, %s, %s, %s, %s, %s, %s)
+ON CONFLICT ON CONSTRAINT
+  user_group_permissions_user_service_unique
+DO UPDATE SET
+  groups = excluded.groups,
+  org_ids = excluded.org_ids,
+  repo_patterns = excluded.repo_patterns,
+  updated_at = excluded.updated_at
+`,
+		p.UserID, p.ServiceType, p.ServiceID,
+		pq.Array(nonNilStrings(p.Groups)), pq.Array(toInt64s(p.OrgIDs)), pq.Array(nonNilStrings(p.RepoPatterns)),
+		p.UpdatedAt.UTC(),
+	)
+	if err = s.execute(ctx, q); err != nil {
+		return errors.Wrap(err, "execute upsert user group permissions query")
+	}
+
+	return nil
+}
+
+// DeleteUserGroupPermissions deletes the group permissions of the user on all authentication
+// providers.
+func (s *PermsStore) DeleteUserGroupPermissions(ctx context.Context, userID int32) (err error) {
+	ctx, save := s.observe(ctx, "DeleteUserGroupPermissions", "")
+	defer func() { save(&err, otlog.Int32("userID", userID)) }()
+
+	if err = s.execute(ctx, sqlf.Sprintf(`DELETE FROM user_group_permissions WHERE user_id = %s`, userID)); err != nil {
+		return errors.Wrap(err, "execute delete user group permissions query")
+	}
+
+	return nil
+}
+
+// AnchorRepoPattern returns the regular expression that a repository pattern of group permissions
+// stands for. Repository patterns must match entire repository names.
+func AnchorRepoPattern(pattern string) string {
+	return "^(?:" + pattern + ")$"
+}
+
+// repoPatternsMatchCond is the condition that the name of "repo" matches one of the repository
+// patterns of the group permissions "g". It must be kept in sync with AnchorRepoPattern.
+const repoPatternsMatchCond = `EXISTS (SELECT FROM unnest(g.repo_patterns) AS p WHERE repo.name ~ ('^(?:' || p || ')$'))`
+
+// ValidateRepoPattern returns an error if the given repository pattern is not a valid PostgreSQL
+// regular expression. Repository patterns are usually validated as Go regular expressions, but
+// they're matched by PostgreSQL, whose syntax differs.
+//
+// 🚨 SECURITY: The pattern is validated on its own as well as anchored, so that patterns such as
+// "a)|(?:b" can't escape the anchors.
+func (s *PermsStore) ValidateRepoPattern(ctx context.Context, pattern string) error {
+	if Mocks.Perms.ValidateRepoPattern != nil {
+		return Mocks.Perms.ValidateRepoPattern(ctx, pattern)
+	}
+
+	q := sqlf.Sprintf(`
+-- source: enterprise/cmd/frontend/db/perms_store.go:PermsStore.ValidateRepoPattern
+SELECT '' ~ %s, '' ~ %s
+`, pattern, AnchorRepoPattern(pattern))
+	rows, err := s.db.QueryContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...)
+	if err != nil {
+		return errors.Wrapf(err, "invalid repository pattern %q", pattern)
+	}
+	defer rows.Close()
+
+	// The regular expression may only be compiled when the row is read.
+	for rows.Next() {
+	}
+	if err = rows.Err(); err != nil {
+		return errors.Wrapf(err, "invalid repository pattern %q", pattern)
+	}
+	return nil
+}
+
+// GroupAuthorizedRepoIDs returns the IDs of the repositories that the user can read through the
+// repository patterns of their groups. When repoIDs is not nil, only the given repositories are
+// considered. Repository names are matched with PostgreSQL regular expressions, anchored with
+// AnchorRepoPattern.
+func (s *PermsStore) GroupAuthorizedRepoIDs(ctx context.Context, userID int32, repoIDs []api.RepoID) (ids []api.RepoID, err error) {
+	if Mocks.Perms.GroupAuthorizedRepoIDs != nil {
+		return Mocks.Perms.GroupAuthorizedRepoIDs(ctx, userID, repoIDs)
+	}
+
+	ctx, save := s.observe(ctx, "GroupAuthorizedRepoIDs", "")
+	defer func() { save(&err, otlog.Int32("userID", userID), otlog.Int("repoIDs.count", len(repoIDs))) }()
+
+	conds := []*sqlf.Query{
+		sqlf.Sprintf("g.user_id = %s", userID),
+		sqlf.Sprintf("repo.deleted_at IS NULL"),
+		sqlf.Sprintf(repoPatternsMatchCond),
+	}
+	if repoIDs != nil {
+		ids := make([]int64, len(repoIDs))
+		for i := range repoIDs {
+			ids[i] = int64(repoIDs[i])
+		}
+		conds = append(conds, sqlf.Sprintf("repo.id = ANY(%s)", pq.Array(ids)))
+	}
+
+	q := sqlf.Sprintf(`
+-- source: enterprise/cmd/frontend/db/perms_store.go:PermsStore.GroupAuthorizedRepoIDs
+SELECT DISTINCT repo.id
+FROM repo, user_group_permissions AS g
+WHERE %s
+`, sqlf.Join(conds, "AND"))
+	rows, err := s.db.QueryContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id api.RepoID
+		if err = rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+// GroupAuthorizedUserIDs returns the IDs of the users who can read the repository through the
+// repository patterns of their groups.
+func (s *PermsStore) GroupAuthorizedUserIDs(ctx context.Context, repoID api.RepoID) (ids []int32, err error) {
+	if Mocks.Perms.GroupAuthorizedUserIDs != nil {
+		return Mocks.Perms.GroupAuthorizedUserIDs(ctx, repoID)
+	}
+
+	ctx, save := s.observe(ctx, "GroupAuthorizedUserIDs", "")
+	defer func() { save(&err, otlog.Int32("repoID", int32(repoID))) }()
+
+	q := sqlf.Sprintf(`
+-- source: enterprise/cmd/frontend/db/perms_store.go:PermsStore.GroupAuthorizedUserIDs
+SELECT DISTINCT g.user_id
+FROM repo, user_group_permissions AS g
+WHERE repo.id = %s
+AND `+repoPatternsMatchCond+`
+`, repoID)
+	rows, err := s.db.QueryContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id int32
+		if err = rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+func nonNilStrings(ss []string) []string {
+	if ss == nil {
+		return []string{}
+	}
+	return ss
+}
+
+func toInt64s(is []int32) []int64 {
+	ids := make([]int64, len(is))
+	for i := range is {
+		ids[i] = int64(is[i])
+	}
+	return ids
+}
+
 // DeleteAllUserPermissions deletes all rows with given user ID from the "user_permissions" table,
 // which effectively removes access to all repositories for the user.
 func (s *PermsStore) DeleteAllUserPermissions(ctx context.Context, userID int32) (err error) {
@@ -1123,6 +1373,145 @@ AND bind_id IN (%s)`,
 	return nil
 }
 
+// PermsSyncHistory is the record of one permissions sync of a user or a repository with the authz
+// providers of code hosts. Exactly one of UserID and RepoID is set.
+type PermsSyncHistory struct {
+	ID     int64
+	UserID int32
+	RepoID api.RepoID
+	// The service IDs of the authz providers that permissions were fetched from.
+	Providers  []string
+	StartedAt  time.Time
+	FinishedAt time.Time
+	// The number of repositories the user was authorized for, for a user sync.
+	NumRepos int
+	// The number of users authorized for the repository, for a repository sync.
+	NumUsers int
+	// The number of code host accounts without a user that were granted pending permissions, for a
+	// repository sync.
+	NumPendingAccounts int
+	// Whether an authz provider returned an error and the permissions were saved from its partial
+	// results.
+	Partial bool
+	// The error that made the sync fail, if any.
+	Error string
+}
+
+// maxPermsSyncHistory is the number of most recent syncs that are kept for each user and repository.
+const maxPermsSyncHistory = 20
+
+// RecordSyncHistory saves the record of a permissions sync, and deletes the oldest records of the
+// same user or repository beyond the most recent ones.
+func (s *PermsStore) RecordSyncHistory(ctx context.Context, h *PermsSyncHistory) (err error) {
+	if Mocks.Perms.RecordSyncHistory != nil {
+		return Mocks.Perms.RecordSyncHistory(ctx, h)
+	}
+
+	ctx, save := s.observe(ctx, "RecordSyncHistory", "")
+	defer func() { save(&err, otlog.Int32("userID", h.UserID), otlog.Int32("repoID", int32(h.RepoID))) }()
+
+	var cond *sqlf.Query
+	if h.UserID != 0 {
+		cond = sqlf.Sprintf("user_id = %s", h.UserID)
+	} else {
+		cond = sqlf.Sprintf("repo_id = %s", h.RepoID)
+	}
+
+	// The inserted row is not visible to the DELETE (both see the same snapshot), so it keeps all
+	// but the most recent existing rows in addition to the new one.
+	q := sqlf.Sprintf(`
+-- source: enterprise/cmd/frontend/db/perms_store.go:PermsStore.RecordSyncHistory
+WITH inserted AS (
+  INSERT INTO perms_sync_history
+    (user_id, repo_id, providers, started_at, finished_at, num_repos, num_users, num_pending_accounts, partial, error)
+  VALUES
+    (%s, %s, %s, %s, %s, %s, %s, %s, %s, %s)
+  RETURNING id
+)
+DELETE FROM perms_sync_history
+WHERE %s
+AND id NOT IN (
+  SELECT id FROM perms_sync_history
+  WHERE %s
+  ORDER BY id DESC
+  LIMIT %s
+)
+`,
+		nullInt32(h.UserID), nullInt32(int32(h.RepoID)), pq.Array(nonNilStrings(h.Providers)),
+		h.StartedAt.UTC(), h.FinishedAt.UTC(), h.NumRepos, h.NumUsers, h.NumPendingAccounts, h.Partial,
+		nullString(h.Error),
+		cond, cond, maxPermsSyncHistory-1,
+	)
+	if err = s.execute(ctx, q); err != nil {
+		return errors.Wrap(err, "execute insert sync history query")
+	}
+
+	return nil
+}
+
+// ListSyncHistory returns the most recent permissions syncs of the user (when userID is not zero)
+// or of the repository, most recent first.
+func (s *PermsStore) ListSyncHistory(ctx context.Context, userID int32, repoID api.RepoID, limit int) (hs []*PermsSyncHistory, err error) {
+	if Mocks.Perms.ListSyncHistory != nil {
+		return Mocks.Perms.ListSyncHistory(ctx, userID, repoID, limit)
+	}
+
+	ctx, save := s.observe(ctx, "ListSyncHistory", "")
+	defer func() { save(&err, otlog.Int32("userID", userID), otlog.Int32("repoID", int32(repoID))) }()
+
+	var cond *sqlf.Query
+	if userID != 0 {
+		cond = sqlf.Sprintf("user_id = %s", userID)
+	} else {
+		cond = sqlf.Sprintf("repo_id = %s", repoID)
+	}
+
+	q := sqlf.Sprintf(`
+-- source: enterprise/cmd/frontend/db/perms_store.go:PermsStore.ListSyncHistory
+SELECT id, user_id, repo_id, providers, started_at, finished_at, num_repos, num_users, num_pending_accounts, partial, error
+FROM perms_sync_history
+WHERE %s
+ORDER BY id DESC
+LIMIT %s
+`, cond, limit)
+	rows, err := s.db.QueryContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			h              PermsSyncHistory
+			userID, repoID sql.NullInt32
+			syncErr        sql.NullString
+		)
+		if err = rows.Scan(
+			&h.ID, &userID, &repoID, pq.Array(&h.Providers), &h.StartedAt, &h.FinishedAt,
+			&h.NumRepos, &h.NumUsers, &h.NumPendingAccounts, &h.Partial, &syncErr,
+		); err != nil {
+			return nil, err
+		}
+		h.UserID = userID.Int32
+		h.RepoID = api.RepoID(repoID.Int32)
+		h.Error = syncErr.String
+		hs = append(hs, &h)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return hs, nil
+}
+
+func nullInt32(i int32) sql.NullInt32 {
+	return sql.NullInt32{Int32: i, Valid: i != 0}
+}
+
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
+
 func (s *PermsStore) execute(ctx context.Context, q *sqlf.Query, vs ...interface{}) (err error) {
 	ctx, save := s.observe(ctx, "execute", "")
 	defer func() { save(&err, otlog.Object("q", q)) }()
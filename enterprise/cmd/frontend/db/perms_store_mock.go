@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/authz"
+	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc"
 )
 
@@ -18,4 +19,11 @@ type MockPerms struct {
 	ListPendingUsers             func(ctx context.Context) ([]string, error)
 	ListExternalAccounts         func(ctx context.Context, userID int32) ([]*extsvc.Account, error)
 	GetUserIDsByExternalAccounts func(ctx context.Context, accounts *extsvc.Accounts) (map[string]int32, error)
+	LoadUserGroupPermissions     func(ctx context.Context, userID int32) ([]*authz.UserGroupPermissions, error)
+	SetUserGroupPermissions      func(ctx context.Context, p *authz.UserGroupPermissions) error
+	ValidateRepoPattern          func(ctx context.Context, pattern string) error
+	GroupAuthorizedRepoIDs       func(ctx context.Context, userID int32, repoIDs []api.RepoID) ([]api.RepoID, error)
+	GroupAuthorizedUserIDs       func(ctx context.Context, repoID api.RepoID) ([]int32, error)
+	RecordSyncHistory            func(ctx context.Context, h *PermsSyncHistory) error
+	ListSyncHistory              func(ctx context.Context, userID int32, repoID api.RepoID, limit int) ([]*PermsSyncHistory, error)
 }
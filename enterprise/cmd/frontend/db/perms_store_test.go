@@ -1573,6 +1573,193 @@ func testPermsStore_DeleteAllUserPermissions(db *sql.DB) func(*testing.T) {
 	}
 }
 
+func testPermsStore_UserGroupPermissions(db *sql.DB) func(*testing.T) {
+	return func(t *testing.T) {
+		s := NewPermsStore(db, clock)
+		t.Cleanup(func() {
+			cleanupUsersTable(t, s)
+			cleanupReposTable(t, s)
+		})
+
+		ctx := context.Background()
+
+		qs := []*sqlf.Query{
+			sqlf.Sprintf(`INSERT INTO users(username) VALUES('alice')`),          // ID=1
+			sqlf.Sprintf(`INSERT INTO users(username) VALUES('bob')`),            // ID=2
+			sqlf.Sprintf(`INSERT INTO repo(name) VALUES('github.com/acme/api')`), // ID=1
+			sqlf.Sprintf(`INSERT INTO repo(name) VALUES('github.com/acme/web')`), // ID=2
+			sqlf.Sprintf(`INSERT INTO repo(name) VALUES('github.com/other/x')`),  // ID=3
+		}
+		for _, q := range qs {
+			if err := s.execute(ctx, q); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		err := s.SetUserGroupPermissions(ctx, &authz.UserGroupPermissions{
+			UserID:      1,
+			ServiceType: "saml",
+			ServiceID:   "https://idp.example.com",
+		})
+		if err != ErrPermsUpdatedAtNotSet {
+			t.Fatalf("err: want %q but got %v", ErrPermsUpdatedAtNotSet, err)
+		}
+
+		p := &authz.UserGroupPermissions{
+			UserID:       1,
+			ServiceType:  "saml",
+			ServiceID:    "https://idp.example.com",
+			Groups:       []string{"engineering"},
+			OrgIDs:       []int32{5},
+			RepoPatterns: []string{"github\\.com/acme/.*"},
+			UpdatedAt:    clock(),
+		}
+		if err := s.SetUserGroupPermissions(ctx, p); err != nil {
+			t.Fatal(err)
+		}
+
+		ps, err := s.LoadUserGroupPermissions(ctx, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		equal(t, "ps", []*authz.UserGroupPermissions{p}, ps)
+
+		repoIDs, err := s.GroupAuthorizedRepoIDs(ctx, 1, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		equal(t, "repoIDs", []api.RepoID{1, 2}, repoIDs)
+
+		repoIDs, err = s.GroupAuthorizedRepoIDs(ctx, 1, []api.RepoID{2, 3})
+		if err != nil {
+			t.Fatal(err)
+		}
+		equal(t, "repoIDs", []api.RepoID{2}, repoIDs)
+
+		userIDs, err := s.GroupAuthorizedUserIDs(ctx, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		equal(t, "userIDs", []int32{1}, userIDs)
+
+		// Patterns must match entire repository names.
+		p.RepoPatterns = []string{"acme", "github\\.com/other"}
+		if err := s.SetUserGroupPermissions(ctx, p); err != nil {
+			t.Fatal(err)
+		}
+		repoIDs, err = s.GroupAuthorizedRepoIDs(ctx, 1, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		equal(t, "repoIDs", 0, len(repoIDs))
+		userIDs, err = s.GroupAuthorizedUserIDs(ctx, 3)
+		if err != nil {
+			t.Fatal(err)
+		}
+		equal(t, "userIDs", 0, len(userIDs))
+
+		// Patterns that aren't valid PostgreSQL regular expressions, on their own or anchored,
+		// are rejected.
+		for _, pattern := range []string{"(?P<name>x)", "a)|(?:b", "(?i)acme"} {
+			if err := s.ValidateRepoPattern(ctx, pattern); err == nil {
+				t.Errorf("ValidateRepoPattern(%q): want error", pattern)
+			}
+			p.RepoPatterns = []string{pattern}
+			if err := s.SetUserGroupPermissions(ctx, p); err == nil {
+				t.Errorf("SetUserGroupPermissions with pattern %q: want error", pattern)
+			}
+		}
+		if err := s.ValidateRepoPattern(ctx, "github\\.com/(acme|other)/.*"); err != nil {
+			t.Fatal(err)
+		}
+
+		// Replacing the permissions of the provider overwrites the previous ones
+		p.RepoPatterns = nil
+		if err := s.SetUserGroupPermissions(ctx, p); err != nil {
+			t.Fatal(err)
+		}
+		repoIDs, err = s.GroupAuthorizedRepoIDs(ctx, 1, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		equal(t, "repoIDs", 0, len(repoIDs))
+
+		if err := s.DeleteUserGroupPermissions(ctx, 1); err != nil {
+			t.Fatal(err)
+		}
+		ps, err = s.LoadUserGroupPermissions(ctx, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		equal(t, "len(ps)", 0, len(ps))
+	}
+}
+
+func testPermsStore_SyncHistory(db *sql.DB) func(*testing.T) {
+	return func(t *testing.T) {
+		s := NewPermsStore(db, clock)
+		t.Cleanup(func() {
+			cleanupUsersTable(t, s)
+			cleanupReposTable(t, s)
+		})
+
+		ctx := context.Background()
+
+		qs := []*sqlf.Query{
+			sqlf.Sprintf(`INSERT INTO users(username) VALUES('alice')`),          // ID=1
+			sqlf.Sprintf(`INSERT INTO repo(name) VALUES('github.com/acme/api')`), // ID=1
+		}
+		for _, q := range qs {
+			if err := s.execute(ctx, q); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		repoSync := &PermsSyncHistory{
+			RepoID:             1,
+			Providers:          []string{"https://github.com/"},
+			StartedAt:          clock(),
+			FinishedAt:         clock(),
+			NumUsers:           1,
+			NumPendingAccounts: 2,
+		}
+		if err := s.RecordSyncHistory(ctx, repoSync); err != nil {
+			t.Fatal(err)
+		}
+
+		// Only the most recent syncs of the user are kept.
+		for i := 0; i < maxPermsSyncHistory+2; i++ {
+			if err := s.RecordSyncHistory(ctx, &PermsSyncHistory{
+				UserID:     1,
+				Providers:  []string{"https://github.com/"},
+				StartedAt:  clock(),
+				FinishedAt: clock(),
+				NumRepos:   i,
+				Partial:    true,
+				Error:      "rate limit exceeded",
+			}); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		hs, err := s.ListSyncHistory(ctx, 1, 0, 100)
+		if err != nil {
+			t.Fatal(err)
+		}
+		equal(t, "len(hs)", maxPermsSyncHistory, len(hs))
+		equal(t, "latest NumRepos", maxPermsSyncHistory+1, hs[0].NumRepos)
+		equal(t, "latest Error", "rate limit exceeded", hs[0].Error)
+
+		hs, err = s.ListSyncHistory(ctx, 0, 1, 100)
+		if err != nil {
+			t.Fatal(err)
+		}
+		equal(t, "len(hs)", 1, len(hs))
+		repoSync.ID = hs[0].ID
+		equal(t, "repo sync", repoSync, hs[0])
+	}
+}
+
 func testPermsStore_DeleteAllUserPendingPermissions(db *sql.DB) func(*testing.T) {
 	return func(t *testing.T) {
 		s := NewPermsStore(db, clock)
@@ -0,0 +1,105 @@
+package gitolite
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/authz"
+	iauthz "github.com/sourcegraph/sourcegraph/enterprise/cmd/frontend/internal/authz"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/db/dbutil"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitolite"
+	"github.com/sourcegraph/sourcegraph/internal/rcache"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+// NewAuthzProviders returns the set of Gitolite authz providers derived from the connections.
+// It also returns any validation problems with the config, separating these into "serious problems" and
+// "warnings". "Serious problems" are those that should make Sourcegraph set authz.allowAccessByDefault
+// to false. "Warnings" are all other validation problems.
+func NewAuthzProviders(
+	cfg *conf.Unified,
+	conns []*schema.GitoliteConnection,
+	db dbutil.DB,
+) (ps []authz.Provider, problems []string, warnings []string) {
+	// Authorization (i.e., permissions) providers
+	for _, c := range conns {
+		p, err := newAuthzProvider(c, cfg.AuthProviders, db)
+		if err != nil {
+			problems = append(problems, err.Error())
+		} else if p != nil {
+			ps = append(ps, p)
+		}
+	}
+
+	for _, p := range ps {
+		for _, problem := range p.Validate() {
+			warnings = append(warnings, fmt.Sprintf("Gitolite config for %s was invalid: %s", p.ServiceID(), problem))
+		}
+	}
+
+	return ps, problems, warnings
+}
+
+func newAuthzProvider(c *schema.GitoliteConnection, ps []schema.AuthProviders, db dbutil.DB) (*Provider, error) {
+	if c.Authorization == nil {
+		return nil, nil
+	}
+	a := c.Authorization.Ldap
+
+	errs := new(multierror.Error)
+
+	ttl, err := iauthz.ParseTTL(c.Authorization.Ttl)
+	if err != nil {
+		errs = multierror.Append(errs, err)
+	}
+
+	// The LDAP auth provider with the same URL holds the connection settings and the service
+	// account, and is the source of the LDAP external accounts of users.
+	var authn *schema.LDAPAuthProvider
+	for _, p := range ps {
+		if p.Ldap != nil && p.Ldap.Url == a.Url {
+			authn = p.Ldap
+			break
+		}
+	}
+	if authn == nil {
+		errs = multierror.Append(errs, errors.Errorf("Did not find LDAP authentication provider matching %q. Check the [**site configuration**](/site-admin/configuration) to verify an entry in [`auth.providers`](https://docs.sourcegraph.com/admin/auth) exists for %s.", a.Url, a.Url))
+	}
+
+	groups := make(map[string][]*regexp.Regexp, len(a.Groups))
+	for _, g := range a.Groups {
+		for _, pattern := range g.Repositories {
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				errs = multierror.Append(errs, errors.Wrapf(err, "authorization.ldap.groups: repositories of group %q", g.Group))
+				continue
+			}
+			groups[g.Group] = append(groups[g.Group], re)
+		}
+	}
+
+	if err := errs.ErrorOrNil(); err != nil {
+		return nil, err
+	}
+
+	serviceID := gitolite.ServiceID(c.Host)
+	return &Provider{
+		serviceID: serviceID,
+		authn:     *authn,
+		config:    a,
+		groups:    groups,
+		ttl:       ttl,
+		cache:     rcache.NewWithTTL(fmt.Sprintf("gitoliteAuthz:%s", serviceID), int(math.Ceil(ttl.Seconds()))),
+		db:        db,
+	}, nil
+}
+
+// ValidateAuthz validates the authorization fields of the given Gitolite external service config.
+func ValidateAuthz(c *schema.GitoliteConnection, ps []schema.AuthProviders) error {
+	_, err := newAuthzProvider(c, ps, nil)
+	return err
+}
@@ -0,0 +1,359 @@
+// Package gitolite contains an authorization provider for Gitolite that grants users access to
+// repositories based on their groups in an LDAP directory.
+package gitolite
+
+import (
+	"context"
+	"encoding/json"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-ldap/ldap/v3"
+	"github.com/keegancsmith/sqlf"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/authz"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	ldapauth "github.com/sourcegraph/sourcegraph/enterprise/cmd/frontend/auth/ldap"
+	"github.com/sourcegraph/sourcegraph/internal/db/dbutil"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitolite"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+// Provider is an implementation of authz.Provider that grants the users of an LDAP auth provider
+// access to the repositories of a Gitolite instance based on their groups in the LDAP directory.
+//
+// The external accounts of this provider have the normalized DN of the user's LDAP entry as their
+// account ID.
+type Provider struct {
+	serviceID string
+	authn     schema.LDAPAuthProvider // the LDAP auth provider whose directory holds the groups
+	config    schema.GitoliteLDAPAuthorization
+
+	// groups maps the names of the configured groups to the patterns of the repositories that
+	// their members can access.
+	groups map[string][]*regexp.Regexp
+
+	ttl   time.Duration
+	cache cache // caches the groups of users, keyed by normalized DN
+	db    dbutil.DB
+}
+
+var _ authz.Provider = (*Provider)(nil)
+
+type cache interface {
+	Get(key string) ([]byte, bool)
+	Set(key string, b []byte)
+	Delete(key string)
+}
+
+// ServiceID returns the Gitolite host that identifies the Gitolite instance this provider is
+// configured with.
+func (p *Provider) ServiceID() string { return p.serviceID }
+
+// ServiceType returns the type of this Provider, namely, "gitolite".
+func (p *Provider) ServiceType() string { return gitolite.ServiceType }
+
+// Validate validates that the Provider can connect to the LDAP server and bind as the service
+// account of the LDAP auth provider.
+func (p *Provider) Validate() []string {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	conn, err := ldapauth.Connect(ctx, &p.authn)
+	if err != nil {
+		return []string{err.Error()}
+	}
+	conn.Close()
+	return nil
+}
+
+// FetchAccount returns the account of the user for this provider, which is derived from the
+// user's external account of the LDAP auth provider. It returns nil if the user has never signed
+// in with the LDAP auth provider.
+func (p *Provider) FetchAccount(ctx context.Context, user *types.User, current []*extsvc.Account) (mine *extsvc.Account, err error) {
+	if user == nil {
+		return nil, nil
+	}
+
+	for _, acct := range current {
+		if acct.ServiceType == p.authn.Type && acct.ServiceID == p.authn.Url {
+			return &extsvc.Account{
+				UserID: user.ID,
+				AccountSpec: extsvc.AccountSpec{
+					ServiceType: p.ServiceType(),
+					ServiceID:   p.ServiceID(),
+					AccountID:   normalizeDN(acct.AccountID),
+				},
+			}, nil
+		}
+	}
+	return nil, nil
+}
+
+// RepoPerms returns the permissions the given external account has in relation to the given set
+// of repos. Gitolite repositories are private, so an unauthenticated account has no permissions.
+func (p *Provider) RepoPerms(ctx context.Context, account *extsvc.Account, repos []*types.Repo) ([]authz.RepoPerms, error) {
+	if account == nil || account.ServiceType != p.ServiceType() || account.ServiceID != p.ServiceID() {
+		return nil, nil
+	}
+
+	groups, err := p.cachedUserGroups(ctx, account.AccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	perms := make([]authz.RepoPerms, 0, len(repos))
+	for _, repo := range repos {
+		if repo.ExternalRepo.ServiceType != p.ServiceType() || repo.ExternalRepo.ServiceID != p.ServiceID() {
+			continue
+		}
+		if p.canAccess(groups, repo.ExternalRepo.ID) {
+			perms = append(perms, authz.RepoPerms{Repo: repo, Perms: authz.Read})
+		}
+	}
+	return perms, nil
+}
+
+// FetchUserPerms returns the names of the Gitolite repositories that the given account can access
+// through its groups.
+func (p *Provider) FetchUserPerms(ctx context.Context, account *extsvc.Account) ([]extsvc.RepoID, error) {
+	if account == nil || account.ServiceType != p.ServiceType() || account.ServiceID != p.ServiceID() {
+		return nil, nil
+	}
+
+	groups, err := p.userGroups(ctx, account.AccountID)
+	if err != nil {
+		return nil, err
+	}
+	if len(groups) == 0 {
+		return []extsvc.RepoID{}, nil
+	}
+
+	names, err := p.listRepoNames(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]extsvc.RepoID, 0, len(names))
+	for _, name := range names {
+		if p.canAccess(groups, name) {
+			ids = append(ids, extsvc.RepoID(name))
+		}
+	}
+	return ids, nil
+}
+
+// FetchRepoPerms returns the normalized DNs of the members of the groups that can access the given
+// repository.
+func (p *Provider) FetchRepoPerms(ctx context.Context, repo *extsvc.Repository) ([]extsvc.AccountID, error) {
+	if repo == nil || repo.ServiceType != p.ServiceType() || repo.ServiceID != p.ServiceID() {
+		return nil, nil
+	}
+
+	var filter strings.Builder
+	filter.WriteString("(|")
+	for _, group := range p.sortedGroups() {
+		if p.canAccess([]string{group}, repo.ID) {
+			filter.WriteString("(" + p.groupNameAttribute() + "=" + ldap.EscapeFilter(group) + ")")
+		}
+	}
+	filter.WriteString(")")
+	if filter.Len() == len("(|)") {
+		return []extsvc.AccountID{}, nil
+	}
+
+	conn, err := ldapauth.Connect(ctx, &p.authn)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	result, err := conn.Search(ldap.NewSearchRequest(
+		p.config.GroupSearchBaseDN, ldap.ScopeWholeSubtree, ldap.NeverDerefAliases, 0, 0, false,
+		filter.String(),
+		[]string{p.groupMemberAttribute()},
+		nil,
+	))
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	ids := []extsvc.AccountID{}
+	for _, e := range result.Entries {
+		for _, member := range e.GetEqualFoldAttributeValues(p.groupMemberAttribute()) {
+			dn := normalizeDN(member)
+			if !seen[dn] {
+				seen[dn] = true
+				ids = append(ids, extsvc.AccountID(dn))
+			}
+		}
+	}
+	return ids, nil
+}
+
+// canAccess reports whether the members of any of the groups can access the Gitolite repository
+// with the given name.
+func (p *Provider) canAccess(groups []string, repoName string) bool {
+	for _, group := range groups {
+		for _, re := range p.groups[group] {
+			if re.MatchString(repoName) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// cachedUserGroups is like userGroups, but uses the cache.
+func (p *Provider) cachedUserGroups(ctx context.Context, dn string) ([]string, error) {
+	if p.ttl == 0 {
+		return p.userGroups(ctx, dn)
+	}
+
+	if b, ok := p.cache.Get(dn); ok {
+		var groups []string
+		if err := json.Unmarshal(b, &groups); err == nil {
+			return groups, nil
+		}
+		p.cache.Delete(dn)
+	}
+
+	groups, err := p.userGroups(ctx, dn)
+	if err != nil {
+		return nil, err
+	}
+	if b, err := json.Marshal(groups); err == nil {
+		p.cache.Set(dn, b)
+	}
+	return groups, nil
+}
+
+// userGroups returns the names of the configured groups that the user with the given DN is a
+// (direct) member of.
+func (p *Provider) userGroups(ctx context.Context, dn string) ([]string, error) {
+	conn, err := ldapauth.Connect(ctx, &p.authn)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	nameAttr := p.groupNameAttribute()
+	result, err := conn.Search(ldap.NewSearchRequest(
+		p.config.GroupSearchBaseDN, ldap.ScopeWholeSubtree, ldap.NeverDerefAliases, 0, 0, false,
+		// 🚨 SECURITY: The DN must be escaped so that it can't alter the filter.
+		"("+p.groupMemberAttribute()+"="+ldap.EscapeFilter(dn)+")",
+		[]string{nameAttr},
+		nil,
+	))
+	if err != nil {
+		return nil, err
+	}
+
+	groups := []string{}
+	for _, e := range result.Entries {
+		for _, name := range e.GetEqualFoldAttributeValues(nameAttr) {
+			if _, ok := p.groups[name]; ok {
+				groups = append(groups, name)
+			}
+		}
+	}
+	sort.Strings(groups)
+	return groups, nil
+}
+
+func (p *Provider) sortedGroups() []string {
+	groups := make([]string, 0, len(p.groups))
+	for group := range p.groups {
+		groups = append(groups, group)
+	}
+	sort.Strings(groups)
+	return groups
+}
+
+func (p *Provider) groupNameAttribute() string {
+	if p.config.GroupNameAttribute != "" {
+		return p.config.GroupNameAttribute
+	}
+	return "cn"
+}
+
+func (p *Provider) groupMemberAttribute() string {
+	if p.config.GroupMemberAttribute != "" {
+		return p.config.GroupMemberAttribute
+	}
+	return "member"
+}
+
+var mockListRepoNames func(serviceID string) ([]string, error)
+
+// listRepoNames returns the names of the Gitolite repositories of this provider's Gitolite
+// instance.
+func (p *Provider) listRepoNames(ctx context.Context) ([]string, error) {
+	if mockListRepoNames != nil {
+		return mockListRepoNames(p.serviceID)
+	}
+
+	q := sqlf.Sprintf(listRepoNamesQueryFmtStr, p.ServiceType(), p.ServiceID())
+	rows, err := p.db.QueryContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var names []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+	}
+	return names, rows.Err()
+}
+
+const listRepoNamesQueryFmtStr = `
+-- source: enterprise/cmd/frontend/internal/authz/gitolite/provider.go:Provider.listRepoNames
+SELECT external_id FROM repo
+WHERE external_service_type = %s AND external_service_id = %s
+AND deleted_at IS NULL
+ORDER BY external_id ASC
+`
+
+// normalizeDN returns a normalized form of the distinguished name dn, so that different
+// representations of the same DN (such as "uid=Alice, ou=People,dc=example,dc=com" and
+// "uid=alice,ou=people,dc=example,dc=com") compare equal. A DN that can't be parsed is only
+// lowercased.
+//
+// The normalization assumes that attribute values are compared case-insensitively, which is true of
+// the attributes commonly used in DNs (uid, cn, ou, o, dc, etc.).
+func normalizeDN(dn string) string {
+	parsed, err := ldap.ParseDN(dn)
+	if err != nil {
+		return strings.ToLower(dn)
+	}
+	rdns := make([]string, 0, len(parsed.RDNs))
+	for _, rdn := range parsed.RDNs {
+		attrs := make([]string, 0, len(rdn.Attributes))
+		for _, a := range rdn.Attributes {
+			attrs = append(attrs, strings.ToLower(a.Type)+"="+escapeDNValue(strings.ToLower(a.Value)))
+		}
+		rdns = append(rdns, strings.Join(attrs, "+"))
+	}
+	return strings.Join(rdns, ",")
+}
+
+// escapeDNValue escapes an attribute value for use in a DN (RFC 4514 section 2.4).
+func escapeDNValue(v string) string {
+	var b strings.Builder
+	for i := 0; i < len(v); i++ {
+		c := v[i]
+		if strings.IndexByte(`\,+"<>;=`, c) >= 0 || i == 0 && (c == ' ' || c == '#') || i == len(v)-1 && c == ' ' {
+			b.WriteByte('\\')
+		}
+		b.WriteByte(c)
+	}
+	return b.String()
+}
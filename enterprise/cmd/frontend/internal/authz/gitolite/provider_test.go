@@ -0,0 +1,262 @@
+package gitolite
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/authz"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/ldap/ldaptest"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+const gitoliteHost = "git@gitolite.example.com"
+
+func newTestProvider(t *testing.T) (*Provider, *ldaptest.Server) {
+	s := ldaptest.NewServer(
+		&ldaptest.Entry{
+			DN:       "cn=sourcegraph,ou=services,dc=example,dc=com",
+			Password: "servicepw",
+		},
+		&ldaptest.Entry{
+			DN: "cn=engineering,ou=groups,dc=example,dc=com",
+			Attributes: map[string][]string{
+				"cn":     {"engineering"},
+				"member": {"uid=alice,ou=people,dc=example,dc=com", "UID=Bob, OU=People, DC=example, DC=com"},
+			},
+		},
+		&ldaptest.Entry{
+			DN: "cn=security,ou=groups,dc=example,dc=com",
+			Attributes: map[string][]string{
+				"cn":     {"security"},
+				"member": {"uid=carol,ou=people,dc=example,dc=com"},
+			},
+		},
+		&ldaptest.Entry{
+			DN: "cn=unconfigured,ou=groups,dc=example,dc=com",
+			Attributes: map[string][]string{
+				"cn":     {"unconfigured"},
+				"member": {"uid=dave,ou=people,dc=example,dc=com"},
+			},
+		},
+	)
+
+	p, err := newAuthzProvider(
+		&schema.GitoliteConnection{
+			Host: gitoliteHost,
+			Authorization: &schema.GitoliteAuthorization{
+				Ldap: schema.GitoliteLDAPAuthorization{
+					Url:               s.URL,
+					GroupSearchBaseDN: "ou=groups,dc=example,dc=com",
+					Groups: []*schema.LDAPGroupRepositories{
+						{Group: "engineering", Repositories: []string{"^services/", "^tools/cli$"}},
+						{Group: "security", Repositories: []string{"^services/auth$", "^secrets$"}},
+					},
+				},
+			},
+		},
+		[]schema.AuthProviders{{Ldap: &schema.LDAPAuthProvider{
+			Type:         "ldap",
+			Url:          s.URL,
+			BindDN:       "cn=sourcegraph,ou=services,dc=example,dc=com",
+			BindPassword: "servicepw",
+		}}},
+		nil,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.cache = make(mockCache)
+	return p, s
+}
+
+func account(dn string) *extsvc.Account {
+	return &extsvc.Account{
+		UserID: 1,
+		AccountSpec: extsvc.AccountSpec{
+			ServiceType: "gitolite",
+			ServiceID:   gitoliteHost,
+			AccountID:   dn,
+		},
+	}
+}
+
+func TestProvider_FetchAccount(t *testing.T) {
+	p, s := newTestProvider(t)
+	defer s.Close()
+
+	user := &types.User{ID: 1}
+	ldapAccount := &extsvc.Account{AccountSpec: extsvc.AccountSpec{
+		ServiceType: "ldap",
+		ServiceID:   s.URL,
+		AccountID:   "uid=Alice,ou=people,dc=example,dc=com",
+	}}
+	otherAccount := &extsvc.Account{AccountSpec: extsvc.AccountSpec{
+		ServiceType: "ldap",
+		ServiceID:   "ldaps://other.example.com",
+		AccountID:   "uid=alice,ou=people,dc=other,dc=com",
+	}}
+
+	got, err := p.FetchAccount(context.Background(), user, []*extsvc.Account{otherAccount, ldapAccount})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := account("uid=alice,ou=people,dc=example,dc=com"); !reflect.DeepEqual(got, want) {
+		t.Errorf("got account %+v, want %+v", got, want)
+	}
+
+	if got, err := p.FetchAccount(context.Background(), user, []*extsvc.Account{otherAccount}); err != nil || got != nil {
+		t.Errorf("got account %+v and error %v, want nil", got, err)
+	}
+}
+
+func TestProvider_RepoPerms(t *testing.T) {
+	p, s := newTestProvider(t)
+	defer s.Close()
+
+	repo := func(id int, name string) *types.Repo {
+		return &types.Repo{ID: api.RepoID(id), ExternalRepo: api.ExternalRepoSpec{
+			ID:          name,
+			ServiceType: "gitolite",
+			ServiceID:   gitoliteHost,
+		}}
+	}
+	repos := []*types.Repo{repo(1, "services/auth"), repo(2, "services/web"), repo(3, "tools/cli"), repo(4, "tools/cli2"), repo(5, "secrets")}
+
+	for _, tc := range []struct {
+		name    string
+		account *extsvc.Account
+		want    []api.RepoID
+	}{
+		{"no account", nil, nil},
+		{"engineering", account("uid=alice,ou=people,dc=example,dc=com"), []api.RepoID{1, 2, 3}},
+		{"engineering (member DN is not normalized)", account("uid=bob,ou=people,dc=example,dc=com"), []api.RepoID{1, 2, 3}},
+		{"security", account("uid=carol,ou=people,dc=example,dc=com"), []api.RepoID{1, 5}},
+		{"unconfigured group", account("uid=dave,ou=people,dc=example,dc=com"), nil},
+		{"filter injection", account("*"), nil},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			perms, err := p.RepoPerms(context.Background(), tc.account, repos)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []api.RepoID
+			for _, perm := range perms {
+				if perm.Perms != authz.Read {
+					t.Errorf("repo %d: got perms %v, want %v", perm.Repo.ID, perm.Perms, authz.Read)
+				}
+				got = append(got, perm.Repo.ID)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("got repos %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestProvider_FetchUserPerms(t *testing.T) {
+	p, s := newTestProvider(t)
+	defer s.Close()
+
+	mockListRepoNames = func(serviceID string) ([]string, error) {
+		if serviceID != gitoliteHost {
+			t.Errorf("got service ID %q, want %q", serviceID, gitoliteHost)
+		}
+		return []string{"secrets", "services/auth", "services/web", "tools/cli", "tools/cli2"}, nil
+	}
+	defer func() { mockListRepoNames = nil }()
+
+	got, err := p.FetchUserPerms(context.Background(), account("uid=carol,ou=people,dc=example,dc=com"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []extsvc.RepoID{"secrets", "services/auth"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestProvider_FetchRepoPerms(t *testing.T) {
+	p, s := newTestProvider(t)
+	defer s.Close()
+
+	for name, want := range map[string][]extsvc.AccountID{
+		"services/auth": {"uid=alice,ou=people,dc=example,dc=com", "uid=bob,ou=people,dc=example,dc=com", "uid=carol,ou=people,dc=example,dc=com"},
+		"secrets":       {"uid=carol,ou=people,dc=example,dc=com"},
+		"other":         {},
+	} {
+		got, err := p.FetchRepoPerms(context.Background(), &extsvc.Repository{
+			URI: "gitolite.example.com/" + name,
+			ExternalRepoSpec: api.ExternalRepoSpec{
+				ID:          name,
+				ServiceType: "gitolite",
+				ServiceID:   gitoliteHost,
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: got %v, want %v", name, got, want)
+		}
+	}
+}
+
+func TestNewAuthzProviders(t *testing.T) {
+	_, problems, _ := NewAuthzProviders(
+		&conf.Unified{},
+		[]*schema.GitoliteConnection{{
+			Host: gitoliteHost,
+			Authorization: &schema.GitoliteAuthorization{
+				Ldap: schema.GitoliteLDAPAuthorization{
+					Url:    "ldaps://ldap.example.com",
+					Groups: []*schema.LDAPGroupRepositories{{Group: "engineering", Repositories: []string{"("}}},
+				},
+			},
+		}},
+		nil,
+	)
+	if len(problems) != 1 {
+		t.Fatalf("got problems %q, want 1", problems)
+	}
+	for _, want := range []string{"Did not find LDAP authentication provider", "engineering"} {
+		if !strings.Contains(problems[0], want) {
+			t.Errorf("got problem %q, want it to contain %q", problems[0], want)
+		}
+	}
+}
+
+func TestNormalizeDN(t *testing.T) {
+	for dn, want := range map[string]string{
+		"uid=alice,ou=people,dc=example,dc=com":       "uid=alice,ou=people,dc=example,dc=com",
+		" UID = Alice , OU=People,DC=Example,DC=com ": "uid=alice,ou=people,dc=example,dc=com",
+		"cn=Alice Zhao+uid=alice,dc=example":          "cn=alice zhao+uid=alice,dc=example",
+		`cn=Zhao\, Alice\ ,dc=example`:                `cn=zhao\, alice\ ,dc=example`,
+		`cn=Zhao\2C Alice,dc=example`:                 `cn=zhao\, alice,dc=example`,
+		`cn=\ ,dc=example`:                            `cn=\ ,dc=example`,
+		"":                                            "",
+	} {
+		if got := normalizeDN(dn); got != want {
+			t.Errorf("normalizeDN(%q): got %q, want %q", dn, got, want)
+		}
+	}
+}
+
+type mockCache map[string]string
+
+func (m mockCache) Get(key string) ([]byte, bool) {
+	v, ok := m[key]
+	return []byte(v), ok
+}
+
+func (m mockCache) Set(key string, b []byte) {
+	m[key] = string(b)
+}
+
+func (m mockCache) Delete(key string) {
+	delete(m, key)
+}
@@ -0,0 +1,148 @@
+package resolvers
+
+import (
+	"context"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/authz"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+)
+
+// reasonGroupPermissions is the kind of the reason that a group of the user grants access to the
+// repository, which is a value of the GraphQL RepositoryAccessReasonKind enum. The other values are
+// the rules of the enforcement policy of the db package.
+const reasonGroupPermissions = "GROUP_PERMISSIONS"
+
+func (r *Resolver) ExplainRepositoryAccess(ctx context.Context, args *graphqlbackend.ExplainRepositoryAccessArgs) (graphqlbackend.RepositoryAccessExplanationResolver, error) {
+	// 🚨 SECURITY: Only site admins can query repository permissions.
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	userID, err := graphqlbackend.UnmarshalUserID(args.User)
+	if err != nil {
+		return nil, err
+	}
+	user, err := db.Users.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	repoID, err := graphqlbackend.UnmarshalRepositoryID(args.Repository)
+	if err != nil {
+		return nil, err
+	}
+	repo, err := db.Repos.Get(ctx, repoID)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.explainRepositoryAccess(ctx, user, repo)
+}
+
+// explainRepositoryAccess explains whether the user can read the repository, with the decisions
+// the enforcement policy of authzFilter of the db package makes for it.
+//
+// 🚨 SECURITY: It is the caller's responsibility to ensure the current authenticated user is a
+// site admin.
+func (r *Resolver) explainRepositoryAccess(ctx context.Context, user *types.User, repo *types.Repo) (*repositoryAccessExplanationResolver, error) {
+	decisions, err := db.ExplainRepoAccess(ctx, user, repo, authz.Read)
+	if err != nil {
+		return nil, err
+	}
+
+	e := &repositoryAccessExplanationResolver{}
+	for _, d := range decisions {
+		if d.Rule != db.AuthzRuleExplicitPermissions && d.Rule != db.AuthzRuleSyncedPermissions {
+			e.add(string(d.Rule), d.Allowed, d.Provider, nil, d.Description)
+			continue
+		}
+
+		// The stored permissions include those granted through the groups of the user, so tell
+		// them apart and report when the permissions of the user were last updated.
+		p := &authz.UserPermissions{
+			UserID: user.ID,
+			Perm:   authz.Read,
+			Type:   authz.PermRepos,
+		}
+		err := r.store.LoadUserPermissions(ctx, p)
+		if err != nil && err != authz.ErrPermsNotFound {
+			return nil, err
+		}
+		var updatedAt *time.Time
+		if err == nil {
+			updatedAt = &p.UpdatedAt
+		}
+
+		if d.Allowed && (err != nil || !p.IDs.Contains(uint32(repo.ID))) {
+			e.add(reasonGroupPermissions, true, "", nil,
+				"A group of the user on an authentication provider grants access to the repository.")
+			continue
+		}
+		e.add(string(d.Rule), d.Allowed, d.Provider, updatedAt, d.Description)
+	}
+	return e, nil
+}
+
+var _ graphqlbackend.RepositoryAccessExplanationResolver = &repositoryAccessExplanationResolver{}
+
+// repositoryAccessExplanationResolver resolves the explanation of whether a user can read a
+// repository. The last reason is the one that decides it.
+type repositoryAccessExplanationResolver struct {
+	reasons []graphqlbackend.RepositoryAccessReasonResolver
+}
+
+func (r *repositoryAccessExplanationResolver) add(kind string, grantsAccess bool, provider string, updatedAt *time.Time, description string) *repositoryAccessExplanationResolver {
+	r.reasons = append(r.reasons, &repositoryAccessReasonResolver{
+		kind:         kind,
+		grantsAccess: grantsAccess,
+		provider:     provider,
+		updatedAt:    updatedAt,
+		description:  description,
+	})
+	return r
+}
+
+func (r *repositoryAccessExplanationResolver) HasAccess() bool {
+	return len(r.reasons) > 0 && r.reasons[len(r.reasons)-1].GrantsAccess()
+}
+
+func (r *repositoryAccessExplanationResolver) Reasons() []graphqlbackend.RepositoryAccessReasonResolver {
+	return r.reasons
+}
+
+var _ graphqlbackend.RepositoryAccessReasonResolver = &repositoryAccessReasonResolver{}
+
+type repositoryAccessReasonResolver struct {
+	kind         string
+	grantsAccess bool
+	provider     string
+	updatedAt    *time.Time
+	description  string
+}
+
+func (r *repositoryAccessReasonResolver) Kind() string {
+	return r.kind
+}
+
+func (r *repositoryAccessReasonResolver) GrantsAccess() bool {
+	return r.grantsAccess
+}
+
+func (r *repositoryAccessReasonResolver) Provider() *string {
+	if r.provider == "" {
+		return nil
+	}
+	return &r.provider
+}
+
+func (r *repositoryAccessReasonResolver) UpdatedAt() *graphqlbackend.DateTime {
+	return graphqlbackend.DateTimeOrNil(r.updatedAt)
+}
+
+func (r *repositoryAccessReasonResolver) Description() string {
+	return r.description
+}
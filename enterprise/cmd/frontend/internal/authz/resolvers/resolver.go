@@ -15,9 +15,12 @@ import (
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
 	edb "github.com/sourcegraph/sourcegraph/enterprise/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/db/dbutil"
 	"github.com/sourcegraph/sourcegraph/internal/errcode"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/repoupdater"
+	"github.com/sourcegraph/sourcegraph/internal/repoupdater/protocol"
 )
 
 type Resolver struct {
@@ -36,46 +39,122 @@ func (r *Resolver) SetRepositoryPermissionsForUsers(ctx context.Context, args *g
 		return nil, err
 	}
 
-	repoID, err := graphqlbackend.UnmarshalRepositoryID(args.Repository)
-	if err != nil {
+	return r.setRepositoryPermissions(ctx, []*graphqlbackend.RepositoryPermissionsInput{{
+		Repository: args.Repository,
+		BindIDs:    args.BindIDs,
+	}})
+}
+
+func (r *Resolver) SetRepositoryPermissionsForBulkOperation(ctx context.Context, args *graphqlbackend.RepoPermsBulkArgs) (*graphqlbackend.EmptyResponse, error) {
+	// 🚨 SECURITY: Only site admins can mutate repository permissions.
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
 		return nil, err
 	}
-	// Make sure the repo ID is valid.
-	if _, err = db.Repos.Get(ctx, repoID); err != nil {
-		return nil, err
+
+	return r.setRepositoryPermissions(ctx, args.Permissions)
+}
+
+// setRepositoryPermissions replaces the permissions of every given repository with the users
+// identified by its bind IDs, in a single transaction. Bind IDs that do not belong to any user yet
+// are saved as pending permissions.
+func (r *Resolver) setRepositoryPermissions(ctx context.Context, inputs []*graphqlbackend.RepositoryPermissionsInput) (_ *graphqlbackend.EmptyResponse, err error) {
+	type repoPerms struct {
+		repoID  api.RepoID
+		bindIDs []string
 	}
+	ps := make([]repoPerms, 0, len(inputs))
+	seen := make(map[api.RepoID]struct{}, len(inputs))
+	var allBindIDs []string
+	for _, input := range inputs {
+		repoID, err := graphqlbackend.UnmarshalRepositoryID(input.Repository)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := seen[repoID]; ok {
+			return nil, fmt.Errorf("duplicate permissions for repository %q", input.Repository)
+		}
+		seen[repoID] = struct{}{}
 
-	// Filter out bind IDs that only contains whitespaces.
-	bindIDs := args.BindIDs[:0]
-	for i := range args.BindIDs {
-		args.BindIDs[i] = strings.TrimSpace(args.BindIDs[i])
-		if len(args.BindIDs[i]) == 0 {
-			continue
+		// Make sure the repo ID is valid.
+		if _, err = db.Repos.Get(ctx, repoID); err != nil {
+			return nil, err
 		}
-		bindIDs = append(bindIDs, args.BindIDs[i])
+
+		// Filter out bind IDs that only contains whitespaces.
+		bindIDs := make([]string, 0, len(input.BindIDs))
+		for _, bindID := range input.BindIDs {
+			if bindID = strings.TrimSpace(bindID); bindID != "" {
+				bindIDs = append(bindIDs, bindID)
+			}
+		}
+		ps = append(ps, repoPerms{repoID: repoID, bindIDs: bindIDs})
+		allBindIDs = append(allBindIDs, bindIDs...)
 	}
 
-	bindIDSet := make(map[string]struct{})
-	for i := range bindIDs {
-		bindIDSet[bindIDs[i]] = struct{}{}
+	userIDs, err := userIDsByBindIDs(ctx, allBindIDs)
+	if err != nil {
+		return nil, err
 	}
 
-	p := &authz.RepoPermissions{
-		RepoID:  int32(repoID),
-		Perm:    authz.Read, // Note: We currently only support read for repository permissions.
-		UserIDs: roaring.NewBitmap(),
+	txs, err := r.store.Transact(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "start transaction")
 	}
+	defer txs.Done(&err)
+
+	for _, rp := range ps {
+		p := &authz.RepoPermissions{
+			RepoID:  int32(rp.repoID),
+			Perm:    authz.Read, // Note: We currently only support read for repository permissions.
+			UserIDs: roaring.NewBitmap(),
+		}
+		pendingBindIDSet := make(map[string]struct{})
+		for _, bindID := range rp.bindIDs {
+			if userID, ok := userIDs[bindID]; ok {
+				p.UserIDs.Add(uint32(userID))
+			} else {
+				pendingBindIDSet[bindID] = struct{}{}
+			}
+		}
+
+		pendingBindIDs := make([]string, 0, len(pendingBindIDSet))
+		for id := range pendingBindIDSet {
+			pendingBindIDs = append(pendingBindIDs, id)
+		}
+		accounts := &extsvc.Accounts{
+			ServiceType: authz.SourcegraphServiceType,
+			ServiceID:   authz.SourcegraphServiceID,
+			AccountIDs:  pendingBindIDs,
+		}
+
+		if err = txs.SetRepoPermissions(ctx, p); err != nil {
+			return nil, errors.Wrap(err, "set repository permissions")
+		} else if err = txs.SetRepoPendingPermissions(ctx, accounts, p); err != nil {
+			return nil, errors.Wrap(err, "set repository pending permissions")
+		}
+	}
+
+	return &graphqlbackend.EmptyResponse{}, nil
+}
+
+// userIDsByBindIDs returns the IDs of the existing users identified by the bind IDs, which are
+// usernames or verified email addresses according to site configuration.
+func userIDsByBindIDs(ctx context.Context, bindIDs []string) (map[string]int32, error) {
+	userIDs := make(map[string]int32, len(bindIDs))
+	if len(bindIDs) == 0 {
+		return userIDs, nil
+	}
+
 	cfg := globals.PermissionsUserMapping()
 	switch cfg.BindID {
 	case "email":
+		// 🚨 SECURITY: It is critical to only bind permissions to verified emails.
 		emails, err := db.UserEmails.GetVerifiedEmails(ctx, bindIDs...)
 		if err != nil {
 			return nil, err
 		}
-
 		for i := range emails {
-			p.UserIDs.Add(uint32(emails[i].UserID))
-			delete(bindIDSet, emails[i].Email)
+			userIDs[emails[i].Email] = emails[i].UserID
 		}
 
 	case "username":
@@ -83,40 +162,14 @@ func (r *Resolver) SetRepositoryPermissionsForUsers(ctx context.Context, args *g
 		if err != nil {
 			return nil, err
 		}
-
 		for i := range users {
-			p.UserIDs.Add(uint32(users[i].ID))
-			delete(bindIDSet, users[i].Username)
+			userIDs[users[i].Username] = users[i].ID
 		}
 
 	default:
 		return nil, fmt.Errorf("unrecognized user mapping bind ID type %q", cfg.BindID)
 	}
-
-	pendingBindIDs := make([]string, 0, len(bindIDSet))
-	for id := range bindIDSet {
-		pendingBindIDs = append(pendingBindIDs, id)
-	}
-
-	txs, err := r.store.Transact(ctx)
-	if err != nil {
-		return nil, errors.Wrap(err, "start transaction")
-	}
-	defer txs.Done(&err)
-
-	accounts := &extsvc.Accounts{
-		ServiceType: authz.SourcegraphServiceType,
-		ServiceID:   authz.SourcegraphServiceID,
-		AccountIDs:  pendingBindIDs,
-	}
-
-	if err = txs.SetRepoPermissions(ctx, p); err != nil {
-		return nil, errors.Wrap(err, "set repository permissions")
-	} else if err = txs.SetRepoPendingPermissions(ctx, accounts, p); err != nil {
-		return nil, errors.Wrap(err, "set repository pending permissions")
-	}
-
-	return &graphqlbackend.EmptyResponse{}, nil
+	return userIDs, nil
 }
 
 func (r *Resolver) AuthorizedUserRepositories(ctx context.Context, args *graphqlbackend.AuthorizedRepoArgs) (graphqlbackend.RepositoryConnectionResolver, error) {
@@ -172,6 +225,18 @@ func (r *Resolver) AuthorizedUserRepositories(ctx context.Context, args *graphql
 		ids = roaring.NewBitmap()
 	}
 
+	// Repositories can also be authorized through the groups of the user on authentication
+	// providers.
+	if user != nil {
+		groupRepoIDs, err := r.store.GroupAuthorizedRepoIDs(ctx, user.ID, nil)
+		if err != nil {
+			return nil, err
+		}
+		for _, id := range groupRepoIDs {
+			ids.Add(uint32(id))
+		}
+	}
+
 	return &repositoryConnectionResolver{
 		ids:   ids,
 		first: args.First,
@@ -216,9 +281,106 @@ func (r *Resolver) AuthorizedUsers(ctx context.Context, args *graphqlbackend.Rep
 		p.UserIDs = roaring.NewBitmap()
 	}
 
+	// Users can also be authorized through their groups on authentication providers.
+	groupUserIDs, err := r.store.GroupAuthorizedUserIDs(ctx, repoID)
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range groupUserIDs {
+		p.UserIDs.Add(uint32(id))
+	}
+
 	return &userConnectionResolver{
 		ids:   p.UserIDs,
 		first: args.First,
 		after: args.After,
 	}, nil
 }
+
+func (r *Resolver) ScheduleUserPermissionsSync(ctx context.Context, args *graphqlbackend.UserPermissionsSyncArgs) (*graphqlbackend.EmptyResponse, error) {
+	// 🚨 SECURITY: Only site admins can schedule permissions syncs.
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	if !globals.PermissionsBackgroundSync().Enabled {
+		return nil, errors.New(`permissions can only be synced when "permissions.backgroundSync" is enabled`)
+	}
+
+	userID, err := graphqlbackend.UnmarshalUserID(args.User)
+	if err != nil {
+		return nil, err
+	}
+	// Make sure the user ID is valid.
+	if _, err = db.Users.GetByID(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	err = repoupdater.DefaultClient.SchedulePermsSync(ctx, protocol.PermsSyncRequest{UserIDs: []int32{userID}})
+	if err != nil {
+		return nil, err
+	}
+	return &graphqlbackend.EmptyResponse{}, nil
+}
+
+func (r *Resolver) ScheduleRepositoryPermissionsSync(ctx context.Context, args *graphqlbackend.RepositoryPermissionsSyncArgs) (*graphqlbackend.EmptyResponse, error) {
+	// 🚨 SECURITY: Only site admins can schedule permissions syncs.
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	if !globals.PermissionsBackgroundSync().Enabled {
+		return nil, errors.New(`permissions can only be synced when "permissions.backgroundSync" is enabled`)
+	}
+
+	repoID, err := graphqlbackend.UnmarshalRepositoryID(args.Repository)
+	if err != nil {
+		return nil, err
+	}
+	// Make sure the repo ID is valid.
+	if _, err = db.Repos.Get(ctx, repoID); err != nil {
+		return nil, err
+	}
+
+	err = repoupdater.DefaultClient.SchedulePermsSync(ctx, protocol.PermsSyncRequest{RepoIDs: []api.RepoID{repoID}})
+	if err != nil {
+		return nil, err
+	}
+	return &graphqlbackend.EmptyResponse{}, nil
+}
+
+func (r *Resolver) UserPermissionsSyncHistory(ctx context.Context, args *graphqlbackend.UserPermissionsSyncHistoryArgs) ([]graphqlbackend.PermissionsSyncHistoryEntryResolver, error) {
+	// 🚨 SECURITY: Only site admins can query permissions sync history.
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	userID, err := graphqlbackend.UnmarshalUserID(args.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	history, err := r.store.ListSyncHistory(ctx, userID, 0, int(args.First))
+	if err != nil {
+		return nil, err
+	}
+	return toPermissionsSyncHistoryEntryResolvers(history), nil
+}
+
+func (r *Resolver) RepositoryPermissionsSyncHistory(ctx context.Context, args *graphqlbackend.RepoPermissionsSyncHistoryArgs) ([]graphqlbackend.PermissionsSyncHistoryEntryResolver, error) {
+	// 🚨 SECURITY: Only site admins can query permissions sync history.
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	repoID, err := graphqlbackend.UnmarshalRepositoryID(args.RepositoryID)
+	if err != nil {
+		return nil, err
+	}
+
+	history, err := r.store.ListSyncHistory(ctx, 0, repoID, int(args.First))
+	if err != nil {
+		return nil, err
+	}
+	return toPermissionsSyncHistoryEntryResolvers(history), nil
+}
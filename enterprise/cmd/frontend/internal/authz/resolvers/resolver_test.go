@@ -22,6 +22,8 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/actor"
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/repoupdater"
+	"github.com/sourcegraph/sourcegraph/internal/repoupdater/protocol"
 	"github.com/sourcegraph/sourcegraph/schema"
 )
 
@@ -193,6 +195,111 @@ func TestResolver_SetRepositoryPermissionsForUsers(t *testing.T) {
 	}
 }
 
+func TestResolver_SetRepositoryPermissionsForBulkOperation(t *testing.T) {
+	t.Run("authenticated as non-admin", func(t *testing.T) {
+		db.Mocks.Users.GetByCurrentAuthUser = func(context.Context) (*types.User, error) {
+			return &types.User{}, nil
+		}
+		defer func() {
+			db.Mocks.Users.GetByCurrentAuthUser = nil
+		}()
+
+		ctx := actor.WithActor(context.Background(), &actor.Actor{UID: 1})
+		result, err := (&Resolver{}).SetRepositoryPermissionsForBulkOperation(ctx, &graphqlbackend.RepoPermsBulkArgs{})
+		if want := backend.ErrMustBeSiteAdmin; err != want {
+			t.Errorf("err: want %q but got %v", want, err)
+		}
+		if result != nil {
+			t.Errorf("result: want nil but got %v", result)
+		}
+	})
+
+	before := globals.PermissionsUserMapping()
+	globals.SetPermissionsUserMapping(&schema.PermissionsUserMapping{BindID: "email"})
+	db.Mocks.Users.GetByCurrentAuthUser = func(context.Context) (*types.User, error) {
+		return &types.User{SiteAdmin: true}, nil
+	}
+	db.Mocks.UserEmails.GetVerifiedEmails = func(_ context.Context, emails ...string) ([]*db.UserEmail, error) {
+		verified := map[string]int32{"alice@example.com": 1, "carol@example.com": 3}
+		var userEmails []*db.UserEmail
+		for _, email := range emails {
+			if userID, ok := verified[email]; ok {
+				userEmails = append(userEmails, &db.UserEmail{UserID: userID, Email: email})
+			}
+		}
+		return userEmails, nil
+	}
+	db.Mocks.Repos.Get = func(_ context.Context, id api.RepoID) (*types.Repo, error) {
+		return &types.Repo{ID: id}, nil
+	}
+	edb.Mocks.Perms.Transact = func(_ context.Context) (*edb.PermsStore, error) {
+		return &edb.PermsStore{}, nil
+	}
+	userIDs := map[int32][]uint32{}
+	edb.Mocks.Perms.SetRepoPermissions = func(_ context.Context, p *authz.RepoPermissions) error {
+		userIDs[p.RepoID] = p.UserIDs.ToArray()
+		return nil
+	}
+	pendingAccountIDs := map[int32][]string{}
+	edb.Mocks.Perms.SetRepoPendingPermissions = func(_ context.Context, accounts *extsvc.Accounts, p *authz.RepoPermissions) error {
+		if accounts.ServiceType != authz.SourcegraphServiceType || accounts.ServiceID != authz.SourcegraphServiceID {
+			return fmt.Errorf("unexpected accounts %+v", accounts)
+		}
+		pendingAccountIDs[p.RepoID] = accounts.AccountIDs
+		return nil
+	}
+	defer func() {
+		globals.SetPermissionsUserMapping(before)
+		db.Mocks.UserEmails = db.MockUserEmails{}
+		db.Mocks.Users = db.MockUsers{}
+		db.Mocks.Repos = db.MockRepos{}
+		edb.Mocks.Perms = edb.MockPerms{}
+	}()
+
+	gqltesting.RunTests(t, []*gqltesting.Test{
+		{
+			Schema: mustParseGraphQLSchema(t, nil),
+			Query: `
+				mutation {
+					setRepositoryPermissionsForBulkOperation(permissions: [
+						{repository: "UmVwb3NpdG9yeTox", bindIDs: ["alice@example.com", "bob@example.com"]},
+						{repository: "UmVwb3NpdG9yeToy", bindIDs: ["carol@example.com", " alice@example.com ", ""]}
+					]) {
+						alwaysNil
+					}
+				}
+			`,
+			ExpectedResult: `
+				{
+					"setRepositoryPermissionsForBulkOperation": {
+						"alwaysNil": null
+					}
+				}
+			`,
+		},
+	})
+
+	if diff := cmp.Diff(map[int32][]uint32{1: {1}, 2: {1, 3}}, userIDs); diff != "" {
+		t.Errorf("userIDs: %v", diff)
+	}
+	if diff := cmp.Diff(map[int32][]string{1: {"bob@example.com"}, 2: {}}, pendingAccountIDs); diff != "" {
+		t.Errorf("pendingAccountIDs: %v", diff)
+	}
+
+	t.Run("duplicate repository", func(t *testing.T) {
+		ctx := actor.WithActor(context.Background(), &actor.Actor{UID: 1})
+		_, err := (&Resolver{}).SetRepositoryPermissionsForBulkOperation(ctx, &graphqlbackend.RepoPermsBulkArgs{
+			Permissions: []*graphqlbackend.RepositoryPermissionsInput{
+				{Repository: "UmVwb3NpdG9yeTox"},
+				{Repository: "UmVwb3NpdG9yeTox"},
+			},
+		})
+		if want := `duplicate permissions for repository "UmVwb3NpdG9yeTox"`; fmt.Sprint(err) != want {
+			t.Errorf("err: want %q but got %v", want, err)
+		}
+	})
+}
+
 func TestResolver_AuthorizedUserRepositories(t *testing.T) {
 	t.Run("authenticated as non-admin", func(t *testing.T) {
 		db.Mocks.Users.GetByCurrentAuthUser = func(context.Context) (*types.User, error) {
@@ -244,6 +351,12 @@ func TestResolver_AuthorizedUserRepositories(t *testing.T) {
 		p.IDs.Add(2)
 		return nil
 	}
+	edb.Mocks.Perms.GroupAuthorizedRepoIDs = func(_ context.Context, userID int32, repoIDs []api.RepoID) ([]api.RepoID, error) {
+		if userID != 1 || repoIDs != nil {
+			return nil, fmt.Errorf("unexpected arguments: %d, %v", userID, repoIDs)
+		}
+		return []api.RepoID{3}, nil
+	}
 	defer func() {
 		db.Mocks.Users = db.MockUsers{}
 		edb.Mocks.Perms = edb.MockPerms{}
@@ -273,7 +386,8 @@ func TestResolver_AuthorizedUserRepositories(t *testing.T) {
 				{
 					"authorizedUserRepositories": {
 						"nodes": [
-							{"id":"UmVwb3NpdG9yeTox"}
+							{"id":"UmVwb3NpdG9yeTox"},
+							{"id":"UmVwb3NpdG9yeToz"}
 						]
     				}
 				}
@@ -301,7 +415,8 @@ func TestResolver_AuthorizedUserRepositories(t *testing.T) {
 				{
 					"authorizedUserRepositories": {
 						"nodes": [
-							{"id":"UmVwb3NpdG9yeTox"}
+							{"id":"UmVwb3NpdG9yeTox"},
+							{"id":"UmVwb3NpdG9yeToz"}
 						]
     				}
 				}
@@ -476,6 +591,9 @@ func TestResolver_AuthorizedUsers(t *testing.T) {
 		p.UserIDs.Add(1)
 		return nil
 	}
+	edb.Mocks.Perms.GroupAuthorizedUserIDs = func(_ context.Context, repoID api.RepoID) ([]int32, error) {
+		return []int32{2}, nil
+	}
 	defer func() {
 		db.Mocks.Users = db.MockUsers{}
 		db.Mocks.Repos = db.MockRepos{}
@@ -507,7 +625,8 @@ func TestResolver_AuthorizedUsers(t *testing.T) {
 					"repository": {
 						"authorizedUsers": {
 							"nodes":[
-								{"id":"VXNlcjox"}
+								{"id":"VXNlcjox"},
+								{"id":"VXNlcjoy"}
 							]
 						}
     				}
@@ -523,3 +642,408 @@ func TestResolver_AuthorizedUsers(t *testing.T) {
 		})
 	}
 }
+
+func TestResolver_ScheduleUserPermissionsSync(t *testing.T) {
+	t.Run("authenticated as non-admin", func(t *testing.T) {
+		db.Mocks.Users.GetByCurrentAuthUser = func(context.Context) (*types.User, error) {
+			return &types.User{}, nil
+		}
+		defer func() {
+			db.Mocks.Users.GetByCurrentAuthUser = nil
+		}()
+
+		ctx := actor.WithActor(context.Background(), &actor.Actor{UID: 1})
+		result, err := (&Resolver{}).ScheduleUserPermissionsSync(ctx, &graphqlbackend.UserPermissionsSyncArgs{})
+		if want := backend.ErrMustBeSiteAdmin; err != want {
+			t.Errorf("err: want %q but got %v", want, err)
+		}
+		if result != nil {
+			t.Errorf("result: want nil but got %v", result)
+		}
+	})
+
+	db.Mocks.Users.GetByCurrentAuthUser = func(context.Context) (*types.User, error) {
+		return &types.User{SiteAdmin: true}, nil
+	}
+	db.Mocks.Users.GetByID = func(_ context.Context, id int32) (*types.User, error) {
+		return &types.User{ID: id}, nil
+	}
+	var calledWith protocol.PermsSyncRequest
+	repoupdater.MockSchedulePermsSync = func(_ context.Context, args protocol.PermsSyncRequest) error {
+		calledWith = args
+		return nil
+	}
+	before := globals.PermissionsBackgroundSync()
+	defer func() {
+		globals.SetPermissionsBackgroundSync(before)
+		db.Mocks.Users = db.MockUsers{}
+		repoupdater.MockSchedulePermsSync = nil
+	}()
+
+	ctx := actor.WithActor(context.Background(), &actor.Actor{UID: 1})
+	args := &graphqlbackend.UserPermissionsSyncArgs{User: graphqlbackend.MarshalUserID(2)}
+
+	globals.SetPermissionsBackgroundSync(&schema.PermissionsBackgroundSync{Enabled: false})
+	if _, err := (&Resolver{}).ScheduleUserPermissionsSync(ctx, args); err == nil {
+		t.Fatal("background sync disabled: want error but got nil")
+	}
+
+	globals.SetPermissionsBackgroundSync(&schema.PermissionsBackgroundSync{Enabled: true})
+	if _, err := (&Resolver{}).ScheduleUserPermissionsSync(ctx, args); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(protocol.PermsSyncRequest{UserIDs: []int32{2}}, calledWith); diff != "" {
+		t.Fatal(diff)
+	}
+}
+
+func TestResolver_ScheduleRepositoryPermissionsSync(t *testing.T) {
+	t.Run("authenticated as non-admin", func(t *testing.T) {
+		db.Mocks.Users.GetByCurrentAuthUser = func(context.Context) (*types.User, error) {
+			return &types.User{}, nil
+		}
+		defer func() {
+			db.Mocks.Users.GetByCurrentAuthUser = nil
+		}()
+
+		ctx := actor.WithActor(context.Background(), &actor.Actor{UID: 1})
+		result, err := (&Resolver{}).ScheduleRepositoryPermissionsSync(ctx, &graphqlbackend.RepositoryPermissionsSyncArgs{})
+		if want := backend.ErrMustBeSiteAdmin; err != want {
+			t.Errorf("err: want %q but got %v", want, err)
+		}
+		if result != nil {
+			t.Errorf("result: want nil but got %v", result)
+		}
+	})
+
+	db.Mocks.Users.GetByCurrentAuthUser = func(context.Context) (*types.User, error) {
+		return &types.User{SiteAdmin: true}, nil
+	}
+	db.Mocks.Repos.Get = func(_ context.Context, id api.RepoID) (*types.Repo, error) {
+		return &types.Repo{ID: id}, nil
+	}
+	var calledWith protocol.PermsSyncRequest
+	repoupdater.MockSchedulePermsSync = func(_ context.Context, args protocol.PermsSyncRequest) error {
+		calledWith = args
+		return nil
+	}
+	before := globals.PermissionsBackgroundSync()
+	globals.SetPermissionsBackgroundSync(&schema.PermissionsBackgroundSync{Enabled: true})
+	defer func() {
+		globals.SetPermissionsBackgroundSync(before)
+		db.Mocks.Users = db.MockUsers{}
+		db.Mocks.Repos = db.MockRepos{}
+		repoupdater.MockSchedulePermsSync = nil
+	}()
+
+	ctx := actor.WithActor(context.Background(), &actor.Actor{UID: 1})
+	_, err := (&Resolver{}).ScheduleRepositoryPermissionsSync(ctx, &graphqlbackend.RepositoryPermissionsSyncArgs{
+		Repository: graphqlbackend.MarshalRepositoryID(3),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(protocol.PermsSyncRequest{RepoIDs: []api.RepoID{3}}, calledWith); diff != "" {
+		t.Fatal(diff)
+	}
+}
+
+type fakeProvider struct {
+	serviceType string
+	serviceID   string
+	readable    map[api.RepoID]bool
+}
+
+func (p *fakeProvider) RepoPerms(_ context.Context, _ *extsvc.Account, repos []*types.Repo) ([]authz.RepoPerms, error) {
+	var perms []authz.RepoPerms
+	for _, r := range repos {
+		if p.readable[r.ID] {
+			perms = append(perms, authz.RepoPerms{Repo: r, Perms: authz.Read})
+		}
+	}
+	return perms, nil
+}
+
+func (p *fakeProvider) FetchAccount(context.Context, *types.User, []*extsvc.Account) (*extsvc.Account, error) {
+	return nil, nil
+}
+
+func (p *fakeProvider) FetchUserPerms(context.Context, *extsvc.Account) ([]extsvc.RepoID, error) {
+	return nil, nil
+}
+
+func (p *fakeProvider) FetchRepoPerms(context.Context, *extsvc.Repository) ([]extsvc.AccountID, error) {
+	return nil, nil
+}
+
+func (p *fakeProvider) ServiceType() string { return p.serviceType }
+func (p *fakeProvider) ServiceID() string   { return p.serviceID }
+func (p *fakeProvider) Validate() []string  { return nil }
+
+func TestResolver_ExplainRepositoryAccess(t *testing.T) {
+	t.Run("authenticated as non-admin", func(t *testing.T) {
+		db.Mocks.Users.GetByCurrentAuthUser = func(context.Context) (*types.User, error) {
+			return &types.User{}, nil
+		}
+		defer func() {
+			db.Mocks.Users.GetByCurrentAuthUser = nil
+		}()
+
+		ctx := actor.WithActor(context.Background(), &actor.Actor{UID: 1})
+		result, err := (&Resolver{}).ExplainRepositoryAccess(ctx, &graphqlbackend.ExplainRepositoryAccessArgs{})
+		if want := backend.ErrMustBeSiteAdmin; err != want {
+			t.Errorf("err: want %q but got %v", want, err)
+		}
+		if result != nil {
+			t.Errorf("result: want nil but got %v", result)
+		}
+	})
+
+	github := &fakeProvider{
+		serviceType: "github",
+		serviceID:   "https://github.com/",
+		readable:    map[api.RepoID]bool{1: true},
+	}
+	githubRepo := func(id api.RepoID, private bool) *types.Repo {
+		return &types.Repo{
+			ID:           id,
+			ExternalRepo: api.ExternalRepoSpec{ServiceType: "github", ServiceID: "https://github.com/"},
+			Private:      private,
+		}
+	}
+	gitoliteRepo := &types.Repo{
+		ID:           10,
+		ExternalRepo: api.ExternalRepoSpec{ServiceType: "gitolite", ServiceID: "git@gitolite.example.com"},
+	}
+
+	edb.Mocks.Perms.LoadUserPermissions = func(_ context.Context, p *authz.UserPermissions) error {
+		p.IDs = roaring.BitmapOf(2, 10)
+		p.UpdatedAt = clock()
+		return nil
+	}
+	edb.Mocks.Perms.GroupAuthorizedRepoIDs = func(_ context.Context, _ int32, repoIDs []api.RepoID) ([]api.RepoID, error) {
+		for _, id := range repoIDs {
+			if id == 3 {
+				return []api.RepoID{3}, nil
+			}
+		}
+		return nil, nil
+	}
+	db.Mocks.ExternalAccounts.List = func(db.ExternalAccountsListOptions) ([]*extsvc.Account, error) {
+		return nil, nil
+	}
+	beforeAuthz := db.Authz
+	db.Authz = edb.NewAuthzStore(nil, clock)
+	beforeUserMapping := globals.PermissionsUserMapping()
+	beforeBackgroundSync := globals.PermissionsBackgroundSync()
+	defer func() {
+		db.Authz = beforeAuthz
+		globals.SetPermissionsUserMapping(beforeUserMapping)
+		globals.SetPermissionsBackgroundSync(beforeBackgroundSync)
+		authz.SetProviders(true, nil)
+		db.Mocks.ExternalAccounts = db.MockExternalAccounts{}
+		edb.Mocks.Perms = edb.MockPerms{}
+	}()
+
+	tests := []struct {
+		name           string
+		userMapping    bool
+		backgroundSync bool
+		allowByDefault bool
+		providers      []authz.Provider
+		user           *types.User
+		repo           *types.Repo
+		wantKinds      []string
+		wantAccess     bool
+	}{
+		{
+			name:       "site admin",
+			user:       &types.User{ID: 1, SiteAdmin: true},
+			repo:       githubRepo(1, true),
+			wantKinds:  []string{"SITE_ADMIN"},
+			wantAccess: true,
+		},
+		{
+			name:           "allowed by default",
+			allowByDefault: true,
+			user:           &types.User{ID: 1},
+			repo:           githubRepo(1, true),
+			wantKinds:      []string{"ALLOWED_BY_DEFAULT"},
+			wantAccess:     true,
+		},
+		{
+			name:        "explicit permissions",
+			userMapping: true,
+			providers:   []authz.Provider{github},
+			user:        &types.User{ID: 1},
+			repo:        gitoliteRepo,
+			wantKinds:   []string{"NO_PROVIDER", "EXPLICIT_PERMISSIONS"},
+			wantAccess:  true,
+		},
+		{
+			name:           "background sync, public repository",
+			backgroundSync: true,
+			providers:      []authz.Provider{github},
+			user:           &types.User{ID: 1},
+			repo:           githubRepo(1, false),
+			wantKinds:      []string{"PUBLIC_REPOSITORY"},
+			wantAccess:     true,
+		},
+		{
+			name:           "background sync, synced permissions",
+			backgroundSync: true,
+			providers:      []authz.Provider{github},
+			user:           &types.User{ID: 1},
+			repo:           githubRepo(2, true),
+			wantKinds:      []string{"NO_EXTERNAL_ACCOUNT", "SYNCED_PERMISSIONS"},
+			wantAccess:     true,
+		},
+		{
+			name:           "background sync, group permissions",
+			backgroundSync: true,
+			providers:      []authz.Provider{github},
+			user:           &types.User{ID: 1},
+			repo:           githubRepo(3, true),
+			wantKinds:      []string{"NO_EXTERNAL_ACCOUNT", "GROUP_PERMISSIONS"},
+			wantAccess:     true,
+		},
+		{
+			name:           "background sync, no permissions",
+			backgroundSync: true,
+			providers:      []authz.Provider{github},
+			user:           &types.User{ID: 1},
+			repo:           githubRepo(4, true),
+			wantKinds:      []string{"NO_EXTERNAL_ACCOUNT", "SYNCED_PERMISSIONS"},
+			wantAccess:     false,
+		},
+		{
+			name:       "code host permissions",
+			providers:  []authz.Provider{github},
+			user:       &types.User{ID: 1},
+			repo:       githubRepo(1, true),
+			wantKinds:  []string{"NO_EXTERNAL_ACCOUNT", "CODE_HOST_PERMISSIONS"},
+			wantAccess: true,
+		},
+		{
+			name:       "no provider for code host",
+			providers:  []authz.Provider{github},
+			user:       &types.User{ID: 1},
+			repo:       gitoliteRepo,
+			wantKinds:  []string{"NO_PROVIDER"},
+			wantAccess: false,
+		},
+		{
+			name:           "no external repo spec",
+			allowByDefault: true,
+			providers:      []authz.Provider{github},
+			user:           &types.User{ID: 1},
+			repo:           &types.Repo{ID: 11},
+			wantKinds:      []string{"NO_PROVIDER"},
+			wantAccess:     false,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			globals.SetPermissionsUserMapping(&schema.PermissionsUserMapping{Enabled: test.userMapping, BindID: "email"})
+			globals.SetPermissionsBackgroundSync(&schema.PermissionsBackgroundSync{Enabled: test.backgroundSync})
+			authz.SetProviders(test.allowByDefault, test.providers)
+
+			e, err := (&Resolver{store: edb.NewPermsStore(nil, clock)}).explainRepositoryAccess(context.Background(), test.user, test.repo)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var kinds []string
+			for _, r := range e.Reasons() {
+				kinds = append(kinds, r.Kind())
+			}
+			if diff := cmp.Diff(test.wantKinds, kinds); diff != "" {
+				t.Fatalf("kinds: %s", diff)
+			}
+			if e.HasAccess() != test.wantAccess {
+				t.Fatalf("hasAccess: want %v but got %v", test.wantAccess, e.HasAccess())
+			}
+		})
+	}
+}
+
+func TestResolver_PermissionsSyncHistory(t *testing.T) {
+	db.Mocks.Users.GetByCurrentAuthUser = func(context.Context) (*types.User, error) {
+		return &types.User{SiteAdmin: true}, nil
+	}
+	db.Mocks.Users.GetByID = func(_ context.Context, id int32) (*types.User, error) {
+		return &types.User{ID: id, Username: "alice"}, nil
+	}
+	edb.Mocks.Perms.ListSyncHistory = func(_ context.Context, userID int32, repoID api.RepoID, limit int) ([]*edb.PermsSyncHistory, error) {
+		if userID != 1 || repoID != 0 || limit != 5 {
+			return nil, fmt.Errorf("unexpected arguments %d, %d, %d", userID, repoID, limit)
+		}
+		return []*edb.PermsSyncHistory{
+			{
+				UserID:     1,
+				Providers:  []string{"https://github.com/"},
+				StartedAt:  time.Date(2020, 5, 1, 10, 0, 0, 0, time.UTC),
+				FinishedAt: time.Date(2020, 5, 1, 10, 1, 0, 0, time.UTC),
+				NumRepos:   3,
+				Partial:    true,
+			},
+			{
+				UserID:     1,
+				Providers:  []string{"https://github.com/"},
+				StartedAt:  time.Date(2020, 4, 30, 10, 0, 0, 0, time.UTC),
+				FinishedAt: time.Date(2020, 4, 30, 10, 0, 5, 0, time.UTC),
+				Error:      "rate limit exceeded",
+			},
+		}, nil
+	}
+	defer func() {
+		db.Mocks.Users = db.MockUsers{}
+		edb.Mocks.Perms = edb.MockPerms{}
+	}()
+
+	gqltesting.RunTests(t, []*gqltesting.Test{
+		{
+			Schema: mustParseGraphQLSchema(t, nil),
+			Query: `
+				{
+					node(id: "VXNlcjox") {
+						... on User {
+							permissionsSyncHistory(first: 5) {
+								startedAt
+								finishedAt
+								providers
+								repositoriesCount
+								partialResults
+								error
+							}
+						}
+					}
+				}
+			`,
+			ExpectedResult: `
+				{
+					"node": {
+						"permissionsSyncHistory": [
+							{
+								"startedAt": "2020-05-01T10:00:00Z",
+								"finishedAt": "2020-05-01T10:01:00Z",
+								"providers": ["https://github.com/"],
+								"repositoriesCount": 3,
+								"partialResults": true,
+								"error": null
+							},
+							{
+								"startedAt": "2020-04-30T10:00:00Z",
+								"finishedAt": "2020-04-30T10:00:05Z",
+								"providers": ["https://github.com/"],
+								"repositoriesCount": 0,
+								"partialResults": false,
+								"error": "rate limit exceeded"
+							}
+						]
+					}
+				}
+			`,
+		},
+	})
+}
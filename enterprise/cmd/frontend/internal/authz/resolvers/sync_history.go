@@ -0,0 +1,59 @@
+package resolvers
+
+import (
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+	edb "github.com/sourcegraph/sourcegraph/enterprise/cmd/frontend/db"
+)
+
+var _ graphqlbackend.PermissionsSyncHistoryEntryResolver = &permissionsSyncHistoryEntryResolver{}
+
+// permissionsSyncHistoryEntryResolver resolves a permissions sync of a user or a repository.
+type permissionsSyncHistoryEntryResolver struct {
+	h *edb.PermsSyncHistory
+}
+
+func toPermissionsSyncHistoryEntryResolvers(history []*edb.PermsSyncHistory) []graphqlbackend.PermissionsSyncHistoryEntryResolver {
+	resolvers := make([]graphqlbackend.PermissionsSyncHistoryEntryResolver, len(history))
+	for i, h := range history {
+		resolvers[i] = &permissionsSyncHistoryEntryResolver{h: h}
+	}
+	return resolvers
+}
+
+func (r *permissionsSyncHistoryEntryResolver) StartedAt() graphqlbackend.DateTime {
+	return graphqlbackend.DateTime{Time: r.h.StartedAt}
+}
+
+func (r *permissionsSyncHistoryEntryResolver) FinishedAt() graphqlbackend.DateTime {
+	return graphqlbackend.DateTime{Time: r.h.FinishedAt}
+}
+
+func (r *permissionsSyncHistoryEntryResolver) Providers() []string {
+	if r.h.Providers == nil {
+		return []string{}
+	}
+	return r.h.Providers
+}
+
+func (r *permissionsSyncHistoryEntryResolver) RepositoriesCount() int32 {
+	return int32(r.h.NumRepos)
+}
+
+func (r *permissionsSyncHistoryEntryResolver) UsersCount() int32 {
+	return int32(r.h.NumUsers)
+}
+
+func (r *permissionsSyncHistoryEntryResolver) PendingAccountsCount() int32 {
+	return int32(r.h.NumPendingAccounts)
+}
+
+func (r *permissionsSyncHistoryEntryResolver) PartialResults() bool {
+	return r.h.Partial
+}
+
+func (r *permissionsSyncHistoryEntryResolver) Error() *string {
+	if r.h.Error == "" {
+		return nil
+	}
+	return &r.h.Error
+}
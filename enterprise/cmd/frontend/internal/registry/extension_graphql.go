@@ -85,7 +85,7 @@ func (r *extensionDBResolver) IsWorkInProgress() bool {
 
 func (r *extensionDBResolver) ViewerCanAdminister(ctx context.Context) (bool, error) {
 	err := toRegistryPublisherID(r.v).viewerCanAdminister(ctx)
-	if err == backend.ErrMustBeSiteAdmin || err == backend.ErrNotAnOrgMember || err == backend.ErrNotAuthenticated {
+	if err == backend.ErrMustBeSiteAdmin || err == backend.ErrNotAnOrgMember || err == backend.ErrNotAuthenticated || backend.IsAccessTokenScopeError(err) {
 		return false, nil
 	}
 	if _, ok := err.(*backend.InsufficientAuthorizationError); ok {
@@ -11,6 +11,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
 	frontendregistry "github.com/sourcegraph/sourcegraph/cmd/frontend/registry"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
 	"github.com/sourcegraph/sourcegraph/enterprise/cmd/frontend/internal/licensing"
 )
 
@@ -140,8 +141,8 @@ func (p *registryPublisherID) viewerCanAdminister(ctx context.Context) error {
 		// 🚨 SECURITY: Check that the current user is either the publisher or a site admin.
 		return backend.CheckSiteAdminOrSameUser(ctx, p.userID)
 	case p.orgID != 0:
-		// 🚨 SECURITY: Check that the current user is a member of the publisher org.
-		return backend.CheckOrgAccess(ctx, p.orgID)
+		// 🚨 SECURITY: Check that the current user is a member (not read-only) of the publisher org.
+		return backend.CheckOrgAccess(ctx, p.orgID, types.OrgRoleMember)
 	default:
 		return errRegistryUnknownPublisher
 	}
@@ -0,0 +1,402 @@
+package scim
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/inconshreveable/log15"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/auth"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+)
+
+// groupResource is a SCIM Group (https://tools.ietf.org/html/rfc7643#section-4.2). Groups are
+// mapped to organizations, and their members to organization members.
+type groupResource struct {
+	Schemas     []string      `json:"schemas"`
+	ID          string        `json:"id,omitempty"`
+	DisplayName string        `json:"displayName"`
+	Members     []groupMember `json:"members"`
+	Meta        *meta         `json:"meta,omitempty"`
+}
+
+type groupMember struct {
+	// Value is the ID of the member user.
+	Value   string `json:"value"`
+	Display string `json:"display,omitempty"`
+}
+
+func toGroupResource(ctx context.Context, org *types.Org) (*groupResource, error) {
+	members, err := db.OrgMembers.GetByOrgID(ctx, org.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	id := strconv.Itoa(int(org.ID))
+	meta := newMeta("Group", "Groups/"+id, org.CreatedAt, org.UpdatedAt)
+	g := &groupResource{
+		Schemas:     []string{schemaGroup},
+		ID:          id,
+		DisplayName: org.Name,
+		Members:     make([]groupMember, 0, len(members)),
+		Meta:        &meta,
+	}
+	if org.DisplayName != nil && *org.DisplayName != "" {
+		g.DisplayName = *org.DisplayName
+	}
+	for _, m := range members {
+		g.Members = append(g.Members, groupMember{Value: strconv.Itoa(int(m.UserID))})
+	}
+	return g, nil
+}
+
+func serveListGroups(r *http.Request) (int, interface{}, error) {
+	params, err := parseListParams(r)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	var (
+		orgs  []*types.Org
+		total int
+	)
+	if params.Filter != nil {
+		org, err := findOrg(r.Context(), params.Filter)
+		if err != nil {
+			return 0, nil, err
+		}
+		if org != nil {
+			total = 1
+			if params.offset() == 0 && params.Count > 0 {
+				orgs = []*types.Org{org}
+			}
+		}
+	} else {
+		opt := db.OrgsListOptions{LimitOffset: &db.LimitOffset{Limit: params.Count, Offset: params.offset()}}
+		if total, err = db.Orgs.Count(r.Context(), opt); err != nil {
+			return 0, nil, err
+		}
+		if params.Count > 0 {
+			if orgs, err = db.Orgs.List(r.Context(), &opt); err != nil {
+				return 0, nil, err
+			}
+		}
+	}
+
+	resources := make([]*groupResource, 0, len(orgs))
+	for _, org := range orgs {
+		g, err := toGroupResource(r.Context(), org)
+		if err != nil {
+			return 0, nil, err
+		}
+		resources = append(resources, g)
+	}
+	return http.StatusOK, &listResponse{
+		Schemas:      []string{schemaListResponse},
+		TotalResults: total,
+		StartIndex:   params.StartIndex,
+		ItemsPerPage: len(resources),
+		Resources:    resources,
+	}, nil
+}
+
+// findOrg returns the organization matching the filter, or nil if there is none.
+func findOrg(ctx context.Context, f *filter) (*types.Org, error) {
+	var (
+		org *types.Org
+		err error
+	)
+	switch strings.ToLower(f.Attribute) {
+	case "displayname":
+		name, normErr := auth.NormalizeUsername(f.Value)
+		if normErr != nil {
+			return nil, nil
+		}
+		org, err = db.Orgs.GetByName(ctx, name)
+	case "id":
+		id, parseErr := strconv.ParseInt(f.Value, 10, 32)
+		if parseErr != nil {
+			return nil, nil
+		}
+		org, err = db.Orgs.GetByID(ctx, int32(id))
+	default:
+		return nil, badRequest("invalidFilter", "filtering groups by %q is not supported", f.Attribute)
+	}
+	if errcode.IsNotFound(err) {
+		return nil, nil
+	}
+	return org, err
+}
+
+func serveGetGroup(r *http.Request) (int, interface{}, error) {
+	org, err := orgByMuxID(r)
+	if err != nil {
+		return 0, nil, err
+	}
+	g, err := toGroupResource(r.Context(), org)
+	if err != nil {
+		return 0, nil, err
+	}
+	return http.StatusOK, g, nil
+}
+
+func orgByMuxID(r *http.Request) (*types.Org, error) {
+	id, s, err := muxID(r)
+	if err != nil {
+		return nil, err
+	}
+	org, err := db.Orgs.GetByID(r.Context(), id)
+	if errcode.IsNotFound(err) {
+		return nil, notFound("Group", s)
+	}
+	return org, err
+}
+
+func serveCreateGroup(r *http.Request) (int, interface{}, error) {
+	ctx := r.Context()
+
+	var g groupResource
+	if err := readJSON(r, &g); err != nil {
+		return 0, nil, err
+	}
+	if g.DisplayName == "" {
+		return 0, nil, badRequest("invalidValue", "displayName is required")
+	}
+	name, err := auth.NormalizeUsername(g.DisplayName)
+	if err != nil {
+		return 0, nil, badRequest("invalidValue", "%s", err)
+	}
+	memberIDs, err := parseMemberIDs(g.Members)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	// Organizations share their namespace with users, so check both.
+	if taken, err := nameIsTaken(ctx, name); err != nil {
+		return 0, nil, err
+	} else if taken {
+		return 0, nil, &scimError{status: http.StatusConflict, scimType: "uniqueness", detail: "the name " + name + " is already taken by a user or organization"}
+	}
+
+	displayName := g.DisplayName
+	org, err := db.Orgs.Create(ctx, name, &displayName)
+	if err != nil {
+		return 0, nil, err
+	}
+	if err := setOrgMembers(ctx, org.ID, memberIDs); err != nil {
+		return 0, nil, err
+	}
+
+	res, err := toGroupResource(ctx, org)
+	if err != nil {
+		return 0, nil, err
+	}
+	return http.StatusCreated, res, nil
+}
+
+func serveReplaceGroup(r *http.Request) (int, interface{}, error) {
+	org, err := orgByMuxID(r)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	var g groupResource
+	if err := readJSON(r, &g); err != nil {
+		return 0, nil, err
+	}
+	memberIDs, err := parseMemberIDs(g.Members)
+	if err != nil {
+		return 0, nil, err
+	}
+	if g.DisplayName != "" {
+		if org, err = updateOrgDisplayName(r.Context(), org, g.DisplayName); err != nil {
+			return 0, nil, err
+		}
+	}
+	if err := setOrgMembers(r.Context(), org.ID, memberIDs); err != nil {
+		return 0, nil, err
+	}
+
+	res, err := toGroupResource(r.Context(), org)
+	if err != nil {
+		return 0, nil, err
+	}
+	return http.StatusOK, res, nil
+}
+
+func servePatchGroup(r *http.Request) (int, interface{}, error) {
+	ctx := r.Context()
+
+	org, err := orgByMuxID(r)
+	if err != nil {
+		return 0, nil, err
+	}
+	req, err := readPatchRequest(r)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	members, err := db.OrgMembers.GetByOrgID(ctx, org.ID)
+	if err != nil {
+		return 0, nil, err
+	}
+	memberIDs := make(map[int32]bool, len(members))
+	for _, m := range members {
+		memberIDs[m.UserID] = true
+	}
+
+	for _, op := range req.Operations {
+		var displayName string
+		if ok, err := op.pathValue("displayName", &displayName); err != nil {
+			return 0, nil, err
+		} else if ok && op.Op != "remove" && displayName != "" {
+			if org, err = updateOrgDisplayName(ctx, org, displayName); err != nil {
+				return 0, nil, err
+			}
+		}
+
+		// A remove operation may select the member to remove with its path, e.g.
+		// `members[value eq "42"]`.
+		if op.Op == "remove" && strings.HasPrefix(strings.ToLower(op.Path), "members[") {
+			f, err := parseFilter(strings.TrimSuffix(op.Path[len("members["):], "]"))
+			if err != nil {
+				return 0, nil, err
+			}
+			if !strings.EqualFold(f.Attribute, "value") {
+				return 0, nil, badRequest("invalidPath", "unsupported path %q", op.Path)
+			}
+			ids, err := parseMemberIDs([]groupMember{{Value: f.Value}})
+			if err != nil {
+				return 0, nil, err
+			}
+			delete(memberIDs, ids[0])
+			continue
+		}
+
+		// Removing the members attribute without a value removes all members.
+		if op.Op == "remove" && strings.EqualFold(op.Path, "members") && len(op.Value) == 0 {
+			memberIDs = map[int32]bool{}
+			continue
+		}
+
+		var opMembers []groupMember
+		ok, err := op.pathValue("members", &opMembers)
+		if err != nil {
+			return 0, nil, err
+		}
+		if !ok {
+			continue
+		}
+		ids, err := parseMemberIDs(opMembers)
+		if err != nil {
+			return 0, nil, err
+		}
+		if op.Op == "replace" {
+			memberIDs = map[int32]bool{}
+		}
+		for _, id := range ids {
+			memberIDs[id] = op.Op != "remove"
+		}
+	}
+
+	ids := make([]int32, 0, len(memberIDs))
+	for id, member := range memberIDs {
+		if member {
+			ids = append(ids, id)
+		}
+	}
+	if err := setOrgMembers(ctx, org.ID, ids); err != nil {
+		return 0, nil, err
+	}
+
+	res, err := toGroupResource(ctx, org)
+	if err != nil {
+		return 0, nil, err
+	}
+	return http.StatusOK, res, nil
+}
+
+func serveDeleteGroup(r *http.Request) (int, interface{}, error) {
+	org, err := orgByMuxID(r)
+	if err != nil {
+		return 0, nil, err
+	}
+	if err := db.Orgs.Delete(r.Context(), org.ID); err != nil {
+		return 0, nil, err
+	}
+	log15.Info("SCIM: deleted organization", "org", org.ID)
+	return http.StatusNoContent, nil, nil
+}
+
+func nameIsTaken(ctx context.Context, name string) (bool, error) {
+	if _, err := db.Orgs.GetByName(ctx, name); err == nil {
+		return true, nil
+	} else if !errcode.IsNotFound(err) {
+		return false, err
+	}
+	if _, err := db.Users.GetByUsername(ctx, name); err == nil {
+		return true, nil
+	} else if !errcode.IsNotFound(err) {
+		return false, err
+	}
+	return false, nil
+}
+
+func updateOrgDisplayName(ctx context.Context, org *types.Org, displayName string) (*types.Org, error) {
+	if org.DisplayName != nil && *org.DisplayName == displayName {
+		return org, nil
+	}
+	return db.Orgs.Update(ctx, org.ID, &displayName)
+}
+
+func parseMemberIDs(members []groupMember) ([]int32, error) {
+	ids := make([]int32, 0, len(members))
+	for _, m := range members {
+		id, err := strconv.ParseInt(m.Value, 10, 32)
+		if err != nil {
+			return nil, badRequest("invalidValue", "invalid member %q", m.Value)
+		}
+		ids = append(ids, int32(id))
+	}
+	return ids, nil
+}
+
+// setOrgMembers makes the given users the only members of the organization. New members are added
+// with the member role, and the roles of existing members are unchanged.
+func setOrgMembers(ctx context.Context, orgID int32, userIDs []int32) error {
+	members, err := db.OrgMembers.GetByOrgID(ctx, orgID)
+	if err != nil {
+		return err
+	}
+	want := make(map[int32]bool, len(userIDs))
+	for _, id := range userIDs {
+		want[id] = true
+	}
+	have := make(map[int32]bool, len(members))
+	for _, m := range members {
+		have[m.UserID] = true
+		if !want[m.UserID] {
+			if err := db.OrgMembers.Remove(ctx, orgID, m.UserID); err != nil {
+				return err
+			}
+		}
+	}
+	for id := range want {
+		if have[id] {
+			continue
+		}
+		if _, err := db.Users.GetByID(ctx, id); err != nil {
+			if errcode.IsNotFound(err) {
+				return badRequest("invalidValue", "member %d is not a user", id)
+			}
+			return err
+		}
+		if _, err := db.OrgMembers.Create(ctx, orgID, id, types.OrgRoleMember); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,369 @@
+// Package scim implements a SCIM 2.0 (https://tools.ietf.org/html/rfc7644) provisioning API, which
+// lets identity providers create, update, deactivate and delete users and map their groups to
+// organizations.
+package scim
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/inconshreveable/log15"
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/globals"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+)
+
+const (
+	schemaUser                  = "urn:ietf:params:scim:schemas:core:2.0:User"
+	schemaGroup                 = "urn:ietf:params:scim:schemas:core:2.0:Group"
+	schemaListResponse          = "urn:ietf:params:scim:api:messages:2.0:ListResponse"
+	schemaPatchOp               = "urn:ietf:params:scim:api:messages:2.0:PatchOp"
+	schemaError                 = "urn:ietf:params:scim:api:messages:2.0:Error"
+	schemaServiceProviderConfig = "urn:ietf:params:scim:schemas:core:2.0:ServiceProviderConfig"
+
+	contentType = "application/scim+json"
+
+	// defaultCount is the number of resources returned by list requests that don't specify a count.
+	defaultCount = 100
+	// maxCount is the maximum number of resources returned by a list request.
+	maxCount = 1000
+)
+
+// NewHandler returns the handler of the SCIM API. It expects requests whose paths are relative to
+// the SCIM base URL, e.g. "/Users".
+//
+// 🚨 SECURITY: Only site admins may use the SCIM API.
+func NewHandler() http.Handler {
+	r := mux.NewRouter()
+	r.StrictSlash(true)
+
+	r.Path("/ServiceProviderConfig").Methods("GET").HandlerFunc(serveServiceProviderConfig)
+
+	r.Path("/Users").Methods("GET").Handler(handler(serveListUsers))
+	r.Path("/Users").Methods("POST").Handler(handler(serveCreateUser))
+	r.Path("/Users/{id}").Methods("GET").Handler(handler(serveGetUser))
+	r.Path("/Users/{id}").Methods("PUT").Handler(handler(serveReplaceUser))
+	r.Path("/Users/{id}").Methods("PATCH").Handler(handler(servePatchUser))
+	r.Path("/Users/{id}").Methods("DELETE").Handler(handler(serveDeleteUser))
+
+	r.Path("/Groups").Methods("GET").Handler(handler(serveListGroups))
+	r.Path("/Groups").Methods("POST").Handler(handler(serveCreateGroup))
+	r.Path("/Groups/{id}").Methods("GET").Handler(handler(serveGetGroup))
+	r.Path("/Groups/{id}").Methods("PUT").Handler(handler(serveReplaceGroup))
+	r.Path("/Groups/{id}").Methods("PATCH").Handler(handler(servePatchGroup))
+	r.Path("/Groups/{id}").Methods("DELETE").Handler(handler(serveDeleteGroup))
+
+	r.NotFoundHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, &scimError{status: http.StatusNotFound, detail: "endpoint not found"})
+	})
+	r.MethodNotAllowedHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, &scimError{status: http.StatusMethodNotAllowed, detail: "method not allowed"})
+	})
+
+	return siteAdminOnly(r)
+}
+
+// siteAdminOnly rejects requests that weren't authenticated as a site admin, usually by a site
+// admin's access token.
+func siteAdminOnly(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// 🚨 SECURITY: Provisioning users and organizations is only allowed for site admins.
+		if err := backend.CheckCurrentUserIsSiteAdmin(r.Context()); err != nil {
+			status := http.StatusForbidden
+			if err == backend.ErrNotAuthenticated {
+				status = http.StatusUnauthorized
+			}
+			writeError(w, &scimError{status: status, detail: err.Error()})
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// handler wraps a SCIM endpoint that returns the status and body of its response, or an error.
+type handler func(r *http.Request) (status int, body interface{}, err error)
+
+func (h handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	status, body, err := h(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	if body == nil {
+		w.WriteHeader(status)
+		return
+	}
+	writeJSON(w, status, body)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(status)
+	_, _ = w.Write(b)
+}
+
+// scimError is an error that's returned to the client as a SCIM error response
+// (https://tools.ietf.org/html/rfc7644#section-3.12).
+type scimError struct {
+	status   int
+	scimType string
+	detail   string
+}
+
+func (e *scimError) Error() string { return e.detail }
+
+func badRequest(scimType, format string, args ...interface{}) error {
+	return &scimError{status: http.StatusBadRequest, scimType: scimType, detail: errors.Errorf(format, args...).Error()}
+}
+
+func notFound(resourceType, id string) error {
+	return &scimError{status: http.StatusNotFound, detail: resourceType + " " + id + " not found"}
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	e, ok := err.(*scimError)
+	if !ok {
+		switch {
+		case errcode.IsNotFound(err):
+			e = &scimError{status: http.StatusNotFound, detail: err.Error()}
+		case errcode.PresentationMessage(err) != "":
+			e = &scimError{status: http.StatusBadRequest, scimType: "invalidValue", detail: errcode.PresentationMessage(err)}
+		default:
+			log15.Error("SCIM request failed.", "err", err)
+			e = &scimError{status: http.StatusInternalServerError, detail: "internal error"}
+		}
+	}
+	writeJSON(w, e.status, struct {
+		Schemas  []string `json:"schemas"`
+		Status   string   `json:"status"`
+		ScimType string   `json:"scimType,omitempty"`
+		Detail   string   `json:"detail,omitempty"`
+	}{
+		Schemas:  []string{schemaError},
+		Status:   strconv.Itoa(e.status),
+		ScimType: e.scimType,
+		Detail:   e.detail,
+	})
+}
+
+func readJSON(r *http.Request, v interface{}) error {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		return badRequest("invalidSyntax", "invalid request body: %s", err)
+	}
+	return nil
+}
+
+// meta is the metadata of a resource (https://tools.ietf.org/html/rfc7643#section-3.1).
+type meta struct {
+	ResourceType string     `json:"resourceType"`
+	Created      *time.Time `json:"created,omitempty"`
+	LastModified *time.Time `json:"lastModified,omitempty"`
+	Location     string     `json:"location"`
+}
+
+func newMeta(resourceType, path string, created, lastModified time.Time) meta {
+	return meta{
+		ResourceType: resourceType,
+		Created:      &created,
+		LastModified: &lastModified,
+		Location:     strings.TrimSuffix(globals.ExternalURL().String(), "/") + "/.api/scim/v2/" + path,
+	}
+}
+
+// listResponse is the response of list requests (https://tools.ietf.org/html/rfc7644#section-3.4.2).
+type listResponse struct {
+	Schemas      []string    `json:"schemas"`
+	TotalResults int         `json:"totalResults"`
+	StartIndex   int         `json:"startIndex"`
+	ItemsPerPage int         `json:"itemsPerPage"`
+	Resources    interface{} `json:"Resources"`
+}
+
+// listParams are the pagination and filtering parameters of a list request.
+type listParams struct {
+	// StartIndex is the 1-based index of the first resource to return.
+	StartIndex int
+	Count      int
+	Filter     *filter
+}
+
+func (p listParams) offset() int { return p.StartIndex - 1 }
+
+func parseListParams(r *http.Request) (*listParams, error) {
+	q := r.URL.Query()
+	p := listParams{StartIndex: 1, Count: defaultCount}
+
+	if v := q.Get("startIndex"); v != "" {
+		i, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, badRequest("invalidValue", "invalid startIndex %q", v)
+		}
+		if i > 1 {
+			p.StartIndex = i
+		}
+	}
+	if v := q.Get("count"); v != "" {
+		i, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, badRequest("invalidValue", "invalid count %q", v)
+		}
+		if i < 0 {
+			i = 0
+		}
+		p.Count = i
+	}
+	if p.Count > maxCount {
+		p.Count = maxCount
+	}
+
+	if v := q.Get("filter"); v != "" {
+		f, err := parseFilter(v)
+		if err != nil {
+			return nil, err
+		}
+		p.Filter = f
+	}
+	return &p, nil
+}
+
+// filter is a SCIM filter (https://tools.ietf.org/html/rfc7644#section-3.4.2.2). Only the
+// "attribute eq value" filters sent by identity providers to look up existing resources are
+// supported.
+type filter struct {
+	Attribute string
+	Value     string
+}
+
+func parseFilter(s string) (*filter, error) {
+	parts := strings.SplitN(strings.TrimSpace(s), " ", 3)
+	if len(parts) != 3 || !strings.EqualFold(parts[1], "eq") {
+		return nil, badRequest("invalidFilter", "unsupported filter %q, only \"attribute eq value\" filters are supported", s)
+	}
+
+	f := filter{Attribute: parts[0]}
+	value := strings.TrimSpace(parts[2])
+	if strings.HasPrefix(value, `"`) {
+		if err := json.Unmarshal([]byte(value), &f.Value); err != nil {
+			return nil, badRequest("invalidFilter", "invalid filter value %s", value)
+		}
+	} else {
+		f.Value = value
+	}
+	return &f, nil
+}
+
+// patchRequest is the body of PATCH requests (https://tools.ietf.org/html/rfc7644#section-3.5.2).
+type patchRequest struct {
+	Schemas    []string  `json:"schemas"`
+	Operations []patchOp `json:"Operations"`
+}
+
+type patchOp struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	Value json.RawMessage `json:"value"`
+}
+
+func readPatchRequest(r *http.Request) (*patchRequest, error) {
+	var req patchRequest
+	if err := readJSON(r, &req); err != nil {
+		return nil, err
+	}
+	for i, op := range req.Operations {
+		// Identity providers differ in the capitalization of operations.
+		req.Operations[i].Op = strings.ToLower(op.Op)
+		switch req.Operations[i].Op {
+		case "add", "remove", "replace":
+		default:
+			return nil, badRequest("invalidSyntax", "unsupported patch operation %q", op.Op)
+		}
+	}
+	return &req, nil
+}
+
+// pathValue returns the value of the given attribute set by the operation, whether the operation
+// specifies it in its path or as a field of its value.
+func (op *patchOp) pathValue(attribute string, v interface{}) (bool, error) {
+	if strings.EqualFold(op.Path, attribute) {
+		if err := json.Unmarshal(op.Value, v); err != nil {
+			return false, badRequest("invalidValue", "invalid value of %s: %s", attribute, err)
+		}
+		return true, nil
+	}
+	if op.Path != "" {
+		return false, nil
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(op.Value, &fields); err != nil {
+		return false, badRequest("invalidValue", "invalid value: %s", err)
+	}
+	for k, raw := range fields {
+		if strings.EqualFold(k, attribute) {
+			if err := json.Unmarshal(raw, v); err != nil {
+				return false, badRequest("invalidValue", "invalid value of %s: %s", attribute, err)
+			}
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func serveServiceProviderConfig(w http.ResponseWriter, r *http.Request) {
+	type supported struct {
+		Supported bool `json:"supported"`
+	}
+	type filterConfig struct {
+		Supported  bool `json:"supported"`
+		MaxResults int  `json:"maxResults"`
+	}
+	type bulkConfig struct {
+		Supported      bool `json:"supported"`
+		MaxOperations  int  `json:"maxOperations"`
+		MaxPayloadSize int  `json:"maxPayloadSize"`
+	}
+	type authenticationScheme struct {
+		Type        string `json:"type"`
+		Name        string `json:"name"`
+		Description string `json:"description"`
+	}
+
+	writeJSON(w, http.StatusOK, struct {
+		Schemas               []string               `json:"schemas"`
+		Patch                 supported              `json:"patch"`
+		Bulk                  bulkConfig             `json:"bulk"`
+		Filter                filterConfig           `json:"filter"`
+		ChangePassword        supported              `json:"changePassword"`
+		Sort                  supported              `json:"sort"`
+		ETag                  supported              `json:"etag"`
+		AuthenticationSchemes []authenticationScheme `json:"authenticationSchemes"`
+	}{
+		Schemas: []string{schemaServiceProviderConfig},
+		Patch:   supported{Supported: true},
+		Filter:  filterConfig{Supported: true, MaxResults: maxCount},
+		AuthenticationSchemes: []authenticationScheme{{
+			Type:        "oauthbearertoken",
+			Name:        "Access token",
+			Description: "A Sourcegraph access token of a site admin, with the user:all scope.",
+		}},
+	})
+}
+
+func muxID(r *http.Request) (int32, string, error) {
+	s := mux.Vars(r)["id"]
+	id, err := strconv.ParseInt(s, 10, 32)
+	if err != nil {
+		return 0, s, &scimError{status: http.StatusNotFound, detail: "resource " + s + " not found"}
+	}
+	return int32(id), s, nil
+}
@@ -0,0 +1,280 @@
+package scim
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+)
+
+func TestParseFilter(t *testing.T) {
+	tests := []struct {
+		filter  string
+		want    *filter
+		wantErr bool
+	}{
+		{filter: `userName eq "alice@example.com"`, want: &filter{Attribute: "userName", Value: "alice@example.com"}},
+		{filter: `externalId EQ "a b"`, want: &filter{Attribute: "externalId", Value: "a b"}},
+		{filter: `displayName eq eng`, want: &filter{Attribute: "displayName", Value: "eng"}},
+		{filter: `userName sw "a"`, wantErr: true},
+		{filter: `userName`, wantErr: true},
+		{filter: `userName eq "a`, wantErr: true},
+	}
+	for _, test := range tests {
+		t.Run(test.filter, func(t *testing.T) {
+			f, err := parseFilter(test.filter)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("got error %v, want error %v", err, test.wantErr)
+			}
+			if !reflect.DeepEqual(f, test.want) {
+				t.Errorf("got %+v, want %+v", f, test.want)
+			}
+		})
+	}
+}
+
+func TestApplyUserPatchOp(t *testing.T) {
+	active := flexBool(true)
+	tests := []struct {
+		name string
+		op   string
+		want userResource
+	}{
+		{
+			name: "deactivate with path",
+			op:   `{"op": "Replace", "path": "active", "value": false}`,
+			want: userResource{UserName: "alice", Active: new(flexBool)},
+		},
+		{
+			name: "deactivate with string value",
+			op:   `{"op": "replace", "value": {"active": "False"}}`,
+			want: userResource{UserName: "alice", Active: new(flexBool)},
+		},
+		{
+			name: "rename",
+			op:   `{"op": "replace", "value": {"userName": "bob", "displayName": "Bob"}}`,
+			want: userResource{UserName: "bob", DisplayName: "Bob", Active: &active},
+		},
+		{
+			name: "work email",
+			op:   `{"op": "replace", "path": "emails[type eq \"work\"].value", "value": "bob@example.com"}`,
+			want: userResource{UserName: "alice", Active: &active, Emails: []userEmail{{Value: "bob@example.com", Primary: true}}},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			active := flexBool(true)
+			u := userResource{UserName: "alice", Active: &active}
+
+			req, err := readPatchRequest(httptest.NewRequest("PATCH", "/Users/1", strings.NewReader(`{"Operations": [`+test.op+`]}`)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := applyUserPatchOp(&u, &req.Operations[0]); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(u, test.want) {
+				t.Errorf("got %+v, want %+v", u, test.want)
+			}
+		})
+	}
+}
+
+func TestHandler_siteAdminOnly(t *testing.T) {
+	defer func() { db.Mocks = db.MockStores{} }()
+	db.Mocks.Users.GetByCurrentAuthUser = func(ctx context.Context) (*types.User, error) {
+		a := actor.FromContext(ctx)
+		if !a.IsAuthenticated() {
+			return nil, db.ErrNoCurrentUser
+		}
+		return &types.User{ID: a.UID, SiteAdmin: a.UID == 1}, nil
+	}
+
+	tests := []struct {
+		name       string
+		actor      *actor.Actor
+		wantStatus int
+	}{
+		{name: "anonymous", actor: &actor.Actor{}, wantStatus: http.StatusUnauthorized},
+		{name: "non-admin", actor: &actor.Actor{UID: 2}, wantStatus: http.StatusForbidden},
+		{name: "site admin", actor: &actor.Actor{UID: 1}, wantStatus: http.StatusOK},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/ServiceProviderConfig", nil)
+			req = req.WithContext(actor.WithActor(context.Background(), test.actor))
+			rr := httptest.NewRecorder()
+			NewHandler().ServeHTTP(rr, req)
+			if rr.Code != test.wantStatus {
+				t.Errorf("got status %d, want %d", rr.Code, test.wantStatus)
+			}
+		})
+	}
+}
+
+func mockUser(t *testing.T, user *types.User) {
+	db.Mocks.Users.GetByCurrentAuthUser = func(ctx context.Context) (*types.User, error) {
+		return &types.User{ID: 1, SiteAdmin: true}, nil
+	}
+	db.Mocks.Users.GetByID = func(ctx context.Context, id int32) (*types.User, error) {
+		if id != user.ID {
+			t.Fatalf("unexpected user %d", id)
+		}
+		return user, nil
+	}
+	db.Mocks.UserEmails.ListByUser = func(ctx context.Context, opt db.UserEmailsListOptions) ([]*db.UserEmail, error) {
+		return nil, nil
+	}
+	db.Mocks.UserEmails.GetPrimaryEmail = func(ctx context.Context, id int32) (string, bool, error) {
+		return "", false, nil
+	}
+	db.Mocks.ExternalAccounts.List = func(db.ExternalAccountsListOptions) ([]*extsvc.Account, error) {
+		return nil, nil
+	}
+}
+
+func serve(t *testing.T, method, path, body string) (*httptest.ResponseRecorder, map[string]interface{}) {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req = req.WithContext(actor.WithActor(context.Background(), &actor.Actor{UID: 1}))
+	rr := httptest.NewRecorder()
+	NewHandler().ServeHTTP(rr, req)
+
+	var resp map[string]interface{}
+	if rr.Body.Len() > 0 {
+		if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return rr, resp
+}
+
+func TestCreateUser(t *testing.T) {
+	defer func() { db.Mocks = db.MockStores{} }()
+	user := &types.User{ID: 2, Username: "alice", CreatedAt: time.Now()}
+	mockUser(t, user)
+
+	var created db.NewUser
+	db.Mocks.ExternalAccounts.CreateUserAndSave = func(newUser db.NewUser, spec extsvc.AccountSpec, data extsvc.AccountData) (int32, error) {
+		if want := externalAccountSpec("abc"); spec != want {
+			t.Errorf("got spec %+v, want %+v", spec, want)
+		}
+		created = newUser
+		return user.ID, nil
+	}
+	var deactivated bool
+	db.Mocks.Users.SetIsDeactivated = func(id int32, isDeactivated bool) error {
+		deactivated = isDeactivated
+		return nil
+	}
+
+	rr, resp := serve(t, "POST", "/Users", `{
+		"schemas": ["urn:ietf:params:scim:schemas:core:2.0:User"],
+		"externalId": "abc",
+		"userName": "alice@example.com",
+		"name": {"givenName": "Alice", "familyName": "Smith"},
+		"emails": [{"value": "alice@corp.example.com"}, {"value": "alice@example.com", "primary": true}],
+		"active": false
+	}`)
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("got status %d, want %d: %v", rr.Code, http.StatusCreated, resp)
+	}
+	want := db.NewUser{
+		Username:        "alice",
+		DisplayName:     "Alice Smith",
+		Email:           "alice@example.com",
+		EmailIsVerified: true,
+	}
+	if !reflect.DeepEqual(created, want) {
+		t.Errorf("got new user %+v, want %+v", created, want)
+	}
+	if !deactivated {
+		t.Error("want user to be deactivated")
+	}
+	if resp["id"] != "2" {
+		t.Errorf("got id %v, want 2", resp["id"])
+	}
+}
+
+func TestListUsers_filter(t *testing.T) {
+	defer func() { db.Mocks = db.MockStores{} }()
+	user := &types.User{ID: 2, Username: "alice"}
+	mockUser(t, user)
+	db.Mocks.Users.GetByUsername = func(ctx context.Context, username string) (*types.User, error) {
+		return nil, db.NewUserNotFoundError(0)
+	}
+	db.Mocks.Users.GetByVerifiedEmail = func(ctx context.Context, email string) (*types.User, error) {
+		if email != "alice@example.com" {
+			t.Errorf("got email %q, want alice@example.com", email)
+		}
+		return user, nil
+	}
+
+	rr, resp := serve(t, "GET", `/Users?filter=userName+eq+"alice@example.com"`, "")
+	if rr.Code != http.StatusOK {
+		t.Fatalf("got status %d, want %d: %v", rr.Code, http.StatusOK, resp)
+	}
+	if resp["totalResults"] != float64(1) {
+		t.Errorf("got totalResults %v, want 1", resp["totalResults"])
+	}
+}
+
+func TestPatchUser_deactivate(t *testing.T) {
+	defer func() { db.Mocks = db.MockStores{} }()
+	mockUser(t, &types.User{ID: 2, Username: "alice"})
+	db.Mocks.Users.Update = func(userID int32, update db.UserUpdate) error {
+		t.Errorf("unexpected update %+v", update)
+		return nil
+	}
+	var deactivated bool
+	db.Mocks.Users.SetIsDeactivated = func(id int32, isDeactivated bool) error {
+		if id != 2 {
+			t.Errorf("got user %d, want 2", id)
+		}
+		deactivated = isDeactivated
+		return nil
+	}
+
+	rr, resp := serve(t, "PATCH", "/Users/2", `{
+		"schemas": ["urn:ietf:params:scim:api:messages:2.0:PatchOp"],
+		"Operations": [{"op": "replace", "path": "active", "value": false}]
+	}`)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("got status %d, want %d: %v", rr.Code, http.StatusOK, resp)
+	}
+	if !deactivated {
+		t.Error("want user to be deactivated")
+	}
+}
+
+func TestGetGroup(t *testing.T) {
+	defer func() { db.Mocks = db.MockStores{} }()
+	mockUser(t, &types.User{ID: 2})
+	displayName := "Engineering"
+	db.Mocks.Orgs.GetByID = func(ctx context.Context, id int32) (*types.Org, error) {
+		return &types.Org{ID: id, Name: "engineering", DisplayName: &displayName}, nil
+	}
+	db.Mocks.OrgMembers.GetByOrgID = func(ctx context.Context, orgID int32) ([]*types.OrgMembership, error) {
+		return []*types.OrgMembership{{OrgID: orgID, UserID: 2}, {OrgID: orgID, UserID: 3}}, nil
+	}
+
+	rr, resp := serve(t, "GET", "/Groups/5", "")
+	if rr.Code != http.StatusOK {
+		t.Fatalf("got status %d, want %d: %v", rr.Code, http.StatusOK, resp)
+	}
+	if resp["displayName"] != "Engineering" {
+		t.Errorf("got displayName %v, want Engineering", resp["displayName"])
+	}
+	want := []interface{}{map[string]interface{}{"value": "2"}, map[string]interface{}{"value": "3"}}
+	if !reflect.DeepEqual(resp["members"], want) {
+		t.Errorf("got members %v, want %v", resp["members"], want)
+	}
+}
@@ -0,0 +1,501 @@
+package scim
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/inconshreveable/log15"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/auth"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/authz"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+)
+
+// serviceType is the service type of the external accounts that hold the externalId of
+// provisioned users.
+const serviceType = "scim"
+
+func externalAccountSpec(externalID string) extsvc.AccountSpec {
+	return extsvc.AccountSpec{ServiceType: serviceType, ServiceID: serviceType, AccountID: externalID}
+}
+
+// userResource is a SCIM User (https://tools.ietf.org/html/rfc7643#section-4.1).
+type userResource struct {
+	Schemas     []string    `json:"schemas"`
+	ID          string      `json:"id,omitempty"`
+	ExternalID  string      `json:"externalId,omitempty"`
+	UserName    string      `json:"userName"`
+	DisplayName string      `json:"displayName,omitempty"`
+	Name        *userName   `json:"name,omitempty"`
+	Emails      []userEmail `json:"emails,omitempty"`
+	Active      *flexBool   `json:"active,omitempty"`
+	Meta        *meta       `json:"meta,omitempty"`
+}
+
+type userName struct {
+	Formatted  string `json:"formatted,omitempty"`
+	GivenName  string `json:"givenName,omitempty"`
+	FamilyName string `json:"familyName,omitempty"`
+}
+
+type userEmail struct {
+	Value   string `json:"value"`
+	Type    string `json:"type,omitempty"`
+	Primary bool   `json:"primary,omitempty"`
+}
+
+// flexBool is a boolean that's also unmarshaled from the strings "true" and "false", which some
+// identity providers send instead of booleans.
+type flexBool bool
+
+func (b *flexBool) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		v, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		*b = flexBool(v)
+		return nil
+	}
+	var v bool
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*b = flexBool(v)
+	return nil
+}
+
+func (u *userResource) active() bool {
+	return u.Active == nil || bool(*u.Active)
+}
+
+func (u *userResource) displayName() string {
+	if u.DisplayName != "" {
+		return u.DisplayName
+	}
+	if u.Name == nil {
+		return ""
+	}
+	if u.Name.Formatted != "" {
+		return u.Name.Formatted
+	}
+	return strings.TrimSpace(u.Name.GivenName + " " + u.Name.FamilyName)
+}
+
+func (u *userResource) primaryEmail() string {
+	for _, e := range u.Emails {
+		if e.Primary {
+			return e.Value
+		}
+	}
+	if len(u.Emails) > 0 {
+		return u.Emails[0].Value
+	}
+	return ""
+}
+
+func toUserResource(ctx context.Context, user *types.User) (*userResource, error) {
+	emails, err := db.UserEmails.ListByUser(ctx, db.UserEmailsListOptions{UserID: user.ID})
+	if err != nil {
+		return nil, err
+	}
+	primary, _, err := db.UserEmails.GetPrimaryEmail(ctx, user.ID)
+	if err != nil && !errcode.IsNotFound(err) {
+		return nil, err
+	}
+	accounts, err := db.ExternalAccounts.List(ctx, db.ExternalAccountsListOptions{
+		UserID:      user.ID,
+		ServiceType: serviceType,
+		ServiceID:   serviceType,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	id := strconv.Itoa(int(user.ID))
+	active := flexBool(user.DeactivatedAt == nil)
+	meta := newMeta("User", "Users/"+id, user.CreatedAt, user.UpdatedAt)
+	u := &userResource{
+		Schemas:     []string{schemaUser},
+		ID:          id,
+		UserName:    user.Username,
+		DisplayName: user.DisplayName,
+		Active:      &active,
+		Meta:        &meta,
+	}
+	if user.DisplayName != "" {
+		u.Name = &userName{Formatted: user.DisplayName}
+	}
+	for _, e := range emails {
+		u.Emails = append(u.Emails, userEmail{Value: e.Email, Primary: e.Email == primary})
+	}
+	if len(accounts) > 0 {
+		u.ExternalID = accounts[len(accounts)-1].AccountID
+	}
+	return u, nil
+}
+
+func serveListUsers(r *http.Request) (int, interface{}, error) {
+	params, err := parseListParams(r)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	var (
+		users []*types.User
+		total int
+	)
+	if params.Filter != nil {
+		user, err := findUser(r.Context(), params.Filter)
+		if err != nil {
+			return 0, nil, err
+		}
+		if user != nil {
+			total = 1
+			if params.offset() == 0 && params.Count > 0 {
+				users = []*types.User{user}
+			}
+		}
+	} else {
+		opt := &db.UsersListOptions{LimitOffset: &db.LimitOffset{Limit: params.Count, Offset: params.offset()}}
+		if total, err = db.Users.Count(r.Context(), opt); err != nil {
+			return 0, nil, err
+		}
+		if params.Count > 0 {
+			if users, err = db.Users.List(r.Context(), opt); err != nil {
+				return 0, nil, err
+			}
+		}
+	}
+
+	resources := make([]*userResource, 0, len(users))
+	for _, user := range users {
+		u, err := toUserResource(r.Context(), user)
+		if err != nil {
+			return 0, nil, err
+		}
+		resources = append(resources, u)
+	}
+	return http.StatusOK, &listResponse{
+		Schemas:      []string{schemaListResponse},
+		TotalResults: total,
+		StartIndex:   params.StartIndex,
+		ItemsPerPage: len(resources),
+		Resources:    resources,
+	}, nil
+}
+
+// findUser returns the user matching the filter, or nil if there is none. Users are matched by
+// their username or, if it's an email address, their verified email, by their verified email and
+// by their externalId.
+func findUser(ctx context.Context, f *filter) (*types.User, error) {
+	var (
+		user *types.User
+		err  error
+	)
+	switch strings.ToLower(f.Attribute) {
+	case "username":
+		user, err = db.Users.GetByUsername(ctx, f.Value)
+		if errcode.IsNotFound(err) && strings.Contains(f.Value, "@") {
+			user, err = db.Users.GetByVerifiedEmail(ctx, f.Value)
+		}
+	case "emails", "emails.value":
+		user, err = db.Users.GetByVerifiedEmail(ctx, f.Value)
+	case "externalid":
+		var userID int32
+		userID, err = db.ExternalAccounts.LookupUserAndSave(ctx, externalAccountSpec(f.Value), extsvc.AccountData{})
+		if err == nil {
+			user, err = db.Users.GetByID(ctx, userID)
+		}
+	case "id":
+		id, parseErr := strconv.ParseInt(f.Value, 10, 32)
+		if parseErr != nil {
+			return nil, nil
+		}
+		user, err = db.Users.GetByID(ctx, int32(id))
+	default:
+		return nil, badRequest("invalidFilter", "filtering users by %q is not supported", f.Attribute)
+	}
+	if errcode.IsNotFound(err) {
+		return nil, nil
+	}
+	return user, err
+}
+
+func serveGetUser(r *http.Request) (int, interface{}, error) {
+	user, err := userByMuxID(r)
+	if err != nil {
+		return 0, nil, err
+	}
+	u, err := toUserResource(r.Context(), user)
+	if err != nil {
+		return 0, nil, err
+	}
+	return http.StatusOK, u, nil
+}
+
+func userByMuxID(r *http.Request) (*types.User, error) {
+	id, s, err := muxID(r)
+	if err != nil {
+		return nil, err
+	}
+	user, err := db.Users.GetByID(r.Context(), id)
+	if errcode.IsNotFound(err) {
+		return nil, notFound("User", s)
+	}
+	return user, err
+}
+
+func serveCreateUser(r *http.Request) (int, interface{}, error) {
+	ctx := r.Context()
+
+	var u userResource
+	if err := readJSON(r, &u); err != nil {
+		return 0, nil, err
+	}
+	if u.UserName == "" {
+		return 0, nil, badRequest("invalidValue", "userName is required")
+	}
+	username, err := auth.NormalizeUsername(u.UserName)
+	if err != nil {
+		return 0, nil, badRequest("invalidValue", "%s", err)
+	}
+
+	// 🚨 SECURITY: The identity provider is trusted to have verified the user's email address,
+	// like it is when the user signs in with it.
+	email := u.primaryEmail()
+	newUser := db.NewUser{
+		Username:        username,
+		DisplayName:     u.displayName(),
+		Email:           email,
+		EmailIsVerified: email != "",
+	}
+
+	var userID int32
+	if u.ExternalID != "" {
+		userID, err = db.ExternalAccounts.CreateUserAndSave(ctx, newUser, externalAccountSpec(u.ExternalID), extsvc.AccountData{})
+	} else {
+		var user *types.User
+		if user, err = db.Users.Create(ctx, newUser); err == nil {
+			userID = user.ID
+		}
+	}
+	switch {
+	case db.IsUsernameExists(err):
+		return 0, nil, &scimError{status: http.StatusConflict, scimType: "uniqueness", detail: "a user with username " + username + " already exists"}
+	case db.IsEmailExists(err):
+		return 0, nil, &scimError{status: http.StatusConflict, scimType: "uniqueness", detail: "a user with email " + email + " already exists"}
+	case err != nil:
+		return 0, nil, err
+	}
+
+	if err = db.Authz.GrantPendingPermissions(ctx, &db.GrantPendingPermissionsArgs{
+		UserID: userID,
+		Perm:   authz.Read,
+		Type:   authz.PermRepos,
+	}); err != nil {
+		log15.Error("Failed to grant user pending permissions", "userID", userID, "error", err)
+	}
+
+	if !u.active() {
+		if err := db.Users.SetIsDeactivated(ctx, userID, true); err != nil {
+			return 0, nil, err
+		}
+	}
+
+	user, err := db.Users.GetByID(ctx, userID)
+	if err != nil {
+		return 0, nil, err
+	}
+	res, err := toUserResource(ctx, user)
+	if err != nil {
+		return 0, nil, err
+	}
+	return http.StatusCreated, res, nil
+}
+
+func serveReplaceUser(r *http.Request) (int, interface{}, error) {
+	user, err := userByMuxID(r)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	var u userResource
+	if err := readJSON(r, &u); err != nil {
+		return 0, nil, err
+	}
+	if u.UserName == "" {
+		return 0, nil, badRequest("invalidValue", "userName is required")
+	}
+	return updateUser(r.Context(), user, &u)
+}
+
+func servePatchUser(r *http.Request) (int, interface{}, error) {
+	user, err := userByMuxID(r)
+	if err != nil {
+		return 0, nil, err
+	}
+	req, err := readPatchRequest(r)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	u, err := toUserResource(r.Context(), user)
+	if err != nil {
+		return 0, nil, err
+	}
+	for _, op := range req.Operations {
+		if err := applyUserPatchOp(u, &op); err != nil {
+			return 0, nil, err
+		}
+	}
+	return updateUser(r.Context(), user, u)
+}
+
+// applyUserPatchOp applies the operation to the user's resource. Operations on attributes that
+// Sourcegraph doesn't store are ignored.
+func applyUserPatchOp(u *userResource, op *patchOp) error {
+	if op.Op == "remove" {
+		switch strings.ToLower(op.Path) {
+		case "externalid":
+			u.ExternalID = ""
+		case "displayname":
+			u.DisplayName = ""
+			u.Name = nil
+		}
+		return nil
+	}
+
+	var active flexBool
+	if ok, err := op.pathValue("active", &active); err != nil {
+		return err
+	} else if ok {
+		u.Active = &active
+	}
+	if ok, err := op.pathValue("userName", &u.UserName); err != nil {
+		return err
+	} else if ok && u.UserName == "" {
+		return badRequest("invalidValue", "userName is required")
+	}
+	if _, err := op.pathValue("externalId", &u.ExternalID); err != nil {
+		return err
+	}
+	if _, err := op.pathValue("displayName", &u.DisplayName); err != nil {
+		return err
+	}
+
+	var name userName
+	if ok, err := op.pathValue("name", &name); err != nil {
+		return err
+	} else if ok && u.DisplayName == "" {
+		u.Name = &name
+	}
+	var formatted string
+	if ok, err := op.pathValue("name.formatted", &formatted); err != nil {
+		return err
+	} else if ok {
+		u.DisplayName = formatted
+	}
+
+	var emails []userEmail
+	if ok, err := op.pathValue("emails", &emails); err != nil {
+		return err
+	} else if ok {
+		u.Emails = emails
+	}
+	// Some identity providers set the work email with a path that selects it.
+	if strings.HasPrefix(strings.ToLower(op.Path), "emails[") {
+		var email string
+		if err := json.Unmarshal(op.Value, &email); err != nil {
+			return badRequest("invalidValue", "invalid value of %s: %s", op.Path, err)
+		}
+		u.Emails = []userEmail{{Value: email, Primary: true}}
+	}
+	return nil
+}
+
+// updateUser updates the user to match the given resource.
+func updateUser(ctx context.Context, user *types.User, u *userResource) (int, interface{}, error) {
+	var update db.UserUpdate
+	username, err := auth.NormalizeUsername(u.UserName)
+	if err != nil {
+		return 0, nil, badRequest("invalidValue", "%s", err)
+	}
+	if username != user.Username {
+		update.Username = username
+	}
+	if displayName := u.displayName(); displayName != user.DisplayName {
+		update.DisplayName = &displayName
+	}
+	if update != (db.UserUpdate{}) {
+		if err := db.Users.Update(ctx, user.ID, update); err != nil {
+			if db.IsUsernameExists(err) {
+				return 0, nil, &scimError{status: http.StatusConflict, scimType: "uniqueness", detail: "a user with username " + username + " already exists"}
+			}
+			return 0, nil, err
+		}
+	}
+
+	// 🚨 SECURITY: The identity provider is trusted to have verified the user's email address,
+	// like it is when the user signs in with it.
+	if email := u.primaryEmail(); email != "" {
+		if _, verified, err := db.UserEmails.Get(ctx, user.ID, email); errcode.IsNotFound(err) {
+			if err := db.UserEmails.Add(ctx, user.ID, email, nil); err != nil {
+				return 0, nil, err
+			}
+		} else if err != nil {
+			return 0, nil, err
+		} else if verified {
+			email = ""
+		}
+		if email != "" {
+			if err := db.UserEmails.SetVerified(ctx, user.ID, email, true); err != nil {
+				return 0, nil, err
+			}
+		}
+	}
+
+	if u.ExternalID != "" {
+		if err := db.ExternalAccounts.AssociateUserAndSave(ctx, user.ID, externalAccountSpec(u.ExternalID), extsvc.AccountData{}); err != nil {
+			return 0, nil, err
+		}
+	}
+
+	// Deactivating a user also revokes their sessions and access tokens, which stay revoked when the
+	// user is reactivated.
+	if active := u.active(); active != (user.DeactivatedAt == nil) {
+		if err := db.Users.SetIsDeactivated(ctx, user.ID, !active); err != nil {
+			return 0, nil, err
+		}
+		log15.Info("SCIM: set user deactivation", "user", user.ID, "deactivated", !active)
+	}
+
+	user, err = db.Users.GetByID(ctx, user.ID)
+	if err != nil {
+		return 0, nil, err
+	}
+	res, err := toUserResource(ctx, user)
+	if err != nil {
+		return 0, nil, err
+	}
+	return http.StatusOK, res, nil
+}
+
+func serveDeleteUser(r *http.Request) (int, interface{}, error) {
+	user, err := userByMuxID(r)
+	if err != nil {
+		return 0, nil, err
+	}
+	// Deleting a user also deletes their access tokens and revokes their sessions.
+	if err := db.Users.Delete(r.Context(), user.ID); err != nil {
+		return 0, nil, err
+	}
+	log15.Info("SCIM: deleted user", "user", user.ID)
+	return http.StatusNoContent, nil, nil
+}
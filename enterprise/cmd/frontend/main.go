@@ -25,6 +25,7 @@ import (
 	_ "github.com/sourcegraph/sourcegraph/enterprise/cmd/frontend/internal/graphqlbackend"
 	"github.com/sourcegraph/sourcegraph/enterprise/cmd/frontend/internal/licensing"
 	_ "github.com/sourcegraph/sourcegraph/enterprise/cmd/frontend/internal/registry"
+	"github.com/sourcegraph/sourcegraph/enterprise/cmd/frontend/internal/scim"
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/campaigns"
 	campaignsResolvers "github.com/sourcegraph/sourcegraph/enterprise/internal/campaigns/resolvers"
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/lsifserver/proxy"
@@ -38,6 +39,7 @@ func main() {
 	initLicensing()
 	initResolvers()
 	initLSIFEndpoints()
+	initSCIMEndpoints()
 
 	// Connect to the database.
 	if err := shared.InitDB(); err != nil {
@@ -138,6 +140,10 @@ func initLSIFEndpoints() {
 	httpapi.NewLSIFServerProxy = proxy.NewProxy
 }
 
+func initSCIMEndpoints() {
+	httpapi.SCIMHandler = scim.NewHandler()
+}
+
 type usersStore struct{}
 
 func (usersStore) Count(ctx context.Context) (int, error) {
@@ -65,14 +65,14 @@ func NewPermsSyncer(
 }
 
 // ScheduleUsers schedules new permissions syncing requests for given users
-// in desired priority.
+// in high priority, so that they are synced as soon as possible.
 //
-// This method implements the authz.PermsSyncer in the OSS namespace.
-func (s *PermsSyncer) ScheduleUsers(ctx context.Context, priority Priority, userIDs ...int32) {
+// This method implements the PermsSyncer of the repo-updater server in the OSS namespace.
+func (s *PermsSyncer) ScheduleUsers(ctx context.Context, userIDs ...int32) {
 	users := make([]scheduledUser, len(userIDs))
 	for i := range userIDs {
 		users[i] = scheduledUser{
-			priority: priority,
+			priority: PriorityHigh,
 			userID:   userIDs[i],
 			// NOTE: Have nextSyncAt with zero value (i.e. not set) gives it higher priority,
 			// as the request is most likely triggered by a user action from OSS namespace.
@@ -103,14 +103,14 @@ func (s *PermsSyncer) scheduleUsers(ctx context.Context, users ...scheduledUser)
 }
 
 // ScheduleRepos schedules new permissions syncing requests for given repositories
-// in desired priority.
+// in high priority, so that they are synced as soon as possible.
 //
-// This method implements the authz.PermsSyncer in the OSS namespace.
-func (s *PermsSyncer) ScheduleRepos(ctx context.Context, priority Priority, repoIDs ...api.RepoID) {
+// This method implements the PermsSyncer of the repo-updater server in the OSS namespace.
+func (s *PermsSyncer) ScheduleRepos(ctx context.Context, repoIDs ...api.RepoID) {
 	repos := make([]scheduledRepo, len(repoIDs))
 	for i := range repoIDs {
 		repos[i] = scheduledRepo{
-			priority: priority,
+			priority: PriorityHigh,
 			repoID:   repoIDs[i],
 			// NOTE: Have nextSyncAt with zero value (i.e. not set) gives it higher priority,
 			// as the request is most likely triggered by a user action from OSS namespace.
@@ -157,6 +157,9 @@ func (s *PermsSyncer) syncUserPerms(ctx context.Context, userID int32, noPerms b
 	ctx, save := s.observe(ctx, "PermsSyncer.syncUserPerms", "")
 	defer save(requestTypeUser, userID, &err)
 
+	history := &edb.PermsSyncHistory{UserID: userID, StartedAt: s.clock()}
+	defer s.recordSyncHistory(ctx, history, &err)
+
 	accts, err := s.permsStore.ListExternalAccounts(ctx, userID)
 	if err != nil {
 		return errors.Wrap(err, "list external accounts")
@@ -169,6 +172,7 @@ func (s *PermsSyncer) syncUserPerms(ctx context.Context, userID int32, noPerms b
 			// We have no authz provider configured for this external account.
 			continue
 		}
+		history.Providers = append(history.Providers, provider.ServiceID())
 
 		extIDs, err := provider.FetchUserPerms(ctx, acct)
 		if err != nil {
@@ -177,6 +181,7 @@ func (s *PermsSyncer) syncUserPerms(ctx context.Context, userID int32, noPerms b
 				return errors.Wrap(err, "fetch user permissions")
 			}
 			log15.Debug("PermsSyncer.syncUserPerms.proceedWithPartialResults", "userID", userID, "err", err)
+			history.Partial = true
 		}
 
 		for i := range extIDs {
@@ -211,6 +216,11 @@ func (s *PermsSyncer) syncUserPerms(ctx context.Context, userID int32, noPerms b
 		p.IDs.Add(uint32(rs[i].ID))
 	}
 
+	if err = s.keepExplicitUserPerms(ctx, p); err != nil {
+		return errors.Wrap(err, "keep explicit user permissions")
+	}
+	history.NumRepos = int(p.IDs.GetCardinality())
+
 	err = s.permsStore.SetUserPermissions(ctx, p)
 	if err != nil {
 		return errors.Wrap(err, "set user permissions")
@@ -220,6 +230,43 @@ func (s *PermsSyncer) syncUserPerms(ctx context.Context, userID int32, noPerms b
 	return nil
 }
 
+// keepExplicitUserPerms adds to p the repositories that the user is currently authorized for and
+// whose code host has no authz provider. Those permissions were set explicitly through the
+// permissions API and are unknown to the authz providers, so they must survive the sync.
+func (s *PermsSyncer) keepExplicitUserPerms(ctx context.Context, p *authz.UserPermissions) error {
+	current := &authz.UserPermissions{
+		UserID: p.UserID,
+		Perm:   p.Perm,
+		Type:   p.Type,
+	}
+	err := s.permsStore.LoadUserPermissions(ctx, current)
+	if err == authz.ErrPermsNotFound {
+		return nil
+	} else if err != nil {
+		return errors.Wrap(err, "load user permissions")
+	}
+	if current.IDs == nil || current.IDs.IsEmpty() {
+		return nil
+	}
+
+	ids := make([]api.RepoID, 0, current.IDs.GetCardinality())
+	for _, id := range current.IDs.ToArray() {
+		ids = append(ids, api.RepoID(id))
+	}
+	rs, err := s.reposStore.ListRepos(ctx, repos.StoreListReposArgs{IDs: ids})
+	if err != nil {
+		return errors.Wrap(err, "list repositories")
+	}
+
+	providers := s.providers()
+	for _, r := range rs {
+		if providers[r.ExternalRepo.ServiceID] == nil {
+			p.IDs.Add(uint32(r.ID))
+		}
+	}
+	return nil
+}
+
 // syncRepoPerms processes permissions syncing request in repository-centric way.
 // It discards requests that are made for non-private repositories based on the
 // value of "repo.private" column. When noPerms is true, the method will use partial
@@ -228,6 +275,8 @@ func (s *PermsSyncer) syncRepoPerms(ctx context.Context, repoID api.RepoID, noPe
 	ctx, save := s.observe(ctx, "PermsSyncer.syncRepoPerms", "")
 	defer save(requestTypeRepo, int32(repoID), &err)
 
+	began := s.clock()
+
 	rs, err := s.reposStore.ListRepos(ctx, repos.StoreListReposArgs{
 		IDs: []api.RepoID{repoID},
 	})
@@ -248,6 +297,9 @@ func (s *PermsSyncer) syncRepoPerms(ctx context.Context, repoID api.RepoID, noPe
 		return nil
 	}
 
+	history := &edb.PermsSyncHistory{RepoID: repoID, Providers: []string{provider.ServiceID()}, StartedAt: began}
+	defer s.recordSyncHistory(ctx, history, &err)
+
 	extAccountIDs, err := provider.FetchRepoPerms(ctx, &extsvc.Repository{
 		URI:              repo.URI,
 		ExternalRepoSpec: repo.ExternalRepo,
@@ -258,6 +310,7 @@ func (s *PermsSyncer) syncRepoPerms(ctx context.Context, repoID api.RepoID, noPe
 			return errors.Wrap(err, "fetch repository permissions")
 		}
 		log15.Debug("PermsSyncer.syncRepoPerms.proceedWithPartialResults", "repoID", repo.ID, "err", err)
+		history.Partial = true
 	}
 
 	pendingAccountIDsSet := make(map[string]struct{})
@@ -304,6 +357,8 @@ func (s *PermsSyncer) syncRepoPerms(ctx context.Context, repoID api.RepoID, noPe
 	for aid := range pendingAccountIDsSet {
 		pendingAccountIDs = append(pendingAccountIDs, aid)
 	}
+	history.NumUsers = int(p.UserIDs.GetCardinality())
+	history.NumPendingAccounts = len(pendingAccountIDs)
 
 	txs, err := s.permsStore.Transact(ctx)
 	if err != nil {
@@ -327,6 +382,18 @@ func (s *PermsSyncer) syncRepoPerms(ctx context.Context, repoID api.RepoID, noPe
 	return nil
 }
 
+// recordSyncHistory saves the record of a finished permissions sync, with the error that made it fail
+// (if any). Failing to save the record does not fail the sync.
+func (s *PermsSyncer) recordSyncHistory(ctx context.Context, h *edb.PermsSyncHistory, err *error) {
+	h.FinishedAt = s.clock()
+	if err != nil && *err != nil {
+		h.Error = (*err).Error()
+	}
+	if err := s.permsStore.RecordSyncHistory(ctx, h); err != nil {
+		log15.Warn("Failed to record permissions sync history", "userID", h.UserID, "repoID", h.RepoID, "err", err)
+	}
+}
+
 // syncPerms processes the permissions syncing request and remove the request from
 // the queue once it is done (independent of success or failure).
 func (s *PermsSyncer) syncPerms(ctx context.Context, request *syncRequest) error {
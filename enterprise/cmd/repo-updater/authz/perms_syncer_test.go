@@ -6,6 +6,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/RoaringBitmap/roaring"
 	"github.com/google/go-cmp/cmp"
 	"github.com/pkg/errors"
 	"github.com/prometheus/client_golang/prometheus"
@@ -20,7 +21,7 @@ import (
 
 func TestPermsSyncer_ScheduleUsers(t *testing.T) {
 	s := NewPermsSyncer(nil, nil, nil)
-	s.ScheduleUsers(context.Background(), PriorityHigh, 1)
+	s.ScheduleUsers(context.Background(), 1)
 
 	expHeap := []*syncRequest{
 		{requestMeta: &requestMeta{
@@ -36,7 +37,7 @@ func TestPermsSyncer_ScheduleUsers(t *testing.T) {
 
 func TestPermsSyncer_ScheduleRepos(t *testing.T) {
 	s := NewPermsSyncer(nil, nil, nil)
-	s.ScheduleRepos(context.Background(), PriorityHigh, 1)
+	s.ScheduleRepos(context.Background(), 1)
 
 	expHeap := []*syncRequest{
 		{requestMeta: &requestMeta{
@@ -141,18 +142,35 @@ func TestPermsSyncer_syncUserPerms(t *testing.T) {
 			return fmt.Errorf("UserID: want 1 but got %d", p.UserID)
 		}
 
-		expIDs := []uint32{1}
+		expIDs := []uint32{1, 3}
 		if diff := cmp.Diff(expIDs, p.IDs.ToArray()); diff != "" {
 			return fmt.Errorf("IDs: %v", diff)
 		}
 		return nil
 	}
+	// Repository 3 is not covered by an authz provider, so its explicit permission is kept,
+	// whereas repository 2 is no longer authorized by the code host.
+	edb.Mocks.Perms.LoadUserPermissions = func(_ context.Context, p *authz.UserPermissions) error {
+		p.IDs = roaring.BitmapOf(2, 3)
+		return nil
+	}
+	var history *edb.PermsSyncHistory
+	edb.Mocks.Perms.RecordSyncHistory = func(_ context.Context, h *edb.PermsSyncHistory) error {
+		history = h
+		return nil
+	}
 	defer func() {
 		edb.Mocks.Perms = edb.MockPerms{}
 	}()
 
 	reposStore := &mockReposStore{
 		listRepos: func(_ context.Context, args repos.StoreListReposArgs) ([]*repos.Repo, error) {
+			if len(args.IDs) > 0 {
+				return []*repos.Repo{
+					{ID: 2, ExternalRepo: api.ExternalRepoSpec{ServiceID: p.ServiceID()}},
+					{ID: 3, ExternalRepo: api.ExternalRepoSpec{ServiceID: "https://gitolite.example.com/"}},
+				}, nil
+			}
 			if !args.PrivateOnly {
 				return nil, errors.New("PrivateOnly want true but got false")
 			}
@@ -192,6 +210,21 @@ func TestPermsSyncer_syncUserPerms(t *testing.T) {
 			if err != nil {
 				t.Fatal(err)
 			}
+
+			if history == nil {
+				t.Fatal("sync history was not recorded")
+			}
+			expHistory := &edb.PermsSyncHistory{
+				UserID:     1,
+				Providers:  []string{p.ServiceID()},
+				StartedAt:  history.StartedAt,
+				FinishedAt: history.FinishedAt,
+				NumRepos:   2,
+				Partial:    test.fetchErr != nil,
+			}
+			if diff := cmp.Diff(expHistory, history); diff != "" {
+				t.Fatalf("history: %v", diff)
+			}
 		})
 	}
 }
@@ -232,6 +265,11 @@ func TestPermsSyncer_syncRepoPerms(t *testing.T) {
 		}
 		return nil
 	}
+	var history *edb.PermsSyncHistory
+	edb.Mocks.Perms.RecordSyncHistory = func(_ context.Context, h *edb.PermsSyncHistory) error {
+		history = h
+		return nil
+	}
 	defer func() {
 		edb.Mocks.Perms = edb.MockPerms{}
 	}()
@@ -282,6 +320,22 @@ func TestPermsSyncer_syncRepoPerms(t *testing.T) {
 			if err != nil {
 				t.Fatal(err)
 			}
+
+			if history == nil {
+				t.Fatal("sync history was not recorded")
+			}
+			expHistory := &edb.PermsSyncHistory{
+				RepoID:             1,
+				Providers:          []string{p.ServiceID()},
+				StartedAt:          history.StartedAt,
+				FinishedAt:         history.FinishedAt,
+				NumUsers:           1,
+				NumPendingAccounts: 1,
+				Partial:            test.fetchErr != nil,
+			}
+			if diff := cmp.Diff(expHistory, history); diff != "" {
+				t.Fatalf("history: %v", diff)
+			}
 		})
 	}
 }
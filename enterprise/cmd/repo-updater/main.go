@@ -62,6 +62,40 @@ func enterpriseInit(
 	sourcer := repos.NewSourcer(cf)
 	go campaigns.RunWorkers(ctx, campaignsStore, clock, gitserver.DefaultClient, sourcer, 5*time.Second)
 
+	merger := &campaigns.ChangesetMerger{
+		Store:       campaignsStore,
+		ReposStore:  repoStore,
+		HTTPFactory: cf,
+		Clock:       clock,
+		Interval:    time.Minute,
+	}
+	go merger.Run(ctx)
+
+	rebaser := &campaigns.ChangesetRebaser{
+		Store:     campaignsStore,
+		GitClient: gitserver.DefaultClient,
+		Clock:     clock,
+		Interval:  10 * time.Minute,
+	}
+	go rebaser.Run(ctx)
+
+	actionWorker := &campaigns.ChangesetActionWorker{
+		Store:       campaignsStore,
+		ReposStore:  repoStore,
+		HTTPFactory: cf,
+		Clock:       clock,
+		Backoff:     5 * time.Second,
+	}
+	go actionWorker.Run(ctx)
+
+	notifier := &campaigns.CampaignNotifier{
+		Store:       campaignsStore,
+		HTTPFactory: cf,
+		Clock:       clock,
+		Backoff:     5 * time.Second,
+	}
+	go notifier.Run(ctx)
+
 	// Set up expired patch set deletion
 	go func() {
 		for {
@@ -78,6 +112,9 @@ func enterpriseInit(
 	permsStore := frontendDB.NewPermsStore(db, clock)
 	permsSyncer := authz.NewPermsSyncer(repoStore, permsStore, clock)
 	go startBackgroundPermsSync(ctx, permsSyncer, db)
+	if server != nil {
+		server.PermsSyncer = permsSyncer
+	}
 	debugDumpers = append(debugDumpers, permsSyncer)
 
 	return debugDumpers
@@ -0,0 +1,156 @@
+package campaigns
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/inconshreveable/log15"
+	"github.com/opentracing/opentracing-go/log"
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/cmd/repo-updater/repos"
+	"github.com/sourcegraph/sourcegraph/internal/campaigns"
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
+	"github.com/sourcegraph/sourcegraph/internal/trace"
+)
+
+// ChangesetActionWorker performs the pending ChangesetActionJobs, i.e. the
+// bulk actions on the changesets of campaigns.
+type ChangesetActionWorker struct {
+	Store       *Store
+	ReposStore  RepoStore
+	HTTPFactory *httpcli.Factory
+	Clock       func() time.Time
+	// Backoff is the time to wait after an error or when there are no
+	// pending jobs.
+	Backoff time.Duration
+}
+
+// Run performs pending ChangesetActionJobs until ctx is canceled.
+func (w *ChangesetActionWorker) Run(ctx context.Context) {
+	process := func(ctx context.Context, s *Store, job campaigns.ChangesetActionJob) error {
+		if runErr := w.ExecChangesetActionJob(ctx, s, &job); runErr != nil {
+			log15.Warn("ExecChangesetActionJob", "jobID", job.ID, "err", runErr)
+		}
+		// ExecChangesetActionJob saves the error in the job row
+		return nil
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		default:
+			didRun, err := w.Store.ProcessPendingChangesetActionJobs(context.Background(), process)
+			if err != nil {
+				log15.Error("Running changeset action job", "err", err)
+			}
+			// Back off on error or when no jobs available
+			if err != nil || !didRun {
+				time.Sleep(w.Backoff)
+			}
+		}
+	}
+}
+
+// ExecChangesetActionJob performs the ChangesetAction of the given job on its
+// Changeset, saves the outcome in the job and syncs the Changeset.
+func (w *ChangesetActionWorker) ExecChangesetActionJob(ctx context.Context, store *Store, job *campaigns.ChangesetActionJob) (err error) {
+	tr, ctx := trace.New(ctx, "ChangesetActionWorker.ExecChangesetActionJob", fmt.Sprintf("job_id: %d", job.ID))
+	defer func() {
+		tr.SetError(err)
+		tr.Finish()
+	}()
+	tr.LogFields(log.Int64("job_id", job.ID), log.Int64("changeset_id", job.ChangesetID))
+
+	if !job.FinishedAt.IsZero() {
+		log15.Info("ChangesetActionJob already finished", "id", job.ID)
+		return nil
+	}
+
+	defer func() {
+		if err != nil {
+			job.Error = err.Error()
+		}
+		job.FinishedAt = w.Clock()
+
+		if e := store.UpdateChangesetActionJob(ctx, job); e != nil {
+			if err == nil {
+				err = e
+			} else {
+				err = multierror.Append(err, e)
+			}
+		}
+	}()
+
+	job.StartedAt = w.Clock()
+
+	a, err := store.GetChangesetAction(ctx, GetChangesetActionOpts{ID: job.ChangesetActionID})
+	if err != nil {
+		return errors.Wrap(err, "getting changeset action")
+	}
+
+	c, err := store.GetChangeset(ctx, GetChangesetOpts{ID: job.ChangesetID})
+	if err != nil {
+		return errors.Wrap(err, "getting changeset")
+	}
+
+	bySource, err := GroupChangesetsBySource(ctx, w.ReposStore, w.HTTPFactory, c)
+	if err != nil {
+		return err
+	}
+	if len(bySource) != 1 || len(bySource[0].Changesets) != 1 {
+		return errors.Errorf("no code host connection found for changeset %d", c.ID)
+	}
+
+	src := bySource[0]
+	if err = performChangesetAction(ctx, src.ChangesetSource, src.Changesets[0], a); err != nil {
+		return err
+	}
+
+	// Sync the changeset so that the outcome of the action, e.g. new labels
+	// or the closed state, shows up right away. A failed sync doesn't fail the
+	// job, since the action itself succeeded and the ChangesetSyncer syncs
+	// the changeset again later.
+	if err := SyncChangesetsWithSources(ctx, store, bySource); err != nil {
+		log15.Warn("Syncing changeset after changeset action", "changeset", c.ID, "err", err)
+	}
+
+	return nil
+}
+
+// performChangesetAction performs the given ChangesetAction on the given
+// Changeset with the given ChangesetSource.
+func performChangesetAction(ctx context.Context, src repos.ChangesetSource, c *repos.Changeset, a *campaigns.ChangesetAction) error {
+	switch a.Type {
+	case campaigns.ChangesetActionTypeComment:
+		return errors.Wrap(src.CreateComment(ctx, c, a.Body), "creating comment")
+
+	case campaigns.ChangesetActionTypeAddLabels:
+		return errors.Wrap(src.AddLabels(ctx, c, a.Labels), "adding labels")
+
+	case campaigns.ChangesetActionTypeRemoveLabels:
+		return errors.Wrap(src.RemoveLabels(ctx, c, a.Labels), "removing labels")
+
+	case campaigns.ChangesetActionTypeRequestReviewers:
+		return errors.Wrap(src.RequestReviewers(ctx, c, a.Reviewers), "requesting reviewers")
+
+	case campaigns.ChangesetActionTypeClose:
+		if c.ExternalState != campaigns.ChangesetStateOpen {
+			return nil
+		}
+		if a.Body != "" {
+			if err := src.CreateComment(ctx, c, a.Body); err != nil {
+				return errors.Wrap(err, "creating comment")
+			}
+		}
+		return errors.Wrap(src.CloseChangeset(ctx, c), "closing changeset")
+
+	case campaigns.ChangesetActionTypeRerunFailedChecks:
+		return errors.Wrap(src.RerunFailedChecks(ctx, c), "re-running failed checks")
+
+	default:
+		return errors.Errorf("unknown changeset action type %q", a.Type)
+	}
+}
@@ -0,0 +1,129 @@
+package campaigns
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sourcegraph/sourcegraph/cmd/repo-updater/repos"
+	"github.com/sourcegraph/sourcegraph/internal/campaigns"
+)
+
+func TestPerformChangesetAction(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		action campaigns.ChangesetAction
+		state  campaigns.ChangesetState
+		calls  []string
+		err    string
+	}{
+		{
+			name:   "comment",
+			action: campaigns.ChangesetAction{Type: campaigns.ChangesetActionTypeComment, Body: "ping"},
+			calls:  []string{"CreateComment(ping)"},
+		},
+		{
+			name:   "add labels",
+			action: campaigns.ChangesetAction{Type: campaigns.ChangesetActionTypeAddLabels, Labels: []string{"a", "b"}},
+			calls:  []string{"AddLabels([a b])"},
+		},
+		{
+			name:   "remove labels",
+			action: campaigns.ChangesetAction{Type: campaigns.ChangesetActionTypeRemoveLabels, Labels: []string{"a"}},
+			calls:  []string{"RemoveLabels([a])"},
+		},
+		{
+			name:   "request reviewers",
+			action: campaigns.ChangesetAction{Type: campaigns.ChangesetActionTypeRequestReviewers, Reviewers: []string{"alice", "org/team"}},
+			calls:  []string{"RequestReviewers([alice org/team])"},
+		},
+		{
+			name:   "close with comment",
+			action: campaigns.ChangesetAction{Type: campaigns.ChangesetActionTypeClose, Body: "bye"},
+			calls:  []string{"CreateComment(bye)", "CloseChangeset"},
+		},
+		{
+			name:   "close without comment",
+			action: campaigns.ChangesetAction{Type: campaigns.ChangesetActionTypeClose},
+			calls:  []string{"CloseChangeset"},
+		},
+		{
+			name:   "close already merged",
+			action: campaigns.ChangesetAction{Type: campaigns.ChangesetActionTypeClose, Body: "bye"},
+			state:  campaigns.ChangesetStateMerged,
+		},
+		{
+			name:   "rerun failed checks",
+			action: campaigns.ChangesetAction{Type: campaigns.ChangesetActionTypeRerunFailedChecks},
+			calls:  []string{"RerunFailedChecks"},
+		},
+		{
+			name:   "unsupported",
+			action: campaigns.ChangesetAction{Type: campaigns.ChangesetActionTypeAddLabels, Labels: []string{"a"}},
+			calls:  []string{"AddLabels([a])"},
+			err:    "adding labels: action not supported by code host",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			src := &recordingChangesetSource{}
+			if tc.err != "" {
+				src.err = repos.ErrChangesetActionNotSupported
+			}
+
+			if tc.state == "" {
+				tc.state = campaigns.ChangesetStateOpen
+			}
+			c := &repos.Changeset{Changeset: &campaigns.Changeset{ExternalState: tc.state}}
+
+			err := performChangesetAction(context.Background(), src, c, &tc.action)
+			if tc.err == "" {
+				tc.err = "<nil>"
+			}
+			if have, want := fmt.Sprint(err), tc.err; have != want {
+				t.Fatalf("have error %q, want %q", have, want)
+			}
+
+			if diff := cmp.Diff(tc.calls, src.calls); diff != "" {
+				t.Fatal(diff)
+			}
+		})
+	}
+}
+
+// recordingChangesetSource records the calls of the ChangesetSource methods
+// that perform changeset actions and returns err from each of them.
+type recordingChangesetSource struct {
+	repos.ChangesetSource
+	calls []string
+	err   error
+}
+
+func (s *recordingChangesetSource) record(call string) error {
+	s.calls = append(s.calls, call)
+	return s.err
+}
+
+func (s *recordingChangesetSource) CreateComment(_ context.Context, _ *repos.Changeset, body string) error {
+	return s.record(fmt.Sprintf("CreateComment(%s)", body))
+}
+
+func (s *recordingChangesetSource) AddLabels(_ context.Context, _ *repos.Changeset, labels []string) error {
+	return s.record(fmt.Sprintf("AddLabels(%v)", labels))
+}
+
+func (s *recordingChangesetSource) RemoveLabels(_ context.Context, _ *repos.Changeset, labels []string) error {
+	return s.record(fmt.Sprintf("RemoveLabels(%v)", labels))
+}
+
+func (s *recordingChangesetSource) RequestReviewers(_ context.Context, _ *repos.Changeset, reviewers []string) error {
+	return s.record(fmt.Sprintf("RequestReviewers(%v)", reviewers))
+}
+
+func (s *recordingChangesetSource) CloseChangeset(context.Context, *repos.Changeset) error {
+	return s.record("CloseChangeset")
+}
+
+func (s *recordingChangesetSource) RerunFailedChecks(context.Context, *repos.Changeset) error {
+	return s.record("RerunFailedChecks")
+}
@@ -0,0 +1,196 @@
+package campaigns
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"os"
+	"strings"
+
+	"github.com/gobwas/glob"
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/go-diff/diff"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/campaigns"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
+)
+
+// codeOwnersPaths are the locations of CODEOWNERS files in a repository, in
+// the order in which GitHub looks for them.
+var codeOwnersPaths = []string{".github/CODEOWNERS", "CODEOWNERS", "docs/CODEOWNERS"}
+
+// maxCodeOwnersBytes is the size up to which CODEOWNERS files are read.
+const maxCodeOwnersBytes = 1 << 20
+
+// codeOwners is a parsed CODEOWNERS file.
+type codeOwners []codeOwnersRule
+
+// codeOwnersRule is a single line of a CODEOWNERS file.
+type codeOwnersRule struct {
+	globs  []glob.Glob
+	owners []string
+}
+
+// parseCodeOwners parses the given CODEOWNERS file. Owners are returned as
+// code host usernames without the leading "@", teams as "org/team". Owners
+// given by email address are skipped, since they can't be requested as
+// reviewers or assigned by name.
+func parseCodeOwners(data []byte) (codeOwners, error) {
+	var rules codeOwners
+
+	s := bufio.NewScanner(bytes.NewReader(data))
+	for s.Scan() {
+		line := strings.TrimSpace(s.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		globs, err := compileCodeOwnersPattern(fields[0])
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid CODEOWNERS pattern %q", fields[0])
+		}
+
+		rule := codeOwnersRule{globs: globs}
+		for _, owner := range fields[1:] {
+			if strings.HasPrefix(owner, "#") {
+				break
+			}
+			if !strings.HasPrefix(owner, "@") {
+				continue
+			}
+			rule.owners = append(rule.owners, strings.TrimPrefix(owner, "@"))
+		}
+		rules = append(rules, rule)
+	}
+
+	return rules, s.Err()
+}
+
+// compileCodeOwnersPattern compiles a CODEOWNERS pattern, which follows the
+// rules of .gitignore patterns, into globs of which at least one matches a
+// file path if the pattern matches it.
+func compileCodeOwnersPattern(pattern string) ([]glob.Glob, error) {
+	dir := strings.HasSuffix(pattern, "/")
+	pattern = strings.TrimSuffix(pattern, "/")
+
+	// Patterns that contain a slash other than a trailing one are relative to
+	// the root of the repository, all others match at any depth.
+	anchored := strings.Contains(pattern, "/")
+	pattern = strings.TrimPrefix(pattern, "/")
+
+	bases := []string{pattern}
+	if !anchored {
+		bases = append(bases, "**/"+pattern)
+	}
+
+	// A pattern matches files as well as everything in the directories it
+	// matches, unless it only matches directories. Patterns ending in "/*",
+	// such as "docs/*", only match the files directly in a directory.
+	nested := !strings.HasSuffix(pattern, "/*")
+
+	var patterns []string
+	for _, base := range bases {
+		if !dir {
+			patterns = append(patterns, base)
+		}
+		if nested {
+			patterns = append(patterns, base+"/**")
+		}
+	}
+
+	globs := make([]glob.Glob, 0, len(patterns))
+	for _, p := range patterns {
+		g, err := glob.Compile(p, '/')
+		if err != nil {
+			return nil, err
+		}
+		globs = append(globs, g)
+	}
+	return globs, nil
+}
+
+// Owners returns the owners of the file at the given path. The last rule that
+// matches the path wins.
+func (o codeOwners) Owners(path string) []string {
+	path = strings.TrimPrefix(path, "/")
+	for i := len(o) - 1; i >= 0; i-- {
+		for _, g := range o[i].globs {
+			if g.Match(path) {
+				return o[i].owners
+			}
+		}
+	}
+	return nil
+}
+
+// changesetCodeOwners returns the owners of the files changed by the diff, as
+// defined by the CODEOWNERS file of the repository at the given commit. It
+// returns no owners if the repository has no CODEOWNERS file.
+func changesetCodeOwners(ctx context.Context, repo api.RepoName, commit api.CommitID, rawDiff string) ([]string, error) {
+	var data []byte
+	for _, name := range codeOwnersPaths {
+		var err error
+		data, err = git.ReadFile(ctx, gitserver.Repo{Name: repo}, commit, name, maxCodeOwnersBytes)
+		if err == nil {
+			break
+		}
+		if !os.IsNotExist(err) {
+			return nil, errors.Wrapf(err, "reading %s", name)
+		}
+	}
+	if data == nil {
+		return nil, nil
+	}
+
+	owners, err := parseCodeOwners(data)
+	if err != nil {
+		return nil, err
+	}
+
+	fileDiffs, err := diff.ParseMultiFileDiff([]byte(rawDiff))
+	if err != nil {
+		return nil, errors.Wrap(err, "parsing diff")
+	}
+
+	var result []string
+	seen := map[string]bool{}
+	for _, fd := range fileDiffs {
+		name := fd.NewName
+		if name == "/dev/null" {
+			name = fd.OrigName
+		}
+		for _, owner := range owners.Owners(name) {
+			if !seen[owner] {
+				seen[owner] = true
+				result = append(result, owner)
+			}
+		}
+	}
+	return result, nil
+}
+
+// changesetUsers returns the users the rule determines for a changeset. The
+// owners of the changed files are only computed, by calling owners, if the
+// rule asks for them.
+func changesetUsers(rule campaigns.ChangesetUsersRule, owners func() ([]string, error)) ([]string, error) {
+	users := append([]string(nil), rule.Users...)
+	if rule.FromCodeOwners {
+		owners, err := owners()
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, owners...)
+	}
+
+	seen := make(map[string]bool, len(users))
+	deduped := users[:0]
+	for _, u := range users {
+		if !seen[u] {
+			seen[u] = true
+			deduped = append(deduped, u)
+		}
+	}
+	return deduped, nil
+}
@@ -0,0 +1,149 @@
+package campaigns
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/campaigns"
+	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
+)
+
+const testCodeOwners = `
+# Default owners
+*                   @sourcegraph/core
+
+*.go                @gopher # Go code
+/docs/              @writer docs@sourcegraph.com
+cmd/frontend/       @frontend @sourcegraph/web
+internal/*          @internal
+/build              @release
+`
+
+func TestCodeOwners(t *testing.T) {
+	owners, err := parseCodeOwners([]byte(testCodeOwners))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		path string
+		want []string
+	}{
+		{path: "README.md", want: []string{"sourcegraph/core"}},
+		{path: "main.go", want: []string{"gopher"}},
+		{path: "internal/campaigns/types.go", want: []string{"gopher"}},
+		{path: "docs/index.md", want: []string{"writer"}},
+		{path: "docs/user/campaigns.md", want: []string{"writer"}},
+		{path: "src/docs/index.md", want: []string{"sourcegraph/core"}},
+		{path: "cmd/frontend/main.ts", want: []string{"frontend", "sourcegraph/web"}},
+		{path: "internal/README.md", want: []string{"internal"}},
+		{path: "internal/campaigns/README.md", want: []string{"sourcegraph/core"}},
+		{path: "build", want: []string{"release"}},
+		{path: "build/Dockerfile", want: []string{"release"}},
+		{path: "/build/Dockerfile", want: []string{"release"}},
+		{path: "cmd/build", want: []string{"sourcegraph/core"}},
+	} {
+		t.Run(tc.path, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want, owners.Owners(tc.path)); diff != "" {
+				t.Fatal(diff)
+			}
+		})
+	}
+}
+
+func TestChangesetCodeOwners(t *testing.T) {
+	const rawDiff = `diff README.md README.md
+index 671e50a..851b23a 100644
+--- README.md
++++ README.md
+@@ -1 +1 @@
+-# README
++# Sourcegraph
+diff cmd/frontend/main.go cmd/frontend/main.go
+deleted file mode 100644
+index 8d1c8b6..0000000
+--- cmd/frontend/main.go
++++ /dev/null
+@@ -1 +0,0 @@
+-package main
+`
+
+	defer git.ResetMocks()
+
+	t.Run("CODEOWNERS", func(t *testing.T) {
+		git.Mocks.ReadFile = func(commit api.CommitID, name string) ([]byte, error) {
+			if name != ".github/CODEOWNERS" {
+				return nil, &os.PathError{Op: "open", Path: name, Err: os.ErrNotExist}
+			}
+			return []byte(testCodeOwners), nil
+		}
+
+		have, err := changesetCodeOwners(context.Background(), "github.com/sourcegraph/sourcegraph", "deadbeef", rawDiff)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		want := []string{"sourcegraph/core", "frontend", "sourcegraph/web"}
+		if diff := cmp.Diff(want, have); diff != "" {
+			t.Fatal(diff)
+		}
+	})
+
+	t.Run("no CODEOWNERS", func(t *testing.T) {
+		git.Mocks.ReadFile = func(commit api.CommitID, name string) ([]byte, error) {
+			return nil, &os.PathError{Op: "open", Path: name, Err: os.ErrNotExist}
+		}
+
+		have, err := changesetCodeOwners(context.Background(), "github.com/sourcegraph/sourcegraph", "deadbeef", rawDiff)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(have) != 0 {
+			t.Fatalf("want no owners, have %v", have)
+		}
+	})
+}
+
+func TestChangesetUsers(t *testing.T) {
+	owners := func() ([]string, error) {
+		return []string{"gopher", "sourcegraph/core"}, nil
+	}
+
+	for _, tc := range []struct {
+		name string
+		rule campaigns.ChangesetUsersRule
+		want []string
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name: "users",
+			rule: campaigns.ChangesetUsersRule{Users: []string{"alice", "bob"}},
+			want: []string{"alice", "bob"},
+		},
+		{
+			name: "code owners",
+			rule: campaigns.ChangesetUsersRule{FromCodeOwners: true},
+			want: []string{"gopher", "sourcegraph/core"},
+		},
+		{
+			name: "users and code owners",
+			rule: campaigns.ChangesetUsersRule{Users: []string{"gopher", "alice"}, FromCodeOwners: true},
+			want: []string{"gopher", "alice", "sourcegraph/core"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			have, err := changesetUsers(tc.rule, owners)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tc.want, have); diff != "" {
+				t.Fatal(diff)
+			}
+		})
+	}
+}
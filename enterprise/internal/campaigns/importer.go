@@ -0,0 +1,233 @@
+package campaigns
+
+import (
+	"context"
+	"strings"
+
+	"github.com/inconshreveable/log15"
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/cmd/repo-updater/repos"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/campaigns"
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
+)
+
+// A ChangesetImporter searches the code host of a ChangesetImportQuery for
+// existing changesets and adds the ones it finds to the query's campaign as
+// tracked changesets.
+type ChangesetImporter struct {
+	SyncStore   SyncStore
+	ReposStore  RepoStore
+	HTTPFactory *httpcli.Factory
+}
+
+// Import runs the given ChangesetImportQuery and returns the changesets that
+// were added to its campaign. Changesets that are already tracked are synced
+// by the ChangesetSyncer as usual and are not returned.
+func (i *ChangesetImporter) Import(ctx context.Context, q *campaigns.ChangesetImportQuery) (added []*campaigns.Changeset, err error) {
+	svc, err := i.externalService(ctx, q.ExternalServiceID)
+	if err != nil {
+		return nil, err
+	}
+
+	found, err := i.search(ctx, svc, q.Query)
+	if err != nil {
+		return nil, err
+	}
+
+	cs, err := i.changesets(ctx, svc, found)
+	if err != nil || len(cs) == 0 {
+		return nil, err
+	}
+
+	tx, err := i.SyncStore.Transact(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Done(&err)
+
+	campaign, err := tx.GetCampaign(ctx, GetCampaignOpts{ID: q.CampaignID})
+	if err != nil {
+		return nil, err
+	}
+
+	if campaign.PatchSetID != 0 {
+		return nil, errors.New("Changesets can only be imported into campaigns that don't create their own changesets")
+	}
+
+	// Closed campaigns don't pick up new changesets.
+	if !campaign.ClosedAt.IsZero() {
+		return nil, nil
+	}
+
+	existing := map[int64]bool{}
+	if err = tx.CreateChangesets(ctx, cs...); err != nil {
+		e, ok := err.(AlreadyExistError)
+		if !ok {
+			return nil, err
+		}
+		for _, id := range e.ChangesetIDs {
+			existing[id] = true
+		}
+	}
+
+	var created []*campaigns.Changeset
+	for _, c := range cs {
+		if !existing[c.ID] {
+			created = append(created, c)
+		}
+	}
+
+	syncer := ChangesetSyncer{
+		ReposStore:  i.ReposStore,
+		SyncStore:   tx,
+		HTTPFactory: i.HTTPFactory,
+	}
+	// NOTE: We are performing a blocking sync here, just like when
+	// changesets are created through the API, so that no unsynced changeset
+	// enters our database.
+	if err = syncer.SyncChangesets(ctx, created...); err != nil {
+		return nil, errors.Wrap(err, "syncing changesets")
+	}
+
+	for _, c := range cs {
+		if !c.HasCampaignID(campaign.ID) {
+			c.CampaignIDs = append(c.CampaignIDs, campaign.ID)
+			added = append(added, c)
+		}
+	}
+
+	if len(added) == 0 {
+		return nil, nil
+	}
+
+	if err = tx.UpdateChangesets(ctx, added...); err != nil {
+		return nil, err
+	}
+
+	for _, c := range added {
+		campaign.ChangesetIDs = append(campaign.ChangesetIDs, c.ID)
+	}
+
+	if err = tx.UpdateCampaign(ctx, campaign); err != nil {
+		return nil, err
+	}
+
+	return added, nil
+}
+
+// externalService returns the external service with the given ID, if
+// changesets can be imported from it.
+func (i *ChangesetImporter) externalService(ctx context.Context, id int64) (*repos.ExternalService, error) {
+	es, err := i.ReposStore.ListExternalServices(ctx, repos.StoreListExternalServicesArgs{
+		IDs: []int64{id},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(es) == 0 {
+		return nil, errors.Errorf("external service %d not found", id)
+	}
+
+	if !isKindSupported(es[0].Kind) {
+		return nil, errors.Errorf("importing changesets from %q is not supported", es[0].Kind)
+	}
+
+	return es[0], nil
+}
+
+// isKindSupported returns true if the external service kind is supported by
+// campaigns.
+func isKindSupported(kind string) bool {
+	for t := range campaigns.SupportedExternalServices {
+		if strings.EqualFold(t, kind) {
+			return true
+		}
+	}
+	return false
+}
+
+// search runs the query on the code host of the external service.
+func (i *ChangesetImporter) search(ctx context.Context, svc *repos.ExternalService, query string) ([]*repos.Changeset, error) {
+	src, err := repos.NewSource(svc, i.HTTPFactory)
+	if err != nil {
+		return nil, err
+	}
+
+	searcher, ok := src.(repos.ChangesetSearcher)
+	if !ok {
+		return nil, errors.Errorf("importing changesets from %q is not supported", svc.Kind)
+	}
+
+	return searcher.SearchChangesets(ctx, query)
+}
+
+// changesets returns a Changeset for each of the found changesets whose
+// repository is in the database, synced from the given external service and
+// supported by campaigns.
+func (i *ChangesetImporter) changesets(ctx context.Context, svc *repos.ExternalService, found []*repos.Changeset) ([]*campaigns.Changeset, error) {
+	if len(found) == 0 {
+		return nil, nil
+	}
+
+	specs := make([]api.ExternalRepoSpec, 0, len(found))
+	seen := make(map[api.ExternalRepoSpec]bool, len(found))
+	for _, c := range found {
+		if spec := c.Repo.ExternalRepo; !seen[spec] {
+			seen[spec] = true
+			specs = append(specs, spec)
+		}
+	}
+
+	rs, err := i.ReposStore.ListRepos(ctx, repos.StoreListReposArgs{ExternalRepos: specs})
+	if err != nil {
+		return nil, err
+	}
+
+	byExternalRepo := make(map[api.ExternalRepoSpec]*repos.Repo, len(rs))
+	for _, r := range rs {
+		byExternalRepo[r.ExternalRepo] = r
+	}
+
+	type key struct {
+		repoID     api.RepoID
+		externalID string
+	}
+
+	cs := make([]*campaigns.Changeset, 0, len(found))
+	imported := make(map[key]bool, len(found))
+	for _, c := range found {
+		r, ok := byExternalRepo[c.Repo.ExternalRepo]
+		if !ok {
+			log15.Debug("changeset not imported, repo not in database", "repo", c.Repo.Name, "external_id", c.ExternalID)
+			continue
+		}
+
+		if !campaigns.IsRepoSupported(&r.ExternalRepo) {
+			continue
+		}
+
+		// 🚨 SECURITY: Only changesets of repositories of the external
+		// service the query was run against may be imported, since the
+		// search results are not trusted to belong to it.
+		if _, ok := r.Sources[svc.URN()]; !ok {
+			log15.Debug("changeset not imported, repo not synced from external service", "repo", r.Name, "external_service", svc.ID)
+			continue
+		}
+
+		k := key{repoID: r.ID, externalID: c.ExternalID}
+		if imported[k] {
+			continue
+		}
+		imported[k] = true
+
+		cs = append(cs, &campaigns.Changeset{
+			RepoID:              r.ID,
+			ExternalID:          c.ExternalID,
+			ExternalServiceType: r.ExternalRepo.ServiceType,
+		})
+	}
+
+	return cs, nil
+}
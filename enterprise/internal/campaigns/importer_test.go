@@ -0,0 +1,124 @@
+package campaigns
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sourcegraph/sourcegraph/cmd/repo-updater/repos"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/campaigns"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/github"
+)
+
+func TestChangesetImporterChangesets(t *testing.T) {
+	svc := &repos.ExternalService{ID: 1, Kind: "GITHUB"}
+	otherSvc := &repos.ExternalService{ID: 2, Kind: "GITHUB"}
+
+	githubRepo := &repos.Repo{
+		ID:   1,
+		Name: "github.com/sourcegraph/sourcegraph",
+		ExternalRepo: api.ExternalRepoSpec{
+			ID:          "MDEwOlJlcG9zaXRvcnkxNDE=",
+			ServiceType: github.ServiceType,
+			ServiceID:   "https://github.com/",
+		},
+		Sources: map[string]*repos.SourceInfo{svc.URN(): {ID: svc.URN()}},
+	}
+	otherServiceRepo := &repos.Repo{
+		ID:   3,
+		Name: "github.example.com/sourcegraph/sourcegraph",
+		ExternalRepo: api.ExternalRepoSpec{
+			ID:          "MDEwOlJlcG9zaXRvcnkxNDI=",
+			ServiceType: github.ServiceType,
+			ServiceID:   "https://github.example.com/",
+		},
+		Sources: map[string]*repos.SourceInfo{otherSvc.URN(): {ID: otherSvc.URN()}},
+	}
+	unsupportedRepo := &repos.Repo{
+		ID:   2,
+		Name: "gitlab.com/sourcegraph/sourcegraph",
+		ExternalRepo: api.ExternalRepoSpec{
+			ID:          "42",
+			ServiceType: "gitlab",
+			ServiceID:   "https://gitlab.com/",
+		},
+		Sources: map[string]*repos.SourceInfo{svc.URN(): {ID: svc.URN()}},
+	}
+	unknownRepo := &repos.Repo{
+		Name: "github.com/sourcegraph/unknown",
+		ExternalRepo: api.ExternalRepoSpec{
+			ID:          "MDEwOlJlcG9zaXRvcnk0Mg==",
+			ServiceType: github.ServiceType,
+			ServiceID:   "https://github.com/",
+		},
+	}
+
+	var listed []api.ExternalRepoSpec
+	importer := &ChangesetImporter{
+		ReposStore: MockRepoStore{
+			listRepos: func(ctx context.Context, args repos.StoreListReposArgs) ([]*repos.Repo, error) {
+				listed = args.ExternalRepos
+				return []*repos.Repo{githubRepo, unsupportedRepo, otherServiceRepo}, nil
+			},
+		},
+	}
+
+	found := []*repos.Changeset{
+		{Changeset: &campaigns.Changeset{ExternalID: "1"}, Repo: githubRepo},
+		{Changeset: &campaigns.Changeset{ExternalID: "2"}, Repo: githubRepo},
+		// Search results can contain the same changeset more than once.
+		{Changeset: &campaigns.Changeset{ExternalID: "1"}, Repo: githubRepo},
+		{Changeset: &campaigns.Changeset{ExternalID: "3"}, Repo: unsupportedRepo},
+		{Changeset: &campaigns.Changeset{ExternalID: "4"}, Repo: unknownRepo},
+		// Changesets of repositories of other external services are ignored.
+		{Changeset: &campaigns.Changeset{ExternalID: "5"}, Repo: otherServiceRepo},
+	}
+
+	have, err := importer.changesets(context.Background(), svc, found)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []*campaigns.Changeset{
+		{RepoID: 1, ExternalID: "1", ExternalServiceType: github.ServiceType},
+		{RepoID: 1, ExternalID: "2", ExternalServiceType: github.ServiceType},
+	}
+	if diff := cmp.Diff(want, have); diff != "" {
+		t.Fatal(diff)
+	}
+
+	wantListed := []api.ExternalRepoSpec{githubRepo.ExternalRepo, unsupportedRepo.ExternalRepo, unknownRepo.ExternalRepo, otherServiceRepo.ExternalRepo}
+	if diff := cmp.Diff(wantListed, listed); diff != "" {
+		t.Fatalf("listed repos: %s", diff)
+	}
+}
+
+func TestChangesetImporterExternalService(t *testing.T) {
+	for _, tc := range []struct {
+		kind    string
+		wantErr string
+	}{
+		{kind: "GITHUB"},
+		{kind: "BITBUCKETSERVER"},
+		{kind: "GITLAB", wantErr: `importing changesets from "GITLAB" is not supported`},
+	} {
+		t.Run(tc.kind, func(t *testing.T) {
+			importer := &ChangesetImporter{
+				ReposStore: MockRepoStore{
+					listExternalServices: func(ctx context.Context, args repos.StoreListExternalServicesArgs) ([]*repos.ExternalService, error) {
+						return []*repos.ExternalService{{ID: 1, Kind: tc.kind}}, nil
+					},
+				},
+			}
+
+			_, err := importer.externalService(context.Background(), 1)
+			if have := fmt.Sprint(err); tc.wantErr != "" && have != tc.wantErr {
+				t.Fatalf("wrong error. want=%q, have=%q", tc.wantErr, have)
+			} else if tc.wantErr == "" && err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
@@ -0,0 +1,249 @@
+package campaigns
+
+import (
+	"context"
+	"time"
+
+	"github.com/inconshreveable/log15"
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/campaigns"
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
+)
+
+// mergeRetryInterval is the time after a failed merge attempt during which
+// a Changeset is not merged again.
+const mergeRetryInterval = time.Hour
+
+// ChangesetMerger periodically merges the changesets of campaigns with
+// AutoMerge enabled that are open, approved and have passing checks.
+type ChangesetMerger struct {
+	Store       *Store
+	ReposStore  RepoStore
+	HTTPFactory *httpcli.Factory
+	Clock       func() time.Time
+	// Interval is the time between two runs of MergeReady.
+	Interval time.Duration
+}
+
+// Run merges ready changesets every Interval until ctx is canceled.
+func (m *ChangesetMerger) Run(ctx context.Context) {
+	t := time.NewTicker(m.Interval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			if err := m.MergeReady(ctx); err != nil {
+				log15.Error("Merging ready changesets", "err", err)
+			}
+		}
+	}
+}
+
+// MergeReady merges the ready changesets of all open campaigns with
+// AutoMerge enabled, respecting the MergesPerHour limit of each campaign.
+func (m *ChangesetMerger) MergeReady(ctx context.Context) error {
+	opts := ListCampaignsOpts{
+		State:     campaigns.CampaignStateOpen,
+		AutoMerge: true,
+		Limit:     100,
+	}
+
+	for {
+		cs, next, err := m.Store.ListCampaigns(ctx, opts)
+		if err != nil {
+			return errors.Wrap(err, "listing campaigns")
+		}
+
+		for _, c := range cs {
+			if err := m.mergeCampaign(ctx, c); err != nil {
+				log15.Error("Merging changesets of campaign", "campaign", c.ID, "err", err)
+			}
+		}
+
+		if next == 0 {
+			return nil
+		}
+		opts.Cursor = next
+	}
+}
+
+func (m *ChangesetMerger) mergeCampaign(ctx context.Context, c *campaigns.Campaign) error {
+	now := m.Clock()
+
+	merged, err := m.Store.CountChangesetEvents(ctx, CountChangesetEventsOpts{
+		CampaignID:   c.ID,
+		Kind:         campaigns.ChangesetEventKindMergeSucceeded,
+		CreatedAfter: now.Add(-time.Hour),
+	})
+	if err != nil {
+		return errors.Wrap(err, "counting merges")
+	}
+
+	budget := mergeBudget(c.MergesPerHour, merged)
+	if budget == 0 {
+		return nil
+	}
+
+	var (
+		open     = campaigns.ChangesetStateOpen
+		approved = campaigns.ChangesetReviewStateApproved
+		passed   = campaigns.ChangesetCheckStatePassed
+	)
+
+	ready, _, err := m.Store.ListChangesets(ctx, ListChangesetsOpts{
+		CampaignID:          c.ID,
+		Limit:               -1,
+		WithoutDeleted:      true,
+		ExternalState:       &open,
+		ExternalReviewState: &approved,
+		ExternalCheckState:  &passed,
+	})
+	if err != nil {
+		return errors.Wrap(err, "listing ready changesets")
+	}
+
+	// Don't retry changesets whose merge failed recently, since the reason,
+	// e.g. a merge conflict, is unlikely to have gone away.
+	recentlyFailed := func(ch *campaigns.Changeset) (bool, error) {
+		failed, err := m.Store.CountChangesetEvents(ctx, CountChangesetEventsOpts{
+			ChangesetID:  ch.ID,
+			Kind:         campaigns.ChangesetEventKindMergeFailed,
+			CreatedAfter: now.Add(-mergeRetryInterval),
+		})
+		if err != nil {
+			return false, errors.Wrap(err, "counting failed merges")
+		}
+		return failed > 0, nil
+	}
+
+	toMerge, err := mergeQueue(ready, budget, recentlyFailed)
+	if err != nil {
+		return err
+	}
+
+	if len(toMerge) == 0 {
+		return nil
+	}
+
+	bySource, err := GroupChangesetsBySource(ctx, m.ReposStore, m.HTTPFactory, toMerge...)
+	if err != nil {
+		return err
+	}
+
+	return mergeChangesets(ctx, m.Store, m.Clock, mergeMethod(c), bySource)
+}
+
+// mergeChangesets merges the given changesets with the given method, records
+// each attempt as a ChangesetEvent and syncs the merged changesets.
+func mergeChangesets(ctx context.Context, store *Store, clock func() time.Time, method campaigns.ChangesetMergeMethod, bySource []*SourceChangesets) error {
+	var (
+		events []*campaigns.ChangesetEvent
+		merged []*SourceChangesets
+	)
+
+	for _, s := range bySource {
+		done := &SourceChangesets{ChangesetSource: s.ChangesetSource}
+
+		for _, c := range s.Changesets {
+			attempt := &campaigns.ChangesetMergeAttempt{
+				Method:      method,
+				AttemptedAt: clock(),
+			}
+
+			if err := s.MergeChangeset(ctx, c, method); err != nil {
+				log15.Warn("Merging changeset failed", "changeset", c.Changeset.ID, "err", err)
+				attempt.Error = err.Error()
+			} else {
+				done.Changesets = append(done.Changesets, c)
+			}
+
+			events = append(events, &campaigns.ChangesetEvent{
+				ChangesetID: c.Changeset.ID,
+				Kind:        campaigns.ChangesetEventKindFor(attempt),
+				Key:         attempt.Key(),
+				CreatedAt:   attempt.AttemptedAt,
+				UpdatedAt:   attempt.AttemptedAt,
+				Metadata:    attempt,
+			})
+		}
+
+		if len(done.Changesets) > 0 {
+			merged = append(merged, done)
+		}
+	}
+
+	if err := store.UpsertChangesetEvents(ctx, events...); err != nil {
+		return errors.Wrap(err, "recording merge attempts")
+	}
+
+	if len(merged) == 0 {
+		return nil
+	}
+
+	// Sync the merged changesets so their state and the events created by
+	// the merge on the code host are up to date.
+	return SyncChangesetsWithSources(ctx, store, merged)
+}
+
+// mergeQueue returns the changesets of ready, which are ordered by ID, that
+// are merged next: the first changeset of each repository that isn't skipped,
+// up to budget changesets in total (-1 meaning no limit).
+//
+// Merging a changeset moves the base branch of the other changesets of the
+// same repository, whose checks then have to run against the new base again.
+// Merging only one changeset per repository at a time makes the changesets of
+// a repository queue up behind each other, with each one being merged in a
+// later run once its state has been synced again.
+func mergeQueue(ready []*campaigns.Changeset, budget int, skip func(*campaigns.Changeset) (bool, error)) ([]*campaigns.Changeset, error) {
+	var (
+		queue  []*campaigns.Changeset
+		queued = make(map[api.RepoID]bool)
+	)
+
+	for _, ch := range ready {
+		if budget >= 0 && len(queue) == budget {
+			break
+		}
+
+		if queued[ch.RepoID] {
+			continue
+		}
+
+		skipped, err := skip(ch)
+		if err != nil {
+			return nil, err
+		}
+		if !skipped {
+			queue = append(queue, ch)
+			queued[ch.RepoID] = true
+		}
+	}
+
+	return queue, nil
+}
+
+// mergeBudget returns the number of changesets that can still be merged in
+// the current hour, given the number merged in the last hour. It returns -1
+// if there is no limit.
+func mergeBudget(mergesPerHour int32, merged int64) int {
+	if mergesPerHour <= 0 {
+		return -1
+	}
+	if left := int64(mergesPerHour) - merged; left > 0 {
+		return int(left)
+	}
+	return 0
+}
+
+// mergeMethod returns the merge method of the campaign, defaulting to a
+// merge commit.
+func mergeMethod(c *campaigns.Campaign) campaigns.ChangesetMergeMethod {
+	if c.MergeMethod.Valid() {
+		return c.MergeMethod
+	}
+	return campaigns.ChangesetMergeMethodMerge
+}
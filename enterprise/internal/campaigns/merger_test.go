@@ -0,0 +1,90 @@
+package campaigns
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/internal/campaigns"
+)
+
+func TestMergeBudget(t *testing.T) {
+	for _, tc := range []struct {
+		name          string
+		mergesPerHour int32
+		merged        int64
+		want          int
+	}{
+		{name: "no limit", mergesPerHour: 0, merged: 10, want: -1},
+		{name: "nothing merged", mergesPerHour: 5, merged: 0, want: 5},
+		{name: "some merged", mergesPerHour: 5, merged: 3, want: 2},
+		{name: "limit reached", mergesPerHour: 5, merged: 5, want: 0},
+		{name: "limit lowered", mergesPerHour: 5, merged: 8, want: 0},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if have := mergeBudget(tc.mergesPerHour, tc.merged); have != tc.want {
+				t.Errorf("wrong budget. want=%d, have=%d", tc.want, have)
+			}
+		})
+	}
+}
+
+func TestMergeMethod(t *testing.T) {
+	for _, tc := range []struct {
+		method campaigns.ChangesetMergeMethod
+		want   campaigns.ChangesetMergeMethod
+	}{
+		{method: "", want: campaigns.ChangesetMergeMethodMerge},
+		{method: "FAST_FORWARD", want: campaigns.ChangesetMergeMethodMerge},
+		{method: campaigns.ChangesetMergeMethodSquash, want: campaigns.ChangesetMergeMethodSquash},
+		{method: campaigns.ChangesetMergeMethodRebase, want: campaigns.ChangesetMergeMethodRebase},
+	} {
+		c := &campaigns.Campaign{MergeMethod: tc.method}
+		if have := mergeMethod(c); have != tc.want {
+			t.Errorf("mergeMethod(%q): want=%q, have=%q", tc.method, tc.want, have)
+		}
+	}
+}
+
+func TestMergeQueue(t *testing.T) {
+	ready := []*campaigns.Changeset{
+		{ID: 1, RepoID: 1},
+		{ID: 2, RepoID: 1},
+		{ID: 3, RepoID: 2},
+		{ID: 4, RepoID: 3},
+		{ID: 5, RepoID: 3},
+	}
+
+	for _, tc := range []struct {
+		name    string
+		budget  int
+		skipped map[int64]bool
+		want    []int64
+	}{
+		{name: "one per repository", budget: -1, want: []int64{1, 3, 4}},
+		{name: "budget", budget: 2, want: []int64{1, 3}},
+		{name: "no budget left", budget: 0, want: nil},
+		{name: "skipped changeset", budget: -1, skipped: map[int64]bool{1: true, 4: true}, want: []int64{2, 3, 5}},
+		{name: "all of repository skipped", budget: -1, skipped: map[int64]bool{1: true, 2: true}, want: []int64{3, 4}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			skip := func(ch *campaigns.Changeset) (bool, error) {
+				return tc.skipped[ch.ID], nil
+			}
+
+			queue, err := mergeQueue(ready, tc.budget, skip)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var have []int64
+			for _, ch := range queue {
+				have = append(have, ch.ID)
+			}
+
+			if diff := cmp.Diff(have, tc.want); diff != "" {
+				t.Fatalf("wrong queue:\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,322 @@
+package campaigns
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/graph-gophers/graphql-go/relay"
+	"github.com/hashicorp/go-multierror"
+	"github.com/inconshreveable/log15"
+	"github.com/opentracing/opentracing-go/log"
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/campaigns"
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
+	"github.com/sourcegraph/sourcegraph/internal/slack"
+	"github.com/sourcegraph/sourcegraph/internal/trace"
+	"github.com/sourcegraph/sourcegraph/internal/txemail"
+	"github.com/sourcegraph/sourcegraph/internal/txemail/txtypes"
+)
+
+// CampaignNotifier delivers the pending CampaignNotifications, i.e. the
+// ChangesetTransitions of changesets, to the webhooks, Slack channels and
+// users subscribed to their campaigns.
+type CampaignNotifier struct {
+	Store       *Store
+	HTTPFactory *httpcli.Factory
+	Clock       func() time.Time
+	// Backoff is the time to wait after an error or when there are no
+	// pending notifications.
+	Backoff time.Duration
+}
+
+// Run delivers pending CampaignNotifications until ctx is canceled.
+func (n *CampaignNotifier) Run(ctx context.Context) {
+	process := func(ctx context.Context, s *Store, notification campaigns.CampaignNotification) error {
+		if runErr := n.Deliver(ctx, s, &notification); runErr != nil {
+			log15.Warn("CampaignNotifier.Deliver", "notificationID", notification.ID, "err", runErr)
+		}
+		// Deliver saves the error in the notification row
+		return nil
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		default:
+			didRun, err := n.Store.ProcessPendingCampaignNotifications(context.Background(), process)
+			if err != nil {
+				log15.Error("Delivering campaign notification", "err", err)
+			}
+			// Back off on error or when no notifications available
+			if err != nil || !didRun {
+				time.Sleep(n.Backoff)
+			}
+		}
+	}
+}
+
+// Deliver delivers the given CampaignNotification to its
+// CampaignNotificationSubscription and saves the outcome in the notification.
+func (n *CampaignNotifier) Deliver(ctx context.Context, store *Store, notification *campaigns.CampaignNotification) (err error) {
+	tr, ctx := trace.New(ctx, "CampaignNotifier.Deliver", fmt.Sprintf("notification_id: %d", notification.ID))
+	defer func() {
+		tr.SetError(err)
+		tr.Finish()
+	}()
+	tr.LogFields(log.Int64("notification_id", notification.ID), log.Int64("changeset_id", notification.ChangesetID))
+
+	if !notification.FinishedAt.IsZero() {
+		log15.Info("CampaignNotification already delivered", "id", notification.ID)
+		return nil
+	}
+
+	defer func() {
+		if err != nil {
+			notification.Error = err.Error()
+		}
+		notification.FinishedAt = n.Clock()
+
+		if e := store.UpdateCampaignNotification(ctx, notification); e != nil {
+			if err == nil {
+				err = e
+			} else {
+				err = multierror.Append(err, e)
+			}
+		}
+	}()
+
+	notification.StartedAt = n.Clock()
+
+	sub, err := store.GetCampaignNotificationSubscription(ctx, GetCampaignNotificationSubscriptionOpts{ID: notification.SubscriptionID})
+	if err != nil {
+		return errors.Wrap(err, "getting notification subscription")
+	}
+
+	campaign, err := store.GetCampaign(ctx, GetCampaignOpts{ID: sub.CampaignID})
+	if err != nil {
+		return errors.Wrap(err, "getting campaign")
+	}
+
+	c, err := store.GetChangeset(ctx, GetChangesetOpts{ID: notification.ChangesetID})
+	if err != nil {
+		return errors.Wrap(err, "getting changeset")
+	}
+
+	externalURL, err := api.InternalClient.ExternalURL(ctx)
+	if err != nil {
+		return errors.Wrap(err, "getting external URL")
+	}
+
+	payload, err := newCampaignNotificationPayload(externalURL, notification.Transition, campaign, c)
+	if err != nil {
+		return err
+	}
+
+	switch sub.Channel {
+	case campaigns.CampaignNotificationChannelWebhook, campaigns.CampaignNotificationChannelSlack:
+		// 🚨 SECURITY: The URL of the subscription is user provided, so we
+		// must not allow it to reach internal services.
+		cli, err := n.HTTPFactory.Doer(httpcli.DenyPrivateNetworksOpt)
+		if err != nil {
+			return err
+		}
+		if sub.Channel == campaigns.CampaignNotificationChannelSlack {
+			return postCampaignNotificationWebhook(ctx, cli, sub.URL, "", payload.slackPayload())
+		}
+		return postCampaignNotificationWebhook(ctx, cli, sub.URL, sub.Secret, payload)
+
+	case campaigns.CampaignNotificationChannelEmail:
+		return sendCampaignNotificationEmail(ctx, sub.UserID, payload)
+
+	default:
+		return errors.Errorf("unknown notification channel %q", sub.Channel)
+	}
+}
+
+// campaignNotificationPayload is the JSON payload posted to the webhooks of
+// CampaignNotificationSubscriptions.
+type campaignNotificationPayload struct {
+	Transition campaigns.ChangesetTransition `json:"transition"`
+	Campaign   campaignNotificationCampaign  `json:"campaign"`
+	Changeset  campaignNotificationChangeset `json:"changeset"`
+}
+
+type campaignNotificationCampaign struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+type campaignNotificationChangeset struct {
+	ID          int64                          `json:"id"`
+	Title       string                         `json:"title"`
+	URL         string                         `json:"url"`
+	State       campaigns.ChangesetState       `json:"state"`
+	ReviewState campaigns.ChangesetReviewState `json:"reviewState"`
+	CheckState  campaigns.ChangesetCheckState  `json:"checkState"`
+}
+
+func newCampaignNotificationPayload(externalURL string, t campaigns.ChangesetTransition, campaign *campaigns.Campaign, c *campaigns.Changeset) (*campaignNotificationPayload, error) {
+	title, err := c.Title()
+	if err != nil {
+		return nil, errors.Wrap(err, "getting changeset title")
+	}
+
+	url, err := c.URL()
+	if err != nil {
+		return nil, errors.Wrap(err, "getting changeset URL")
+	}
+
+	id := string(relay.MarshalID("Campaign", campaign.ID))
+
+	return &campaignNotificationPayload{
+		Transition: t,
+		Campaign: campaignNotificationCampaign{
+			ID:   id,
+			Name: campaign.Name,
+			URL:  strings.TrimSuffix(externalURL, "/") + "/campaigns/" + id,
+		},
+		Changeset: campaignNotificationChangeset{
+			ID:          c.ID,
+			Title:       title,
+			URL:         url,
+			State:       c.ExternalState,
+			ReviewState: c.ExternalReviewState,
+			CheckState:  c.ExternalCheckState,
+		},
+	}, nil
+}
+
+// summary returns a one-line, human readable summary of the transition.
+func (p *campaignNotificationPayload) summary() string {
+	var what string
+	switch p.Transition {
+	case campaigns.ChangesetTransitionMerged:
+		what = "was merged"
+	case campaigns.ChangesetTransitionClosed:
+		what = "was closed"
+	case campaigns.ChangesetTransitionChecksFailed:
+		what = "has failing checks"
+	case campaigns.ChangesetTransitionChangesRequested:
+		what = "has changes requested"
+	default:
+		what = "changed"
+	}
+	return fmt.Sprintf("Changeset %q of campaign %q %s", p.Changeset.Title, p.Campaign.Name, what)
+}
+
+func (p *campaignNotificationPayload) slackPayload() *slack.Payload {
+	return &slack.Payload{
+		Username:  "Sourcegraph campaigns",
+		IconEmoji: ":sourcegraph:",
+		Text:      p.summary(),
+		Attachments: []*slack.Attachment{
+			{
+				Fallback:  p.summary(),
+				Title:     p.Changeset.Title,
+				TitleLink: p.Changeset.URL,
+				Text:      fmt.Sprintf("<%s|View campaign %s on Sourcegraph>", p.Campaign.URL, p.Campaign.Name),
+			},
+		},
+	}
+}
+
+// campaignNotificationSignatureHeader is the header of webhook requests that
+// holds the hex encoded HMAC-SHA256 of the request body, keyed with the secret
+// of the CampaignNotificationSubscription.
+const campaignNotificationSignatureHeader = "X-Sourcegraph-Signature"
+
+// postCampaignNotificationWebhook posts the given payload as JSON to the given
+// URL, signing it with the given secret if it's not empty.
+func postCampaignNotificationWebhook(ctx context.Context, cli httpcli.Doer, url, secret string, payload interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return errors.Wrap(err, "marshaling webhook payload")
+	}
+
+	req, err := http.NewRequest("POST", url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if secret != "" {
+		req.Header.Set(campaignNotificationSignatureHeader, "sha256="+signCampaignNotification(secret, body))
+	}
+
+	resp, err := cli.Do(req.WithContext(ctx))
+	if err != nil {
+		return errors.Wrap(err, "posting webhook")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		b, _ := ioutil.ReadAll(resp.Body)
+		return errors.Errorf("webhook returned status %d: %s", resp.StatusCode, bytes.TrimSpace(b))
+	}
+
+	return nil
+}
+
+func signCampaignNotification(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func sendCampaignNotificationEmail(ctx context.Context, userID int32, payload *campaignNotificationPayload) error {
+	canSendEmail, err := api.InternalClient.CanSendEmail(ctx)
+	if err != nil {
+		return errors.Wrap(err, "InternalClient.CanSendEmail")
+	}
+	if !canSendEmail {
+		return errors.New("SMTP server not set in site configuration")
+	}
+
+	email, err := api.InternalClient.UserEmailsGetEmail(ctx, userID)
+	if err != nil {
+		return errors.Wrapf(err, "InternalClient.UserEmailsGetEmail for userID=%d", userID)
+	}
+	if email == nil {
+		return errors.Errorf("unable to send email to user ID %d with unknown email address", userID)
+	}
+
+	return api.InternalClient.SendEmail(ctx, txtypes.Message{
+		To:       []string{*email},
+		Template: campaignNotificationEmailTemplates,
+		Data: struct {
+			Summary      string
+			CampaignURL  string
+			ChangesetURL string
+		}{
+			Summary:      payload.summary(),
+			CampaignURL:  payload.Campaign.URL,
+			ChangesetURL: payload.Changeset.URL,
+		},
+	})
+}
+
+var campaignNotificationEmailTemplates = txemail.MustValidate(txtypes.Templates{
+	Subject: `{{.Summary}}`,
+	Text: `
+{{.Summary}}.
+
+View the changeset: {{.ChangesetURL}}
+View the campaign on Sourcegraph: {{.CampaignURL}}
+`,
+	HTML: `
+<p>{{.Summary}}.</p>
+<p><a href="{{.ChangesetURL}}">View the changeset</a></p>
+<p><a href="{{.CampaignURL}}">View the campaign on Sourcegraph</a></p>
+`,
+})
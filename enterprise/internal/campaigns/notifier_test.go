@@ -0,0 +1,123 @@
+package campaigns
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sourcegraph/sourcegraph/internal/campaigns"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/github"
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
+)
+
+func TestPostCampaignNotificationWebhook(t *testing.T) {
+	payload, err := newCampaignNotificationPayload(
+		"https://sourcegraph.example.com/",
+		campaigns.ChangesetTransitionMerged,
+		&campaigns.Campaign{ID: 1, Name: "Fix all the things"},
+		&campaigns.Changeset{
+			ID:            2,
+			ExternalState: campaigns.ChangesetStateMerged,
+			Metadata: &github.PullRequest{
+				Title: "Fix the thing",
+				URL:   "https://github.com/sourcegraph/sourcegraph/pull/1",
+			},
+		},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if have, want := payload.Campaign.URL, "https://sourcegraph.example.com/campaigns/Q2FtcGFpZ246MQ=="; have != want {
+		t.Fatalf("wrong campaign URL. want=%q, have=%q", want, have)
+	}
+
+	for _, tc := range []struct {
+		name      string
+		secret    string
+		status    int
+		signature string
+		err       string
+	}{
+		{
+			name:   "unsigned",
+			status: http.StatusOK,
+		},
+		{
+			name:   "signed",
+			secret: "s3cr3t",
+			status: http.StatusNoContent,
+		},
+		{
+			name:   "error status",
+			status: http.StatusInternalServerError,
+			err:    "webhook returned status 500: boom",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var (
+				body      []byte
+				signature string
+			)
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ = ioutil.ReadAll(r.Body)
+				signature = r.Header.Get(campaignNotificationSignatureHeader)
+				w.WriteHeader(tc.status)
+				if tc.status >= 300 {
+					_, _ = w.Write([]byte("boom\n"))
+				}
+			}))
+			defer srv.Close()
+
+			err := postCampaignNotificationWebhook(context.Background(), http.DefaultClient, srv.URL, tc.secret, payload)
+			if have, want := errString(err), tc.err; have != want {
+				t.Fatalf("wrong error. want=%q, have=%q", want, have)
+			}
+
+			var have campaignNotificationPayload
+			if err := json.Unmarshal(body, &have); err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(*payload, have); diff != "" {
+				t.Fatalf("wrong payload: %s", diff)
+			}
+
+			var wantSignature string
+			if tc.secret != "" {
+				wantSignature = "sha256=" + signCampaignNotification(tc.secret, body)
+			}
+			if signature != wantSignature {
+				t.Fatalf("wrong signature. want=%q, have=%q", wantSignature, signature)
+			}
+		})
+	}
+
+	t.Run("private network", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			t.Error("webhook to loopback address wasn't denied")
+		}))
+		defer srv.Close()
+
+		cli, err := httpcli.NewExternalHTTPClientFactory().Doer(httpcli.DenyPrivateNetworksOpt)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = postCampaignNotificationWebhook(context.Background(), cli, srv.URL, "", payload)
+		if err == nil || !strings.Contains(err.Error(), "is not allowed") {
+			t.Fatalf("want webhook to be denied, have err=%v", err)
+		}
+	})
+}
+
+func errString(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
@@ -0,0 +1,213 @@
+package campaigns
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/inconshreveable/log15"
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/cmd/repo-updater/repos"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/campaigns"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
+	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
+)
+
+// ChangesetRebaser periodically re-applies the patches of open campaign
+// changesets whose base branch moved onto the new base, force-pushing the
+// changeset's branch if the patch still applies cleanly and the branch is
+// still at the commit the campaign pushed last.
+type ChangesetRebaser struct {
+	Store     *Store
+	GitClient GitserverClient
+	Clock     func() time.Time
+	// Interval is the time between two runs of RebaseOutdated.
+	Interval time.Duration
+}
+
+// Run rebases outdated changesets every Interval until ctx is canceled.
+func (r *ChangesetRebaser) Run(ctx context.Context) {
+	t := time.NewTicker(r.Interval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			if err := r.RebaseOutdated(ctx); err != nil {
+				log15.Error("Rebasing outdated changesets", "err", err)
+			}
+		}
+	}
+}
+
+// RebaseOutdated rebases the open changesets of all open campaigns with a
+// patch set whose base branch moved since they were last rebased.
+func (r *ChangesetRebaser) RebaseOutdated(ctx context.Context) error {
+	hasPatchSet := true
+	opts := ListCampaignsOpts{
+		State:       campaigns.CampaignStateOpen,
+		HasPatchSet: &hasPatchSet,
+		Limit:       100,
+	}
+
+	for {
+		cs, next, err := r.Store.ListCampaigns(ctx, opts)
+		if err != nil {
+			return errors.Wrap(err, "listing campaigns")
+		}
+
+		for _, c := range cs {
+			if err := r.rebaseCampaign(ctx, c); err != nil {
+				log15.Error("Rebasing changesets of campaign", "campaign", c.ID, "err", err)
+			}
+		}
+
+		if next == 0 {
+			return nil
+		}
+		opts.Cursor = next
+	}
+}
+
+func (r *ChangesetRebaser) rebaseCampaign(ctx context.Context, c *campaigns.Campaign) error {
+	jobs, _, err := r.Store.ListChangesetJobs(ctx, ListChangesetJobsOpts{
+		CampaignID: c.ID,
+		Limit:      -1,
+	})
+	if err != nil {
+		return errors.Wrap(err, "listing changeset jobs")
+	}
+
+	for _, job := range jobs {
+		if job.ChangesetID == 0 || job.Branch == "" {
+			// Not published yet.
+			continue
+		}
+		if err := r.rebaseChangeset(ctx, c, job); err != nil {
+			log15.Error("Rebasing changeset", "changeset", job.ChangesetID, "err", err)
+		}
+	}
+
+	return nil
+}
+
+func (r *ChangesetRebaser) rebaseChangeset(ctx context.Context, c *campaigns.Campaign, job *campaigns.ChangesetJob) error {
+	ch, err := r.Store.GetChangeset(ctx, GetChangesetOpts{ID: job.ChangesetID})
+	if err != nil {
+		return errors.Wrap(err, "getting changeset")
+	}
+	if ch.ExternalState != campaigns.ChangesetStateOpen {
+		return nil
+	}
+
+	patch, err := r.Store.GetPatch(ctx, GetPatchOpts{ID: job.PatchID})
+	if err != nil {
+		return errors.Wrap(err, "getting patch")
+	}
+
+	reposStore := repos.NewDBStore(r.Store.DB(), sql.TxOptions{})
+	rs, err := reposStore.ListRepos(ctx, repos.StoreListReposArgs{IDs: []api.RepoID{ch.RepoID}})
+	if err != nil {
+		return err
+	}
+	if len(rs) != 1 {
+		return errors.Errorf("repo not found: %d", ch.RepoID)
+	}
+	repo := gitserver.Repo{Name: api.RepoName(rs[0].Name)}
+
+	baseRef, err := ch.BaseRef()
+	if err != nil {
+		return err
+	}
+
+	head, err := git.ResolveRevision(ctx, repo, nil, baseRef, &git.ResolveRevisionOptions{NoEnsureRevision: true})
+	if err != nil {
+		return errors.Wrap(err, "resolving base branch commit")
+	}
+
+	base := ch.BaseCommit
+	if base == "" {
+		base = patch.Rev
+	}
+	if head == base {
+		return nil
+	}
+
+	// We record the base commit even if the changeset isn't rebased, so that
+	// we only try again once the base branch moved again.
+	ch.BaseCommit = head
+
+	if ch.PushedCommit == "" {
+		// We don't know which commit we pushed last, so we can't tell whether
+		// someone else pushed to the branch since.
+		log15.Info("Changeset needs manual rebase, pushed commit unknown", "changeset", ch.ID, "base", head)
+		ch.RebaseState = campaigns.ChangesetRebaseStateNeedsManualRebase
+		return r.Store.UpdateChangesets(ctx, ch)
+	}
+
+	committer, err := campaignCommitter(ctx, c)
+	if err != nil {
+		return errors.Wrap(err, "getting campaign committer")
+	}
+
+	ref, err := r.GitClient.CreateCommitFromPatch(ctx, protocol.CreateCommitFromPatchRequest{
+		Repo:       repo.Name,
+		BaseCommit: head,
+		// See ExecChangesetJob for why the trailing newline and the `git
+		// apply` arguments are needed.
+		Patch:     patch.Diff + "\n",
+		TargetRef: job.Branch,
+		UniqueRef: false,
+		CommitInfo: protocol.PatchCommitInfo{
+			Message:        c.Name,
+			AuthorName:     "Sourcegraph Bot",
+			AuthorEmail:    "campaigns@sourcegraph.com",
+			CommitterName:  committer.Name,
+			CommitterEmail: committer.Email,
+			Date:           r.Clock(),
+		},
+		GitApplyArgs: []string{"-p0", "--unidiff-zero"},
+		Push:         true,
+		// Don't overwrite commits that were pushed to the branch by others.
+		ExpectedRemoteCommit: ch.PushedCommit,
+	})
+	switch {
+	case err == nil:
+		pushed, err := git.ResolveRevision(ctx, repo, nil, ref, &git.ResolveRevisionOptions{NoEnsureRevision: true})
+		if err != nil {
+			return errors.Wrap(err, "resolving pushed commit")
+		}
+		ch.PushedCommit = pushed
+		ch.RebaseState = campaigns.ChangesetRebaseStateUpToDate
+	case isPatchConflict(err):
+		log15.Info("Changeset needs manual rebase", "changeset", ch.ID, "base", head)
+		ch.RebaseState = campaigns.ChangesetRebaseStateNeedsManualRebase
+	case isBranchMoved(err):
+		log15.Info("Changeset needs manual rebase, branch has commits not pushed by campaign", "changeset", ch.ID, "base", head)
+		ch.RebaseState = campaigns.ChangesetRebaseStateNeedsManualRebase
+	default:
+		return errors.Wrap(err, "rebasing branch")
+	}
+
+	return r.Store.UpdateChangesets(ctx, ch)
+}
+
+// isPatchConflict returns true if err is returned by CreateCommitFromPatch
+// because the patch doesn't apply to the base commit.
+func isPatchConflict(err error) bool {
+	e, ok := err.(*protocol.CreateCommitFromPatchError)
+	return ok && strings.HasPrefix(e.Command, "git apply")
+}
+
+// isBranchMoved returns true if err is returned by CreateCommitFromPatch
+// because the branch on the code host is no longer at the
+// ExpectedRemoteCommit.
+func isBranchMoved(err error) bool {
+	e, ok := err.(*protocol.CreateCommitFromPatchError)
+	return ok && strings.HasPrefix(e.Command, "git push") && strings.Contains(e.CombinedOutput, "stale info")
+}
@@ -0,0 +1,228 @@
+package campaigns
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/campaigns"
+	"github.com/sourcegraph/sourcegraph/internal/db/dbconn"
+	"github.com/sourcegraph/sourcegraph/internal/db/dbtest"
+	"github.com/sourcegraph/sourcegraph/internal/db/dbtesting"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/github"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
+	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
+)
+
+func TestIsPatchConflict(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{
+			name: "patch does not apply",
+			err: &protocol.CreateCommitFromPatchError{
+				Command:        "git apply -p0 --unidiff-zero --cached /tmp/patch",
+				CombinedOutput: "error: patch failed: README.md:1",
+			},
+			want: true,
+		},
+		{
+			name: "push failed",
+			err: &protocol.CreateCommitFromPatchError{
+				Command:        "git push --force https://github.com/sourcegraph/sourcegraph campaigns/test",
+				CombinedOutput: "remote: Permission denied",
+			},
+			want: false,
+		},
+		{
+			name: "other error",
+			err:  errors.New("connection refused"),
+			want: false,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if have := isPatchConflict(tc.err); have != tc.want {
+				t.Errorf("want %t, have %t", tc.want, have)
+			}
+		})
+	}
+}
+
+func TestChangesetRebaser(t *testing.T) {
+	ctx := context.Background()
+
+	now := time.Now().UTC().Truncate(time.Microsecond)
+	clock := func() time.Time { return now.UTC().Truncate(time.Microsecond) }
+
+	dbtesting.SetupGlobalTestDB(t)
+
+	db.Mocks.Settings.GetLatest = func(ctx context.Context, subject api.SettingsSubject) (*api.Settings, error) {
+		return nil, nil
+	}
+	defer func() { db.Mocks.Settings = db.MockSettings{} }()
+
+	const (
+		newBase    = api.CommitID("b4s3")
+		pushed     = api.CommitID("pu5h3d")
+		rebased    = api.CommitID("r3b4s3d")
+		branchName = "refs/heads/dead-code-b-gone"
+	)
+
+	git.Mocks.ResolveRevision = func(spec string, opt *git.ResolveRevisionOptions) (api.CommitID, error) {
+		switch spec {
+		case "refs/heads/master":
+			return newBase, nil
+		case branchName:
+			return rebased, nil
+		}
+		return "", errors.New("unknown revision")
+	}
+	defer func() { git.Mocks.ResolveRevision = nil }()
+
+	for _, tc := range []struct {
+		name         string
+		baseCommit   api.CommitID
+		pushedCommit api.CommitID
+		gitErr       error
+
+		wantRequest *protocol.CreateCommitFromPatchRequest
+		wantState   campaigns.ChangesetRebaseState
+		wantPushed  api.CommitID
+	}{
+		{
+			name:         "base branch moved",
+			pushedCommit: pushed,
+			wantRequest: &protocol.CreateCommitFromPatchRequest{
+				BaseCommit:           newBase,
+				TargetRef:            branchName,
+				ExpectedRemoteCommit: pushed,
+			},
+			wantState:  campaigns.ChangesetRebaseStateUpToDate,
+			wantPushed: rebased,
+		},
+		{
+			name:         "base branch unchanged",
+			baseCommit:   newBase,
+			pushedCommit: pushed,
+			wantPushed:   pushed,
+		},
+		{
+			name:         "patch conflicts",
+			pushedCommit: pushed,
+			gitErr: &protocol.CreateCommitFromPatchError{
+				Command:        "git apply -p0 --unidiff-zero --cached /tmp/patch",
+				CombinedOutput: "error: patch failed: README.md:1",
+			},
+			wantRequest: &protocol.CreateCommitFromPatchRequest{
+				BaseCommit:           newBase,
+				TargetRef:            branchName,
+				ExpectedRemoteCommit: pushed,
+			},
+			wantState:  campaigns.ChangesetRebaseStateNeedsManualRebase,
+			wantPushed: pushed,
+		},
+		{
+			name:         "branch has other commits",
+			pushedCommit: pushed,
+			gitErr: &protocol.CreateCommitFromPatchError{
+				Command:        "git push --force-with-lease=refs/heads/dead-code-b-gone:pu5h3d https://github.com/sourcegraph/sourcegraph 1234:refs/heads/dead-code-b-gone",
+				CombinedOutput: " ! [rejected]        1234 -> dead-code-b-gone (stale info)",
+			},
+			wantRequest: &protocol.CreateCommitFromPatchRequest{
+				BaseCommit:           newBase,
+				TargetRef:            branchName,
+				ExpectedRemoteCommit: pushed,
+			},
+			wantState:  campaigns.ChangesetRebaseStateNeedsManualRebase,
+			wantPushed: pushed,
+		},
+		{
+			name:      "pushed commit unknown",
+			wantState: campaigns.ChangesetRebaseStateNeedsManualRebase,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tx := dbtest.NewTx(t, dbconn.Global)
+			s := NewStoreWithClock(tx, clock)
+
+			repo, _ := createGitHubRepo(t, ctx, now, s)
+			campaign, patch := createCampaignPatch(t, ctx, now, s, repo)
+
+			ch := &campaigns.Changeset{
+				RepoID:        repo.ID,
+				CampaignIDs:   []int64{campaign.ID},
+				ExternalState: campaigns.ChangesetStateOpen,
+				BaseCommit:    tc.baseCommit,
+				PushedCommit:  tc.pushedCommit,
+			}
+			ch.SetMetadata(&github.PullRequest{
+				ID:          "FOOBARID",
+				Number:      12345,
+				State:       "OPEN",
+				BaseRefName: "master",
+				HeadRefName: "dead-code-b-gone",
+			})
+			if err := s.CreateChangesets(ctx, ch); err != nil {
+				t.Fatal(err)
+			}
+
+			job := &campaigns.ChangesetJob{
+				CampaignID:  campaign.ID,
+				PatchID:     patch.ID,
+				ChangesetID: ch.ID,
+				Branch:      branchName,
+			}
+
+			gitClient := &recordingGitserverClient{ref: branchName, err: tc.gitErr}
+			r := &ChangesetRebaser{Store: s, GitClient: gitClient, Clock: clock}
+
+			if err := r.rebaseChangeset(ctx, campaign, job); err != nil {
+				t.Fatal(err)
+			}
+
+			var have *protocol.CreateCommitFromPatchRequest
+			if gitClient.req != nil {
+				have = &protocol.CreateCommitFromPatchRequest{
+					BaseCommit:           gitClient.req.BaseCommit,
+					TargetRef:            gitClient.req.TargetRef,
+					ExpectedRemoteCommit: gitClient.req.ExpectedRemoteCommit,
+				}
+			}
+			if diff := cmp.Diff(tc.wantRequest, have); diff != "" {
+				t.Fatalf("wrong CreateCommitFromPatch request:\n%s", diff)
+			}
+
+			ch, err := s.GetChangeset(ctx, GetChangesetOpts{ID: ch.ID})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if have, want := ch.RebaseState, tc.wantState; have != want {
+				t.Errorf("wrong rebase state. want=%q, have=%q", want, have)
+			}
+			if have, want := ch.PushedCommit, tc.wantPushed; have != want {
+				t.Errorf("wrong pushed commit. want=%q, have=%q", want, have)
+			}
+			if have, want := ch.BaseCommit, newBase; have != want {
+				t.Errorf("wrong base commit. want=%q, have=%q", want, have)
+			}
+		})
+	}
+}
+
+type recordingGitserverClient struct {
+	ref string
+	err error
+	req *protocol.CreateCommitFromPatchRequest
+}
+
+func (c *recordingGitserverClient) CreateCommitFromPatch(ctx context.Context, req protocol.CreateCommitFromPatchRequest) (string, error) {
+	c.req = &req
+	return c.ref, c.err
+}
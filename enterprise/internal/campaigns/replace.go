@@ -0,0 +1,239 @@
+package campaigns
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/sergi/go-diff/diffmatchpatch"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/campaigns"
+	"github.com/sourcegraph/sourcegraph/internal/comby"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/search"
+	"golang.org/x/net/context/ctxhttp"
+)
+
+// maxReplacementFileSize is the size above which files are skipped when
+// applying a regexp Replacement.
+const maxReplacementFileSize = 1 << 20
+
+// replacementDiff returns the diff produced by applying the Replacement to
+// the given commit of the repository. The diff is in the format expected by
+// ExecChangesetJob: unified, without `a/` and `b/` filename prefixes. It is
+// empty if the Replacement doesn't match anything.
+func replacementDiff(ctx context.Context, repo api.RepoName, commit api.CommitID, r *campaigns.Replacement) (string, error) {
+	switch r.Kind {
+	case campaigns.ReplacementKindRegexp:
+		return regexpReplacementDiff(ctx, repo, commit, r)
+	case campaigns.ReplacementKindComby:
+		return combyReplacementDiff(ctx, repo, commit, r)
+	default:
+		return "", errors.Errorf("invalid replacement kind %q", r.Kind)
+	}
+}
+
+func regexpReplacementDiff(ctx context.Context, repo api.RepoName, commit api.CommitID, r *campaigns.Replacement) (string, error) {
+	re, err := regexp.Compile(r.Match)
+	if err != nil {
+		return "", errors.Wrap(err, "compiling replacement match")
+	}
+
+	archive, err := gitserver.DefaultClient.Archive(ctx, gitserver.Repo{Name: repo}, gitserver.ArchiveOptions{
+		Treeish: string(commit),
+		Format:  "tar",
+	})
+	if err != nil {
+		return "", errors.Wrap(err, "fetching archive")
+	}
+	defer archive.Close()
+
+	return replaceInArchive(tar.NewReader(archive), re, r.Rewrite, r.FileExtension)
+}
+
+// replaceInArchive replaces all matches of re with rewrite in the regular
+// files of the tar archive and returns the diff of all changed files.
+func replaceInArchive(tr *tar.Reader, re *regexp.Regexp, rewrite, fileExtension string) (string, error) {
+	var diffs []string
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", errors.Wrap(err, "reading archive")
+		}
+
+		if hdr.Typeflag != tar.TypeReg || hdr.Size > maxReplacementFileSize {
+			continue
+		}
+		if fileExtension != "" && path.Ext(hdr.Name) != fileExtension {
+			continue
+		}
+
+		content, err := ioutil.ReadAll(tr)
+		if err != nil {
+			return "", errors.Wrapf(err, "reading %q from archive", hdr.Name)
+		}
+		// Skip binary files.
+		if bytes.IndexByte(content, 0) != -1 {
+			continue
+		}
+
+		before := string(content)
+		after := re.ReplaceAllString(before, rewrite)
+		if before == after {
+			continue
+		}
+		diffs = append(diffs, unifiedDiff(hdr.Name, before, after))
+	}
+	return strings.Join(diffs, ""), nil
+}
+
+// unifiedDiff returns a unified diff without context lines between before and
+// after, the contents of the file at the given path.
+func unifiedDiff(path, before, after string) string {
+	dmp := diffmatchpatch.New()
+	a, b, lines := dmp.DiffLinesToChars(before, after)
+	diffs := dmp.DiffCharsToLines(dmp.DiffMain(a, b, false), lines)
+
+	var out strings.Builder
+	fmt.Fprintf(&out, "--- %s\n+++ %s\n", path, path)
+
+	// oldLine and newLine are the 1-based numbers of the next line in
+	// before and after.
+	oldLine, newLine := 1, 1
+	for i := 0; i < len(diffs); {
+		if diffs[i].Type == diffmatchpatch.DiffEqual {
+			n := len(splitLines(diffs[i].Text))
+			oldLine += n
+			newLine += n
+			i++
+			continue
+		}
+
+		// Collect all consecutive insertions and deletions into one hunk.
+		var deleted, inserted []string
+		for ; i < len(diffs) && diffs[i].Type != diffmatchpatch.DiffEqual; i++ {
+			if diffs[i].Type == diffmatchpatch.DiffDelete {
+				deleted = append(deleted, splitLines(diffs[i].Text)...)
+			} else {
+				inserted = append(inserted, splitLines(diffs[i].Text)...)
+			}
+		}
+
+		fmt.Fprintf(&out, "@@ -%s +%s @@\n",
+			hunkRange(oldLine, len(deleted)),
+			hunkRange(newLine, len(inserted)),
+		)
+		writeHunkLines(&out, "-", deleted)
+		writeHunkLines(&out, "+", inserted)
+
+		oldLine += len(deleted)
+		newLine += len(inserted)
+	}
+
+	return out.String()
+}
+
+// hunkRange returns the range of a hunk without context lines. Empty ranges
+// start at the line preceding the change.
+func hunkRange(start, count int) string {
+	if count == 0 {
+		start--
+	}
+	return fmt.Sprintf("%d,%d", start, count)
+}
+
+func writeHunkLines(w *strings.Builder, prefix string, lines []string) {
+	for _, l := range lines {
+		w.WriteString(prefix)
+		w.WriteString(l)
+		if !strings.HasSuffix(l, "\n") {
+			w.WriteString("\n\\ No newline at end of file\n")
+		}
+	}
+}
+
+// splitLines splits s after each newline. The last line is only missing the
+// newline if s doesn't end with one.
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	lines := strings.SplitAfter(s, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+func combyReplacementDiff(ctx context.Context, repo api.RepoName, commit api.CommitID, r *campaigns.Replacement) (string, error) {
+	u, err := url.Parse(search.ReplacerURL())
+	if err != nil {
+		return "", err
+	}
+	q := u.Query()
+	q.Set("repo", string(repo))
+	q.Set("commit", string(commit))
+	q.Set("matchtemplate", r.Match)
+	q.Set("rewritetemplate", r.Rewrite)
+	q.Set("fileextension", r.FileExtension)
+	u.RawQuery = q.Encode()
+
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := ctxhttp.Do(ctx, nil, req)
+	if err != nil {
+		return "", errors.Wrap(err, "replacer request failed")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := ioutil.ReadAll(resp.Body)
+		return "", errors.Errorf("replacer request failed with status %d: %s", resp.StatusCode, body)
+	}
+
+	return readCombyDiffs(resp.Body)
+}
+
+// readCombyDiffs concatenates the file diffs of the JSON lines returned by
+// the replacer service.
+func readCombyDiffs(r io.Reader) (string, error) {
+	scanner := bufio.NewScanner(r)
+	// Diffs of large files are returned on a single line.
+	scanner.Buffer(make([]byte, 100), 10*bufio.MaxScanTokenSize)
+
+	var out strings.Builder
+	for scanner.Scan() {
+		var d comby.FileDiff
+		if err := json.Unmarshal(scanner.Bytes(), &d); err != nil {
+			return "", errors.Wrap(err, "decoding replacer result")
+		}
+		if d.Diff == "" {
+			continue
+		}
+		out.WriteString(d.Diff)
+		if !strings.HasSuffix(d.Diff, "\n") {
+			out.WriteString("\n")
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", errors.Wrap(err, "reading replacer results")
+	}
+	return out.String(), nil
+}
@@ -0,0 +1,159 @@
+package campaigns
+
+import (
+	"archive/tar"
+	"bytes"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestUnifiedDiff(t *testing.T) {
+	for _, tc := range []struct {
+		name          string
+		before, after string
+		want          string
+	}{
+		{
+			name:   "single line",
+			before: "a\nb\nc\n",
+			after:  "a\nB\nc\n",
+			want: `--- f.go
++++ f.go
+@@ -2,1 +2,1 @@
+-b
++B
+`,
+		},
+		{
+			name:   "separate hunks",
+			before: "a\nb\nc\nd\n",
+			after:  "A\nb\nc\nD\n",
+			want: `--- f.go
++++ f.go
+@@ -1,1 +1,1 @@
+-a
++A
+@@ -4,1 +4,1 @@
+-d
++D
+`,
+		},
+		{
+			name:   "line removed",
+			before: "a\nb\nc\n",
+			after:  "a\nc\n",
+			want: `--- f.go
++++ f.go
+@@ -2,1 +1,0 @@
+-b
+`,
+		},
+		{
+			name:   "lines added",
+			before: "a\nc\n",
+			after:  "a\nb\nb\nc\n",
+			want: `--- f.go
++++ f.go
+@@ -1,0 +2,2 @@
++b
++b
+`,
+		},
+		{
+			name:   "no newline at end of file",
+			before: "a\nb",
+			after:  "a\nB",
+			want: `--- f.go
++++ f.go
+@@ -2,1 +2,1 @@
+-b
+\ No newline at end of file
++B
+\ No newline at end of file
+`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			have := unifiedDiff("f.go", tc.before, tc.after)
+			if diff := cmp.Diff(tc.want, have); diff != "" {
+				t.Errorf("wrong diff (-want +have):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestReplaceInArchive(t *testing.T) {
+	files := []struct {
+		name, content string
+	}{
+		{"main.go", "package main\n\nfunc main() {\n\tfoo()\n}\n"},
+		{"README.md", "Call foo()\n"},
+		{"lib/lib.go", "package lib\n"},
+		{"bin/foo.go", "foo()\x00"},
+	}
+
+	var buf bytes.Buffer
+	w := tar.NewWriter(&buf)
+	for _, f := range files {
+		err := w.WriteHeader(&tar.Header{
+			Typeflag: tar.TypeReg,
+			Name:     f.name,
+			Mode:     0644,
+			Size:     int64(len(f.content)),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write([]byte(f.content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	have, err := replaceInArchive(tar.NewReader(&buf), regexp.MustCompile(`foo\((.*)\)`), "bar($1)", ".go")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `--- main.go
++++ main.go
+@@ -4,1 +4,1 @@
+-	foo()
++	bar()
+`
+	if diff := cmp.Diff(want, have); diff != "" {
+		t.Errorf("wrong diff (-want +have):\n%s", diff)
+	}
+}
+
+func TestReadCombyDiffs(t *testing.T) {
+	results := strings.Join([]string{
+		`{"uri":"main.go","diff":"--- main.go\n+++ main.go\n@@ -4,1 +4,1 @@\n-func main() {\n+func run() {"}`,
+		`{"uri":"lib.go","diff":"--- lib.go\n+++ lib.go\n@@ -1,1 +1,1 @@\n-package lib\n+package pkg\n"}`,
+	}, "\n")
+
+	have, err := readCombyDiffs(strings.NewReader(results))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `--- main.go
++++ main.go
+@@ -4,1 +4,1 @@
+-func main() {
++func run() {
+--- lib.go
++++ lib.go
+@@ -1,1 +1,1 @@
+-package lib
++package pkg
+`
+	if diff := cmp.Diff(want, have); diff != "" {
+		t.Errorf("wrong diff (-want +have):\n%s", diff)
+	}
+}
@@ -0,0 +1,324 @@
+package campaigns
+
+import (
+	"encoding/csv"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/campaigns"
+)
+
+// ChangesetReport holds the points in time of a single Changeset's lifetime
+// that are relevant for reporting, computed from its Events.
+type ChangesetReport struct {
+	Changeset *campaigns.Changeset
+	RepoName  api.RepoName
+
+	OpenedAt       time.Time
+	FirstReviewAt  time.Time
+	MergedAt       time.Time
+	LastActivityAt time.Time
+}
+
+// TimeToFirstReview returns the duration between the Changeset being opened
+// and its first review. The boolean is false if the Changeset hasn't been
+// reviewed.
+func (r *ChangesetReport) TimeToFirstReview() (time.Duration, bool) {
+	if r.OpenedAt.IsZero() || r.FirstReviewAt.IsZero() {
+		return 0, false
+	}
+	return r.FirstReviewAt.Sub(r.OpenedAt), true
+}
+
+// TimeToMerge returns the duration between the Changeset being opened and
+// merged. The boolean is false if the Changeset hasn't been merged.
+func (r *ChangesetReport) TimeToMerge() (time.Duration, bool) {
+	if r.OpenedAt.IsZero() || r.MergedAt.IsZero() {
+		return 0, false
+	}
+	return r.MergedAt.Sub(r.OpenedAt), true
+}
+
+// Stale returns true if the Changeset is open and there has been no activity
+// on it since the given point in time.
+func (r *ChangesetReport) Stale(since time.Time) bool {
+	return r.Changeset.ExternalState == campaigns.ChangesetStateOpen &&
+		r.LastActivityAt.Before(since)
+}
+
+// NewChangesetReport computes the ChangesetReport of the given Changeset
+// from its Events. The Events need to be sorted by their timestamps.
+func NewChangesetReport(c *campaigns.Changeset, repoName api.RepoName, es Events) *ChangesetReport {
+	r := &ChangesetReport{
+		Changeset:      c,
+		RepoName:       repoName,
+		OpenedAt:       c.ExternalCreatedAt(),
+		LastActivityAt: c.ExternalCreatedAt(),
+	}
+
+	for _, e := range es {
+		t := e.Timestamp()
+		if t.IsZero() {
+			continue
+		}
+
+		if t.After(r.LastActivityAt) {
+			r.LastActivityAt = t
+		}
+
+		switch e.Type() {
+		case campaigns.ChangesetEventKindGitHubReviewed,
+			campaigns.ChangesetEventKindBitbucketServerApproved,
+			campaigns.ChangesetEventKindBitbucketServerReviewed:
+			if r.FirstReviewAt.IsZero() {
+				r.FirstReviewAt = t
+			}
+
+		case campaigns.ChangesetEventKindGitHubMerged,
+			campaigns.ChangesetEventKindBitbucketServerMerged:
+			if r.MergedAt.IsZero() {
+				r.MergedAt = t
+			}
+		}
+	}
+
+	return r
+}
+
+// CampaignsReport is a report about the review and merge progress of the
+// Changesets of one or more Campaigns.
+type CampaignsReport struct {
+	Changesets []*ChangesetReport
+	// StaleSince is the point in time after which open Changesets without
+	// activity are considered stale.
+	StaleSince time.Time
+}
+
+// NewCampaignsReport computes a CampaignsReport for the given Changesets and
+// their Events. repoNames maps the IDs of the Changesets' repositories to
+// their names.
+func NewCampaignsReport(staleSince time.Time, cs []*campaigns.Changeset, repoNames map[api.RepoID]api.RepoName, es ...Event) *CampaignsReport {
+	events := Events(es)
+	sort.Sort(events)
+
+	byChangesetID := make(map[int64]Events)
+	for _, e := range events {
+		id := e.Changeset()
+		byChangesetID[id] = append(byChangesetID[id], e)
+	}
+
+	r := &CampaignsReport{
+		Changesets: make([]*ChangesetReport, 0, len(cs)),
+		StaleSince: staleSince,
+	}
+	for _, c := range cs {
+		r.Changesets = append(r.Changesets, NewChangesetReport(c, repoNames[c.RepoID], byChangesetID[c.ID]))
+	}
+
+	return r
+}
+
+// TimeToFirstReview returns the distribution of the time to first review of
+// all reviewed Changesets.
+func (r *CampaignsReport) TimeToFirstReview() DurationDistribution {
+	var ds []time.Duration
+	for _, c := range r.Changesets {
+		if d, ok := c.TimeToFirstReview(); ok {
+			ds = append(ds, d)
+		}
+	}
+	return NewDurationDistribution(ds)
+}
+
+// TimeToMerge returns the distribution of the time to merge of all merged
+// Changesets.
+func (r *CampaignsReport) TimeToMerge() DurationDistribution {
+	var ds []time.Duration
+	for _, c := range r.Changesets {
+		if d, ok := c.TimeToMerge(); ok {
+			ds = append(ds, d)
+		}
+	}
+	return NewDurationDistribution(ds)
+}
+
+// StaleChangesets returns the reports of the stale Changesets, the ones with
+// the least recent activity first.
+func (r *CampaignsReport) StaleChangesets() []*ChangesetReport {
+	var stale []*ChangesetReport
+	for _, c := range r.Changesets {
+		if c.Stale(r.StaleSince) {
+			stale = append(stale, c)
+		}
+	}
+	sort.SliceStable(stale, func(i, j int) bool {
+		return stale[i].LastActivityAt.Before(stale[j].LastActivityAt)
+	})
+	return stale
+}
+
+// RepoOwnerCounts are the numbers of Changesets in the repositories of a
+// single owner, by state.
+type RepoOwnerCounts struct {
+	Owner  string
+	Total  int32
+	Open   int32
+	Merged int32
+	Closed int32
+	Stale  int32
+}
+
+// ByRepoOwner returns the Changeset counts per repository owner, sorted by
+// owner. See repoOwner for the definition of an owner.
+func (r *CampaignsReport) ByRepoOwner() []*RepoOwnerCounts {
+	byOwner := map[string]*RepoOwnerCounts{}
+	for _, c := range r.Changesets {
+		owner := repoOwner(c.RepoName)
+		counts, ok := byOwner[owner]
+		if !ok {
+			counts = &RepoOwnerCounts{Owner: owner}
+			byOwner[owner] = counts
+		}
+
+		counts.Total++
+		switch c.Changeset.ExternalState {
+		case campaigns.ChangesetStateOpen:
+			counts.Open++
+		case campaigns.ChangesetStateMerged:
+			counts.Merged++
+		case campaigns.ChangesetStateClosed, campaigns.ChangesetStateDeleted:
+			counts.Closed++
+		}
+		if c.Stale(r.StaleSince) {
+			counts.Stale++
+		}
+	}
+
+	owners := make([]*RepoOwnerCounts, 0, len(byOwner))
+	for _, counts := range byOwner {
+		owners = append(owners, counts)
+	}
+	sort.Slice(owners, func(i, j int) bool { return owners[i].Owner < owners[j].Owner })
+	return owners
+}
+
+// repoOwner returns the owner of the repository with the given name, which
+// is its name without the last path component. For example, the owner of
+// "github.com/sourcegraph/sourcegraph" is "github.com/sourcegraph".
+func repoOwner(name api.RepoName) string {
+	i := strings.LastIndex(string(name), "/")
+	if i == -1 {
+		return string(name)
+	}
+	return string(name[:i])
+}
+
+// csvHeader is the header row of the CSV export of a CampaignsReport.
+var csvHeader = []string{
+	"repository",
+	"external_id",
+	"state",
+	"review_state",
+	"check_state",
+	"opened_at",
+	"first_review_at",
+	"merged_at",
+	"last_activity_at",
+	"time_to_first_review_seconds",
+	"time_to_merge_seconds",
+	"stale",
+}
+
+// WriteCSV writes one row per Changeset of the report to w, preceded by a
+// header row. Points in time are formatted as RFC 3339 and empty if they
+// didn't happen yet, as are the durations derived from them.
+func (r *CampaignsReport) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvHeader); err != nil {
+		return err
+	}
+
+	for _, c := range r.Changesets {
+		record := []string{
+			string(c.RepoName),
+			c.Changeset.ExternalID,
+			string(c.Changeset.ExternalState),
+			string(c.Changeset.ExternalReviewState),
+			string(c.Changeset.ExternalCheckState),
+			csvTime(c.OpenedAt),
+			csvTime(c.FirstReviewAt),
+			csvTime(c.MergedAt),
+			csvTime(c.LastActivityAt),
+			csvDuration(c.TimeToFirstReview()),
+			csvDuration(c.TimeToMerge()),
+			strconv.FormatBool(c.Stale(r.StaleSince)),
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+func csvTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+func csvDuration(d time.Duration, ok bool) string {
+	if !ok {
+		return ""
+	}
+	return strconv.FormatInt(int64(d/time.Second), 10)
+}
+
+// DurationDistribution summarizes a set of durations.
+type DurationDistribution struct {
+	Count int
+	Mean  time.Duration
+	P50   time.Duration
+	P90   time.Duration
+	Max   time.Duration
+}
+
+// NewDurationDistribution returns the DurationDistribution of the given
+// durations. All durations of the distribution are 0 if ds is empty.
+func NewDurationDistribution(ds []time.Duration) DurationDistribution {
+	dist := DurationDistribution{Count: len(ds)}
+	if len(ds) == 0 {
+		return dist
+	}
+
+	sorted := make([]time.Duration, len(ds))
+	copy(sorted, ds)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	var sum time.Duration
+	for _, d := range sorted {
+		sum += d
+	}
+
+	dist.Mean = sum / time.Duration(len(sorted))
+	dist.P50 = percentile(sorted, 50)
+	dist.P90 = percentile(sorted, 90)
+	dist.Max = sorted[len(sorted)-1]
+	return dist
+}
+
+// percentile returns the p-th percentile of the sorted durations, using the
+// nearest-rank method.
+func percentile(sorted []time.Duration, p int) time.Duration {
+	rank := (p*len(sorted) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
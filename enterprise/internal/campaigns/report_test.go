@@ -0,0 +1,145 @@
+package campaigns
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/campaigns"
+)
+
+func TestCampaignsReport(t *testing.T) {
+	now := time.Date(2020, 5, 1, 12, 0, 0, 0, time.UTC)
+	daysAgo := func(days int) time.Time { return now.AddDate(0, 0, -days) }
+
+	withState := func(c *campaigns.Changeset, s campaigns.ChangesetState) *campaigns.Changeset {
+		c.ExternalState = s
+		return c
+	}
+
+	cs := []*campaigns.Changeset{
+		// Reviewed and merged.
+		withState(ghChangeset(1, daysAgo(10)), campaigns.ChangesetStateMerged),
+		// Reviewed, still open and recently active.
+		withState(ghChangeset(2, daysAgo(10)), campaigns.ChangesetStateOpen),
+		// Open without any activity.
+		withState(ghChangeset(3, daysAgo(30)), campaigns.ChangesetStateOpen),
+		// Closed without any activity.
+		withState(ghChangeset(4, daysAgo(30)), campaigns.ChangesetStateClosed),
+	}
+	cs[0].RepoID, cs[1].RepoID, cs[2].RepoID, cs[3].RepoID = 1, 2, 3, 3
+
+	repoNames := map[api.RepoID]api.RepoName{
+		1: "github.com/sourcegraph/sourcegraph",
+		2: "github.com/sourcegraph/src-cli",
+		3: "github.com/other/repo",
+	}
+
+	es := []Event{
+		fakeEvent{t: daysAgo(8), kind: campaigns.ChangesetEventKindGitHubReviewed, id: 1},
+		fakeEvent{t: daysAgo(9), kind: campaigns.ChangesetEventKindGitHubCommented, id: 1},
+		fakeEvent{t: daysAgo(6), kind: campaigns.ChangesetEventKindGitHubMerged, id: 1},
+		fakeEvent{t: daysAgo(4), kind: campaigns.ChangesetEventKindGitHubReviewed, id: 2},
+		fakeEvent{t: daysAgo(1), kind: campaigns.ChangesetEventKindGitHubReviewed, id: 2},
+	}
+
+	r := NewCampaignsReport(daysAgo(14), cs, repoNames, es...)
+
+	t.Run("ChangesetReport", func(t *testing.T) {
+		have := r.Changesets[0]
+		if !have.FirstReviewAt.Equal(daysAgo(8)) {
+			t.Errorf("wrong FirstReviewAt: %s", have.FirstReviewAt)
+		}
+		if !have.MergedAt.Equal(daysAgo(6)) {
+			t.Errorf("wrong MergedAt: %s", have.MergedAt)
+		}
+		if !have.LastActivityAt.Equal(daysAgo(6)) {
+			t.Errorf("wrong LastActivityAt: %s", have.LastActivityAt)
+		}
+	})
+
+	t.Run("TimeToFirstReview", func(t *testing.T) {
+		want := DurationDistribution{
+			Count: 2,
+			Mean:  4 * 24 * time.Hour,
+			P50:   2 * 24 * time.Hour,
+			P90:   6 * 24 * time.Hour,
+			Max:   6 * 24 * time.Hour,
+		}
+		if diff := cmp.Diff(want, r.TimeToFirstReview()); diff != "" {
+			t.Error(diff)
+		}
+	})
+
+	t.Run("TimeToMerge", func(t *testing.T) {
+		want := DurationDistribution{
+			Count: 1,
+			Mean:  4 * 24 * time.Hour,
+			P50:   4 * 24 * time.Hour,
+			P90:   4 * 24 * time.Hour,
+			Max:   4 * 24 * time.Hour,
+		}
+		if diff := cmp.Diff(want, r.TimeToMerge()); diff != "" {
+			t.Error(diff)
+		}
+	})
+
+	t.Run("StaleChangesets", func(t *testing.T) {
+		stale := r.StaleChangesets()
+		if len(stale) != 1 || stale[0].Changeset.ID != 3 {
+			t.Errorf("wrong stale changesets: %+v", stale)
+		}
+	})
+
+	t.Run("ByRepoOwner", func(t *testing.T) {
+		want := []*RepoOwnerCounts{
+			{Owner: "github.com/other", Total: 2, Open: 1, Closed: 1, Stale: 1},
+			{Owner: "github.com/sourcegraph", Total: 2, Open: 1, Merged: 1},
+		}
+		if diff := cmp.Diff(want, r.ByRepoOwner()); diff != "" {
+			t.Error(diff)
+		}
+	})
+
+	t.Run("WriteCSV", func(t *testing.T) {
+		var b strings.Builder
+		if err := r.WriteCSV(&b); err != nil {
+			t.Fatal(err)
+		}
+
+		want := strings.Join([]string{
+			"repository,external_id,state,review_state,check_state,opened_at,first_review_at,merged_at,last_activity_at,time_to_first_review_seconds,time_to_merge_seconds,stale",
+			"github.com/sourcegraph/sourcegraph,,MERGED,,,2020-04-21T12:00:00Z,2020-04-23T12:00:00Z,2020-04-25T12:00:00Z,2020-04-25T12:00:00Z,172800,345600,false",
+			"github.com/sourcegraph/src-cli,,OPEN,,,2020-04-21T12:00:00Z,2020-04-27T12:00:00Z,,2020-04-30T12:00:00Z,518400,,false",
+			"github.com/other/repo,,OPEN,,,2020-04-01T12:00:00Z,,,2020-04-01T12:00:00Z,,,true",
+			"github.com/other/repo,,CLOSED,,,2020-04-01T12:00:00Z,,,2020-04-01T12:00:00Z,,,false",
+		}, "\n") + "\n"
+		if diff := cmp.Diff(want, b.String()); diff != "" {
+			t.Error(diff)
+		}
+	})
+}
+
+func TestNewDurationDistribution(t *testing.T) {
+	ds := make([]time.Duration, 0, 10)
+	for i := 10; i > 0; i-- {
+		ds = append(ds, time.Duration(i)*time.Minute)
+	}
+
+	want := DurationDistribution{
+		Count: 10,
+		Mean:  330 * time.Second,
+		P50:   5 * time.Minute,
+		P90:   9 * time.Minute,
+		Max:   10 * time.Minute,
+	}
+	if diff := cmp.Diff(want, NewDurationDistribution(ds)); diff != "" {
+		t.Error(diff)
+	}
+
+	if have := NewDurationDistribution(nil); have != (DurationDistribution{}) {
+		t.Errorf("want empty distribution, have %+v", have)
+	}
+}
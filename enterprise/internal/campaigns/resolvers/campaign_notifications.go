@@ -0,0 +1,189 @@
+package resolvers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/graph-gophers/graphql-go"
+	"github.com/graph-gophers/graphql-go/relay"
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/authz"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+	ee "github.com/sourcegraph/sourcegraph/enterprise/internal/campaigns"
+	"github.com/sourcegraph/sourcegraph/internal/campaigns"
+	"github.com/sourcegraph/sourcegraph/internal/trace"
+)
+
+const campaignNotificationSubscriptionIDKind = "CampaignNotificationSubscription"
+
+func marshalCampaignNotificationSubscriptionID(id int64) graphql.ID {
+	return relay.MarshalID(campaignNotificationSubscriptionIDKind, id)
+}
+
+func unmarshalCampaignNotificationSubscriptionID(id graphql.ID) (subscriptionID int64, err error) {
+	err = relay.UnmarshalSpec(id, &subscriptionID)
+	return
+}
+
+func (r *Resolver) CreateCampaignNotificationSubscription(ctx context.Context, args *graphqlbackend.CreateCampaignNotificationSubscriptionArgs) (_ graphqlbackend.CampaignNotificationSubscriptionResolver, err error) {
+	tr, ctx := trace.New(ctx, "Resolver.CreateCampaignNotificationSubscription", fmt.Sprintf("Campaign: %q, Channel: %q", args.Campaign, args.Input.Channel))
+	defer func() {
+		tr.SetError(err)
+		tr.Finish()
+	}()
+
+	channel := campaigns.CampaignNotificationChannel(args.Input.Channel)
+
+	campaignID, err := unmarshalCampaignID(args.Campaign)
+	if err != nil {
+		return nil, err
+	}
+
+	// 🚨 SECURITY: Users who can view campaigns may subscribe themselves to
+	// email notifications. Only site admins may send notifications to
+	// webhooks and Slack channels.
+	if channel == campaigns.CampaignNotificationChannelEmail {
+		err = allowReadAccess(ctx)
+	} else {
+		err = checkSiteAdminAccess(ctx)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := backend.CurrentUser(ctx)
+	if err != nil {
+		return nil, errors.Wrapf(err, "%v", backend.ErrNotAuthenticated)
+	}
+	if user == nil {
+		return nil, backend.ErrNotAuthenticated
+	}
+
+	sub := &campaigns.CampaignNotificationSubscription{
+		CampaignID: campaignID,
+		UserID:     user.ID,
+		Channel:    channel,
+	}
+	if args.Input.URL != nil {
+		sub.URL = *args.Input.URL
+	}
+	if args.Input.Secret != nil {
+		sub.Secret = *args.Input.Secret
+	}
+	if args.Input.Transitions != nil {
+		for _, t := range *args.Input.Transitions {
+			sub.Transitions = append(sub.Transitions, campaigns.ChangesetTransition(t))
+		}
+	}
+
+	if err = sub.Validate(); err != nil {
+		return nil, err
+	}
+
+	if _, err = r.store.GetCampaign(ctx, ee.GetCampaignOpts{ID: campaignID}); err != nil {
+		return nil, errors.Wrap(err, "getting campaign")
+	}
+
+	if err = r.store.CreateCampaignNotificationSubscription(ctx, sub); err != nil {
+		return nil, err
+	}
+
+	return &campaignNotificationSubscriptionResolver{sub: sub}, nil
+}
+
+func (r *Resolver) DeleteCampaignNotificationSubscription(ctx context.Context, args *graphqlbackend.DeleteCampaignNotificationSubscriptionArgs) (_ *graphqlbackend.EmptyResponse, err error) {
+	tr, ctx := trace.New(ctx, "Resolver.DeleteCampaignNotificationSubscription", fmt.Sprintf("Subscription: %q", args.Subscription))
+	defer func() {
+		tr.SetError(err)
+		tr.Finish()
+	}()
+
+	id, err := unmarshalCampaignNotificationSubscriptionID(args.Subscription)
+	if err != nil {
+		return nil, err
+	}
+
+	sub, err := r.store.GetCampaignNotificationSubscription(ctx, ee.GetCampaignNotificationSubscriptionOpts{ID: id})
+	if err != nil {
+		return nil, err
+	}
+
+	// 🚨 SECURITY: Only site admins and the user who created the
+	// subscription may delete it.
+	if err := backend.CheckSiteAdminOrSameUser(ctx, sub.UserID); err != nil {
+		return nil, err
+	}
+
+	if err = r.store.DeleteCampaignNotificationSubscription(ctx, sub.ID); err != nil {
+		return nil, err
+	}
+
+	return &graphqlbackend.EmptyResponse{}, nil
+}
+
+func (r *campaignResolver) NotificationSubscriptions(ctx context.Context) ([]graphqlbackend.CampaignNotificationSubscriptionResolver, error) {
+	opts := ee.ListCampaignNotificationSubscriptionsOpts{
+		CampaignID: r.Campaign.ID,
+		Limit:      -1,
+	}
+
+	// 🚨 SECURITY: Subscriptions hold the URLs notifications are posted to,
+	// so users other than site admins only see their own.
+	if err := backend.CheckCurrentUserIsSiteAdminWithScope(ctx, authz.ScopeCampaignsWrite); err != nil {
+		user, err := backend.CurrentUser(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if user == nil {
+			return []graphqlbackend.CampaignNotificationSubscriptionResolver{}, nil
+		}
+		opts.UserID = user.ID
+	}
+
+	subs, _, err := r.store.ListCampaignNotificationSubscriptions(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	resolvers := make([]graphqlbackend.CampaignNotificationSubscriptionResolver, 0, len(subs))
+	for _, sub := range subs {
+		resolvers = append(resolvers, &campaignNotificationSubscriptionResolver{sub: sub})
+	}
+	return resolvers, nil
+}
+
+type campaignNotificationSubscriptionResolver struct {
+	sub *campaigns.CampaignNotificationSubscription
+}
+
+func (r *campaignNotificationSubscriptionResolver) ID() graphql.ID {
+	return marshalCampaignNotificationSubscriptionID(r.sub.ID)
+}
+
+func (r *campaignNotificationSubscriptionResolver) Channel() campaigns.CampaignNotificationChannel {
+	return r.sub.Channel
+}
+
+func (r *campaignNotificationSubscriptionResolver) URL() *string {
+	if r.sub.URL == "" {
+		return nil
+	}
+	return &r.sub.URL
+}
+
+func (r *campaignNotificationSubscriptionResolver) HasSecret() bool {
+	return r.sub.Secret != ""
+}
+
+func (r *campaignNotificationSubscriptionResolver) Transitions() []campaigns.ChangesetTransition {
+	return r.sub.Transitions
+}
+
+func (r *campaignNotificationSubscriptionResolver) User(ctx context.Context) (*graphqlbackend.UserResolver, error) {
+	return graphqlbackend.UserByIDInt32(ctx, r.sub.UserID)
+}
+
+func (r *campaignNotificationSubscriptionResolver) CreatedAt() graphqlbackend.DateTime {
+	return graphqlbackend.DateTime{Time: r.sub.CreatedAt}
+}
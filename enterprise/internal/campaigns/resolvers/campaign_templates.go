@@ -0,0 +1,548 @@
+package resolvers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/graph-gophers/graphql-go"
+	"github.com/graph-gophers/graphql-go/relay"
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/authz"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend/graphqlutil"
+	ee "github.com/sourcegraph/sourcegraph/enterprise/internal/campaigns"
+	"github.com/sourcegraph/sourcegraph/internal/campaigns"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/trace"
+)
+
+const campaignTemplateIDKind = "CampaignTemplate"
+
+func marshalCampaignTemplateID(id int64) graphql.ID {
+	return relay.MarshalID(campaignTemplateIDKind, id)
+}
+
+func unmarshalCampaignTemplateID(id graphql.ID) (templateID int64, err error) {
+	err = relay.UnmarshalSpec(id, &templateID)
+	return
+}
+
+// unmarshalNamespaceID returns the ID of the user or org with the given
+// GraphQL ID, exactly one of which is non-zero.
+func unmarshalNamespaceID(id graphql.ID) (userID, orgID int32, err error) {
+	switch relay.UnmarshalKind(id) {
+	case "User":
+		err = relay.UnmarshalSpec(id, &userID)
+	case "Org":
+		err = relay.UnmarshalSpec(id, &orgID)
+	default:
+		err = errors.Errorf("Invalid namespace %q", id)
+	}
+	return userID, orgID, err
+}
+
+func campaignTemplateSpecFromInput(in *graphqlbackend.CampaignTemplateSpecInput) *campaigns.CampaignTemplateSpec {
+	spec := &campaigns.CampaignTemplateSpec{
+		Action:        in.Action,
+		ScopeQuery:    in.ScopeQuery,
+		BranchPattern: in.BranchPattern,
+		TitleTemplate: in.TitleTemplate,
+	}
+	if in.BodyTemplate != nil {
+		spec.BodyTemplate = *in.BodyTemplate
+	}
+	if in.Parameters != nil {
+		for _, p := range *in.Parameters {
+			param := campaigns.CampaignTemplateParameter{Name: p.Name, Default: p.Default}
+			if p.Description != nil {
+				param.Description = *p.Description
+			}
+			spec.Parameters = append(spec.Parameters, param)
+		}
+	}
+	return spec
+}
+
+func (r *Resolver) CampaignTemplateByID(ctx context.Context, id graphql.ID) (graphqlbackend.CampaignTemplateResolver, error) {
+	// 🚨 SECURITY: Only site admins or users when read-access is enabled may access campaign templates.
+	if err := allowReadAccess(ctx); err != nil {
+		return nil, err
+	}
+
+	templateID, err := unmarshalCampaignTemplateID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	template, err := r.store.GetCampaignTemplate(ctx, ee.GetCampaignTemplateOpts{ID: templateID})
+	if err != nil {
+		if err == ee.ErrNoResults {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &campaignTemplateResolver{store: r.store, template: template}, nil
+}
+
+func (r *Resolver) CampaignTemplates(ctx context.Context, args *graphqlbackend.ListCampaignTemplatesArgs) (graphqlbackend.CampaignTemplatesConnectionResolver, error) {
+	// 🚨 SECURITY: Only site admins or users when read-access is enabled may access campaign templates.
+	if err := allowReadAccess(ctx); err != nil {
+		return nil, err
+	}
+
+	var opts ee.ListCampaignTemplatesOpts
+	if args.Namespace != nil {
+		var err error
+		opts.NamespaceUserID, opts.NamespaceOrgID, err = unmarshalNamespaceID(*args.Namespace)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if args.First != nil {
+		opts.Limit = int(*args.First)
+	}
+
+	return &campaignTemplatesConnectionResolver{store: r.store, opts: opts}, nil
+}
+
+func (r *Resolver) CreateCampaignTemplate(ctx context.Context, args *graphqlbackend.CreateCampaignTemplateArgs) (_ graphqlbackend.CampaignTemplateResolver, err error) {
+	tr, ctx := trace.New(ctx, "Resolver.CreateCampaignTemplate", args.Input.Name)
+	defer func() {
+		tr.SetError(err)
+		tr.Finish()
+	}()
+
+	user, err := db.Users.GetByCurrentAuthUser(ctx)
+	if err != nil {
+		return nil, errors.Wrapf(err, "%v", backend.ErrNotAuthenticated)
+	}
+
+	// 🚨 SECURITY: Only site admins may create campaign templates for now.
+	if err := backend.CheckAccessTokenScope(ctx, authz.ScopeCampaignsWrite); err != nil {
+		return nil, err
+	}
+	if !user.SiteAdmin {
+		return nil, backend.ErrMustBeSiteAdmin
+	}
+
+	template := &campaigns.CampaignTemplate{
+		Name:     args.Input.Name,
+		AuthorID: user.ID,
+	}
+	if args.Input.Description != nil {
+		template.Description = *args.Input.Description
+	}
+
+	template.NamespaceUserID, template.NamespaceOrgID, err = unmarshalNamespaceID(args.Input.Namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	svc := ee.NewService(r.store, gitserver.DefaultClient, r.httpFactory)
+	if _, err = svc.CreateCampaignTemplate(ctx, template, campaignTemplateSpecFromInput(&args.Input.Spec)); err != nil {
+		return nil, err
+	}
+
+	return &campaignTemplateResolver{store: r.store, template: template}, nil
+}
+
+func (r *Resolver) UpdateCampaignTemplate(ctx context.Context, args *graphqlbackend.UpdateCampaignTemplateArgs) (_ graphqlbackend.CampaignTemplateResolver, err error) {
+	tr, ctx := trace.New(ctx, "Resolver.UpdateCampaignTemplate", fmt.Sprintf("CampaignTemplate: %q", args.Input.ID))
+	defer func() {
+		tr.SetError(err)
+		tr.Finish()
+	}()
+
+	user, err := db.Users.GetByCurrentAuthUser(ctx)
+	if err != nil {
+		return nil, errors.Wrapf(err, "%v", backend.ErrNotAuthenticated)
+	}
+
+	// 🚨 SECURITY: Only site admins may update campaign templates for now.
+	if err := backend.CheckAccessTokenScope(ctx, authz.ScopeCampaignsWrite); err != nil {
+		return nil, err
+	}
+	if !user.SiteAdmin {
+		return nil, backend.ErrMustBeSiteAdmin
+	}
+
+	templateID, err := unmarshalCampaignTemplateID(args.Input.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	updateArgs := ee.UpdateCampaignTemplateArgs{
+		Template:    templateID,
+		Name:        args.Input.Name,
+		Description: args.Input.Description,
+		AuthorID:    user.ID,
+	}
+	if args.Input.Spec != nil {
+		updateArgs.Spec = campaignTemplateSpecFromInput(args.Input.Spec)
+	}
+
+	svc := ee.NewService(r.store, gitserver.DefaultClient, r.httpFactory)
+	template, err := svc.UpdateCampaignTemplate(ctx, updateArgs)
+	if err != nil {
+		return nil, err
+	}
+
+	return &campaignTemplateResolver{store: r.store, template: template}, nil
+}
+
+func (r *Resolver) DeleteCampaignTemplate(ctx context.Context, args *graphqlbackend.DeleteCampaignTemplateArgs) (_ *graphqlbackend.EmptyResponse, err error) {
+	tr, ctx := trace.New(ctx, "Resolver.DeleteCampaignTemplate", fmt.Sprintf("CampaignTemplate: %q", args.CampaignTemplate))
+	defer func() {
+		tr.SetError(err)
+		tr.Finish()
+	}()
+
+	// 🚨 SECURITY: Only site admins may delete campaign templates for now.
+	if err := backend.CheckCurrentUserIsSiteAdminWithScope(ctx, authz.ScopeCampaignsWrite); err != nil {
+		return nil, err
+	}
+
+	templateID, err := unmarshalCampaignTemplateID(args.CampaignTemplate)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = r.store.DeleteCampaignTemplate(ctx, templateID); err != nil {
+		return nil, err
+	}
+
+	return &graphqlbackend.EmptyResponse{}, nil
+}
+
+func (r *Resolver) InstantiateCampaignTemplate(ctx context.Context, args *graphqlbackend.InstantiateCampaignTemplateArgs) (_ graphqlbackend.CampaignResolver, err error) {
+	tr, ctx := trace.New(ctx, "Resolver.InstantiateCampaignTemplate", fmt.Sprintf("CampaignTemplate: %q", args.Input.Template))
+	defer func() {
+		tr.SetError(err)
+		tr.Finish()
+	}()
+
+	user, err := db.Users.GetByCurrentAuthUser(ctx)
+	if err != nil {
+		return nil, errors.Wrapf(err, "%v", backend.ErrNotAuthenticated)
+	}
+
+	// 🚨 SECURITY: Only site admins may create a campaign for now.
+	if err := backend.CheckAccessTokenScope(ctx, authz.ScopeCampaignsWrite); err != nil {
+		return nil, err
+	}
+	if !user.SiteAdmin {
+		return nil, backend.ErrMustBeSiteAdmin
+	}
+
+	templateID, err := unmarshalCampaignTemplateID(args.Input.Template)
+	if err != nil {
+		return nil, err
+	}
+
+	campaign := &campaigns.Campaign{AuthorID: user.ID}
+	if args.Input.Name != nil {
+		campaign.Name = *args.Input.Name
+	}
+	if args.Input.Description != nil {
+		campaign.Description = *args.Input.Description
+	}
+
+	campaign.NamespaceUserID, campaign.NamespaceOrgID, err = unmarshalNamespaceID(args.Input.Namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	if args.Input.PatchSet != nil {
+		campaign.PatchSetID, err = unmarshalPatchSetID(*args.Input.PatchSet)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	instantiateArgs := ee.InstantiateCampaignTemplateArgs{
+		Template: templateID,
+		Campaign: campaign,
+	}
+	if args.Input.Version != nil {
+		instantiateArgs.Version = *args.Input.Version
+	}
+	if args.Input.Parameters != nil {
+		instantiateArgs.Params = make(map[string]string, len(*args.Input.Parameters))
+		for _, p := range *args.Input.Parameters {
+			instantiateArgs.Params[p.Name] = p.Value
+		}
+	}
+	if args.Input.Draft != nil {
+		instantiateArgs.Draft = *args.Input.Draft
+	}
+
+	svc := ee.NewService(r.store, gitserver.DefaultClient, r.httpFactory)
+	campaign, err = svc.InstantiateCampaignTemplate(ctx, instantiateArgs)
+	if err != nil {
+		return nil, err
+	}
+
+	return &campaignResolver{store: r.store, Campaign: campaign}, nil
+}
+
+var _ graphqlbackend.CampaignTemplatesConnectionResolver = &campaignTemplatesConnectionResolver{}
+
+type campaignTemplatesConnectionResolver struct {
+	store *ee.Store
+	opts  ee.ListCampaignTemplatesOpts
+
+	// cache results because they are used by multiple fields
+	once      sync.Once
+	templates []*campaigns.CampaignTemplate
+	next      int64
+	err       error
+}
+
+func (r *campaignTemplatesConnectionResolver) Nodes(ctx context.Context) ([]graphqlbackend.CampaignTemplateResolver, error) {
+	templates, _, err := r.compute(ctx)
+	if err != nil {
+		return nil, err
+	}
+	resolvers := make([]graphqlbackend.CampaignTemplateResolver, 0, len(templates))
+	for _, t := range templates {
+		resolvers = append(resolvers, &campaignTemplateResolver{store: r.store, template: t})
+	}
+	return resolvers, nil
+}
+
+func (r *campaignTemplatesConnectionResolver) TotalCount(ctx context.Context) (int32, error) {
+	opts := ee.CountCampaignTemplatesOpts{NamespaceUserID: r.opts.NamespaceUserID, NamespaceOrgID: r.opts.NamespaceOrgID}
+	count, err := r.store.CountCampaignTemplates(ctx, opts)
+	return int32(count), err
+}
+
+func (r *campaignTemplatesConnectionResolver) PageInfo(ctx context.Context) (*graphqlutil.PageInfo, error) {
+	_, next, err := r.compute(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return graphqlutil.HasNextPage(next != 0), nil
+}
+
+func (r *campaignTemplatesConnectionResolver) compute(ctx context.Context) ([]*campaigns.CampaignTemplate, int64, error) {
+	r.once.Do(func() {
+		r.templates, r.next, r.err = r.store.ListCampaignTemplates(ctx, r.opts)
+	})
+	return r.templates, r.next, r.err
+}
+
+var _ graphqlbackend.CampaignTemplateResolver = &campaignTemplateResolver{}
+
+type campaignTemplateResolver struct {
+	store    *ee.Store
+	template *campaigns.CampaignTemplate
+}
+
+func (r *campaignTemplateResolver) ID() graphql.ID {
+	return marshalCampaignTemplateID(r.template.ID)
+}
+
+func (r *campaignTemplateResolver) Namespace(ctx context.Context) (n graphqlbackend.NamespaceResolver, err error) {
+	if r.template.NamespaceUserID != 0 {
+		n.Namespace, err = graphqlbackend.UserByIDInt32(ctx, r.template.NamespaceUserID)
+	} else {
+		n.Namespace, err = graphqlbackend.OrgByIDInt32(ctx, r.template.NamespaceOrgID)
+	}
+
+	return n, err
+}
+
+func (r *campaignTemplateResolver) Name() string {
+	return r.template.Name
+}
+
+func (r *campaignTemplateResolver) Description() *string {
+	if r.template.Description == "" {
+		return nil
+	}
+	return &r.template.Description
+}
+
+func (r *campaignTemplateResolver) Author(ctx context.Context) (*graphqlbackend.UserResolver, error) {
+	return graphqlbackend.UserByIDInt32(ctx, r.template.AuthorID)
+}
+
+func (r *campaignTemplateResolver) CreatedAt() graphqlbackend.DateTime {
+	return graphqlbackend.DateTime{Time: r.template.CreatedAt}
+}
+
+func (r *campaignTemplateResolver) UpdatedAt() graphqlbackend.DateTime {
+	return graphqlbackend.DateTime{Time: r.template.UpdatedAt}
+}
+
+func (r *campaignTemplateResolver) LatestVersion(ctx context.Context) (graphqlbackend.CampaignTemplateVersionResolver, error) {
+	v, err := r.store.GetCampaignTemplateVersion(ctx, ee.GetCampaignTemplateVersionOpts{
+		TemplateID: r.template.ID,
+		Version:    r.template.LatestVersion,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &campaignTemplateVersionResolver{store: r.store, version: v, preloadedTemplate: r.template}, nil
+}
+
+func (r *campaignTemplateResolver) Versions(ctx context.Context, args *graphqlutil.ConnectionArgs) graphqlbackend.CampaignTemplateVersionsConnectionResolver {
+	return &campaignTemplateVersionsConnectionResolver{
+		store:    r.store,
+		template: r.template,
+		opts: ee.ListCampaignTemplateVersionsOpts{
+			TemplateID: r.template.ID,
+			Limit:      int(args.GetFirst()),
+		},
+	}
+}
+
+var _ graphqlbackend.CampaignTemplateVersionsConnectionResolver = &campaignTemplateVersionsConnectionResolver{}
+
+type campaignTemplateVersionsConnectionResolver struct {
+	store    *ee.Store
+	template *campaigns.CampaignTemplate
+	opts     ee.ListCampaignTemplateVersionsOpts
+
+	// cache results because they are used by multiple fields
+	once     sync.Once
+	versions []*campaigns.CampaignTemplateVersion
+	next     int64
+	err      error
+}
+
+func (r *campaignTemplateVersionsConnectionResolver) Nodes(ctx context.Context) ([]graphqlbackend.CampaignTemplateVersionResolver, error) {
+	versions, _, err := r.compute(ctx)
+	if err != nil {
+		return nil, err
+	}
+	resolvers := make([]graphqlbackend.CampaignTemplateVersionResolver, 0, len(versions))
+	for _, v := range versions {
+		resolvers = append(resolvers, &campaignTemplateVersionResolver{store: r.store, version: v, preloadedTemplate: r.template})
+	}
+	return resolvers, nil
+}
+
+func (r *campaignTemplateVersionsConnectionResolver) TotalCount(ctx context.Context) (int32, error) {
+	// Versions are never deleted, so the latest version is also their count.
+	return r.template.LatestVersion, nil
+}
+
+func (r *campaignTemplateVersionsConnectionResolver) PageInfo(ctx context.Context) (*graphqlutil.PageInfo, error) {
+	_, next, err := r.compute(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return graphqlutil.HasNextPage(next != 0), nil
+}
+
+func (r *campaignTemplateVersionsConnectionResolver) compute(ctx context.Context) ([]*campaigns.CampaignTemplateVersion, int64, error) {
+	r.once.Do(func() {
+		r.versions, r.next, r.err = r.store.ListCampaignTemplateVersions(ctx, r.opts)
+	})
+	return r.versions, r.next, r.err
+}
+
+var _ graphqlbackend.CampaignTemplateVersionResolver = &campaignTemplateVersionResolver{}
+
+type campaignTemplateVersionResolver struct {
+	store   *ee.Store
+	version *campaigns.CampaignTemplateVersion
+
+	preloadedTemplate *campaigns.CampaignTemplate
+}
+
+func (r *campaignTemplateVersionResolver) Template(ctx context.Context) (graphqlbackend.CampaignTemplateResolver, error) {
+	if r.preloadedTemplate != nil {
+		return &campaignTemplateResolver{store: r.store, template: r.preloadedTemplate}, nil
+	}
+
+	template, err := r.store.GetCampaignTemplate(ctx, ee.GetCampaignTemplateOpts{ID: r.version.TemplateID})
+	if err != nil {
+		if err == ee.ErrNoResults {
+			// The template has been deleted.
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &campaignTemplateResolver{store: r.store, template: template}, nil
+}
+
+func (r *campaignTemplateVersionResolver) Version() int32        { return r.version.Version }
+func (r *campaignTemplateVersionResolver) Action() string        { return r.version.Spec.Action }
+func (r *campaignTemplateVersionResolver) ScopeQuery() string    { return r.version.Spec.ScopeQuery }
+func (r *campaignTemplateVersionResolver) BranchPattern() string { return r.version.Spec.BranchPattern }
+func (r *campaignTemplateVersionResolver) TitleTemplate() string { return r.version.Spec.TitleTemplate }
+func (r *campaignTemplateVersionResolver) BodyTemplate() string  { return r.version.Spec.BodyTemplate }
+
+func (r *campaignTemplateVersionResolver) Parameters() []graphqlbackend.CampaignTemplateParameterResolver {
+	resolvers := make([]graphqlbackend.CampaignTemplateParameterResolver, len(r.version.Spec.Parameters))
+	for i := range r.version.Spec.Parameters {
+		resolvers[i] = &campaignTemplateParameterResolver{param: &r.version.Spec.Parameters[i]}
+	}
+	return resolvers
+}
+
+func (r *campaignTemplateVersionResolver) Author(ctx context.Context) (*graphqlbackend.UserResolver, error) {
+	return graphqlbackend.UserByIDInt32(ctx, r.version.AuthorID)
+}
+
+func (r *campaignTemplateVersionResolver) CreatedAt() graphqlbackend.DateTime {
+	return graphqlbackend.DateTime{Time: r.version.CreatedAt}
+}
+
+type campaignTemplateParameterResolver struct {
+	param *campaigns.CampaignTemplateParameter
+}
+
+func (r *campaignTemplateParameterResolver) Name() string { return r.param.Name }
+
+func (r *campaignTemplateParameterResolver) Description() *string {
+	if r.param.Description == "" {
+		return nil
+	}
+	return &r.param.Description
+}
+
+func (r *campaignTemplateParameterResolver) Default() *string { return r.param.Default }
+
+type campaignTemplateParameterValueResolver struct {
+	name, value string
+}
+
+func (r *campaignTemplateParameterValueResolver) Name() string  { return r.name }
+func (r *campaignTemplateParameterValueResolver) Value() string { return r.value }
+
+func (r *campaignResolver) TemplateVersion(ctx context.Context) (graphqlbackend.CampaignTemplateVersionResolver, error) {
+	if r.Campaign.TemplateVersionID == 0 {
+		return nil, nil
+	}
+
+	v, err := r.store.GetCampaignTemplateVersion(ctx, ee.GetCampaignTemplateVersionOpts{ID: r.Campaign.TemplateVersionID})
+	if err != nil {
+		if err == ee.ErrNoResults {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &campaignTemplateVersionResolver{store: r.store, version: v}, nil
+}
+
+func (r *campaignResolver) TemplateParameters() []graphqlbackend.CampaignTemplateParameterValueResolver {
+	names := make([]string, 0, len(r.Campaign.TemplateParams))
+	for name := range r.Campaign.TemplateParams {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	resolvers := make([]graphqlbackend.CampaignTemplateParameterValueResolver, len(names))
+	for i, name := range names {
+		resolvers[i] = &campaignTemplateParameterValueResolver{name: name, value: r.Campaign.TemplateParams[name]}
+	}
+	return resolvers
+}
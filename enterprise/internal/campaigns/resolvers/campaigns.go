@@ -142,6 +142,21 @@ func (r *campaignResolver) ClosedAt() *graphqlbackend.DateTime {
 	return &graphqlbackend.DateTime{Time: r.Campaign.ClosedAt}
 }
 
+func (r *campaignResolver) AutoMerge() bool {
+	return r.Campaign.AutoMerge
+}
+
+func (r *campaignResolver) MergeMethod() campaigns.ChangesetMergeMethod {
+	if r.Campaign.MergeMethod == "" {
+		return campaigns.ChangesetMergeMethodMerge
+	}
+	return r.Campaign.MergeMethod
+}
+
+func (r *campaignResolver) MergesPerHour() int32 {
+	return r.Campaign.MergesPerHour
+}
+
 func (r *campaignResolver) PublishedAt(ctx context.Context) (*graphqlbackend.DateTime, error) {
 	if r.Campaign.PatchSetID == 0 {
 		return &graphqlbackend.DateTime{Time: r.Campaign.CreatedAt}, nil
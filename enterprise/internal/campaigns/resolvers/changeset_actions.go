@@ -0,0 +1,161 @@
+package resolvers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/graph-gophers/graphql-go"
+	"github.com/graph-gophers/graphql-go/relay"
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+	ee "github.com/sourcegraph/sourcegraph/enterprise/internal/campaigns"
+	"github.com/sourcegraph/sourcegraph/internal/campaigns"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/trace"
+)
+
+const changesetActionIDKind = "ChangesetAction"
+
+func marshalChangesetActionID(id int64) graphql.ID {
+	return relay.MarshalID(changesetActionIDKind, id)
+}
+
+func (r *Resolver) PerformChangesetAction(ctx context.Context, args *graphqlbackend.PerformChangesetActionArgs) (_ graphqlbackend.ChangesetActionResolver, err error) {
+	tr, ctx := trace.New(ctx, "Resolver.PerformChangesetAction", fmt.Sprintf("Campaign: %q, Type: %q", args.Campaign, args.Input.Type))
+	defer func() {
+		tr.SetError(err)
+		tr.Finish()
+	}()
+
+	campaignID, err := unmarshalCampaignID(args.Campaign)
+	if err != nil {
+		return nil, err
+	}
+
+	// 🚨 SECURITY: Only site admins may perform actions on changesets, since
+	// they're performed with the site's code host tokens.
+	if err := checkSiteAdminAccess(ctx); err != nil {
+		return nil, err
+	}
+
+	user, err := backend.CurrentUser(ctx)
+	if err != nil {
+		return nil, errors.Wrapf(err, "%v", backend.ErrNotAuthenticated)
+	}
+	if user == nil {
+		return nil, backend.ErrNotAuthenticated
+	}
+
+	a := &campaigns.ChangesetAction{
+		CampaignID: campaignID,
+		UserID:     user.ID,
+		Type:       campaigns.ChangesetActionType(args.Input.Type),
+	}
+	if args.Input.Body != nil {
+		a.Body = *args.Input.Body
+	}
+	if args.Input.Labels != nil {
+		a.Labels = *args.Input.Labels
+	}
+	if args.Input.Reviewers != nil {
+		a.Reviewers = *args.Input.Reviewers
+	}
+
+	svc := ee.NewService(r.store, gitserver.DefaultClient, r.httpFactory)
+	if err = svc.CreateChangesetAction(ctx, a); err != nil {
+		return nil, err
+	}
+
+	return &changesetActionResolver{store: r.store, action: a}, nil
+}
+
+func (r *campaignResolver) ChangesetActions(ctx context.Context) ([]graphqlbackend.ChangesetActionResolver, error) {
+	as, _, err := r.store.ListChangesetActions(ctx, ee.ListChangesetActionsOpts{
+		CampaignID: r.Campaign.ID,
+		Limit:      -1,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resolvers := make([]graphqlbackend.ChangesetActionResolver, 0, len(as))
+	for _, a := range as {
+		resolvers = append(resolvers, &changesetActionResolver{store: r.store, action: a})
+	}
+	return resolvers, nil
+}
+
+type changesetActionResolver struct {
+	store  *ee.Store
+	action *campaigns.ChangesetAction
+}
+
+func (r *changesetActionResolver) ID() graphql.ID {
+	return marshalChangesetActionID(r.action.ID)
+}
+
+func (r *changesetActionResolver) Type() campaigns.ChangesetActionType {
+	return r.action.Type
+}
+
+func (r *changesetActionResolver) Body() *string {
+	if r.action.Body == "" {
+		return nil
+	}
+	return &r.action.Body
+}
+
+func (r *changesetActionResolver) Labels() []string {
+	return r.action.Labels
+}
+
+func (r *changesetActionResolver) Reviewers() []string {
+	return r.action.Reviewers
+}
+
+func (r *changesetActionResolver) Author(ctx context.Context) (*graphqlbackend.UserResolver, error) {
+	return graphqlbackend.UserByIDInt32(ctx, r.action.UserID)
+}
+
+func (r *changesetActionResolver) CreatedAt() graphqlbackend.DateTime {
+	return graphqlbackend.DateTime{Time: r.action.CreatedAt}
+}
+
+func (r *changesetActionResolver) Status(ctx context.Context) (graphqlbackend.BackgroundProcessStatus, error) {
+	return r.store.GetChangesetActionStatus(ctx, r.action.ID)
+}
+
+func (r *changesetActionResolver) Failures(ctx context.Context) ([]graphqlbackend.ChangesetActionFailureResolver, error) {
+	js, _, err := r.store.ListChangesetActionJobs(ctx, ee.ListChangesetActionJobsOpts{
+		ChangesetActionID: r.action.ID,
+		OnlyWithError:     true,
+		Limit:             -1,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resolvers := make([]graphqlbackend.ChangesetActionFailureResolver, 0, len(js))
+	for _, j := range js {
+		resolvers = append(resolvers, &changesetActionFailureResolver{store: r.store, job: j})
+	}
+	return resolvers, nil
+}
+
+type changesetActionFailureResolver struct {
+	store *ee.Store
+	job   *campaigns.ChangesetActionJob
+}
+
+func (r *changesetActionFailureResolver) Changeset(ctx context.Context) (graphqlbackend.ExternalChangesetResolver, error) {
+	c, err := r.store.GetChangeset(ctx, ee.GetChangesetOpts{ID: r.job.ChangesetID})
+	if err != nil {
+		return nil, err
+	}
+	return &changesetResolver{store: r.store, Changeset: c}, nil
+}
+
+func (r *changesetActionFailureResolver) Error() string {
+	return r.job.Error
+}
@@ -0,0 +1,152 @@
+package resolvers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/graph-gophers/graphql-go"
+	"github.com/graph-gophers/graphql-go/relay"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/authz"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+	ee "github.com/sourcegraph/sourcegraph/enterprise/internal/campaigns"
+	"github.com/sourcegraph/sourcegraph/internal/campaigns"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/trace"
+)
+
+const changesetImportQueryIDKind = "ChangesetImportQuery"
+
+func marshalChangesetImportQueryID(id int64) graphql.ID {
+	return relay.MarshalID(changesetImportQueryIDKind, id)
+}
+
+func unmarshalChangesetImportQueryID(id graphql.ID) (queryID int64, err error) {
+	err = relay.UnmarshalSpec(id, &queryID)
+	return
+}
+
+func (r *Resolver) ImportChangesets(ctx context.Context, args *graphqlbackend.ImportChangesetsArgs) (_ graphqlbackend.ChangesetImportQueryResolver, err error) {
+	tr, ctx := trace.New(ctx, "Resolver.ImportChangesets", fmt.Sprintf("Campaign: %q, ExternalService: %q", args.Campaign, args.ExternalService))
+	defer func() {
+		tr.SetError(err)
+		tr.Finish()
+	}()
+
+	campaignID, err := unmarshalCampaignID(args.Campaign)
+	if err != nil {
+		return nil, err
+	}
+
+	// 🚨 SECURITY: Only site admins may import changesets, since they're
+	// searched for and synced with the site's code host tokens.
+	if err := checkSiteAdminAccess(ctx); err != nil {
+		return nil, err
+	}
+
+	externalServiceID, err := args.ExternalServiceID()
+	if err != nil {
+		return nil, err
+	}
+
+	q := &campaigns.ChangesetImportQuery{
+		CampaignID:        campaignID,
+		ExternalServiceID: externalServiceID,
+		Query:             args.Query,
+	}
+
+	svc := ee.NewService(r.store, gitserver.DefaultClient, r.httpFactory)
+	if _, err = svc.ImportChangesets(ctx, q); err != nil {
+		return nil, err
+	}
+
+	return newChangesetImportQueryResolver(q), nil
+}
+
+func (r *Resolver) DeleteChangesetImportQuery(ctx context.Context, args *graphqlbackend.DeleteChangesetImportQueryArgs) (_ *graphqlbackend.EmptyResponse, err error) {
+	tr, ctx := trace.New(ctx, "Resolver.DeleteChangesetImportQuery", fmt.Sprintf("ChangesetImportQuery: %q", args.ChangesetImportQuery))
+	defer func() {
+		tr.SetError(err)
+		tr.Finish()
+	}()
+
+	queryID, err := unmarshalChangesetImportQueryID(args.ChangesetImportQuery)
+	if err != nil {
+		return nil, err
+	}
+
+	// 🚨 SECURITY: Only site admins and admins of the campaign's organization
+	// may modify changesets and campaigns.
+	q, err := r.store.GetChangesetImportQuery(ctx, ee.GetChangesetImportQueryOpts{ID: queryID})
+	if err != nil {
+		if err == ee.ErrNoResults {
+			if adminErr := backend.CheckCurrentUserIsSiteAdminWithScope(ctx, authz.ScopeCampaignsWrite); adminErr != nil {
+				return nil, adminErr
+			}
+		}
+		return nil, err
+	}
+	if err := r.checkCampaignAdminAccess(ctx, q.CampaignID); err != nil {
+		return nil, err
+	}
+
+	if err = r.store.DeleteChangesetImportQuery(ctx, queryID); err != nil {
+		return nil, err
+	}
+
+	return &graphqlbackend.EmptyResponse{}, nil
+}
+
+func (r *campaignResolver) ChangesetImportQueries(ctx context.Context) ([]graphqlbackend.ChangesetImportQueryResolver, error) {
+	qs, _, err := r.store.ListChangesetImportQueries(ctx, ee.ListChangesetImportQueriesOpts{
+		CampaignID: r.Campaign.ID,
+		Limit:      -1,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resolvers := make([]graphqlbackend.ChangesetImportQueryResolver, 0, len(qs))
+	for _, q := range qs {
+		resolvers = append(resolvers, newChangesetImportQueryResolver(q))
+	}
+	return resolvers, nil
+}
+
+type changesetImportQueryResolver struct {
+	graphqlbackend.ExternalServiceField
+	query *campaigns.ChangesetImportQuery
+}
+
+func newChangesetImportQueryResolver(q *campaigns.ChangesetImportQuery) *changesetImportQueryResolver {
+	return &changesetImportQueryResolver{
+		ExternalServiceField: graphqlbackend.ExternalServiceField{ExternalServiceID: q.ExternalServiceID},
+		query:                q,
+	}
+}
+
+func (r *changesetImportQueryResolver) ID() graphql.ID {
+	return marshalChangesetImportQueryID(r.query.ID)
+}
+
+func (r *changesetImportQueryResolver) Query() string {
+	return r.query.Query
+}
+
+func (r *changesetImportQueryResolver) LastImportedAt() *graphqlbackend.DateTime {
+	if r.query.LastImportedAt.IsZero() {
+		return nil
+	}
+	return &graphqlbackend.DateTime{Time: r.query.LastImportedAt}
+}
+
+func (r *changesetImportQueryResolver) LastError() *string {
+	if r.query.LastError == "" {
+		return nil
+	}
+	return &r.query.LastError
+}
+
+func (r *changesetImportQueryResolver) CreatedAt() graphqlbackend.DateTime {
+	return graphqlbackend.DateTime{Time: r.query.CreatedAt}
+}
@@ -0,0 +1,96 @@
+package resolvers
+
+import (
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+	"github.com/sourcegraph/sourcegraph/internal/campaigns"
+)
+
+func changesetOptionsFromInput(in *graphqlbackend.ChangesetOptionsInput) campaigns.ChangesetOptions {
+	var opts campaigns.ChangesetOptions
+	if in.TitleTemplate != nil {
+		opts.TitleTemplate = *in.TitleTemplate
+	}
+	if in.BodyTemplate != nil {
+		opts.BodyTemplate = *in.BodyTemplate
+	}
+	if in.Labels != nil && len(*in.Labels) > 0 {
+		opts.Labels = *in.Labels
+	}
+	if in.Reviewers != nil {
+		opts.Reviewers = changesetUsersRuleFromInput(in.Reviewers)
+	}
+	if in.Assignees != nil {
+		opts.Assignees = changesetUsersRuleFromInput(in.Assignees)
+	}
+	if in.Draft != nil {
+		opts.Draft = *in.Draft
+	}
+	return opts
+}
+
+func changesetUsersRuleFromInput(in *graphqlbackend.ChangesetUsersRuleInput) campaigns.ChangesetUsersRule {
+	var rule campaigns.ChangesetUsersRule
+	if in.Users != nil && len(*in.Users) > 0 {
+		rule.Users = *in.Users
+	}
+	if in.FromCodeOwners != nil {
+		rule.FromCodeOwners = *in.FromCodeOwners
+	}
+	return rule
+}
+
+func (r *campaignResolver) ChangesetOptions() graphqlbackend.ChangesetOptionsResolver {
+	return &changesetOptionsResolver{opts: r.Campaign.ChangesetOptions}
+}
+
+type changesetOptionsResolver struct {
+	opts campaigns.ChangesetOptions
+}
+
+func (r *changesetOptionsResolver) TitleTemplate() *string {
+	if r.opts.TitleTemplate == "" {
+		return nil
+	}
+	return &r.opts.TitleTemplate
+}
+
+func (r *changesetOptionsResolver) BodyTemplate() *string {
+	if r.opts.BodyTemplate == "" {
+		return nil
+	}
+	return &r.opts.BodyTemplate
+}
+
+func (r *changesetOptionsResolver) Labels() []string {
+	if r.opts.Labels == nil {
+		return []string{}
+	}
+	return r.opts.Labels
+}
+
+func (r *changesetOptionsResolver) Reviewers() graphqlbackend.ChangesetUsersRuleResolver {
+	return &changesetUsersRuleResolver{rule: r.opts.Reviewers}
+}
+
+func (r *changesetOptionsResolver) Assignees() graphqlbackend.ChangesetUsersRuleResolver {
+	return &changesetUsersRuleResolver{rule: r.opts.Assignees}
+}
+
+func (r *changesetOptionsResolver) Draft() bool {
+	return r.opts.Draft
+}
+
+type changesetUsersRuleResolver struct {
+	rule campaigns.ChangesetUsersRule
+}
+
+func (r *changesetUsersRuleResolver) Users() []string {
+	if r.rule.Users == nil {
+		return []string{}
+	}
+	return r.rule.Users
+}
+
+func (r *changesetUsersRuleResolver) FromCodeOwners() bool {
+	return r.rule.FromCodeOwners
+}
@@ -230,6 +230,13 @@ func (r *changesetResolver) CheckState(ctx context.Context) (*campaigns.Changese
 	return &state, nil
 }
 
+func (r *changesetResolver) RebaseState() campaigns.ChangesetRebaseState {
+	if r.Changeset.RebaseState == "" {
+		return campaigns.ChangesetRebaseStateUpToDate
+	}
+	return r.Changeset.RebaseState
+}
+
 func (r *changesetResolver) Labels(ctx context.Context) ([]graphqlbackend.ChangesetLabelResolver, error) {
 	// Only GitHub supports labels on pull requests so don't make a DB call unless we need to
 	if _, ok := r.Changeset.Metadata.(*github.PullRequest); !ok {
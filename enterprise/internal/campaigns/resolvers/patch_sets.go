@@ -76,6 +76,10 @@ func (r *patchSetResolver) PreviewURL() string {
 	return u.String()
 }
 
+func (r *patchSetResolver) Status(ctx context.Context) (graphqlbackend.BackgroundProcessStatus, error) {
+	return r.store.GetPatchSetStatus(ctx, r.patchSet.ID)
+}
+
 type patchesConnectionResolver struct {
 	store *ee.Store
 	opts  ee.ListPatchesOpts
@@ -0,0 +1,75 @@
+package resolvers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/graph-gophers/graphql-go/relay"
+	"github.com/pkg/errors"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+)
+
+func TestOrgAdminCodeHostActions(t *testing.T) {
+	db.Mocks.Users.GetByCurrentAuthUser = func(context.Context) (*types.User, error) {
+		return &types.User{ID: 1}, nil
+	}
+	db.Mocks.OrgMembers.GetByOrgIDAndUserID = func(ctx context.Context, orgID, userID int32) (*types.OrgMembership, error) {
+		return &types.OrgMembership{OrgID: orgID, UserID: userID, Role: types.OrgRoleAdmin}, nil
+	}
+	defer func() { db.Mocks = db.MockStores{} }()
+
+	ctx := actor.WithActor(context.Background(), &actor.Actor{UID: 1})
+	r := &Resolver{}
+	campaign := marshalCampaignID(1)
+
+	for name, mutation := range map[string]func() error{
+		"createCampaign without draft": func() error {
+			args := &graphqlbackend.CreateCampaignArgs{}
+			args.Input.Namespace = relay.MarshalID("Org", 1)
+			args.Input.Name = "c"
+			patchSet := marshalPatchSetID(1)
+			args.Input.PatchSet = &patchSet
+			_, err := r.CreateCampaign(ctx, args)
+			return err
+		},
+		"publishCampaign": func() error {
+			_, err := r.PublishCampaign(ctx, &graphqlbackend.PublishCampaignArgs{Campaign: campaign})
+			return err
+		},
+		"retryCampaign": func() error {
+			_, err := r.RetryCampaign(ctx, &graphqlbackend.RetryCampaignArgs{Campaign: campaign})
+			return err
+		},
+		"addChangesetsToCampaign": func() error {
+			_, err := r.AddChangesetsToCampaign(ctx, &graphqlbackend.AddChangesetsToCampaignArgs{Campaign: campaign})
+			return err
+		},
+		"importChangesets": func() error {
+			_, err := r.ImportChangesets(ctx, &graphqlbackend.ImportChangesetsArgs{Campaign: campaign})
+			return err
+		},
+		"performChangesetAction": func() error {
+			args := &graphqlbackend.PerformChangesetActionArgs{Campaign: campaign}
+			args.Input.Type = "COMMENT"
+			_, err := r.PerformChangesetAction(ctx, args)
+			return err
+		},
+		"createCampaignNotificationSubscription": func() error {
+			args := &graphqlbackend.CreateCampaignNotificationSubscriptionArgs{Campaign: campaign}
+			args.Input.Channel = "WEBHOOK"
+			_, err := r.CreateCampaignNotificationSubscription(ctx, args)
+			return err
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if err := mutation(); errors.Cause(err) != backend.ErrMustBeSiteAdmin {
+				t.Fatalf("have error %v, want %v", err, backend.ErrMustBeSiteAdmin)
+			}
+		})
+	}
+}
@@ -0,0 +1,201 @@
+package resolvers
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/graph-gophers/graphql-go"
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+	"github.com/sourcegraph/sourcegraph/cmd/repo-updater/repos"
+	ee "github.com/sourcegraph/sourcegraph/enterprise/internal/campaigns"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/campaigns"
+)
+
+func (r *Resolver) CampaignsReport(ctx context.Context, args *graphqlbackend.CampaignsReportArgs) (graphqlbackend.CampaignsReportResolver, error) {
+	// 🚨 SECURITY: Only site admins or users when read-access is enabled may access campaigns.
+	if err := allowReadAccess(ctx); err != nil {
+		return nil, err
+	}
+
+	if args.StaleAfterDays < 0 {
+		return nil, errors.New("staleAfterDays cannot be negative")
+	}
+
+	cs, err := r.reportChangesets(ctx, args.Campaigns)
+	if err != nil {
+		return nil, err
+	}
+
+	reposByID := map[api.RepoID]*repos.Repo{}
+	repoNames := map[api.RepoID]api.RepoName{}
+	var events []ee.Event
+
+	if len(cs) > 0 {
+		repoIDs := make([]api.RepoID, 0, len(cs))
+		changesetIDs := make([]int64, 0, len(cs))
+		for _, c := range cs {
+			repoIDs = append(repoIDs, c.RepoID)
+			changesetIDs = append(changesetIDs, c.ID)
+		}
+
+		reposStore := repos.NewDBStore(r.store.DB(), sql.TxOptions{})
+		rs, err := reposStore.ListRepos(ctx, repos.StoreListReposArgs{IDs: repoIDs})
+		if err != nil {
+			return nil, err
+		}
+		for _, repo := range rs {
+			reposByID[repo.ID] = repo
+			repoNames[repo.ID] = api.RepoName(repo.Name)
+		}
+
+		es, _, err := r.store.ListChangesetEvents(ctx, ee.ListChangesetEventsOpts{
+			ChangesetIDs: changesetIDs,
+			Limit:        -1,
+		})
+		if err != nil {
+			return nil, err
+		}
+		events = make([]ee.Event, len(es))
+		for i, e := range es {
+			events[i] = e
+		}
+	}
+
+	staleSince := time.Now().AddDate(0, 0, -int(args.StaleAfterDays))
+
+	return &campaignsReportResolver{
+		store:     r.store,
+		report:    ee.NewCampaignsReport(staleSince, cs, repoNames, events...),
+		reposByID: reposByID,
+	}, nil
+}
+
+// reportChangesets returns the changesets of the campaigns with the given
+// IDs, or the changesets of all campaigns if ids is nil.
+func (r *Resolver) reportChangesets(ctx context.Context, ids *[]graphql.ID) ([]*campaigns.Changeset, error) {
+	if ids == nil {
+		all, _, err := r.store.ListChangesets(ctx, ee.ListChangesetsOpts{Limit: -1})
+		if err != nil {
+			return nil, err
+		}
+
+		cs := all[:0]
+		for _, c := range all {
+			if len(c.CampaignIDs) > 0 {
+				cs = append(cs, c)
+			}
+		}
+		return cs, nil
+	}
+
+	var cs []*campaigns.Changeset
+	seen := map[int64]bool{}
+	for _, id := range *ids {
+		campaignID, err := unmarshalCampaignID(id)
+		if err != nil {
+			return nil, err
+		}
+
+		campaignChangesets, _, err := r.store.ListChangesets(ctx, ee.ListChangesetsOpts{
+			CampaignID: campaignID,
+			Limit:      -1,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, c := range campaignChangesets {
+			if !seen[c.ID] {
+				seen[c.ID] = true
+				cs = append(cs, c)
+			}
+		}
+	}
+	return cs, nil
+}
+
+type campaignsReportResolver struct {
+	store     *ee.Store
+	report    *ee.CampaignsReport
+	reposByID map[api.RepoID]*repos.Repo
+}
+
+func (r *campaignsReportResolver) TotalCount() int32 {
+	return int32(len(r.report.Changesets))
+}
+
+func (r *campaignsReportResolver) TimeToFirstReview() graphqlbackend.DurationDistributionResolver {
+	return &durationDistributionResolver{dist: r.report.TimeToFirstReview()}
+}
+
+func (r *campaignsReportResolver) TimeToMerge() graphqlbackend.DurationDistributionResolver {
+	return &durationDistributionResolver{dist: r.report.TimeToMerge()}
+}
+
+func (r *campaignsReportResolver) StaleChangesets(ctx context.Context) ([]graphqlbackend.ExternalChangesetResolver, error) {
+	stale := r.report.StaleChangesets()
+	resolvers := make([]graphqlbackend.ExternalChangesetResolver, 0, len(stale))
+	for _, c := range stale {
+		repo, ok := r.reposByID[c.Changeset.RepoID]
+		if !ok {
+			// The repository was deleted since.
+			continue
+		}
+		resolvers = append(resolvers, &changesetResolver{
+			store:         r.store,
+			Changeset:     c.Changeset,
+			preloadedRepo: repo,
+		})
+	}
+	return resolvers, nil
+}
+
+func (r *campaignsReportResolver) RepositoryOwners() []graphqlbackend.CampaignsReportRepositoryOwnerResolver {
+	owners := r.report.ByRepoOwner()
+	resolvers := make([]graphqlbackend.CampaignsReportRepositoryOwnerResolver, len(owners))
+	for i, o := range owners {
+		resolvers[i] = &repositoryOwnerResolver{counts: o}
+	}
+	return resolvers
+}
+
+func (r *campaignsReportResolver) CSV() (string, error) {
+	var b strings.Builder
+	if err := r.report.WriteCSV(&b); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+type durationDistributionResolver struct {
+	dist ee.DurationDistribution
+}
+
+func (r *durationDistributionResolver) Count() int32 { return int32(r.dist.Count) }
+func (r *durationDistributionResolver) Mean() *int32 { return r.seconds(r.dist.Mean) }
+func (r *durationDistributionResolver) P50() *int32  { return r.seconds(r.dist.P50) }
+func (r *durationDistributionResolver) P90() *int32  { return r.seconds(r.dist.P90) }
+func (r *durationDistributionResolver) Max() *int32  { return r.seconds(r.dist.Max) }
+
+func (r *durationDistributionResolver) seconds(d time.Duration) *int32 {
+	if r.dist.Count == 0 {
+		return nil
+	}
+	s := int32(d / time.Second)
+	return &s
+}
+
+type repositoryOwnerResolver struct {
+	counts *ee.RepoOwnerCounts
+}
+
+func (r *repositoryOwnerResolver) Owner() string { return r.counts.Owner }
+func (r *repositoryOwnerResolver) Total() int32  { return r.counts.Total }
+func (r *repositoryOwnerResolver) Open() int32   { return r.counts.Open }
+func (r *repositoryOwnerResolver) Merged() int32 { return r.counts.Merged }
+func (r *repositoryOwnerResolver) Closed() int32 { return r.counts.Closed }
+func (r *repositoryOwnerResolver) Stale() int32  { return r.counts.Stale }
@@ -12,9 +12,11 @@ import (
 	"github.com/inconshreveable/log15"
 	"github.com/pkg/errors"
 	"github.com/sourcegraph/go-diff/diff"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/authz"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
 	"github.com/sourcegraph/sourcegraph/cmd/repo-updater/repos"
 	ee "github.com/sourcegraph/sourcegraph/enterprise/internal/campaigns"
 	"github.com/sourcegraph/sourcegraph/internal/api"
@@ -38,17 +40,92 @@ func NewResolver(db *sql.DB) graphqlbackend.CampaignsResolver {
 }
 
 func allowReadAccess(ctx context.Context) error {
+	// 🚨 SECURITY: Restricted access tokens need the campaigns:write scope to
+	// access campaigns, even if read-access is enabled.
+	if err := backend.CheckAccessTokenScope(ctx, authz.ScopeCampaignsWrite); err != nil {
+		return err
+	}
+
 	if readAccess := conf.CampaignsReadAccessEnabled(); readAccess {
 		return nil
 	}
 
-	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
+	if err := backend.CheckCurrentUserIsSiteAdminWithScope(ctx, authz.ScopeCampaignsWrite); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// checkCampaignAdminAccess returns an error if the current user may not modify
+// the campaign with the given ID, i.e. if they're neither a site admin nor an
+// admin of the organization whose namespace the campaign belongs to.
+func (r *Resolver) checkCampaignAdminAccess(ctx context.Context, campaignID int64) error {
+	err := backend.CheckCurrentUserIsSiteAdminWithScope(ctx, authz.ScopeCampaignsWrite)
+	if err != backend.ErrMustBeSiteAdmin {
+		return err
+	}
+
+	campaign, err := r.store.GetCampaign(ctx, ee.GetCampaignOpts{ID: campaignID})
+	if err != nil {
+		// Don't reveal the existence of campaigns to users who aren't
+		// allowed to modify them.
+		if err == ee.ErrNoResults {
+			return backend.ErrMustBeSiteAdmin
+		}
+		return err
+	}
+
+	return checkNamespaceAdminAccess(ctx, campaign.NamespaceOrgID)
+}
+
+// checkSiteAdminAccess returns an error if the current user is not a site
+// admin. Organization admins may manage the campaigns in their organization's
+// namespace, but actions that create, update, merge or close changesets on code
+// hosts use the tokens of the site's external services and are reserved to site
+// admins, as is sending notifications to webhooks.
+func checkSiteAdminAccess(ctx context.Context) error {
+	return backend.CheckCurrentUserIsSiteAdminWithScope(ctx, authz.ScopeCampaignsWrite)
+}
+
+// checkCampaignUnpublishedOrSiteAdmin returns an error if the current user is
+// not a site admin and the campaign with the given ID has already been
+// published, i.e. if changing it could change its changesets on code hosts.
+func (r *Resolver) checkCampaignUnpublishedOrSiteAdmin(ctx context.Context, campaignID int64) error {
+	err := checkSiteAdminAccess(ctx)
+	if err != backend.ErrMustBeSiteAdmin {
 		return err
 	}
 
+	campaign, err := r.store.GetCampaign(ctx, ee.GetCampaignOpts{ID: campaignID})
+	if err != nil {
+		return err
+	}
+	if len(campaign.ChangesetIDs) > 0 {
+		return backend.ErrMustBeSiteAdmin
+	}
+	publishedAt, err := r.store.GetLatestChangesetJobCreatedAt(ctx, campaign.ID)
+	if err != nil {
+		return err
+	}
+	if !publishedAt.IsZero() {
+		return backend.ErrMustBeSiteAdmin
+	}
 	return nil
 }
 
+// checkNamespaceAdminAccess returns an error if the current user may not
+// create or modify campaigns in the namespace of the organization with the
+// given ID. Organization admins may manage their organization's campaigns,
+// while all other campaigns can only be managed by site admins.
+func checkNamespaceAdminAccess(ctx context.Context, orgID int32) error {
+	err := backend.CheckCurrentUserIsSiteAdminWithScope(ctx, authz.ScopeCampaignsWrite)
+	if err != backend.ErrMustBeSiteAdmin || orgID == 0 {
+		return err
+	}
+	return backend.CheckOrgAccessWithScope(ctx, orgID, types.OrgRoleAdmin, authz.ScopeCampaignsWrite)
+}
+
 func (r *Resolver) ChangesetByID(ctx context.Context, id graphql.ID) (graphqlbackend.ExternalChangesetResolver, error) {
 	// 🚨 SECURITY: Only site admins or users when read-access is enabled may access changesets.
 	if err := allowReadAccess(ctx); err != nil {
@@ -72,9 +149,11 @@ func (r *Resolver) ChangesetByID(ctx context.Context, id graphql.ID) (graphqlbac
 }
 
 func (r *Resolver) CampaignByID(ctx context.Context, id graphql.ID) (graphqlbackend.CampaignResolver, error) {
-	// 🚨 SECURITY: Only site admins or users when read-access is enabled may access campaign.
-	if err := allowReadAccess(ctx); err != nil {
-		return nil, err
+	// 🚨 SECURITY: Only site admins, members of the campaign's organization or
+	// users when read-access is enabled may access campaign.
+	readErr := allowReadAccess(ctx)
+	if readErr != nil && readErr != backend.ErrMustBeSiteAdmin {
+		return nil, readErr
 	}
 
 	campaignID, err := unmarshalCampaignID(id)
@@ -84,12 +163,24 @@ func (r *Resolver) CampaignByID(ctx context.Context, id graphql.ID) (graphqlback
 
 	campaign, err := r.store.GetCampaign(ctx, ee.GetCampaignOpts{ID: campaignID})
 	if err != nil {
-		if err == ee.ErrNoResults {
+		if err == ee.ErrNoResults && readErr == nil {
 			return nil, nil
 		}
+		if err == ee.ErrNoResults {
+			return nil, readErr
+		}
 		return nil, err
 	}
 
+	if readErr != nil {
+		if campaign.NamespaceOrgID == 0 {
+			return nil, readErr
+		}
+		if err := backend.CheckOrgAccessWithScope(ctx, campaign.NamespaceOrgID, types.OrgRoleReadOnly, authz.ScopeCampaignsWrite); err != nil {
+			return nil, err
+		}
+	}
+
 	return &campaignResolver{store: r.store, Campaign: campaign}, nil
 }
 
@@ -138,13 +229,15 @@ func (r *Resolver) PatchSetByID(ctx context.Context, id graphql.ID) (graphqlback
 }
 
 func (r *Resolver) AddChangesetsToCampaign(ctx context.Context, args *graphqlbackend.AddChangesetsToCampaignArgs) (_ graphqlbackend.CampaignResolver, err error) {
-	// 🚨 SECURITY: Only site admins may modify changesets and campaigns for now.
-	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
+	campaignID, err := unmarshalCampaignID(args.Campaign)
+	if err != nil {
 		return nil, err
 	}
 
-	campaignID, err := unmarshalCampaignID(args.Campaign)
-	if err != nil {
+	// 🚨 SECURITY: Only site admins may add changesets to campaigns, since
+	// any changeset can be added and they're then updated with the site's code
+	// host tokens.
+	if err := checkSiteAdminAccess(ctx); err != nil {
 		return nil, err
 	}
 
@@ -215,16 +308,30 @@ func (r *Resolver) CreateCampaign(ctx context.Context, args *graphqlbackend.Crea
 		return nil, errors.Wrapf(err, "%v", backend.ErrNotAuthenticated)
 	}
 
-	// 🚨 SECURITY: Only site admins may create a campaign for now.
-	if !user.SiteAdmin {
-		return nil, backend.ErrMustBeSiteAdmin
-	}
-
 	campaign := &campaigns.Campaign{
 		Name:     args.Input.Name,
 		AuthorID: user.ID,
 	}
 
+	switch relay.UnmarshalKind(args.Input.Namespace) {
+	case "User":
+		err = relay.UnmarshalSpec(args.Input.Namespace, &campaign.NamespaceUserID)
+	case "Org":
+		err = relay.UnmarshalSpec(args.Input.Namespace, &campaign.NamespaceOrgID)
+	default:
+		err = errors.Errorf("Invalid namespace %q", args.Input.Namespace)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	// 🚨 SECURITY: Only site admins may create a campaign, except for admins
+	// of an organization, who may create campaigns in its namespace.
+	if err := checkNamespaceAdminAccess(ctx, campaign.NamespaceOrgID); err != nil {
+		return nil, err
+	}
+
 	if args.Input.Description != nil {
 		campaign.Description = *args.Input.Description
 	}
@@ -245,17 +352,25 @@ func (r *Resolver) CreateCampaign(ctx context.Context, args *graphqlbackend.Crea
 		draft = *args.Input.Draft
 	}
 
-	switch relay.UnmarshalKind(args.Input.Namespace) {
-	case "User":
-		err = relay.UnmarshalSpec(args.Input.Namespace, &campaign.NamespaceUserID)
-	case "Org":
-		err = relay.UnmarshalSpec(args.Input.Namespace, &campaign.NamespaceOrgID)
-	default:
-		err = errors.Errorf("Invalid namespace %q", args.Input.Namespace)
+	// 🚨 SECURITY: Only site admins may create campaigns that are published
+	// right away.
+	if campaign.PatchSetID != 0 && !draft {
+		if err := checkSiteAdminAccess(ctx); err != nil {
+			return nil, err
+		}
 	}
 
-	if err != nil {
-		return nil, err
+	if args.Input.AutoMerge != nil {
+		campaign.AutoMerge = *args.Input.AutoMerge
+	}
+	if args.Input.MergeMethod != nil {
+		campaign.MergeMethod = campaigns.ChangesetMergeMethod(*args.Input.MergeMethod)
+	}
+	if args.Input.MergesPerHour != nil {
+		campaign.MergesPerHour = *args.Input.MergesPerHour
+	}
+	if args.Input.ChangesetOptions != nil {
+		campaign.ChangesetOptions = changesetOptionsFromInput(args.Input.ChangesetOptions)
 	}
 
 	svc := ee.NewService(r.store, gitserver.DefaultClient, r.httpFactory)
@@ -274,13 +389,18 @@ func (r *Resolver) UpdateCampaign(ctx context.Context, args *graphqlbackend.Upda
 		tr.Finish()
 	}()
 
-	// 🚨 SECURITY: Only site admins may update campaigns for now
-	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
+	campaignID, err := unmarshalCampaignID(args.Input.ID)
+	if err != nil {
 		return nil, err
 	}
 
-	campaignID, err := unmarshalCampaignID(args.Input.ID)
-	if err != nil {
+	// 🚨 SECURITY: Only site admins and admins of the campaign's organization
+	// may update campaigns, and only site admins may update published
+	// campaigns, whose changesets are updated on the code hosts.
+	if err := r.checkCampaignAdminAccess(ctx, campaignID); err != nil {
+		return nil, err
+	}
+	if err := r.checkCampaignUnpublishedOrSiteAdmin(ctx, campaignID); err != nil {
 		return nil, err
 	}
 
@@ -288,6 +408,16 @@ func (r *Resolver) UpdateCampaign(ctx context.Context, args *graphqlbackend.Upda
 	updateArgs.Name = args.Input.Name
 	updateArgs.Description = args.Input.Description
 	updateArgs.Branch = args.Input.Branch
+	updateArgs.AutoMerge = args.Input.AutoMerge
+	updateArgs.MergesPerHour = args.Input.MergesPerHour
+	if args.Input.MergeMethod != nil {
+		method := campaigns.ChangesetMergeMethod(*args.Input.MergeMethod)
+		updateArgs.MergeMethod = &method
+	}
+	if args.Input.ChangesetOptions != nil {
+		opts := changesetOptionsFromInput(args.Input.ChangesetOptions)
+		updateArgs.ChangesetOptions = &opts
+	}
 
 	if args.Input.PatchSet != nil {
 		patchSetID, err := unmarshalPatchSetID(*args.Input.PatchSet)
@@ -323,15 +453,21 @@ func (r *Resolver) DeleteCampaign(ctx context.Context, args *graphqlbackend.Dele
 		tr.Finish()
 	}()
 
-	// 🚨 SECURITY: Only site admins may update campaigns for now
-	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
+	campaignID, err := unmarshalCampaignID(args.Campaign)
+	if err != nil {
 		return nil, err
 	}
 
-	campaignID, err := unmarshalCampaignID(args.Campaign)
-	if err != nil {
+	// 🚨 SECURITY: Only site admins and admins of the campaign's organization
+	// may delete campaigns, and only site admins may close their changesets.
+	if err := r.checkCampaignAdminAccess(ctx, campaignID); err != nil {
 		return nil, err
 	}
+	if args.CloseChangesets {
+		if err := checkSiteAdminAccess(ctx); err != nil {
+			return nil, err
+		}
+	}
 
 	svc := ee.NewService(r.store, gitserver.DefaultClient, r.httpFactory)
 	err = svc.DeleteCampaign(ctx, campaignID, args.CloseChangesets)
@@ -346,16 +482,16 @@ func (r *Resolver) RetryCampaign(ctx context.Context, args *graphqlbackend.Retry
 		tr.Finish()
 	}()
 
-	// 🚨 SECURITY: Only site admins may update campaigns for now
-	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
-		return nil, errors.Wrap(err, "checking if user is admin")
-	}
-
 	campaignID, err := unmarshalCampaignID(args.Campaign)
 	if err != nil {
 		return nil, errors.Wrap(err, "unmarshaling campaign id")
 	}
 
+	// 🚨 SECURITY: Only site admins may retry the creation of changesets.
+	if err := checkSiteAdminAccess(ctx); err != nil {
+		return nil, errors.Wrap(err, "checking if user is admin")
+	}
+
 	campaign, err := r.store.GetCampaign(ctx, ee.GetCampaignOpts{ID: campaignID})
 	if err != nil {
 		return nil, errors.Wrap(err, "getting campaign")
@@ -393,7 +529,7 @@ func (r *Resolver) Campaigns(ctx context.Context, args *graphqlbackend.ListCampa
 
 func (r *Resolver) CreateChangesets(ctx context.Context, args *graphqlbackend.CreateChangesetsArgs) (_ []graphqlbackend.ExternalChangesetResolver, err error) {
 	// 🚨 SECURITY: Only site admins may create changesets for now
-	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
+	if err := backend.CheckCurrentUserIsSiteAdminWithScope(ctx, authz.ScopeCampaignsWrite); err != nil {
 		return nil, err
 	}
 
@@ -541,7 +677,7 @@ func (r *Resolver) CreatePatchSetFromPatches(ctx context.Context, args graphqlba
 	}()
 
 	// 🚨 SECURITY: Only site admins may create patch sets for now.
-	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
+	if err := backend.CheckCurrentUserIsSiteAdminWithScope(ctx, authz.ScopeCampaignsWrite); err != nil {
 		return nil, err
 	}
 
@@ -589,23 +725,136 @@ func (r *Resolver) CreatePatchSetFromPatches(ctx context.Context, args graphqlba
 	return &patchSetResolver{store: r.store, patchSet: patchSet}, nil
 }
 
-func (r *Resolver) CloseCampaign(ctx context.Context, args *graphqlbackend.CloseCampaignArgs) (_ graphqlbackend.CampaignResolver, err error) {
-	tr, ctx := trace.New(ctx, "Resolver.CloseCampaign", fmt.Sprintf("Campaign: %q", args.Campaign))
+func (r *Resolver) CreatePatchSetFromReplacement(ctx context.Context, args *graphqlbackend.CreatePatchSetFromReplacementArgs) (_ graphqlbackend.PatchSetResolver, err error) {
+	tr, ctx := trace.New(ctx, "Resolver.CreatePatchSetFromReplacement", fmt.Sprintf("Query: %q", args.Replacement.Query))
 	defer func() {
 		tr.SetError(err)
 		tr.Finish()
 	}()
 
-	// 🚨 SECURITY: Only site admins may update campaigns for now
-	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
-		return nil, errors.Wrap(err, "checking if user is admin")
+	// 🚨 SECURITY: Only site admins may create patch sets for now.
+	if err := backend.CheckCurrentUserIsSiteAdminWithScope(ctx, authz.ScopeCampaignsWrite); err != nil {
+		return nil, err
+	}
+
+	user, err := backend.CurrentUser(ctx)
+	if err != nil {
+		return nil, errors.Wrapf(err, "%v", backend.ErrNotAuthenticated)
+	}
+	if user == nil {
+		return nil, backend.ErrNotAuthenticated
+	}
+
+	replacement := &campaigns.Replacement{
+		Query:   args.Replacement.Query,
+		Kind:    args.Replacement.Kind,
+		Match:   args.Replacement.Match,
+		Rewrite: args.Replacement.Rewrite,
+	}
+	if args.Replacement.FileExtension != nil {
+		replacement.FileExtension = *args.Replacement.FileExtension
+	}
+	if err := replacement.Validate(); err != nil {
+		return nil, err
+	}
+
+	repoIDs, err := searchRepoIDs(ctx, replacement.Query)
+	if err != nil {
+		return nil, err
+	}
+
+	svc := ee.NewService(r.store, gitserver.DefaultClient, r.httpFactory)
+	patchSet, err := svc.CreatePatchSetFromReplacement(ctx, replacement, repoIDs, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &patchSetResolver{store: r.store, patchSet: patchSet}, nil
+}
+
+// searchPageSize is the number of results requested per page of the
+// paginated search in searchRepoIDs, which is the maximum allowed.
+const searchPageSize = 5000
+
+// searchRepoIDs returns the IDs of the repositories with results for the
+// given search query. It pages through all results of the query, so that no
+// repository is missed because of a result limit.
+func searchRepoIDs(ctx context.Context, query string) ([]api.RepoID, error) {
+	var (
+		repoIDs []api.RepoID
+		seen    = make(map[api.RepoID]bool)
+		first   = int32(searchPageSize)
+		after   *string
+	)
+	for {
+		search, err := graphqlbackend.NewSearchImplementer(&graphqlbackend.SearchArgs{
+			Version: "V2",
+			Query:   query,
+			First:   &first,
+			After:   after,
+		})
+		if err != nil {
+			return nil, err
+		}
+		results, err := search.Results(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if alert := results.Alert(); alert != nil && len(results.Results()) == 0 {
+			return nil, errors.Errorf("search query %q: %s", query, alert.Title())
+		}
+
+		for _, res := range results.Results() {
+			var repo *graphqlbackend.RepositoryResolver
+			if r, ok := res.ToRepository(); ok {
+				repo = r
+			} else if fm, ok := res.ToFileMatch(); ok {
+				repo = fm.Repository()
+			} else if c, ok := res.ToCommitSearchResult(); ok {
+				repo = c.Commit().Repository()
+			}
+			if repo == nil {
+				continue
+			}
+
+			id := repo.Type().ID
+			if !seen[id] {
+				seen[id] = true
+				repoIDs = append(repoIDs, id)
+			}
+		}
+
+		pageInfo := results.PageInfo()
+		if !pageInfo.HasNextPage() {
+			return repoIDs, nil
+		}
+		after = pageInfo.EndCursor()
 	}
+}
+
+func (r *Resolver) CloseCampaign(ctx context.Context, args *graphqlbackend.CloseCampaignArgs) (_ graphqlbackend.CampaignResolver, err error) {
+	tr, ctx := trace.New(ctx, "Resolver.CloseCampaign", fmt.Sprintf("Campaign: %q", args.Campaign))
+	defer func() {
+		tr.SetError(err)
+		tr.Finish()
+	}()
 
 	campaignID, err := unmarshalCampaignID(args.Campaign)
 	if err != nil {
 		return nil, errors.Wrap(err, "unmarshaling campaign id")
 	}
 
+	// 🚨 SECURITY: Only site admins and admins of the campaign's organization
+	// may close campaigns, and only site admins may close their changesets.
+	if err := r.checkCampaignAdminAccess(ctx, campaignID); err != nil {
+		return nil, errors.Wrap(err, "checking if user is admin")
+	}
+	if args.CloseChangesets {
+		if err := checkSiteAdminAccess(ctx); err != nil {
+			return nil, errors.Wrap(err, "checking if user is admin")
+		}
+	}
+
 	svc := ee.NewService(r.store, gitserver.DefaultClient, r.httpFactory)
 
 	campaign, err := svc.CloseCampaign(ctx, campaignID, args.CloseChangesets)
@@ -623,16 +872,16 @@ func (r *Resolver) PublishCampaign(ctx context.Context, args *graphqlbackend.Pub
 		tr.Finish()
 	}()
 
-	// 🚨 SECURITY: Only site admins may update campaigns for now
-	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
-		return nil, errors.Wrap(err, "checking if user is admin")
-	}
-
 	campaignID, err := unmarshalCampaignID(args.Campaign)
 	if err != nil {
 		return nil, errors.Wrap(err, "unmarshaling campaign id")
 	}
 
+	// 🚨 SECURITY: Only site admins may publish campaigns.
+	if err := checkSiteAdminAccess(ctx); err != nil {
+		return nil, errors.Wrap(err, "checking if user is admin")
+	}
+
 	svc := ee.NewService(r.store, gitserver.DefaultClient, r.httpFactory)
 	campaign, err := svc.PublishCampaign(ctx, campaignID)
 	if err != nil {
@@ -650,7 +899,7 @@ func (r *Resolver) PublishChangeset(ctx context.Context, args *graphqlbackend.Pu
 	}()
 
 	// 🚨 SECURITY: Only site admins may update campaigns for now
-	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
+	if err := backend.CheckCurrentUserIsSiteAdminWithScope(ctx, authz.ScopeCampaignsWrite); err != nil {
 		return nil, errors.Wrap(err, "checking if user is admin")
 	}
 
@@ -676,7 +925,7 @@ func (r *Resolver) SyncChangeset(ctx context.Context, args *graphqlbackend.SyncC
 	}()
 
 	// 🚨 SECURITY: Only site admins may update campaigns for now
-	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
+	if err := backend.CheckCurrentUserIsSiteAdminWithScope(ctx, authz.ScopeCampaignsWrite); err != nil {
 		return nil, errors.Wrap(err, "checking if user is admin")
 	}
 
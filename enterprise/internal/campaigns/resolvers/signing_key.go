@@ -0,0 +1,31 @@
+package resolvers
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+	"github.com/sourcegraph/sourcegraph/internal/secret"
+	"github.com/sourcegraph/sourcegraph/internal/trace"
+)
+
+func (r *Resolver) EncryptCampaignsSigningKey(ctx context.Context, args *graphqlbackend.EncryptCampaignsSigningKeyArgs) (_ string, err error) {
+	tr, ctx := trace.New(ctx, "Resolver.EncryptCampaignsSigningKey", "")
+	defer func() {
+		tr.SetError(err)
+		tr.Finish()
+	}()
+
+	// 🚨 SECURITY: Only site admins may configure the signing key, since
+	// it's used to sign the commits of all campaigns.
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
+		return "", errors.Wrap(err, "checking if user is admin")
+	}
+
+	if args.Key == "" {
+		return "", errors.New("signing key must not be empty")
+	}
+
+	return secret.Encrypt([]byte(args.Key))
+}
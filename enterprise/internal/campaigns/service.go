@@ -4,6 +4,8 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"reflect"
+	"strings"
 	"time"
 
 	"github.com/hashicorp/go-multierror"
@@ -13,6 +15,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/cmd/repo-updater/repos"
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/campaigns"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/github"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
 	"github.com/sourcegraph/sourcegraph/internal/httpcli"
 	"github.com/sourcegraph/sourcegraph/internal/trace"
@@ -97,6 +100,55 @@ func (s *Service) CreatePatchSetFromPatches(ctx context.Context, patches []*camp
 	return patchSet, nil
 }
 
+// CreatePatchSetFromReplacement creates a PatchSet with the given Replacement
+// and a PatchJob for each of the given repositories that is supported by
+// campaigns. The Patches of the PatchSet are generated by the PatchJobs, in
+// the background.
+func (s *Service) CreatePatchSetFromReplacement(ctx context.Context, r *campaigns.Replacement, repoIDs []api.RepoID, userID int32) (patchSet *campaigns.PatchSet, err error) {
+	tr, ctx := trace.New(ctx, "Service.CreatePatchSetFromReplacement", fmt.Sprintf("Kind: %q, Query: %q", r.Kind, r.Query))
+	defer func() {
+		tr.SetError(err)
+		tr.Finish()
+	}()
+
+	if userID == 0 {
+		return nil, backend.ErrNotAuthenticated
+	}
+	if err = r.Validate(); err != nil {
+		return nil, err
+	}
+
+	reposStore := repos.NewDBStore(s.store.DB(), sql.TxOptions{})
+	rs, err := reposStore.ListRepos(ctx, repos.StoreListReposArgs{IDs: repoIDs})
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.store.Transact(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Done(&err)
+
+	patchSet = &campaigns.PatchSet{UserID: userID, Replacement: r}
+	if err = tx.CreatePatchSet(ctx, patchSet); err != nil {
+		return nil, err
+	}
+
+	for _, repo := range rs {
+		if !campaigns.IsRepoSupported(&repo.ExternalRepo) {
+			continue
+		}
+
+		job := &campaigns.PatchJob{PatchSetID: patchSet.ID, RepoID: repo.ID}
+		if err = tx.CreatePatchJob(ctx, job); err != nil {
+			return nil, err
+		}
+	}
+
+	return patchSet, nil
+}
+
 // CreateCampaign creates the Campaign. When a PatchSetID is set on the
 // Campaign and the Campaign is not created as a draft, it calls
 // CreateChangesetJobs inside the same transaction in which it creates the
@@ -113,6 +165,16 @@ func (s *Service) CreateCampaign(ctx context.Context, c *campaigns.Campaign, dra
 		return ErrCampaignNameBlank
 	}
 
+	if c.MergeMethod == "" {
+		c.MergeMethod = campaigns.ChangesetMergeMethodMerge
+	}
+	if err = validateMergePolicy(c.MergeMethod, c.MergesPerHour); err != nil {
+		return err
+	}
+	if err = ValidateChangesetOptions(&c.ChangesetOptions); err != nil {
+		return err
+	}
+
 	tx, err := s.store.Transact(ctx)
 	if err != nil {
 		return err
@@ -397,6 +459,121 @@ func (s *Service) CloseOpenChangesets(ctx context.Context, cs []*campaigns.Chang
 	return SyncChangesetsWithSources(ctx, s.store, bySource)
 }
 
+// ErrChangesetImportQueryBlank is returned by ImportChangesets if the query
+// of the ChangesetImportQuery is blank.
+var ErrChangesetImportQueryBlank = errors.New("Changeset import query cannot be blank")
+
+// ImportChangesets imports the changesets matching the given
+// ChangesetImportQuery into its campaign and then creates the query, so that
+// the ChangesetSyncer of its external service re-runs it periodically. It
+// returns the changesets that were added to the campaign.
+func (s *Service) ImportChangesets(ctx context.Context, q *campaigns.ChangesetImportQuery) (added []*campaigns.Changeset, err error) {
+	traceTitle := fmt.Sprintf("campaign: %d, externalService: %d, query: %q", q.CampaignID, q.ExternalServiceID, q.Query)
+	tr, ctx := trace.New(ctx, "service.ImportChangesets", traceTitle)
+	defer func() {
+		tr.SetError(err)
+		tr.Finish()
+	}()
+
+	q.Query = strings.TrimSpace(q.Query)
+	if q.Query == "" {
+		return nil, ErrChangesetImportQueryBlank
+	}
+
+	campaign, err := s.store.GetCampaign(ctx, GetCampaignOpts{ID: q.CampaignID})
+	if err != nil {
+		return nil, errors.Wrap(err, "getting campaign")
+	}
+
+	if campaign.PatchSetID != 0 {
+		return nil, errors.New("Changesets can only be imported into campaigns that don't create their own changesets")
+	}
+
+	if !campaign.ClosedAt.IsZero() {
+		return nil, errors.New("Changesets cannot be imported into a closed campaign")
+	}
+
+	importer := &ChangesetImporter{
+		SyncStore:   s.store,
+		ReposStore:  repos.NewDBStore(s.store.DB(), sql.TxOptions{}),
+		HTTPFactory: s.cf,
+	}
+
+	if added, err = importer.Import(ctx, q); err != nil {
+		return nil, err
+	}
+
+	q.LastImportedAt = s.clock()
+	if err = s.store.CreateChangesetImportQuery(ctx, q); err != nil {
+		return nil, err
+	}
+
+	return added, nil
+}
+
+// ErrNoOpenChangesets is returned by CreateChangesetAction if the campaign
+// has no open changesets to perform the action on.
+var ErrNoOpenChangesets = errors.New("Campaign has no open changesets")
+
+// CreateChangesetAction creates the given ChangesetAction and a
+// ChangesetActionJob for each open changeset of its campaign. The action is
+// performed on the changesets by the ChangesetActionWorker, in the
+// background.
+func (s *Service) CreateChangesetAction(ctx context.Context, a *campaigns.ChangesetAction) (err error) {
+	traceTitle := fmt.Sprintf("campaign: %d, type: %q", a.CampaignID, a.Type)
+	tr, ctx := trace.New(ctx, "service.CreateChangesetAction", traceTitle)
+	defer func() {
+		tr.SetError(err)
+		tr.Finish()
+	}()
+
+	if a.UserID == 0 {
+		return backend.ErrNotAuthenticated
+	}
+	if err = a.Validate(); err != nil {
+		return err
+	}
+
+	tx, err := s.store.Transact(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Done(&err)
+
+	campaign, err := tx.GetCampaign(ctx, GetCampaignOpts{ID: a.CampaignID})
+	if err != nil {
+		return errors.Wrap(err, "getting campaign")
+	}
+
+	open := campaigns.ChangesetStateOpen
+	cs, _, err := tx.ListChangesets(ctx, ListChangesetsOpts{
+		CampaignID:     campaign.ID,
+		Limit:          -1,
+		WithoutDeleted: true,
+		ExternalState:  &open,
+	})
+	if err != nil {
+		return errors.Wrap(err, "listing open changesets")
+	}
+
+	if len(cs) == 0 {
+		return ErrNoOpenChangesets
+	}
+
+	if err = tx.CreateChangesetAction(ctx, a); err != nil {
+		return err
+	}
+
+	for _, c := range cs {
+		job := &campaigns.ChangesetActionJob{ChangesetActionID: a.ID, ChangesetID: c.ID}
+		if err = tx.CreateChangesetActionJob(ctx, job); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 // CreateChangesetJobForPatch creates a ChangesetJob for the
 // Patch with the given ID. The Patch has to belong to a
 // PatchSet that was attached to a Campaign.
@@ -458,6 +635,12 @@ type UpdateCampaignArgs struct {
 	Description *string
 	Branch      *string
 	PatchSet    *int64
+
+	AutoMerge     *bool
+	MergeMethod   *campaigns.ChangesetMergeMethod
+	MergesPerHour *int32
+
+	ChangesetOptions *campaigns.ChangesetOptions
 }
 
 // ErrCampaignNameBlank is returned by CreateCampaign or UpdateCampaign if the
@@ -476,6 +659,14 @@ var ErrPublishedCampaignBranchChange = errors.New("Published campaign branch can
 // specified patch set is already attached to another campaign.
 var ErrPatchSetDuplicate = errors.New("Campaign cannot use the same patch set as another campaign")
 
+// ErrInvalidMergeMethod is returned by CreateCampaign or UpdateCampaign if
+// the specified merge method is not a valid ChangesetMergeMethod.
+var ErrInvalidMergeMethod = errors.New("Campaign merge method is invalid")
+
+// ErrNegativeMergesPerHour is returned by CreateCampaign or UpdateCampaign if
+// the specified maximum number of merges per hour is negative.
+var ErrNegativeMergesPerHour = errors.New("Campaign merges per hour cannot be negative")
+
 // ErrUpdateClosedCampaign is returned by UpdateCampaign if the Campaign
 // has been closed.
 var ErrUpdateClosedCampaign = errors.New("cannot update a closed Campaign")
@@ -484,6 +675,16 @@ var ErrUpdateClosedCampaign = errors.New("cannot update a closed Campaign")
 // is to be attached to a manual campaign.
 var ErrManualCampaignUpdatePatchIllegal = errors.New("cannot update a manual campaign to have a patch set")
 
+func validateMergePolicy(method campaigns.ChangesetMergeMethod, mergesPerHour int32) error {
+	if !method.Valid() {
+		return ErrInvalidMergeMethod
+	}
+	if mergesPerHour < 0 {
+		return ErrNegativeMergesPerHour
+	}
+	return nil
+}
+
 // UpdateCampaign updates the Campaign with the given arguments.
 func (s *Service) UpdateCampaign(ctx context.Context, args UpdateCampaignArgs) (campaign *campaigns.Campaign, detachedChangesets []*campaigns.Changeset, err error) {
 	traceTitle := fmt.Sprintf("campaign: %d", args.Campaign)
@@ -525,6 +726,15 @@ func (s *Service) UpdateCampaign(ctx context.Context, args UpdateCampaignArgs) (
 		updateAttributes = true
 	}
 
+	if args.ChangesetOptions != nil && !reflect.DeepEqual(campaign.ChangesetOptions, *args.ChangesetOptions) {
+		if err := ValidateChangesetOptions(args.ChangesetOptions); err != nil {
+			return nil, nil, err
+		}
+
+		campaign.ChangesetOptions = args.ChangesetOptions.Clone()
+		updateAttributes = true
+	}
+
 	oldPatchSetID := campaign.PatchSetID
 	if oldPatchSetID == 0 && args.PatchSet != nil {
 		return nil, nil, ErrManualCampaignUpdatePatchIllegal
@@ -552,7 +762,35 @@ func (s *Service) UpdateCampaign(ctx context.Context, args UpdateCampaignArgs) (
 		updateBranch = true
 	}
 
+	// The merge policy only affects the Campaign itself, not its
+	// ChangesetJobs, so it can be updated regardless of the Campaign's status.
+	var updateMergePolicy bool
+
+	if args.AutoMerge != nil && campaign.AutoMerge != *args.AutoMerge {
+		campaign.AutoMerge = *args.AutoMerge
+		updateMergePolicy = true
+	}
+
+	if args.MergeMethod != nil && campaign.MergeMethod != *args.MergeMethod {
+		campaign.MergeMethod = *args.MergeMethod
+		updateMergePolicy = true
+	}
+
+	if args.MergesPerHour != nil && campaign.MergesPerHour != *args.MergesPerHour {
+		campaign.MergesPerHour = *args.MergesPerHour
+		updateMergePolicy = true
+	}
+
+	if updateMergePolicy {
+		if err = validateMergePolicy(campaign.MergeMethod, campaign.MergesPerHour); err != nil {
+			return nil, nil, err
+		}
+	}
+
 	if !updateAttributes && !updatePatchSetID && !updateBranch {
+		if updateMergePolicy {
+			return campaign, nil, tx.UpdateCampaign(ctx, campaign)
+		}
 		return campaign, nil, nil
 	}
 
@@ -652,6 +890,189 @@ func (s *Service) UpdateCampaign(ctx context.Context, args UpdateCampaignArgs) (
 	return campaign, changesets, tx.UpdateCampaign(ctx, campaign)
 }
 
+// ErrCampaignTemplateNameBlank is returned by CreateCampaignTemplate or
+// UpdateCampaignTemplate if the specified CampaignTemplate name is blank.
+var ErrCampaignTemplateNameBlank = errors.New("Campaign template name cannot be blank")
+
+// CreateCampaignTemplate creates the given CampaignTemplate and its first
+// version with the given spec.
+func (s *Service) CreateCampaignTemplate(ctx context.Context, t *campaigns.CampaignTemplate, spec *campaigns.CampaignTemplateSpec) (version *campaigns.CampaignTemplateVersion, err error) {
+	tr, ctx := trace.New(ctx, "Service.CreateCampaignTemplate", fmt.Sprintf("Name: %q", t.Name))
+	defer func() {
+		tr.SetError(err)
+		tr.Finish()
+	}()
+
+	if t.Name == "" {
+		return nil, ErrCampaignTemplateNameBlank
+	}
+	if err = ValidateCampaignTemplateSpec(spec); err != nil {
+		return nil, err
+	}
+
+	tx, err := s.store.Transact(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Done(&err)
+
+	t.LatestVersion = 1
+	t.CreatedAt = s.clock()
+	t.UpdatedAt = t.CreatedAt
+
+	if err = tx.CreateCampaignTemplate(ctx, t); err != nil {
+		return nil, err
+	}
+
+	version = &campaigns.CampaignTemplateVersion{
+		TemplateID: t.ID,
+		Version:    t.LatestVersion,
+		Spec:       *spec,
+		AuthorID:   t.AuthorID,
+		CreatedAt:  t.CreatedAt,
+	}
+	err = tx.CreateCampaignTemplateVersion(ctx, version)
+	return version, err
+}
+
+type UpdateCampaignTemplateArgs struct {
+	Template    int64
+	Name        *string
+	Description *string
+	// Spec, if set, is stored as a new version of the template. Campaigns
+	// that were instantiated from previous versions are not changed.
+	Spec *campaigns.CampaignTemplateSpec
+	// AuthorID is the ID of the user updating the template, recorded as the
+	// author of the new version.
+	AuthorID int32
+}
+
+// UpdateCampaignTemplate updates the CampaignTemplate with the given
+// arguments.
+func (s *Service) UpdateCampaignTemplate(ctx context.Context, args UpdateCampaignTemplateArgs) (template *campaigns.CampaignTemplate, err error) {
+	tr, ctx := trace.New(ctx, "service.UpdateCampaignTemplate", fmt.Sprintf("template: %d", args.Template))
+	defer func() {
+		tr.SetError(err)
+		tr.Finish()
+	}()
+
+	if args.Name != nil && *args.Name == "" {
+		return nil, ErrCampaignTemplateNameBlank
+	}
+	if args.Spec != nil {
+		if err = ValidateCampaignTemplateSpec(args.Spec); err != nil {
+			return nil, err
+		}
+	}
+
+	tx, err := s.store.Transact(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Done(&err)
+
+	template, err = tx.GetCampaignTemplate(ctx, GetCampaignTemplateOpts{ID: args.Template})
+	if err != nil {
+		return nil, errors.Wrap(err, "getting campaign template")
+	}
+
+	if args.Name != nil {
+		template.Name = *args.Name
+	}
+	if args.Description != nil {
+		template.Description = *args.Description
+	}
+
+	if args.Spec != nil {
+		template.LatestVersion++
+		err = tx.CreateCampaignTemplateVersion(ctx, &campaigns.CampaignTemplateVersion{
+			TemplateID: template.ID,
+			Version:    template.LatestVersion,
+			Spec:       *args.Spec,
+			AuthorID:   args.AuthorID,
+			CreatedAt:  s.clock(),
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = tx.UpdateCampaignTemplate(ctx, template)
+	return template, err
+}
+
+type InstantiateCampaignTemplateArgs struct {
+	Template int64
+	// Version is the version of the template to instantiate. Zero means the
+	// latest version.
+	Version int32
+	// Params are the values of the template's parameters. Parameters without
+	// a value use their default.
+	Params map[string]string
+
+	// Campaign holds the fields of the campaign that don't come from the
+	// template: its author, namespace, patch set and, optionally, its name.
+	// The name defaults to the name of the template.
+	Campaign *campaigns.Campaign
+	Draft    bool
+}
+
+// InstantiateCampaignTemplate creates a Campaign from a version of a
+// CampaignTemplate. The campaign records the template version and the
+// parameter values it was created with, which are used to render the titles
+// and bodies of its changesets.
+func (s *Service) InstantiateCampaignTemplate(ctx context.Context, args InstantiateCampaignTemplateArgs) (campaign *campaigns.Campaign, err error) {
+	tr, ctx := trace.New(ctx, "service.InstantiateCampaignTemplate", fmt.Sprintf("template: %d", args.Template))
+	defer func() {
+		tr.SetError(err)
+		tr.Finish()
+	}()
+
+	template, err := s.store.GetCampaignTemplate(ctx, GetCampaignTemplateOpts{ID: args.Template})
+	if err != nil {
+		return nil, errors.Wrap(err, "getting campaign template")
+	}
+
+	version := args.Version
+	if version == 0 {
+		version = template.LatestVersion
+	}
+
+	v, err := s.store.GetCampaignTemplateVersion(ctx, GetCampaignTemplateVersionOpts{
+		TemplateID: template.ID,
+		Version:    version,
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "getting version %d of campaign template", version)
+	}
+
+	params, err := ResolveCampaignTemplateParams(&v.Spec, args.Params)
+	if err != nil {
+		return nil, err
+	}
+
+	branch, err := RenderCampaignTemplate(v.Spec.BranchPattern, &CampaignTemplateData{Params: params})
+	if err != nil {
+		return nil, errors.Wrap(err, "rendering campaign branch")
+	}
+
+	campaign = args.Campaign
+	if campaign.Name == "" {
+		campaign.Name = template.Name
+	}
+	if campaign.Description == "" {
+		campaign.Description = template.Description
+	}
+	campaign.Branch = strings.TrimSpace(branch)
+	campaign.TemplateVersionID = v.ID
+	campaign.TemplateParams = params
+
+	if err = s.CreateCampaign(ctx, campaign, args.Draft); err != nil {
+		return nil, err
+	}
+	return campaign, nil
+}
+
 // campaignPublished returns true if all ChangesetJobs have been created yet
 // (they might still be processing).
 func campaignPublished(ctx context.Context, store *Store, campaign int64) (bool, error) {
@@ -843,7 +1264,29 @@ func isOutdated(c *repos.Changeset) (bool, error) {
 		return true, nil
 	}
 
-	return false, nil
+	if len(campaigns.MissingReviewers(c.Changeset.ReviewerNames(), c.Reviewers)) > 0 {
+		return true, nil
+	}
+
+	// Bitbucket Server doesn't support labels, assignees or drafts.
+	if c.Changeset.ExternalServiceType != github.ServiceType {
+		return false, nil
+	}
+
+	if c.Changeset.IsDraft() != c.Draft {
+		return true, nil
+	}
+
+	if len(campaigns.MissingNames(c.Changeset.AssigneeNames(), c.Assignees)) > 0 {
+		return true, nil
+	}
+
+	var currentLabels []string
+	for _, l := range c.Changeset.Labels() {
+		currentLabels = append(currentLabels, l.Name)
+	}
+
+	return len(campaigns.MissingNames(currentLabels, c.Labels)) > 0, nil
 }
 
 func mergeByRepoID(
@@ -17,6 +17,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/campaigns"
 	"github.com/sourcegraph/sourcegraph/internal/db/dbconn"
 	"github.com/sourcegraph/sourcegraph/internal/db/dbtesting"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketserver"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/github"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
 	"github.com/sourcegraph/sourcegraph/internal/httpcli"
@@ -1160,6 +1161,132 @@ func testCampaign(user int32, patchSet int64) *campaigns.Campaign {
 	return c
 }
 
+func TestIsOutdated(t *testing.T) {
+	githubPR := func() *github.PullRequest {
+		pr := &github.PullRequest{
+			Title:       "Title",
+			Body:        "Body",
+			BaseRefName: "master",
+			IsDraft:     true,
+		}
+		pr.Labels.Nodes = []github.Label{{Name: "area/web"}}
+		pr.Assignees.Nodes = []github.Actor{{Login: "carol"}}
+		pr.ReviewRequests.Nodes = []github.ReviewRequest{
+			{RequestedReviewer: github.Actor{Login: "alice"}},
+		}
+		pr.TimelineItems = []github.TimelineItem{
+			{Type: "PullRequestReview", Item: &github.PullRequestReview{Author: github.Actor{Login: "bob"}}},
+		}
+		return pr
+	}
+
+	bbsPR := func() *bitbucketserver.PullRequest {
+		pr := &bitbucketserver.PullRequest{
+			Title:       "Title",
+			Description: "Body",
+			Reviewers:   []bitbucketserver.Reviewer{{User: &bitbucketserver.User{Name: "alice"}}},
+		}
+		pr.ToRef.ID = "refs/heads/master"
+		return pr
+	}
+
+	for _, tc := range []struct {
+		name   string
+		meta   interface{}
+		update func(*repos.Changeset)
+		want   bool
+	}{
+		{
+			name: "github up to date",
+			meta: githubPR(),
+			want: false,
+		},
+		{
+			name:   "github title changed",
+			meta:   githubPR(),
+			update: func(c *repos.Changeset) { c.Title = "New title" },
+			want:   true,
+		},
+		{
+			name:   "github reviewer already reviewed",
+			meta:   githubPR(),
+			update: func(c *repos.Changeset) { c.Reviewers = []string{"Alice", "bob"} },
+			want:   false,
+		},
+		{
+			name:   "github reviewer missing",
+			meta:   githubPR(),
+			update: func(c *repos.Changeset) { c.Reviewers = []string{"dave"} },
+			want:   true,
+		},
+		{
+			name:   "github assignee missing",
+			meta:   githubPR(),
+			update: func(c *repos.Changeset) { c.Assignees = []string{"carol", "dave"} },
+			want:   true,
+		},
+		{
+			name:   "github label missing",
+			meta:   githubPR(),
+			update: func(c *repos.Changeset) { c.Labels = []string{"area/web", "campaign"} },
+			want:   true,
+		},
+		{
+			name:   "github draft changed",
+			meta:   githubPR(),
+			update: func(c *repos.Changeset) { c.Draft = false },
+			want:   true,
+		},
+		{
+			name: "bitbucket server ignores unsupported options",
+			meta: bbsPR(),
+			update: func(c *repos.Changeset) {
+				c.Labels = []string{"campaign"}
+				c.Assignees = []string{"carol"}
+				c.Draft = true
+			},
+			want: false,
+		},
+		{
+			name:   "bitbucket server reviewer missing",
+			meta:   bbsPR(),
+			update: func(c *repos.Changeset) { c.Reviewers = []string{"alice", "bob"} },
+			want:   true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ch := &campaigns.Changeset{}
+			if err := ch.SetMetadata(tc.meta); err != nil {
+				t.Fatal(err)
+			}
+
+			c := &repos.Changeset{
+				Title:     "Title",
+				Body:      "Body",
+				BaseRef:   "refs/heads/master",
+				Labels:    []string{"area/web"},
+				Reviewers: []string{"alice"},
+				Changeset: ch,
+			}
+			if _, ok := tc.meta.(*github.PullRequest); ok {
+				c.Assignees = []string{"carol"}
+				c.Draft = true
+			}
+			if tc.update != nil {
+				tc.update(c)
+			}
+
+			have, err := isOutdated(c)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if have != tc.want {
+				t.Errorf("want outdated=%t, have %t", tc.want, have)
+			}
+		})
+	}
+}
+
 func testChangeset(repoID api.RepoID, campaign int64, changesetJob int64, state campaigns.ChangesetState) *campaigns.Changeset {
 	pr := &github.PullRequest{State: string(state)}
 	return &campaigns.Changeset{
@@ -34,6 +34,37 @@ func SetDerivedState(c *cmpgn.Changeset, es []*cmpgn.ChangesetEvent) {
 	c.ExternalCheckState = ComputeCheckState(c, events)
 }
 
+// ChangesetTransitions returns the ChangesetTransitions between the derived
+// state of prev and c, which must be the same Changeset before and after
+// SetDerivedState. Changesets whose state hasn't been derived before have no
+// transitions.
+func ChangesetTransitions(prev, c *cmpgn.Changeset) (ts []cmpgn.ChangesetTransition) {
+	if prev.ExternalState == "" {
+		return nil
+	}
+
+	if c.ExternalState != prev.ExternalState {
+		switch c.ExternalState {
+		case cmpgn.ChangesetStateMerged:
+			ts = append(ts, cmpgn.ChangesetTransitionMerged)
+		case cmpgn.ChangesetStateClosed:
+			ts = append(ts, cmpgn.ChangesetTransitionClosed)
+		}
+	}
+
+	if c.ExternalCheckState == cmpgn.ChangesetCheckStateFailed &&
+		prev.ExternalCheckState != cmpgn.ChangesetCheckStateFailed {
+		ts = append(ts, cmpgn.ChangesetTransitionChecksFailed)
+	}
+
+	if c.ExternalReviewState == cmpgn.ChangesetReviewStateChangesRequested &&
+		prev.ExternalReviewState != cmpgn.ChangesetReviewStateChangesRequested {
+		ts = append(ts, cmpgn.ChangesetTransitionChangesRequested)
+	}
+
+	return ts
+}
+
 // ComputeCheckState computes the overall check state based on the current synced check state
 // and any webhook events that have arrived after the most recent sync
 func ComputeCheckState(c *cmpgn.Changeset, events ChangesetEvents) cmpgn.ChangesetCheckState {
@@ -258,3 +258,82 @@ func TestComputeBitbucketBuildStatus(t *testing.T) {
 		})
 	}
 }
+
+func TestChangesetTransitions(t *testing.T) {
+	open := cmpgn.Changeset{
+		ExternalState:       cmpgn.ChangesetStateOpen,
+		ExternalReviewState: cmpgn.ChangesetReviewStatePending,
+		ExternalCheckState:  cmpgn.ChangesetCheckStatePending,
+	}
+
+	for _, tc := range []struct {
+		name string
+		prev cmpgn.Changeset
+		c    cmpgn.Changeset
+		want []cmpgn.ChangesetTransition
+	}{
+		{
+			name: "no derived state before",
+			prev: cmpgn.Changeset{},
+			c:    cmpgn.Changeset{ExternalState: cmpgn.ChangesetStateMerged},
+		},
+		{
+			name: "unchanged",
+			prev: open,
+			c:    open,
+		},
+		{
+			name: "merged",
+			prev: open,
+			c: cmpgn.Changeset{
+				ExternalState:       cmpgn.ChangesetStateMerged,
+				ExternalReviewState: cmpgn.ChangesetReviewStateApproved,
+				ExternalCheckState:  cmpgn.ChangesetCheckStatePassed,
+			},
+			want: []cmpgn.ChangesetTransition{cmpgn.ChangesetTransitionMerged},
+		},
+		{
+			name: "closed with failing checks",
+			prev: open,
+			c: cmpgn.Changeset{
+				ExternalState:       cmpgn.ChangesetStateClosed,
+				ExternalReviewState: cmpgn.ChangesetReviewStatePending,
+				ExternalCheckState:  cmpgn.ChangesetCheckStateFailed,
+			},
+			want: []cmpgn.ChangesetTransition{
+				cmpgn.ChangesetTransitionClosed,
+				cmpgn.ChangesetTransitionChecksFailed,
+			},
+		},
+		{
+			name: "changes requested",
+			prev: open,
+			c: cmpgn.Changeset{
+				ExternalState:       cmpgn.ChangesetStateOpen,
+				ExternalReviewState: cmpgn.ChangesetReviewStateChangesRequested,
+				ExternalCheckState:  cmpgn.ChangesetCheckStatePending,
+			},
+			want: []cmpgn.ChangesetTransition{cmpgn.ChangesetTransitionChangesRequested},
+		},
+		{
+			name: "still failing",
+			prev: cmpgn.Changeset{
+				ExternalState:       cmpgn.ChangesetStateOpen,
+				ExternalReviewState: cmpgn.ChangesetReviewStateChangesRequested,
+				ExternalCheckState:  cmpgn.ChangesetCheckStateFailed,
+			},
+			c: cmpgn.Changeset{
+				ExternalState:       cmpgn.ChangesetStateOpen,
+				ExternalReviewState: cmpgn.ChangesetReviewStateChangesRequested,
+				ExternalCheckState:  cmpgn.ChangesetCheckStateFailed,
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			have := ChangesetTransitions(&tc.prev, &tc.c)
+			if diff := cmp.Diff(tc.want, have); diff != "" {
+				t.Fatalf(diff)
+			}
+		})
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"sort"
 	"time"
 
+	"github.com/hashicorp/go-multierror"
 	"github.com/keegancsmith/sqlf"
 	"github.com/lib/pq"
 	"github.com/pkg/errors"
@@ -116,6 +117,192 @@ RETURNING j.id,
   j.updated_at
 `
 
+// MaxPatchJobAttempts is the number of times a PatchJob is attempted before it
+// is marked as failed.
+const MaxPatchJobAttempts = 3
+
+// ProcessPendingPatchJobs attempts to fetch one pending patch job.
+// A pending job is one that has never been started.
+// If found, 'process' is called with the same guarantees as in
+// ProcessPendingChangesetJobs.
+// If 'process' returns an error, its changes are rolled back and the attempt
+// is recorded. After MaxPatchJobAttempts failed attempts, the job is finished
+// with the error, i.e. it failed, instead of being pending again.
+// NOTE: It should not be called from within an existing transaction
+func (s *Store) ProcessPendingPatchJobs(ctx context.Context, process func(ctx context.Context, s *Store, job campaigns.PatchJob) error) (didRun bool, err error) {
+	var job campaigns.PatchJob
+	didRun, err = s.processPendingPatchJob(ctx, &job, process)
+	if !didRun || err == nil {
+		return didRun, err
+	}
+
+	if e := s.exec(ctx, s.failPatchJobAttemptQuery(job.ID, err), nil); e != nil {
+		err = multierror.Append(err, errors.Wrap(e, "recording failed patch job attempt"))
+	}
+	return didRun, err
+}
+
+func (s *Store) processPendingPatchJob(ctx context.Context, job *campaigns.PatchJob, process func(ctx context.Context, s *Store, job campaigns.PatchJob) error) (didRun bool, err error) {
+	tx, err := s.Transact(ctx)
+	if err != nil {
+		return false, errors.Wrap(err, "starting transaction")
+	}
+	defer tx.Done(&err)
+	q := sqlf.Sprintf(getPendingPatchJobQuery)
+	_, count, err := tx.query(ctx, q, func(sc scanner) (last, count int64, err error) {
+		err = scanPatchJob(job, sc)
+		if err != nil {
+			return 0, 0, errors.Wrap(err, "scanning patch job row")
+		}
+		return job.ID, 1, nil
+	})
+	if err != nil {
+		return false, errors.Wrap(err, "querying for pending patch job")
+	}
+	if count == 0 {
+		return false, nil
+	}
+	err = process(ctx, tx, *job)
+	return true, err
+}
+
+var failPatchJobAttemptQueryFmtstr = `
+-- source: enterprise/internal/campaigns/store.go:ProcessPendingPatchJobs
+UPDATE patch_jobs
+SET
+  attempts    = attempts + 1,
+  error       = CASE WHEN attempts + 1 >= %s THEN %s END,
+  started_at  = CASE WHEN attempts + 1 >= %s THEN %s::timestamptz END,
+  finished_at = CASE WHEN attempts + 1 >= %s THEN %s::timestamptz END,
+  updated_at  = %s
+WHERE id = %s
+`
+
+func (s *Store) failPatchJobAttemptQuery(id int64, err error) *sqlf.Query {
+	now := s.now()
+	return sqlf.Sprintf(
+		failPatchJobAttemptQueryFmtstr,
+		MaxPatchJobAttempts, err.Error(),
+		MaxPatchJobAttempts, now,
+		MaxPatchJobAttempts, now,
+		now,
+		id,
+	)
+}
+
+const getPendingPatchJobQuery = `
+UPDATE patch_jobs j SET started_at = now() WHERE id = (
+	SELECT j.id FROM patch_jobs j
+	WHERE j.started_at IS NULL
+	ORDER BY j.id ASC
+	FOR UPDATE SKIP LOCKED LIMIT 1
+)
+RETURNING j.id,
+  j.patch_set_id,
+  j.repo_id,
+  j.patch_id,
+  j.error,
+  j.started_at,
+  j.finished_at,
+  j.created_at,
+  j.updated_at
+`
+
+// ProcessPendingChangesetActionJobs attempts to fetch one pending changeset
+// action job. A pending job is one that has never been started.
+// If found, 'process' is called with the same guarantees as in
+// ProcessPendingChangesetJobs.
+// NOTE: It should not be called from within an existing transaction
+func (s *Store) ProcessPendingChangesetActionJobs(ctx context.Context, process func(ctx context.Context, s *Store, job campaigns.ChangesetActionJob) error) (didRun bool, err error) {
+	tx, err := s.Transact(ctx)
+	if err != nil {
+		return false, errors.Wrap(err, "starting transaction")
+	}
+	defer tx.Done(&err)
+	q := sqlf.Sprintf(getPendingChangesetActionJobQuery)
+	var job campaigns.ChangesetActionJob
+	_, count, err := tx.query(ctx, q, func(sc scanner) (last, count int64, err error) {
+		err = scanChangesetActionJob(&job, sc)
+		if err != nil {
+			return 0, 0, errors.Wrap(err, "scanning changeset action job row")
+		}
+		return job.ID, 1, nil
+	})
+	if err != nil {
+		return false, errors.Wrap(err, "querying for pending changeset action job")
+	}
+	if count == 0 {
+		return false, nil
+	}
+	err = process(ctx, tx, job)
+	return true, err
+}
+
+const getPendingChangesetActionJobQuery = `
+UPDATE changeset_action_jobs j SET started_at = now() WHERE id = (
+	SELECT j.id FROM changeset_action_jobs j
+	WHERE j.started_at IS NULL
+	ORDER BY j.id ASC
+	FOR UPDATE SKIP LOCKED LIMIT 1
+)
+RETURNING j.id,
+  j.changeset_action_id,
+  j.changeset_id,
+  j.error,
+  j.started_at,
+  j.finished_at,
+  j.created_at,
+  j.updated_at
+`
+
+// ProcessPendingCampaignNotifications attempts to fetch one pending campaign
+// notification. A pending notification is one that has never been started.
+// If found, 'process' is called with the same guarantees as in
+// ProcessPendingChangesetJobs.
+// NOTE: It should not be called from within an existing transaction
+func (s *Store) ProcessPendingCampaignNotifications(ctx context.Context, process func(ctx context.Context, s *Store, n campaigns.CampaignNotification) error) (didRun bool, err error) {
+	tx, err := s.Transact(ctx)
+	if err != nil {
+		return false, errors.Wrap(err, "starting transaction")
+	}
+	defer tx.Done(&err)
+	q := sqlf.Sprintf(getPendingCampaignNotificationQuery)
+	var n campaigns.CampaignNotification
+	_, count, err := tx.query(ctx, q, func(sc scanner) (last, count int64, err error) {
+		err = scanCampaignNotification(&n, sc)
+		if err != nil {
+			return 0, 0, errors.Wrap(err, "scanning campaign notification row")
+		}
+		return n.ID, 1, nil
+	})
+	if err != nil {
+		return false, errors.Wrap(err, "querying for pending campaign notification")
+	}
+	if count == 0 {
+		return false, nil
+	}
+	err = process(ctx, tx, n)
+	return true, err
+}
+
+const getPendingCampaignNotificationQuery = `
+UPDATE campaign_notifications n SET started_at = now() WHERE id = (
+	SELECT n.id FROM campaign_notifications n
+	WHERE n.started_at IS NULL
+	ORDER BY n.id ASC
+	FOR UPDATE SKIP LOCKED LIMIT 1
+)
+RETURNING n.id,
+  n.subscription_id,
+  n.changeset_id,
+  n.transition,
+  n.error,
+  n.started_at,
+  n.finished_at,
+  n.created_at,
+  n.updated_at
+`
+
 // Done terminates the underlying Tx in a Store either by committing or rolling
 // back based on the value pointed to by the first given error pointer.
 // It's a no-op if the `Store` is not operating within a transaction,
@@ -267,7 +454,10 @@ WITH batch AS (
       external_updated_at   timestamptz,
       external_state        text,
       external_review_state text,
-      external_check_state  text
+      external_check_state  text,
+      rebase_state          text,
+      base_commit           text,
+      pushed_commit         text
     )
   )
   WITH ORDINALITY
@@ -290,7 +480,10 @@ changed AS (
     external_updated_at,
     external_state,
     external_review_state,
-    external_check_state
+    external_check_state,
+    rebase_state,
+    base_commit,
+    pushed_commit
   )
   SELECT
     repo_id,
@@ -305,7 +498,10 @@ changed AS (
     external_updated_at,
     external_state,
     external_review_state,
-    external_check_state
+    external_check_state,
+    rebase_state,
+    base_commit,
+    pushed_commit
   FROM batch
   ON CONFLICT ON CONSTRAINT
     changesets_repo_external_id_unique
@@ -329,7 +525,10 @@ SELECT
   COALESCE(changed.external_updated_at, existing.external_updated_at) AS external_updated_at,
   COALESCE(changed.external_state, existing.external_state) AS external_state,
   COALESCE(changed.external_review_state, existing.external_review_state) AS external_review_state,
-  COALESCE(changed.external_check_state, existing.external_check_state) AS external_check_state
+  COALESCE(changed.external_check_state, existing.external_check_state) AS external_check_state,
+  COALESCE(changed.rebase_state, existing.rebase_state) AS rebase_state,
+  COALESCE(changed.base_commit, existing.base_commit) AS base_commit,
+  COALESCE(changed.pushed_commit, existing.pushed_commit) AS pushed_commit
 FROM changed
 RIGHT JOIN batch ON batch.repo_id = changed.repo_id
 AND batch.external_id = changed.external_id
@@ -368,6 +567,9 @@ func batchChangesetsQuery(fmtstr string, cs []*campaigns.Changeset) (*sqlf.Query
 		ExternalState       *campaigns.ChangesetState       `json:"external_state"`
 		ExternalReviewState *campaigns.ChangesetReviewState `json:"external_review_state"`
 		ExternalCheckState  *campaigns.ChangesetCheckState  `json:"external_check_state"`
+		RebaseState         *campaigns.ChangesetRebaseState `json:"rebase_state"`
+		BaseCommit          *string                         `json:"base_commit"`
+		PushedCommit        *string                         `json:"pushed_commit"`
 	}
 
 	records := make([]record, 0, len(cs))
@@ -395,6 +597,8 @@ func batchChangesetsQuery(fmtstr string, cs []*campaigns.Changeset) (*sqlf.Query
 			ExternalBranch:      c.ExternalBranch,
 			ExternalDeletedAt:   nullTimeColumn(c.ExternalDeletedAt),
 			ExternalUpdatedAt:   nullTimeColumn(c.ExternalUpdatedAt),
+			BaseCommit:          nullStringColumn(string(c.BaseCommit)),
+			PushedCommit:        nullStringColumn(string(c.PushedCommit)),
 		}
 		if len(c.ExternalState) > 0 {
 			r.ExternalState = &c.ExternalState
@@ -405,6 +609,9 @@ func batchChangesetsQuery(fmtstr string, cs []*campaigns.Changeset) (*sqlf.Query
 		if len(c.ExternalCheckState) > 0 {
 			r.ExternalCheckState = &c.ExternalCheckState
 		}
+		if len(c.RebaseState) > 0 {
+			r.RebaseState = &c.RebaseState
+		}
 
 		records = append(records, r)
 	}
@@ -525,7 +732,10 @@ SELECT
   external_updated_at,
   external_state,
   external_review_state,
-  external_check_state
+  external_check_state,
+  rebase_state,
+  base_commit,
+  pushed_commit
 FROM changesets
 WHERE %s
 LIMIT 1
@@ -690,7 +900,10 @@ SELECT
   changesets.external_updated_at,
   changesets.external_state,
   changesets.external_review_state,
-  changesets.external_check_state
+  changesets.external_check_state,
+  changesets.rebase_state,
+  changesets.base_commit,
+  changesets.pushed_commit
 FROM changesets
 INNER JOIN repo ON repo.id = changesets.repo_id
 WHERE %s
@@ -781,7 +994,10 @@ changed AS (
 	external_updated_at   = batch.external_updated_at,
     external_state        = batch.external_state,
     external_review_state = batch.external_review_state,
-    external_check_state  = batch.external_check_state
+    external_check_state  = batch.external_check_state,
+    rebase_state          = batch.rebase_state,
+    base_commit           = batch.base_commit,
+    pushed_commit         = batch.pushed_commit
   FROM batch
   WHERE changesets.id = batch.id
   RETURNING changesets.*
@@ -803,7 +1019,10 @@ SELECT
   changed.external_updated_at,
   changed.external_state,
   changed.external_review_state,
-  changed.external_check_state
+  changed.external_check_state,
+  changed.rebase_state,
+  changed.base_commit,
+  changed.pushed_commit
 FROM changed
 LEFT JOIN batch ON batch.repo_id = changed.repo_id
 AND batch.external_id = changed.external_id
@@ -961,7 +1180,10 @@ func listChangesetEventsQuery(opts *ListChangesetEventsOpts) *sqlf.Query {
 // CountChangesetEventsOpts captures the query options needed for
 // counting changeset events.
 type CountChangesetEventsOpts struct {
-	ChangesetID int64
+	ChangesetID  int64
+	CampaignID   int64
+	Kind         campaigns.ChangesetEventKind
+	CreatedAfter time.Time
 }
 
 // CountChangesetEvents returns the number of changeset events in the database.
@@ -986,6 +1208,21 @@ func countChangesetEventsQuery(opts *CountChangesetEventsOpts) *sqlf.Query {
 		preds = append(preds, sqlf.Sprintf("changeset_id = %s", opts.ChangesetID))
 	}
 
+	if opts.CampaignID != 0 {
+		preds = append(preds, sqlf.Sprintf(
+			"changeset_id IN (SELECT id FROM changesets WHERE campaign_ids ? %s)",
+			opts.CampaignID,
+		))
+	}
+
+	if opts.Kind != "" {
+		preds = append(preds, sqlf.Sprintf("kind = %s", opts.Kind))
+	}
+
+	if !opts.CreatedAfter.IsZero() {
+		preds = append(preds, sqlf.Sprintf("created_at > %s", opts.CreatedAfter))
+	}
+
 	if len(preds) == 0 {
 		preds = append(preds, sqlf.Sprintf("TRUE"))
 	}
@@ -1150,9 +1387,15 @@ INSERT INTO campaigns (
   updated_at,
   changeset_ids,
   patch_set_id,
-  closed_at
+  closed_at,
+  auto_merge,
+  merge_method,
+  merges_per_hour,
+  template_version_id,
+  template_params,
+  changeset_options
 )
-VALUES (%s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s)
+VALUES (%s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s)
 RETURNING
   id,
   name,
@@ -1165,7 +1408,13 @@ RETURNING
   updated_at,
   changeset_ids,
   patch_set_id,
-  closed_at
+  closed_at,
+  auto_merge,
+  merge_method,
+  merges_per_hour,
+  template_version_id,
+  template_params,
+  changeset_options
 `
 
 func (s *Store) createCampaignQuery(c *campaigns.Campaign) (*sqlf.Query, error) {
@@ -1174,6 +1423,16 @@ func (s *Store) createCampaignQuery(c *campaigns.Campaign) (*sqlf.Query, error)
 		return nil, err
 	}
 
+	templateParams, err := templateParamsColumn(c.TemplateParams)
+	if err != nil {
+		return nil, err
+	}
+
+	changesetOptions, err := json.Marshal(c.ChangesetOptions)
+	if err != nil {
+		return nil, err
+	}
+
 	if c.CreatedAt.IsZero() {
 		c.CreatedAt = s.now()
 	}
@@ -1195,6 +1454,12 @@ func (s *Store) createCampaignQuery(c *campaigns.Campaign) (*sqlf.Query, error)
 		changesetIDs,
 		nullInt64Column(c.PatchSetID),
 		nullTimeColumn(c.ClosedAt),
+		c.AutoMerge,
+		mergeMethodColumn(c.MergeMethod),
+		c.MergesPerHour,
+		nullInt64Column(c.TemplateVersionID),
+		templateParams,
+		changesetOptions,
 	), nil
 }
 
@@ -1219,6 +1484,13 @@ func nullTimeColumn(t time.Time) *time.Time {
 	return &t
 }
 
+func mergeMethodColumn(m campaigns.ChangesetMergeMethod) campaigns.ChangesetMergeMethod {
+	if m == "" {
+		return campaigns.ChangesetMergeMethodMerge
+	}
+	return m
+}
+
 func nullStringColumn(s string) *string {
 	if s == "" {
 		return nil
@@ -1252,8 +1524,14 @@ SET (
   updated_at,
   changeset_ids,
   patch_set_id,
-  closed_at
-) = (%s, %s, %s, %s, %s, %s, %s, %s, %s, %s)
+  closed_at,
+  auto_merge,
+  merge_method,
+  merges_per_hour,
+  template_version_id,
+  template_params,
+  changeset_options
+) = (%s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s)
 WHERE id = %s
 RETURNING
   id,
@@ -1267,7 +1545,13 @@ RETURNING
   updated_at,
   changeset_ids,
   patch_set_id,
-  closed_at
+  closed_at,
+  auto_merge,
+  merge_method,
+  merges_per_hour,
+  template_version_id,
+  template_params,
+  changeset_options
 `
 
 func (s *Store) updateCampaignQuery(c *campaigns.Campaign) (*sqlf.Query, error) {
@@ -1276,6 +1560,16 @@ func (s *Store) updateCampaignQuery(c *campaigns.Campaign) (*sqlf.Query, error)
 		return nil, err
 	}
 
+	templateParams, err := templateParamsColumn(c.TemplateParams)
+	if err != nil {
+		return nil, err
+	}
+
+	changesetOptions, err := json.Marshal(c.ChangesetOptions)
+	if err != nil {
+		return nil, err
+	}
+
 	c.UpdatedAt = s.now()
 
 	return sqlf.Sprintf(
@@ -1290,6 +1584,12 @@ func (s *Store) updateCampaignQuery(c *campaigns.Campaign) (*sqlf.Query, error)
 		changesetIDs,
 		nullInt64Column(c.PatchSetID),
 		nullTimeColumn(c.ClosedAt),
+		c.AutoMerge,
+		mergeMethodColumn(c.MergeMethod),
+		c.MergesPerHour,
+		nullInt64Column(c.TemplateVersionID),
+		templateParams,
+		changesetOptions,
 		c.ID,
 	), nil
 }
@@ -1401,7 +1701,13 @@ SELECT
   updated_at,
   changeset_ids,
   patch_set_id,
-  closed_at
+  closed_at,
+  auto_merge,
+  merge_method,
+  merges_per_hour,
+  template_version_id,
+  template_params,
+  changeset_options
 FROM campaigns
 WHERE %s
 LIMIT 1
@@ -1432,6 +1738,7 @@ type ListCampaignsOpts struct {
 	Limit       int
 	State       campaigns.CampaignState
 	HasPatchSet *bool
+	AutoMerge   bool
 }
 
 // ListCampaigns lists Campaigns with the given filters.
@@ -1470,7 +1777,13 @@ SELECT
   updated_at,
   changeset_ids,
   patch_set_id,
-  closed_at
+  closed_at,
+  auto_merge,
+  merge_method,
+  merges_per_hour,
+  template_version_id,
+  template_params,
+  changeset_options
 FROM campaigns
 WHERE %s
 ORDER BY id ASC
@@ -1506,6 +1819,10 @@ func listCampaignsQuery(opts *ListCampaignsOpts) *sqlf.Query {
 		}
 	}
 
+	if opts.AutoMerge {
+		preds = append(preds, sqlf.Sprintf("auto_merge"))
+	}
+
 	return sqlf.Sprintf(
 		listCampaignsQueryFmtstr,
 		sqlf.Join(preds, "\n AND "),
@@ -1513,93 +1830,123 @@ func listCampaignsQuery(opts *ListCampaignsOpts) *sqlf.Query {
 	)
 }
 
-// CreatePatchSet creates the given PatchSet.
-func (s *Store) CreatePatchSet(ctx context.Context, c *campaigns.PatchSet) error {
-	q, err := s.createPatchSetQuery(c)
-	if err != nil {
-		return err
-	}
+// CreateCampaignTemplate creates the given CampaignTemplate.
+func (s *Store) CreateCampaignTemplate(ctx context.Context, t *campaigns.CampaignTemplate) error {
+	q := s.createCampaignTemplateQuery(t)
 
 	return s.exec(ctx, q, func(sc scanner) (last, count int64, err error) {
-		err = scanPatchSet(c, sc)
-		return c.ID, 1, err
+		err = scanCampaignTemplate(t, sc)
+		return t.ID, 1, err
 	})
 }
 
-var createPatchSetQueryFmtstr = `
--- source: enterprise/internal/campaigns/store.go:CreatePatchSet
-INSERT INTO patch_sets (
+var createCampaignTemplateQueryFmtstr = `
+-- source: enterprise/internal/campaigns/store.go:CreateCampaignTemplate
+INSERT INTO campaign_templates (
+  name,
+  description,
+  author_id,
+  namespace_user_id,
+  namespace_org_id,
+  latest_version,
   created_at,
-  updated_at,
-  user_id
+  updated_at
 )
-VALUES (%s, %s, %s)
+VALUES (%s, %s, %s, %s, %s, %s, %s, %s)
 RETURNING
   id,
+  name,
+  description,
+  author_id,
+  namespace_user_id,
+  namespace_org_id,
+  latest_version,
   created_at,
-  updated_at,
-  user_id
+  updated_at
 `
 
-func (s *Store) createPatchSetQuery(c *campaigns.PatchSet) (*sqlf.Query, error) {
-	if c.CreatedAt.IsZero() {
-		c.CreatedAt = s.now()
+func (s *Store) createCampaignTemplateQuery(t *campaigns.CampaignTemplate) *sqlf.Query {
+	if t.CreatedAt.IsZero() {
+		t.CreatedAt = s.now()
 	}
 
-	if c.UpdatedAt.IsZero() {
-		c.UpdatedAt = c.CreatedAt
+	if t.UpdatedAt.IsZero() {
+		t.UpdatedAt = t.CreatedAt
+	}
+
+	if t.LatestVersion == 0 {
+		t.LatestVersion = 1
 	}
 
 	return sqlf.Sprintf(
-		createPatchSetQueryFmtstr,
-		c.CreatedAt,
-		c.UpdatedAt,
-		c.UserID,
-	), nil
+		createCampaignTemplateQueryFmtstr,
+		t.Name,
+		nullStringColumn(t.Description),
+		t.AuthorID,
+		nullInt32Column(t.NamespaceUserID),
+		nullInt32Column(t.NamespaceOrgID),
+		t.LatestVersion,
+		t.CreatedAt,
+		t.UpdatedAt,
+	)
 }
 
-// UpdatePatchSet updates the given PatchSet.
-func (s *Store) UpdatePatchSet(ctx context.Context, c *campaigns.PatchSet) error {
-	q, err := s.updatePatchSetQuery(c)
-	if err != nil {
-		return err
-	}
+// UpdateCampaignTemplate updates the given CampaignTemplate.
+func (s *Store) UpdateCampaignTemplate(ctx context.Context, t *campaigns.CampaignTemplate) error {
+	q := s.updateCampaignTemplateQuery(t)
 
 	return s.exec(ctx, q, func(sc scanner) (last, count int64, err error) {
-		err = scanPatchSet(c, sc)
-		return c.ID, 1, err
+		err = scanCampaignTemplate(t, sc)
+		return t.ID, 1, err
 	})
 }
 
-var updatePatchSetQueryFmtstr = `
--- source: enterprise/internal/campaigns/store.go:UpdatePatchSet
-UPDATE patch_sets
+var updateCampaignTemplateQueryFmtstr = `
+-- source: enterprise/internal/campaigns/store.go:UpdateCampaignTemplate
+UPDATE campaign_templates
 SET (
-  updated_at,
-  user_id
-) = (%s, %s)
-WHERE id = %s
+  name,
+  description,
+  author_id,
+  namespace_user_id,
+  namespace_org_id,
+  latest_version,
+  updated_at
+) = (%s, %s, %s, %s, %s, %s, %s)
+WHERE id = %s AND deleted_at IS NULL
 RETURNING
   id,
+  name,
+  description,
+  author_id,
+  namespace_user_id,
+  namespace_org_id,
+  latest_version,
   created_at,
-  updated_at,
-  user_id
+  updated_at
 `
 
-func (s *Store) updatePatchSetQuery(c *campaigns.PatchSet) (*sqlf.Query, error) {
-	c.UpdatedAt = s.now()
+func (s *Store) updateCampaignTemplateQuery(t *campaigns.CampaignTemplate) *sqlf.Query {
+	t.UpdatedAt = s.now()
 
 	return sqlf.Sprintf(
-		updatePatchSetQueryFmtstr,
-		c.UpdatedAt,
-		c.UserID,
-		c.ID,
-	), nil
+		updateCampaignTemplateQueryFmtstr,
+		t.Name,
+		nullStringColumn(t.Description),
+		t.AuthorID,
+		nullInt32Column(t.NamespaceUserID),
+		nullInt32Column(t.NamespaceOrgID),
+		t.LatestVersion,
+		t.UpdatedAt,
+		t.ID,
+	)
 }
 
-// DeletePatchSet deletes the PatchSet with the given ID.
-func (s *Store) DeletePatchSet(ctx context.Context, id int64) error {
-	q := sqlf.Sprintf(deletePatchSetQueryFmtstr, id)
+// DeleteCampaignTemplate soft-deletes the CampaignTemplate with the given ID.
+// Its versions are kept, so that the campaigns instantiated from it can still
+// refer to them.
+func (s *Store) DeleteCampaignTemplate(ctx context.Context, id int64) error {
+	q := sqlf.Sprintf(deleteCampaignTemplateQueryFmtstr, s.now(), id)
 
 	rows, err := s.db.QueryContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...)
 	if err != nil {
@@ -1608,68 +1955,1555 @@ func (s *Store) DeletePatchSet(ctx context.Context, id int64) error {
 	return rows.Close()
 }
 
-var deletePatchSetQueryFmtstr = `
--- source: enterprise/internal/campaigns/store.go:DeletePatchSet
-DELETE FROM patch_sets WHERE id = %s
+var deleteCampaignTemplateQueryFmtstr = `
+-- source: enterprise/internal/campaigns/store.go:DeleteCampaignTemplate
+UPDATE campaign_templates SET deleted_at = %s WHERE id = %s AND deleted_at IS NULL
 `
 
-const PatchSetTTL = 1 * time.Hour
-
-// DeleteExpiredPatchSets deletes PatchSets that have not been attached to a Campaign within PatchSetTTL.
-func (s *Store) DeleteExpiredPatchSets(ctx context.Context) error {
-	expirationTime := s.now().Add(-PatchSetTTL)
-	q := sqlf.Sprintf(deleteExpiredPatchSetsQueryFmtstr, expirationTime)
-
-	rows, err := s.db.QueryContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...)
-	if err != nil {
-		return err
-	}
-	return rows.Close()
+// CountCampaignTemplatesOpts captures the query options needed for
+// counting campaign templates.
+type CountCampaignTemplatesOpts struct {
+	NamespaceUserID int32
+	NamespaceOrgID  int32
 }
 
-var deleteExpiredPatchSetsQueryFmtstr = `
--- source: enterprise/internal/campaigns/store.go:DeleteExpiredPatchSets
-DELETE FROM
-  patch_sets
-WHERE
-  created_at < %s
-AND
-NOT EXISTS (
-  SELECT 1
-  FROM
-    campaigns
-  WHERE
-    campaigns.patch_set_id = patch_sets.id
-  )
-AND
-NOT EXISTS (
-  SELECT 1
-  FROM
-    changeset_jobs
-  JOIN patches ON patches.id = changeset_jobs.patch_id
-  JOIN changesets ON changesets.id = changeset_jobs.changeset_id
-  WHERE
-    (SELECT COUNT(*) FROM jsonb_object_keys(changesets.campaign_ids)) > 0
-);
-`
-
-// CountPatchSets returns the number of code mods in the database.
-func (s *Store) CountPatchSets(ctx context.Context) (count int64, _ error) {
-	q := sqlf.Sprintf(countPatchSetsQueryFmtstr)
+// CountCampaignTemplates returns the number of campaign templates that
+// haven't been deleted.
+func (s *Store) CountCampaignTemplates(ctx context.Context, opts CountCampaignTemplatesOpts) (count int64, _ error) {
+	q := countCampaignTemplatesQuery(&opts)
 	return count, s.exec(ctx, q, func(sc scanner) (_, _ int64, err error) {
 		err = sc.Scan(&count)
 		return 0, count, err
 	})
 }
 
-var countPatchSetsQueryFmtstr = `
--- source: enterprise/internal/campaigns/store.go:CountPatchSets
+var countCampaignTemplatesQueryFmtstr = `
+-- source: enterprise/internal/campaigns/store.go:CountCampaignTemplates
 SELECT COUNT(id)
-FROM patch_sets
+FROM campaign_templates
+WHERE %s
 `
 
-// GetPatchSetOpts captures the query options needed for getting a PatchSet
-type GetPatchSetOpts struct {
+func countCampaignTemplatesQuery(opts *CountCampaignTemplatesOpts) *sqlf.Query {
+	preds := campaignTemplateNamespacePreds(opts.NamespaceUserID, opts.NamespaceOrgID)
+	return sqlf.Sprintf(countCampaignTemplatesQueryFmtstr, sqlf.Join(preds, "\n AND "))
+}
+
+func campaignTemplateNamespacePreds(userID, orgID int32) []*sqlf.Query {
+	preds := []*sqlf.Query{
+		sqlf.Sprintf("deleted_at IS NULL"),
+	}
+
+	if userID != 0 {
+		preds = append(preds, sqlf.Sprintf("namespace_user_id = %s", userID))
+	}
+
+	if orgID != 0 {
+		preds = append(preds, sqlf.Sprintf("namespace_org_id = %s", orgID))
+	}
+
+	return preds
+}
+
+// GetCampaignTemplateOpts captures the query options needed for getting a
+// CampaignTemplate.
+type GetCampaignTemplateOpts struct {
+	ID int64
+}
+
+// GetCampaignTemplate gets a campaign template matching the given options.
+// Deleted campaign templates are never returned.
+func (s *Store) GetCampaignTemplate(ctx context.Context, opts GetCampaignTemplateOpts) (*campaigns.CampaignTemplate, error) {
+	q := getCampaignTemplateQuery(&opts)
+
+	var t campaigns.CampaignTemplate
+	err := s.exec(ctx, q, func(sc scanner) (_, _ int64, err error) {
+		return 0, 0, scanCampaignTemplate(&t, sc)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if t.ID == 0 {
+		return nil, ErrNoResults
+	}
+
+	return &t, nil
+}
+
+var getCampaignTemplatesQueryFmtstr = `
+-- source: enterprise/internal/campaigns/store.go:GetCampaignTemplate
+SELECT
+  id,
+  name,
+  description,
+  author_id,
+  namespace_user_id,
+  namespace_org_id,
+  latest_version,
+  created_at,
+  updated_at
+FROM campaign_templates
+WHERE %s
+LIMIT 1
+`
+
+func getCampaignTemplateQuery(opts *GetCampaignTemplateOpts) *sqlf.Query {
+	preds := []*sqlf.Query{
+		sqlf.Sprintf("id = %s", opts.ID),
+		sqlf.Sprintf("deleted_at IS NULL"),
+	}
+
+	return sqlf.Sprintf(getCampaignTemplatesQueryFmtstr, sqlf.Join(preds, "\n AND "))
+}
+
+// ListCampaignTemplatesOpts captures the query options needed for
+// listing campaign templates.
+type ListCampaignTemplatesOpts struct {
+	Cursor          int64
+	Limit           int
+	NamespaceUserID int32
+	NamespaceOrgID  int32
+}
+
+// ListCampaignTemplates lists CampaignTemplates with the given filters.
+// Deleted campaign templates are never returned.
+func (s *Store) ListCampaignTemplates(ctx context.Context, opts ListCampaignTemplatesOpts) (ts []*campaigns.CampaignTemplate, next int64, err error) {
+	q := listCampaignTemplatesQuery(&opts)
+
+	ts = make([]*campaigns.CampaignTemplate, 0, opts.Limit)
+	_, _, err = s.query(ctx, q, func(sc scanner) (last, count int64, err error) {
+		var t campaigns.CampaignTemplate
+		if err = scanCampaignTemplate(&t, sc); err != nil {
+			return 0, 0, err
+		}
+		ts = append(ts, &t)
+		return t.ID, 1, err
+	})
+
+	if opts.Limit != 0 && len(ts) == opts.Limit {
+		next = ts[len(ts)-1].ID
+		ts = ts[:len(ts)-1]
+	}
+
+	return ts, next, err
+}
+
+var listCampaignTemplatesQueryFmtstr = `
+-- source: enterprise/internal/campaigns/store.go:ListCampaignTemplates
+SELECT
+  id,
+  name,
+  description,
+  author_id,
+  namespace_user_id,
+  namespace_org_id,
+  latest_version,
+  created_at,
+  updated_at
+FROM campaign_templates
+WHERE %s
+ORDER BY id ASC
+LIMIT %s
+`
+
+func listCampaignTemplatesQuery(opts *ListCampaignTemplatesOpts) *sqlf.Query {
+	if opts.Limit == 0 {
+		opts.Limit = defaultListLimit
+	}
+	opts.Limit++
+
+	preds := append(
+		[]*sqlf.Query{sqlf.Sprintf("id >= %s", opts.Cursor)},
+		campaignTemplateNamespacePreds(opts.NamespaceUserID, opts.NamespaceOrgID)...,
+	)
+
+	return sqlf.Sprintf(
+		listCampaignTemplatesQueryFmtstr,
+		sqlf.Join(preds, "\n AND "),
+		opts.Limit,
+	)
+}
+
+// CreateCampaignTemplateVersion creates the given CampaignTemplateVersion.
+func (s *Store) CreateCampaignTemplateVersion(ctx context.Context, v *campaigns.CampaignTemplateVersion) error {
+	q, err := s.createCampaignTemplateVersionQuery(v)
+	if err != nil {
+		return err
+	}
+
+	return s.exec(ctx, q, func(sc scanner) (last, count int64, err error) {
+		err = scanCampaignTemplateVersion(v, sc)
+		return v.ID, 1, err
+	})
+}
+
+var createCampaignTemplateVersionQueryFmtstr = `
+-- source: enterprise/internal/campaigns/store.go:CreateCampaignTemplateVersion
+INSERT INTO campaign_template_versions (
+  template_id,
+  version,
+  spec,
+  author_id,
+  created_at
+)
+VALUES (%s, %s, %s, %s, %s)
+RETURNING
+  id,
+  template_id,
+  version,
+  spec,
+  author_id,
+  created_at
+`
+
+func (s *Store) createCampaignTemplateVersionQuery(v *campaigns.CampaignTemplateVersion) (*sqlf.Query, error) {
+	spec, err := json.Marshal(v.Spec)
+	if err != nil {
+		return nil, err
+	}
+
+	if v.CreatedAt.IsZero() {
+		v.CreatedAt = s.now()
+	}
+
+	return sqlf.Sprintf(
+		createCampaignTemplateVersionQueryFmtstr,
+		v.TemplateID,
+		v.Version,
+		spec,
+		v.AuthorID,
+		v.CreatedAt,
+	), nil
+}
+
+// GetCampaignTemplateVersionOpts captures the query options needed for
+// getting a CampaignTemplateVersion, either by its ID or by the ID of its
+// template and its version number.
+type GetCampaignTemplateVersionOpts struct {
+	ID         int64
+	TemplateID int64
+	Version    int32
+}
+
+// GetCampaignTemplateVersion gets a campaign template version matching the
+// given options.
+func (s *Store) GetCampaignTemplateVersion(ctx context.Context, opts GetCampaignTemplateVersionOpts) (*campaigns.CampaignTemplateVersion, error) {
+	q := getCampaignTemplateVersionQuery(&opts)
+
+	var v campaigns.CampaignTemplateVersion
+	err := s.exec(ctx, q, func(sc scanner) (_, _ int64, err error) {
+		return 0, 0, scanCampaignTemplateVersion(&v, sc)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if v.ID == 0 {
+		return nil, ErrNoResults
+	}
+
+	return &v, nil
+}
+
+var getCampaignTemplateVersionsQueryFmtstr = `
+-- source: enterprise/internal/campaigns/store.go:GetCampaignTemplateVersion
+SELECT
+  id,
+  template_id,
+  version,
+  spec,
+  author_id,
+  created_at
+FROM campaign_template_versions
+WHERE %s
+LIMIT 1
+`
+
+func getCampaignTemplateVersionQuery(opts *GetCampaignTemplateVersionOpts) *sqlf.Query {
+	var preds []*sqlf.Query
+	if opts.ID != 0 {
+		preds = append(preds, sqlf.Sprintf("id = %s", opts.ID))
+	}
+
+	if opts.TemplateID != 0 {
+		preds = append(preds, sqlf.Sprintf("template_id = %s", opts.TemplateID))
+	}
+
+	if opts.Version != 0 {
+		preds = append(preds, sqlf.Sprintf("version = %s", opts.Version))
+	}
+
+	if len(preds) == 0 {
+		preds = append(preds, sqlf.Sprintf("TRUE"))
+	}
+
+	return sqlf.Sprintf(getCampaignTemplateVersionsQueryFmtstr, sqlf.Join(preds, "\n AND "))
+}
+
+// ListCampaignTemplateVersionsOpts captures the query options needed for
+// listing the versions of a campaign template.
+type ListCampaignTemplateVersionsOpts struct {
+	TemplateID int64
+	Cursor     int64
+	Limit      int
+}
+
+// ListCampaignTemplateVersions lists CampaignTemplateVersions with the given
+// filters, ordered by their ID.
+func (s *Store) ListCampaignTemplateVersions(ctx context.Context, opts ListCampaignTemplateVersionsOpts) (vs []*campaigns.CampaignTemplateVersion, next int64, err error) {
+	q := listCampaignTemplateVersionsQuery(&opts)
+
+	vs = make([]*campaigns.CampaignTemplateVersion, 0, opts.Limit)
+	_, _, err = s.query(ctx, q, func(sc scanner) (last, count int64, err error) {
+		var v campaigns.CampaignTemplateVersion
+		if err = scanCampaignTemplateVersion(&v, sc); err != nil {
+			return 0, 0, err
+		}
+		vs = append(vs, &v)
+		return v.ID, 1, err
+	})
+
+	if opts.Limit != 0 && len(vs) == opts.Limit {
+		next = vs[len(vs)-1].ID
+		vs = vs[:len(vs)-1]
+	}
+
+	return vs, next, err
+}
+
+var listCampaignTemplateVersionsQueryFmtstr = `
+-- source: enterprise/internal/campaigns/store.go:ListCampaignTemplateVersions
+SELECT
+  id,
+  template_id,
+  version,
+  spec,
+  author_id,
+  created_at
+FROM campaign_template_versions
+WHERE %s
+ORDER BY id ASC
+LIMIT %s
+`
+
+func listCampaignTemplateVersionsQuery(opts *ListCampaignTemplateVersionsOpts) *sqlf.Query {
+	if opts.Limit == 0 {
+		opts.Limit = defaultListLimit
+	}
+	opts.Limit++
+
+	preds := []*sqlf.Query{
+		sqlf.Sprintf("id >= %s", opts.Cursor),
+	}
+
+	if opts.TemplateID != 0 {
+		preds = append(preds, sqlf.Sprintf("template_id = %s", opts.TemplateID))
+	}
+
+	return sqlf.Sprintf(
+		listCampaignTemplateVersionsQueryFmtstr,
+		sqlf.Join(preds, "\n AND "),
+		opts.Limit,
+	)
+}
+
+// CreateChangesetImportQuery creates the given ChangesetImportQuery.
+func (s *Store) CreateChangesetImportQuery(ctx context.Context, q *campaigns.ChangesetImportQuery) error {
+	query := s.createChangesetImportQueryQuery(q)
+
+	return s.exec(ctx, query, func(sc scanner) (last, count int64, err error) {
+		err = scanChangesetImportQuery(q, sc)
+		return q.ID, 1, err
+	})
+}
+
+var createChangesetImportQueryQueryFmtstr = `
+-- source: enterprise/internal/campaigns/store.go:CreateChangesetImportQuery
+INSERT INTO changeset_import_queries (
+  campaign_id,
+  external_service_id,
+  query,
+  last_imported_at,
+  last_error,
+  created_at,
+  updated_at
+)
+VALUES (%s, %s, %s, %s, %s, %s, %s)
+RETURNING
+  id,
+  campaign_id,
+  external_service_id,
+  query,
+  last_imported_at,
+  last_error,
+  created_at,
+  updated_at
+`
+
+func (s *Store) createChangesetImportQueryQuery(q *campaigns.ChangesetImportQuery) *sqlf.Query {
+	if q.CreatedAt.IsZero() {
+		q.CreatedAt = s.now()
+	}
+
+	if q.UpdatedAt.IsZero() {
+		q.UpdatedAt = q.CreatedAt
+	}
+
+	return sqlf.Sprintf(
+		createChangesetImportQueryQueryFmtstr,
+		q.CampaignID,
+		q.ExternalServiceID,
+		q.Query,
+		nullTimeColumn(q.LastImportedAt),
+		q.LastError,
+		q.CreatedAt,
+		q.UpdatedAt,
+	)
+}
+
+// UpdateChangesetImportQuery updates the given ChangesetImportQuery.
+func (s *Store) UpdateChangesetImportQuery(ctx context.Context, q *campaigns.ChangesetImportQuery) error {
+	query := s.updateChangesetImportQueryQuery(q)
+
+	return s.exec(ctx, query, func(sc scanner) (last, count int64, err error) {
+		err = scanChangesetImportQuery(q, sc)
+		return q.ID, 1, err
+	})
+}
+
+var updateChangesetImportQueryQueryFmtstr = `
+-- source: enterprise/internal/campaigns/store.go:UpdateChangesetImportQuery
+UPDATE changeset_import_queries
+SET (
+  campaign_id,
+  external_service_id,
+  query,
+  last_imported_at,
+  last_error,
+  updated_at
+) = (%s, %s, %s, %s, %s, %s)
+WHERE id = %s
+RETURNING
+  id,
+  campaign_id,
+  external_service_id,
+  query,
+  last_imported_at,
+  last_error,
+  created_at,
+  updated_at
+`
+
+func (s *Store) updateChangesetImportQueryQuery(q *campaigns.ChangesetImportQuery) *sqlf.Query {
+	q.UpdatedAt = s.now()
+
+	return sqlf.Sprintf(
+		updateChangesetImportQueryQueryFmtstr,
+		q.CampaignID,
+		q.ExternalServiceID,
+		q.Query,
+		nullTimeColumn(q.LastImportedAt),
+		q.LastError,
+		q.UpdatedAt,
+		q.ID,
+	)
+}
+
+// DeleteChangesetImportQuery deletes the ChangesetImportQuery with the given
+// ID. The changesets it imported stay in their campaign.
+func (s *Store) DeleteChangesetImportQuery(ctx context.Context, id int64) error {
+	q := sqlf.Sprintf(deleteChangesetImportQueryQueryFmtstr, id)
+
+	rows, err := s.db.QueryContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...)
+	if err != nil {
+		return err
+	}
+	return rows.Close()
+}
+
+var deleteChangesetImportQueryQueryFmtstr = `
+-- source: enterprise/internal/campaigns/store.go:DeleteChangesetImportQuery
+DELETE FROM changeset_import_queries WHERE id = %s
+`
+
+// GetChangesetImportQueryOpts captures the query options needed for getting
+// a ChangesetImportQuery.
+type GetChangesetImportQueryOpts struct {
+	ID int64
+}
+
+// GetChangesetImportQuery gets a changeset import query matching the given
+// options.
+func (s *Store) GetChangesetImportQuery(ctx context.Context, opts GetChangesetImportQueryOpts) (*campaigns.ChangesetImportQuery, error) {
+	q := getChangesetImportQueryQuery(&opts)
+
+	var c campaigns.ChangesetImportQuery
+	err := s.exec(ctx, q, func(sc scanner) (_, _ int64, err error) {
+		return 0, 0, scanChangesetImportQuery(&c, sc)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if c.ID == 0 {
+		return nil, ErrNoResults
+	}
+
+	return &c, nil
+}
+
+var getChangesetImportQueryQueryFmtstr = `
+-- source: enterprise/internal/campaigns/store.go:GetChangesetImportQuery
+SELECT
+  id,
+  campaign_id,
+  external_service_id,
+  query,
+  last_imported_at,
+  last_error,
+  created_at,
+  updated_at
+FROM changeset_import_queries
+WHERE id = %s
+LIMIT 1
+`
+
+func getChangesetImportQueryQuery(opts *GetChangesetImportQueryOpts) *sqlf.Query {
+	return sqlf.Sprintf(getChangesetImportQueryQueryFmtstr, opts.ID)
+}
+
+// ListChangesetImportQueriesOpts captures the query options needed for
+// listing changeset import queries.
+type ListChangesetImportQueriesOpts struct {
+	Cursor            int64
+	Limit             int
+	CampaignID        int64
+	ExternalServiceID int64
+	// ImportedBefore, if set, only lists the queries that haven't been run
+	// since the given time.
+	ImportedBefore time.Time
+}
+
+// ListChangesetImportQueries lists ChangesetImportQueries with the given
+// filters.
+func (s *Store) ListChangesetImportQueries(ctx context.Context, opts ListChangesetImportQueriesOpts) (qs []*campaigns.ChangesetImportQuery, next int64, err error) {
+	q := listChangesetImportQueriesQuery(&opts)
+
+	qs = make([]*campaigns.ChangesetImportQuery, 0, opts.Limit)
+	_, _, err = s.query(ctx, q, func(sc scanner) (last, count int64, err error) {
+		var c campaigns.ChangesetImportQuery
+		if err = scanChangesetImportQuery(&c, sc); err != nil {
+			return 0, 0, err
+		}
+		qs = append(qs, &c)
+		return c.ID, 1, err
+	})
+
+	if opts.Limit != 0 && len(qs) == opts.Limit {
+		next = qs[len(qs)-1].ID
+		qs = qs[:len(qs)-1]
+	}
+
+	return qs, next, err
+}
+
+var listChangesetImportQueriesQueryFmtstr = `
+-- source: enterprise/internal/campaigns/store.go:ListChangesetImportQueries
+SELECT
+  id,
+  campaign_id,
+  external_service_id,
+  query,
+  last_imported_at,
+  last_error,
+  created_at,
+  updated_at
+FROM changeset_import_queries
+WHERE %s
+ORDER BY id ASC
+`
+
+func listChangesetImportQueriesQuery(opts *ListChangesetImportQueriesOpts) *sqlf.Query {
+	if opts.Limit == 0 {
+		opts.Limit = defaultListLimit
+	}
+	opts.Limit++
+
+	var limitClause string
+	if opts.Limit > 0 {
+		limitClause = fmt.Sprintf("LIMIT %d", opts.Limit)
+	}
+
+	preds := []*sqlf.Query{
+		sqlf.Sprintf("id >= %s", opts.Cursor),
+	}
+
+	if opts.CampaignID != 0 {
+		preds = append(preds, sqlf.Sprintf("campaign_id = %s", opts.CampaignID))
+	}
+
+	if opts.ExternalServiceID != 0 {
+		preds = append(preds, sqlf.Sprintf("external_service_id = %s", opts.ExternalServiceID))
+	}
+
+	if !opts.ImportedBefore.IsZero() {
+		preds = append(preds, sqlf.Sprintf("(last_imported_at IS NULL OR last_imported_at < %s)", opts.ImportedBefore))
+	}
+
+	return sqlf.Sprintf(
+		listChangesetImportQueriesQueryFmtstr+limitClause,
+		sqlf.Join(preds, "\n AND "),
+	)
+}
+
+// CreateChangesetAction creates the given ChangesetAction.
+func (s *Store) CreateChangesetAction(ctx context.Context, a *campaigns.ChangesetAction) error {
+	q, err := s.createChangesetActionQuery(a)
+	if err != nil {
+		return err
+	}
+
+	return s.exec(ctx, q, func(sc scanner) (last, count int64, err error) {
+		err = scanChangesetAction(a, sc)
+		return a.ID, 1, err
+	})
+}
+
+var createChangesetActionQueryFmtstr = `
+-- source: enterprise/internal/campaigns/store.go:CreateChangesetAction
+INSERT INTO changeset_actions (
+  campaign_id,
+  user_id,
+  type,
+  body,
+  labels,
+  reviewers,
+  created_at,
+  updated_at
+)
+VALUES (%s, %s, %s, %s, %s, %s, %s, %s)
+RETURNING
+  id,
+  campaign_id,
+  user_id,
+  type,
+  body,
+  labels,
+  reviewers,
+  created_at,
+  updated_at
+`
+
+func (s *Store) createChangesetActionQuery(a *campaigns.ChangesetAction) (*sqlf.Query, error) {
+	labels, err := stringsColumn(a.Labels)
+	if err != nil {
+		return nil, err
+	}
+
+	reviewers, err := stringsColumn(a.Reviewers)
+	if err != nil {
+		return nil, err
+	}
+
+	if a.CreatedAt.IsZero() {
+		a.CreatedAt = s.now()
+	}
+
+	if a.UpdatedAt.IsZero() {
+		a.UpdatedAt = a.CreatedAt
+	}
+
+	return sqlf.Sprintf(
+		createChangesetActionQueryFmtstr,
+		a.CampaignID,
+		a.UserID,
+		a.Type,
+		a.Body,
+		labels,
+		reviewers,
+		a.CreatedAt,
+		a.UpdatedAt,
+	), nil
+}
+
+// GetChangesetActionOpts captures the query options needed for getting a
+// ChangesetAction.
+type GetChangesetActionOpts struct {
+	ID int64
+}
+
+// GetChangesetAction gets a changeset action matching the given options.
+func (s *Store) GetChangesetAction(ctx context.Context, opts GetChangesetActionOpts) (*campaigns.ChangesetAction, error) {
+	q := sqlf.Sprintf(getChangesetActionQueryFmtstr, opts.ID)
+
+	var a campaigns.ChangesetAction
+	err := s.exec(ctx, q, func(sc scanner) (_, _ int64, err error) {
+		return 0, 0, scanChangesetAction(&a, sc)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if a.ID == 0 {
+		return nil, ErrNoResults
+	}
+
+	return &a, nil
+}
+
+var getChangesetActionQueryFmtstr = `
+-- source: enterprise/internal/campaigns/store.go:GetChangesetAction
+SELECT
+  id,
+  campaign_id,
+  user_id,
+  type,
+  body,
+  labels,
+  reviewers,
+  created_at,
+  updated_at
+FROM changeset_actions
+WHERE id = %s
+LIMIT 1
+`
+
+// ListChangesetActionsOpts captures the query options needed for listing
+// changeset actions.
+type ListChangesetActionsOpts struct {
+	Cursor     int64
+	Limit      int
+	CampaignID int64
+}
+
+// ListChangesetActions lists ChangesetActions with the given filters.
+func (s *Store) ListChangesetActions(ctx context.Context, opts ListChangesetActionsOpts) (as []*campaigns.ChangesetAction, next int64, err error) {
+	q := listChangesetActionsQuery(&opts)
+
+	as = make([]*campaigns.ChangesetAction, 0, opts.Limit)
+	_, _, err = s.query(ctx, q, func(sc scanner) (last, count int64, err error) {
+		var a campaigns.ChangesetAction
+		if err = scanChangesetAction(&a, sc); err != nil {
+			return 0, 0, err
+		}
+		as = append(as, &a)
+		return a.ID, 1, err
+	})
+
+	if opts.Limit != 0 && len(as) == opts.Limit {
+		next = as[len(as)-1].ID
+		as = as[:len(as)-1]
+	}
+
+	return as, next, err
+}
+
+var listChangesetActionsQueryFmtstr = `
+-- source: enterprise/internal/campaigns/store.go:ListChangesetActions
+SELECT
+  id,
+  campaign_id,
+  user_id,
+  type,
+  body,
+  labels,
+  reviewers,
+  created_at,
+  updated_at
+FROM changeset_actions
+WHERE %s
+ORDER BY id ASC
+`
+
+func listChangesetActionsQuery(opts *ListChangesetActionsOpts) *sqlf.Query {
+	if opts.Limit == 0 {
+		opts.Limit = defaultListLimit
+	}
+	opts.Limit++
+
+	var limitClause string
+	if opts.Limit > 0 {
+		limitClause = fmt.Sprintf("LIMIT %d", opts.Limit)
+	}
+
+	preds := []*sqlf.Query{
+		sqlf.Sprintf("id >= %s", opts.Cursor),
+	}
+
+	if opts.CampaignID != 0 {
+		preds = append(preds, sqlf.Sprintf("campaign_id = %s", opts.CampaignID))
+	}
+
+	return sqlf.Sprintf(
+		listChangesetActionsQueryFmtstr+limitClause,
+		sqlf.Join(preds, "\n AND "),
+	)
+}
+
+// GetChangesetActionStatus gets the campaigns.BackgroundProcessStatus of the
+// ChangesetActionJobs of a ChangesetAction.
+func (s *Store) GetChangesetActionStatus(ctx context.Context, id int64) (*campaigns.BackgroundProcessStatus, error) {
+	return s.queryBackgroundProcessStatus(ctx, sqlf.Sprintf(
+		getChangesetActionStatusQueryFmtstr,
+		sqlf.Sprintf("changeset_action_id = %s", id),
+	))
+}
+
+var getChangesetActionStatusQueryFmtstr = `
+-- source: enterprise/internal/campaigns/store.go:GetChangesetActionStatus
+SELECT
+  -- canceled is here so that this can be used with scanBackgroundProcessStatus
+  false AS canceled,
+  COUNT(*) AS total,
+  COUNT(*) FILTER (WHERE finished_at IS NULL) AS pending,
+  COUNT(*) FILTER (WHERE finished_at IS NOT NULL) AS completed,
+  array_agg(error) FILTER (WHERE error != '') AS errors
+FROM changeset_action_jobs
+WHERE %s
+LIMIT 1
+`
+
+// CreateChangesetActionJob creates the given ChangesetActionJob.
+func (s *Store) CreateChangesetActionJob(ctx context.Context, j *campaigns.ChangesetActionJob) error {
+	q := s.createChangesetActionJobQuery(j)
+
+	return s.exec(ctx, q, func(sc scanner) (last, count int64, err error) {
+		err = scanChangesetActionJob(j, sc)
+		return j.ID, 1, err
+	})
+}
+
+var createChangesetActionJobQueryFmtstr = `
+-- source: enterprise/internal/campaigns/store.go:CreateChangesetActionJob
+INSERT INTO changeset_action_jobs (
+  changeset_action_id,
+  changeset_id,
+  error,
+  started_at,
+  finished_at,
+  created_at,
+  updated_at
+)
+VALUES (%s, %s, %s, %s, %s, %s, %s)
+RETURNING
+  id,
+  changeset_action_id,
+  changeset_id,
+  error,
+  started_at,
+  finished_at,
+  created_at,
+  updated_at
+`
+
+func (s *Store) createChangesetActionJobQuery(j *campaigns.ChangesetActionJob) *sqlf.Query {
+	if j.CreatedAt.IsZero() {
+		j.CreatedAt = s.now()
+	}
+
+	if j.UpdatedAt.IsZero() {
+		j.UpdatedAt = j.CreatedAt
+	}
+
+	return sqlf.Sprintf(
+		createChangesetActionJobQueryFmtstr,
+		j.ChangesetActionID,
+		j.ChangesetID,
+		nullStringColumn(j.Error),
+		nullTimeColumn(j.StartedAt),
+		nullTimeColumn(j.FinishedAt),
+		j.CreatedAt,
+		j.UpdatedAt,
+	)
+}
+
+// UpdateChangesetActionJob updates the given ChangesetActionJob.
+func (s *Store) UpdateChangesetActionJob(ctx context.Context, j *campaigns.ChangesetActionJob) error {
+	q := s.updateChangesetActionJobQuery(j)
+
+	return s.exec(ctx, q, func(sc scanner) (last, count int64, err error) {
+		err = scanChangesetActionJob(j, sc)
+		return j.ID, 1, err
+	})
+}
+
+var updateChangesetActionJobQueryFmtstr = `
+-- source: enterprise/internal/campaigns/store.go:UpdateChangesetActionJob
+UPDATE changeset_action_jobs
+SET (
+  changeset_action_id,
+  changeset_id,
+  error,
+  started_at,
+  finished_at,
+  updated_at
+) = (%s, %s, %s, %s, %s, %s)
+WHERE id = %s
+RETURNING
+  id,
+  changeset_action_id,
+  changeset_id,
+  error,
+  started_at,
+  finished_at,
+  created_at,
+  updated_at
+`
+
+func (s *Store) updateChangesetActionJobQuery(j *campaigns.ChangesetActionJob) *sqlf.Query {
+	j.UpdatedAt = s.now()
+
+	return sqlf.Sprintf(
+		updateChangesetActionJobQueryFmtstr,
+		j.ChangesetActionID,
+		j.ChangesetID,
+		nullStringColumn(j.Error),
+		nullTimeColumn(j.StartedAt),
+		nullTimeColumn(j.FinishedAt),
+		j.UpdatedAt,
+		j.ID,
+	)
+}
+
+// ListChangesetActionJobsOpts captures the query options needed for listing
+// changeset action jobs.
+type ListChangesetActionJobsOpts struct {
+	Cursor            int64
+	Limit             int
+	ChangesetActionID int64
+	// OnlyWithError, if set, only lists the jobs that failed.
+	OnlyWithError bool
+}
+
+// ListChangesetActionJobs lists ChangesetActionJobs with the given filters.
+func (s *Store) ListChangesetActionJobs(ctx context.Context, opts ListChangesetActionJobsOpts) (js []*campaigns.ChangesetActionJob, next int64, err error) {
+	q := listChangesetActionJobsQuery(&opts)
+
+	js = make([]*campaigns.ChangesetActionJob, 0, opts.Limit)
+	_, _, err = s.query(ctx, q, func(sc scanner) (last, count int64, err error) {
+		var j campaigns.ChangesetActionJob
+		if err = scanChangesetActionJob(&j, sc); err != nil {
+			return 0, 0, err
+		}
+		js = append(js, &j)
+		return j.ID, 1, err
+	})
+
+	if opts.Limit != 0 && len(js) == opts.Limit {
+		next = js[len(js)-1].ID
+		js = js[:len(js)-1]
+	}
+
+	return js, next, err
+}
+
+var listChangesetActionJobsQueryFmtstr = `
+-- source: enterprise/internal/campaigns/store.go:ListChangesetActionJobs
+SELECT
+  id,
+  changeset_action_id,
+  changeset_id,
+  error,
+  started_at,
+  finished_at,
+  created_at,
+  updated_at
+FROM changeset_action_jobs
+WHERE %s
+ORDER BY id ASC
+`
+
+func listChangesetActionJobsQuery(opts *ListChangesetActionJobsOpts) *sqlf.Query {
+	if opts.Limit == 0 {
+		opts.Limit = defaultListLimit
+	}
+	opts.Limit++
+
+	var limitClause string
+	if opts.Limit > 0 {
+		limitClause = fmt.Sprintf("LIMIT %d", opts.Limit)
+	}
+
+	preds := []*sqlf.Query{
+		sqlf.Sprintf("id >= %s", opts.Cursor),
+	}
+
+	if opts.ChangesetActionID != 0 {
+		preds = append(preds, sqlf.Sprintf("changeset_action_id = %s", opts.ChangesetActionID))
+	}
+
+	if opts.OnlyWithError {
+		preds = append(preds, sqlf.Sprintf("error != ''"))
+	}
+
+	return sqlf.Sprintf(
+		listChangesetActionJobsQueryFmtstr+limitClause,
+		sqlf.Join(preds, "\n AND "),
+	)
+}
+
+// CreateCampaignNotificationSubscription creates the given
+// CampaignNotificationSubscription.
+func (s *Store) CreateCampaignNotificationSubscription(ctx context.Context, sub *campaigns.CampaignNotificationSubscription) error {
+	q, err := s.createCampaignNotificationSubscriptionQuery(sub)
+	if err != nil {
+		return err
+	}
+
+	return s.exec(ctx, q, func(sc scanner) (last, count int64, err error) {
+		err = scanCampaignNotificationSubscription(sub, sc)
+		return sub.ID, 1, err
+	})
+}
+
+var createCampaignNotificationSubscriptionQueryFmtstr = `
+-- source: enterprise/internal/campaigns/store.go:CreateCampaignNotificationSubscription
+INSERT INTO campaign_notification_subscriptions (
+  campaign_id,
+  user_id,
+  channel,
+  url,
+  secret,
+  transitions,
+  created_at,
+  updated_at
+)
+VALUES (%s, %s, %s, %s, %s, %s, %s, %s)
+RETURNING
+  id,
+  campaign_id,
+  user_id,
+  channel,
+  url,
+  secret,
+  transitions,
+  created_at,
+  updated_at
+`
+
+func (s *Store) createCampaignNotificationSubscriptionQuery(sub *campaigns.CampaignNotificationSubscription) (*sqlf.Query, error) {
+	transitions, err := transitionsColumn(sub.Transitions)
+	if err != nil {
+		return nil, err
+	}
+
+	if sub.CreatedAt.IsZero() {
+		sub.CreatedAt = s.now()
+	}
+
+	if sub.UpdatedAt.IsZero() {
+		sub.UpdatedAt = sub.CreatedAt
+	}
+
+	return sqlf.Sprintf(
+		createCampaignNotificationSubscriptionQueryFmtstr,
+		sub.CampaignID,
+		sub.UserID,
+		sub.Channel,
+		sub.URL,
+		sub.Secret,
+		transitions,
+		sub.CreatedAt,
+		sub.UpdatedAt,
+	), nil
+}
+
+// DeleteCampaignNotificationSubscription deletes the
+// CampaignNotificationSubscription with the given ID.
+func (s *Store) DeleteCampaignNotificationSubscription(ctx context.Context, id int64) error {
+	q := sqlf.Sprintf(deleteCampaignNotificationSubscriptionQueryFmtstr, id)
+
+	rows, err := s.db.QueryContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...)
+	if err != nil {
+		return err
+	}
+	return rows.Close()
+}
+
+var deleteCampaignNotificationSubscriptionQueryFmtstr = `
+-- source: enterprise/internal/campaigns/store.go:DeleteCampaignNotificationSubscription
+DELETE FROM campaign_notification_subscriptions WHERE id = %s
+`
+
+// GetCampaignNotificationSubscriptionOpts captures the query options needed
+// for getting a CampaignNotificationSubscription.
+type GetCampaignNotificationSubscriptionOpts struct {
+	ID int64
+}
+
+// GetCampaignNotificationSubscription gets a campaign notification
+// subscription matching the given options.
+func (s *Store) GetCampaignNotificationSubscription(ctx context.Context, opts GetCampaignNotificationSubscriptionOpts) (*campaigns.CampaignNotificationSubscription, error) {
+	q := sqlf.Sprintf(getCampaignNotificationSubscriptionQueryFmtstr, opts.ID)
+
+	var sub campaigns.CampaignNotificationSubscription
+	err := s.exec(ctx, q, func(sc scanner) (_, _ int64, err error) {
+		return 0, 0, scanCampaignNotificationSubscription(&sub, sc)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if sub.ID == 0 {
+		return nil, ErrNoResults
+	}
+
+	return &sub, nil
+}
+
+var getCampaignNotificationSubscriptionQueryFmtstr = `
+-- source: enterprise/internal/campaigns/store.go:GetCampaignNotificationSubscription
+SELECT
+  id,
+  campaign_id,
+  user_id,
+  channel,
+  url,
+  secret,
+  transitions,
+  created_at,
+  updated_at
+FROM campaign_notification_subscriptions
+WHERE id = %s
+LIMIT 1
+`
+
+// ListCampaignNotificationSubscriptionsOpts captures the query options needed
+// for listing campaign notification subscriptions.
+type ListCampaignNotificationSubscriptionsOpts struct {
+	Cursor     int64
+	Limit      int
+	CampaignID int64
+	UserID     int32
+}
+
+// ListCampaignNotificationSubscriptions lists
+// CampaignNotificationSubscriptions with the given filters.
+func (s *Store) ListCampaignNotificationSubscriptions(ctx context.Context, opts ListCampaignNotificationSubscriptionsOpts) (subs []*campaigns.CampaignNotificationSubscription, next int64, err error) {
+	q := listCampaignNotificationSubscriptionsQuery(&opts)
+
+	subs = make([]*campaigns.CampaignNotificationSubscription, 0, opts.Limit)
+	_, _, err = s.query(ctx, q, func(sc scanner) (last, count int64, err error) {
+		var sub campaigns.CampaignNotificationSubscription
+		if err = scanCampaignNotificationSubscription(&sub, sc); err != nil {
+			return 0, 0, err
+		}
+		subs = append(subs, &sub)
+		return sub.ID, 1, err
+	})
+
+	if opts.Limit != 0 && len(subs) == opts.Limit {
+		next = subs[len(subs)-1].ID
+		subs = subs[:len(subs)-1]
+	}
+
+	return subs, next, err
+}
+
+var listCampaignNotificationSubscriptionsQueryFmtstr = `
+-- source: enterprise/internal/campaigns/store.go:ListCampaignNotificationSubscriptions
+SELECT
+  id,
+  campaign_id,
+  user_id,
+  channel,
+  url,
+  secret,
+  transitions,
+  created_at,
+  updated_at
+FROM campaign_notification_subscriptions
+WHERE %s
+ORDER BY id ASC
+`
+
+func listCampaignNotificationSubscriptionsQuery(opts *ListCampaignNotificationSubscriptionsOpts) *sqlf.Query {
+	if opts.Limit == 0 {
+		opts.Limit = defaultListLimit
+	}
+	opts.Limit++
+
+	var limitClause string
+	if opts.Limit > 0 {
+		limitClause = fmt.Sprintf("LIMIT %d", opts.Limit)
+	}
+
+	preds := []*sqlf.Query{
+		sqlf.Sprintf("id >= %s", opts.Cursor),
+	}
+
+	if opts.CampaignID != 0 {
+		preds = append(preds, sqlf.Sprintf("campaign_id = %s", opts.CampaignID))
+	}
+
+	if opts.UserID != 0 {
+		preds = append(preds, sqlf.Sprintf("user_id = %s", opts.UserID))
+	}
+
+	return sqlf.Sprintf(
+		listCampaignNotificationSubscriptionsQueryFmtstr+limitClause,
+		sqlf.Join(preds, "\n AND "),
+	)
+}
+
+// EnqueueCampaignNotifications creates a pending CampaignNotification for
+// every CampaignNotificationSubscription of the campaigns the Changeset with
+// the given ID belongs to that is notified about one of the given
+// transitions.
+func (s *Store) EnqueueCampaignNotifications(ctx context.Context, changesetID int64, transitions ...campaigns.ChangesetTransition) error {
+	if len(transitions) == 0 {
+		return nil
+	}
+
+	ts := make([]string, 0, len(transitions))
+	for _, t := range transitions {
+		ts = append(ts, string(t))
+	}
+
+	now := s.now()
+	q := sqlf.Sprintf(
+		enqueueCampaignNotificationsQueryFmtstr,
+		now,
+		now,
+		pq.Array(ts),
+		changesetID,
+	)
+
+	return s.exec(ctx, q, nil)
+}
+
+var enqueueCampaignNotificationsQueryFmtstr = `
+-- source: enterprise/internal/campaigns/store.go:EnqueueCampaignNotifications
+INSERT INTO campaign_notifications (
+  subscription_id,
+  changeset_id,
+  transition,
+  created_at,
+  updated_at
+)
+SELECT s.id, c.id, t.transition, %s, %s
+FROM campaign_notification_subscriptions s
+JOIN changesets c ON c.campaign_ids ? s.campaign_id::text
+CROSS JOIN unnest(%s::text[]) AS t(transition)
+WHERE c.id = %s
+AND (s.transitions = '[]'::jsonb OR s.transitions ? t.transition)
+ORDER BY s.id ASC
+`
+
+// UpdateCampaignNotification updates the given CampaignNotification.
+func (s *Store) UpdateCampaignNotification(ctx context.Context, n *campaigns.CampaignNotification) error {
+	q := s.updateCampaignNotificationQuery(n)
+
+	return s.exec(ctx, q, func(sc scanner) (last, count int64, err error) {
+		err = scanCampaignNotification(n, sc)
+		return n.ID, 1, err
+	})
+}
+
+var updateCampaignNotificationQueryFmtstr = `
+-- source: enterprise/internal/campaigns/store.go:UpdateCampaignNotification
+UPDATE campaign_notifications
+SET (
+  subscription_id,
+  changeset_id,
+  transition,
+  error,
+  started_at,
+  finished_at,
+  updated_at
+) = (%s, %s, %s, %s, %s, %s, %s)
+WHERE id = %s
+RETURNING
+  id,
+  subscription_id,
+  changeset_id,
+  transition,
+  error,
+  started_at,
+  finished_at,
+  created_at,
+  updated_at
+`
+
+func (s *Store) updateCampaignNotificationQuery(n *campaigns.CampaignNotification) *sqlf.Query {
+	n.UpdatedAt = s.now()
+
+	return sqlf.Sprintf(
+		updateCampaignNotificationQueryFmtstr,
+		n.SubscriptionID,
+		n.ChangesetID,
+		n.Transition,
+		nullStringColumn(n.Error),
+		nullTimeColumn(n.StartedAt),
+		nullTimeColumn(n.FinishedAt),
+		n.UpdatedAt,
+		n.ID,
+	)
+}
+
+// ListCampaignNotificationsOpts captures the query options needed for
+// listing campaign notifications.
+type ListCampaignNotificationsOpts struct {
+	Cursor         int64
+	Limit          int
+	SubscriptionID int64
+	ChangesetID    int64
+}
+
+// ListCampaignNotifications lists CampaignNotifications with the given
+// filters.
+func (s *Store) ListCampaignNotifications(ctx context.Context, opts ListCampaignNotificationsOpts) (ns []*campaigns.CampaignNotification, next int64, err error) {
+	q := listCampaignNotificationsQuery(&opts)
+
+	ns = make([]*campaigns.CampaignNotification, 0, opts.Limit)
+	_, _, err = s.query(ctx, q, func(sc scanner) (last, count int64, err error) {
+		var n campaigns.CampaignNotification
+		if err = scanCampaignNotification(&n, sc); err != nil {
+			return 0, 0, err
+		}
+		ns = append(ns, &n)
+		return n.ID, 1, err
+	})
+
+	if opts.Limit != 0 && len(ns) == opts.Limit {
+		next = ns[len(ns)-1].ID
+		ns = ns[:len(ns)-1]
+	}
+
+	return ns, next, err
+}
+
+var listCampaignNotificationsQueryFmtstr = `
+-- source: enterprise/internal/campaigns/store.go:ListCampaignNotifications
+SELECT
+  id,
+  subscription_id,
+  changeset_id,
+  transition,
+  error,
+  started_at,
+  finished_at,
+  created_at,
+  updated_at
+FROM campaign_notifications
+WHERE %s
+ORDER BY id ASC
+`
+
+func listCampaignNotificationsQuery(opts *ListCampaignNotificationsOpts) *sqlf.Query {
+	if opts.Limit == 0 {
+		opts.Limit = defaultListLimit
+	}
+	opts.Limit++
+
+	var limitClause string
+	if opts.Limit > 0 {
+		limitClause = fmt.Sprintf("LIMIT %d", opts.Limit)
+	}
+
+	preds := []*sqlf.Query{
+		sqlf.Sprintf("id >= %s", opts.Cursor),
+	}
+
+	if opts.SubscriptionID != 0 {
+		preds = append(preds, sqlf.Sprintf("subscription_id = %s", opts.SubscriptionID))
+	}
+
+	if opts.ChangesetID != 0 {
+		preds = append(preds, sqlf.Sprintf("changeset_id = %s", opts.ChangesetID))
+	}
+
+	return sqlf.Sprintf(
+		listCampaignNotificationsQueryFmtstr+limitClause,
+		sqlf.Join(preds, "\n AND "),
+	)
+}
+
+// CreatePatchSet creates the given PatchSet.
+func (s *Store) CreatePatchSet(ctx context.Context, c *campaigns.PatchSet) error {
+	q, err := s.createPatchSetQuery(c)
+	if err != nil {
+		return err
+	}
+
+	return s.exec(ctx, q, func(sc scanner) (last, count int64, err error) {
+		err = scanPatchSet(c, sc)
+		return c.ID, 1, err
+	})
+}
+
+var createPatchSetQueryFmtstr = `
+-- source: enterprise/internal/campaigns/store.go:CreatePatchSet
+INSERT INTO patch_sets (
+  created_at,
+  updated_at,
+  user_id,
+  replacement
+)
+VALUES (%s, %s, %s, %s)
+RETURNING
+  id,
+  created_at,
+  updated_at,
+  user_id,
+  replacement
+`
+
+func (s *Store) createPatchSetQuery(c *campaigns.PatchSet) (*sqlf.Query, error) {
+	if c.CreatedAt.IsZero() {
+		c.CreatedAt = s.now()
+	}
+
+	if c.UpdatedAt.IsZero() {
+		c.UpdatedAt = c.CreatedAt
+	}
+
+	replacement, err := replacementColumn(c.Replacement)
+	if err != nil {
+		return nil, err
+	}
+
+	return sqlf.Sprintf(
+		createPatchSetQueryFmtstr,
+		c.CreatedAt,
+		c.UpdatedAt,
+		c.UserID,
+		replacement,
+	), nil
+}
+
+// UpdatePatchSet updates the given PatchSet.
+func (s *Store) UpdatePatchSet(ctx context.Context, c *campaigns.PatchSet) error {
+	q, err := s.updatePatchSetQuery(c)
+	if err != nil {
+		return err
+	}
+
+	return s.exec(ctx, q, func(sc scanner) (last, count int64, err error) {
+		err = scanPatchSet(c, sc)
+		return c.ID, 1, err
+	})
+}
+
+var updatePatchSetQueryFmtstr = `
+-- source: enterprise/internal/campaigns/store.go:UpdatePatchSet
+UPDATE patch_sets
+SET (
+  updated_at,
+  user_id,
+  replacement
+) = (%s, %s, %s)
+WHERE id = %s
+RETURNING
+  id,
+  created_at,
+  updated_at,
+  user_id,
+  replacement
+`
+
+func (s *Store) updatePatchSetQuery(c *campaigns.PatchSet) (*sqlf.Query, error) {
+	replacement, err := replacementColumn(c.Replacement)
+	if err != nil {
+		return nil, err
+	}
+
+	c.UpdatedAt = s.now()
+
+	return sqlf.Sprintf(
+		updatePatchSetQueryFmtstr,
+		c.UpdatedAt,
+		c.UserID,
+		replacement,
+		c.ID,
+	), nil
+}
+
+// DeletePatchSet deletes the PatchSet with the given ID.
+func (s *Store) DeletePatchSet(ctx context.Context, id int64) error {
+	q := sqlf.Sprintf(deletePatchSetQueryFmtstr, id)
+
+	rows, err := s.db.QueryContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...)
+	if err != nil {
+		return err
+	}
+	return rows.Close()
+}
+
+var deletePatchSetQueryFmtstr = `
+-- source: enterprise/internal/campaigns/store.go:DeletePatchSet
+DELETE FROM patch_sets WHERE id = %s
+`
+
+const PatchSetTTL = 1 * time.Hour
+
+// DeleteExpiredPatchSets deletes PatchSets that have not been attached to a Campaign within PatchSetTTL.
+// PatchSets whose PatchJobs are still being processed are never deleted.
+func (s *Store) DeleteExpiredPatchSets(ctx context.Context) error {
+	expirationTime := s.now().Add(-PatchSetTTL)
+	q := sqlf.Sprintf(deleteExpiredPatchSetsQueryFmtstr, expirationTime)
+
+	rows, err := s.db.QueryContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...)
+	if err != nil {
+		return err
+	}
+	return rows.Close()
+}
+
+var deleteExpiredPatchSetsQueryFmtstr = `
+-- source: enterprise/internal/campaigns/store.go:DeleteExpiredPatchSets
+DELETE FROM
+  patch_sets
+WHERE
+  created_at < %s
+AND
+NOT EXISTS (
+  SELECT 1
+  FROM
+    campaigns
+  WHERE
+    campaigns.patch_set_id = patch_sets.id
+  )
+AND
+NOT EXISTS (
+  SELECT 1
+  FROM
+    changeset_jobs
+  JOIN patches ON patches.id = changeset_jobs.patch_id
+  JOIN changesets ON changesets.id = changeset_jobs.changeset_id
+  WHERE
+    (SELECT COUNT(*) FROM jsonb_object_keys(changesets.campaign_ids)) > 0
+)
+AND
+NOT EXISTS (
+  SELECT 1
+  FROM
+    patch_jobs
+  WHERE
+    patch_jobs.patch_set_id = patch_sets.id
+  AND
+    patch_jobs.finished_at IS NULL
+);
+`
+
+// CountPatchSets returns the number of code mods in the database.
+func (s *Store) CountPatchSets(ctx context.Context) (count int64, _ error) {
+	q := sqlf.Sprintf(countPatchSetsQueryFmtstr)
+	return count, s.exec(ctx, q, func(sc scanner) (_, _ int64, err error) {
+		err = sc.Scan(&count)
+		return 0, count, err
+	})
+}
+
+var countPatchSetsQueryFmtstr = `
+-- source: enterprise/internal/campaigns/store.go:CountPatchSets
+SELECT COUNT(id)
+FROM patch_sets
+`
+
+// GetPatchSetOpts captures the query options needed for getting a PatchSet
+type GetPatchSetOpts struct {
 	ID int64
 }
 
@@ -1698,7 +3532,8 @@ SELECT
   id,
   created_at,
   updated_at,
-  user_id
+  user_id,
+  replacement
 FROM patch_sets
 WHERE %s
 LIMIT 1
@@ -1762,6 +3597,29 @@ WHERE %s
 LIMIT 1
 `
 
+// GetPatchSetStatus gets the campaigns.BackgroundProcessStatus of the
+// PatchJobs generating the Patches of a PatchSet.
+func (s *Store) GetPatchSetStatus(ctx context.Context, id int64) (*campaigns.BackgroundProcessStatus, error) {
+	return s.queryBackgroundProcessStatus(ctx, sqlf.Sprintf(
+		getPatchSetStatusQueryFmtstr,
+		sqlf.Sprintf("patch_set_id = %s", id),
+	))
+}
+
+var getPatchSetStatusQueryFmtstr = `
+-- source: enterprise/internal/campaigns/store.go:GetPatchSetStatus
+SELECT
+  -- canceled is here so that this can be used with scanBackgroundProcessStatus
+  false AS canceled,
+  COUNT(*) AS total,
+  COUNT(*) FILTER (WHERE finished_at IS NULL) AS pending,
+  COUNT(*) FILTER (WHERE finished_at IS NOT NULL) AS completed,
+  array_agg(error) FILTER (WHERE error != '') AS errors
+FROM patch_jobs
+WHERE %s
+LIMIT 1
+`
+
 // ListPatchSetsOpts captures the query options needed for
 // listing code mods.
 type ListPatchSetsOpts struct {
@@ -1797,7 +3655,8 @@ SELECT
   id,
   created_at,
   updated_at,
-  user_id
+  user_id,
+  replacement
 FROM patch_sets
 WHERE %s
 ORDER BY id ASC
@@ -2547,6 +4406,189 @@ SET
 WHERE %s
 `
 
+// CreatePatchJob creates the given PatchJob.
+func (s *Store) CreatePatchJob(ctx context.Context, j *campaigns.PatchJob) error {
+	q, err := s.createPatchJobQuery(j)
+	if err != nil {
+		return err
+	}
+
+	return s.exec(ctx, q, func(sc scanner) (last, count int64, err error) {
+		err = scanPatchJob(j, sc)
+		return j.ID, 1, err
+	})
+}
+
+var createPatchJobQueryFmtstr = `
+-- source: enterprise/internal/campaigns/store.go:CreatePatchJob
+INSERT INTO patch_jobs (
+  patch_set_id,
+  repo_id,
+  patch_id,
+  error,
+  started_at,
+  finished_at,
+  created_at,
+  updated_at
+)
+VALUES (%s, %s, %s, %s, %s, %s, %s, %s)
+RETURNING
+  id,
+  patch_set_id,
+  repo_id,
+  patch_id,
+  error,
+  started_at,
+  finished_at,
+  created_at,
+  updated_at
+`
+
+func (s *Store) createPatchJobQuery(j *campaigns.PatchJob) (*sqlf.Query, error) {
+	if j.CreatedAt.IsZero() {
+		j.CreatedAt = s.now()
+	}
+
+	if j.UpdatedAt.IsZero() {
+		j.UpdatedAt = j.CreatedAt
+	}
+
+	return sqlf.Sprintf(
+		createPatchJobQueryFmtstr,
+		j.PatchSetID,
+		j.RepoID,
+		nullInt64Column(j.PatchID),
+		nullStringColumn(j.Error),
+		nullTimeColumn(j.StartedAt),
+		nullTimeColumn(j.FinishedAt),
+		j.CreatedAt,
+		j.UpdatedAt,
+	), nil
+}
+
+// UpdatePatchJob updates the given PatchJob.
+func (s *Store) UpdatePatchJob(ctx context.Context, j *campaigns.PatchJob) error {
+	q, err := s.updatePatchJobQuery(j)
+	if err != nil {
+		return err
+	}
+
+	return s.exec(ctx, q, func(sc scanner) (last, count int64, err error) {
+		err = scanPatchJob(j, sc)
+		return j.ID, 1, err
+	})
+}
+
+var updatePatchJobQueryFmtstr = `
+-- source: enterprise/internal/campaigns/store.go:UpdatePatchJob
+UPDATE patch_jobs
+SET (
+  patch_set_id,
+  repo_id,
+  patch_id,
+  error,
+  started_at,
+  finished_at,
+  updated_at
+) = (%s, %s, %s, %s, %s, %s, %s)
+WHERE id = %s
+RETURNING
+  id,
+  patch_set_id,
+  repo_id,
+  patch_id,
+  error,
+  started_at,
+  finished_at,
+  created_at,
+  updated_at
+`
+
+func (s *Store) updatePatchJobQuery(j *campaigns.PatchJob) (*sqlf.Query, error) {
+	j.UpdatedAt = s.now()
+
+	return sqlf.Sprintf(
+		updatePatchJobQueryFmtstr,
+		j.PatchSetID,
+		j.RepoID,
+		nullInt64Column(j.PatchID),
+		nullStringColumn(j.Error),
+		nullTimeColumn(j.StartedAt),
+		nullTimeColumn(j.FinishedAt),
+		j.UpdatedAt,
+		j.ID,
+	), nil
+}
+
+// ListPatchJobsOpts captures the query options needed for
+// listing patch jobs.
+type ListPatchJobsOpts struct {
+	PatchSetID int64
+	Cursor     int64
+	Limit      int
+}
+
+// ListPatchJobs lists PatchJobs with the given filters.
+func (s *Store) ListPatchJobs(ctx context.Context, opts ListPatchJobsOpts) (js []*campaigns.PatchJob, next int64, err error) {
+	q := listPatchJobsQuery(&opts)
+
+	js = make([]*campaigns.PatchJob, 0, opts.Limit)
+	_, _, err = s.query(ctx, q, func(sc scanner) (last, count int64, err error) {
+		var j campaigns.PatchJob
+		if err = scanPatchJob(&j, sc); err != nil {
+			return 0, 0, err
+		}
+		js = append(js, &j)
+		return j.ID, 1, err
+	})
+
+	if opts.Limit != 0 && len(js) == opts.Limit {
+		next = js[len(js)-1].ID
+		js = js[:len(js)-1]
+	}
+
+	return js, next, err
+}
+
+var listPatchJobsQueryFmtstr = `
+-- source: enterprise/internal/campaigns/store.go:ListPatchJobs
+SELECT
+  id,
+  patch_set_id,
+  repo_id,
+  patch_id,
+  error,
+  started_at,
+  finished_at,
+  created_at,
+  updated_at
+FROM patch_jobs
+WHERE %s
+ORDER BY id ASC
+LIMIT %s
+`
+
+func listPatchJobsQuery(opts *ListPatchJobsOpts) *sqlf.Query {
+	if opts.Limit == 0 {
+		opts.Limit = defaultListLimit
+	}
+	opts.Limit++
+
+	preds := []*sqlf.Query{
+		sqlf.Sprintf("id >= %s", opts.Cursor),
+	}
+
+	if opts.PatchSetID != 0 {
+		preds = append(preds, sqlf.Sprintf("patch_set_id = %s", opts.PatchSetID))
+	}
+
+	return sqlf.Sprintf(
+		listPatchJobsQueryFmtstr,
+		sqlf.Join(preds, "\n AND "),
+		opts.Limit,
+	)
+}
+
 // GetChangesetExternalIDs allows us to find the external ids for pull requests based on
 // a slice of head refs. We need this in order to match incoming webhooks to pull requests as
 // the only information they provide is the remote branch
@@ -2637,6 +4679,9 @@ func scanChangeset(t *campaigns.Changeset, s scanner) error {
 		externalState       string
 		externamReviewState string
 		externalCheckState  string
+		rebaseState         string
+		baseCommit          string
+		pushedCommit        string
 	)
 	err := s.Scan(
 		&t.ID,
@@ -2653,6 +4698,9 @@ func scanChangeset(t *campaigns.Changeset, s scanner) error {
 		&dbutil.NullString{S: &externalState},
 		&dbutil.NullString{S: &externamReviewState},
 		&dbutil.NullString{S: &externalCheckState},
+		&dbutil.NullString{S: &rebaseState},
+		&dbutil.NullString{S: &baseCommit},
+		&dbutil.NullString{S: &pushedCommit},
 	)
 	if err != nil {
 		return errors.Wrap(err, "scanning changeset")
@@ -2661,6 +4709,9 @@ func scanChangeset(t *campaigns.Changeset, s scanner) error {
 	t.ExternalState = campaigns.ChangesetState(externalState)
 	t.ExternalReviewState = campaigns.ChangesetReviewState(externamReviewState)
 	t.ExternalCheckState = campaigns.ChangesetCheckState(externalCheckState)
+	t.RebaseState = campaigns.ChangesetRebaseState(rebaseState)
+	t.BaseCommit = api.CommitID(baseCommit)
+	t.PushedCommit = api.CommitID(pushedCommit)
 
 	switch t.ExternalServiceType {
 	case github.ServiceType:
@@ -2707,7 +4758,8 @@ func scanChangesetEvent(e *campaigns.ChangesetEvent, s scanner) error {
 }
 
 func scanCampaign(c *campaigns.Campaign, s scanner) error {
-	return s.Scan(
+	var templateParams, changesetOptions json.RawMessage
+	err := s.Scan(
 		&c.ID,
 		&c.Name,
 		&dbutil.NullString{S: &c.Description},
@@ -2720,11 +4772,187 @@ func scanCampaign(c *campaigns.Campaign, s scanner) error {
 		&dbutil.JSONInt64Set{Set: &c.ChangesetIDs},
 		&dbutil.NullInt64{N: &c.PatchSetID},
 		&dbutil.NullTime{Time: &c.ClosedAt},
+		&c.AutoMerge,
+		&c.MergeMethod,
+		&c.MergesPerHour,
+		&dbutil.NullInt64{N: &c.TemplateVersionID},
+		&templateParams,
+		&changesetOptions,
+	)
+	if err != nil {
+		return err
+	}
+
+	c.TemplateParams = nil
+	if err = json.Unmarshal(templateParams, &c.TemplateParams); err != nil {
+		return errors.Wrap(err, "scanCampaign: failed to unmarshal template params")
+	}
+	if len(c.TemplateParams) == 0 {
+		c.TemplateParams = nil
+	}
+
+	c.ChangesetOptions = campaigns.ChangesetOptions{}
+	if err = json.Unmarshal(changesetOptions, &c.ChangesetOptions); err != nil {
+		return errors.Wrap(err, "scanCampaign: failed to unmarshal changeset options")
+	}
+	return nil
+}
+
+func scanCampaignTemplate(t *campaigns.CampaignTemplate, s scanner) error {
+	return s.Scan(
+		&t.ID,
+		&t.Name,
+		&dbutil.NullString{S: &t.Description},
+		&t.AuthorID,
+		&dbutil.NullInt32{N: &t.NamespaceUserID},
+		&dbutil.NullInt32{N: &t.NamespaceOrgID},
+		&t.LatestVersion,
+		&t.CreatedAt,
+		&t.UpdatedAt,
+	)
+}
+
+func scanCampaignTemplateVersion(v *campaigns.CampaignTemplateVersion, s scanner) error {
+	var spec json.RawMessage
+	err := s.Scan(
+		&v.ID,
+		&v.TemplateID,
+		&v.Version,
+		&spec,
+		&v.AuthorID,
+		&v.CreatedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	v.Spec = campaigns.CampaignTemplateSpec{}
+	if err = json.Unmarshal(spec, &v.Spec); err != nil {
+		return errors.Wrap(err, "scanCampaignTemplateVersion: failed to unmarshal spec")
+	}
+	return nil
+}
+
+func scanChangesetImportQuery(q *campaigns.ChangesetImportQuery, s scanner) error {
+	return s.Scan(
+		&q.ID,
+		&q.CampaignID,
+		&q.ExternalServiceID,
+		&q.Query,
+		&dbutil.NullTime{Time: &q.LastImportedAt},
+		&q.LastError,
+		&q.CreatedAt,
+		&q.UpdatedAt,
+	)
+}
+
+func scanChangesetAction(a *campaigns.ChangesetAction, s scanner) error {
+	var labels, reviewers json.RawMessage
+	err := s.Scan(
+		&a.ID,
+		&a.CampaignID,
+		&a.UserID,
+		&a.Type,
+		&a.Body,
+		&labels,
+		&reviewers,
+		&a.CreatedAt,
+		&a.UpdatedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	if err = json.Unmarshal(labels, &a.Labels); err != nil {
+		return errors.Wrap(err, "scanChangesetAction: failed to unmarshal labels")
+	}
+	if err = json.Unmarshal(reviewers, &a.Reviewers); err != nil {
+		return errors.Wrap(err, "scanChangesetAction: failed to unmarshal reviewers")
+	}
+	return nil
+}
+
+func scanChangesetActionJob(j *campaigns.ChangesetActionJob, s scanner) error {
+	return s.Scan(
+		&j.ID,
+		&j.ChangesetActionID,
+		&j.ChangesetID,
+		&dbutil.NullString{S: &j.Error},
+		&dbutil.NullTime{Time: &j.StartedAt},
+		&dbutil.NullTime{Time: &j.FinishedAt},
+		&j.CreatedAt,
+		&j.UpdatedAt,
+	)
+}
+
+func scanCampaignNotificationSubscription(sub *campaigns.CampaignNotificationSubscription, s scanner) error {
+	var transitions json.RawMessage
+	err := s.Scan(
+		&sub.ID,
+		&sub.CampaignID,
+		&sub.UserID,
+		&sub.Channel,
+		&sub.URL,
+		&sub.Secret,
+		&transitions,
+		&sub.CreatedAt,
+		&sub.UpdatedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	if err = json.Unmarshal(transitions, &sub.Transitions); err != nil {
+		return errors.Wrap(err, "scanCampaignNotificationSubscription: failed to unmarshal transitions")
+	}
+	return nil
+}
+
+func scanCampaignNotification(n *campaigns.CampaignNotification, s scanner) error {
+	return s.Scan(
+		&n.ID,
+		&n.SubscriptionID,
+		&n.ChangesetID,
+		&n.Transition,
+		&dbutil.NullString{S: &n.Error},
+		&dbutil.NullTime{Time: &n.StartedAt},
+		&dbutil.NullTime{Time: &n.FinishedAt},
+		&n.CreatedAt,
+		&n.UpdatedAt,
 	)
 }
 
 func scanPatchSet(c *campaigns.PatchSet, s scanner) error {
-	return s.Scan(&c.ID, &c.CreatedAt, &c.UpdatedAt, &c.UserID)
+	var replacement []byte
+	err := s.Scan(&c.ID, &c.CreatedAt, &c.UpdatedAt, &c.UserID, &replacement)
+	if err != nil {
+		return err
+	}
+
+	c.Replacement = nil
+	if len(replacement) == 0 {
+		return nil
+	}
+
+	c.Replacement = new(campaigns.Replacement)
+	if err = json.Unmarshal(replacement, c.Replacement); err != nil {
+		return errors.Wrap(err, "scanPatchSet: failed to unmarshal replacement")
+	}
+	return nil
+}
+
+func scanPatchJob(j *campaigns.PatchJob, s scanner) error {
+	return s.Scan(
+		&j.ID,
+		&j.PatchSetID,
+		&j.RepoID,
+		&dbutil.NullInt64{N: &j.PatchID},
+		&dbutil.NullString{S: &j.Error},
+		&dbutil.NullTime{Time: &j.StartedAt},
+		&dbutil.NullTime{Time: &j.FinishedAt},
+		&j.CreatedAt,
+		&j.UpdatedAt,
+	)
 }
 
 func scanPatch(c *campaigns.Patch, s scanner) error {
@@ -2781,6 +5009,17 @@ func metadataColumn(metadata interface{}) (msg json.RawMessage, err error) {
 	return
 }
 
+func replacementColumn(r *campaigns.Replacement) (*string, error) {
+	if r == nil {
+		return nil, nil
+	}
+	b, err := json.Marshal(r)
+	if err != nil {
+		return nil, err
+	}
+	return nullStringColumn(string(b)), nil
+}
+
 func jsonSetColumn(ids []int64) ([]byte, error) {
 	set := make(map[int64]*struct{}, len(ids))
 	for _, id := range ids {
@@ -2788,3 +5027,24 @@ func jsonSetColumn(ids []int64) ([]byte, error) {
 	}
 	return json.Marshal(set)
 }
+
+func stringsColumn(ss []string) ([]byte, error) {
+	if ss == nil {
+		ss = []string{}
+	}
+	return json.Marshal(ss)
+}
+
+func transitionsColumn(ts []campaigns.ChangesetTransition) ([]byte, error) {
+	if ts == nil {
+		ts = []campaigns.ChangesetTransition{}
+	}
+	return json.Marshal(ts)
+}
+
+func templateParamsColumn(params map[string]string) ([]byte, error) {
+	if params == nil {
+		params = map[string]string{}
+	}
+	return json.Marshal(params)
+}
@@ -95,6 +95,16 @@ func testStore(db *sql.DB) func(*testing.T) {
 						c.NamespaceUserID = 42
 					}
 
+					if i == 1 {
+						c.ChangesetOptions = cmpgn.ChangesetOptions{
+							TitleTemplate: "Upgrade ES-Lint in {{.RepositoryName}}",
+							Labels:        []string{"automation"},
+							Reviewers:     cmpgn.ChangesetUsersRule{FromCodeOwners: true},
+							Assignees:     cmpgn.ChangesetUsersRule{Users: []string{"alice"}},
+							Draft:         true,
+						}
+					}
+
 					want := c.Clone()
 					have := c
 
@@ -415,516 +425,348 @@ func testStore(db *sql.DB) func(*testing.T) {
 
 		})
 
-		t.Run("Changesets", func(t *testing.T) {
-			githubActor := github.Actor{
-				AvatarURL: "https://avatars2.githubusercontent.com/u/1185253",
-				Login:     "mrnugget",
-				URL:       "https://github.com/mrnugget",
-			}
-			githubPR := &github.PullRequest{
-				ID:           "FOOBARID",
-				Title:        "Fix a bunch of bugs",
-				Body:         "This fixes a bunch of bugs",
-				URL:          "https://github.com/sourcegraph/sourcegraph/pull/12345",
-				Number:       12345,
-				Author:       githubActor,
-				Participants: []github.Actor{githubActor},
-				CreatedAt:    now,
-				UpdatedAt:    now,
-				HeadRefName:  "campaigns/test",
-			}
-
-			changesets := make([]*cmpgn.Changeset, 0, 3)
+		t.Run("CampaignTemplates", func(t *testing.T) {
+			templates := make([]*cmpgn.CampaignTemplate, 0, 3)
 
 			t.Run("Create", func(t *testing.T) {
-				var i int
-				for i = 0; i < cap(changesets); i++ {
-					th := &cmpgn.Changeset{
-						RepoID:              repo.ID,
-						CreatedAt:           now,
-						UpdatedAt:           now,
-						Metadata:            githubPR,
-						CampaignIDs:         []int64{int64(i) + 1},
-						ExternalID:          fmt.Sprintf("foobar-%d", i),
-						ExternalServiceType: "github",
-						ExternalBranch:      "campaigns/test",
-						ExternalUpdatedAt:   now,
-						ExternalState:       cmpgn.ChangesetStateOpen,
-						ExternalReviewState: cmpgn.ChangesetReviewStateApproved,
-						ExternalCheckState:  cmpgn.ChangesetCheckStatePassed,
+				for i := 0; i < cap(templates); i++ {
+					tmpl := &cmpgn.CampaignTemplate{
+						Name:        fmt.Sprintf("Upgrade ES-Lint %d", i),
+						Description: "All the Javascripts are belong to us",
+						AuthorID:    23,
 					}
 
-					changesets = append(changesets, th)
-				}
-
-				err := s.CreateChangesets(ctx, changesets...)
-				if err != nil {
-					t.Fatal(err)
-				}
+					if i%2 == 0 {
+						tmpl.NamespaceOrgID = 23
+					} else {
+						tmpl.NamespaceUserID = 42
+					}
 
-				err = s.CreateChangesets(ctx, &cmpgn.Changeset{
-					RepoID:              deletedRepo.ID,
-					CreatedAt:           now,
-					UpdatedAt:           now,
-					Metadata:            githubPR,
-					CampaignIDs:         []int64{int64(i) + 1},
-					ExternalID:          fmt.Sprintf("foobar-%d", i),
-					ExternalServiceType: "github",
-					ExternalBranch:      "campaigns/test",
-					ExternalUpdatedAt:   now,
-					ExternalState:       cmpgn.ChangesetStateOpen,
-					ExternalReviewState: cmpgn.ChangesetReviewStateApproved,
-					ExternalCheckState:  cmpgn.ChangesetCheckStatePassed,
-				})
-				if err != nil {
-					t.Fatal(err)
-				}
+					want := tmpl.Clone()
+					have := tmpl
 
-				for _, have := range changesets {
-					if have.ID == 0 {
-						t.Fatal("id should not be zero")
+					err := s.CreateCampaignTemplate(ctx, have)
+					if err != nil {
+						t.Fatal(err)
 					}
 
-					if have.IsDeleted() {
-						t.Fatal("changeset is deleted")
+					if have.ID == 0 {
+						t.Fatal("ID should not be zero")
 					}
 
-					want := have.Clone()
-
 					want.ID = have.ID
+					want.LatestVersion = 1
 					want.CreatedAt = now
 					want.UpdatedAt = now
 
 					if diff := cmp.Diff(have, want); diff != "" {
 						t.Fatal(diff)
 					}
-				}
-			})
 
-			t.Run("GetChangesetExternalIDs", func(t *testing.T) {
-				spec := api.ExternalRepoSpec{
-					ID:          "external-id",
-					ServiceType: "github",
-					ServiceID:   "https://github.com/",
-				}
-				have, err := s.GetChangesetExternalIDs(ctx, spec, []string{"campaigns/test"})
-				if err != nil {
-					t.Fatal(err)
-				}
-				want := []string{"foobar-0", "foobar-1", "foobar-2"}
-				if diff := cmp.Diff(want, have); diff != "" {
-					t.Fatal(diff)
+					templates = append(templates, tmpl)
 				}
 			})
 
-			t.Run("GetChangesetExternalIDs no branch", func(t *testing.T) {
-				spec := api.ExternalRepoSpec{
-					ID:          "external-id",
-					ServiceType: "github",
-					ServiceID:   "https://github.com/",
-				}
-				have, err := s.GetChangesetExternalIDs(ctx, spec, []string{"foo"})
+			t.Run("Count", func(t *testing.T) {
+				count, err := s.CountCampaignTemplates(ctx, CountCampaignTemplatesOpts{})
 				if err != nil {
 					t.Fatal(err)
 				}
-				want := []string{}
-				if diff := cmp.Diff(want, have); diff != "" {
-					t.Fatal(diff)
-				}
-			})
 
-			t.Run("GetChangesetExternalIDs invalid external-id", func(t *testing.T) {
-				spec := api.ExternalRepoSpec{
-					ID:          "invalid",
-					ServiceType: "github",
-					ServiceID:   "https://github.com/",
-				}
-				have, err := s.GetChangesetExternalIDs(ctx, spec, []string{"campaigns/test"})
-				if err != nil {
-					t.Fatal(err)
-				}
-				want := []string{}
-				if diff := cmp.Diff(want, have); diff != "" {
-					t.Fatal(diff)
+				if have, want := count, int64(len(templates)); have != want {
+					t.Fatalf("have count: %d, want: %d", have, want)
 				}
-			})
 
-			t.Run("GetChangesetExternalIDs invalid external service id", func(t *testing.T) {
-				spec := api.ExternalRepoSpec{
-					ID:          "external-id",
-					ServiceType: "github",
-					ServiceID:   "invalid",
-				}
-				have, err := s.GetChangesetExternalIDs(ctx, spec, []string{"campaigns/test"})
+				count, err = s.CountCampaignTemplates(ctx, CountCampaignTemplatesOpts{NamespaceOrgID: 23})
 				if err != nil {
 					t.Fatal(err)
 				}
-				want := []string{}
-				if diff := cmp.Diff(want, have); diff != "" {
-					t.Fatal(diff)
-				}
-			})
 
-			t.Run("CreateAlreadyExistingChangesets", func(t *testing.T) {
-				ids := make([]int64, len(changesets))
-				for i, c := range changesets {
-					ids[i] = c.ID
+				if have, want := count, int64(2); have != want {
+					t.Fatalf("have count: %d, want: %d", have, want)
 				}
+			})
 
-				clones := make([]*cmpgn.Changeset, len(changesets))
+			t.Run("List", func(t *testing.T) {
+				t.Run("ByNamespace", func(t *testing.T) {
+					have, _, err := s.ListCampaignTemplates(ctx, ListCampaignTemplatesOpts{NamespaceUserID: 42})
+					if err != nil {
+						t.Fatal(err)
+					}
 
-				for i, c := range changesets {
-					// Set only the fields on which we have a unique constraint
-					clones[i] = &cmpgn.Changeset{
-						RepoID:              c.RepoID,
-						ExternalID:          c.ExternalID,
-						ExternalServiceType: c.ExternalServiceType,
+					want := templates[1:2]
+					if diff := cmp.Diff(have, want); diff != "" {
+						t.Fatal(diff)
 					}
-				}
+				})
 
-				// Advance clock so store can determine whether Changeset was
-				// inserted or not
-				now = now.Add(time.Second)
+				t.Run("WithLimit", func(t *testing.T) {
+					for i := 1; i <= len(templates); i++ {
+						ts, next, err := s.ListCampaignTemplates(ctx, ListCampaignTemplatesOpts{Limit: i})
+						if err != nil {
+							t.Fatal(err)
+						}
 
-				err := s.CreateChangesets(ctx, clones...)
-				ae, ok := err.(AlreadyExistError)
-				if !ok {
-					t.Fatal(err)
-				}
+						{
+							have, want := next, int64(0)
+							if i < len(templates) {
+								want = templates[i].ID
+							}
 
-				{
-					sort.Slice(ae.ChangesetIDs, func(i, j int) bool { return ae.ChangesetIDs[i] < ae.ChangesetIDs[j] })
-					sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+							if have != want {
+								t.Fatalf("limit: %v: have next %v, want %v", i, have, want)
+							}
+						}
 
-					have, want := ae.ChangesetIDs, ids
-					if len(have) != len(want) {
-						t.Fatalf("%d changesets already exist, want: %d", len(have), len(want))
-					}
+						{
+							have, want := ts, templates[:i]
+							if len(have) != len(want) {
+								t.Fatalf("listed %d templates, want: %d", len(have), len(want))
+							}
 
-					if diff := cmp.Diff(have, want); diff != "" {
-						t.Fatal(diff)
+							if diff := cmp.Diff(have, want); diff != "" {
+								t.Fatalf("opts: %+v, diff: %s", i, diff)
+							}
+						}
 					}
-				}
+				})
+			})
 
-				{
-					// Verify that we got the original changesets back
-					have, want := clones, changesets
-					if len(have) != len(want) {
-						t.Fatalf("created %d changesets, want: %d", len(have), len(want))
+			t.Run("Update", func(t *testing.T) {
+				for _, tmpl := range templates {
+					now = now.Add(time.Second)
+					tmpl.Name += "-updated"
+					tmpl.LatestVersion++
+
+					want := tmpl.Clone()
+					want.UpdatedAt = now
+
+					if err := s.UpdateCampaignTemplate(ctx, tmpl); err != nil {
+						t.Fatal(err)
 					}
 
-					if diff := cmp.Diff(have, want); diff != "" {
+					if diff := cmp.Diff(tmpl, want); diff != "" {
 						t.Fatal(diff)
 					}
 				}
 			})
 
-			t.Run("Count", func(t *testing.T) {
-				count, err := s.CountChangesets(ctx, CountChangesetsOpts{})
-				if err != nil {
-					t.Fatal(err)
-				}
+			t.Run("Versions", func(t *testing.T) {
+				def := "v1"
+				versions := make([]*cmpgn.CampaignTemplateVersion, 0, 2)
+				for i := 0; i < cap(versions); i++ {
+					v := &cmpgn.CampaignTemplateVersion{
+						TemplateID: templates[0].ID,
+						Version:    int32(i + 1),
+						Spec: cmpgn.CampaignTemplateSpec{
+							Action:        `{"steps":[]}`,
+							ScopeQuery:    "repo:github.com/sourcegraph/",
+							BranchPattern: "upgrade-es-lint-{{.Params.version}}",
+							TitleTemplate: "Upgrade ES-Lint in {{.RepositoryName}}",
+							Parameters: []cmpgn.CampaignTemplateParameter{
+								{Name: "version", Description: "The version", Default: &def},
+							},
+						},
+						AuthorID: 23,
+					}
 
-				if have, want := count, int64(len(changesets)); have != want {
-					t.Fatalf("have count: %d, want: %d", have, want)
-				}
+					want := v.Clone()
+					if err := s.CreateCampaignTemplateVersion(ctx, v); err != nil {
+						t.Fatal(err)
+					}
 
-				count, err = s.CountChangesets(ctx, CountChangesetsOpts{CampaignID: 1})
-				if err != nil {
-					t.Fatal(err)
-				}
+					want.ID = v.ID
+					want.CreatedAt = now
+					if diff := cmp.Diff(v, want); diff != "" {
+						t.Fatal(diff)
+					}
 
-				if have, want := count, int64(1); have != want {
-					t.Fatalf("have count: %d, want: %d", have, want)
+					versions = append(versions, v)
 				}
-			})
 
-			t.Run("List", func(t *testing.T) {
-				for i := 1; i <= len(changesets); i++ {
-					opts := ListChangesetsOpts{CampaignID: int64(i)}
-
-					ts, next, err := s.ListChangesets(ctx, opts)
+				t.Run("GetByID", func(t *testing.T) {
+					have, err := s.GetCampaignTemplateVersion(ctx, GetCampaignTemplateVersionOpts{ID: versions[0].ID})
 					if err != nil {
 						t.Fatal(err)
 					}
 
-					if have, want := next, int64(0); have != want {
-						t.Fatalf("opts: %+v: have next %v, want %v", opts, have, want)
+					if diff := cmp.Diff(have, versions[0]); diff != "" {
+						t.Fatal(diff)
 					}
+				})
 
-					have, want := ts, changesets[i-1:i]
-					if len(have) != len(want) {
-						t.Fatalf("listed %d changesets, want: %d", len(have), len(want))
+				t.Run("GetByVersion", func(t *testing.T) {
+					have, err := s.GetCampaignTemplateVersion(ctx, GetCampaignTemplateVersionOpts{
+						TemplateID: templates[0].ID,
+						Version:    2,
+					})
+					if err != nil {
+						t.Fatal(err)
 					}
 
-					if diff := cmp.Diff(have, want); diff != "" {
-						t.Fatalf("opts: %+v, diff: %s", opts, diff)
+					if diff := cmp.Diff(have, versions[1]); diff != "" {
+						t.Fatal(diff)
 					}
-				}
+				})
 
-				for i := 1; i <= len(changesets); i++ {
-					ts, next, err := s.ListChangesets(ctx, ListChangesetsOpts{Limit: i})
+				t.Run("List", func(t *testing.T) {
+					have, next, err := s.ListCampaignTemplateVersions(ctx, ListCampaignTemplateVersionsOpts{TemplateID: templates[0].ID})
 					if err != nil {
 						t.Fatal(err)
 					}
 
-					{
-						have, want := next, int64(0)
-						if i < len(changesets) {
-							want = changesets[i].ID
-						}
-
-						if have != want {
-							t.Fatalf("limit: %v: have next %v, want %v", i, have, want)
-						}
+					if next != 0 {
+						t.Fatalf("have next %d, want 0", next)
 					}
 
-					{
-						have, want := ts, changesets[:i]
-						if len(have) != len(want) {
-							t.Fatalf("listed %d changesets, want: %d", len(have), len(want))
-						}
-
-						if diff := cmp.Diff(have, want); diff != "" {
-							t.Fatal(diff)
-						}
+					if diff := cmp.Diff(have, versions); diff != "" {
+						t.Fatal(diff)
 					}
-				}
+				})
 
-				{
-					ids := make([]int64, len(changesets))
-					for i := range changesets {
-						ids[i] = changesets[i].ID
+				t.Run("CampaignReference", func(t *testing.T) {
+					c := &cmpgn.Campaign{
+						Name:              "From template",
+						AuthorID:          23,
+						NamespaceUserID:   42,
+						TemplateVersionID: versions[1].ID,
+						TemplateParams:    map[string]string{"version": "v2"},
+					}
+					if err := s.CreateCampaign(ctx, c); err != nil {
+						t.Fatal(err)
 					}
 
-					have, _, err := s.ListChangesets(ctx, ListChangesetsOpts{IDs: ids})
+					have, err := s.GetCampaign(ctx, GetCampaignOpts{ID: c.ID})
 					if err != nil {
 						t.Fatal(err)
 					}
 
-					want := changesets
-					if diff := cmp.Diff(have, want); diff != "" {
+					if diff := cmp.Diff(have, c); diff != "" {
 						t.Fatal(diff)
 					}
-				}
-
-				{
-					var cursor int64
-					for i := 1; i <= len(changesets); i++ {
-						opts := ListChangesetsOpts{Cursor: cursor, Limit: 1}
-						have, next, err := s.ListChangesets(ctx, opts)
-						if err != nil {
-							t.Fatal(err)
-						}
-
-						want := changesets[i-1 : i]
-						if diff := cmp.Diff(have, want); diff != "" {
-							t.Fatalf("opts: %+v, diff: %s", opts, diff)
-						}
 
-						cursor = next
+					if err := s.DeleteCampaign(ctx, c.ID); err != nil {
+						t.Fatal(err)
 					}
-				}
+				})
+			})
 
-				{
-					have, _, err := s.ListChangesets(ctx, ListChangesetsOpts{WithoutDeleted: true})
+			t.Run("Get", func(t *testing.T) {
+				t.Run("ByID", func(t *testing.T) {
+					want := templates[0]
+
+					have, err := s.GetCampaignTemplate(ctx, GetCampaignTemplateOpts{ID: want.ID})
 					if err != nil {
 						t.Fatal(err)
 					}
 
-					if len(have) != len(changesets) {
-						t.Fatalf("have 0 changesets. want %d", len(changesets))
+					if diff := cmp.Diff(have, want); diff != "" {
+						t.Fatal(diff)
 					}
+				})
 
-					for _, c := range changesets {
-						c.SetDeleted()
-						c.UpdatedAt = now
-					}
+				t.Run("NoResults", func(t *testing.T) {
+					_, have := s.GetCampaignTemplate(ctx, GetCampaignTemplateOpts{ID: 0xdeadbeef})
+					want := ErrNoResults
 
-					if err := s.UpdateChangesets(ctx, changesets...); err != nil {
-						t.Fatal(err)
+					if have != want {
+						t.Fatalf("have err %v, want %v", have, want)
 					}
+				})
+			})
 
-					have, _, err = s.ListChangesets(ctx, ListChangesetsOpts{WithoutDeleted: true})
+			t.Run("Delete", func(t *testing.T) {
+				for i := range templates {
+					err := s.DeleteCampaignTemplate(ctx, templates[i].ID)
 					if err != nil {
 						t.Fatal(err)
 					}
 
-					if len(have) != 0 {
-						t.Fatalf("have %d changesets. want 0", len(changesets))
-					}
-				}
-
-				// Limit of -1 should return all ChangeSets
-				{
-					have, _, err := s.ListChangesets(ctx, ListChangesetsOpts{Limit: -1})
+					count, err := s.CountCampaignTemplates(ctx, CountCampaignTemplatesOpts{})
 					if err != nil {
 						t.Fatal(err)
 					}
 
-					if len(have) != 3 {
-						t.Fatalf("have %d changesets. want 3", len(have))
+					if have, want := count, int64(len(templates)-(i+1)); have != want {
+						t.Fatalf("have count: %d, want: %d", have, want)
 					}
-				}
-
-				stateOpen := cmpgn.ChangesetStateOpen
-				stateClosed := cmpgn.ChangesetStateClosed
-				stateApproved := cmpgn.ChangesetReviewStateApproved
-				stateChangesRequested := cmpgn.ChangesetReviewStateChangesRequested
-				statePassed := cmpgn.ChangesetCheckStatePassed
-				stateFailed := cmpgn.ChangesetCheckStateFailed
 
-				filterCases := []struct {
-					opts      ListChangesetsOpts
-					wantCount int
-				}{
-					{
-						opts: ListChangesetsOpts{
-							ExternalState: &stateOpen,
-						},
-						wantCount: 3,
-					},
-					{
-						opts: ListChangesetsOpts{
-							ExternalState: &stateClosed,
-						},
-						wantCount: 0,
-					},
-					{
-						opts: ListChangesetsOpts{
-							ExternalReviewState: &stateApproved,
-						},
-						wantCount: 3,
-					},
-					{
-						opts: ListChangesetsOpts{
-							ExternalReviewState: &stateChangesRequested,
-						},
-						wantCount: 0,
-					},
-					{
-						opts: ListChangesetsOpts{
-							ExternalCheckState: &statePassed,
-						},
-						wantCount: 3,
-					},
-					{
-						opts: ListChangesetsOpts{
-							ExternalCheckState: &stateFailed,
-						},
-						wantCount: 0,
-					},
-					{
-						opts: ListChangesetsOpts{
-							ExternalState:      &stateOpen,
-							ExternalCheckState: &stateFailed,
-						},
-						wantCount: 0,
-					},
-					{
-						opts: ListChangesetsOpts{
-							ExternalState:       &stateOpen,
-							ExternalReviewState: &stateChangesRequested,
-						},
-						wantCount: 0,
-					},
+					_, err = s.GetCampaignTemplate(ctx, GetCampaignTemplateOpts{ID: templates[i].ID})
+					if have, want := err, ErrNoResults; have != want {
+						t.Fatalf("have err %v, want %v", have, want)
+					}
 				}
 
-				for _, tc := range filterCases {
-					t.Run("", func(t *testing.T) {
-						have, _, err := s.ListChangesets(ctx, tc.opts)
-						if err != nil {
-							t.Fatal(err)
-						}
-						if len(have) != tc.wantCount {
-							t.Fatalf("have %d changesets. want %d", len(have), tc.wantCount)
-						}
-					})
+				// Versions of deleted templates are kept.
+				_, err := s.GetCampaignTemplateVersion(ctx, GetCampaignTemplateVersionOpts{TemplateID: templates[0].ID, Version: 1})
+				if err != nil {
+					t.Fatal(err)
 				}
 			})
+		})
 
-			t.Run("Null changeset state", func(t *testing.T) {
-				cs := &cmpgn.Changeset{
-					RepoID:              repo.ID,
-					Metadata:            githubPR,
-					CampaignIDs:         []int64{1},
-					ExternalID:          fmt.Sprintf("foobar-%d", 42),
-					ExternalServiceType: "github",
-					ExternalBranch:      "campaigns/test",
-					ExternalUpdatedAt:   now,
-					ExternalState:       "",
-					ExternalReviewState: "",
-					ExternalCheckState:  "",
-				}
+		t.Run("ChangesetImportQueries", func(t *testing.T) {
+			ext := &repos.ExternalService{
+				Kind:        github.ServiceType,
+				DisplayName: "GitHub",
+				Config:      `{"url": "https://github.com", "token": "SECRETTOKEN"}`,
+			}
+			if err := repos.NewDBStore(tx, sql.TxOptions{}).UpsertExternalServices(ctx, ext); err != nil {
+				t.Fatal(err)
+			}
 
-				err := s.CreateChangesets(ctx, cs)
-				if err != nil {
-					t.Fatal(err)
-				}
-				defer func() {
-					err := s.DeleteChangeset(ctx, cs.ID)
-					if err != nil {
-						t.Fatal(err)
-					}
-				}()
+			campaign := &cmpgn.Campaign{
+				Name:            "Import campaign",
+				AuthorID:        1,
+				NamespaceUserID: 1,
+			}
+			if err := s.CreateCampaign(ctx, campaign); err != nil {
+				t.Fatal(err)
+			}
 
-				fromDB, err := s.GetChangeset(ctx, GetChangesetOpts{
-					ID: cs.ID,
-				})
-				if err != nil {
-					t.Fatal(err)
-				}
+			queries := make([]*cmpgn.ChangesetImportQuery, 0, 3)
 
-				if diff := cmp.Diff(cs.ExternalState, fromDB.ExternalState); diff != "" {
-					t.Error(diff)
-				}
-				if diff := cmp.Diff(cs.ExternalReviewState, fromDB.ExternalReviewState); diff != "" {
-					t.Error(diff)
-				}
-				if diff := cmp.Diff(cs.ExternalCheckState, fromDB.ExternalCheckState); diff != "" {
-					t.Error(diff)
-				}
-			})
+			t.Run("Create", func(t *testing.T) {
+				for i := 0; i < cap(queries); i++ {
+					q := &cmpgn.ChangesetImportQuery{
+						CampaignID:        campaign.ID,
+						ExternalServiceID: ext.ID,
+						Query:             fmt.Sprintf("author:renovate label:security-%d", i),
+					}
 
-			t.Run("Get", func(t *testing.T) {
-				t.Run("ByID", func(t *testing.T) {
-					want := changesets[0]
-					opts := GetChangesetOpts{ID: want.ID}
+					want := q.Clone()
+					have := q
 
-					have, err := s.GetChangeset(ctx, opts)
+					err := s.CreateChangesetImportQuery(ctx, have)
 					if err != nil {
 						t.Fatal(err)
 					}
 
-					if diff := cmp.Diff(have, want); diff != "" {
-						t.Fatal(diff)
-					}
-				})
-
-				t.Run("ByExternalID", func(t *testing.T) {
-					want := changesets[0]
-					opts := GetChangesetOpts{
-						ExternalID:          want.ExternalID,
-						ExternalServiceType: want.ExternalServiceType,
+					if have.ID == 0 {
+						t.Fatal("id should not be zero")
 					}
 
-					have, err := s.GetChangeset(ctx, opts)
-					if err != nil {
-						t.Fatal(err)
-					}
+					want.ID = have.ID
+					want.CreatedAt = now
+					want.UpdatedAt = now
 
 					if diff := cmp.Diff(have, want); diff != "" {
 						t.Fatal(diff)
 					}
-				})
 
-				t.Run("ByRepoID", func(t *testing.T) {
-					want := changesets[0]
-					opts := GetChangesetOpts{
-						RepoID: want.RepoID,
-					}
+					queries = append(queries, q)
+				}
 
-					have, err := s.GetChangeset(ctx, opts)
+				err := s.CreateChangesetImportQuery(ctx, queries[0].Clone())
+				if err == nil {
+					t.Fatal("want error creating a duplicate query, have nil")
+				}
+			})
+
+			t.Run("Get", func(t *testing.T) {
+				t.Run("ByID", func(t *testing.T) {
+					want := queries[0]
+					have, err := s.GetChangesetImportQuery(ctx, GetChangesetImportQueryOpts{ID: want.ID})
 					if err != nil {
 						t.Fatal(err)
 					}
@@ -935,9 +777,7 @@ func testStore(db *sql.DB) func(*testing.T) {
 				})
 
 				t.Run("NoResults", func(t *testing.T) {
-					opts := GetChangesetOpts{ID: 0xdeadbeef}
-
-					_, have := s.GetChangeset(ctx, opts)
+					_, have := s.GetChangesetImportQuery(ctx, GetChangesetImportQueryOpts{ID: 0xdeadbeef})
 					want := ErrNoResults
 
 					if have != want {
@@ -947,122 +787,159 @@ func testStore(db *sql.DB) func(*testing.T) {
 			})
 
 			t.Run("Update", func(t *testing.T) {
-				want := make([]*cmpgn.Changeset, 0, len(changesets))
-				have := make([]*cmpgn.Changeset, 0, len(changesets))
-
-				now = now.Add(time.Second)
-				for _, c := range changesets {
-					c.Metadata = &bitbucketserver.PullRequest{ID: 1234}
-					c.ExternalServiceType = bitbucketserver.ServiceType
+				for i, q := range queries[:2] {
+					q.LastImportedAt = now.Add(time.Duration(-i) * time.Hour)
+					q.LastError = fmt.Sprintf("error-%d", i)
 
-					have = append(have, c.Clone())
+					want := q.Clone()
+					if err := s.UpdateChangesetImportQuery(ctx, q); err != nil {
+						t.Fatal(err)
+					}
 
-					c.UpdatedAt = now
-					want = append(want, c)
+					if diff := cmp.Diff(q, want); diff != "" {
+						t.Fatal(diff)
+					}
 				}
+			})
 
-				if err := s.UpdateChangesets(ctx, have...); err != nil {
-					t.Fatal(err)
-				}
-
-				if diff := cmp.Diff(have, want); diff != "" {
-					t.Fatal(diff)
-				}
+			t.Run("List", func(t *testing.T) {
+				t.Run("ByCampaignID", func(t *testing.T) {
+					have, next, err := s.ListChangesetImportQueries(ctx, ListChangesetImportQueriesOpts{CampaignID: campaign.ID})
+					if err != nil {
+						t.Fatal(err)
+					}
 
-				for i := range have {
-					// Test that duplicates are not introduced.
-					have[i].CampaignIDs = append(have[i].CampaignIDs, have[i].CampaignIDs...)
-				}
+					if have, want := next, int64(0); have != want {
+						t.Fatalf("opts: %+v: have next %v, want %v", campaign.ID, have, want)
+					}
 
-				if err := s.UpdateChangesets(ctx, have...); err != nil {
-					t.Fatal(err)
-				}
+					if diff := cmp.Diff(have, queries); diff != "" {
+						t.Fatal(diff)
+					}
+				})
 
-				if diff := cmp.Diff(have, want); diff != "" {
-					t.Fatal(diff)
-				}
+				t.Run("WithLimit", func(t *testing.T) {
+					have, next, err := s.ListChangesetImportQueries(ctx, ListChangesetImportQueriesOpts{ExternalServiceID: ext.ID, Limit: 1})
+					if err != nil {
+						t.Fatal(err)
+					}
 
-				for i := range have {
-					// Test we can add to the set.
-					have[i].CampaignIDs = append(have[i].CampaignIDs, 42)
-					want[i].CampaignIDs = append(want[i].CampaignIDs, 42)
-				}
+					if have, want := next, queries[1].ID; have != want {
+						t.Fatalf("have next %v, want %v", have, want)
+					}
 
-				if err := s.UpdateChangesets(ctx, have...); err != nil {
-					t.Fatal(err)
-				}
+					if diff := cmp.Diff(have, queries[:1]); diff != "" {
+						t.Fatal(diff)
+					}
+				})
 
-				for i := range have {
-					sort.Slice(have[i].CampaignIDs, func(a, b int) bool {
-						return have[i].CampaignIDs[a] < have[i].CampaignIDs[b]
+				t.Run("ImportedBefore", func(t *testing.T) {
+					have, _, err := s.ListChangesetImportQueries(ctx, ListChangesetImportQueriesOpts{
+						ExternalServiceID: ext.ID,
+						ImportedBefore:    now.Add(-30 * time.Minute),
 					})
+					if err != nil {
+						t.Fatal(err)
+					}
 
-					if diff := cmp.Diff(have[i], want[i]); diff != "" {
+					// queries[0] was imported just now, queries[2] never.
+					want := []*cmpgn.ChangesetImportQuery{queries[1], queries[2]}
+					if diff := cmp.Diff(have, want); diff != "" {
 						t.Fatal(diff)
 					}
-				}
-
-				for i := range have {
-					// Test we can remove from the set.
-					have[i].CampaignIDs = have[i].CampaignIDs[:0]
-					want[i].CampaignIDs = want[i].CampaignIDs[:0]
-				}
+				})
+			})
 
-				if err := s.UpdateChangesets(ctx, have...); err != nil {
-					t.Fatal(err)
-				}
+			t.Run("Delete", func(t *testing.T) {
+				for _, q := range queries {
+					if err := s.DeleteChangesetImportQuery(ctx, q.ID); err != nil {
+						t.Fatal(err)
+					}
 
-				if diff := cmp.Diff(have, want); diff != "" {
-					t.Fatal(diff)
+					_, err := s.GetChangesetImportQuery(ctx, GetChangesetImportQueryOpts{ID: q.ID})
+					if have, want := err, ErrNoResults; have != want {
+						t.Fatalf("have err %v, want %v", have, want)
+					}
 				}
 			})
 		})
 
-		t.Run("ChangesetEvents", func(t *testing.T) {
-			events := make([]*cmpgn.ChangesetEvent, 0, 3)
-
-			issueComment := &github.IssueComment{
-				DatabaseID: 443827703,
-				Author: github.Actor{
-					AvatarURL: "https://avatars0.githubusercontent.com/u/1976?v=4",
-					Login:     "sqs",
-					URL:       "https://github.com/sqs",
-				},
-				Editor:              nil,
-				AuthorAssociation:   "MEMBER",
-				Body:                "> Just to be sure: you mean the \"searchFilters\" \"Filters\" should be lowercase, not the \"Search Filters\" from the description, right?\r\n\r\nNo, the prose “Search Filters” should have the F lowercased to fit with our style guide preference for sentence case over title case. (Can’t find this comment on the GitHub mobile interface anymore so quoting the email.)",
-				URL:                 "https://github.com/sourcegraph/sourcegraph/pull/999#issuecomment-443827703",
-				CreatedAt:           now,
-				UpdatedAt:           now,
-				IncludesCreatedEdit: false,
+		t.Run("Changesets", func(t *testing.T) {
+			githubActor := github.Actor{
+				AvatarURL: "https://avatars2.githubusercontent.com/u/1185253",
+				Login:     "mrnugget",
+				URL:       "https://github.com/mrnugget",
+			}
+			githubPR := &github.PullRequest{
+				ID:           "FOOBARID",
+				Title:        "Fix a bunch of bugs",
+				Body:         "This fixes a bunch of bugs",
+				URL:          "https://github.com/sourcegraph/sourcegraph/pull/12345",
+				Number:       12345,
+				Author:       githubActor,
+				Participants: []github.Actor{githubActor},
+				CreatedAt:    now,
+				UpdatedAt:    now,
+				HeadRefName:  "campaigns/test",
 			}
 
-			t.Run("Upsert", func(t *testing.T) {
-				for i := 1; i < cap(events); i++ {
-					e := &cmpgn.ChangesetEvent{
-						ChangesetID: int64(i),
-						Kind:        cmpgn.ChangesetEventKindGitHubCommented,
-						Key:         issueComment.Key(),
-						CreatedAt:   now,
-						Metadata:    issueComment,
+			changesets := make([]*cmpgn.Changeset, 0, 3)
+
+			t.Run("Create", func(t *testing.T) {
+				var i int
+				for i = 0; i < cap(changesets); i++ {
+					th := &cmpgn.Changeset{
+						RepoID:              repo.ID,
+						CreatedAt:           now,
+						UpdatedAt:           now,
+						Metadata:            githubPR,
+						CampaignIDs:         []int64{int64(i) + 1},
+						ExternalID:          fmt.Sprintf("foobar-%d", i),
+						ExternalServiceType: "github",
+						ExternalBranch:      "campaigns/test",
+						ExternalUpdatedAt:   now,
+						ExternalState:       cmpgn.ChangesetStateOpen,
+						ExternalReviewState: cmpgn.ChangesetReviewStateApproved,
+						ExternalCheckState:  cmpgn.ChangesetCheckStatePassed,
+						RebaseState:         cmpgn.ChangesetRebaseStateUpToDate,
+						BaseCommit:          "deadbeef",
 					}
 
-					events = append(events, e)
+					changesets = append(changesets, th)
 				}
 
-				// Verify that no duplicates are introduced and no error is returned.
-				for i := 0; i < 2; i++ {
-					err := s.UpsertChangesetEvents(ctx, events...)
-					if err != nil {
-						t.Fatal(err)
-					}
+				err := s.CreateChangesets(ctx, changesets...)
+				if err != nil {
+					t.Fatal(err)
 				}
 
-				for _, have := range events {
+				err = s.CreateChangesets(ctx, &cmpgn.Changeset{
+					RepoID:              deletedRepo.ID,
+					CreatedAt:           now,
+					UpdatedAt:           now,
+					Metadata:            githubPR,
+					CampaignIDs:         []int64{int64(i) + 1},
+					ExternalID:          fmt.Sprintf("foobar-%d", i),
+					ExternalServiceType: "github",
+					ExternalBranch:      "campaigns/test",
+					ExternalUpdatedAt:   now,
+					ExternalState:       cmpgn.ChangesetStateOpen,
+					ExternalReviewState: cmpgn.ChangesetReviewStateApproved,
+					ExternalCheckState:  cmpgn.ChangesetCheckStatePassed,
+				})
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				for _, have := range changesets {
 					if have.ID == 0 {
 						t.Fatal("id should not be zero")
 					}
 
+					if have.IsDeleted() {
+						t.Fatal("changeset is deleted")
+					}
+
 					want := have.Clone()
 
 					want.ID = have.ID
@@ -1075,242 +952,816 @@ func testStore(db *sql.DB) func(*testing.T) {
 				}
 			})
 
-			t.Run("Count", func(t *testing.T) {
-				count, err := s.CountChangesetEvents(ctx, CountChangesetEventsOpts{})
+			t.Run("GetChangesetExternalIDs", func(t *testing.T) {
+				spec := api.ExternalRepoSpec{
+					ID:          "external-id",
+					ServiceType: "github",
+					ServiceID:   "https://github.com/",
+				}
+				have, err := s.GetChangesetExternalIDs(ctx, spec, []string{"campaigns/test"})
 				if err != nil {
 					t.Fatal(err)
 				}
-
-				if have, want := count, int64(len(events)); have != want {
-					t.Fatalf("have count: %d, want: %d", have, want)
+				want := []string{"foobar-0", "foobar-1", "foobar-2"}
+				if diff := cmp.Diff(want, have); diff != "" {
+					t.Fatal(diff)
 				}
+			})
 
-				count, err = s.CountChangesetEvents(ctx, CountChangesetEventsOpts{ChangesetID: 1})
+			t.Run("GetChangesetExternalIDs no branch", func(t *testing.T) {
+				spec := api.ExternalRepoSpec{
+					ID:          "external-id",
+					ServiceType: "github",
+					ServiceID:   "https://github.com/",
+				}
+				have, err := s.GetChangesetExternalIDs(ctx, spec, []string{"foo"})
 				if err != nil {
 					t.Fatal(err)
 				}
+				want := []string{}
+				if diff := cmp.Diff(want, have); diff != "" {
+					t.Fatal(diff)
+				}
+			})
 
-				if have, want := count, int64(1); have != want {
-					t.Fatalf("have count: %d, want: %d", have, want)
+			t.Run("GetChangesetExternalIDs invalid external-id", func(t *testing.T) {
+				spec := api.ExternalRepoSpec{
+					ID:          "invalid",
+					ServiceType: "github",
+					ServiceID:   "https://github.com/",
+				}
+				have, err := s.GetChangesetExternalIDs(ctx, spec, []string{"campaigns/test"})
+				if err != nil {
+					t.Fatal(err)
+				}
+				want := []string{}
+				if diff := cmp.Diff(want, have); diff != "" {
+					t.Fatal(diff)
 				}
 			})
 
-			t.Run("Get", func(t *testing.T) {
-				t.Run("ByID", func(t *testing.T) {
-					want := events[0]
-					opts := GetChangesetEventOpts{ID: want.ID}
+			t.Run("GetChangesetExternalIDs invalid external service id", func(t *testing.T) {
+				spec := api.ExternalRepoSpec{
+					ID:          "external-id",
+					ServiceType: "github",
+					ServiceID:   "invalid",
+				}
+				have, err := s.GetChangesetExternalIDs(ctx, spec, []string{"campaigns/test"})
+				if err != nil {
+					t.Fatal(err)
+				}
+				want := []string{}
+				if diff := cmp.Diff(want, have); diff != "" {
+					t.Fatal(diff)
+				}
+			})
 
-					have, err := s.GetChangesetEvent(ctx, opts)
-					if err != nil {
-						t.Fatal(err)
-					}
+			t.Run("CreateAlreadyExistingChangesets", func(t *testing.T) {
+				ids := make([]int64, len(changesets))
+				for i, c := range changesets {
+					ids[i] = c.ID
+				}
 
-					if diff := cmp.Diff(have, want); diff != "" {
-						t.Fatal(diff)
-					}
-				})
+				clones := make([]*cmpgn.Changeset, len(changesets))
 
-				t.Run("ByKey", func(t *testing.T) {
-					want := events[0]
-					opts := GetChangesetEventOpts{
-						ChangesetID: want.ChangesetID,
-						Kind:        want.Kind,
-						Key:         want.Key,
+				for i, c := range changesets {
+					// Set only the fields on which we have a unique constraint
+					clones[i] = &cmpgn.Changeset{
+						RepoID:              c.RepoID,
+						ExternalID:          c.ExternalID,
+						ExternalServiceType: c.ExternalServiceType,
 					}
+				}
 
-					have, err := s.GetChangesetEvent(ctx, opts)
-					if err != nil {
-						t.Fatal(err)
+				// Advance clock so store can determine whether Changeset was
+				// inserted or not
+				now = now.Add(time.Second)
+
+				err := s.CreateChangesets(ctx, clones...)
+				ae, ok := err.(AlreadyExistError)
+				if !ok {
+					t.Fatal(err)
+				}
+
+				{
+					sort.Slice(ae.ChangesetIDs, func(i, j int) bool { return ae.ChangesetIDs[i] < ae.ChangesetIDs[j] })
+					sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+					have, want := ae.ChangesetIDs, ids
+					if len(have) != len(want) {
+						t.Fatalf("%d changesets already exist, want: %d", len(have), len(want))
 					}
 
 					if diff := cmp.Diff(have, want); diff != "" {
 						t.Fatal(diff)
 					}
-				})
-
-				t.Run("NoResults", func(t *testing.T) {
-					opts := GetChangesetEventOpts{ID: 0xdeadbeef}
+				}
 
-					_, have := s.GetChangesetEvent(ctx, opts)
-					want := ErrNoResults
+				{
+					// Verify that we got the original changesets back
+					have, want := clones, changesets
+					if len(have) != len(want) {
+						t.Fatalf("created %d changesets, want: %d", len(have), len(want))
+					}
 
-					if have != want {
-						t.Fatalf("have err %v, want %v", have, want)
+					if diff := cmp.Diff(have, want); diff != "" {
+						t.Fatal(diff)
 					}
-				})
+				}
 			})
 
-			t.Run("List", func(t *testing.T) {
-				t.Run("ByChangesetIDs", func(t *testing.T) {
-					for i := 1; i <= len(events); i++ {
-						opts := ListChangesetEventsOpts{ChangesetIDs: []int64{int64(i)}}
+			t.Run("Count", func(t *testing.T) {
+				count, err := s.CountChangesets(ctx, CountChangesetsOpts{})
+				if err != nil {
+					t.Fatal(err)
+				}
 
-						ts, next, err := s.ListChangesetEvents(ctx, opts)
-						if err != nil {
-							t.Fatal(err)
-						}
+				if have, want := count, int64(len(changesets)); have != want {
+					t.Fatalf("have count: %d, want: %d", have, want)
+				}
 
-						if have, want := next, int64(0); have != want {
-							t.Fatalf("opts: %+v: have next %v, want %v", opts, have, want)
-						}
+				count, err = s.CountChangesets(ctx, CountChangesetsOpts{CampaignID: 1})
+				if err != nil {
+					t.Fatal(err)
+				}
 
-						have, want := ts, events[i-1:i]
-						if len(have) != len(want) {
-							t.Fatalf("listed %d events, want: %d", len(have), len(want))
-						}
+				if have, want := count, int64(1); have != want {
+					t.Fatalf("have count: %d, want: %d", have, want)
+				}
+			})
 
-						if diff := cmp.Diff(have, want); diff != "" {
-							t.Fatalf("opts: %+v, diff: %s", opts, diff)
-						}
+			t.Run("List", func(t *testing.T) {
+				for i := 1; i <= len(changesets); i++ {
+					opts := ListChangesetsOpts{CampaignID: int64(i)}
+
+					ts, next, err := s.ListChangesets(ctx, opts)
+					if err != nil {
+						t.Fatal(err)
 					}
 
-					{
-						opts := ListChangesetEventsOpts{ChangesetIDs: []int64{}}
+					if have, want := next, int64(0); have != want {
+						t.Fatalf("opts: %+v: have next %v, want %v", opts, have, want)
+					}
 
-						for i := 1; i <= len(events); i++ {
-							opts.ChangesetIDs = append(opts.ChangesetIDs, int64(i))
-						}
+					have, want := ts, changesets[i-1:i]
+					if len(have) != len(want) {
+						t.Fatalf("listed %d changesets, want: %d", len(have), len(want))
+					}
 
-						ts, next, err := s.ListChangesetEvents(ctx, opts)
-						if err != nil {
-							t.Fatal(err)
-						}
+					if diff := cmp.Diff(have, want); diff != "" {
+						t.Fatalf("opts: %+v, diff: %s", opts, diff)
+					}
+				}
 
-						if have, want := next, int64(0); have != want {
-							t.Fatalf("opts: %+v: have next %v, want %v", opts, have, want)
+				for i := 1; i <= len(changesets); i++ {
+					ts, next, err := s.ListChangesets(ctx, ListChangesetsOpts{Limit: i})
+					if err != nil {
+						t.Fatal(err)
+					}
+
+					{
+						have, want := next, int64(0)
+						if i < len(changesets) {
+							want = changesets[i].ID
 						}
 
-						have, want := ts, events
-						if len(have) != len(want) {
-							t.Fatalf("listed %d events, want: %d", len(have), len(want))
+						if have != want {
+							t.Fatalf("limit: %v: have next %v, want %v", i, have, want)
 						}
 					}
-				})
 
-				t.Run("WithLimit", func(t *testing.T) {
-					for i := 1; i <= len(events); i++ {
-						cs, next, err := s.ListChangesetEvents(ctx, ListChangesetEventsOpts{Limit: i})
-						if err != nil {
-							t.Fatal(err)
+					{
+						have, want := ts, changesets[:i]
+						if len(have) != len(want) {
+							t.Fatalf("listed %d changesets, want: %d", len(have), len(want))
 						}
 
-						{
-							have, want := next, int64(0)
-							if i < len(events) {
-								want = events[i].ID
-							}
-
-							if have != want {
-								t.Fatalf("limit: %v: have next %v, want %v", i, have, want)
-							}
+						if diff := cmp.Diff(have, want); diff != "" {
+							t.Fatal(diff)
 						}
+					}
+				}
 
-						{
-							have, want := cs, events[:i]
-							if len(have) != len(want) {
-								t.Fatalf("listed %d events, want: %d", len(have), len(want))
-							}
+				{
+					ids := make([]int64, len(changesets))
+					for i := range changesets {
+						ids[i] = changesets[i].ID
+					}
 
-							if diff := cmp.Diff(have, want); diff != "" {
-								t.Fatal(diff)
-							}
-						}
+					have, _, err := s.ListChangesets(ctx, ListChangesetsOpts{IDs: ids})
+					if err != nil {
+						t.Fatal(err)
 					}
-				})
 
-				t.Run("WithCursor", func(t *testing.T) {
+					want := changesets
+					if diff := cmp.Diff(have, want); diff != "" {
+						t.Fatal(diff)
+					}
+				}
+
+				{
 					var cursor int64
-					for i := 1; i <= len(events); i++ {
-						opts := ListChangesetEventsOpts{Cursor: cursor, Limit: 1}
-						have, next, err := s.ListChangesetEvents(ctx, opts)
+					for i := 1; i <= len(changesets); i++ {
+						opts := ListChangesetsOpts{Cursor: cursor, Limit: 1}
+						have, next, err := s.ListChangesets(ctx, opts)
 						if err != nil {
 							t.Fatal(err)
 						}
 
-						want := events[i-1 : i]
+						want := changesets[i-1 : i]
 						if diff := cmp.Diff(have, want); diff != "" {
 							t.Fatalf("opts: %+v, diff: %s", opts, diff)
 						}
 
 						cursor = next
 					}
-				})
-
-				t.Run("EmptyResultListingAll", func(t *testing.T) {
-					opts := ListChangesetEventsOpts{ChangesetIDs: []int64{99999}, Limit: -1}
+				}
 
-					ts, next, err := s.ListChangesetEvents(ctx, opts)
+				{
+					have, _, err := s.ListChangesets(ctx, ListChangesetsOpts{WithoutDeleted: true})
 					if err != nil {
 						t.Fatal(err)
 					}
 
-					if have, want := next, int64(0); have != want {
-						t.Fatalf("opts: %+v: have next %v, want %v", opts, have, want)
+					if len(have) != len(changesets) {
+						t.Fatalf("have 0 changesets. want %d", len(changesets))
 					}
 
-					if len(ts) != 0 {
-						t.Fatalf("listed %d events, want: %d", len(ts), 0)
+					for _, c := range changesets {
+						c.SetDeleted()
+						c.UpdatedAt = now
 					}
-				})
-			})
-		})
-
-		t.Run("ListChangesetSyncData", func(t *testing.T) {
-			changesets, _, err := s.ListChangesets(ctx, ListChangesetsOpts{
-				Limit: -1,
-			})
-			if err != nil {
-				t.Fatal(err)
-			}
 
-			// A previous test decreases the repoID, set it back
-			for i := range changesets {
-				changesets[i].RepoID = repo.ID
-			}
+					if err := s.UpdateChangesets(ctx, changesets...); err != nil {
+						t.Fatal(err)
+					}
 
-			err = s.UpdateChangesets(ctx, changesets...)
-			if err != nil {
-				t.Fatal(err)
-			}
+					have, _, err = s.ListChangesets(ctx, ListChangesetsOpts{WithoutDeleted: true})
+					if err != nil {
+						t.Fatal(err)
+					}
 
-			// We need campaigns attached to each changeset
-			for i, cs := range changesets {
-				c := &cmpgn.Campaign{
-					Name:           fmt.Sprintf("ListChangesetSyncData test"),
-					ChangesetIDs:   []int64{cs.ID},
-					NamespaceOrgID: 23,
-				}
-				err := s.CreateCampaign(ctx, c)
-				if err != nil {
-					t.Fatal(err)
-				}
-				changesets[i].CampaignIDs = []int64{c.ID}
-				if err := s.UpdateChangesets(ctx, changesets[i]); err != nil {
-					t.Fatal(err)
+					if len(have) != 0 {
+						t.Fatalf("have %d changesets. want 0", len(changesets))
+					}
 				}
-			}
 
-			// Differs from clock() due to updates higher up
-			externalUpdatedAt := clock().Add(-2 * time.Second)
-			hs, err := s.ListChangesetSyncData(ctx, ListChangesetSyncDataOpts{})
-			if err != nil {
-				t.Fatal(err)
-			}
-			want := []cmpgn.ChangesetSyncData{
-				{
-					ChangesetID:        1,
-					UpdatedAt:          clock(),
-					LatestEvent:        clock(),
-					ExternalUpdatedAt:  externalUpdatedAt,
-					ExternalServiceIDs: []int64{4},
-				},
-				{
-					ChangesetID:        2,
-					UpdatedAt:          clock(),
-					LatestEvent:        clock(),
-					ExternalUpdatedAt:  externalUpdatedAt,
-					ExternalServiceIDs: []int64{4},
-				},
+				// Limit of -1 should return all ChangeSets
+				{
+					have, _, err := s.ListChangesets(ctx, ListChangesetsOpts{Limit: -1})
+					if err != nil {
+						t.Fatal(err)
+					}
+
+					if len(have) != 3 {
+						t.Fatalf("have %d changesets. want 3", len(have))
+					}
+				}
+
+				stateOpen := cmpgn.ChangesetStateOpen
+				stateClosed := cmpgn.ChangesetStateClosed
+				stateApproved := cmpgn.ChangesetReviewStateApproved
+				stateChangesRequested := cmpgn.ChangesetReviewStateChangesRequested
+				statePassed := cmpgn.ChangesetCheckStatePassed
+				stateFailed := cmpgn.ChangesetCheckStateFailed
+
+				filterCases := []struct {
+					opts      ListChangesetsOpts
+					wantCount int
+				}{
+					{
+						opts: ListChangesetsOpts{
+							ExternalState: &stateOpen,
+						},
+						wantCount: 3,
+					},
+					{
+						opts: ListChangesetsOpts{
+							ExternalState: &stateClosed,
+						},
+						wantCount: 0,
+					},
+					{
+						opts: ListChangesetsOpts{
+							ExternalReviewState: &stateApproved,
+						},
+						wantCount: 3,
+					},
+					{
+						opts: ListChangesetsOpts{
+							ExternalReviewState: &stateChangesRequested,
+						},
+						wantCount: 0,
+					},
+					{
+						opts: ListChangesetsOpts{
+							ExternalCheckState: &statePassed,
+						},
+						wantCount: 3,
+					},
+					{
+						opts: ListChangesetsOpts{
+							ExternalCheckState: &stateFailed,
+						},
+						wantCount: 0,
+					},
+					{
+						opts: ListChangesetsOpts{
+							ExternalState:      &stateOpen,
+							ExternalCheckState: &stateFailed,
+						},
+						wantCount: 0,
+					},
+					{
+						opts: ListChangesetsOpts{
+							ExternalState:       &stateOpen,
+							ExternalReviewState: &stateChangesRequested,
+						},
+						wantCount: 0,
+					},
+				}
+
+				for _, tc := range filterCases {
+					t.Run("", func(t *testing.T) {
+						have, _, err := s.ListChangesets(ctx, tc.opts)
+						if err != nil {
+							t.Fatal(err)
+						}
+						if len(have) != tc.wantCount {
+							t.Fatalf("have %d changesets. want %d", len(have), tc.wantCount)
+						}
+					})
+				}
+			})
+
+			t.Run("Null changeset state", func(t *testing.T) {
+				cs := &cmpgn.Changeset{
+					RepoID:              repo.ID,
+					Metadata:            githubPR,
+					CampaignIDs:         []int64{1},
+					ExternalID:          fmt.Sprintf("foobar-%d", 42),
+					ExternalServiceType: "github",
+					ExternalBranch:      "campaigns/test",
+					ExternalUpdatedAt:   now,
+					ExternalState:       "",
+					ExternalReviewState: "",
+					ExternalCheckState:  "",
+				}
+
+				err := s.CreateChangesets(ctx, cs)
+				if err != nil {
+					t.Fatal(err)
+				}
+				defer func() {
+					err := s.DeleteChangeset(ctx, cs.ID)
+					if err != nil {
+						t.Fatal(err)
+					}
+				}()
+
+				fromDB, err := s.GetChangeset(ctx, GetChangesetOpts{
+					ID: cs.ID,
+				})
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if diff := cmp.Diff(cs.ExternalState, fromDB.ExternalState); diff != "" {
+					t.Error(diff)
+				}
+				if diff := cmp.Diff(cs.ExternalReviewState, fromDB.ExternalReviewState); diff != "" {
+					t.Error(diff)
+				}
+				if diff := cmp.Diff(cs.ExternalCheckState, fromDB.ExternalCheckState); diff != "" {
+					t.Error(diff)
+				}
+			})
+
+			t.Run("Get", func(t *testing.T) {
+				t.Run("ByID", func(t *testing.T) {
+					want := changesets[0]
+					opts := GetChangesetOpts{ID: want.ID}
+
+					have, err := s.GetChangeset(ctx, opts)
+					if err != nil {
+						t.Fatal(err)
+					}
+
+					if diff := cmp.Diff(have, want); diff != "" {
+						t.Fatal(diff)
+					}
+				})
+
+				t.Run("ByExternalID", func(t *testing.T) {
+					want := changesets[0]
+					opts := GetChangesetOpts{
+						ExternalID:          want.ExternalID,
+						ExternalServiceType: want.ExternalServiceType,
+					}
+
+					have, err := s.GetChangeset(ctx, opts)
+					if err != nil {
+						t.Fatal(err)
+					}
+
+					if diff := cmp.Diff(have, want); diff != "" {
+						t.Fatal(diff)
+					}
+				})
+
+				t.Run("ByRepoID", func(t *testing.T) {
+					want := changesets[0]
+					opts := GetChangesetOpts{
+						RepoID: want.RepoID,
+					}
+
+					have, err := s.GetChangeset(ctx, opts)
+					if err != nil {
+						t.Fatal(err)
+					}
+
+					if diff := cmp.Diff(have, want); diff != "" {
+						t.Fatal(diff)
+					}
+				})
+
+				t.Run("NoResults", func(t *testing.T) {
+					opts := GetChangesetOpts{ID: 0xdeadbeef}
+
+					_, have := s.GetChangeset(ctx, opts)
+					want := ErrNoResults
+
+					if have != want {
+						t.Fatalf("have err %v, want %v", have, want)
+					}
+				})
+			})
+
+			t.Run("Update", func(t *testing.T) {
+				want := make([]*cmpgn.Changeset, 0, len(changesets))
+				have := make([]*cmpgn.Changeset, 0, len(changesets))
+
+				now = now.Add(time.Second)
+				for _, c := range changesets {
+					c.Metadata = &bitbucketserver.PullRequest{ID: 1234}
+					c.ExternalServiceType = bitbucketserver.ServiceType
+
+					have = append(have, c.Clone())
+
+					c.UpdatedAt = now
+					want = append(want, c)
+				}
+
+				if err := s.UpdateChangesets(ctx, have...); err != nil {
+					t.Fatal(err)
+				}
+
+				if diff := cmp.Diff(have, want); diff != "" {
+					t.Fatal(diff)
+				}
+
+				for i := range have {
+					// Test that duplicates are not introduced.
+					have[i].CampaignIDs = append(have[i].CampaignIDs, have[i].CampaignIDs...)
+				}
+
+				if err := s.UpdateChangesets(ctx, have...); err != nil {
+					t.Fatal(err)
+				}
+
+				if diff := cmp.Diff(have, want); diff != "" {
+					t.Fatal(diff)
+				}
+
+				for i := range have {
+					// Test we can add to the set.
+					have[i].CampaignIDs = append(have[i].CampaignIDs, 42)
+					want[i].CampaignIDs = append(want[i].CampaignIDs, 42)
+				}
+
+				if err := s.UpdateChangesets(ctx, have...); err != nil {
+					t.Fatal(err)
+				}
+
+				for i := range have {
+					sort.Slice(have[i].CampaignIDs, func(a, b int) bool {
+						return have[i].CampaignIDs[a] < have[i].CampaignIDs[b]
+					})
+
+					if diff := cmp.Diff(have[i], want[i]); diff != "" {
+						t.Fatal(diff)
+					}
+				}
+
+				for i := range have {
+					// Test we can remove from the set.
+					have[i].CampaignIDs = have[i].CampaignIDs[:0]
+					want[i].CampaignIDs = want[i].CampaignIDs[:0]
+				}
+
+				if err := s.UpdateChangesets(ctx, have...); err != nil {
+					t.Fatal(err)
+				}
+
+				if diff := cmp.Diff(have, want); diff != "" {
+					t.Fatal(diff)
+				}
+			})
+		})
+
+		t.Run("ChangesetEvents", func(t *testing.T) {
+			events := make([]*cmpgn.ChangesetEvent, 0, 3)
+
+			issueComment := &github.IssueComment{
+				DatabaseID: 443827703,
+				Author: github.Actor{
+					AvatarURL: "https://avatars0.githubusercontent.com/u/1976?v=4",
+					Login:     "sqs",
+					URL:       "https://github.com/sqs",
+				},
+				Editor:              nil,
+				AuthorAssociation:   "MEMBER",
+				Body:                "> Just to be sure: you mean the \"searchFilters\" \"Filters\" should be lowercase, not the \"Search Filters\" from the description, right?\r\n\r\nNo, the prose “Search Filters” should have the F lowercased to fit with our style guide preference for sentence case over title case. (Can’t find this comment on the GitHub mobile interface anymore so quoting the email.)",
+				URL:                 "https://github.com/sourcegraph/sourcegraph/pull/999#issuecomment-443827703",
+				CreatedAt:           now,
+				UpdatedAt:           now,
+				IncludesCreatedEdit: false,
+			}
+
+			t.Run("Upsert", func(t *testing.T) {
+				for i := 1; i < cap(events); i++ {
+					e := &cmpgn.ChangesetEvent{
+						ChangesetID: int64(i),
+						Kind:        cmpgn.ChangesetEventKindGitHubCommented,
+						Key:         issueComment.Key(),
+						CreatedAt:   now,
+						Metadata:    issueComment,
+					}
+
+					events = append(events, e)
+				}
+
+				// Verify that no duplicates are introduced and no error is returned.
+				for i := 0; i < 2; i++ {
+					err := s.UpsertChangesetEvents(ctx, events...)
+					if err != nil {
+						t.Fatal(err)
+					}
+				}
+
+				for _, have := range events {
+					if have.ID == 0 {
+						t.Fatal("id should not be zero")
+					}
+
+					want := have.Clone()
+
+					want.ID = have.ID
+					want.CreatedAt = now
+					want.UpdatedAt = now
+
+					if diff := cmp.Diff(have, want); diff != "" {
+						t.Fatal(diff)
+					}
+				}
+			})
+
+			t.Run("Count", func(t *testing.T) {
+				count, err := s.CountChangesetEvents(ctx, CountChangesetEventsOpts{})
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if have, want := count, int64(len(events)); have != want {
+					t.Fatalf("have count: %d, want: %d", have, want)
+				}
+
+				count, err = s.CountChangesetEvents(ctx, CountChangesetEventsOpts{ChangesetID: 1})
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if have, want := count, int64(1); have != want {
+					t.Fatalf("have count: %d, want: %d", have, want)
+				}
+			})
+
+			t.Run("Get", func(t *testing.T) {
+				t.Run("ByID", func(t *testing.T) {
+					want := events[0]
+					opts := GetChangesetEventOpts{ID: want.ID}
+
+					have, err := s.GetChangesetEvent(ctx, opts)
+					if err != nil {
+						t.Fatal(err)
+					}
+
+					if diff := cmp.Diff(have, want); diff != "" {
+						t.Fatal(diff)
+					}
+				})
+
+				t.Run("ByKey", func(t *testing.T) {
+					want := events[0]
+					opts := GetChangesetEventOpts{
+						ChangesetID: want.ChangesetID,
+						Kind:        want.Kind,
+						Key:         want.Key,
+					}
+
+					have, err := s.GetChangesetEvent(ctx, opts)
+					if err != nil {
+						t.Fatal(err)
+					}
+
+					if diff := cmp.Diff(have, want); diff != "" {
+						t.Fatal(diff)
+					}
+				})
+
+				t.Run("NoResults", func(t *testing.T) {
+					opts := GetChangesetEventOpts{ID: 0xdeadbeef}
+
+					_, have := s.GetChangesetEvent(ctx, opts)
+					want := ErrNoResults
+
+					if have != want {
+						t.Fatalf("have err %v, want %v", have, want)
+					}
+				})
+			})
+
+			t.Run("List", func(t *testing.T) {
+				t.Run("ByChangesetIDs", func(t *testing.T) {
+					for i := 1; i <= len(events); i++ {
+						opts := ListChangesetEventsOpts{ChangesetIDs: []int64{int64(i)}}
+
+						ts, next, err := s.ListChangesetEvents(ctx, opts)
+						if err != nil {
+							t.Fatal(err)
+						}
+
+						if have, want := next, int64(0); have != want {
+							t.Fatalf("opts: %+v: have next %v, want %v", opts, have, want)
+						}
+
+						have, want := ts, events[i-1:i]
+						if len(have) != len(want) {
+							t.Fatalf("listed %d events, want: %d", len(have), len(want))
+						}
+
+						if diff := cmp.Diff(have, want); diff != "" {
+							t.Fatalf("opts: %+v, diff: %s", opts, diff)
+						}
+					}
+
+					{
+						opts := ListChangesetEventsOpts{ChangesetIDs: []int64{}}
+
+						for i := 1; i <= len(events); i++ {
+							opts.ChangesetIDs = append(opts.ChangesetIDs, int64(i))
+						}
+
+						ts, next, err := s.ListChangesetEvents(ctx, opts)
+						if err != nil {
+							t.Fatal(err)
+						}
+
+						if have, want := next, int64(0); have != want {
+							t.Fatalf("opts: %+v: have next %v, want %v", opts, have, want)
+						}
+
+						have, want := ts, events
+						if len(have) != len(want) {
+							t.Fatalf("listed %d events, want: %d", len(have), len(want))
+						}
+					}
+				})
+
+				t.Run("WithLimit", func(t *testing.T) {
+					for i := 1; i <= len(events); i++ {
+						cs, next, err := s.ListChangesetEvents(ctx, ListChangesetEventsOpts{Limit: i})
+						if err != nil {
+							t.Fatal(err)
+						}
+
+						{
+							have, want := next, int64(0)
+							if i < len(events) {
+								want = events[i].ID
+							}
+
+							if have != want {
+								t.Fatalf("limit: %v: have next %v, want %v", i, have, want)
+							}
+						}
+
+						{
+							have, want := cs, events[:i]
+							if len(have) != len(want) {
+								t.Fatalf("listed %d events, want: %d", len(have), len(want))
+							}
+
+							if diff := cmp.Diff(have, want); diff != "" {
+								t.Fatal(diff)
+							}
+						}
+					}
+				})
+
+				t.Run("WithCursor", func(t *testing.T) {
+					var cursor int64
+					for i := 1; i <= len(events); i++ {
+						opts := ListChangesetEventsOpts{Cursor: cursor, Limit: 1}
+						have, next, err := s.ListChangesetEvents(ctx, opts)
+						if err != nil {
+							t.Fatal(err)
+						}
+
+						want := events[i-1 : i]
+						if diff := cmp.Diff(have, want); diff != "" {
+							t.Fatalf("opts: %+v, diff: %s", opts, diff)
+						}
+
+						cursor = next
+					}
+				})
+
+				t.Run("EmptyResultListingAll", func(t *testing.T) {
+					opts := ListChangesetEventsOpts{ChangesetIDs: []int64{99999}, Limit: -1}
+
+					ts, next, err := s.ListChangesetEvents(ctx, opts)
+					if err != nil {
+						t.Fatal(err)
+					}
+
+					if have, want := next, int64(0); have != want {
+						t.Fatalf("opts: %+v: have next %v, want %v", opts, have, want)
+					}
+
+					if len(ts) != 0 {
+						t.Fatalf("listed %d events, want: %d", len(ts), 0)
+					}
+				})
+			})
+		})
+
+		t.Run("ListChangesetSyncData", func(t *testing.T) {
+			changesets, _, err := s.ListChangesets(ctx, ListChangesetsOpts{
+				Limit: -1,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// A previous test decreases the repoID, set it back
+			for i := range changesets {
+				changesets[i].RepoID = repo.ID
+			}
+
+			err = s.UpdateChangesets(ctx, changesets...)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// We need campaigns attached to each changeset
+			for i, cs := range changesets {
+				c := &cmpgn.Campaign{
+					Name:           fmt.Sprintf("ListChangesetSyncData test"),
+					ChangesetIDs:   []int64{cs.ID},
+					NamespaceOrgID: 23,
+				}
+				err := s.CreateCampaign(ctx, c)
+				if err != nil {
+					t.Fatal(err)
+				}
+				changesets[i].CampaignIDs = []int64{c.ID}
+				if err := s.UpdateChangesets(ctx, changesets[i]); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			// Differs from clock() due to updates higher up
+			externalUpdatedAt := clock().Add(-2 * time.Second)
+			hs, err := s.ListChangesetSyncData(ctx, ListChangesetSyncDataOpts{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := []cmpgn.ChangesetSyncData{
+				{
+					ChangesetID:        1,
+					UpdatedAt:          clock(),
+					LatestEvent:        clock(),
+					ExternalUpdatedAt:  externalUpdatedAt,
+					ExternalServiceIDs: []int64{4},
+				},
+				{
+					ChangesetID:        2,
+					UpdatedAt:          clock(),
+					LatestEvent:        clock(),
+					ExternalUpdatedAt:  externalUpdatedAt,
+					ExternalServiceIDs: []int64{4},
+				},
 				{
 					// No events
 					ChangesetID:        3,
@@ -1434,17 +1885,211 @@ func testStore(db *sql.DB) func(*testing.T) {
 			}
 		})
 
-		t.Run("PatchSets", func(t *testing.T) {
-			patchSets := make([]*cmpgn.PatchSet, 0, 3)
+		t.Run("PatchSets", func(t *testing.T) {
+			patchSets := make([]*cmpgn.PatchSet, 0, 3)
+
+			t.Run("Create", func(t *testing.T) {
+				for i := 0; i < cap(patchSets); i++ {
+					c := &cmpgn.PatchSet{UserID: 999}
+					if i == 0 {
+						c.Replacement = &cmpgn.Replacement{
+							Query:         "repo:sourcegraph lang:go",
+							Kind:          cmpgn.ReplacementKindRegexp,
+							Match:         `fmt\.Errorf\(`,
+							Rewrite:       "errors.Errorf(",
+							FileExtension: ".go",
+						}
+					}
+
+					want := c.Clone()
+					have := c
+
+					err := s.CreatePatchSet(ctx, have)
+					if err != nil {
+						t.Fatal(err)
+					}
+
+					if have.ID == 0 {
+						t.Fatal("ID should not be zero")
+					}
+
+					want.ID = have.ID
+					want.CreatedAt = now
+					want.UpdatedAt = now
+
+					if diff := cmp.Diff(have, want); diff != "" {
+						t.Fatal(diff)
+					}
+
+					patchSets = append(patchSets, c)
+				}
+			})
+
+			t.Run("Count", func(t *testing.T) {
+				count, err := s.CountPatchSets(ctx)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if have, want := count, int64(len(patchSets)); have != want {
+					t.Fatalf("have count: %d, want: %d", have, want)
+				}
+			})
+
+			t.Run("List", func(t *testing.T) {
+				opts := ListPatchSetsOpts{}
+
+				ts, next, err := s.ListPatchSets(ctx, opts)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if have, want := next, int64(0); have != want {
+					t.Fatalf("opts: %+v: have next %v, want %v", opts, have, want)
+				}
+
+				have, want := ts, patchSets
+				if len(have) != len(want) {
+					t.Fatalf("listed %d patchSets, want: %d", len(have), len(want))
+				}
+
+				if diff := cmp.Diff(have, want); diff != "" {
+					t.Fatalf("opts: %+v, diff: %s", opts, diff)
+				}
+
+				for i := 1; i <= len(patchSets); i++ {
+					cs, next, err := s.ListPatchSets(ctx, ListPatchSetsOpts{Limit: i})
+					if err != nil {
+						t.Fatal(err)
+					}
+
+					{
+						have, want := next, int64(0)
+						if i < len(patchSets) {
+							want = patchSets[i].ID
+						}
+
+						if have != want {
+							t.Fatalf("limit: %v: have next %v, want %v", i, have, want)
+						}
+					}
+
+					{
+						have, want := cs, patchSets[:i]
+						if len(have) != len(want) {
+							t.Fatalf("listed %d patchSets, want: %d", len(have), len(want))
+						}
+
+						if diff := cmp.Diff(have, want); diff != "" {
+							t.Fatal(diff)
+						}
+					}
+				}
+
+				{
+					var cursor int64
+					for i := 1; i <= len(patchSets); i++ {
+						opts := ListPatchSetsOpts{Cursor: cursor, Limit: 1}
+						have, next, err := s.ListPatchSets(ctx, opts)
+						if err != nil {
+							t.Fatal(err)
+						}
+
+						want := patchSets[i-1 : i]
+						if diff := cmp.Diff(have, want); diff != "" {
+							t.Fatalf("opts: %+v, diff: %s", opts, diff)
+						}
+
+						cursor = next
+					}
+				}
+			})
+
+			t.Run("Update", func(t *testing.T) {
+				for _, c := range patchSets {
+					c.UserID += 1234
+
+					now = now.Add(time.Second)
+					want := c
+					want.UpdatedAt = now
+
+					have := c.Clone()
+					if err := s.UpdatePatchSet(ctx, have); err != nil {
+						t.Fatal(err)
+					}
+
+					if diff := cmp.Diff(have, want); diff != "" {
+						t.Fatal(diff)
+					}
+				}
+			})
+
+			t.Run("Get", func(t *testing.T) {
+				t.Run("ByID", func(t *testing.T) {
+					if len(patchSets) == 0 {
+						t.Fatalf("patchSets is empty")
+					}
+					want := patchSets[0]
+					opts := GetPatchSetOpts{ID: want.ID}
+
+					have, err := s.GetPatchSet(ctx, opts)
+					if err != nil {
+						t.Fatal(err)
+					}
+
+					if diff := cmp.Diff(have, want); diff != "" {
+						t.Fatal(diff)
+					}
+				})
+
+				t.Run("NoResults", func(t *testing.T) {
+					opts := GetPatchSetOpts{ID: 0xdeadbeef}
+
+					_, have := s.GetPatchSet(ctx, opts)
+					want := ErrNoResults
+
+					if have != want {
+						t.Fatalf("have err %v, want %v", have, want)
+					}
+				})
+			})
+
+			t.Run("Delete", func(t *testing.T) {
+				for i := range patchSets {
+					err := s.DeletePatchSet(ctx, patchSets[i].ID)
+					if err != nil {
+						t.Fatal(err)
+					}
+
+					count, err := s.CountPatchSets(ctx)
+					if err != nil {
+						t.Fatal(err)
+					}
+
+					if have, want := count, int64(len(patchSets)-(i+1)); have != want {
+						t.Fatalf("have count: %d, want: %d", have, want)
+					}
+				}
+			})
+		})
+
+		t.Run("Patches", func(t *testing.T) {
+			patches := make([]*cmpgn.Patch, 0, 3)
 
 			t.Run("Create", func(t *testing.T) {
-				for i := 0; i < cap(patchSets); i++ {
-					c := &cmpgn.PatchSet{UserID: 999}
+				for i := 0; i < cap(patches); i++ {
+					p := &cmpgn.Patch{
+						PatchSetID: int64(i + 1),
+						RepoID:     1,
+						Rev:        api.CommitID("deadbeef"),
+						BaseRef:    "master",
+						Diff:       "+ foobar - barfoo",
+					}
 
-					want := c.Clone()
-					have := c
+					want := p.Clone()
+					have := p
 
-					err := s.CreatePatchSet(ctx, have)
+					err := s.CreatePatch(ctx, have)
 					if err != nil {
 						t.Fatal(err)
 					}
@@ -1461,100 +2106,280 @@ func testStore(db *sql.DB) func(*testing.T) {
 						t.Fatal(diff)
 					}
 
-					patchSets = append(patchSets, c)
+					patches = append(patches, p)
+				}
+			})
+
+			t.Run("Count", func(t *testing.T) {
+				count, err := s.CountPatches(ctx, CountPatchesOpts{})
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if have, want := count, int64(len(patches)); have != want {
+					t.Fatalf("have count: %d, want: %d", have, want)
+				}
+
+				count, err = s.CountPatches(ctx, CountPatchesOpts{PatchSetID: 1})
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if have, want := count, int64(1); have != want {
+					t.Fatalf("have count: %d, want: %d", have, want)
+				}
+			})
+
+			t.Run("List", func(t *testing.T) {
+				t.Run("WithPatchSetID", func(t *testing.T) {
+					for i := 1; i <= len(patches); i++ {
+						opts := ListPatchesOpts{PatchSetID: int64(i)}
+
+						ts, next, err := s.ListPatches(ctx, opts)
+						if err != nil {
+							t.Fatal(err)
+						}
+
+						if have, want := next, int64(0); have != want {
+							t.Fatalf("opts: %+v: have next %v, want %v", opts, have, want)
+						}
+
+						have, want := ts, patches[i-1:i]
+						if len(have) != len(want) {
+							t.Fatalf("listed %d patches, want: %d", len(have), len(want))
+						}
+
+						if diff := cmp.Diff(have, want); diff != "" {
+							t.Fatalf("opts: %+v, diff: %s", opts, diff)
+						}
+					}
+				})
+
+				t.Run("WithPositiveLimit", func(t *testing.T) {
+					for i := 1; i <= len(patches); i++ {
+						cs, next, err := s.ListPatches(ctx, ListPatchesOpts{Limit: i})
+						if err != nil {
+							t.Fatal(err)
+						}
+
+						{
+							have, want := next, int64(0)
+							if i < len(patches) {
+								want = patches[i].ID
+							}
+
+							if have != want {
+								t.Fatalf("limit: %v: have next %v, want %v", i, have, want)
+							}
+						}
+
+						{
+							have, want := cs, patches[:i]
+							if len(have) != len(want) {
+								t.Fatalf("listed %d patches, want: %d", len(have), len(want))
+							}
+
+							if diff := cmp.Diff(have, want); diff != "" {
+								t.Fatal(diff)
+							}
+						}
+					}
+				})
+
+				t.Run("WithNegativeLimitToListAll", func(t *testing.T) {
+					cs, next, err := s.ListPatches(ctx, ListPatchesOpts{Limit: -1})
+					if err != nil {
+						t.Fatal(err)
+					}
+
+					if have, want := next, int64(0); have != want {
+						t.Fatalf("have next %v, want %v", have, want)
+					}
+
+					have, want := cs, patches
+					if len(have) != len(want) {
+						t.Fatalf("listed %d patches, want: %d", len(have), len(want))
+					}
+
+					if diff := cmp.Diff(have, want); diff != "" {
+						t.Fatal(diff)
+					}
+				})
+
+				t.Run("EmptyResultListingAll", func(t *testing.T) {
+					opts := ListPatchesOpts{PatchSetID: 99999, Limit: -1}
+
+					js, next, err := s.ListPatches(ctx, opts)
+					if err != nil {
+						t.Fatal(err)
+					}
+
+					if have, want := next, int64(0); have != want {
+						t.Fatalf("opts: %+v: have next %v, want %v", opts, have, want)
+					}
+
+					if len(js) != 0 {
+						t.Fatalf("listed %d jobs, want: %d", len(js), 0)
+					}
+				})
+
+				t.Run("WithCursor", func(t *testing.T) {
+					{
+						var cursor int64
+						for i := 1; i <= len(patches); i++ {
+							opts := ListPatchesOpts{Cursor: cursor, Limit: 1}
+							have, next, err := s.ListPatches(ctx, opts)
+							if err != nil {
+								t.Fatal(err)
+							}
+
+							want := patches[i-1 : i]
+							if diff := cmp.Diff(have, want); diff != "" {
+								t.Fatalf("opts: %+v, diff: %s", opts, diff)
+							}
+
+							cursor = next
+						}
+					}
+				})
+			})
+
+			t.Run("Listing and Counting OnlyWithDiff", func(t *testing.T) {
+				listOpts := ListPatchesOpts{OnlyWithDiff: true}
+				countOpts := CountPatchesOpts{OnlyWithDiff: true}
+
+				have, _, err := s.ListPatches(ctx, listOpts)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				have, want := have, patches
+				if len(have) != len(want) {
+					t.Fatalf("listed %d patches, want: %d", len(have), len(want))
+				}
+
+				if diff := cmp.Diff(have, want); diff != "" {
+					t.Fatalf("opts: %+v, diff: %s", listOpts, diff)
+				}
+
+				count, err := s.CountPatches(ctx, countOpts)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if int(count) != len(want) {
+					t.Errorf("jobs counted: %d", count)
+				}
+
+				for _, p := range patches {
+					p.Diff = ""
+
+					err := s.UpdatePatch(ctx, p)
+					if err != nil {
+						t.Fatal(err)
+					}
+				}
+
+				have, _, err = s.ListPatches(ctx, listOpts)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if len(have) != 0 {
+					t.Errorf("jobs returned: %d", len(have))
+				}
+
+				count, err = s.CountPatches(ctx, countOpts)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if count != 0 {
+					t.Errorf("jobs counted: %d", count)
+				}
+			})
+
+			t.Run("Listing and Counting OnlyUnpublishedInCampaign", func(t *testing.T) {
+				campaignID := int64(999)
+				changesetJob := &cmpgn.ChangesetJob{
+					PatchID:     patches[0].ID,
+					CampaignID:  campaignID,
+					ChangesetID: 789,
+					StartedAt:   now,
+					FinishedAt:  now,
+				}
+				err := s.CreateChangesetJob(ctx, changesetJob)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				listOpts := ListPatchesOpts{OnlyUnpublishedInCampaign: campaignID}
+				countOpts := CountPatchesOpts{OnlyUnpublishedInCampaign: campaignID}
+
+				have, _, err := s.ListPatches(ctx, listOpts)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				have, want := have, patches[1:] // Except patches[0]
+				if len(have) != len(want) {
+					t.Fatalf("listed %d patches, want: %d", len(have), len(want))
+				}
+
+				if diff := cmp.Diff(have, want); diff != "" {
+					t.Fatalf("opts: %+v, diff: %s", listOpts, diff)
 				}
-			})
 
-			t.Run("Count", func(t *testing.T) {
-				count, err := s.CountPatchSets(ctx)
+				count, err := s.CountPatches(ctx, countOpts)
 				if err != nil {
 					t.Fatal(err)
 				}
 
-				if have, want := count, int64(len(patchSets)); have != want {
-					t.Fatalf("have count: %d, want: %d", have, want)
+				if int(count) != len(want) {
+					t.Errorf("jobs counted: %d", count)
 				}
-			})
 
-			t.Run("List", func(t *testing.T) {
-				opts := ListPatchSetsOpts{}
-
-				ts, next, err := s.ListPatchSets(ctx, opts)
+				// Update ChangesetJob so condition does not apply
+				changesetJob.ChangesetID = 0
+				err = s.UpdateChangesetJob(ctx, changesetJob)
 				if err != nil {
 					t.Fatal(err)
 				}
 
-				if have, want := next, int64(0); have != want {
-					t.Fatalf("opts: %+v: have next %v, want %v", opts, have, want)
+				have, _, err = s.ListPatches(ctx, listOpts)
+				if err != nil {
+					t.Fatal(err)
 				}
 
-				have, want := ts, patchSets
+				want = patches // All Patches
 				if len(have) != len(want) {
-					t.Fatalf("listed %d patchSets, want: %d", len(have), len(want))
+					t.Fatalf("listed %d patches, want: %d", len(have), len(want))
 				}
 
 				if diff := cmp.Diff(have, want); diff != "" {
-					t.Fatalf("opts: %+v, diff: %s", opts, diff)
+					t.Fatalf("opts: %+v, diff: %s", listOpts, diff)
 				}
 
-				for i := 1; i <= len(patchSets); i++ {
-					cs, next, err := s.ListPatchSets(ctx, ListPatchSetsOpts{Limit: i})
-					if err != nil {
-						t.Fatal(err)
-					}
-
-					{
-						have, want := next, int64(0)
-						if i < len(patchSets) {
-							want = patchSets[i].ID
-						}
-
-						if have != want {
-							t.Fatalf("limit: %v: have next %v, want %v", i, have, want)
-						}
-					}
-
-					{
-						have, want := cs, patchSets[:i]
-						if len(have) != len(want) {
-							t.Fatalf("listed %d patchSets, want: %d", len(have), len(want))
-						}
-
-						if diff := cmp.Diff(have, want); diff != "" {
-							t.Fatal(diff)
-						}
-					}
+				count, err = s.CountPatches(ctx, countOpts)
+				if err != nil {
+					t.Fatal(err)
 				}
 
-				{
-					var cursor int64
-					for i := 1; i <= len(patchSets); i++ {
-						opts := ListPatchSetsOpts{Cursor: cursor, Limit: 1}
-						have, next, err := s.ListPatchSets(ctx, opts)
-						if err != nil {
-							t.Fatal(err)
-						}
-
-						want := patchSets[i-1 : i]
-						if diff := cmp.Diff(have, want); diff != "" {
-							t.Fatalf("opts: %+v, diff: %s", opts, diff)
-						}
-
-						cursor = next
-					}
+				if int(count) != len(want) {
+					t.Errorf("jobs counted: %d", count)
 				}
 			})
 
 			t.Run("Update", func(t *testing.T) {
-				for _, c := range patchSets {
-					c.UserID += 1234
-
+				for _, p := range patches {
 					now = now.Add(time.Second)
-					want := c
+					p.Diff += "-updated"
+
+					want := p
 					want.UpdatedAt = now
 
-					have := c.Clone()
-					if err := s.UpdatePatchSet(ctx, have); err != nil {
+					have := p.Clone()
+					if err := s.UpdatePatch(ctx, have); err != nil {
 						t.Fatal(err)
 					}
 
@@ -1566,13 +2391,13 @@ func testStore(db *sql.DB) func(*testing.T) {
 
 			t.Run("Get", func(t *testing.T) {
 				t.Run("ByID", func(t *testing.T) {
-					if len(patchSets) == 0 {
-						t.Fatalf("patchSets is empty")
+					if len(patches) == 0 {
+						t.Fatal("patches is empty")
 					}
-					want := patchSets[0]
-					opts := GetPatchSetOpts{ID: want.ID}
+					want := patches[0]
+					opts := GetPatchOpts{ID: want.ID}
 
-					have, err := s.GetPatchSet(ctx, opts)
+					have, err := s.GetPatch(ctx, opts)
 					if err != nil {
 						t.Fatal(err)
 					}
@@ -1583,9 +2408,9 @@ func testStore(db *sql.DB) func(*testing.T) {
 				})
 
 				t.Run("NoResults", func(t *testing.T) {
-					opts := GetPatchSetOpts{ID: 0xdeadbeef}
+					opts := GetPatchOpts{ID: 0xdeadbeef}
 
-					_, have := s.GetPatchSet(ctx, opts)
+					_, have := s.GetPatch(ctx, opts)
 					want := ErrNoResults
 
 					if have != want {
@@ -1595,41 +2420,189 @@ func testStore(db *sql.DB) func(*testing.T) {
 			})
 
 			t.Run("Delete", func(t *testing.T) {
-				for i := range patchSets {
-					err := s.DeletePatchSet(ctx, patchSets[i].ID)
+				for i := range patches {
+					err := s.DeletePatch(ctx, patches[i].ID)
+					if err != nil {
+						t.Fatal(err)
+					}
+
+					count, err := s.CountPatches(ctx, CountPatchesOpts{})
+					if err != nil {
+						t.Fatal(err)
+					}
+
+					if have, want := count, int64(len(patches)-(i+1)); have != want {
+						t.Fatalf("have count: %d, want: %d", have, want)
+					}
+				}
+			})
+		})
+
+		t.Run("PatchSet DeleteExpired", func(t *testing.T) {
+			tests := []struct {
+				createdAt                      time.Time
+				hasCampaign                    bool
+				patchesAttachedToOtherCampaign bool
+				patches                        []*cmpgn.Patch
+				wantDeleted                    bool
+				want                           *cmpgn.BackgroundProcessStatus
+			}{
+				{
+					hasCampaign: false,
+					createdAt:   now,
+					wantDeleted: false,
+				},
+				{
+					hasCampaign: false,
+					createdAt:   now.Add(-500 * time.Minute),
+					wantDeleted: true,
+				},
+				{
+					hasCampaign: true,
+					createdAt:   now,
+					wantDeleted: false,
+				},
+				{
+					hasCampaign: true,
+					createdAt:   now.Add(-500 * time.Minute),
+					wantDeleted: false,
+				},
+				{
+					hasCampaign: false,
+					createdAt:   now.Add(-500 * time.Minute),
+
+					patchesAttachedToOtherCampaign: true,
+					patches: []*cmpgn.Patch{
+						{Diff: "foobar", Rev: "f00b4r", BaseRef: "refs/heads/master"},
+						{Diff: "barfoo", Rev: "b4rf00", BaseRef: "refs/heads/master"},
+					},
+
+					wantDeleted: false,
+				},
+			}
+
+			for _, tc := range tests {
+				patchSet := &cmpgn.PatchSet{CreatedAt: tc.createdAt}
+
+				err := s.CreatePatchSet(ctx, patchSet)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				for _, p := range tc.patches {
+					p.PatchSetID = patchSet.ID
+					err := s.CreatePatch(ctx, p)
+					if err != nil {
+						t.Fatal(err)
+					}
+				}
+
+				if tc.hasCampaign {
+					err = s.CreateCampaign(ctx, &cmpgn.Campaign{
+						PatchSetID:      patchSet.ID,
+						Name:            "Test",
+						AuthorID:        4567,
+						NamespaceUserID: 4567,
+					})
+					if err != nil {
+						t.Fatal(err)
+					}
+				}
+
+				if tc.patchesAttachedToOtherCampaign {
+					otherPatchSet := &cmpgn.PatchSet{}
+					err = s.CreatePatchSet(ctx, otherPatchSet)
+					if err != nil {
+						t.Fatal(err)
+					}
+					otherCampaign := &cmpgn.Campaign{
+						PatchSetID:      otherPatchSet.ID,
+						AuthorID:        4567,
+						Name:            "Other campaign",
+						NamespaceUserID: 4567,
+					}
+
+					err = s.CreateCampaign(ctx, otherCampaign)
 					if err != nil {
 						t.Fatal(err)
 					}
 
-					count, err := s.CountPatchSets(ctx)
-					if err != nil {
-						t.Fatal(err)
-					}
+					for i, p := range tc.patches {
+						changeset := &cmpgn.Changeset{
+							RepoID:              api.RepoID(99 + i),
+							CreatedAt:           now,
+							UpdatedAt:           now,
+							Metadata:            &github.PullRequest{},
+							CampaignIDs:         []int64{otherCampaign.ID},
+							ExternalID:          fmt.Sprintf("foobar-%d", i),
+							ExternalServiceType: "github",
+							ExternalBranch:      "campaigns/test",
+							ExternalUpdatedAt:   now,
+							ExternalState:       cmpgn.ChangesetStateOpen,
+							ExternalReviewState: cmpgn.ChangesetReviewStateApproved,
+							ExternalCheckState:  cmpgn.ChangesetCheckStatePassed,
+						}
+						err = s.CreateChangesets(ctx, changeset)
+						if err != nil {
+							t.Fatal(err)
+						}
+						job := &cmpgn.ChangesetJob{
+							CampaignID:  otherCampaign.ID,
+							PatchID:     p.ID,
+							ChangesetID: changeset.ID,
+						}
+						err = s.CreateChangesetJob(ctx, job)
+						if err != nil {
+							t.Fatal(err)
+						}
+						defer func() {
+							err = s.DeleteChangesetJob(ctx, job.ID)
+							if err != nil {
+								t.Fatal(err)
+							}
+						}()
+					}
+				}
+
+				err = s.DeleteExpiredPatchSets(ctx)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				havePatchSet, err := s.GetPatchSet(ctx, GetPatchSetOpts{ID: patchSet.ID})
+				if err != nil && err != ErrNoResults {
+					t.Fatal(err)
+				}
+
+				if tc.wantDeleted && err == nil {
+					t.Fatalf("tc=%+v\n\t want patch set to be deleted. got: %v", tc, havePatchSet)
+				}
 
-					if have, want := count, int64(len(patchSets)-(i+1)); have != want {
-						t.Fatalf("have count: %d, want: %d", have, want)
-					}
+				if !tc.wantDeleted && err == ErrNoResults {
+					t.Fatalf("want patch set not to be deleted, but got deleted")
 				}
-			})
+			}
 		})
 
-		t.Run("Patches", func(t *testing.T) {
-			patches := make([]*cmpgn.Patch, 0, 3)
+		t.Run("ChangesetJobs", func(t *testing.T) {
+			changesetJobs := make([]*cmpgn.ChangesetJob, 0, 3)
 
 			t.Run("Create", func(t *testing.T) {
-				for i := 0; i < cap(patches); i++ {
-					p := &cmpgn.Patch{
-						PatchSetID: int64(i + 1),
-						RepoID:     1,
-						Rev:        api.CommitID("deadbeef"),
-						BaseRef:    "master",
-						Diff:       "+ foobar - barfoo",
+				for i := 0; i < cap(changesetJobs); i++ {
+					c := &cmpgn.ChangesetJob{
+						CampaignID:  int64(i + 1),
+						PatchID:     int64(i + 1),
+						ChangesetID: int64(i + 1),
+						Branch:      "test-branch",
+						Error:       "only set on error",
+						StartedAt:   now,
+						FinishedAt:  now,
 					}
 
-					want := p.Clone()
-					have := p
+					want := c.Clone()
+					have := c
 
-					err := s.CreatePatch(ctx, have)
+					err := s.CreateChangesetJob(ctx, have)
 					if err != nil {
 						t.Fatal(err)
 					}
@@ -1646,21 +2619,21 @@ func testStore(db *sql.DB) func(*testing.T) {
 						t.Fatal(diff)
 					}
 
-					patches = append(patches, p)
+					changesetJobs = append(changesetJobs, c)
 				}
 			})
 
 			t.Run("Count", func(t *testing.T) {
-				count, err := s.CountPatches(ctx, CountPatchesOpts{})
+				count, err := s.CountChangesetJobs(ctx, CountChangesetJobsOpts{})
 				if err != nil {
 					t.Fatal(err)
 				}
 
-				if have, want := count, int64(len(patches)); have != want {
+				if have, want := count, int64(len(changesetJobs)); have != want {
 					t.Fatalf("have count: %d, want: %d", have, want)
 				}
 
-				count, err = s.CountPatches(ctx, CountPatchesOpts{PatchSetID: 1})
+				count, err = s.CountChangesetJobs(ctx, CountChangesetJobsOpts{CampaignID: 1})
 				if err != nil {
 					t.Fatal(err)
 				}
@@ -1671,11 +2644,11 @@ func testStore(db *sql.DB) func(*testing.T) {
 			})
 
 			t.Run("List", func(t *testing.T) {
-				t.Run("WithPatchSetID", func(t *testing.T) {
-					for i := 1; i <= len(patches); i++ {
-						opts := ListPatchesOpts{PatchSetID: int64(i)}
+				t.Run("WithCampaignID", func(t *testing.T) {
+					for i := 1; i <= len(changesetJobs); i++ {
+						opts := ListChangesetJobsOpts{CampaignID: int64(i)}
 
-						ts, next, err := s.ListPatches(ctx, opts)
+						ts, next, err := s.ListChangesetJobs(ctx, opts)
 						if err != nil {
 							t.Fatal(err)
 						}
@@ -1684,9 +2657,9 @@ func testStore(db *sql.DB) func(*testing.T) {
 							t.Fatalf("opts: %+v: have next %v, want %v", opts, have, want)
 						}
 
-						have, want := ts, patches[i-1:i]
+						have, want := ts, changesetJobs[i-1:i]
 						if len(have) != len(want) {
-							t.Fatalf("listed %d patches, want: %d", len(have), len(want))
+							t.Fatalf("listed %d changesetJobs, want: %d", len(have), len(want))
 						}
 
 						if diff := cmp.Diff(have, want); diff != "" {
@@ -1696,16 +2669,16 @@ func testStore(db *sql.DB) func(*testing.T) {
 				})
 
 				t.Run("WithPositiveLimit", func(t *testing.T) {
-					for i := 1; i <= len(patches); i++ {
-						cs, next, err := s.ListPatches(ctx, ListPatchesOpts{Limit: i})
+					for i := 1; i <= len(changesetJobs); i++ {
+						cs, next, err := s.ListChangesetJobs(ctx, ListChangesetJobsOpts{Limit: i})
 						if err != nil {
 							t.Fatal(err)
 						}
 
 						{
 							have, want := next, int64(0)
-							if i < len(patches) {
-								want = patches[i].ID
+							if i < len(changesetJobs) {
+								want = changesetJobs[i].ID
 							}
 
 							if have != want {
@@ -1714,9 +2687,9 @@ func testStore(db *sql.DB) func(*testing.T) {
 						}
 
 						{
-							have, want := cs, patches[:i]
+							have, want := cs, changesetJobs[:i]
 							if len(have) != len(want) {
-								t.Fatalf("listed %d patches, want: %d", len(have), len(want))
+								t.Fatalf("listed %d changesetJobs, want: %d", len(have), len(want))
 							}
 
 							if diff := cmp.Diff(have, want); diff != "" {
@@ -1727,7 +2700,7 @@ func testStore(db *sql.DB) func(*testing.T) {
 				})
 
 				t.Run("WithNegativeLimitToListAll", func(t *testing.T) {
-					cs, next, err := s.ListPatches(ctx, ListPatchesOpts{Limit: -1})
+					cs, next, err := s.ListChangesetJobs(ctx, ListChangesetJobsOpts{Limit: -1})
 					if err != nil {
 						t.Fatal(err)
 					}
@@ -1736,1062 +2709,1018 @@ func testStore(db *sql.DB) func(*testing.T) {
 						t.Fatalf("have next %v, want %v", have, want)
 					}
 
-					have, want := cs, patches
-					if len(have) != len(want) {
-						t.Fatalf("listed %d patches, want: %d", len(have), len(want))
-					}
-
-					if diff := cmp.Diff(have, want); diff != "" {
-						t.Fatal(diff)
-					}
-				})
-
-				t.Run("EmptyResultListingAll", func(t *testing.T) {
-					opts := ListPatchesOpts{PatchSetID: 99999, Limit: -1}
-
-					js, next, err := s.ListPatches(ctx, opts)
-					if err != nil {
-						t.Fatal(err)
-					}
-
-					if have, want := next, int64(0); have != want {
-						t.Fatalf("opts: %+v: have next %v, want %v", opts, have, want)
-					}
-
-					if len(js) != 0 {
-						t.Fatalf("listed %d jobs, want: %d", len(js), 0)
-					}
-				})
-
-				t.Run("WithCursor", func(t *testing.T) {
-					{
-						var cursor int64
-						for i := 1; i <= len(patches); i++ {
-							opts := ListPatchesOpts{Cursor: cursor, Limit: 1}
-							have, next, err := s.ListPatches(ctx, opts)
-							if err != nil {
-								t.Fatal(err)
-							}
-
-							want := patches[i-1 : i]
-							if diff := cmp.Diff(have, want); diff != "" {
-								t.Fatalf("opts: %+v, diff: %s", opts, diff)
-							}
-
-							cursor = next
-						}
-					}
-				})
-			})
-
-			t.Run("Listing and Counting OnlyWithDiff", func(t *testing.T) {
-				listOpts := ListPatchesOpts{OnlyWithDiff: true}
-				countOpts := CountPatchesOpts{OnlyWithDiff: true}
-
-				have, _, err := s.ListPatches(ctx, listOpts)
-				if err != nil {
-					t.Fatal(err)
-				}
-
-				have, want := have, patches
-				if len(have) != len(want) {
-					t.Fatalf("listed %d patches, want: %d", len(have), len(want))
-				}
-
-				if diff := cmp.Diff(have, want); diff != "" {
-					t.Fatalf("opts: %+v, diff: %s", listOpts, diff)
-				}
-
-				count, err := s.CountPatches(ctx, countOpts)
-				if err != nil {
-					t.Fatal(err)
-				}
-
-				if int(count) != len(want) {
-					t.Errorf("jobs counted: %d", count)
-				}
-
-				for _, p := range patches {
-					p.Diff = ""
-
-					err := s.UpdatePatch(ctx, p)
-					if err != nil {
-						t.Fatal(err)
-					}
-				}
-
-				have, _, err = s.ListPatches(ctx, listOpts)
-				if err != nil {
-					t.Fatal(err)
-				}
-
-				if len(have) != 0 {
-					t.Errorf("jobs returned: %d", len(have))
-				}
-
-				count, err = s.CountPatches(ctx, countOpts)
-				if err != nil {
-					t.Fatal(err)
-				}
-
-				if count != 0 {
-					t.Errorf("jobs counted: %d", count)
-				}
-			})
-
-			t.Run("Listing and Counting OnlyUnpublishedInCampaign", func(t *testing.T) {
-				campaignID := int64(999)
-				changesetJob := &cmpgn.ChangesetJob{
-					PatchID:     patches[0].ID,
-					CampaignID:  campaignID,
-					ChangesetID: 789,
-					StartedAt:   now,
-					FinishedAt:  now,
-				}
-				err := s.CreateChangesetJob(ctx, changesetJob)
-				if err != nil {
-					t.Fatal(err)
-				}
-
-				listOpts := ListPatchesOpts{OnlyUnpublishedInCampaign: campaignID}
-				countOpts := CountPatchesOpts{OnlyUnpublishedInCampaign: campaignID}
-
-				have, _, err := s.ListPatches(ctx, listOpts)
-				if err != nil {
-					t.Fatal(err)
-				}
-
-				have, want := have, patches[1:] // Except patches[0]
-				if len(have) != len(want) {
-					t.Fatalf("listed %d patches, want: %d", len(have), len(want))
-				}
-
-				if diff := cmp.Diff(have, want); diff != "" {
-					t.Fatalf("opts: %+v, diff: %s", listOpts, diff)
-				}
-
-				count, err := s.CountPatches(ctx, countOpts)
-				if err != nil {
-					t.Fatal(err)
-				}
-
-				if int(count) != len(want) {
-					t.Errorf("jobs counted: %d", count)
-				}
-
-				// Update ChangesetJob so condition does not apply
-				changesetJob.ChangesetID = 0
-				err = s.UpdateChangesetJob(ctx, changesetJob)
-				if err != nil {
-					t.Fatal(err)
-				}
-
-				have, _, err = s.ListPatches(ctx, listOpts)
-				if err != nil {
-					t.Fatal(err)
-				}
-
-				want = patches // All Patches
-				if len(have) != len(want) {
-					t.Fatalf("listed %d patches, want: %d", len(have), len(want))
-				}
-
-				if diff := cmp.Diff(have, want); diff != "" {
-					t.Fatalf("opts: %+v, diff: %s", listOpts, diff)
-				}
-
-				count, err = s.CountPatches(ctx, countOpts)
-				if err != nil {
-					t.Fatal(err)
-				}
-
-				if int(count) != len(want) {
-					t.Errorf("jobs counted: %d", count)
-				}
-			})
-
-			t.Run("Update", func(t *testing.T) {
-				for _, p := range patches {
-					now = now.Add(time.Second)
-					p.Diff += "-updated"
-
-					want := p
-					want.UpdatedAt = now
-
-					have := p.Clone()
-					if err := s.UpdatePatch(ctx, have); err != nil {
-						t.Fatal(err)
+					have, want := cs, changesetJobs
+					if len(have) != len(want) {
+						t.Fatalf("listed %d patches, want: %d", len(have), len(want))
 					}
 
 					if diff := cmp.Diff(have, want); diff != "" {
 						t.Fatal(diff)
 					}
-				}
-			})
+				})
 
-			t.Run("Get", func(t *testing.T) {
-				t.Run("ByID", func(t *testing.T) {
-					if len(patches) == 0 {
-						t.Fatal("patches is empty")
-					}
-					want := patches[0]
-					opts := GetPatchOpts{ID: want.ID}
+				t.Run("EmptyResultListingAll", func(t *testing.T) {
+					opts := ListChangesetJobsOpts{CampaignID: 99999, Limit: -1}
 
-					have, err := s.GetPatch(ctx, opts)
+					cs, next, err := s.ListChangesetJobs(ctx, opts)
 					if err != nil {
 						t.Fatal(err)
 					}
 
-					if diff := cmp.Diff(have, want); diff != "" {
-						t.Fatal(diff)
+					if have, want := next, int64(0); have != want {
+						t.Fatalf("opts: %+v: have next %v, want %v", opts, have, want)
+					}
+
+					if len(cs) != 0 {
+						t.Fatalf("listed %d jobs, want: %d", len(cs), 0)
 					}
 				})
 
-				t.Run("NoResults", func(t *testing.T) {
-					opts := GetPatchOpts{ID: 0xdeadbeef}
+				t.Run("WithCursor", func(t *testing.T) {
+					var cursor int64
+					for i := 1; i <= len(changesetJobs); i++ {
+						opts := ListChangesetJobsOpts{Cursor: cursor, Limit: 1}
+						have, next, err := s.ListChangesetJobs(ctx, opts)
+						if err != nil {
+							t.Fatal(err)
+						}
 
-					_, have := s.GetPatch(ctx, opts)
-					want := ErrNoResults
+						want := changesetJobs[i-1 : i]
+						if diff := cmp.Diff(have, want); diff != "" {
+							t.Fatalf("opts: %+v, diff: %s", opts, diff)
+						}
 
-					if have != want {
-						t.Fatalf("have err %v, want %v", have, want)
+						cursor = next
 					}
 				})
-			})
 
-			t.Run("Delete", func(t *testing.T) {
-				for i := range patches {
-					err := s.DeletePatch(ctx, patches[i].ID)
-					if err != nil {
-						t.Fatal(err)
-					}
+				t.Run("WithPatchSetID", func(t *testing.T) {
+					for i := 1; i <= len(changesetJobs); i++ {
+						c := &cmpgn.Campaign{
+							Name:            fmt.Sprintf("Upgrade ES-Lint %d", i),
+							Description:     "All the Javascripts are belong to us",
+							AuthorID:        4567,
+							NamespaceUserID: 4567,
+							PatchSetID:      1234 + int64(i),
+						}
 
-					count, err := s.CountPatches(ctx, CountPatchesOpts{})
-					if err != nil {
-						t.Fatal(err)
-					}
+						err := s.CreateCampaign(ctx, c)
+						if err != nil {
+							t.Fatal(err)
+						}
+						job := changesetJobs[i-1]
 
-					if have, want := count, int64(len(patches)-(i+1)); have != want {
-						t.Fatalf("have count: %d, want: %d", have, want)
-					}
-				}
-			})
-		})
+						job.CampaignID = c.ID
+						err = s.UpdateChangesetJob(ctx, job)
+						if err != nil {
+							t.Fatal(err)
+						}
 
-		t.Run("PatchSet DeleteExpired", func(t *testing.T) {
-			tests := []struct {
-				createdAt                      time.Time
-				hasCampaign                    bool
-				patchesAttachedToOtherCampaign bool
-				patches                        []*cmpgn.Patch
-				wantDeleted                    bool
-				want                           *cmpgn.BackgroundProcessStatus
-			}{
-				{
-					hasCampaign: false,
-					createdAt:   now,
-					wantDeleted: false,
-				},
-				{
-					hasCampaign: false,
-					createdAt:   now.Add(-500 * time.Minute),
-					wantDeleted: true,
-				},
-				{
-					hasCampaign: true,
-					createdAt:   now,
-					wantDeleted: false,
-				},
-				{
-					hasCampaign: true,
-					createdAt:   now.Add(-500 * time.Minute),
-					wantDeleted: false,
-				},
-				{
-					hasCampaign: false,
-					createdAt:   now.Add(-500 * time.Minute),
+						opts := ListChangesetJobsOpts{PatchSetID: c.PatchSetID}
+						ts, next, err := s.ListChangesetJobs(ctx, opts)
+						if err != nil {
+							t.Fatal(err)
+						}
 
-					patchesAttachedToOtherCampaign: true,
-					patches: []*cmpgn.Patch{
-						{Diff: "foobar", Rev: "f00b4r", BaseRef: "refs/heads/master"},
-						{Diff: "barfoo", Rev: "b4rf00", BaseRef: "refs/heads/master"},
-					},
+						if have, want := next, int64(0); have != want {
+							t.Fatalf("opts: %+v: have next %v, want %v", opts, have, want)
+						}
 
-					wantDeleted: false,
-				},
-			}
+						have, want := ts, changesetJobs[i-1:i]
+						if len(have) != len(want) {
+							t.Fatalf("listed %d changesetJobs, want: %d", len(have), len(want))
+						}
 
-			for _, tc := range tests {
-				patchSet := &cmpgn.PatchSet{CreatedAt: tc.createdAt}
+						if diff := cmp.Diff(have, want); diff != "" {
+							t.Fatalf("opts: %+v, diff: %s", opts, diff)
+						}
+					}
+				})
+			})
 
-				err := s.CreatePatchSet(ctx, patchSet)
-				if err != nil {
-					t.Fatal(err)
-				}
+			t.Run("Update", func(t *testing.T) {
+				for _, c := range changesetJobs {
+					now = now.Add(time.Second)
+					c.StartedAt = now.Add(1 * time.Second)
+					c.FinishedAt = now.Add(1 * time.Second)
+					c.Branch = "upgrade-es-lint"
+					c.Error = "updated-error"
 
-				for _, p := range tc.patches {
-					p.PatchSetID = patchSet.ID
-					err := s.CreatePatch(ctx, p)
-					if err != nil {
+					want := c
+					want.UpdatedAt = now
+
+					have := c.Clone()
+					if err := s.UpdateChangesetJob(ctx, have); err != nil {
 						t.Fatal(err)
 					}
-				}
 
-				if tc.hasCampaign {
-					err = s.CreateCampaign(ctx, &cmpgn.Campaign{
-						PatchSetID:      patchSet.ID,
-						Name:            "Test",
-						AuthorID:        4567,
-						NamespaceUserID: 4567,
-					})
-					if err != nil {
-						t.Fatal(err)
+					if diff := cmp.Diff(have, want); diff != "" {
+						t.Fatal(diff)
 					}
 				}
+			})
 
-				if tc.patchesAttachedToOtherCampaign {
-					otherPatchSet := &cmpgn.PatchSet{}
-					err = s.CreatePatchSet(ctx, otherPatchSet)
-					if err != nil {
-						t.Fatal(err)
-					}
-					otherCampaign := &cmpgn.Campaign{
-						PatchSetID:      otherPatchSet.ID,
-						AuthorID:        4567,
-						Name:            "Other campaign",
-						NamespaceUserID: 4567,
+			t.Run("Get", func(t *testing.T) {
+				t.Run("ByID", func(t *testing.T) {
+					if len(changesetJobs) == 0 {
+						t.Fatal("changesetJobs is empty")
 					}
+					want := changesetJobs[0]
+					opts := GetChangesetJobOpts{ID: want.ID}
 
-					err = s.CreateCampaign(ctx, otherCampaign)
+					have, err := s.GetChangesetJob(ctx, opts)
 					if err != nil {
 						t.Fatal(err)
 					}
 
-					for i, p := range tc.patches {
-						changeset := &cmpgn.Changeset{
-							RepoID:              api.RepoID(99 + i),
-							CreatedAt:           now,
-							UpdatedAt:           now,
-							Metadata:            &github.PullRequest{},
-							CampaignIDs:         []int64{otherCampaign.ID},
-							ExternalID:          fmt.Sprintf("foobar-%d", i),
-							ExternalServiceType: "github",
-							ExternalBranch:      "campaigns/test",
-							ExternalUpdatedAt:   now,
-							ExternalState:       cmpgn.ChangesetStateOpen,
-							ExternalReviewState: cmpgn.ChangesetReviewStateApproved,
-							ExternalCheckState:  cmpgn.ChangesetCheckStatePassed,
-						}
-						err = s.CreateChangesets(ctx, changeset)
-						if err != nil {
-							t.Fatal(err)
-						}
-						job := &cmpgn.ChangesetJob{
-							CampaignID:  otherCampaign.ID,
-							PatchID:     p.ID,
-							ChangesetID: changeset.ID,
-						}
-						err = s.CreateChangesetJob(ctx, job)
-						if err != nil {
-							t.Fatal(err)
-						}
-						defer func() {
-							err = s.DeleteChangesetJob(ctx, job.ID)
-							if err != nil {
-								t.Fatal(err)
-							}
-						}()
+					if diff := cmp.Diff(have, want); diff != "" {
+						t.Fatal(diff)
 					}
-				}
-
-				err = s.DeleteExpiredPatchSets(ctx)
-				if err != nil {
-					t.Fatal(err)
-				}
-
-				havePatchSet, err := s.GetPatchSet(ctx, GetPatchSetOpts{ID: patchSet.ID})
-				if err != nil && err != ErrNoResults {
-					t.Fatal(err)
-				}
-
-				if tc.wantDeleted && err == nil {
-					t.Fatalf("tc=%+v\n\t want patch set to be deleted. got: %v", tc, havePatchSet)
-				}
+				})
 
-				if !tc.wantDeleted && err == ErrNoResults {
-					t.Fatalf("want patch set not to be deleted, but got deleted")
-				}
-			}
-		})
+				t.Run("ByPatchID", func(t *testing.T) {
+					if len(changesetJobs) == 0 {
+						t.Fatal("changesetJobs is empty")
+					}
+					want := changesetJobs[0]
+					opts := GetChangesetJobOpts{PatchID: want.PatchID}
 
-		t.Run("ChangesetJobs", func(t *testing.T) {
-			changesetJobs := make([]*cmpgn.ChangesetJob, 0, 3)
+					have, err := s.GetChangesetJob(ctx, opts)
+					if err != nil {
+						t.Fatal(err)
+					}
 
-			t.Run("Create", func(t *testing.T) {
-				for i := 0; i < cap(changesetJobs); i++ {
-					c := &cmpgn.ChangesetJob{
-						CampaignID:  int64(i + 1),
-						PatchID:     int64(i + 1),
-						ChangesetID: int64(i + 1),
-						Branch:      "test-branch",
-						Error:       "only set on error",
-						StartedAt:   now,
-						FinishedAt:  now,
+					if diff := cmp.Diff(have, want); diff != "" {
+						t.Fatal(diff)
 					}
+				})
 
-					want := c.Clone()
-					have := c
+				t.Run("ByChangesetID", func(t *testing.T) {
+					if len(changesetJobs) == 0 {
+						t.Fatal("changesetJobs is empty")
+					}
+					want := changesetJobs[0]
+					opts := GetChangesetJobOpts{ChangesetID: want.ChangesetID}
 
-					err := s.CreateChangesetJob(ctx, have)
+					have, err := s.GetChangesetJob(ctx, opts)
 					if err != nil {
 						t.Fatal(err)
 					}
 
-					if have.ID == 0 {
-						t.Fatal("ID should not be zero")
+					if diff := cmp.Diff(have, want); diff != "" {
+						t.Fatal(diff)
 					}
+				})
 
-					want.ID = have.ID
-					want.CreatedAt = now
-					want.UpdatedAt = now
+				t.Run("ByCampaignID", func(t *testing.T) {
+					if len(changesetJobs) == 0 {
+						t.Fatal("changesetJobs is empty")
+					}
+					// Use the last changesetJob, which we don't get by
+					// accident when selecting all with LIMIT 1
+					want := changesetJobs[2]
+					opts := GetChangesetJobOpts{CampaignID: want.CampaignID}
+
+					have, err := s.GetChangesetJob(ctx, opts)
+					if err != nil {
+						t.Fatal(err)
+					}
 
 					if diff := cmp.Diff(have, want); diff != "" {
 						t.Fatal(diff)
 					}
+				})
 
-					changesetJobs = append(changesetJobs, c)
-				}
+				t.Run("NoResults", func(t *testing.T) {
+					opts := GetChangesetJobOpts{ID: 0xdeadbeef}
+
+					_, have := s.GetChangesetJob(ctx, opts)
+					want := ErrNoResults
+
+					if have != want {
+						t.Fatalf("have err %v, want %v", have, want)
+					}
+				})
 			})
 
-			t.Run("Count", func(t *testing.T) {
-				count, err := s.CountChangesetJobs(ctx, CountChangesetJobsOpts{})
-				if err != nil {
-					t.Fatal(err)
-				}
+			t.Run("Delete", func(t *testing.T) {
+				for i := range changesetJobs {
+					err := s.DeleteChangesetJob(ctx, changesetJobs[i].ID)
+					if err != nil {
+						t.Fatal(err)
+					}
 
-				if have, want := count, int64(len(changesetJobs)); have != want {
-					t.Fatalf("have count: %d, want: %d", have, want)
-				}
+					count, err := s.CountChangesetJobs(ctx, CountChangesetJobsOpts{})
+					if err != nil {
+						t.Fatal(err)
+					}
 
-				count, err = s.CountChangesetJobs(ctx, CountChangesetJobsOpts{CampaignID: 1})
-				if err != nil {
-					t.Fatal(err)
+					if have, want := count, int64(len(changesetJobs)-(i+1)); have != want {
+						t.Fatalf("have count: %d, want: %d", have, want)
+					}
 				}
+			})
 
-				if have, want := count, int64(1); have != want {
-					t.Fatalf("have count: %d, want: %d", have, want)
+			t.Run("BackgroundProcessStatus", func(t *testing.T) {
+				tests := []struct {
+					jobs []*cmpgn.ChangesetJob
+					want *cmpgn.BackgroundProcessStatus
+				}{
+					{
+						jobs: []*cmpgn.ChangesetJob{}, // no jobs
+						want: &cmpgn.BackgroundProcessStatus{
+							ProcessState:  cmpgn.BackgroundProcessStateCompleted,
+							Total:         0,
+							Completed:     0,
+							Pending:       0,
+							ProcessErrors: nil,
+						},
+					},
+					{
+						jobs: []*cmpgn.ChangesetJob{
+							// not started (pending)
+							{},
+							// started (pending)
+							{StartedAt: now},
+						},
+						want: &cmpgn.BackgroundProcessStatus{
+							ProcessState:  cmpgn.BackgroundProcessStateProcessing,
+							Total:         2,
+							Completed:     0,
+							Pending:       2,
+							ProcessErrors: nil,
+						},
+					},
+					{
+						jobs: []*cmpgn.ChangesetJob{
+							// completed, no errors
+							{StartedAt: now, FinishedAt: now, ChangesetID: 23},
+						},
+						want: &cmpgn.BackgroundProcessStatus{
+							ProcessState:  cmpgn.BackgroundProcessStateCompleted,
+							Total:         1,
+							Completed:     1,
+							Pending:       0,
+							ProcessErrors: nil,
+						},
+					},
+					{
+						jobs: []*cmpgn.ChangesetJob{
+							// completed, error
+							{StartedAt: now, FinishedAt: now, Error: "error1"},
+						},
+						want: &cmpgn.BackgroundProcessStatus{
+							ProcessState:  cmpgn.BackgroundProcessStateErrored,
+							Total:         1,
+							Completed:     1,
+							Pending:       0,
+							ProcessErrors: []string{"error1"},
+						},
+					},
+					{
+						jobs: []*cmpgn.ChangesetJob{
+							// not started (pending)
+							{},
+							// started (pending)
+							{StartedAt: now},
+							// completed, no errors
+							{StartedAt: now, FinishedAt: now, ChangesetID: 23},
+							// completed, error
+							{StartedAt: now, FinishedAt: now, Error: "error1"},
+							// completed, another error
+							{StartedAt: now, FinishedAt: now, Error: "error2"},
+						},
+						want: &cmpgn.BackgroundProcessStatus{
+							ProcessState:  cmpgn.BackgroundProcessStateProcessing,
+							Total:         5,
+							Completed:     3,
+							Pending:       2,
+							ProcessErrors: []string{"error1", "error2"},
+						},
+					},
 				}
-			})
 
-			t.Run("List", func(t *testing.T) {
-				t.Run("WithCampaignID", func(t *testing.T) {
-					for i := 1; i <= len(changesetJobs); i++ {
-						opts := ListChangesetJobsOpts{CampaignID: int64(i)}
+				for campaignID, tc := range tests {
+					for i, j := range tc.jobs {
+						j.CampaignID = int64(campaignID)
+						j.PatchID = int64(i)
 
-						ts, next, err := s.ListChangesetJobs(ctx, opts)
+						err := s.CreateChangesetJob(ctx, j)
 						if err != nil {
 							t.Fatal(err)
 						}
+					}
 
-						if have, want := next, int64(0); have != want {
-							t.Fatalf("opts: %+v: have next %v, want %v", opts, have, want)
-						}
+					status, err := s.GetCampaignStatus(ctx, int64(campaignID))
+					if err != nil {
+						t.Fatal(err)
+					}
 
-						have, want := ts, changesetJobs[i-1:i]
-						if len(have) != len(want) {
-							t.Fatalf("listed %d changesetJobs, want: %d", len(have), len(want))
-						}
+					if diff := cmp.Diff(status, tc.want); diff != "" {
+						t.Fatalf("wrong diff: %s", diff)
+					}
+				}
+			})
 
-						if diff := cmp.Diff(have, want); diff != "" {
-							t.Fatalf("opts: %+v, diff: %s", opts, diff)
-						}
+			t.Run("ResetFailedChangesetJobs", func(t *testing.T) {
+				campaignID := 9999
+				jobs := []*cmpgn.ChangesetJob{
+					// completed, no errors
+					{StartedAt: now, FinishedAt: now, ChangesetID: 23},
+					// completed, error
+					{StartedAt: now, FinishedAt: now, Error: "error1"},
+					// completed, another error
+					{StartedAt: now, FinishedAt: now, Error: "error2"},
+				}
+
+				for i, j := range jobs {
+					j.CampaignID = int64(campaignID)
+					j.PatchID = int64(i)
+
+					err := s.CreateChangesetJob(ctx, j)
+					if err != nil {
+						t.Fatal(err)
 					}
-				})
 
-				t.Run("WithPositiveLimit", func(t *testing.T) {
-					for i := 1; i <= len(changesetJobs); i++ {
-						cs, next, err := s.ListChangesetJobs(ctx, ListChangesetJobsOpts{Limit: i})
-						if err != nil {
-							t.Fatal(err)
-						}
+				}
 
-						{
-							have, want := next, int64(0)
-							if i < len(changesetJobs) {
-								want = changesetJobs[i].ID
-							}
+				mustReset := map[int64]bool{
+					jobs[1].ID: true,
+					jobs[2].ID: true,
+				}
 
-							if have != want {
-								t.Fatalf("limit: %v: have next %v, want %v", i, have, want)
-							}
-						}
+				err := s.ResetFailedChangesetJobs(ctx, int64(campaignID))
+				if err != nil {
+					t.Fatal(err)
+				}
 
-						{
-							have, want := cs, changesetJobs[:i]
-							if len(have) != len(want) {
-								t.Fatalf("listed %d changesetJobs, want: %d", len(have), len(want))
-							}
+				have, _, err := s.ListChangesetJobs(ctx, ListChangesetJobsOpts{CampaignID: int64(campaignID)})
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if len(have) != len(jobs) {
+					t.Fatalf("wrong number of jobs returned. have=%d, want=%d", len(have), len(jobs))
+				}
 
-							if diff := cmp.Diff(have, want); diff != "" {
-								t.Fatal(diff)
-							}
+				for _, job := range have {
+					if _, ok := mustReset[job.ID]; ok {
+						if job.Error != "" {
+							t.Errorf("job should be reset but has error: %+v", job.Error)
+						}
+						if !job.FinishedAt.IsZero() {
+							t.Errorf("job should be reset but has FinishedAt: %+v", job.FinishedAt)
+						}
+						if !job.StartedAt.IsZero() {
+							t.Errorf("job should be reset but has StartedAt: %+v", job.StartedAt)
+						}
+					} else {
+						if job.StartedAt.IsZero() {
+							t.Errorf("job should not be reset but StartedAt is zero: %+v", job.StartedAt)
+						}
+						if job.FinishedAt.IsZero() {
+							t.Errorf("job should not be reset but FinishedAt is zero: %+v", job.FinishedAt)
 						}
 					}
-				})
+				}
+			})
 
-				t.Run("WithNegativeLimitToListAll", func(t *testing.T) {
-					cs, next, err := s.ListChangesetJobs(ctx, ListChangesetJobsOpts{Limit: -1})
+			t.Run("ResetChangesetJobs", func(t *testing.T) {
+				campaignID := 12345
+				jobs := []*cmpgn.ChangesetJob{
+					// completed, no errors
+					{StartedAt: now, FinishedAt: now, ChangesetID: 12345},
+					// completed, error
+					{StartedAt: now, FinishedAt: now, Error: "error1"},
+				}
+
+				for i, j := range jobs {
+					j.CampaignID = int64(campaignID)
+					j.PatchID = int64(i)
+
+					err := s.CreateChangesetJob(ctx, j)
 					if err != nil {
 						t.Fatal(err)
 					}
 
-					if have, want := next, int64(0); have != want {
-						t.Fatalf("have next %v, want %v", have, want)
-					}
+				}
 
-					have, want := cs, changesetJobs
-					if len(have) != len(want) {
-						t.Fatalf("listed %d patches, want: %d", len(have), len(want))
-					}
+				err := s.ResetChangesetJobs(ctx, int64(campaignID))
+				if err != nil {
+					t.Fatal(err)
+				}
 
-					if diff := cmp.Diff(have, want); diff != "" {
-						t.Fatal(diff)
-					}
-				})
+				have, _, err := s.ListChangesetJobs(ctx, ListChangesetJobsOpts{CampaignID: int64(campaignID)})
+				if err != nil {
+					t.Fatal(err)
+				}
 
-				t.Run("EmptyResultListingAll", func(t *testing.T) {
-					opts := ListChangesetJobsOpts{CampaignID: 99999, Limit: -1}
+				if len(have) != len(jobs) {
+					t.Fatalf("wrong number of jobs returned. have=%d, want=%d", len(have), len(jobs))
+				}
 
-					cs, next, err := s.ListChangesetJobs(ctx, opts)
-					if err != nil {
-						t.Fatal(err)
+				for _, job := range have {
+					if job.Error != "" {
+						t.Errorf("job should be reset but has error: %+v", job.Error)
 					}
-
-					if have, want := next, int64(0); have != want {
-						t.Fatalf("opts: %+v: have next %v, want %v", opts, have, want)
+					if !job.FinishedAt.IsZero() {
+						t.Errorf("job should be reset but has FinishedAt: %+v", job.FinishedAt)
 					}
-
-					if len(cs) != 0 {
-						t.Fatalf("listed %d jobs, want: %d", len(cs), 0)
+					if !job.StartedAt.IsZero() {
+						t.Errorf("job should be reset but has StartedAt: %+v", job.StartedAt)
 					}
-				})
+				}
+			})
 
-				t.Run("WithCursor", func(t *testing.T) {
-					var cursor int64
-					for i := 1; i <= len(changesetJobs); i++ {
-						opts := ListChangesetJobsOpts{Cursor: cursor, Limit: 1}
-						have, next, err := s.ListChangesetJobs(ctx, opts)
-						if err != nil {
-							t.Fatal(err)
-						}
+			t.Run("GetLatestChangesetJobCreatedAt", func(t *testing.T) {
+				patchSet := &cmpgn.PatchSet{}
+				err := s.CreatePatchSet(ctx, patchSet)
+				if err != nil {
+					t.Fatal(err)
+				}
 
-						want := changesetJobs[i-1 : i]
-						if diff := cmp.Diff(have, want); diff != "" {
-							t.Fatalf("opts: %+v, diff: %s", opts, diff)
-						}
+				campaign := testCampaign(123, patchSet.ID)
+				err = s.CreateCampaign(ctx, campaign)
+				if err != nil {
+					t.Fatal(err)
+				}
 
-						cursor = next
+				// Cleanup existing ChangesetJobs so we don't have interference
+				// between the previous tests and this one.
+				chjs, _, err := s.ListChangesetJobs(ctx, ListChangesetJobsOpts{Limit: -1})
+				if err != nil {
+					t.Fatal(err)
+				}
+				for _, j := range chjs {
+					err := s.DeleteChangesetJob(ctx, j.ID)
+					if err != nil {
+						t.Fatal(err)
 					}
-				})
-
-				t.Run("WithPatchSetID", func(t *testing.T) {
-					for i := 1; i <= len(changesetJobs); i++ {
-						c := &cmpgn.Campaign{
-							Name:            fmt.Sprintf("Upgrade ES-Lint %d", i),
-							Description:     "All the Javascripts are belong to us",
-							AuthorID:        4567,
-							NamespaceUserID: 4567,
-							PatchSetID:      1234 + int64(i),
-						}
-
-						err := s.CreateCampaign(ctx, c)
-						if err != nil {
-							t.Fatal(err)
-						}
-						job := changesetJobs[i-1]
-
-						job.CampaignID = c.ID
-						err = s.UpdateChangesetJob(ctx, job)
-						if err != nil {
-							t.Fatal(err)
-						}
+				}
 
-						opts := ListChangesetJobsOpts{PatchSetID: c.PatchSetID}
-						ts, next, err := s.ListChangesetJobs(ctx, opts)
-						if err != nil {
-							t.Fatal(err)
-						}
+				patch := &cmpgn.Patch{
+					PatchSetID: patchSet.ID,
+					BaseRef:    "x",
+					RepoID:     api.RepoID(123),
+				}
+				err = s.CreatePatch(ctx, patch)
+				if err != nil {
+					t.Fatal(err)
+				}
 
-						if have, want := next, int64(0); have != want {
-							t.Fatalf("opts: %+v: have next %v, want %v", opts, have, want)
-						}
+				// 0 ChangesetJob, 1 Patches
+				have, err := s.GetLatestChangesetJobCreatedAt(ctx, campaign.ID)
+				if err != nil {
+					t.Fatal(err)
+				}
+				// Job counts don't match, should get back null
+				if !have.IsZero() {
+					t.Fatalf("publishedAt is not zero: %v", have)
+				}
 
-						have, want := ts, changesetJobs[i-1:i]
-						if len(have) != len(want) {
-							t.Fatalf("listed %d changesetJobs, want: %d", len(have), len(want))
-						}
+				changesetJob1 := &cmpgn.ChangesetJob{
+					CampaignID: campaign.ID,
+					PatchID:    patch.ID,
+				}
+				err = s.CreateChangesetJob(ctx, changesetJob1)
+				if err != nil {
+					t.Fatal(err)
+				}
 
-						if diff := cmp.Diff(have, want); diff != "" {
-							t.Fatalf("opts: %+v, diff: %s", opts, diff)
-						}
-					}
-				})
-			})
+				// 1 ChangesetJob, 1 Patches
+				have, err = s.GetLatestChangesetJobCreatedAt(ctx, campaign.ID)
+				if err != nil {
+					t.Fatal(err)
+				}
+				// Job counts are the same, we should get a valid time
+				if !have.Equal(clock()) {
+					t.Fatalf("want %v, got %v", clock(), have)
+				}
 
-			t.Run("Update", func(t *testing.T) {
-				for _, c := range changesetJobs {
-					now = now.Add(time.Second)
-					c.StartedAt = now.Add(1 * time.Second)
-					c.FinishedAt = now.Add(1 * time.Second)
-					c.Branch = "upgrade-es-lint"
-					c.Error = "updated-error"
+				// Create another round to ensure that we get the latest date
+				// when there are more than one
+				oldClock := clock
+				defer func() {
+					clock = oldClock
+				}()
+				clock = func() time.Time {
+					return oldClock().Add(5 * time.Minute)
+				}
+				oldStore := s
+				defer func() {
+					s = oldStore
+				}()
+				s = NewStoreWithClock(tx, clock)
+				patch = &cmpgn.Patch{
+					PatchSetID: patchSet.ID,
+					BaseRef:    "x",
+					RepoID:     api.RepoID(123),
+				}
+				err = s.CreatePatch(ctx, patch)
+				if err != nil {
+					t.Fatal(err)
+				}
 
-					want := c
-					want.UpdatedAt = now
+				// 1 ChangesetJob, 2 Patches
+				have, err = s.GetLatestChangesetJobCreatedAt(ctx, campaign.ID)
+				if err != nil {
+					t.Fatal(err)
+				}
+				// Job counts don't match, should get back null
+				if !have.IsZero() {
+					t.Fatalf("publishedAt is not zero: %v", have)
+				}
 
-					have := c.Clone()
-					if err := s.UpdateChangesetJob(ctx, have); err != nil {
-						t.Fatal(err)
-					}
+				// Add another changesetjob
+				changesetJob2 := &cmpgn.ChangesetJob{
+					CampaignID: campaign.ID,
+					PatchID:    patch.ID,
+				}
+				err = s.CreateChangesetJob(ctx, changesetJob2)
+				if err != nil {
+					t.Fatal(err)
+				}
 
-					if diff := cmp.Diff(have, want); diff != "" {
-						t.Fatal(diff)
-					}
+				// 2 ChangesetJob, 2 Patches
+				have, err = s.GetLatestChangesetJobCreatedAt(ctx, campaign.ID)
+				if err != nil {
+					t.Fatal(err)
+				}
+				// Job counts are the same, we should get a valid time
+				if !have.Equal(clock()) {
+					t.Fatalf("want %v, got %v", clock(), have)
 				}
 			})
 
-			t.Run("Get", func(t *testing.T) {
-				t.Run("ByID", func(t *testing.T) {
-					if len(changesetJobs) == 0 {
-						t.Fatal("changesetJobs is empty")
-					}
-					want := changesetJobs[0]
-					opts := GetChangesetJobOpts{ID: want.ID}
+		})
 
-					have, err := s.GetChangesetJob(ctx, opts)
-					if err != nil {
-						t.Fatal(err)
-					}
+		t.Run("PatchJobs", func(t *testing.T) {
+			patchSet := &cmpgn.PatchSet{
+				UserID: 999,
+				Replacement: &cmpgn.Replacement{
+					Query:   "repo:sourcegraph",
+					Kind:    cmpgn.ReplacementKindComby,
+					Match:   "fmt.Errorf(:[args])",
+					Rewrite: "errors.Errorf(:[args])",
+				},
+			}
+			if err := s.CreatePatchSet(ctx, patchSet); err != nil {
+				t.Fatal(err)
+			}
 
-					if diff := cmp.Diff(have, want); diff != "" {
-						t.Fatal(diff)
-					}
-				})
+			patchJobs := make([]*cmpgn.PatchJob, 0, 3)
 
-				t.Run("ByPatchID", func(t *testing.T) {
-					if len(changesetJobs) == 0 {
-						t.Fatal("changesetJobs is empty")
+			t.Run("Create", func(t *testing.T) {
+				for i := 0; i < cap(patchJobs); i++ {
+					j := &cmpgn.PatchJob{
+						PatchSetID: patchSet.ID,
+						RepoID:     api.RepoID(i + 1),
 					}
-					want := changesetJobs[0]
-					opts := GetChangesetJobOpts{PatchID: want.PatchID}
 
-					have, err := s.GetChangesetJob(ctx, opts)
-					if err != nil {
-						t.Fatal(err)
-					}
+					want := j.Clone()
+					have := j
 
-					if diff := cmp.Diff(have, want); diff != "" {
-						t.Fatal(diff)
+					if err := s.CreatePatchJob(ctx, have); err != nil {
+						t.Fatal(err)
 					}
-				})
 
-				t.Run("ByChangesetID", func(t *testing.T) {
-					if len(changesetJobs) == 0 {
-						t.Fatal("changesetJobs is empty")
+					if have.ID == 0 {
+						t.Fatal("ID should not be zero")
 					}
-					want := changesetJobs[0]
-					opts := GetChangesetJobOpts{ChangesetID: want.ChangesetID}
 
-					have, err := s.GetChangesetJob(ctx, opts)
-					if err != nil {
-						t.Fatal(err)
-					}
+					want.ID = have.ID
+					want.CreatedAt = now
+					want.UpdatedAt = now
 
 					if diff := cmp.Diff(have, want); diff != "" {
 						t.Fatal(diff)
 					}
-				})
 
-				t.Run("ByCampaignID", func(t *testing.T) {
-					if len(changesetJobs) == 0 {
-						t.Fatal("changesetJobs is empty")
-					}
-					// Use the last changesetJob, which we don't get by
-					// accident when selecting all with LIMIT 1
-					want := changesetJobs[2]
-					opts := GetChangesetJobOpts{CampaignID: want.CampaignID}
+					patchJobs = append(patchJobs, j)
+				}
+			})
 
-					have, err := s.GetChangesetJob(ctx, opts)
-					if err != nil {
-						t.Fatal(err)
-					}
+			t.Run("List", func(t *testing.T) {
+				opts := ListPatchJobsOpts{PatchSetID: patchSet.ID}
 
-					if diff := cmp.Diff(have, want); diff != "" {
-						t.Fatal(diff)
-					}
-				})
+				have, next, err := s.ListPatchJobs(ctx, opts)
+				if err != nil {
+					t.Fatal(err)
+				}
 
-				t.Run("NoResults", func(t *testing.T) {
-					opts := GetChangesetJobOpts{ID: 0xdeadbeef}
+				if next != 0 {
+					t.Fatalf("opts: %+v: have next %v, want 0", opts, next)
+				}
 
-					_, have := s.GetChangesetJob(ctx, opts)
-					want := ErrNoResults
+				if diff := cmp.Diff(have, patchJobs); diff != "" {
+					t.Fatalf("opts: %+v, diff: %s", opts, diff)
+				}
+			})
 
-					if have != want {
-						t.Fatalf("have err %v, want %v", have, want)
-					}
-				})
+			t.Run("Status", func(t *testing.T) {
+				status, err := s.GetPatchSetStatus(ctx, patchSet.ID)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				want := &cmpgn.BackgroundProcessStatus{
+					Total:        3,
+					Pending:      3,
+					ProcessState: cmpgn.BackgroundProcessStateProcessing,
+				}
+				if diff := cmp.Diff(status, want); diff != "" {
+					t.Fatal(diff)
+				}
 			})
 
-			t.Run("Delete", func(t *testing.T) {
-				for i := range changesetJobs {
-					err := s.DeleteChangesetJob(ctx, changesetJobs[i].ID)
-					if err != nil {
-						t.Fatal(err)
+			t.Run("Update", func(t *testing.T) {
+				for i, j := range patchJobs {
+					j.StartedAt = now
+					j.FinishedAt = now
+					if i == 0 {
+						j.Error = "comby failed"
 					}
 
-					count, err := s.CountChangesetJobs(ctx, CountChangesetJobsOpts{})
-					if err != nil {
+					now = now.Add(time.Second)
+					want := j
+					want.UpdatedAt = now
+
+					have := j.Clone()
+					if err := s.UpdatePatchJob(ctx, have); err != nil {
 						t.Fatal(err)
 					}
 
-					if have, want := count, int64(len(changesetJobs)-(i+1)); have != want {
-						t.Fatalf("have count: %d, want: %d", have, want)
+					if diff := cmp.Diff(have, want); diff != "" {
+						t.Fatal(diff)
 					}
 				}
-			})
 
-			t.Run("BackgroundProcessStatus", func(t *testing.T) {
-				tests := []struct {
-					jobs []*cmpgn.ChangesetJob
-					want *cmpgn.BackgroundProcessStatus
-				}{
-					{
-						jobs: []*cmpgn.ChangesetJob{}, // no jobs
-						want: &cmpgn.BackgroundProcessStatus{
-							ProcessState:  cmpgn.BackgroundProcessStateCompleted,
-							Total:         0,
-							Completed:     0,
-							Pending:       0,
-							ProcessErrors: nil,
-						},
-					},
-					{
-						jobs: []*cmpgn.ChangesetJob{
-							// not started (pending)
-							{},
-							// started (pending)
-							{StartedAt: now},
-						},
-						want: &cmpgn.BackgroundProcessStatus{
-							ProcessState:  cmpgn.BackgroundProcessStateProcessing,
-							Total:         2,
-							Completed:     0,
-							Pending:       2,
-							ProcessErrors: nil,
-						},
-					},
-					{
-						jobs: []*cmpgn.ChangesetJob{
-							// completed, no errors
-							{StartedAt: now, FinishedAt: now, ChangesetID: 23},
-						},
-						want: &cmpgn.BackgroundProcessStatus{
-							ProcessState:  cmpgn.BackgroundProcessStateCompleted,
-							Total:         1,
-							Completed:     1,
-							Pending:       0,
-							ProcessErrors: nil,
-						},
-					},
-					{
-						jobs: []*cmpgn.ChangesetJob{
-							// completed, error
-							{StartedAt: now, FinishedAt: now, Error: "error1"},
-						},
-						want: &cmpgn.BackgroundProcessStatus{
-							ProcessState:  cmpgn.BackgroundProcessStateErrored,
-							Total:         1,
-							Completed:     1,
-							Pending:       0,
-							ProcessErrors: []string{"error1"},
-						},
-					},
-					{
-						jobs: []*cmpgn.ChangesetJob{
-							// not started (pending)
-							{},
-							// started (pending)
-							{StartedAt: now},
-							// completed, no errors
-							{StartedAt: now, FinishedAt: now, ChangesetID: 23},
-							// completed, error
-							{StartedAt: now, FinishedAt: now, Error: "error1"},
-							// completed, another error
-							{StartedAt: now, FinishedAt: now, Error: "error2"},
-						},
-						want: &cmpgn.BackgroundProcessStatus{
-							ProcessState:  cmpgn.BackgroundProcessStateProcessing,
-							Total:         5,
-							Completed:     3,
-							Pending:       2,
-							ProcessErrors: []string{"error1", "error2"},
-						},
-					},
+				status, err := s.GetPatchSetStatus(ctx, patchSet.ID)
+				if err != nil {
+					t.Fatal(err)
 				}
 
-				for campaignID, tc := range tests {
-					for i, j := range tc.jobs {
-						j.CampaignID = int64(campaignID)
-						j.PatchID = int64(i)
+				want := &cmpgn.BackgroundProcessStatus{
+					Total:         3,
+					Completed:     3,
+					ProcessState:  cmpgn.BackgroundProcessStateErrored,
+					ProcessErrors: []string{"comby failed"},
+				}
+				if diff := cmp.Diff(status, want); diff != "" {
+					t.Fatal(diff)
+				}
+			})
+		})
 
-						err := s.CreateChangesetJob(ctx, j)
-						if err != nil {
-							t.Fatal(err)
-						}
-					}
+		t.Run("ChangesetActions", func(t *testing.T) {
+			campaign := &cmpgn.Campaign{
+				Name:            "Changeset actions campaign",
+				AuthorID:        1,
+				NamespaceUserID: 1,
+			}
+			if err := s.CreateCampaign(ctx, campaign); err != nil {
+				t.Fatal(err)
+			}
 
-					status, err := s.GetCampaignStatus(ctx, int64(campaignID))
-					if err != nil {
+			actions := []*cmpgn.ChangesetAction{
+				{
+					CampaignID: campaign.ID,
+					UserID:     1,
+					Type:       cmpgn.ChangesetActionTypeComment,
+					Body:       "Please review this!",
+					Labels:     []string{},
+					Reviewers:  []string{},
+				},
+				{
+					CampaignID: campaign.ID,
+					UserID:     1,
+					Type:       cmpgn.ChangesetActionTypeAddLabels,
+					Labels:     []string{"automerge", "security"},
+					Reviewers:  []string{},
+				},
+			}
+
+			t.Run("Create", func(t *testing.T) {
+				for _, a := range actions {
+					want := a.Clone()
+					have := a
+
+					if err := s.CreateChangesetAction(ctx, have); err != nil {
 						t.Fatal(err)
 					}
 
-					if diff := cmp.Diff(status, tc.want); diff != "" {
-						t.Fatalf("wrong diff: %s", diff)
+					if have.ID == 0 {
+						t.Fatal("ID should not be zero")
+					}
+
+					want.ID = have.ID
+					want.CreatedAt = now
+					want.UpdatedAt = now
+
+					if diff := cmp.Diff(have, want); diff != "" {
+						t.Fatal(diff)
 					}
 				}
 			})
 
-			t.Run("ResetFailedChangesetJobs", func(t *testing.T) {
-				campaignID := 9999
-				jobs := []*cmpgn.ChangesetJob{
-					// completed, no errors
-					{StartedAt: now, FinishedAt: now, ChangesetID: 23},
-					// completed, error
-					{StartedAt: now, FinishedAt: now, Error: "error1"},
-					// completed, another error
-					{StartedAt: now, FinishedAt: now, Error: "error2"},
+			t.Run("Get", func(t *testing.T) {
+				want := actions[1]
+				have, err := s.GetChangesetAction(ctx, GetChangesetActionOpts{ID: want.ID})
+				if err != nil {
+					t.Fatal(err)
 				}
 
-				for i, j := range jobs {
-					j.CampaignID = int64(campaignID)
-					j.PatchID = int64(i)
-
-					err := s.CreateChangesetJob(ctx, j)
-					if err != nil {
-						t.Fatal(err)
-					}
-
+				if diff := cmp.Diff(have, want); diff != "" {
+					t.Fatal(diff)
 				}
 
-				mustReset := map[int64]bool{
-					jobs[1].ID: true,
-					jobs[2].ID: true,
+				_, err = s.GetChangesetAction(ctx, GetChangesetActionOpts{ID: 0xdeadbeef})
+				if have, want := err, ErrNoResults; have != want {
+					t.Fatalf("have err %v, want %v", have, want)
 				}
+			})
 
-				err := s.ResetFailedChangesetJobs(ctx, int64(campaignID))
+			t.Run("List", func(t *testing.T) {
+				have, next, err := s.ListChangesetActions(ctx, ListChangesetActionsOpts{CampaignID: campaign.ID, Limit: 1})
 				if err != nil {
 					t.Fatal(err)
 				}
 
-				have, _, err := s.ListChangesetJobs(ctx, ListChangesetJobsOpts{CampaignID: int64(campaignID)})
-				if err != nil {
-					t.Fatal(err)
+				if have, want := next, actions[1].ID; have != want {
+					t.Fatalf("have next %v, want %v", have, want)
 				}
 
-				if len(have) != len(jobs) {
-					t.Fatalf("wrong number of jobs returned. have=%d, want=%d", len(have), len(jobs))
+				if diff := cmp.Diff(have, actions[:1]); diff != "" {
+					t.Fatal(diff)
 				}
+			})
 
-				for _, job := range have {
-					if _, ok := mustReset[job.ID]; ok {
-						if job.Error != "" {
-							t.Errorf("job should be reset but has error: %+v", job.Error)
-						}
-						if !job.FinishedAt.IsZero() {
-							t.Errorf("job should be reset but has FinishedAt: %+v", job.FinishedAt)
-						}
-						if !job.StartedAt.IsZero() {
-							t.Errorf("job should be reset but has StartedAt: %+v", job.StartedAt)
-						}
-					} else {
-						if job.StartedAt.IsZero() {
-							t.Errorf("job should not be reset but StartedAt is zero: %+v", job.StartedAt)
-						}
-						if job.FinishedAt.IsZero() {
-							t.Errorf("job should not be reset but FinishedAt is zero: %+v", job.FinishedAt)
-						}
+			jobs := make([]*cmpgn.ChangesetActionJob, 0, 3)
+
+			t.Run("CreateJobs", func(t *testing.T) {
+				for i := 0; i < cap(jobs); i++ {
+					j := &cmpgn.ChangesetActionJob{
+						ChangesetActionID: actions[0].ID,
+						ChangesetID:       int64(i + 1),
+					}
+
+					want := j.Clone()
+					have := j
+
+					if err := s.CreateChangesetActionJob(ctx, have); err != nil {
+						t.Fatal(err)
+					}
+
+					if have.ID == 0 {
+						t.Fatal("ID should not be zero")
+					}
+
+					want.ID = have.ID
+					want.CreatedAt = now
+					want.UpdatedAt = now
+
+					if diff := cmp.Diff(have, want); diff != "" {
+						t.Fatal(diff)
 					}
+
+					jobs = append(jobs, j)
 				}
 			})
 
-			t.Run("ResetChangesetJobs", func(t *testing.T) {
-				campaignID := 12345
-				jobs := []*cmpgn.ChangesetJob{
-					// completed, no errors
-					{StartedAt: now, FinishedAt: now, ChangesetID: 12345},
-					// completed, error
-					{StartedAt: now, FinishedAt: now, Error: "error1"},
-				}
+			t.Run("UpdateJobs", func(t *testing.T) {
+				for i, j := range jobs[:2] {
+					j.StartedAt = now
+					j.FinishedAt = now
+					if i == 0 {
+						j.Error = "creating comment: action not supported by code host"
+					}
 
-				for i, j := range jobs {
-					j.CampaignID = int64(campaignID)
-					j.PatchID = int64(i)
+					now = now.Add(time.Second)
+					want := j
+					want.UpdatedAt = now
 
-					err := s.CreateChangesetJob(ctx, j)
-					if err != nil {
+					have := j.Clone()
+					if err := s.UpdateChangesetActionJob(ctx, have); err != nil {
 						t.Fatal(err)
 					}
 
+					if diff := cmp.Diff(have, want); diff != "" {
+						t.Fatal(diff)
+					}
 				}
+			})
 
-				err := s.ResetChangesetJobs(ctx, int64(campaignID))
+			t.Run("ListJobs", func(t *testing.T) {
+				have, _, err := s.ListChangesetActionJobs(ctx, ListChangesetActionJobsOpts{
+					ChangesetActionID: actions[0].ID,
+					OnlyWithError:     true,
+				})
 				if err != nil {
 					t.Fatal(err)
 				}
 
-				have, _, err := s.ListChangesetJobs(ctx, ListChangesetJobsOpts{CampaignID: int64(campaignID)})
+				if diff := cmp.Diff(have, jobs[:1]); diff != "" {
+					t.Fatal(diff)
+				}
+			})
+
+			t.Run("Status", func(t *testing.T) {
+				status, err := s.GetChangesetActionStatus(ctx, actions[0].ID)
 				if err != nil {
 					t.Fatal(err)
 				}
 
-				if len(have) != len(jobs) {
-					t.Fatalf("wrong number of jobs returned. have=%d, want=%d", len(have), len(jobs))
+				want := &cmpgn.BackgroundProcessStatus{
+					Total:         3,
+					Pending:       1,
+					Completed:     2,
+					ProcessState:  cmpgn.BackgroundProcessStateProcessing,
+					ProcessErrors: []string{"creating comment: action not supported by code host"},
 				}
+				if diff := cmp.Diff(status, want); diff != "" {
+					t.Fatal(diff)
+				}
+			})
+		})
 
-				for _, job := range have {
-					if job.Error != "" {
-						t.Errorf("job should be reset but has error: %+v", job.Error)
+		t.Run("CampaignNotifications", func(t *testing.T) {
+			campaign := &cmpgn.Campaign{
+				Name:            "Campaign notifications campaign",
+				AuthorID:        1,
+				NamespaceUserID: 1,
+			}
+			if err := s.CreateCampaign(ctx, campaign); err != nil {
+				t.Fatal(err)
+			}
+
+			subs := []*cmpgn.CampaignNotificationSubscription{
+				{
+					CampaignID:  campaign.ID,
+					UserID:      1,
+					Channel:     cmpgn.CampaignNotificationChannelWebhook,
+					URL:         "https://example.com/hook",
+					Secret:      "s3cr3t",
+					Transitions: []cmpgn.ChangesetTransition{},
+				},
+				{
+					CampaignID:  campaign.ID,
+					UserID:      2,
+					Channel:     cmpgn.CampaignNotificationChannelEmail,
+					Transitions: []cmpgn.ChangesetTransition{cmpgn.ChangesetTransitionMerged},
+				},
+			}
+
+			t.Run("CreateSubscriptions", func(t *testing.T) {
+				for _, sub := range subs {
+					want := sub.Clone()
+					have := sub
+
+					if err := s.CreateCampaignNotificationSubscription(ctx, have); err != nil {
+						t.Fatal(err)
 					}
-					if !job.FinishedAt.IsZero() {
-						t.Errorf("job should be reset but has FinishedAt: %+v", job.FinishedAt)
+
+					if have.ID == 0 {
+						t.Fatal("ID should not be zero")
 					}
-					if !job.StartedAt.IsZero() {
-						t.Errorf("job should be reset but has StartedAt: %+v", job.StartedAt)
+
+					want.ID = have.ID
+					want.CreatedAt = now
+					want.UpdatedAt = now
+
+					if diff := cmp.Diff(have, want); diff != "" {
+						t.Fatal(diff)
 					}
 				}
 			})
 
-			t.Run("GetLatestChangesetJobCreatedAt", func(t *testing.T) {
-				patchSet := &cmpgn.PatchSet{}
-				err := s.CreatePatchSet(ctx, patchSet)
+			t.Run("GetSubscription", func(t *testing.T) {
+				want := subs[1]
+				have, err := s.GetCampaignNotificationSubscription(ctx, GetCampaignNotificationSubscriptionOpts{ID: want.ID})
 				if err != nil {
 					t.Fatal(err)
 				}
 
-				campaign := testCampaign(123, patchSet.ID)
-				err = s.CreateCampaign(ctx, campaign)
-				if err != nil {
-					t.Fatal(err)
+				if diff := cmp.Diff(have, want); diff != "" {
+					t.Fatal(diff)
 				}
 
-				// Cleanup existing ChangesetJobs so we don't have interference
-				// between the previous tests and this one.
-				chjs, _, err := s.ListChangesetJobs(ctx, ListChangesetJobsOpts{Limit: -1})
-				if err != nil {
-					t.Fatal(err)
-				}
-				for _, j := range chjs {
-					err := s.DeleteChangesetJob(ctx, j.ID)
-					if err != nil {
-						t.Fatal(err)
-					}
+				_, err = s.GetCampaignNotificationSubscription(ctx, GetCampaignNotificationSubscriptionOpts{ID: 0xdeadbeef})
+				if have, want := err, ErrNoResults; have != want {
+					t.Fatalf("have err %v, want %v", have, want)
 				}
+			})
 
-				patch := &cmpgn.Patch{
-					PatchSetID: patchSet.ID,
-					BaseRef:    "x",
-					RepoID:     api.RepoID(123),
-				}
-				err = s.CreatePatch(ctx, patch)
+			t.Run("ListSubscriptions", func(t *testing.T) {
+				have, _, err := s.ListCampaignNotificationSubscriptions(ctx, ListCampaignNotificationSubscriptionsOpts{
+					CampaignID: campaign.ID,
+					UserID:     2,
+				})
 				if err != nil {
 					t.Fatal(err)
 				}
 
-				// 0 ChangesetJob, 1 Patches
-				have, err := s.GetLatestChangesetJobCreatedAt(ctx, campaign.ID)
-				if err != nil {
-					t.Fatal(err)
-				}
-				// Job counts don't match, should get back null
-				if !have.IsZero() {
-					t.Fatalf("publishedAt is not zero: %v", have)
+				if diff := cmp.Diff(have, subs[1:]); diff != "" {
+					t.Fatal(diff)
 				}
+			})
 
-				changesetJob1 := &cmpgn.ChangesetJob{
-					CampaignID: campaign.ID,
-					PatchID:    patch.ID,
-				}
-				err = s.CreateChangesetJob(ctx, changesetJob1)
+			changeset := &cmpgn.Changeset{
+				RepoID:              1,
+				CampaignIDs:         []int64{campaign.ID},
+				ExternalID:          "notifications-1",
+				ExternalServiceType: "github",
+				ExternalState:       cmpgn.ChangesetStateMerged,
+			}
+			if err := s.CreateChangesets(ctx, changeset); err != nil {
+				t.Fatal(err)
+			}
+
+			var notifications []*cmpgn.CampaignNotification
+
+			t.Run("Enqueue", func(t *testing.T) {
+				err := s.EnqueueCampaignNotifications(ctx, changeset.ID,
+					cmpgn.ChangesetTransitionMerged,
+					cmpgn.ChangesetTransitionChecksFailed,
+				)
 				if err != nil {
 					t.Fatal(err)
 				}
 
-				// 1 ChangesetJob, 1 Patches
-				have, err = s.GetLatestChangesetJobCreatedAt(ctx, campaign.ID)
+				notifications, _, err = s.ListCampaignNotifications(ctx, ListCampaignNotificationsOpts{
+					ChangesetID: changeset.ID,
+				})
 				if err != nil {
 					t.Fatal(err)
 				}
-				// Job counts are the same, we should get a valid time
-				if !have.Equal(clock()) {
-					t.Fatalf("want %v, got %v", clock(), have)
+
+				type delivery struct {
+					SubscriptionID int64
+					Transition     cmpgn.ChangesetTransition
 				}
 
-				// Create another round to ensure that we get the latest date
-				// when there are more than one
-				oldClock := clock
-				defer func() {
-					clock = oldClock
-				}()
-				clock = func() time.Time {
-					return oldClock().Add(5 * time.Minute)
+				have := make([]delivery, 0, len(notifications))
+				for _, n := range notifications {
+					have = append(have, delivery{n.SubscriptionID, n.Transition})
 				}
-				oldStore := s
-				defer func() {
-					s = oldStore
-				}()
-				s = NewStoreWithClock(tx, clock)
-				patch = &cmpgn.Patch{
-					PatchSetID: patchSet.ID,
-					BaseRef:    "x",
-					RepoID:     api.RepoID(123),
+				sort.Slice(have, func(i, j int) bool {
+					if have[i].SubscriptionID != have[j].SubscriptionID {
+						return have[i].SubscriptionID < have[j].SubscriptionID
+					}
+					return have[i].Transition < have[j].Transition
+				})
+
+				// The webhook is notified about all transitions, the email
+				// subscription only about merges.
+				want := []delivery{
+					{subs[0].ID, cmpgn.ChangesetTransitionChecksFailed},
+					{subs[0].ID, cmpgn.ChangesetTransitionMerged},
+					{subs[1].ID, cmpgn.ChangesetTransitionMerged},
 				}
-				err = s.CreatePatch(ctx, patch)
-				if err != nil {
-					t.Fatal(err)
+				if diff := cmp.Diff(have, want); diff != "" {
+					t.Fatal(diff)
 				}
+			})
 
-				// 1 ChangesetJob, 2 Patches
-				have, err = s.GetLatestChangesetJobCreatedAt(ctx, campaign.ID)
-				if err != nil {
+			t.Run("UpdateNotification", func(t *testing.T) {
+				n := notifications[0]
+				n.StartedAt = now
+				n.FinishedAt = now
+				n.Error = "posting webhook: connection refused"
+
+				now = now.Add(time.Second)
+				want := n
+				want.UpdatedAt = now
+
+				have := n.Clone()
+				if err := s.UpdateCampaignNotification(ctx, have); err != nil {
 					t.Fatal(err)
 				}
-				// Job counts don't match, should get back null
-				if !have.IsZero() {
-					t.Fatalf("publishedAt is not zero: %v", have)
-				}
 
-				// Add another changesetjob
-				changesetJob2 := &cmpgn.ChangesetJob{
-					CampaignID: campaign.ID,
-					PatchID:    patch.ID,
+				if diff := cmp.Diff(have, want); diff != "" {
+					t.Fatal(diff)
 				}
-				err = s.CreateChangesetJob(ctx, changesetJob2)
-				if err != nil {
+			})
+
+			t.Run("DeleteSubscription", func(t *testing.T) {
+				if err := s.DeleteCampaignNotificationSubscription(ctx, subs[0].ID); err != nil {
 					t.Fatal(err)
 				}
 
-				// 2 ChangesetJob, 2 Patches
-				have, err = s.GetLatestChangesetJobCreatedAt(ctx, campaign.ID)
+				_, err := s.GetCampaignNotificationSubscription(ctx, GetCampaignNotificationSubscriptionOpts{ID: subs[0].ID})
+				if have, want := err, ErrNoResults; have != want {
+					t.Fatalf("have err %v, want %v", have, want)
+				}
+
+				have, _, err := s.ListCampaignNotifications(ctx, ListCampaignNotificationsOpts{
+					SubscriptionID: subs[0].ID,
+				})
 				if err != nil {
 					t.Fatal(err)
 				}
-				// Job counts are the same, we should get a valid time
-				if !have.Equal(clock()) {
-					t.Fatalf("want %v, got %v", clock(), have)
+				if len(have) != 0 {
+					t.Fatalf("have %d notifications of deleted subscription, want 0", len(have))
 				}
 			})
-
 		})
 	}
 }
@@ -2941,6 +3870,76 @@ func testProcessChangesetJob(db *sql.DB) func(*testing.T) {
 				t.Errorf("Want %d, got %d", 1, rc)
 			}
 		})
+
+		t.Run("FailedPatchJobAttempts", func(t *testing.T) {
+			s := NewStoreWithClock(db, clock)
+
+			ps := &cmpgn.PatchSet{
+				UserID: user.ID,
+				Replacement: &cmpgn.Replacement{
+					Query: "repo:sourcegraph",
+					Kind:  cmpgn.ReplacementKindRegexp,
+					Match: "foo",
+				},
+			}
+			if err := s.CreatePatchSet(ctx, ps); err != nil {
+				t.Fatal(err)
+			}
+			job := &cmpgn.PatchJob{PatchSetID: ps.ID, RepoID: repo.ID}
+			if err := s.CreatePatchJob(ctx, job); err != nil {
+				t.Fatal(err)
+			}
+
+			process := func(ctx context.Context, s *Store, job cmpgn.PatchJob) error {
+				return errors.New("connection reset")
+			}
+
+			for i := 1; i <= MaxPatchJobAttempts; i++ {
+				ran, err := s.ProcessPendingPatchJobs(ctx, process)
+				if !ran {
+					t.Fatalf("attempt %d: process function should have run", i)
+				}
+				if err == nil || err.Error() != "connection reset" {
+					t.Fatalf("attempt %d: have error %v, want %q", i, err, "connection reset")
+				}
+
+				jobs, _, err := s.ListPatchJobs(ctx, ListPatchJobsOpts{PatchSetID: ps.ID})
+				if err != nil {
+					t.Fatal(err)
+				}
+				have := jobs[0]
+				if i < MaxPatchJobAttempts {
+					if !have.StartedAt.IsZero() || !have.FinishedAt.IsZero() || have.Error != "" {
+						t.Fatalf("attempt %d: job should be pending again, have %+v", i, have)
+					}
+				} else if have.FinishedAt.IsZero() || have.Error != "connection reset" {
+					t.Fatalf("attempt %d: job should have failed, have %+v", i, have)
+				}
+			}
+
+			// The failed job isn't attempted again.
+			ran, err := s.ProcessPendingPatchJobs(ctx, process)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if ran {
+				t.Fatal("process function should not have run")
+			}
+
+			status, err := s.GetPatchSetStatus(ctx, ps.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := &cmpgn.BackgroundProcessStatus{
+				Total:         1,
+				Completed:     1,
+				ProcessState:  cmpgn.BackgroundProcessStateErrored,
+				ProcessErrors: []string{"connection reset"},
+			}
+			if diff := cmp.Diff(status, want); diff != "" {
+				t.Fatal(diff)
+			}
+		})
 	}
 }
 
@@ -219,8 +219,9 @@ type ChangesetSyncer struct {
 	priorityNotify chan []int64
 
 	// Replaceable for testing
-	syncFunc func(ctx context.Context, id int64) error
-	clock    func() time.Time
+	syncFunc   func(ctx context.Context, id int64) error
+	importFunc func(ctx context.Context, q *campaigns.ChangesetImportQuery) ([]*campaigns.Changeset, error)
+	clock      func() time.Time
 
 	// cancel should be called to stop this syncer
 	cancel context.CancelFunc
@@ -232,6 +233,8 @@ type SyncStore interface {
 	ListChangesets(context.Context, ListChangesetsOpts) ([]*campaigns.Changeset, int64, error)
 	UpdateChangesets(ctx context.Context, cs ...*campaigns.Changeset) error
 	UpsertChangesetEvents(ctx context.Context, cs ...*campaigns.ChangesetEvent) error
+	ListChangesetImportQueries(context.Context, ListChangesetImportQueriesOpts) ([]*campaigns.ChangesetImportQuery, int64, error)
+	UpdateChangesetImportQuery(context.Context, *campaigns.ChangesetImportQuery) error
 	Transact(context.Context) (*Store, error)
 }
 
@@ -246,6 +249,14 @@ func (s *ChangesetSyncer) Run(ctx context.Context) {
 	if s.syncFunc == nil {
 		s.syncFunc = s.SyncChangesetByID
 	}
+	if s.importFunc == nil {
+		importer := &ChangesetImporter{
+			SyncStore:   s.SyncStore,
+			ReposStore:  s.ReposStore,
+			HTTPFactory: s.HTTPFactory,
+		}
+		s.importFunc = importer.Import
+	}
 	if s.clock == nil {
 		s.clock = time.Now
 	}
@@ -290,6 +301,9 @@ func (s *ChangesetSyncer) Run(ctx context.Context) {
 			if timer != nil {
 				timer.Stop()
 			}
+			// Import new changesets first so that they are part of the
+			// schedule.
+			s.importChangesets(ctx)
 			schedule, err := s.computeSchedule(ctx)
 			if err != nil {
 				log15.Error("Computing queue", "err", err)
@@ -329,8 +343,41 @@ func (s *ChangesetSyncer) Run(ctx context.Context) {
 var (
 	minSyncDelay = 2 * time.Minute
 	maxSyncDelay = 8 * time.Hour
+
+	// importInterval is how often the changeset import queries are run.
+	importInterval = 15 * time.Minute
 )
 
+// importChangesets runs the changeset import queries of the syncer's external
+// service that haven't been run in the last importInterval.
+func (s *ChangesetSyncer) importChangesets(ctx context.Context) {
+	qs, _, err := s.SyncStore.ListChangesetImportQueries(ctx, ListChangesetImportQueriesOpts{
+		ExternalServiceID: s.externalServiceID,
+		ImportedBefore:    s.clock().Add(-importInterval),
+		Limit:             -1,
+	})
+	if err != nil {
+		log15.Error("Listing changeset import queries", "err", err)
+		return
+	}
+
+	for _, q := range qs {
+		added, err := s.importFunc(ctx, q)
+		if err != nil {
+			log15.Error("Importing changesets", "query_id", q.ID, "err", err)
+			q.LastError = err.Error()
+		} else {
+			log15.Debug("Imported changesets", "query_id", q.ID, "count", len(added))
+			q.LastError = ""
+		}
+
+		q.LastImportedAt = s.clock()
+		if err := s.SyncStore.UpdateChangesetImportQuery(ctx, q); err != nil {
+			log15.Error("Updating changeset import query", "query_id", q.ID, "err", err)
+		}
+	}
+}
+
 // nextSync computes the time we want the next sync to happen.
 func nextSync(clock func() time.Time, h campaigns.ChangesetSyncData) time.Time {
 	lastSync := h.UpdatedAt
@@ -449,8 +496,9 @@ func (s *ChangesetSyncer) SyncChangesets(ctx context.Context, cs ...*campaigns.C
 // with the given ChangesetSources and updates them in the database.
 func SyncChangesetsWithSources(ctx context.Context, store SyncStore, bySource []*SourceChangesets) (err error) {
 	var (
-		events []*campaigns.ChangesetEvent
-		cs     []*campaigns.Changeset
+		events      []*campaigns.ChangesetEvent
+		cs          []*campaigns.Changeset
+		transitions = map[int64][]campaigns.ChangesetTransition{}
 	)
 
 	for _, s := range bySource {
@@ -476,9 +524,14 @@ func SyncChangesetsWithSources(ctx context.Context, store SyncStore, bySource []
 				c.Changeset.SetDeleted()
 			}
 
+			prev := *c.Changeset
 			csEvents := c.Events()
 			SetDerivedState(c.Changeset, csEvents)
 
+			if ts := ChangesetTransitions(&prev, c.Changeset); len(ts) > 0 {
+				transitions[c.Changeset.ID] = ts
+			}
+
 			events = append(events, csEvents...)
 			cs = append(cs, c.Changeset)
 		}
@@ -494,7 +547,17 @@ func SyncChangesetsWithSources(ctx context.Context, store SyncStore, bySource []
 		return err
 	}
 
-	return tx.UpsertChangesetEvents(ctx, events...)
+	if err = tx.UpsertChangesetEvents(ctx, events...); err != nil {
+		return err
+	}
+
+	for id, ts := range transitions {
+		if err = tx.EnqueueCampaignNotifications(ctx, id, ts...); err != nil {
+			return errors.Wrap(err, "enqueueing campaign notifications")
+		}
+	}
+
+	return nil
 }
 
 // GroupChangesetsBySource returns a slice of SourceChangesets in which the
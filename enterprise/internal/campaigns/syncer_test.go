@@ -13,6 +13,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/campaigns"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
 )
 
 func TestNextSync(t *testing.T) {
@@ -268,6 +269,70 @@ func TestSyncerRun(t *testing.T) {
 		}
 	})
 
+	t.Run("Import due", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		now := time.Now()
+		queries := []*campaigns.ChangesetImportQuery{
+			{ID: 1, ExternalServiceID: 4, Query: "author:renovate"},
+			{ID: 2, ExternalServiceID: 4, Query: "label:security"},
+		}
+		updated := make(chan *campaigns.ChangesetImportQuery, len(queries))
+		store := MockSyncStore{
+			listChangesetSyncData: func(ctx context.Context, opts ListChangesetSyncDataOpts) ([]campaigns.ChangesetSyncData, error) {
+				return []campaigns.ChangesetSyncData{}, nil
+			},
+			listImportQueries: func(ctx context.Context, opts ListChangesetImportQueriesOpts) ([]*campaigns.ChangesetImportQuery, int64, error) {
+				if have, want := opts.ExternalServiceID, int64(4); have != want {
+					t.Errorf("have external service %d, want %d", have, want)
+				}
+				if have, want := opts.ImportedBefore, now.Add(-importInterval); !have.Equal(want) {
+					t.Errorf("have imported before %v, want %v", have, want)
+				}
+				qs := make([]*campaigns.ChangesetImportQuery, 0, len(queries))
+				for _, q := range queries {
+					qs = append(qs, q.Clone())
+				}
+				return qs, 0, nil
+			},
+			updateImportQuery: func(ctx context.Context, q *campaigns.ChangesetImportQuery) error {
+				// Later ticks import again, we only look at the first one.
+				select {
+				case updated <- q:
+				default:
+				}
+				return nil
+			},
+		}
+		importFunc := func(ctx context.Context, q *campaigns.ChangesetImportQuery) ([]*campaigns.Changeset, error) {
+			if q.ID == 2 {
+				return nil, errors.New("rate limited")
+			}
+			return []*campaigns.Changeset{{ID: 1}}, nil
+		}
+		syncer := &ChangesetSyncer{
+			SyncStore:         store,
+			externalServiceID: 4,
+			scheduleInterval:  time.Millisecond,
+			importFunc:        importFunc,
+			clock:             func() time.Time { return now },
+		}
+		go syncer.Run(ctx)
+
+		for _, want := range []string{"", "rate limited"} {
+			select {
+			case q := <-updated:
+				if have := q.LastError; have != want {
+					t.Fatalf("query %d: have last error %q, want %q", q.ID, have, want)
+				}
+				if have := q.LastImportedAt; !have.Equal(now) {
+					t.Fatalf("query %d: have last imported at %v, want %v", q.ID, have, now)
+				}
+			case <-time.After(50 * time.Millisecond):
+				t.Fatal("Import not run")
+			}
+		}
+	})
 }
 
 func TestFilterSyncData(t *testing.T) {
@@ -458,6 +523,8 @@ type MockSyncStore struct {
 	listChangesets        func(context.Context, ListChangesetsOpts) ([]*campaigns.Changeset, int64, error)
 	updateChangesets      func(context.Context, ...*campaigns.Changeset) error
 	upsertChangesetEvents func(context.Context, ...*campaigns.ChangesetEvent) error
+	listImportQueries     func(context.Context, ListChangesetImportQueriesOpts) ([]*campaigns.ChangesetImportQuery, int64, error)
+	updateImportQuery     func(context.Context, *campaigns.ChangesetImportQuery) error
 	transact              func(context.Context) (*Store, error)
 }
 
@@ -481,6 +548,14 @@ func (m MockSyncStore) UpsertChangesetEvents(ctx context.Context, cs ...*campaig
 	return m.upsertChangesetEvents(ctx, cs...)
 }
 
+func (m MockSyncStore) ListChangesetImportQueries(ctx context.Context, opts ListChangesetImportQueriesOpts) ([]*campaigns.ChangesetImportQuery, int64, error) {
+	return m.listImportQueries(ctx, opts)
+}
+
+func (m MockSyncStore) UpdateChangesetImportQuery(ctx context.Context, q *campaigns.ChangesetImportQuery) error {
+	return m.updateImportQuery(ctx, q)
+}
+
 func (m MockSyncStore) Transact(ctx context.Context) (*Store, error) {
 	return m.transact(ctx)
 }
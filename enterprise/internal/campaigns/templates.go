@@ -0,0 +1,189 @@
+package campaigns
+
+import (
+	"context"
+	"path"
+	"regexp"
+	"strings"
+	"text/template"
+
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/campaigns"
+	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
+)
+
+// validTemplateParamName matches the names of CampaignTemplateParameters.
+// They need to be valid Go identifiers so that they can be referred to as
+// {{.Params.name}} in the templates of a CampaignTemplateSpec.
+var validTemplateParamName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// CampaignTemplateData is the data the templates of a CampaignTemplateSpec
+// are executed with. The repository fields are only set when rendering the
+// title and body of a single changeset.
+type CampaignTemplateData struct {
+	// Params are the values of the template's parameters.
+	Params map[string]string
+	// Repository is the full name of the repository, e.g.
+	// "github.com/sourcegraph/sourcegraph".
+	Repository string
+	// RepositoryName is the last path component of Repository, e.g.
+	// "sourcegraph".
+	RepositoryName string
+	// BaseRef is the abbreviated name of the branch the changeset is opened
+	// against, e.g. "master".
+	BaseRef string
+}
+
+// ValidateCampaignTemplateSpec returns an error if the given spec can't be
+// instantiated.
+func ValidateCampaignTemplateSpec(spec *campaigns.CampaignTemplateSpec) error {
+	if strings.TrimSpace(spec.ScopeQuery) == "" {
+		return errors.New("campaign template scope query must not be empty")
+	}
+	if strings.TrimSpace(spec.BranchPattern) == "" {
+		return errors.New("campaign template branch pattern must not be empty")
+	}
+	if strings.TrimSpace(spec.TitleTemplate) == "" {
+		return errors.New("campaign template title template must not be empty")
+	}
+
+	seen := make(map[string]bool, len(spec.Parameters))
+	for _, p := range spec.Parameters {
+		if !validTemplateParamName.MatchString(p.Name) {
+			return errors.Errorf("invalid campaign template parameter name %q", p.Name)
+		}
+		if seen[p.Name] {
+			return errors.Errorf("duplicate campaign template parameter %q", p.Name)
+		}
+		seen[p.Name] = true
+	}
+
+	for name, text := range map[string]string{
+		"branch pattern": spec.BranchPattern,
+		"title template": spec.TitleTemplate,
+		"body template":  spec.BodyTemplate,
+	} {
+		if _, err := parseCampaignTemplate(text); err != nil {
+			return errors.Wrapf(err, "invalid campaign template %s", name)
+		}
+	}
+
+	return nil
+}
+
+// ValidateChangesetOptions returns an error if the changesets of a campaign
+// can't be created with the given options.
+func ValidateChangesetOptions(opts *campaigns.ChangesetOptions) error {
+	if opts.TitleTemplate == "" && opts.BodyTemplate != "" {
+		return errors.New("changeset body template requires a title template")
+	}
+
+	for name, text := range map[string]string{
+		"title template": opts.TitleTemplate,
+		"body template":  opts.BodyTemplate,
+	} {
+		if _, err := parseCampaignTemplate(text); err != nil {
+			return errors.Wrapf(err, "invalid changeset %s", name)
+		}
+	}
+
+	for _, l := range opts.Labels {
+		if strings.TrimSpace(l) == "" {
+			return errors.New("changeset labels must not be blank")
+		}
+	}
+
+	for _, users := range [][]string{opts.Reviewers.Users, opts.Assignees.Users} {
+		for _, u := range users {
+			if strings.TrimSpace(u) == "" {
+				return errors.New("changeset reviewers and assignees must not be blank")
+			}
+		}
+	}
+
+	return nil
+}
+
+// ResolveCampaignTemplateParams returns the values of all parameters of the
+// given spec, using the defaults of the parameters that have no value in
+// values. It returns an error if a required parameter has no value or if
+// values contains unknown parameters.
+func ResolveCampaignTemplateParams(spec *campaigns.CampaignTemplateSpec, values map[string]string) (map[string]string, error) {
+	resolved := make(map[string]string, len(spec.Parameters))
+	for _, p := range spec.Parameters {
+		if v, ok := values[p.Name]; ok {
+			resolved[p.Name] = v
+			continue
+		}
+		if p.Default == nil {
+			return nil, errors.Errorf("missing value for required campaign template parameter %q", p.Name)
+		}
+		resolved[p.Name] = *p.Default
+	}
+
+	for name := range values {
+		if _, ok := resolved[name]; !ok {
+			return nil, errors.Errorf("unknown campaign template parameter %q", name)
+		}
+	}
+
+	return resolved, nil
+}
+
+// RenderCampaignTemplate executes the given template text with data.
+// Referring to parameters that don't exist is an error.
+func RenderCampaignTemplate(text string, data *CampaignTemplateData) (string, error) {
+	t, err := parseCampaignTemplate(text)
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	if err := t.Execute(&b, data); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+func parseCampaignTemplate(text string) (*template.Template, error) {
+	return template.New("").Option("missingkey=error").Parse(text)
+}
+
+// changesetTitleAndBody returns the title and body of the changeset the
+// given campaign opens in the repository, against baseRef. The templates of
+// the campaign's ChangesetOptions take precedence over the templates of the
+// CampaignTemplateVersion it was instantiated from. Without templates, the
+// campaign's name and description are used.
+func changesetTitleAndBody(ctx context.Context, store *Store, c *campaigns.Campaign, repo api.RepoName, baseRef string) (title, body string, err error) {
+	titleTemplate, bodyTemplate := c.ChangesetOptions.TitleTemplate, c.ChangesetOptions.BodyTemplate
+	if titleTemplate == "" && c.TemplateVersionID != 0 {
+		v, err := store.GetCampaignTemplateVersion(ctx, GetCampaignTemplateVersionOpts{ID: c.TemplateVersionID})
+		if err != nil {
+			return "", "", errors.Wrap(err, "getting campaign template version")
+		}
+		titleTemplate, bodyTemplate = v.Spec.TitleTemplate, v.Spec.BodyTemplate
+	}
+
+	if titleTemplate == "" {
+		return c.Name, c.Description, nil
+	}
+
+	data := &CampaignTemplateData{
+		Params:         c.TemplateParams,
+		Repository:     string(repo),
+		RepositoryName: path.Base(string(repo)),
+		BaseRef:        git.AbbreviateRef(baseRef),
+	}
+	if data.Params == nil {
+		data.Params = map[string]string{}
+	}
+
+	if title, err = RenderCampaignTemplate(titleTemplate, data); err != nil {
+		return "", "", errors.Wrap(err, "rendering changeset title")
+	}
+	if body, err = RenderCampaignTemplate(bodyTemplate, data); err != nil {
+		return "", "", errors.Wrap(err, "rendering changeset body")
+	}
+	return title, body, nil
+}
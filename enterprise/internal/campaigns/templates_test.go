@@ -0,0 +1,231 @@
+package campaigns
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sourcegraph/sourcegraph/internal/campaigns"
+)
+
+func TestValidateCampaignTemplateSpec(t *testing.T) {
+	valid := func() *campaigns.CampaignTemplateSpec {
+		return &campaigns.CampaignTemplateSpec{
+			Action:        `{"steps":[]}`,
+			ScopeQuery:    "repo:github.com/sourcegraph/",
+			BranchPattern: "upgrade-{{.Params.package}}",
+			TitleTemplate: "Upgrade {{.Params.package}} in {{.RepositoryName}}",
+			BodyTemplate:  "Upgrades {{.Params.package}} on {{.BaseRef}}.",
+			Parameters: []campaigns.CampaignTemplateParameter{
+				{Name: "package"},
+			},
+		}
+	}
+
+	for _, tc := range []struct {
+		name    string
+		mutate  func(*campaigns.CampaignTemplateSpec)
+		wantErr bool
+	}{
+		{
+			name:   "valid",
+			mutate: func(*campaigns.CampaignTemplateSpec) {},
+		},
+		{
+			name:    "empty scope query",
+			mutate:  func(s *campaigns.CampaignTemplateSpec) { s.ScopeQuery = " " },
+			wantErr: true,
+		},
+		{
+			name:    "empty branch pattern",
+			mutate:  func(s *campaigns.CampaignTemplateSpec) { s.BranchPattern = "" },
+			wantErr: true,
+		},
+		{
+			name:    "invalid title template",
+			mutate:  func(s *campaigns.CampaignTemplateSpec) { s.TitleTemplate = "Upgrade {{.Params.package" },
+			wantErr: true,
+		},
+		{
+			name: "invalid parameter name",
+			mutate: func(s *campaigns.CampaignTemplateSpec) {
+				s.Parameters = append(s.Parameters, campaigns.CampaignTemplateParameter{Name: "new-version"})
+			},
+			wantErr: true,
+		},
+		{
+			name: "duplicate parameter",
+			mutate: func(s *campaigns.CampaignTemplateSpec) {
+				s.Parameters = append(s.Parameters, campaigns.CampaignTemplateParameter{Name: "package"})
+			},
+			wantErr: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			spec := valid()
+			tc.mutate(spec)
+
+			err := ValidateCampaignTemplateSpec(spec)
+			if have, want := err != nil, tc.wantErr; have != want {
+				t.Errorf("wrong error. want error=%t, have=%v", want, err)
+			}
+		})
+	}
+}
+
+func TestResolveCampaignTemplateParams(t *testing.T) {
+	latest := "latest"
+	spec := &campaigns.CampaignTemplateSpec{
+		Parameters: []campaigns.CampaignTemplateParameter{
+			{Name: "package"},
+			{Name: "version", Default: &latest},
+		},
+	}
+
+	for _, tc := range []struct {
+		name    string
+		values  map[string]string
+		want    map[string]string
+		wantErr bool
+	}{
+		{
+			name:   "defaults",
+			values: map[string]string{"package": "eslint"},
+			want:   map[string]string{"package": "eslint", "version": "latest"},
+		},
+		{
+			name:   "all values",
+			values: map[string]string{"package": "eslint", "version": "7.0.0"},
+			want:   map[string]string{"package": "eslint", "version": "7.0.0"},
+		},
+		{
+			name:    "missing required",
+			values:  map[string]string{"version": "7.0.0"},
+			wantErr: true,
+		},
+		{
+			name:    "unknown",
+			values:  map[string]string{"package": "eslint", "registry": "npm"},
+			wantErr: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			have, err := ResolveCampaignTemplateParams(spec, tc.values)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatal("want error, have nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if diff := cmp.Diff(tc.want, have); diff != "" {
+				t.Fatal(diff)
+			}
+		})
+	}
+}
+
+func TestRenderCampaignTemplate(t *testing.T) {
+	data := &CampaignTemplateData{
+		Params:         map[string]string{"package": "eslint"},
+		Repository:     "github.com/sourcegraph/sourcegraph",
+		RepositoryName: "sourcegraph",
+		BaseRef:        "master",
+	}
+
+	for _, tc := range []struct {
+		name    string
+		text    string
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "params and repository",
+			text: "Upgrade {{.Params.package}} in {{.RepositoryName}} ({{.BaseRef}})",
+			want: "Upgrade eslint in sourcegraph (master)",
+		},
+		{
+			name: "full repository name",
+			text: "{{.Repository}}",
+			want: "github.com/sourcegraph/sourcegraph",
+		},
+		{
+			name:    "unknown param",
+			text:    "Upgrade {{.Params.version}}",
+			wantErr: true,
+		},
+		{
+			name:    "unknown field",
+			text:    "{{.Owner}}",
+			wantErr: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			have, err := RenderCampaignTemplate(tc.text, data)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("want error, have %q", have)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if have != tc.want {
+				t.Errorf("want %q, have %q", tc.want, have)
+			}
+		})
+	}
+}
+
+func TestValidateChangesetOptions(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		opts    campaigns.ChangesetOptions
+		wantErr bool
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name: "valid",
+			opts: campaigns.ChangesetOptions{
+				TitleTemplate: "Upgrade eslint in {{.RepositoryName}}",
+				BodyTemplate:  "Upgrades eslint on {{.BaseRef}}.",
+				Labels:        []string{"automation"},
+				Reviewers:     campaigns.ChangesetUsersRule{Users: []string{"sourcegraph/core"}, FromCodeOwners: true},
+				Draft:         true,
+			},
+		},
+		{
+			name:    "body without title",
+			opts:    campaigns.ChangesetOptions{BodyTemplate: "Upgrades eslint."},
+			wantErr: true,
+		},
+		{
+			name:    "invalid title template",
+			opts:    campaigns.ChangesetOptions{TitleTemplate: "Upgrade {{.RepositoryName"},
+			wantErr: true,
+		},
+		{
+			name:    "blank label",
+			opts:    campaigns.ChangesetOptions{Labels: []string{" "}},
+			wantErr: true,
+		},
+		{
+			name:    "blank assignee",
+			opts:    campaigns.ChangesetOptions{Assignees: campaigns.ChangesetUsersRule{Users: []string{""}}},
+			wantErr: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateChangesetOptions(&tc.opts)
+			if have, want := err != nil, tc.wantErr; have != want {
+				t.Errorf("wrong error. want error=%t, have=%v", want, err)
+			}
+		})
+	}
+}
@@ -164,12 +164,13 @@ func (h Webhook) upsertChangesetEvent(
 		ChangesetIDs: []int64{cs.ID},
 		Limit:        -1,
 	})
+	prev := *cs
 	SetDerivedState(cs, events)
 	if err := tx.UpdateChangesets(ctx, cs); err != nil {
 		return err
 	}
 
-	return nil
+	return tx.EnqueueCampaignNotifications(ctx, cs.ID, ChangesetTransitions(&prev, cs)...)
 }
 
 // GitHubWebhook receives GitHub organization webhook events that are
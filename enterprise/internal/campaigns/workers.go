@@ -12,10 +12,12 @@ import (
 	"github.com/inconshreveable/log15"
 	"github.com/opentracing/opentracing-go/log"
 	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
 	"github.com/sourcegraph/sourcegraph/cmd/repo-updater/repos"
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/campaigns"
 	"github.com/sourcegraph/sourcegraph/internal/env"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
 	"github.com/sourcegraph/sourcegraph/internal/trace"
 	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
@@ -28,7 +30,7 @@ var maxWorkers = env.Get("CAMPAIGNS_MAX_WORKERS", "8", "maximum number of reposi
 const defaultWorkerCount = 8
 
 // RunWorkers should be executed in a background goroutine and is responsible
-// for finding pending ChangesetJobs and executing them.
+// for finding pending ChangesetJobs and PatchJobs and executing them.
 // ctx should be canceled to terminate the function.
 func RunWorkers(ctx context.Context, s *Store, clock func() time.Time, gitClient GitserverClient, sourcer repos.Sourcer, backoffDuration time.Duration) {
 	workerCount, err := strconv.Atoi(maxWorkers)
@@ -71,6 +73,154 @@ func RunWorkers(ctx context.Context, s *Store, clock func() time.Time, gitClient
 	for i := 0; i < workerCount; i++ {
 		go worker()
 	}
+
+	processPatchJob := func(ctx context.Context, s *Store, job campaigns.PatchJob) error {
+		ps, err := s.GetPatchSet(ctx, GetPatchSetOpts{ID: job.PatchSetID})
+		if err != nil {
+			return errors.Wrap(err, "getting patch set")
+		}
+
+		if runErr := ExecPatchJob(ctx, clock, s, ps, &job); runErr != nil {
+			log15.Error("ExecPatchJob", "jobID", job.ID, "err", runErr)
+		}
+		// ExecPatchJob saves the error in the job row
+		return nil
+	}
+	patchWorker := func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			default:
+				didRun, err := s.ProcessPendingPatchJobs(context.Background(), processPatchJob)
+				if err != nil {
+					log15.Error("Running patch job", "err", err)
+				}
+				// Back off on error or when no jobs available
+				if err != nil || !didRun {
+					time.Sleep(backoffDuration)
+				}
+			}
+		}
+	}
+	for i := 0; i < workerCount; i++ {
+		go patchWorker()
+	}
+}
+
+// ExecPatchJob generates the Patch of the given PatchJob by applying the
+// Replacement of the PatchSet to the tip of the default branch of the
+// PatchJob's repository. No Patch is created if the Replacement doesn't
+// change the repository.
+func ExecPatchJob(
+	ctx context.Context,
+	clock func() time.Time,
+	store *Store,
+	ps *campaigns.PatchSet,
+	job *campaigns.PatchJob,
+) (err error) {
+	tr, ctx := trace.New(ctx, "service.ExecPatchJob", fmt.Sprintf("job_id: %d", job.ID))
+	defer func() {
+		tr.SetError(err)
+		tr.Finish()
+	}()
+	tr.LogFields(log.Int64("job_id", job.ID), log.Int64("patch_set_id", ps.ID))
+
+	if !job.FinishedAt.IsZero() {
+		log15.Info("PatchJob already finished", "id", job.ID)
+		return nil
+	}
+
+	defer func() {
+		if err != nil {
+			job.Error = err.Error()
+		}
+		job.FinishedAt = clock()
+
+		if e := store.UpdatePatchJob(ctx, job); e != nil {
+			if err == nil {
+				err = e
+			} else {
+				err = multierror.Append(err, e)
+			}
+		}
+	}()
+
+	job.StartedAt = clock()
+
+	if ps.Replacement == nil {
+		return errors.Errorf("patch set %d has no replacement", ps.ID)
+	}
+
+	reposStore := repos.NewDBStore(store.DB(), sql.TxOptions{})
+	rs, err := reposStore.ListRepos(ctx, repos.StoreListReposArgs{IDs: []api.RepoID{job.RepoID}})
+	if err != nil {
+		return err
+	}
+	if len(rs) != 1 {
+		return errors.Errorf("repo not found: %d", job.RepoID)
+	}
+	repo := gitserver.Repo{Name: api.RepoName(rs[0].Name)}
+
+	ref, _, exitCode, err := git.ExecSafe(ctx, repo, []string{"symbolic-ref", "HEAD"})
+	if err != nil {
+		return errors.Wrap(err, "resolving default branch")
+	}
+	if exitCode != 0 {
+		return errors.Errorf("resolving default branch of repository %q", repo.Name)
+	}
+	baseRef := strings.TrimSpace(string(ref))
+
+	commit, err := git.ResolveRevision(ctx, repo, nil, baseRef, &git.ResolveRevisionOptions{NoEnsureRevision: true})
+	if err != nil {
+		return errors.Wrap(err, "resolving default branch commit")
+	}
+
+	diff, err := replacementDiff(ctx, repo.Name, commit, ps.Replacement)
+	if err != nil {
+		return err
+	}
+	if diff == "" {
+		return nil
+	}
+
+	patch := &campaigns.Patch{
+		PatchSetID: ps.ID,
+		RepoID:     job.RepoID,
+		Rev:        commit,
+		BaseRef:    baseRef,
+		Diff:       diff,
+	}
+	if err = store.CreatePatch(ctx, patch); err != nil {
+		return err
+	}
+
+	job.PatchID = patch.ID
+	return nil
+}
+
+// campaignCommitter returns the campaigns.committer setting of the
+// campaign's namespace. If it's not set, the returned committer is empty and
+// gitserver falls back to the author.
+func campaignCommitter(ctx context.Context, c *campaigns.Campaign) (schema.CampaignsCommitter, error) {
+	var subject api.SettingsSubject
+	switch {
+	case c.NamespaceUserID != 0:
+		subject.User = &c.NamespaceUserID
+	case c.NamespaceOrgID != 0:
+		subject.Org = &c.NamespaceOrgID
+	default:
+		return schema.CampaignsCommitter{}, nil
+	}
+
+	settings, err := backend.Configuration.GetForSubject(ctx, subject)
+	if err != nil {
+		return schema.CampaignsCommitter{}, err
+	}
+	if settings.CampaignsCommitter == nil {
+		return schema.CampaignsCommitter{}, nil
+	}
+	return *settings.CampaignsCommitter, nil
 }
 
 // ExecChangesetJob will execute the given ChangesetJob for the given campaign.
@@ -155,6 +305,11 @@ func ExecChangesetJob(
 		ensureUniqueRef = false
 	}
 
+	committer, err := campaignCommitter(ctx, c)
+	if err != nil {
+		return errors.Wrap(err, "getting campaign committer")
+	}
+
 	ref, err := gitClient.CreateCommitFromPatch(ctx, protocol.CreateCommitFromPatchRequest{
 		Repo:       api.RepoName(repo.Name),
 		BaseCommit: patch.Rev,
@@ -164,10 +319,12 @@ func ExecChangesetJob(
 		TargetRef: branch,
 		UniqueRef: ensureUniqueRef,
 		CommitInfo: protocol.PatchCommitInfo{
-			Message:     c.Name,
-			AuthorName:  "Sourcegraph Bot",
-			AuthorEmail: "campaigns@sourcegraph.com",
-			Date:        job.CreatedAt,
+			Message:        c.Name,
+			AuthorName:     "Sourcegraph Bot",
+			AuthorEmail:    "campaigns@sourcegraph.com",
+			CommitterName:  committer.Name,
+			CommitterEmail: committer.Email,
+			Date:           job.CreatedAt,
 		},
 		// We use unified diffs, not git diffs, which means they're missing the
 		// `a/` and `/b` filename prefixes. `-p0` tells `git apply` to not
@@ -194,6 +351,13 @@ func ExecChangesetJob(
 	}
 	job.Branch = ref
 
+	// We remember the commit we pushed, so that the changeset is only rebased
+	// later on if nobody else pushed to its branch in the meantime.
+	pushed, err := git.ResolveRevision(ctx, gitserver.Repo{Name: api.RepoName(repo.Name)}, nil, ref, &git.ResolveRevisionOptions{NoEnsureRevision: true})
+	if err != nil {
+		return errors.Wrap(err, "resolving pushed commit")
+	}
+
 	var externalService *repos.ExternalService
 	{
 		args := repos.StoreListExternalServicesArgs{IDs: repo.ExternalServiceIDs()}
@@ -243,15 +407,52 @@ func ExecChangesetJob(
 		baseRef = patch.BaseRef
 	}
 
+	title, body, err := changesetTitleAndBody(ctx, store, c, api.RepoName(repo.Name), baseRef)
+	if err != nil {
+		return err
+	}
+
+	// The owners of the changed files are computed at most once, even if both
+	// the reviewers and the assignees are derived from them.
+	var (
+		owners     []string
+		ownersDone bool
+	)
+	codeOwners := func() (_ []string, err error) {
+		if !ownersDone {
+			owners, err = changesetCodeOwners(ctx, api.RepoName(repo.Name), patch.Rev, patch.Diff)
+			if err != nil {
+				return nil, errors.Wrap(err, "determining code owners")
+			}
+			ownersDone = true
+		}
+		return owners, nil
+	}
+
+	opts := c.ChangesetOptions
+	reviewers, err := changesetUsers(opts.Reviewers, codeOwners)
+	if err != nil {
+		return err
+	}
+	assignees, err := changesetUsers(opts.Assignees, codeOwners)
+	if err != nil {
+		return err
+	}
+
 	cs := repos.Changeset{
-		Title:   c.Name,
-		Body:    c.Description,
-		BaseRef: baseRef,
-		HeadRef: git.EnsureRefPrefix(ref),
-		Repo:    repo,
+		Title:     title,
+		Body:      body,
+		BaseRef:   baseRef,
+		HeadRef:   git.EnsureRefPrefix(ref),
+		Labels:    opts.Labels,
+		Reviewers: reviewers,
+		Assignees: assignees,
+		Draft:     opts.Draft,
+		Repo:      repo,
 		Changeset: &campaigns.Changeset{
-			RepoID:      repo.ID,
-			CampaignIDs: []int64{job.CampaignID},
+			RepoID:       repo.ID,
+			CampaignIDs:  []int64{job.CampaignID},
+			PushedCommit: pushed,
 		},
 	}
 
@@ -305,6 +506,7 @@ func ExecChangesetJob(
 		SetDerivedState(clone, events)
 
 		clone.CampaignIDs = append(clone.CampaignIDs, job.CampaignID)
+		clone.PushedCommit = pushed
 
 		if err = store.UpdateChangesets(ctx, clone); err != nil {
 			return err
@@ -10,7 +10,9 @@ import (
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
 	"github.com/sourcegraph/sourcegraph/cmd/repo-updater/repos"
+	"github.com/sourcegraph/sourcegraph/internal/api"
 	cmpgn "github.com/sourcegraph/sourcegraph/internal/campaigns"
 	"github.com/sourcegraph/sourcegraph/internal/db/dbconn"
 	"github.com/sourcegraph/sourcegraph/internal/db/dbtest"
@@ -29,6 +31,12 @@ func TestExecChangesetJob(t *testing.T) {
 
 	dbtesting.SetupGlobalTestDB(t)
 
+	const pushedCommit = api.CommitID("b69072d5f687b31b9f6ae3ceafdc24c259c4b9ec")
+	git.Mocks.ResolveRevision = func(spec string, opt *git.ResolveRevisionOptions) (api.CommitID, error) {
+		return pushedCommit, nil
+	}
+	defer func() { git.Mocks.ResolveRevision = nil }()
+
 	tests := []struct {
 		name string
 
@@ -147,6 +155,7 @@ func TestExecChangesetJob(t *testing.T) {
 				ExternalState:       cmpgn.ChangesetStateOpen,
 				ExternalReviewState: cmpgn.ChangesetReviewStatePending,
 				ExternalCheckState:  cmpgn.ChangesetCheckStateUnknown,
+				PushedCommit:        pushedCommit,
 				CreatedAt:           now,
 				UpdatedAt:           now,
 			}
@@ -171,6 +180,55 @@ func TestExecChangesetJob(t *testing.T) {
 	}
 }
 
+func TestCampaignCommitter(t *testing.T) {
+	ctx := context.Background()
+
+	db.Mocks.Settings.GetLatest = func(ctx context.Context, subject api.SettingsSubject) (*api.Settings, error) {
+		switch {
+		case subject.User != nil && *subject.User == 1:
+			return &api.Settings{Contents: `{
+				// Comments are allowed in settings.
+				"campaigns.committer": {"name": "Alice", "email": "alice@example.com"}
+			}`}, nil
+		case subject.Org != nil && *subject.Org == 2:
+			return &api.Settings{Contents: `{"campaigns.committer": {"name": "ACME", "email": "bot@acme.com"}}`}, nil
+		}
+		return nil, nil
+	}
+	defer func() { db.Mocks.Settings = db.MockSettings{} }()
+
+	for _, tc := range []struct {
+		name     string
+		campaign *cmpgn.Campaign
+		want     schema.CampaignsCommitter
+	}{
+		{
+			name:     "user namespace",
+			campaign: &cmpgn.Campaign{NamespaceUserID: 1},
+			want:     schema.CampaignsCommitter{Name: "Alice", Email: "alice@example.com"},
+		},
+		{
+			name:     "org namespace",
+			campaign: &cmpgn.Campaign{NamespaceOrgID: 2},
+			want:     schema.CampaignsCommitter{Name: "ACME", Email: "bot@acme.com"},
+		},
+		{
+			name:     "no settings",
+			campaign: &cmpgn.Campaign{NamespaceUserID: 3},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			have, err := campaignCommitter(ctx, tc.campaign)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tc.want, have); diff != "" {
+				t.Fatal(diff)
+			}
+		})
+	}
+}
+
 const testDiff = `diff --git foobar.c foobar.c
 index d75b080..cf04b5b 100644
 --- foobar.c
@@ -237,6 +295,24 @@ func (s fakeChangesetSource) LoadChangesets(ctx context.Context, cs ...*repos.Ch
 func (s fakeChangesetSource) CloseChangeset(ctx context.Context, c *repos.Changeset) error {
 	return fakeNotImplemented
 }
+func (s fakeChangesetSource) MergeChangeset(ctx context.Context, c *repos.Changeset, method cmpgn.ChangesetMergeMethod) error {
+	return fakeNotImplemented
+}
+func (s fakeChangesetSource) CreateComment(ctx context.Context, c *repos.Changeset, body string) error {
+	return fakeNotImplemented
+}
+func (s fakeChangesetSource) AddLabels(ctx context.Context, c *repos.Changeset, labels []string) error {
+	return fakeNotImplemented
+}
+func (s fakeChangesetSource) RemoveLabels(ctx context.Context, c *repos.Changeset, labels []string) error {
+	return fakeNotImplemented
+}
+func (s fakeChangesetSource) RequestReviewers(ctx context.Context, c *repos.Changeset, reviewers []string) error {
+	return fakeNotImplemented
+}
+func (s fakeChangesetSource) RerunFailedChecks(ctx context.Context, c *repos.Changeset) error {
+	return fakeNotImplemented
+}
 
 func createGitHubRepo(t *testing.T, ctx context.Context, now time.Time, s *Store) (*repos.Repo, *repos.ExternalService) {
 	t.Helper()
@@ -5,6 +5,7 @@ package actor
 import (
 	"context"
 	"fmt"
+	"regexp"
 	"strconv"
 
 	"github.com/sourcegraph/sourcegraph/internal/trace"
@@ -24,6 +25,19 @@ type Actor struct {
 	// to selectively display a logout link. (If the actor wasn't authenticated with a session
 	// cookie, logout would be ineffective.)
 	FromSessionCookie bool `json:"-"`
+
+	// SessionID is the ID of the recorded session (see db.UserSessions) that the session cookie
+	// belongs to, if the actor was authenticated with a session cookie.
+	SessionID int64 `json:"-"`
+
+	// Scopes is the list of access token scopes the actor is restricted to, if the actor was
+	// authenticated with an access token that doesn't have the "user:all" scope. It is nil for
+	// actors that are not restricted.
+	Scopes []string `json:"-"`
+
+	// RepoPattern, if non-nil, restricts the repositories the actor can access to those whose
+	// names match it. It is set for actors authenticated with a repository-restricted access token.
+	RepoPattern *regexp.Regexp `json:"-"`
 }
 
 // FromUser returns an actor corresponding to a user
@@ -41,6 +55,20 @@ func (a *Actor) IsAuthenticated() bool {
 	return a != nil && a.UID != 0
 }
 
+// HasScope reports whether the actor is allowed to perform actions requiring the given access
+// token scope. Actors that are not restricted to a list of scopes have all scopes.
+func (a *Actor) HasScope(scope string) bool {
+	if a == nil || a.Scopes == nil {
+		return true
+	}
+	for _, s := range a.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
 type key int
 
 const actorKey key = iota
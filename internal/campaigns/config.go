@@ -2,6 +2,7 @@ package campaigns
 
 import (
 	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/secret"
 )
 
 func init() {
@@ -9,6 +10,11 @@ func init() {
 		if c.AutomationReadAccessEnabled != nil {
 			problems = append(problems, conf.NewSiteProblem("The `automation.readAccess.enabled` property was renamed to `campaigns.readAccess.enabled`. Use that new property name instead. The old name is deprecated and will be removed in a future release."))
 		}
+		if key := c.CampaignsSigningKey; key != nil {
+			if _, err := secret.Decrypt(key.EncryptedKey); err != nil {
+				problems = append(problems, conf.NewSiteProblem("The `campaigns.signingKey` can't be decrypted: "+err.Error()+". Use the `encryptCampaignsSigningKey` GraphQL mutation to encrypt the key with the SRC_SECRET_KEY of this instance."))
+			}
+		}
 		return
 	})
 }
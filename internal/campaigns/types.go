@@ -1,7 +1,9 @@
 package campaigns
 
 import (
+	"net/url"
 	"reflect"
+	"regexp"
 	"strconv"
 	"strings"
 	"time"
@@ -37,6 +39,10 @@ type PatchSet struct {
 
 	UserID int32
 
+	// Replacement is only set for PatchSets whose Patches are generated on the
+	// server by PatchJobs.
+	Replacement *Replacement
+
 	CreatedAt time.Time
 	UpdatedAt time.Time
 }
@@ -44,9 +50,97 @@ type PatchSet struct {
 // Clone returns a clone of a PatchSet.
 func (c *PatchSet) Clone() *PatchSet {
 	cc := *c
+	if c.Replacement != nil {
+		r := *c.Replacement
+		cc.Replacement = &r
+	}
 	return &cc
 }
 
+// ReplacementKind defines the possible kinds of a Replacement.
+type ReplacementKind string
+
+// ReplacementKind constants.
+const (
+	ReplacementKindRegexp ReplacementKind = "REGEXP"
+	ReplacementKindComby  ReplacementKind = "COMBY"
+)
+
+// Valid returns true if the given ReplacementKind is valid.
+func (k ReplacementKind) Valid() bool {
+	switch k {
+	case ReplacementKindRegexp, ReplacementKindComby:
+		return true
+	default:
+		return false
+	}
+}
+
+// A Replacement describes how the Patches of a PatchSet are generated on the
+// server: Match is replaced with Rewrite in the files of every repository
+// matched by the search Query, at the tip of its default branch.
+type Replacement struct {
+	Query string          `json:"query"`
+	Kind  ReplacementKind `json:"kind"`
+
+	// Match is a regular expression for ReplacementKindRegexp and a comby
+	// match template for ReplacementKindComby.
+	Match string `json:"match"`
+	// Rewrite is a regexp replacement string (that can refer to submatches
+	// with $1) for ReplacementKindRegexp and a comby rewrite template for
+	// ReplacementKindComby.
+	Rewrite string `json:"rewrite"`
+
+	// FileExtension, if set, limits the replacement to files with the given
+	// extension (e.g. ".go").
+	FileExtension string `json:"fileExtension,omitempty"`
+}
+
+// Validate returns an error if the Replacement can't be executed.
+func (r *Replacement) Validate() error {
+	if strings.TrimSpace(r.Query) == "" {
+		return errors.New("replacement query must not be empty")
+	}
+	if !r.Kind.Valid() {
+		return errors.Errorf("invalid replacement kind %q", r.Kind)
+	}
+	if r.Match == "" {
+		return errors.New("replacement match must not be empty")
+	}
+	if r.Kind == ReplacementKindRegexp {
+		if _, err := regexp.Compile(r.Match); err != nil {
+			return errors.Wrap(err, "invalid replacement match")
+		}
+	}
+	return nil
+}
+
+// A PatchJob is the generation of a Patch for a PatchSet with a Replacement in
+// a single repository.
+type PatchJob struct {
+	ID         int64
+	PatchSetID int64
+	RepoID     api.RepoID
+
+	// Only set once the PatchJob has successfully finished and the
+	// Replacement produced a non-empty diff in the repository.
+	PatchID int64
+
+	Error string
+
+	StartedAt  time.Time
+	FinishedAt time.Time
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// Clone returns a clone of a PatchJob.
+func (j *PatchJob) Clone() *PatchJob {
+	jj := *j
+	return &jj
+}
+
 // A Patch is the application of a CampaignType over PatchSet arguments in
 // a specific repository at a specific revision.
 type Patch struct {
@@ -83,15 +177,155 @@ type Campaign struct {
 	ChangesetIDs    []int64
 	PatchSetID      int64
 	ClosedAt        time.Time
+
+	// AutoMerge, if true, makes Sourcegraph merge the open changesets of the
+	// campaign with MergeMethod once they are approved and their checks
+	// passed, merging at most MergesPerHour changesets per hour. Zero
+	// MergesPerHour means no limit.
+	AutoMerge     bool
+	MergeMethod   ChangesetMergeMethod
+	MergesPerHour int32
+
+	// TemplateVersionID is the ID of the CampaignTemplateVersion the campaign
+	// was instantiated from, if any, and TemplateParams are the values of the
+	// template's parameters it was instantiated with.
+	TemplateVersionID int64
+	TemplateParams    map[string]string
+
+	// ChangesetOptions customize the changesets the campaign creates.
+	ChangesetOptions ChangesetOptions
 }
 
 // Clone returns a clone of a Campaign.
 func (c *Campaign) Clone() *Campaign {
 	cc := *c
 	cc.ChangesetIDs = c.ChangesetIDs[:len(c.ChangesetIDs):len(c.ChangesetIDs)]
+	if c.TemplateParams != nil {
+		cc.TemplateParams = make(map[string]string, len(c.TemplateParams))
+		for k, v := range c.TemplateParams {
+			cc.TemplateParams[k] = v
+		}
+	}
+	cc.ChangesetOptions = c.ChangesetOptions.Clone()
 	return &cc
 }
 
+// ChangesetOptions customize the changesets a Campaign creates on code hosts.
+type ChangesetOptions struct {
+	// TitleTemplate and BodyTemplate, if set, are rendered for every
+	// repository to produce the title and body of its changeset. They take
+	// precedence over the templates of the CampaignTemplateVersion the
+	// campaign was instantiated from and over its name and description.
+	TitleTemplate string `json:"titleTemplate,omitempty"`
+	BodyTemplate  string `json:"bodyTemplate,omitempty"`
+
+	// Labels are added to every changeset.
+	Labels []string `json:"labels,omitempty"`
+
+	// Reviewers and Assignees determine who is requested to review and who
+	// is assigned to every changeset.
+	Reviewers ChangesetUsersRule `json:"reviewers,omitempty"`
+	Assignees ChangesetUsersRule `json:"assignees,omitempty"`
+
+	// Draft, if true, creates changesets as drafts on code hosts that
+	// support them.
+	Draft bool `json:"draft,omitempty"`
+}
+
+// Clone returns a deep copy of o.
+func (o ChangesetOptions) Clone() ChangesetOptions {
+	oo := o
+	oo.Labels = cloneStrings(o.Labels)
+	oo.Reviewers.Users = cloneStrings(o.Reviewers.Users)
+	oo.Assignees.Users = cloneStrings(o.Assignees.Users)
+	return oo
+}
+
+// ChangesetUsersRule determines a set of code host users for a changeset.
+type ChangesetUsersRule struct {
+	// Users are code host usernames. On GitHub, "org/team" names a team.
+	Users []string `json:"users,omitempty"`
+	// FromCodeOwners, if true, adds the owners of the changed files, as
+	// defined by the CODEOWNERS file in the changeset's base revision.
+	FromCodeOwners bool `json:"fromCodeOwners,omitempty"`
+}
+
+func cloneStrings(ss []string) []string {
+	if ss == nil {
+		return nil
+	}
+	return append([]string(nil), ss...)
+}
+
+// A CampaignTemplate is a reusable, versioned description of a Campaign that
+// is stored in a user or org namespace. Its spec is stored in
+// CampaignTemplateVersions, of which LatestVersion is the most recent.
+type CampaignTemplate struct {
+	ID              int64
+	Name            string
+	Description     string
+	AuthorID        int32
+	NamespaceUserID int32
+	NamespaceOrgID  int32
+	LatestVersion   int32
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
+
+// Clone returns a clone of a CampaignTemplate.
+func (t *CampaignTemplate) Clone() *CampaignTemplate {
+	tt := *t
+	return &tt
+}
+
+// A CampaignTemplateVersion is an immutable revision of the spec of a
+// CampaignTemplate.
+type CampaignTemplateVersion struct {
+	ID         int64
+	TemplateID int64
+	Version    int32
+	Spec       CampaignTemplateSpec
+	AuthorID   int32
+	CreatedAt  time.Time
+}
+
+// Clone returns a clone of a CampaignTemplateVersion.
+func (v *CampaignTemplateVersion) Clone() *CampaignTemplateVersion {
+	vv := *v
+	vv.Spec.Parameters = append([]CampaignTemplateParameter(nil), v.Spec.Parameters...)
+	return &vv
+}
+
+// A CampaignTemplateSpec describes what a Campaign instantiated from a
+// CampaignTemplate does.
+//
+// BranchPattern, TitleTemplate and BodyTemplate are Go text/template
+// templates. They can refer to the values of the Parameters with
+// {{.Params.name}}. TitleTemplate and BodyTemplate are rendered for every
+// repository and can additionally refer to {{.Repository}}, {{.RepositoryName}}
+// and {{.BaseRef}}.
+type CampaignTemplateSpec struct {
+	// Action is the src-cli action definition that produces the patches of
+	// the campaign.
+	Action string `json:"action"`
+	// ScopeQuery is the search query that selects the repositories the
+	// campaign applies to.
+	ScopeQuery    string                      `json:"scopeQuery"`
+	BranchPattern string                      `json:"branchPattern"`
+	TitleTemplate string                      `json:"titleTemplate"`
+	BodyTemplate  string                      `json:"bodyTemplate"`
+	Parameters    []CampaignTemplateParameter `json:"parameters,omitempty"`
+}
+
+// A CampaignTemplateParameter is a parameter of a CampaignTemplateSpec whose
+// value is provided when the template is instantiated. Parameters without a
+// Default are required.
+type CampaignTemplateParameter struct {
+	Name        string  `json:"name"`
+	Description string  `json:"description,omitempty"`
+	Default     *string `json:"default,omitempty"`
+}
+
 // RemoveChangesetID removes the given id from the Campaigns ChangesetIDs slice.
 // If the id is not in ChangesetIDs calling this method doesn't have an effect.
 func (c *Campaign) RemoveChangesetID(id int64) {
@@ -126,6 +360,63 @@ func (s ChangesetState) Valid() bool {
 	}
 }
 
+// ChangesetRebaseState defines the possible states of rebasing a Changeset's
+// branch onto its moved base branch.
+type ChangesetRebaseState string
+
+// ChangesetRebaseState constants.
+const (
+	ChangesetRebaseStateUpToDate          ChangesetRebaseState = "UP_TO_DATE"
+	ChangesetRebaseStateNeedsManualRebase ChangesetRebaseState = "NEEDS_MANUAL_REBASE"
+)
+
+// Valid returns true if the given ChangesetRebaseState is valid.
+func (s ChangesetRebaseState) Valid() bool {
+	switch s {
+	case ChangesetRebaseStateUpToDate,
+		ChangesetRebaseStateNeedsManualRebase:
+		return true
+	default:
+		return false
+	}
+}
+
+// ChangesetMergeMethod defines how a Changeset is merged on the code host.
+type ChangesetMergeMethod string
+
+// ChangesetMergeMethod constants.
+const (
+	ChangesetMergeMethodMerge  ChangesetMergeMethod = "MERGE"
+	ChangesetMergeMethodSquash ChangesetMergeMethod = "SQUASH"
+	ChangesetMergeMethodRebase ChangesetMergeMethod = "REBASE"
+)
+
+// Valid returns true if the given ChangesetMergeMethod is valid.
+func (m ChangesetMergeMethod) Valid() bool {
+	switch m {
+	case ChangesetMergeMethodMerge,
+		ChangesetMergeMethodSquash,
+		ChangesetMergeMethodRebase:
+		return true
+	default:
+		return false
+	}
+}
+
+// A ChangesetMergeAttempt is the metadata of the ChangesetEvent recorded when
+// Sourcegraph merges a Changeset of a Campaign with AutoMerge enabled.
+type ChangesetMergeAttempt struct {
+	Method      ChangesetMergeMethod
+	Error       string
+	AttemptedAt time.Time
+}
+
+// Key is a unique key identifying this attempt in the context of its
+// Changeset.
+func (a *ChangesetMergeAttempt) Key() string {
+	return a.AttemptedAt.UTC().Format(time.RFC3339Nano)
+}
+
 // ChangesetLabel represents a label applied to a changeset
 type ChangesetLabel struct {
 	Name        string
@@ -225,6 +516,272 @@ func (s ChangesetCheckState) Valid() bool {
 	}
 }
 
+// A ChangesetImportQuery is a query in the search syntax of the code host
+// of an external service, whose matching changesets are periodically
+// imported into a Campaign.
+type ChangesetImportQuery struct {
+	ID                int64
+	CampaignID        int64
+	ExternalServiceID int64
+	Query             string
+	// LastImportedAt is the time the query was last run and LastError the
+	// error it failed with, if any.
+	LastImportedAt time.Time
+	LastError      string
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+// Clone returns a clone of a ChangesetImportQuery.
+func (q *ChangesetImportQuery) Clone() *ChangesetImportQuery {
+	qq := *q
+	return &qq
+}
+
+// ChangesetActionType defines the action a ChangesetAction performs on the
+// changesets of a Campaign.
+type ChangesetActionType string
+
+// ChangesetActionType constants.
+const (
+	ChangesetActionTypeComment           ChangesetActionType = "COMMENT"
+	ChangesetActionTypeAddLabels         ChangesetActionType = "ADD_LABELS"
+	ChangesetActionTypeRemoveLabels      ChangesetActionType = "REMOVE_LABELS"
+	ChangesetActionTypeRequestReviewers  ChangesetActionType = "REQUEST_REVIEWERS"
+	ChangesetActionTypeClose             ChangesetActionType = "CLOSE"
+	ChangesetActionTypeRerunFailedChecks ChangesetActionType = "RERUN_FAILED_CHECKS"
+)
+
+// Valid returns true if the given ChangesetActionType is valid.
+func (t ChangesetActionType) Valid() bool {
+	switch t {
+	case ChangesetActionTypeComment,
+		ChangesetActionTypeAddLabels,
+		ChangesetActionTypeRemoveLabels,
+		ChangesetActionTypeRequestReviewers,
+		ChangesetActionTypeClose,
+		ChangesetActionTypeRerunFailedChecks:
+		return true
+	default:
+		return false
+	}
+}
+
+// A ChangesetAction is an action performed by a user on all open changesets
+// of a Campaign, e.g. posting a comment. It's executed in the background by
+// one ChangesetActionJob per changeset.
+type ChangesetAction struct {
+	ID         int64
+	CampaignID int64
+	UserID     int32
+	Type       ChangesetActionType
+
+	// Body is the comment posted by COMMENT and CLOSE actions. It's
+	// optional for CLOSE actions.
+	Body string
+	// Labels are the labels added or removed by ADD_LABELS and
+	// REMOVE_LABELS actions.
+	Labels []string
+	// Reviewers are the users or "org/team" teams requested by
+	// REQUEST_REVIEWERS actions.
+	Reviewers []string
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// Clone returns a clone of a ChangesetAction.
+func (a *ChangesetAction) Clone() *ChangesetAction {
+	aa := *a
+	aa.Labels = append(a.Labels[:0:0], a.Labels...)
+	aa.Reviewers = append(a.Reviewers[:0:0], a.Reviewers...)
+	return &aa
+}
+
+// Validate returns an error if the ChangesetAction lacks the arguments its
+// Type requires.
+func (a *ChangesetAction) Validate() error {
+	switch a.Type {
+	case ChangesetActionTypeComment:
+		if strings.TrimSpace(a.Body) == "" {
+			return errors.New("comment body must not be blank")
+		}
+	case ChangesetActionTypeAddLabels, ChangesetActionTypeRemoveLabels:
+		if len(a.Labels) == 0 {
+			return errors.New("at least one label is required")
+		}
+	case ChangesetActionTypeRequestReviewers:
+		if len(a.Reviewers) == 0 {
+			return errors.New("at least one reviewer is required")
+		}
+	case ChangesetActionTypeClose, ChangesetActionTypeRerunFailedChecks:
+	default:
+		return errors.Errorf("invalid changeset action type %q", a.Type)
+	}
+	return nil
+}
+
+// A ChangesetActionJob is the execution of a ChangesetAction on a single
+// Changeset.
+type ChangesetActionJob struct {
+	ID                int64
+	ChangesetActionID int64
+	ChangesetID       int64
+
+	Error string
+
+	StartedAt  time.Time
+	FinishedAt time.Time
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// Clone returns a clone of a ChangesetActionJob.
+func (j *ChangesetActionJob) Clone() *ChangesetActionJob {
+	jj := *j
+	return &jj
+}
+
+// ChangesetTransition is a change of the derived state of a Changeset that
+// campaign notification subscriptions are notified about.
+type ChangesetTransition string
+
+// ChangesetTransition constants.
+const (
+	ChangesetTransitionMerged           ChangesetTransition = "MERGED"
+	ChangesetTransitionClosed           ChangesetTransition = "CLOSED"
+	ChangesetTransitionChecksFailed     ChangesetTransition = "CHECKS_FAILED"
+	ChangesetTransitionChangesRequested ChangesetTransition = "CHANGES_REQUESTED"
+)
+
+// Valid returns true if the given ChangesetTransition is valid.
+func (t ChangesetTransition) Valid() bool {
+	switch t {
+	case ChangesetTransitionMerged,
+		ChangesetTransitionClosed,
+		ChangesetTransitionChecksFailed,
+		ChangesetTransitionChangesRequested:
+		return true
+	default:
+		return false
+	}
+}
+
+// CampaignNotificationChannel defines how a CampaignNotificationSubscription
+// is notified.
+type CampaignNotificationChannel string
+
+// CampaignNotificationChannel constants.
+const (
+	// CampaignNotificationChannelWebhook posts a JSON payload, signed with
+	// the secret of the subscription, to its URL.
+	CampaignNotificationChannelWebhook CampaignNotificationChannel = "WEBHOOK"
+	// CampaignNotificationChannelSlack posts a message to the Slack incoming
+	// webhook URL of the subscription.
+	CampaignNotificationChannelSlack CampaignNotificationChannel = "SLACK"
+	// CampaignNotificationChannelEmail emails the user of the subscription.
+	CampaignNotificationChannelEmail CampaignNotificationChannel = "EMAIL"
+)
+
+// A CampaignNotificationSubscription subscribes a webhook, a Slack channel or
+// a user's email address to ChangesetTransitions of the changesets of a
+// Campaign.
+type CampaignNotificationSubscription struct {
+	ID         int64
+	CampaignID int64
+	// UserID is the user who created the subscription and, for EMAIL
+	// subscriptions, the user who is emailed.
+	UserID  int32
+	Channel CampaignNotificationChannel
+	// URL is the URL that WEBHOOK and SLACK subscriptions post to.
+	URL string
+	// Secret is the key WEBHOOK payloads are signed with, if any.
+	Secret string
+	// Transitions are the transitions the subscription is notified about.
+	// If empty, it's notified about all transitions.
+	Transitions []ChangesetTransition
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// Clone returns a clone of a CampaignNotificationSubscription.
+func (s *CampaignNotificationSubscription) Clone() *CampaignNotificationSubscription {
+	ss := *s
+	ss.Transitions = append(s.Transitions[:0:0], s.Transitions...)
+	return &ss
+}
+
+// Validate returns an error if the CampaignNotificationSubscription is
+// invalid.
+func (s *CampaignNotificationSubscription) Validate() error {
+	switch s.Channel {
+	case CampaignNotificationChannelWebhook, CampaignNotificationChannelSlack:
+		u, err := url.Parse(s.URL)
+		if err != nil {
+			return errors.Wrap(err, "invalid notification URL")
+		}
+		if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return errors.Errorf("notification URL %q must be an absolute HTTP(S) URL", s.URL)
+		}
+	case CampaignNotificationChannelEmail:
+		if s.URL != "" || s.Secret != "" {
+			return errors.New("email notifications have no URL or secret")
+		}
+	default:
+		return errors.Errorf("invalid notification channel %q", s.Channel)
+	}
+
+	if s.Secret != "" && s.Channel != CampaignNotificationChannelWebhook {
+		return errors.New("only webhook notifications can have a secret")
+	}
+
+	for _, t := range s.Transitions {
+		if !t.Valid() {
+			return errors.Errorf("invalid changeset transition %q", t)
+		}
+	}
+
+	return nil
+}
+
+// Notifies returns true if the subscription is notified about the given
+// ChangesetTransition.
+func (s *CampaignNotificationSubscription) Notifies(t ChangesetTransition) bool {
+	if len(s.Transitions) == 0 {
+		return true
+	}
+	for _, st := range s.Transitions {
+		if st == t {
+			return true
+		}
+	}
+	return false
+}
+
+// A CampaignNotification is the delivery of a ChangesetTransition of a
+// Changeset to a CampaignNotificationSubscription.
+type CampaignNotification struct {
+	ID             int64
+	SubscriptionID int64
+	ChangesetID    int64
+	Transition     ChangesetTransition
+
+	Error string
+
+	StartedAt  time.Time
+	FinishedAt time.Time
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// Clone returns a clone of a CampaignNotification.
+func (n *CampaignNotification) Clone() *CampaignNotification {
+	nn := *n
+	return &nn
+}
+
 // A ChangesetJob is the creation of a Changeset on an external host from a
 // local Patch for a given Campaign.
 type ChangesetJob struct {
@@ -282,6 +839,19 @@ type Changeset struct {
 	ExternalState       ChangesetState
 	ExternalReviewState ChangesetReviewState
 	ExternalCheckState  ChangesetCheckState
+
+	// RebaseState is the outcome of the last attempt to rebase the
+	// Changeset's branch after its base branch moved.
+	RebaseState ChangesetRebaseState
+	// BaseCommit is the commit of the base branch that the Changeset's patch
+	// was last applied to, or failed to apply to if RebaseState is
+	// ChangesetRebaseStateNeedsManualRebase. If empty, the patch was only
+	// applied to the revision it was created on.
+	BaseCommit api.CommitID
+	// PushedCommit is the commit that was last pushed to the Changeset's
+	// branch by its campaign. The Changeset is only rebased if its branch is
+	// still at this commit, so that commits pushed by others aren't lost.
+	PushedCommit api.CommitID
 }
 
 // Clone returns a clone of a Changeset.
@@ -321,6 +891,17 @@ func (c *Changeset) RemoveCampaignID(id int64) {
 	}
 }
 
+// HasCampaignID returns whether the given id is in the Changesets CampaignIDs
+// slice.
+func (c *Changeset) HasCampaignID(id int64) bool {
+	for _, cid := range c.CampaignIDs {
+		if cid == id {
+			return true
+		}
+	}
+	return false
+}
+
 // Title of the Changeset.
 func (c *Changeset) Title() (string, error) {
 	switch m := c.Metadata.(type) {
@@ -536,6 +1117,82 @@ func (c *Changeset) Labels() []ChangesetLabel {
 	}
 }
 
+// ReviewerNames returns the names of the users and the slugs of the teams
+// that were requested to review the Changeset or already reviewed it.
+func (c *Changeset) ReviewerNames() []string {
+	var names []string
+	switch m := c.Metadata.(type) {
+	case *github.PullRequest:
+		for _, r := range m.ReviewRequests.Nodes {
+			if r.RequestedReviewer.Login != "" {
+				names = append(names, r.RequestedReviewer.Login)
+			} else if r.RequestedTeam.Slug != "" {
+				names = append(names, r.RequestedTeam.Slug)
+			}
+		}
+		// Reviewers are removed from the review requests once they reviewed.
+		for _, ti := range m.TimelineItems {
+			if r, ok := ti.Item.(*github.PullRequestReview); ok {
+				names = append(names, r.Author.Login)
+			}
+		}
+	case *bitbucketserver.PullRequest:
+		for _, r := range m.Reviewers {
+			if r.User != nil {
+				names = append(names, r.User.Name)
+			}
+		}
+	}
+	return names
+}
+
+// AssigneeNames returns the names of the users assigned to the Changeset.
+func (c *Changeset) AssigneeNames() []string {
+	var names []string
+	if m, ok := c.Metadata.(*github.PullRequest); ok {
+		for _, a := range m.Assignees.Nodes {
+			names = append(names, a.Login)
+		}
+	}
+	return names
+}
+
+// IsDraft returns true if the Changeset is a draft.
+func (c *Changeset) IsDraft() bool {
+	m, ok := c.Metadata.(*github.PullRequest)
+	return ok && m.IsDraft
+}
+
+// MissingNames returns the names in want that aren't in have. Names are
+// compared case-insensitively.
+func MissingNames(have, want []string) []string {
+	return missingNames(have, want, func(name string) string { return name })
+}
+
+// MissingReviewers is like MissingNames, but compares the teams in want,
+// which are of the form "org/team", by their slug only, since code hosts
+// only allow teams of the organization owning the repository as reviewers.
+func MissingReviewers(have, want []string) []string {
+	return missingNames(have, want, func(name string) string {
+		return name[strings.LastIndexByte(name, '/')+1:]
+	})
+}
+
+func missingNames(have, want []string, key func(string) string) []string {
+	seen := make(map[string]bool, len(have))
+	for _, name := range have {
+		seen[strings.ToLower(name)] = true
+	}
+
+	var missing []string
+	for _, name := range want {
+		if !seen[strings.ToLower(key(name))] {
+			missing = append(missing, name)
+		}
+	}
+	return missing
+}
+
 // A ChangesetEvent is an event that happened in the lifetime
 // and context of a Changeset.
 type ChangesetEvent struct {
@@ -704,6 +1361,8 @@ func (e *ChangesetEvent) Timestamp() time.Time {
 		t = unixMilliToTime(int64(e.CreatedDate))
 	case *bitbucketserver.CommitStatus:
 		t = unixMilliToTime(int64(e.Status.DateAdded))
+	case *ChangesetMergeAttempt:
+		t = e.AttemptedAt
 	}
 
 	return t
@@ -1156,6 +1815,11 @@ func ChangesetEventKindFor(e interface{}) ChangesetEventKind {
 		return ChangesetEventKind("bitbucketserver:" + strings.ToLower(string(e.Action)))
 	case *bitbucketserver.CommitStatus:
 		return ChangesetEventKindBitbucketServerCommitStatus
+	case *ChangesetMergeAttempt:
+		if e.Error != "" {
+			return ChangesetEventKindMergeFailed
+		}
+		return ChangesetEventKindMergeSucceeded
 	default:
 		panic(errors.Errorf("unknown changeset event kind for %T", e))
 	}
@@ -1211,6 +1875,8 @@ func NewChangesetEventMetadata(k ChangesetEventKind) (interface{}, error) {
 		case ChangesetEventKindCheckRun:
 			return new(github.CheckRun), nil
 		}
+	case k == ChangesetEventKindMergeSucceeded, k == ChangesetEventKindMergeFailed:
+		return new(ChangesetMergeAttempt), nil
 	}
 	return nil, errors.Errorf("unknown changeset event kind %q", k)
 }
@@ -1252,6 +1918,9 @@ const (
 	ChangesetEventKindBitbucketServerCommented    ChangesetEventKind = "bitbucketserver:commented"
 	ChangesetEventKindBitbucketServerMerged       ChangesetEventKind = "bitbucketserver:merged"
 	ChangesetEventKindBitbucketServerCommitStatus ChangesetEventKind = "bitbucketserver:commit_status"
+
+	ChangesetEventKindMergeSucceeded ChangesetEventKind = "campaigns:merge_succeeded"
+	ChangesetEventKindMergeFailed    ChangesetEventKind = "campaigns:merge_failed"
 )
 
 // ChangesetSyncData represents data about the sync status of a changeset
@@ -256,3 +256,47 @@ func TestChangesetEvents(t *testing.T) {
 		})
 	}
 }
+
+func TestChangesetParticipants(t *testing.T) {
+	pr := &github.PullRequest{IsDraft: true}
+	pr.ReviewRequests.Nodes = []github.ReviewRequest{
+		{RequestedReviewer: github.Actor{Login: "alice"}},
+		{RequestedTeam: struct{ Slug string }{Slug: "frontend"}},
+	}
+	pr.Assignees.Nodes = []github.Actor{{Login: "carol"}}
+	pr.TimelineItems = []github.TimelineItem{
+		{Type: "PullRequestReview", Item: &github.PullRequestReview{Author: github.Actor{Login: "bob"}}},
+	}
+
+	c := &Changeset{Metadata: pr}
+	if diff := cmp.Diff([]string{"alice", "frontend", "bob"}, c.ReviewerNames()); diff != "" {
+		t.Errorf("wrong reviewers: %s", diff)
+	}
+	if diff := cmp.Diff([]string{"carol"}, c.AssigneeNames()); diff != "" {
+		t.Errorf("wrong assignees: %s", diff)
+	}
+	if !c.IsDraft() {
+		t.Error("changeset is not a draft")
+	}
+
+	bbs := &Changeset{Metadata: &bitbucketserver.PullRequest{
+		Reviewers: []bitbucketserver.Reviewer{{User: &bitbucketserver.User{Name: "dave"}}},
+	}}
+	if diff := cmp.Diff([]string{"dave"}, bbs.ReviewerNames()); diff != "" {
+		t.Errorf("wrong reviewers: %s", diff)
+	}
+	if names := bbs.AssigneeNames(); len(names) != 0 {
+		t.Errorf("unexpected assignees: %v", names)
+	}
+}
+
+func TestMissingNames(t *testing.T) {
+	have := []string{"alice", "Frontend", "area/web"}
+
+	if diff := cmp.Diff([]string{"bob", "acme/frontend"}, MissingNames(have, []string{"Alice", "bob", "area/web", "acme/frontend"})); diff != "" {
+		t.Errorf("wrong missing names: %s", diff)
+	}
+	if diff := cmp.Diff([]string{"bob"}, MissingReviewers(have, []string{"Alice", "bob", "acme/frontend"})); diff != "" {
+		t.Errorf("wrong missing reviewers: %s", diff)
+	}
+}
@@ -0,0 +1,50 @@
+package reposource
+
+import (
+	"net"
+	"strings"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/perforce"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+type Perforce struct {
+	*schema.PerforceConnection
+}
+
+var _ RepoSource = Perforce{}
+
+func (c Perforce) CloneURLToRepoName(cloneURL string) (repoName api.RepoName, err error) {
+	if !perforce.IsCloneURL(cloneURL) {
+		return "", nil
+	}
+
+	remote, err := perforce.ParseCloneURL(cloneURL)
+	if err != nil {
+		return "", err
+	}
+	if remote.Port != c.P4port {
+		return "", nil
+	}
+	return PerforceRepoName(c.RepositoryPathPattern, c.P4port, remote.DepotPath), nil
+}
+
+// PerforceRepoName returns the Sourcegraph name for a Perforce depot or stream
+// given the repositoryPathPattern and P4PORT (defined in the Perforce external
+// service config) and the depot path (e.g. "//engine/main/").
+func PerforceRepoName(repositoryPathPattern, p4port, depotPath string) api.RepoName {
+	if repositoryPathPattern == "" {
+		repositoryPathPattern = "{host}/{depot}"
+	}
+
+	host, _, err := net.SplitHostPort(p4port)
+	if err != nil {
+		host = p4port
+	}
+
+	return api.RepoName(strings.NewReplacer(
+		"{host}", host,
+		"{depot}", strings.Trim(depotPath, "/"),
+	).Replace(repositoryPathPattern))
+}
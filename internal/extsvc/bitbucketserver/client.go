@@ -440,13 +440,52 @@ func (c *Client) LoadPullRequest(ctx context.Context, pr *PullRequest) error {
 	return c.send(ctx, "GET", path, nil, nil, pr)
 }
 
+// PullRequestFilter filters the pull requests listed by Client.PullRequests.
+type PullRequestFilter struct {
+	// State is one of OPEN, DECLINED, MERGED or ALL. The API defaults to
+	// OPEN.
+	State string
+	// At, if set, filters the returned pull requests to those from
+	// (Direction OUTGOING) or into (Direction INCOMING) the given ref.
+	At        string
+	Direction string
+}
+
+// EncodeTo encodes the PullRequestFilter to the given url.Values.
+func (f PullRequestFilter) EncodeTo(qry url.Values) {
+	if f.State != "" {
+		qry.Set("state", f.State)
+	}
+
+	if f.At != "" {
+		qry.Set("at", f.At)
+		if f.Direction != "" {
+			qry.Set("direction", f.Direction)
+		}
+	}
+}
+
+// PullRequests retrieves a page of the pull requests of the given
+// repository, filtered by the given PullRequestFilter.
+func (c *Client) PullRequests(ctx context.Context, projectKey, repoSlug string, f PullRequestFilter, pageToken *PageToken) ([]*PullRequest, *PageToken, error) {
+	qry := make(url.Values)
+	f.EncodeTo(qry)
+
+	path := fmt.Sprintf("rest/api/1.0/projects/%s/repos/%s/pull-requests", projectKey, repoSlug)
+
+	var prs []*PullRequest
+	next, err := c.page(ctx, path, qry, pageToken, &prs)
+	return prs, next, err
+}
+
 type UpdatePullRequestInput struct {
 	PullRequestID string `json:"-"`
 	Version       int    `json:"version"`
 
-	Title       string `json:"title"`
-	Description string `json:"description"`
-	ToRef       Ref    `json:"toRef"`
+	Title       string     `json:"title"`
+	Description string     `json:"description"`
+	ToRef       Ref        `json:"toRef"`
+	Reviewers   []Reviewer `json:"-"`
 }
 
 func (c *Client) UpdatePullRequest(ctx context.Context, in *UpdatePullRequestInput) (*PullRequest, error) {
@@ -457,8 +496,16 @@ func (c *Client) UpdatePullRequest(ctx context.Context, in *UpdatePullRequestInp
 		in.PullRequestID,
 	)
 
+	payload := struct {
+		*UpdatePullRequestInput
+		Reviewers []reviewerInput `json:"reviewers,omitempty"`
+	}{
+		UpdatePullRequestInput: in,
+		Reviewers:              reviewerInputs(in.Reviewers),
+	}
+
 	pr := &PullRequest{}
-	return pr, c.send(ctx, "PUT", path, nil, in, pr)
+	return pr, c.send(ctx, "PUT", path, nil, payload, pr)
 }
 
 // ErrAlreadyExists is returned by Client.CreatePullRequest when a Pull Request
@@ -492,14 +539,15 @@ func (c *Client) CreatePullRequest(ctx context.Context, pr *PullRequest) error {
 	}
 
 	type requestBody struct {
-		Title       string `json:"title"`
-		Description string `json:"description"`
-		State       string `json:"state"`
-		Open        bool   `json:"open"`
-		Closed      bool   `json:"closed"`
-		FromRef     Ref    `json:"fromRef"`
-		ToRef       Ref    `json:"toRef"`
-		Locked      bool   `json:"locked"`
+		Title       string          `json:"title"`
+		Description string          `json:"description"`
+		State       string          `json:"state"`
+		Open        bool            `json:"open"`
+		Closed      bool            `json:"closed"`
+		FromRef     Ref             `json:"fromRef"`
+		ToRef       Ref             `json:"toRef"`
+		Locked      bool            `json:"locked"`
+		Reviewers   []reviewerInput `json:"reviewers,omitempty"`
 	}
 
 	// Bitbucket Server doesn't support GFM taskitems. But since we might add
@@ -516,6 +564,7 @@ func (c *Client) CreatePullRequest(ctx context.Context, pr *PullRequest) error {
 		FromRef:     pr.FromRef,
 		ToRef:       pr.ToRef,
 		Locked:      false,
+		Reviewers:   reviewerInputs(pr.Reviewers),
 	}
 
 	path := fmt.Sprintf(
@@ -562,6 +611,63 @@ func (c *Client) DeclinePullRequest(ctx context.Context, pr *PullRequest) error
 	return c.send(ctx, "POST", path, qry, nil, pr)
 }
 
+// MergePullRequest merges the given PullRequest with the merge strategy of
+// the given ID (e.g. "no-ff", "squash" or "rebase-no-ff"), returning an error
+// in case of failure. An empty strategy ID uses the repository's default merge
+// strategy.
+func (c *Client) MergePullRequest(ctx context.Context, pr *PullRequest, strategyID string) error {
+	if pr.ToRef.Repository.Slug == "" {
+		return errors.New("repository slug empty")
+	}
+
+	if pr.ToRef.Repository.Project.Key == "" {
+		return errors.New("project key empty")
+	}
+
+	path := fmt.Sprintf(
+		"rest/api/1.0/projects/%s/repos/%s/pull-requests/%d/merge",
+		pr.ToRef.Repository.Project.Key,
+		pr.ToRef.Repository.Slug,
+		pr.ID,
+	)
+
+	qry := url.Values{"version": {strconv.Itoa(pr.Version)}}
+
+	var payload interface{}
+	if strategyID != "" {
+		payload = struct {
+			StrategyID string `json:"strategyId"`
+		}{StrategyID: strategyID}
+	}
+
+	return c.send(ctx, "POST", path, qry, payload, pr)
+}
+
+// CreatePullRequestComment adds a general comment with the given text to the
+// given PullRequest, returning an error in case of failure.
+func (c *Client) CreatePullRequestComment(ctx context.Context, pr *PullRequest, text string) error {
+	if pr.ToRef.Repository.Slug == "" {
+		return errors.New("repository slug empty")
+	}
+
+	if pr.ToRef.Repository.Project.Key == "" {
+		return errors.New("project key empty")
+	}
+
+	path := fmt.Sprintf(
+		"rest/api/1.0/projects/%s/repos/%s/pull-requests/%d/comments",
+		pr.ToRef.Repository.Project.Key,
+		pr.ToRef.Repository.Slug,
+		pr.ID,
+	)
+
+	payload := struct {
+		Text string `json:"text"`
+	}{Text: text}
+
+	return c.send(ctx, "POST", path, nil, payload, nil)
+}
+
 // LoadPullRequestActivities loads the given PullRequest's timeline of activities,
 // returning an error in case of failure.
 func (c *Client) LoadPullRequestActivities(ctx context.Context, pr *PullRequest) (err error) {
@@ -1074,13 +1180,7 @@ type PullRequest struct {
 		Approved bool   `json:"approved"`
 		Status   string `json:"status"`
 	} `json:"author"`
-	Reviewers []struct {
-		User               *User  `json:"user"`
-		LastReviewedCommit string `json:"lastReviewedCommit"`
-		Role               string `json:"role"`
-		Approved           bool   `json:"approved"`
-		Status             string `json:"status"`
-	} `json:"reviewers"`
+	Reviewers    []Reviewer `json:"reviewers"`
 	Participants []struct {
 		User     *User  `json:"user"`
 		Role     string `json:"role"`
@@ -1101,6 +1201,37 @@ type PullRequest struct {
 	BuildStatuses []*BuildStatus `json:"buildstatuses,omitempty"`
 }
 
+// Reviewer is a user that is asked to review a PullRequest. Only the User's
+// Name needs to be set when creating or updating a PullRequest.
+type Reviewer struct {
+	User               *User  `json:"user"`
+	LastReviewedCommit string `json:"lastReviewedCommit"`
+	Role               string `json:"role"`
+	Approved           bool   `json:"approved"`
+	Status             string `json:"status"`
+}
+
+// reviewerInput is a Reviewer in requests that create or update a
+// PullRequest.
+type reviewerInput struct {
+	User struct {
+		Name string `json:"name"`
+	} `json:"user"`
+}
+
+func reviewerInputs(reviewers []Reviewer) []reviewerInput {
+	var inputs []reviewerInput
+	for _, r := range reviewers {
+		if r.User == nil {
+			continue
+		}
+		var in reviewerInput
+		in.User.Name = r.User.Name
+		inputs = append(inputs, in)
+	}
+	return inputs
+}
+
 // Activity is a union type of all supported pull request activity items.
 type Activity struct {
 	ID          int            `json:"id"`
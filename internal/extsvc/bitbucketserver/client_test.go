@@ -122,6 +122,45 @@ func TestUserFilters(t *testing.T) {
 	}
 }
 
+func TestPullRequestFilter(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		f    PullRequestFilter
+		qry  url.Values
+	}{
+		{
+			name: "empty",
+			qry:  url.Values{},
+		},
+		{
+			name: "state",
+			f:    PullRequestFilter{State: "ALL"},
+			qry:  url.Values{"state": []string{"ALL"}},
+		},
+		{
+			name: "at",
+			f:    PullRequestFilter{At: "refs/heads/master", Direction: "INCOMING"},
+			qry: url.Values{
+				"at":        []string{"refs/heads/master"},
+				"direction": []string{"INCOMING"},
+			},
+		},
+		{
+			name: "direction without at",
+			f:    PullRequestFilter{Direction: "OUTGOING"},
+			qry:  url.Values{},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			have := make(url.Values)
+			tc.f.EncodeTo(have)
+			if want := tc.qry; !reflect.DeepEqual(have, want) {
+				t.Error(cmp.Diff(have, want))
+			}
+		})
+	}
+}
+
 func TestClient_Users(t *testing.T) {
 	cli, save := NewTestClient(t, "Users", *update)
 	defer save()
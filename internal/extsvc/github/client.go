@@ -233,6 +233,9 @@ func (c *Client) do(ctx context.Context, token string, req *http.Request, result
 		err.Code = resp.StatusCode
 		return &err
 	}
+	if result == nil {
+		return nil
+	}
 	return json.NewDecoder(resp.Body).Decode(result)
 }
 
@@ -256,6 +259,25 @@ func (c *Client) listRepositories(ctx context.Context, requestURI string) ([]*Re
 	return repos, nil
 }
 
+// listRepositoriesUpdatedSince lists the repositories on the page at
+// requestURI, which must be sorted by most recently updated first, that were
+// updated after since. There is no next page once a repository that wasn't
+// updated after since is listed.
+func (c *Client) listRepositoriesUpdatedSince(ctx context.Context, requestURI string, since time.Time) (repos []*Repository, hasNextPage bool, err error) {
+	var restRepos []restRepository
+	if err := c.requestGet(ctx, "", requestURI, &restRepos); err != nil {
+		return nil, false, err
+	}
+	repos = make([]*Repository, 0, len(restRepos))
+	for _, restRepo := range restRepos {
+		if !restRepo.UpdatedAt.After(since) {
+			return repos, false, nil
+		}
+		repos = append(repos, convertRestRepo(restRepo))
+	}
+	return repos, len(restRepos) > 0, nil
+}
+
 // ListInstallationRepositories lists repositories on which the authenticated
 // GitHub App has been installed.
 func (c *Client) ListInstallationRepositories(ctx context.Context) ([]*Repository, error) {
@@ -293,6 +315,22 @@ func (c *Client) requestGet(ctx context.Context, token, requestURI string, resul
 	return c.do(ctx, token, req, result)
 }
 
+// requestPost sends a POST request with the JSON encoding of payload to the
+// REST API.
+func (c *Client) requestPost(ctx context.Context, token, requestURI string, payload, result interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest("POST", requestURI, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	return c.do(ctx, token, req, result)
+}
+
 func (c *Client) requestGraphQL(ctx context.Context, token, query string, vars map[string]interface{}, result interface{}) (err error) {
 	reqBody, err := json.Marshal(struct {
 		Query     string                 `json:"query"`
@@ -319,6 +357,9 @@ func (c *Client) requestGraphQL(ctx context.Context, token, query string, vars m
 	// Enable Checks API
 	// https://developer.github.com/v4/previews/#checks
 	req.Header.Add("Accept", "application/vnd.github.antiope-preview+json")
+	// Enable draft pull requests
+	// https://developer.github.com/v4/previews/#draft-pull-requests-preview
+	req.Header.Add("Accept", "application/vnd.github.shadow-cat-preview+json")
 	var respBody struct {
 		Data   json.RawMessage `json:"data"`
 		Errors graphqlErrors   `json:"errors"`
@@ -7,6 +7,7 @@ import (
 	"encoding/json"
 	"flag"
 	"fmt"
+	"io/ioutil"
 	"net/http"
 	"net/url"
 	"os"
@@ -338,6 +339,155 @@ func TestClient_GetAuthenticatedUserOrgs(t *testing.T) {
 	)
 }
 
+func TestClient_SearchPullRequests(t *testing.T) {
+	mock := mockHTTPResponseBody{
+		responseBody: `{"data": {"search": {
+  "nodes": [
+    {"number": 12, "repository": {"id": "MDEwOlJlcG9zaXRvcnkx", "nameWithOwner": "sourcegraph/sourcegraph"}},
+    {},
+    {"number": 3, "repository": {"id": "MDEwOlJlcG9zaXRvcnky", "nameWithOwner": "sourcegraph/src-cli"}}
+  ],
+  "pageInfo": {"endCursor": "Y3Vyc29yOjM=", "hasNextPage": true}
+}}}`,
+	}
+	c := newTestClient(t, &mock)
+
+	page, err := c.SearchPullRequests(context.Background(), "author:app/renovate label:security", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !page.HasNextPage || page.EndCursor != "Y3Vyc29yOjM=" {
+		t.Errorf("unexpected page info: %+v", page)
+	}
+
+	var have []string
+	for _, pr := range page.PullRequests {
+		have = append(have, fmt.Sprintf("%s#%d (%s)", pr.Repository.NameWithOwner, pr.Number, pr.Repository.ID))
+	}
+	want := []string{
+		"sourcegraph/sourcegraph#12 (MDEwOlJlcG9zaXRvcnkx)",
+		"sourcegraph/src-cli#3 (MDEwOlJlcG9zaXRvcnky)",
+	}
+	if !reflect.DeepEqual(have, want) {
+		t.Errorf("pull requests: want %v, have %v", want, have)
+	}
+}
+
+func TestClient_PullRequestActions(t *testing.T) {
+	pr := &PullRequest{
+		ID:            "MDExOlB1bGxSZXF1ZXN0MQ==",
+		Number:        7,
+		RepoWithOwner: "sourcegraph/sourcegraph",
+		HeadRefOid:    "deadbeef",
+	}
+	pr.Labels.Nodes = []Label{
+		{ID: "bGFiZWwx", Name: "needs-review"},
+		{ID: "bGFiZWwy", Name: "Security"},
+	}
+
+	type request struct {
+		Method string
+		Path   string
+		Body   string
+	}
+
+	// respond returns a Doer that records the requests and responds to them
+	// with the body for their method and path.
+	respond := func(responses map[string]string) (*[]request, httpcli.Doer) {
+		var reqs []request
+		return &reqs, httpcli.DoerFunc(func(req *http.Request) (*http.Response, error) {
+			var body []byte
+			if req.Body != nil {
+				body, _ = ioutil.ReadAll(req.Body)
+			}
+			reqs = append(reqs, request{Method: req.Method, Path: req.URL.Path, Body: string(body)})
+
+			status := http.StatusOK
+			resp, ok := responses[req.Method+" "+req.URL.Path]
+			if !ok {
+				status = http.StatusNoContent
+			}
+			return &http.Response{
+				Request:    req,
+				StatusCode: status,
+				Body:       ioutil.NopCloser(strings.NewReader(resp)),
+			}, nil
+		})
+	}
+
+	t.Run("CreatePullRequestComment", func(t *testing.T) {
+		reqs, doer := respond(map[string]string{
+			"POST /repos/sourcegraph/sourcegraph/issues/7/comments": `{"id": 1}`,
+		})
+		c := newTestClient(t, doer)
+
+		if err := c.CreatePullRequestComment(context.Background(), pr, "Please review"); err != nil {
+			t.Fatal(err)
+		}
+
+		want := []request{{Method: "POST", Path: "/repos/sourcegraph/sourcegraph/issues/7/comments", Body: `{"body":"Please review"}`}}
+		if !reflect.DeepEqual(*reqs, want) {
+			t.Errorf("requests: want %+v, have %+v", want, *reqs)
+		}
+	})
+
+	t.Run("RemoveLabelsFromPullRequest", func(t *testing.T) {
+		reqs, doer := respond(map[string]string{
+			"POST /graphql": `{"data": {"removeLabelsFromLabelable": {"clientMutationId": null}}}`,
+		})
+		c := newTestClient(t, doer)
+
+		if err := c.RemoveLabelsFromPullRequest(context.Background(), pr, []string{"security", "unknown"}); err != nil {
+			t.Fatal(err)
+		}
+
+		if len(*reqs) != 1 {
+			t.Fatalf("want 1 request, have %d", len(*reqs))
+		}
+		if have, want := (*reqs)[0].Body, `"variables":{"input":{"labelableId":"MDExOlB1bGxSZXF1ZXN0MQ==","labelIds":["bGFiZWwy"]}}`; !strings.Contains(have, want) {
+			t.Errorf("request body %q does not contain %q", have, want)
+		}
+
+		// No request is sent if the pull request has none of the labels.
+		if err := c.RemoveLabelsFromPullRequest(context.Background(), pr, []string{"unknown"}); err != nil {
+			t.Fatal(err)
+		}
+		if len(*reqs) != 1 {
+			t.Fatalf("want 1 request, have %d", len(*reqs))
+		}
+	})
+
+	t.Run("RerequestFailedCheckSuites", func(t *testing.T) {
+		reqs, doer := respond(map[string]string{
+			"GET /repos/sourcegraph/sourcegraph/commits/deadbeef/check-suites": `{"check_suites": [
+  {"id": 1, "conclusion": "success"},
+  {"id": 2, "conclusion": "failure"},
+  {"id": 3, "conclusion": null},
+  {"id": 4, "conclusion": "timed_out"}
+]}`,
+		})
+		c := newTestClient(t, doer)
+
+		rerun, err := c.RerequestFailedCheckSuites(context.Background(), pr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if rerun != 2 {
+			t.Errorf("want 2 check suites re-run, have %d", rerun)
+		}
+
+		want := []request{
+			{Method: "GET", Path: "/repos/sourcegraph/sourcegraph/commits/deadbeef/check-suites"},
+			{Method: "POST", Path: "/repos/sourcegraph/sourcegraph/check-suites/2/rerequest", Body: "{}"},
+			{Method: "POST", Path: "/repos/sourcegraph/sourcegraph/check-suites/4/rerequest", Body: "{}"},
+		}
+		if !reflect.DeepEqual(*reqs, want) {
+			t.Errorf("requests: want %+v, have %+v", want, *reqs)
+		}
+	})
+}
+
 func newClient(t testing.TB, name string) (*Client, func()) {
 	t.Helper()
 
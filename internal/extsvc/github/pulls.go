@@ -137,13 +137,23 @@ type PullRequest struct {
 	HeadRefName   string
 	BaseRefName   string
 	Number        int64
-	Author        Actor
-	Participants  []Actor
-	Labels        struct{ Nodes []Label }
-	TimelineItems []TimelineItem
-	Commits       struct{ Nodes []CommitWithChecks }
-	CreatedAt     time.Time
-	UpdatedAt     time.Time
+	Author         Actor
+	Participants   []Actor
+	Labels         struct{ Nodes []Label }
+	Assignees      struct{ Nodes []Actor }
+	ReviewRequests struct{ Nodes []ReviewRequest }
+	IsDraft        bool
+	TimelineItems  []TimelineItem
+	Commits        struct{ Nodes []CommitWithChecks }
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+// ReviewRequest is a pending request for a review of a PullRequest by a user
+// or by a team, of which only the slug is set.
+type ReviewRequest struct {
+	RequestedReviewer Actor
+	RequestedTeam     struct{ Slug string }
 }
 
 // AssignedEvent represents an 'assigned' event on a PullRequest.
@@ -457,6 +467,8 @@ type CreatePullRequestInput struct {
 	Title string `json:"title"`
 	// The body of the pull request (optional).
 	Body string `json:"body"`
+	// Whether the pull request is created as a draft (optional).
+	Draft bool `json:"draft,omitempty"`
 }
 
 // CreatePullRequest creates a PullRequest on Github.
@@ -588,6 +600,228 @@ func (c *Client) ClosePullRequest(ctx context.Context, pr *PullRequest) error {
 	return nil
 }
 
+// MergePullRequest merges the PullRequest on GitHub with the given merge
+// method ("MERGE", "SQUASH" or "REBASE") and updates it. The merge fails if
+// the head of the pull request is no longer at pr.HeadRefOid, so commits
+// pushed after pr was fetched are never merged unseen.
+func (c *Client) MergePullRequest(ctx context.Context, pr *PullRequest, mergeMethod string) error {
+	var q strings.Builder
+	q.WriteString(pullRequestFragments)
+	q.WriteString(`mutation	MergePullRequest($input:MergePullRequestInput!) {
+  mergePullRequest(input:$input) {
+    pullRequest {
+      ... pr
+    }
+  }
+}`)
+
+	var result struct {
+		MergePullRequest struct {
+			PullRequest struct {
+				PullRequest
+				Participants  struct{ Nodes []Actor }
+				TimelineItems struct{ Nodes []TimelineItem }
+			} `json:"pullRequest"`
+		} `json:"mergePullRequest"`
+	}
+
+	input := map[string]interface{}{"input": struct {
+		ID              string `json:"pullRequestId"`
+		MergeMethod     string `json:"mergeMethod"`
+		ExpectedHeadOid string `json:"expectedHeadOid,omitempty"`
+	}{ID: pr.ID, MergeMethod: mergeMethod, ExpectedHeadOid: pr.HeadRefOid}}
+	err := c.requestGraphQL(ctx, "", q.String(), input, &result)
+	if err != nil {
+		return err
+	}
+
+	*pr = result.MergePullRequest.PullRequest.PullRequest
+	pr.TimelineItems = result.MergePullRequest.PullRequest.TimelineItems.Nodes
+	pr.Participants = result.MergePullRequest.PullRequest.Participants.Nodes
+
+	return nil
+}
+
+// MarkPullRequestReadyForReview marks the draft PullRequest on GitHub as ready
+// for review and updates it.
+func (c *Client) MarkPullRequestReadyForReview(ctx context.Context, pr *PullRequest) error {
+	return c.setPullRequestDraft(ctx, pr, "markPullRequestReadyForReview", "MarkPullRequestReadyForReviewInput")
+}
+
+// ConvertPullRequestToDraft converts the PullRequest on GitHub to a draft and
+// updates it.
+func (c *Client) ConvertPullRequestToDraft(ctx context.Context, pr *PullRequest) error {
+	return c.setPullRequestDraft(ctx, pr, "convertPullRequestToDraft", "ConvertPullRequestToDraftInput")
+}
+
+func (c *Client) setPullRequestDraft(ctx context.Context, pr *PullRequest, mutation, inputType string) error {
+	var q strings.Builder
+	q.WriteString(pullRequestFragments)
+	fmt.Fprintf(&q, `mutation	SetPullRequestDraft($input:%s!) {
+  %s(input:$input) {
+    pullRequest {
+      ... pr
+    }
+  }
+}`, inputType, mutation)
+
+	var result map[string]*struct {
+		PullRequest struct {
+			PullRequest
+			Participants  struct{ Nodes []Actor }
+			TimelineItems struct{ Nodes []TimelineItem }
+		} `json:"pullRequest"`
+	}
+
+	input := map[string]interface{}{"input": struct {
+		ID string `json:"pullRequestId"`
+	}{ID: pr.ID}}
+	err := c.requestGraphQL(ctx, "", q.String(), input, &result)
+	if err != nil {
+		return err
+	}
+
+	payload := result[mutation]
+	if payload == nil {
+		return errors.Errorf("%s returned no pull request", mutation)
+	}
+
+	*pr = payload.PullRequest.PullRequest
+	pr.TimelineItems = payload.PullRequest.TimelineItems.Nodes
+	pr.Participants = payload.PullRequest.Participants.Nodes
+
+	return nil
+}
+
+// AddLabelsToPullRequest adds the labels with the given names to the
+// PullRequest. Labels that don't exist in the repository yet are created.
+func (c *Client) AddLabelsToPullRequest(ctx context.Context, pr *PullRequest, labels []string) error {
+	owner, repo, err := SplitRepositoryNameWithOwner(pr.RepoWithOwner)
+	if err != nil {
+		return err
+	}
+
+	path := fmt.Sprintf("repos/%s/%s/issues/%d/labels", owner, repo, pr.Number)
+	payload := struct {
+		Labels []string `json:"labels"`
+	}{Labels: labels}
+	return c.requestPost(ctx, "", path, payload, nil)
+}
+
+// RequestPullRequestReviewers requests reviews of the PullRequest from the
+// given users and the teams with the given slugs.
+func (c *Client) RequestPullRequestReviewers(ctx context.Context, pr *PullRequest, users, teams []string) error {
+	owner, repo, err := SplitRepositoryNameWithOwner(pr.RepoWithOwner)
+	if err != nil {
+		return err
+	}
+
+	path := fmt.Sprintf("repos/%s/%s/pulls/%d/requested_reviewers", owner, repo, pr.Number)
+	payload := struct {
+		Reviewers     []string `json:"reviewers,omitempty"`
+		TeamReviewers []string `json:"team_reviewers,omitempty"`
+	}{Reviewers: users, TeamReviewers: teams}
+	return c.requestPost(ctx, "", path, payload, nil)
+}
+
+// AddAssigneesToPullRequest assigns the given users to the PullRequest.
+func (c *Client) AddAssigneesToPullRequest(ctx context.Context, pr *PullRequest, assignees []string) error {
+	owner, repo, err := SplitRepositoryNameWithOwner(pr.RepoWithOwner)
+	if err != nil {
+		return err
+	}
+
+	path := fmt.Sprintf("repos/%s/%s/issues/%d/assignees", owner, repo, pr.Number)
+	payload := struct {
+		Assignees []string `json:"assignees"`
+	}{Assignees: assignees}
+	return c.requestPost(ctx, "", path, payload, nil)
+}
+
+// CreatePullRequestComment posts a comment with the given body on the
+// PullRequest.
+func (c *Client) CreatePullRequestComment(ctx context.Context, pr *PullRequest, body string) error {
+	owner, repo, err := SplitRepositoryNameWithOwner(pr.RepoWithOwner)
+	if err != nil {
+		return err
+	}
+
+	path := fmt.Sprintf("repos/%s/%s/issues/%d/comments", owner, repo, pr.Number)
+	payload := struct {
+		Body string `json:"body"`
+	}{Body: body}
+	return c.requestPost(ctx, "", path, payload, nil)
+}
+
+// RemoveLabelsFromPullRequest removes the labels with the given names from
+// the PullRequest. Labels the PullRequest doesn't have are ignored.
+func (c *Client) RemoveLabelsFromPullRequest(ctx context.Context, pr *PullRequest, labels []string) error {
+	var ids []string
+	for _, l := range pr.Labels.Nodes {
+		for _, name := range labels {
+			// Label names are case-insensitive on GitHub.
+			if strings.EqualFold(l.Name, name) {
+				ids = append(ids, l.ID)
+				break
+			}
+		}
+	}
+
+	if len(ids) == 0 {
+		return nil
+	}
+
+	q := `mutation RemoveLabelsFromPullRequest($input:RemoveLabelsFromLabelableInput!) {
+  removeLabelsFromLabelable(input:$input) {
+    clientMutationId
+  }
+}`
+
+	input := map[string]interface{}{"input": struct {
+		LabelableID string   `json:"labelableId"`
+		LabelIDs    []string `json:"labelIds"`
+	}{LabelableID: pr.ID, LabelIDs: ids}}
+
+	var result struct{}
+	return c.requestGraphQL(ctx, "", q, input, &result)
+}
+
+// RerequestFailedCheckSuites re-runs the check suites of the head commit of
+// the PullRequest that failed or timed out. It returns the number of check
+// suites that were re-run.
+func (c *Client) RerequestFailedCheckSuites(ctx context.Context, pr *PullRequest) (int, error) {
+	owner, repo, err := SplitRepositoryNameWithOwner(pr.RepoWithOwner)
+	if err != nil {
+		return 0, err
+	}
+
+	var result struct {
+		CheckSuites []struct {
+			ID         int64  `json:"id"`
+			Conclusion string `json:"conclusion"`
+		} `json:"check_suites"`
+	}
+	path := fmt.Sprintf("repos/%s/%s/commits/%s/check-suites", owner, repo, pr.HeadRefOid)
+	if err := c.requestGet(ctx, "", path, &result); err != nil {
+		return 0, err
+	}
+
+	rerun := 0
+	for _, suite := range result.CheckSuites {
+		if suite.Conclusion != "failure" && suite.Conclusion != "timed_out" {
+			continue
+		}
+
+		path := fmt.Sprintf("repos/%s/%s/check-suites/%d/rerequest", owner, repo, suite.ID)
+		if err := c.requestPost(ctx, "", path, struct{}{}, nil); err != nil {
+			return rerun, err
+		}
+		rerun++
+	}
+
+	return rerun, nil
+}
+
 // LoadPullRequests loads a list of PullRequests from Github.
 func (c *Client) LoadPullRequests(ctx context.Context, prs ...*PullRequest) error {
 	const batchSize = 15
@@ -674,6 +908,73 @@ func (c *Client) loadPullRequests(ctx context.Context, prs ...*PullRequest) erro
 	return nil
 }
 
+// PullRequestSearchResult is a pull request found by SearchPullRequests.
+type PullRequestSearchResult struct {
+	Number     int64
+	Repository struct {
+		// The Node ID of the repository.
+		ID            string
+		NameWithOwner string
+	}
+}
+
+// PullRequestSearchPage is a page of pull requests returned by
+// SearchPullRequests.
+type PullRequestSearchPage struct {
+	PullRequests []*PullRequestSearchResult
+	EndCursor    string
+	HasNextPage  bool
+}
+
+// SearchPullRequests returns a page of the pull requests matching the given
+// GitHub search query, e.g. "author:app/renovate label:security". The query
+// is restricted to pull requests. after is the EndCursor of the previous page
+// or empty for the first page.
+func (c *Client) SearchPullRequests(ctx context.Context, query, after string) (PullRequestSearchPage, error) {
+	const q = `query SearchPullRequests($query: String!, $after: String) {
+  search(query: $query, type: ISSUE, first: 100, after: $after) {
+    nodes {
+      ... on PullRequest {
+        number
+        repository { id nameWithOwner }
+      }
+    }
+    pageInfo { endCursor hasNextPage }
+  }
+}`
+
+	vars := map[string]interface{}{"query": "is:pr " + query}
+	if after != "" {
+		vars["after"] = after
+	}
+
+	var result struct {
+		Search struct {
+			Nodes    []*PullRequestSearchResult
+			PageInfo struct {
+				EndCursor   string
+				HasNextPage bool
+			}
+		}
+	}
+	if err := c.requestGraphQL(ctx, "", q, vars, &result); err != nil {
+		return PullRequestSearchPage{}, err
+	}
+
+	page := PullRequestSearchPage{
+		EndCursor:   result.Search.PageInfo.EndCursor,
+		HasNextPage: result.Search.PageInfo.HasNextPage,
+	}
+	for _, pr := range result.Search.Nodes {
+		// Issues are returned as empty nodes, since they don't match the
+		// PullRequest fragment.
+		if pr != nil && pr.Number != 0 {
+			page.PullRequests = append(page.PullRequests, pr)
+		}
+	}
+	return page, nil
+}
+
 // GetOpenPullRequestByRefs fetches the the pull request associated with the supplied
 // refs. GitHub only allows one open PR by ref at a time.
 // If nothing is found an error is returned.
@@ -812,6 +1113,7 @@ fragment pr on PullRequest {
   baseRefOid
   headRefName
   baseRefName
+  isDraft
   author {
     ...actor
   }
@@ -825,6 +1127,23 @@ fragment pr on PullRequest {
       ...label
     }
   }
+  assignees(first: 100) {
+    nodes {
+      ...actor
+    }
+  }
+  reviewRequests(first: 100) {
+    nodes {
+      requestedReviewer {
+        ...actor
+      }
+      requestedTeam: requestedReviewer {
+        ... on Team {
+          slug
+        }
+      }
+    }
+  }
   commits(last: 1) {
     nodes {
       ...prCommit
@@ -8,6 +8,7 @@ import (
 	"net/url"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/inconshreveable/log15"
 	"github.com/pkg/errors"
@@ -26,15 +27,44 @@ func SplitRepositoryNameWithOwner(nameWithOwner string) (owner, repo string, err
 
 // Repository is a GitHub repository.
 type Repository struct {
-	ID               string // ID of repository (GitHub GraphQL ID, not GitHub database ID)
-	DatabaseID       int64  // The integer database id
-	NameWithOwner    string // full name of repository ("owner/name")
-	Description      string // description of repository
-	URL              string // the web URL of this repository ("https://github.com/foo/bar")
-	IsPrivate        bool   // whether the repository is private
-	IsFork           bool   // whether the repository is a fork of another repository
-	IsArchived       bool   // whether the repository is archived on the code host
-	ViewerPermission string // ADMIN, WRITE, READ, or empty if unknown. Only the graphql api populates this. https://developer.github.com/v4/enum/repositorypermission/
+	ID               string   // ID of repository (GitHub GraphQL ID, not GitHub database ID)
+	DatabaseID       int64    // The integer database id
+	NameWithOwner    string   // full name of repository ("owner/name")
+	Description      string   // description of repository
+	URL              string   // the web URL of this repository ("https://github.com/foo/bar")
+	IsPrivate        bool     // whether the repository is private
+	IsFork           bool     // whether the repository is a fork of another repository
+	IsArchived       bool     // whether the repository is archived on the code host
+	ViewerPermission string   // ADMIN, WRITE, READ, or empty if unknown. Only the graphql api populates this. https://developer.github.com/v4/enum/repositorypermission/
+	Topics           []string // topics the repository is tagged with
+}
+
+// UnmarshalJSON implements json.Unmarshaler. It flattens the repositoryTopics
+// connection returned by the GraphQL API into Topics.
+func (r *Repository) UnmarshalJSON(data []byte) error {
+	type repository Repository
+	var v struct {
+		repository
+		RepositoryTopics *struct {
+			Nodes []struct {
+				Topic struct {
+					Name string
+				}
+			}
+		} `json:"repositoryTopics"`
+	}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	*r = Repository(v.repository)
+	if v.RepositoryTopics != nil {
+		r.Topics = make([]string, 0, len(v.RepositoryTopics.Nodes))
+		for _, n := range v.RepositoryTopics.Nodes {
+			r.Topics = append(r.Topics, n.Topic.Name)
+		}
+	}
+	return nil
 }
 
 // repositoryFieldsGraphQLFragment returns a GraphQL fragment that contains the fields needed to populate the
@@ -52,6 +82,13 @@ fragment RepositoryFields on Repository {
 	isFork
 	isArchived
 	viewerPermission
+	repositoryTopics(first: 100) {
+		nodes {
+			topic {
+				name
+			}
+		}
+	}
 }
 	`
 	}
@@ -68,6 +105,13 @@ fragment RepositoryFields on Repository {
 	isPrivate
 	isFork
 	isArchived
+	repositoryTopics(first: 100) {
+		nodes {
+			topic {
+				name
+			}
+		}
+	}
 }
 	`
 }
@@ -251,7 +295,9 @@ type restRepository struct {
 	Private     bool
 	Fork        bool
 	Archived    bool
+	Topics      []string                  `json:"topics"`
 	Permissions restRepositoryPermissions `json:"permissions"`
+	UpdatedAt   time.Time                 `json:"updated_at"`
 }
 
 // getRepositoryFromAPI attempts to fetch a repository from the GitHub API without use of the redis cache.
@@ -283,6 +329,7 @@ func convertRestRepo(restRepo restRepository) *Repository {
 		IsFork:           restRepo.Fork,
 		IsArchived:       restRepo.Archived,
 		ViewerPermission: convertRestRepoPermissions(restRepo.Permissions),
+		Topics:           restRepo.Topics,
 	}
 }
 
@@ -507,6 +554,37 @@ func (c *Client) ListUserRepositories(ctx context.Context, user string, page int
 	return repos, len(repos) > 0, 1, err
 }
 
+// ListAffiliatedRepositoriesUpdatedSince is like ListAffiliatedRepositories,
+// but only lists the repositories that were updated after since, most recently
+// updated first.
+func (c *Client) ListAffiliatedRepositoriesUpdatedSince(ctx context.Context, since time.Time, page int) (repos []*Repository, hasNextPage bool, rateLimitCost int, err error) {
+	path := fmt.Sprintf("user/repos?sort=updated&direction=desc&page=%d&per_page=100", page)
+	repos, hasNextPage, err = c.listRepositoriesUpdatedSince(ctx, path, since)
+	if err == nil {
+		// 🚨 SECURITY: must forward token here to ensure caching by token
+		c.addRepositoriesToCache("", repos)
+	}
+
+	return repos, hasNextPage, 1, err
+}
+
+// ListOrgRepositoriesUpdatedSince is like ListOrgRepositories, but only lists
+// the repositories that were updated after since, most recently updated first.
+func (c *Client) ListOrgRepositoriesUpdatedSince(ctx context.Context, org string, since time.Time, page int) (repos []*Repository, hasNextPage bool, rateLimitCost int, err error) {
+	path := fmt.Sprintf("orgs/%s/repos?sort=updated&direction=desc&page=%d&per_page=100", org, page)
+	repos, hasNextPage, err = c.listRepositoriesUpdatedSince(ctx, path, since)
+	return repos, hasNextPage, 1, err
+}
+
+// ListUserRepositoriesUpdatedSince is like ListUserRepositories, but only
+// lists the repositories that were updated after since, most recently updated
+// first.
+func (c *Client) ListUserRepositoriesUpdatedSince(ctx context.Context, user string, since time.Time, page int) (repos []*Repository, hasNextPage bool, rateLimitCost int, err error) {
+	path := fmt.Sprintf("users/%s/repos?sort=updated&direction=desc&type=owner&page=%d&per_page=100", user, page)
+	repos, hasNextPage, err = c.listRepositoriesUpdatedSince(ctx, path, since)
+	return repos, hasNextPage, 1, err
+}
+
 type restSearchResponse struct {
 	TotalCount        int              `json:"total_count"`
 	IncompleteResults bool             `json:"incomplete_results"`
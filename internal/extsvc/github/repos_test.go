@@ -11,6 +11,7 @@ import (
 	"sort"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/davecgh/go-spew/spew"
 	"github.com/sergi/go-diff/diffmatchpatch"
@@ -295,7 +296,13 @@ func TestClient_GetRepositoryByNodeID(t *testing.T) {
 			"nameWithOwner": "o/r",
 			"description": "d",
 			"url": "https://github.example.com/o/r",
-			"isFork": true
+			"isFork": true,
+			"repositoryTopics": {
+				"nodes": [
+					{"topic": {"name": "go"}},
+					{"topic": {"name": "tier-1"}}
+				]
+			}
 		}
 	}
 }
@@ -309,6 +316,7 @@ func TestClient_GetRepositoryByNodeID(t *testing.T) {
 		Description:   "d",
 		URL:           "https://github.example.com/o/r",
 		IsFork:        true,
+		Topics:        []string{"go", "tier-1"},
 	}
 
 	repo, err := c.GetRepositoryByNodeID(context.Background(), "", "i")
@@ -414,6 +422,46 @@ func TestClient_ListOrgRepositories(t *testing.T) {
 	}
 }
 
+func TestClient_ListOrgRepositoriesUpdatedSince(t *testing.T) {
+	mock := mockHTTPResponseBody{
+		responseBody: `[
+  {
+    "node_id": "i",
+    "full_name": "o/r",
+    "html_url": "https://github.example.com/o/r",
+    "updated_at": "2020-03-02T10:00:00Z"
+  },
+  {
+    "node_id": "j",
+    "full_name": "o/b",
+    "html_url": "https://github.example.com/o/b",
+    "updated_at": "2020-02-28T10:00:00Z"
+  }
+]
+`}
+
+	c := newTestClient(t, &mock)
+	wantRepos := []*Repository{
+		{
+			ID:            "i",
+			NameWithOwner: "o/r",
+			URL:           "https://github.example.com/o/r",
+		},
+	}
+
+	since := time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC)
+	repos, hasNextPage, _, err := c.ListOrgRepositoriesUpdatedSince(context.Background(), "o", since, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !repoListsAreEqual(repos, wantRepos) {
+		t.Errorf("got repositories:\n%s\nwant:\n%s", stringForRepoList(repos), stringForRepoList(wantRepos))
+	}
+	if hasNextPage {
+		t.Errorf("got hasNextPage: true want: false")
+	}
+}
+
 func stringForRepoList(repos []*Repository) string {
 	repoStrings := []string{}
 	for _, repo := range repos {
@@ -427,7 +475,7 @@ func repoListsAreEqual(a []*Repository, b []*Repository) bool {
 		return false
 	}
 	for i := 0; i < len(a); i++ {
-		if *a[i] != *b[i] {
+		if !reflect.DeepEqual(a[i], b[i]) {
 			return false
 		}
 	}
@@ -0,0 +1,3 @@
+// Package manifest implements parsing of repository manifests: JSON or YAML
+// documents that list the repositories Sourcegraph should sync.
+package manifest
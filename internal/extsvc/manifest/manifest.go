@@ -0,0 +1,96 @@
+package manifest
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"github.com/hashicorp/go-multierror"
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+)
+
+// ServiceType is the (api.ExternalRepoSpec).ServiceType value for repositories
+// listed in a manifest. The ServiceID value is the location of the manifest.
+const ServiceType = "manifest"
+
+// Visibility values of a manifest Repo.
+const (
+	VisibilityPublic  = "public"
+	VisibilityPrivate = "private"
+)
+
+// Manifest is a list of repositories to sync.
+type Manifest struct {
+	Repos []*Repo `json:"repos"`
+}
+
+// Repo is a repository listed in a manifest.
+type Repo struct {
+	// Name is the name of the repository on Sourcegraph.
+	Name string `json:"name"`
+	// CloneURL is the Git clone URL of the repository.
+	CloneURL string `json:"cloneURL"`
+	// Description is a brief description of the repository.
+	Description string `json:"description,omitempty"`
+	// Visibility is either "public" (the default) or "private".
+	Visibility string `json:"visibility,omitempty"`
+	// Fork is whether the repository is a fork of another repository.
+	Fork bool `json:"fork,omitempty"`
+	// Archived is whether the repository is archived.
+	Archived bool `json:"archived,omitempty"`
+	// Metadata holds arbitrary key/value pairs describing the repository,
+	// e.g. the owning team.
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// Private reports whether the repository is private.
+func (r *Repo) Private() bool {
+	return strings.EqualFold(r.Visibility, VisibilityPrivate)
+}
+
+// ExternalRepoSpec returns an api.ExternalRepoSpec that refers to the given
+// repository of the manifest at the given location.
+func ExternalRepoSpec(repo *Repo, serviceID string) api.ExternalRepoSpec {
+	return api.ExternalRepoSpec{
+		ID:          repo.Name,
+		ServiceType: ServiceType,
+		ServiceID:   serviceID,
+	}
+}
+
+// Parse parses and validates a JSON or YAML manifest.
+func Parse(data []byte) (*Manifest, error) {
+	var m Manifest
+	if err := yaml.Unmarshal(data, &m); err != nil {
+		return nil, errors.Wrap(err, "failed to decode manifest")
+	}
+
+	var errs *multierror.Error
+	seen := make(map[string]bool, len(m.Repos))
+	for i, r := range m.Repos {
+		if r == nil {
+			errs = multierror.Append(errs, fmt.Errorf("repos[%d]: empty entry", i))
+			continue
+		}
+		if r.Name == "" {
+			errs = multierror.Append(errs, fmt.Errorf("repos[%d]: name is required", i))
+		} else if seen[strings.ToLower(r.Name)] {
+			errs = multierror.Append(errs, fmt.Errorf("repos[%d]: duplicate name %q", i, r.Name))
+		}
+		seen[strings.ToLower(r.Name)] = true
+		if r.CloneURL == "" {
+			errs = multierror.Append(errs, fmt.Errorf("repos[%d]: cloneURL is required", i))
+		}
+		switch strings.ToLower(r.Visibility) {
+		case "", VisibilityPublic, VisibilityPrivate:
+		default:
+			errs = multierror.Append(errs, fmt.Errorf("repos[%d]: invalid visibility %q", i, r.Visibility))
+		}
+	}
+
+	if err := errs.ErrorOrNil(); err != nil {
+		return nil, errors.Wrap(err, "invalid manifest")
+	}
+	return &m, nil
+}
@@ -0,0 +1,90 @@
+package manifest
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParse(t *testing.T) {
+	want := &Manifest{Repos: []*Repo{
+		{
+			Name:        "services/billing",
+			CloneURL:    "https://git.example.com/services/billing.git",
+			Description: "Billing service",
+			Visibility:  "private",
+			Metadata:    map[string]string{"owner": "payments", "tier": "1"},
+		},
+		{
+			Name:     "tools/legacy",
+			CloneURL: "ssh://git@git.example.com/tools/legacy.git",
+			Fork:     true,
+			Archived: true,
+		},
+	}}
+
+	for _, tc := range []struct {
+		name string
+		data string
+	}{
+		{
+			name: "json",
+			data: `{"repos": [
+				{"name": "services/billing", "cloneURL": "https://git.example.com/services/billing.git", "description": "Billing service", "visibility": "private", "metadata": {"owner": "payments", "tier": "1"}},
+				{"name": "tools/legacy", "cloneURL": "ssh://git@git.example.com/tools/legacy.git", "fork": true, "archived": true}
+			]}`,
+		},
+		{
+			name: "yaml",
+			data: `
+repos:
+  - name: services/billing
+    cloneURL: https://git.example.com/services/billing.git
+    description: Billing service
+    visibility: private
+    metadata:
+      owner: payments
+      tier: "1"
+  - name: tools/legacy
+    cloneURL: ssh://git@git.example.com/tools/legacy.git
+    fork: true
+    archived: true
+`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			have, err := Parse([]byte(tc.data))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(want, have); diff != "" {
+				t.Fatal(diff)
+			}
+			if !have.Repos[0].Private() || have.Repos[1].Private() {
+				t.Fatal("unexpected visibility")
+			}
+		})
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		data string
+		err  string
+	}{
+		{name: "garbage", data: `{`, err: "failed to decode manifest"},
+		{name: "missing name", data: `{"repos": [{"cloneURL": "https://git.example.com/a"}]}`, err: "repos[0]: name is required"},
+		{name: "missing clone URL", data: `{"repos": [{"name": "a"}]}`, err: "repos[0]: cloneURL is required"},
+		{name: "duplicate", data: `{"repos": [{"name": "a", "cloneURL": "x"}, {"name": "A", "cloneURL": "y"}]}`, err: `repos[1]: duplicate name "A"`},
+		{name: "visibility", data: `{"repos": [{"name": "a", "cloneURL": "x", "visibility": "secret"}]}`, err: `repos[0]: invalid visibility "secret"`},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := Parse([]byte(tc.data))
+			if err == nil || !strings.Contains(err.Error(), tc.err) {
+				t.Fatalf("have error %v, want %q", err, tc.err)
+			}
+		})
+	}
+}
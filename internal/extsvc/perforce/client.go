@@ -0,0 +1,146 @@
+package perforce
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Depot is a Perforce depot or stream that is imported as a single Git
+// repository.
+type Depot struct {
+	// Path is the depot path of the depot or stream with a trailing slash,
+	// e.g. "//depot/" or "//engine/main/".
+	Path string
+	// Type is the depot type (e.g. "local" or "stream") for depots, or the
+	// stream type (e.g. "mainline" or "development") for streams.
+	Type string
+	// Description is the description of the depot or stream.
+	Description string
+	// Stream is true if Path refers to a stream of a stream depot.
+	Stream bool
+}
+
+// Client is a client for Perforce Server.
+//
+// Impl note: the client shells out to the p4 CLI, which must be installed in
+// the environment of the calling service.
+type Client struct {
+	Port     string
+	User     string
+	Password string
+}
+
+// NewClient returns a new Client for the Perforce Server at p4port.
+func NewClient(p4port, p4user, p4passwd string) *Client {
+	return &Client{Port: p4port, User: p4user, Password: p4passwd}
+}
+
+// ListDepots returns all depots visible to the client's user.
+func (c *Client) ListDepots(ctx context.Context) ([]*Depot, error) {
+	out, err := c.run(ctx, "depots")
+	if err != nil {
+		return nil, err
+	}
+
+	var depots []*Depot
+	for _, rec := range parseTaggedOutput(out) {
+		name := rec["name"]
+		if name == "" {
+			continue
+		}
+		depots = append(depots, &Depot{
+			Path:        "//" + name + "/",
+			Type:        rec["type"],
+			Description: strings.TrimSpace(rec["desc"]),
+		})
+	}
+	return depots, nil
+}
+
+// ListStreams returns all streams of the stream depot with the given depot
+// path (e.g. "//engine/").
+func (c *Client) ListStreams(ctx context.Context, depotPath string) ([]*Depot, error) {
+	out, err := c.run(ctx, "streams", strings.TrimSuffix(depotPath, "/")+"/...")
+	if err != nil {
+		return nil, err
+	}
+
+	var streams []*Depot
+	for _, rec := range parseTaggedOutput(out) {
+		stream := rec["Stream"]
+		if stream == "" {
+			continue
+		}
+		streams = append(streams, &Depot{
+			Path:        stream + "/",
+			Type:        rec["Type"],
+			Description: strings.TrimSpace(rec["desc"]),
+			Stream:      true,
+		})
+	}
+	return streams, nil
+}
+
+// CheckDepot returns an error if the depot path does not exist or is not
+// readable by the client's user.
+func (c *Client) CheckDepot(ctx context.Context, depotPath string) error {
+	_, err := c.run(ctx, "changes", "-m", "1", strings.TrimSuffix(depotPath, "/")+"/...")
+	return err
+}
+
+// run runs the p4 CLI in tagged mode with the given arguments.
+func (c *Client) run(ctx context.Context, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, "p4", append([]string{"-ztag"}, args...)...)
+	cmd.Env = append(os.Environ(), (&Remote{Port: c.Port, User: c.User, Password: c.Password}).Env()...)
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, errors.Wrapf(err, "p4 %s: %s", args[0], strings.TrimSpace(stderr.String()))
+	}
+	return out, nil
+}
+
+// parseTaggedOutput parses the output of a p4 command run with -ztag into one
+// map of fields per record. Records are separated by blank lines and each
+// field line has the form "... key value".
+func parseTaggedOutput(out []byte) []map[string]string {
+	var (
+		recs []map[string]string
+		rec  map[string]string
+	)
+
+	s := bufio.NewScanner(bytes.NewReader(out))
+	for s.Scan() {
+		line := s.Text()
+		if !strings.HasPrefix(line, "... ") {
+			if strings.TrimSpace(line) == "" && rec != nil {
+				recs = append(recs, rec)
+				rec = nil
+			}
+			continue
+		}
+
+		field := strings.SplitN(strings.TrimPrefix(line, "... "), " ", 2)
+		if rec == nil {
+			rec = map[string]string{}
+		}
+		if len(field) == 2 {
+			rec[field[0]] = field[1]
+		} else {
+			rec[field[0]] = ""
+		}
+	}
+
+	if rec != nil {
+		recs = append(recs, rec)
+	}
+	return recs
+}
@@ -0,0 +1,46 @@
+package perforce
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_parseTaggedOutput(t *testing.T) {
+	out := `... name depot
+... time 1577836800
+... type local
+... map depot/...
+... desc Default depot
+
+... name engine
+... time 1577836800
+... type stream
+... map engine/...
+... desc Game engine
+... extra
+
+`
+
+	want := []map[string]string{
+		{
+			"name": "depot",
+			"time": "1577836800",
+			"type": "local",
+			"map":  "depot/...",
+			"desc": "Default depot",
+		},
+		{
+			"name":  "engine",
+			"time":  "1577836800",
+			"type":  "stream",
+			"map":   "engine/...",
+			"desc":  "Game engine",
+			"extra": "",
+		},
+	}
+
+	if diff := cmp.Diff(want, parseTaggedOutput([]byte(out))); diff != "" {
+		t.Fatal(diff)
+	}
+}
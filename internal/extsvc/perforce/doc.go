@@ -0,0 +1,4 @@
+// Package perforce implements a client for Perforce Server that shells out to
+// the p4 CLI, along with helpers to map depots onto Git repositories imported
+// with git p4.
+package perforce
@@ -148,6 +148,10 @@ type CreateCommitFromPatchRequest struct {
 	CommitInfo PatchCommitInfo
 	// Push specifies whether the target ref will be pushed to the code host
 	Push bool
+	// ExpectedRemoteCommit, if set, is the commit the target ref has to point
+	// to on the code host for the push to succeed, which prevents overwriting
+	// commits that were pushed by others (see `git push --force-with-lease`).
+	ExpectedRemoteCommit api.CommitID
 	// GitApplyArgs are the arguments that will be passed to `git apply` along
 	// with `--cached`.
 	GitApplyArgs []string
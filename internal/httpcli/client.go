@@ -3,7 +3,9 @@ package httpcli
 import (
 	"crypto/tls"
 	"crypto/x509"
+	"net"
 	"net/http"
+	"syscall"
 	"time"
 
 	"github.com/gregjones/httpcache"
@@ -260,6 +262,84 @@ func NewIdleConnTimeoutOpt(timeout time.Duration) Opt {
 	}
 }
 
+// DenyPrivateNetworksOpt returns an Opt that makes the http.Client's transport
+// refuse to connect to loopback, private, link-local, unspecified and multicast
+// addresses. It should be used for requests to user provided URLs, such as
+// webhooks, to prevent them from reaching internal services.
+//
+// The check is done on the resolved address of every connection, so it can't
+// be circumvented with DNS names pointing to internal addresses or redirects.
+// Proxies configured in the environment are ignored, since they'd dial the
+// destination on our behalf.
+func DenyPrivateNetworksOpt(cli *http.Client) error {
+	tr, err := getTransportForMutation(cli)
+	if err != nil {
+		return errors.Wrap(err, "httpcli.DenyPrivateNetworksOpt")
+	}
+
+	dialer := &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || IsPrivateIP(ip) {
+				return errors.Errorf("httpcli: connecting to %s is not allowed", host)
+			}
+			return nil
+		},
+	}
+
+	tr.Proxy = nil
+	tr.DialContext = dialer.DialContext
+
+	return nil
+}
+
+// privateNetworks are the IPv4 and IPv6 ranges that aren't publicly routable
+// and aren't covered by the net.IP predicates.
+var privateNetworks = func() []*net.IPNet {
+	var nets []*net.IPNet
+	for _, cidr := range []string{
+		"10.0.0.0/8",
+		"172.16.0.0/12",
+		"192.168.0.0/16",
+		"fc00::/7",      // unique local
+		"100.64.0.0/10", // carrier-grade NAT
+		"192.0.0.0/24",  // IETF protocol assignments
+		"198.18.0.0/15", // benchmarking
+		"64:ff9b::/96",  // NAT64
+	} {
+		_, n, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic(err)
+		}
+		nets = append(nets, n)
+	}
+	return nets
+}()
+
+// IsPrivateIP reports whether ip is a loopback, private, link-local,
+// unspecified or multicast address.
+func IsPrivateIP(ip net.IP) bool {
+	if ip.IsLoopback() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() {
+		return true
+	}
+	if ip4 := ip.To4(); ip4 != nil && ip4[0] == 0 {
+		return true // "this" network
+	}
+	for _, n := range privateNetworks {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
 // getTransport returns the http.Transport for cli. If Transport is nil, it is
 // set to a copy of the DefaultTransport. If it is the DefaultTransport, it is
 // updated to a copy of the DefaultTransport.
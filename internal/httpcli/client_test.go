@@ -11,6 +11,7 @@ import (
 	"errors"
 	"fmt"
 	"math/big"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"reflect"
@@ -276,3 +277,50 @@ type bogusTransport struct{}
 func (t bogusTransport) RoundTrip(*http.Request) (*http.Response, error) {
 	panic("should not be called")
 }
+
+func TestDenyPrivateNetworksOpt(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("request to loopback address wasn't denied")
+	}))
+	defer srv.Close()
+
+	cli := &http.Client{}
+	if err := DenyPrivateNetworksOpt(cli); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := cli.Get(srv.URL)
+	if err == nil || !strings.Contains(err.Error(), "is not allowed") {
+		t.Fatalf("have error %v, want connection to be denied", err)
+	}
+
+	err = DenyPrivateNetworksOpt(&http.Client{Transport: bogusTransport{}})
+	if have, want := fmt.Sprint(err), "httpcli.DenyPrivateNetworksOpt: http.Client.Transport is not an *http.Transport: httpcli.bogusTransport"; have != want {
+		t.Fatalf("have error: %q\nwant error: %q", have, want)
+	}
+}
+
+func TestIsPrivateIP(t *testing.T) {
+	for ip, want := range map[string]bool{
+		"127.0.0.1":       true,
+		"10.1.2.3":        true,
+		"172.20.0.1":      true,
+		"192.168.1.1":     true,
+		"169.254.169.254": true,
+		"100.100.1.1":     true,
+		"0.0.0.0":         true,
+		"224.0.0.1":       true,
+		"::1":             true,
+		"fe80::1":         true,
+		"fd00::1":         true,
+		"::ffff:10.0.0.1": true,
+		"8.8.8.8":         false,
+		"172.32.0.1":      false,
+		"140.82.112.3":    false,
+		"2001:4860::8888": false,
+	} {
+		if have := IsPrivateIP(net.ParseIP(ip)); have != want {
+			t.Errorf("IsPrivateIP(%s): have %t, want %t", ip, have, want)
+		}
+	}
+}
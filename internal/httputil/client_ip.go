@@ -0,0 +1,100 @@
+package httputil
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+)
+
+func init() {
+	conf.ContributeValidator(func(cfg conf.Unified) (problems conf.Problems) {
+		for _, p := range cfg.TrustedProxies {
+			if _, err := parseIPNet(p); err != nil {
+				problems = append(problems, conf.NewSiteProblem(fmt.Sprintf("trustedProxies: %s", err)))
+			}
+		}
+		return problems
+	})
+}
+
+// ClientIP returns the IP address of the client that made the request.
+//
+// If the request was made by one of the proxies in the "trustedProxies" site configuration, the
+// address is taken from the X-Forwarded-For header: it is the rightmost address in the header that
+// isn't one of a trusted proxy. Addresses to the left of it could have been set by the client.
+//
+// 🚨 SECURITY: Use this instead of parsing http.Request.RemoteAddr, so that all callers agree on
+// the client IP address and don't trust X-Forwarded-For headers set by clients.
+func ClientIP(r *http.Request) string {
+	return clientIP(r, conf.Get().TrustedProxies)
+}
+
+func clientIP(r *http.Request, trustedProxies []string) string {
+	ip, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		ip = r.RemoteAddr
+	}
+	if len(trustedProxies) == 0 {
+		return ip
+	}
+
+	var trusted []*net.IPNet
+	for _, p := range trustedProxies {
+		if n, err := parseIPNet(p); err == nil {
+			trusted = append(trusted, n)
+		}
+	}
+	isTrusted := func(ip string) bool {
+		parsed := net.ParseIP(ip)
+		if parsed == nil {
+			return false
+		}
+		for _, n := range trusted {
+			if n.Contains(parsed) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if !isTrusted(ip) {
+		return ip
+	}
+
+	var forwarded []string
+	for _, h := range r.Header.Values("X-Forwarded-For") {
+		forwarded = append(forwarded, strings.Split(h, ",")...)
+	}
+	for i := len(forwarded) - 1; i >= 0; i-- {
+		hop := strings.TrimSpace(forwarded[i])
+		if net.ParseIP(hop) == nil {
+			// The proxy that appended this value can't be trusted to have recorded the client.
+			break
+		}
+		ip = hop
+		if !isTrusted(hop) {
+			break
+		}
+	}
+	return ip
+}
+
+// parseIPNet parses an IP address or CIDR range.
+func parseIPNet(s string) (*net.IPNet, error) {
+	if strings.Contains(s, "/") {
+		_, n, err := net.ParseCIDR(s)
+		return n, err
+	}
+	ip := net.ParseIP(s)
+	if ip == nil {
+		return nil, fmt.Errorf("invalid IP address %q", s)
+	}
+	bits := 8 * net.IPv6len
+	if ip4 := ip.To4(); ip4 != nil {
+		ip, bits = ip4, 8*net.IPv4len
+	}
+	return &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)}, nil
+}
@@ -0,0 +1,81 @@
+package httputil
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestClientIP(t *testing.T) {
+	trusted := []string{"10.0.0.0/8", "192.168.1.1"}
+
+	for _, tc := range []struct {
+		name           string
+		remoteAddr     string
+		forwardedFor   []string
+		trustedProxies []string
+		want           string
+	}{
+		{
+			name:         "no trusted proxies",
+			remoteAddr:   "10.1.1.1:1234",
+			forwardedFor: []string{"1.1.1.1"},
+			want:         "10.1.1.1",
+		},
+		{
+			name:           "untrusted remote address",
+			remoteAddr:     "2.2.2.2:1234",
+			forwardedFor:   []string{"1.1.1.1"},
+			trustedProxies: trusted,
+			want:           "2.2.2.2",
+		},
+		{
+			name:           "trusted proxy",
+			remoteAddr:     "10.1.1.1:1234",
+			forwardedFor:   []string{"1.1.1.1"},
+			trustedProxies: trusted,
+			want:           "1.1.1.1",
+		},
+		{
+			name:           "spoofed addresses to the left are ignored",
+			remoteAddr:     "10.1.1.1:1234",
+			forwardedFor:   []string{"3.3.3.3, 1.1.1.1", "192.168.1.1"},
+			trustedProxies: trusted,
+			want:           "1.1.1.1",
+		},
+		{
+			name:           "all hops trusted",
+			remoteAddr:     "10.1.1.1:1234",
+			forwardedFor:   []string{"10.2.2.2"},
+			trustedProxies: trusted,
+			want:           "10.2.2.2",
+		},
+		{
+			name:           "invalid hop",
+			remoteAddr:     "10.1.1.1:1234",
+			forwardedFor:   []string{"1.1.1.1, bogus, 10.2.2.2"},
+			trustedProxies: trusted,
+			want:           "10.2.2.2",
+		},
+		{
+			name:           "no header",
+			remoteAddr:     "10.1.1.1:1234",
+			trustedProxies: trusted,
+			want:           "10.1.1.1",
+		},
+		{
+			name:       "remote address without port",
+			remoteAddr: "2.2.2.2",
+			want:       "2.2.2.2",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			r := &http.Request{RemoteAddr: tc.remoteAddr, Header: http.Header{}}
+			for _, v := range tc.forwardedFor {
+				r.Header.Add("X-Forwarded-For", v)
+			}
+			if have := clientIP(r, tc.trustedProxies); have != tc.want {
+				t.Fatalf("have %q, want %q", have, tc.want)
+			}
+		})
+	}
+}
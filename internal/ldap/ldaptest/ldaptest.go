@@ -0,0 +1,494 @@
+// Package ldaptest provides an in-process LDAP server for tests, in the style of net/http/httptest.
+//
+// The server keeps its directory in memory and supports the operations that Sourcegraph performs
+// with github.com/go-ldap/ldap: simple binds, searches (with all of the filter types of RFC 4515
+// except extensible matches), and StartTLS. Values are compared case-insensitively, and the values of the DN-valued attributes member
+// and uniqueMember are compared as DNs (ignoring the spaces around separators).
+package ldaptest
+
+import (
+	"bufio"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	ber "github.com/go-asn1-ber/asn1-ber"
+)
+
+// Entry is an entry in the directory of a Server.
+type Entry struct {
+	DN         string
+	Attributes map[string][]string
+
+	// Password, if set, is the password to use to bind as the entry.
+	Password string
+}
+
+// Server is an LDAP server listening on a system-chosen port on the loopback interface.
+type Server struct {
+	// URL is the URL of the server, of the form ldap://127.0.0.1:port (or ldaps:// for servers
+	// started with StartTLS).
+	URL string
+
+	// Entries is the directory. It must not be modified after the server is started.
+	Entries []*Entry
+
+	// RequireTLS, if true, makes the server reject binds on connections that are not secured with
+	// TLS (either with LDAPS or StartTLS).
+	RequireTLS bool
+
+	// Certificate is the PEM-encoded self-signed certificate that the server presents to TLS
+	// clients. It is valid for 127.0.0.1 and localhost.
+	Certificate string
+
+	listener  net.Listener
+	tlsConfig *tls.Config
+	wg        sync.WaitGroup
+
+	mu    sync.Mutex
+	conns map[net.Conn]struct{}
+	binds []string
+}
+
+// NewServer starts and returns a new Server with the given directory entries.
+func NewServer(entries ...*Entry) *Server {
+	s := NewUnstartedServer(entries...)
+	s.Start()
+	return s
+}
+
+// NewUnstartedServer returns a new Server but doesn't start it. After changing its configuration,
+// the caller should call Start or StartTLS.
+func NewUnstartedServer(entries ...*Entry) *Server {
+	cert, certPEM, err := selfSignedCertificate()
+	if err != nil {
+		panic(fmt.Sprintf("ldaptest: failed to generate certificate: %v", err))
+	}
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		panic(fmt.Sprintf("ldaptest: failed to listen on a port: %v", err))
+	}
+	return &Server{
+		Entries:     entries,
+		Certificate: certPEM,
+		listener:    l,
+		tlsConfig:   &tls.Config{Certificates: []tls.Certificate{cert}},
+		conns:       map[net.Conn]struct{}{},
+	}
+}
+
+// Start starts a server that accepts plain connections (which can be upgraded with StartTLS).
+func (s *Server) Start() {
+	s.URL = "ldap://" + s.listener.Addr().String()
+	s.serve(false)
+}
+
+// StartTLS starts a server that accepts LDAPS connections.
+func (s *Server) StartTLS() {
+	s.URL = "ldaps://" + s.listener.Addr().String()
+	s.serve(true)
+}
+
+// Close shuts down the server and blocks until all connections have been closed.
+func (s *Server) Close() {
+	s.listener.Close()
+	s.mu.Lock()
+	for c := range s.conns {
+		c.Close()
+	}
+	s.mu.Unlock()
+	s.wg.Wait()
+}
+
+// Binds returns the DNs of all successful binds, in order.
+func (s *Server) Binds() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.binds...)
+}
+
+func (s *Server) serve(useTLS bool) {
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		for {
+			c, err := s.listener.Accept()
+			if err != nil {
+				return
+			}
+			if useTLS {
+				c = tls.Server(c, s.tlsConfig)
+			}
+			s.mu.Lock()
+			s.conns[c] = struct{}{}
+			s.mu.Unlock()
+
+			s.wg.Add(1)
+			go func() {
+				defer s.wg.Done()
+				(&session{server: s, conn: c, tls: useTLS}).serve()
+				s.mu.Lock()
+				delete(s.conns, c)
+				s.mu.Unlock()
+				c.Close()
+			}()
+		}
+	}()
+}
+
+// Protocol operation tags (RFC 4511 section 4.2 and following).
+const (
+	opBindRequest       ber.Tag = 0
+	opBindResponse      ber.Tag = 1
+	opSearchRequest     ber.Tag = 3
+	opSearchResultEntry ber.Tag = 4
+	opSearchResultDone  ber.Tag = 5
+	opExtendedRequest   ber.Tag = 23
+	opExtendedResponse  ber.Tag = 24
+)
+
+// Result codes (RFC 4511 appendix A).
+const (
+	resultSuccess                 = 0
+	resultProtocolError           = 2
+	resultSizeLimitExceeded       = 4
+	resultConfidentialityRequired = 13
+	resultInvalidCredentials      = 49
+	resultInsufficientAccess      = 50
+)
+
+// session is a client connection to a Server.
+type session struct {
+	server *Server
+	conn   net.Conn
+	tls    bool
+	bound  string // the DN that the connection is authenticated as
+}
+
+func (c *session) serve() {
+	r := bufio.NewReader(c.conn)
+	for {
+		msg, err := ber.ReadPacket(r)
+		if err != nil || len(msg.Children) < 2 {
+			return
+		}
+		id, ok := msg.Children[0].Value.(int64)
+		if !ok {
+			return
+		}
+
+		op := msg.Children[1]
+		switch {
+		case is(op, ber.ClassApplication, opBindRequest):
+			c.bind(id, op)
+		case is(op, ber.ClassApplication, opSearchRequest):
+			c.search(id, op)
+		case is(op, ber.ClassApplication, opExtendedRequest):
+			if !c.startTLS(id, op) {
+				return
+			}
+			r = bufio.NewReader(c.conn)
+		default: // including unbind requests
+			return
+		}
+	}
+}
+
+func (c *session) bind(id int64, op *ber.Packet) {
+	if len(op.Children) != 3 || !is(op.Children[2], ber.ClassContext, 0) {
+		c.respond(id, opBindResponse, resultProtocolError, "only simple binds are supported")
+		return
+	}
+	dn, password := str(op.Children[1]), str(op.Children[2])
+
+	if c.server.RequireTLS && !c.tls {
+		c.respond(id, opBindResponse, resultConfidentialityRequired, "TLS is required")
+		return
+	}
+	for _, e := range c.server.Entries {
+		if strings.EqualFold(e.DN, dn) && e.Password != "" && e.Password == password {
+			c.bound = e.DN
+			c.server.mu.Lock()
+			c.server.binds = append(c.server.binds, e.DN)
+			c.server.mu.Unlock()
+			c.respond(id, opBindResponse, resultSuccess, "")
+			return
+		}
+	}
+	c.bound = ""
+	c.respond(id, opBindResponse, resultInvalidCredentials, "invalid credentials")
+}
+
+func (c *session) search(id int64, op *ber.Packet) {
+	if len(op.Children) != 8 {
+		c.respond(id, opSearchResultDone, resultProtocolError, "malformed search request")
+		return
+	}
+	if c.bound == "" {
+		c.respond(id, opSearchResultDone, resultInsufficientAccess, "anonymous searches are not allowed")
+		return
+	}
+
+	baseDN := str(op.Children[0])
+	scope, _ := op.Children[1].Value.(int64)
+	sizeLimit, _ := op.Children[3].Value.(int64)
+	filter := op.Children[6]
+	var attrs []string
+	for _, a := range op.Children[7].Children {
+		attrs = append(attrs, str(a))
+	}
+
+	var n int64
+	for _, e := range c.server.Entries {
+		if !inScope(e.DN, baseDN, scope) || !matches(e, filter) {
+			continue
+		}
+		if sizeLimit > 0 && n == sizeLimit {
+			c.respond(id, opSearchResultDone, resultSizeLimitExceeded, "")
+			return
+		}
+		n++
+		c.write(id, resultEntry(e, attrs))
+	}
+	c.respond(id, opSearchResultDone, resultSuccess, "")
+}
+
+func (c *session) startTLS(id int64, op *ber.Packet) bool {
+	const startTLSOID = "1.3.6.1.4.1.1466.20037"
+	if len(op.Children) == 0 || str(op.Children[0]) != startTLSOID || c.tls {
+		c.respond(id, opExtendedResponse, resultProtocolError, "unsupported extended operation")
+		return true
+	}
+	c.respond(id, opExtendedResponse, resultSuccess, "")
+
+	conn := tls.Server(c.conn, c.server.tlsConfig)
+	if err := conn.Handshake(); err != nil {
+		return false
+	}
+	c.server.mu.Lock()
+	delete(c.server.conns, c.conn)
+	c.server.conns[conn] = struct{}{}
+	c.server.mu.Unlock()
+	c.conn, c.tls = conn, true
+	return true
+}
+
+func (c *session) respond(id int64, tag ber.Tag, code int64, message string) {
+	op := ber.Encode(ber.ClassApplication, ber.TypeConstructed, tag, nil, "")
+	op.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagEnumerated, code, ""))
+	op.AppendChild(octetString(""))
+	op.AppendChild(octetString(message))
+	c.write(id, op)
+}
+
+func (c *session) write(id int64, op *ber.Packet) {
+	msg := ber.NewSequence("")
+	msg.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagInteger, id, ""))
+	msg.AppendChild(op)
+	_, _ = c.conn.Write(msg.Bytes())
+}
+
+func resultEntry(e *Entry, attrs []string) *ber.Packet {
+	all := len(attrs) == 0
+	for _, a := range attrs {
+		if a == "*" {
+			all = true
+		}
+	}
+
+	list := ber.NewSequence("")
+	for name, values := range e.Attributes {
+		if !all && !containsFold(attrs, name) {
+			continue
+		}
+		set := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSet, nil, "")
+		for _, v := range values {
+			set.AppendChild(octetString(v))
+		}
+		attr := ber.NewSequence("")
+		attr.AppendChild(octetString(name))
+		attr.AppendChild(set)
+		list.AppendChild(attr)
+	}
+	op := ber.Encode(ber.ClassApplication, ber.TypeConstructed, opSearchResultEntry, nil, "")
+	op.AppendChild(octetString(e.DN))
+	op.AppendChild(list)
+	return op
+}
+
+func octetString(s string) *ber.Packet {
+	return ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, s, "")
+}
+
+// is reports whether the packet has the given class and tag.
+func is(p *ber.Packet, class ber.Class, tag ber.Tag) bool {
+	return p.ClassType == class && p.Tag == tag
+}
+
+// str returns the contents of a primitive packet as a string.
+func str(p *ber.Packet) string {
+	if s, ok := p.Value.(string); ok {
+		return s
+	}
+	if p.Data == nil {
+		return ""
+	}
+	return p.Data.String()
+}
+
+func inScope(dn, baseDN string, scope int64) bool {
+	dn, baseDN = strings.ToLower(dn), strings.ToLower(baseDN)
+	switch scope {
+	case 0: // baseObject
+		return dn == baseDN
+	case 1: // singleLevel
+		i := strings.IndexByte(dn, ',')
+		return i >= 0 && dn[i+1:] == baseDN
+	default: // wholeSubtree
+		return baseDN == "" || dn == baseDN || strings.HasSuffix(dn, ","+baseDN)
+	}
+}
+
+// matches evaluates the BER-encoded filter against the entry.
+func matches(e *Entry, filter *ber.Packet) bool {
+	switch filter.Tag {
+	case 0: // and
+		for _, f := range filter.Children {
+			if !matches(e, f) {
+				return false
+			}
+		}
+		return true
+	case 1: // or
+		for _, f := range filter.Children {
+			if matches(e, f) {
+				return true
+			}
+		}
+		return false
+	case 2: // not
+		return len(filter.Children) == 1 && !matches(e, filter.Children[0])
+	case 7: // present
+		return len(values(e, str(filter))) > 0
+	}
+
+	if len(filter.Children) != 2 {
+		return false
+	}
+	name, value := str(filter.Children[0]), str(filter.Children[1])
+	for _, v := range values(e, name) {
+		v = strings.ToLower(v)
+		switch filter.Tag {
+		case 3, 8: // equalityMatch, approxMatch
+			if v == strings.ToLower(value) {
+				return true
+			}
+			if isDNAttribute(name) && normalizeDN(v) == normalizeDN(value) {
+				return true
+			}
+		case 4: // substrings
+			if matchesSubstrings(v, filter.Children[1].Children) {
+				return true
+			}
+		case 5: // greaterOrEqual
+			if v >= strings.ToLower(value) {
+				return true
+			}
+		case 6: // lessOrEqual
+			if v <= strings.ToLower(value) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func matchesSubstrings(v string, substrings []*ber.Packet) bool {
+	for _, s := range substrings {
+		sub := strings.ToLower(str(s))
+		switch s.Tag {
+		case 0: // initial
+			if !strings.HasPrefix(v, sub) {
+				return false
+			}
+			v = v[len(sub):]
+		case 1: // any
+			i := strings.Index(v, sub)
+			if i < 0 {
+				return false
+			}
+			v = v[i+len(sub):]
+		case 2: // final
+			if !strings.HasSuffix(v, sub) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func values(e *Entry, name string) []string {
+	for k, v := range e.Attributes {
+		if strings.EqualFold(k, name) {
+			return v
+		}
+	}
+	return nil
+}
+
+func isDNAttribute(name string) bool {
+	return strings.EqualFold(name, "member") || strings.EqualFold(name, "uniqueMember")
+}
+
+var dnSeparatorSpaces = regexp.MustCompile(` *([,=+]) *`)
+
+// normalizeDN lowercases the DN and removes the spaces around its separators. It doesn't handle
+// escaped characters.
+func normalizeDN(dn string) string {
+	return dnSeparatorSpaces.ReplaceAllString(strings.ToLower(strings.TrimSpace(dn)), "$1")
+}
+
+func containsFold(list []string, s string) bool {
+	for _, v := range list {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
+
+func selfSignedCertificate() (tls.Certificate, string, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return tls.Certificate{}, "", err
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "ldaptest"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(24 * time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		IPAddresses:           []net.IP{net.IPv4(127, 0, 0, 1)},
+		DNSNames:              []string{"localhost"},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		return tls.Certificate{}, "", err
+	}
+	cert := tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}
+	return cert, string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})), nil
+}
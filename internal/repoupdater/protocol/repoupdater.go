@@ -170,6 +170,18 @@ type ChangesetSyncResponse struct {
 	Error string
 }
 
+// PermsSyncRequest is a request to sync permissions of users and repositories as soon as
+// possible.
+type PermsSyncRequest struct {
+	UserIDs []int32      `json:"user_ids"`
+	RepoIDs []api.RepoID `json:"repo_ids"`
+}
+
+// PermsSyncResponse is a response to sync permissions.
+type PermsSyncResponse struct {
+	Error string
+}
+
 // ExternalServiceSyncRequest is a request to sync a specific external service eagerly.
 //
 // The FrontendAPI is one of the issuers of this request. It does so when creating or
@@ -185,6 +197,34 @@ type ExternalServiceSyncResult struct {
 	Error           string
 }
 
+// ExternalServiceSyncPreviewRequest is a request to preview the repositories
+// that a sync of an external service with a proposed configuration would
+// change, without persisting anything.
+//
+// The ID of ExternalService identifies the stored external service whose
+// configuration would change. It is zero for a new external service.
+type ExternalServiceSyncPreviewRequest struct {
+	ExternalService api.ExternalService
+}
+
+// ExternalServiceSyncPreviewResult is the result type of an external service's
+// sync preview request.
+type ExternalServiceSyncPreviewResult struct {
+	Added      RepoDiffSummary
+	Modified   RepoDiffSummary
+	Deleted    RepoDiffSummary
+	Unmodified RepoDiffSummary
+}
+
+// RepoDiffSummary summarizes the repositories that a sync changes in the same
+// way (e.g. that are all added).
+type RepoDiffSummary struct {
+	// Count is the total number of repositories.
+	Count int
+	// Sample is the names of some of the repositories, in alphabetical order.
+	Sample []api.RepoName
+}
+
 type CloningProgress struct {
 	Message string
 }
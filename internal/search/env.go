@@ -19,6 +19,8 @@ import (
 var (
 	searcherURL = env.Get("SEARCHER_URL", "k8s+http://searcher:3181", "searcher server URL")
 
+	replacerURL = env.Get("REPLACER_URL", "http://replacer:3185", "replacer server URL")
+
 	searcherURLsOnce sync.Once
 	searcherURLs     *endpoint.Map
 
@@ -40,6 +42,12 @@ func SearcherURLs() *endpoint.Map {
 	return searcherURLs
 }
 
+// ReplacerURL returns the URL of the replacer service, which runs comby
+// match/rewrite templates over a repository revision.
+func ReplacerURL() string {
+	return replacerURL
+}
+
 func Indexed() *backend.Zoekt {
 	indexedSearchOnce.Do(func() {
 		indexedSearch = &backend.Zoekt{}
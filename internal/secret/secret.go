@@ -0,0 +1,79 @@
+// Package secret encrypts secrets that are stored at rest, such as in the
+// site configuration, with a key shared by all Sourcegraph services.
+package secret
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"io"
+
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/internal/env"
+)
+
+var key = env.Get("SRC_SECRET_KEY", "", "Key used to encrypt secrets stored at rest, such as the campaigns signing key. Must be the same for all services.")
+
+// ErrNoKey is returned by Encrypt and Decrypt if SRC_SECRET_KEY is not set.
+var ErrNoKey = errors.New("SRC_SECRET_KEY is not set")
+
+// MockKey sets the key used by Encrypt and Decrypt. It is meant to be used in
+// tests only.
+func MockKey(k string) {
+	key = k
+}
+
+// Encrypt encrypts plaintext with AES-GCM and returns the base64 encoded
+// nonce and ciphertext.
+func Encrypt(plaintext []byte) (string, error) {
+	aead, err := newAEAD()
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", errors.Wrap(err, "generating nonce")
+	}
+
+	return base64.StdEncoding.EncodeToString(aead.Seal(nonce, nonce, plaintext, nil)), nil
+}
+
+// Decrypt decrypts a secret encrypted with Encrypt.
+func Decrypt(encrypted string) ([]byte, error) {
+	aead, err := newAEAD()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := base64.StdEncoding.DecodeString(encrypted)
+	if err != nil {
+		return nil, errors.Wrap(err, "decoding secret")
+	}
+	if len(data) < aead.NonceSize() {
+		return nil, errors.New("secret is too short")
+	}
+
+	nonce, ciphertext := data[:aead.NonceSize()], data[aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "decrypting secret")
+	}
+	return plaintext, nil
+}
+
+func newAEAD() (cipher.AEAD, error) {
+	if key == "" {
+		return nil, ErrNoKey
+	}
+
+	// Derive a key of the length AES-256 requires from SRC_SECRET_KEY.
+	k := sha256.Sum256([]byte(key))
+	block, err := aes.NewCipher(k[:])
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
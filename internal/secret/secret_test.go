@@ -0,0 +1,41 @@
+package secret
+
+import (
+	"testing"
+)
+
+func TestEncryptDecrypt(t *testing.T) {
+	defer MockKey("")
+
+	MockKey("")
+	if _, err := Encrypt([]byte("secret")); err != ErrNoKey {
+		t.Fatalf("want ErrNoKey, have %v", err)
+	}
+
+	MockKey("correct horse battery staple")
+	encrypted, err := Encrypt([]byte("secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	decrypted, err := Decrypt(encrypted)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if have, want := string(decrypted), "secret"; have != want {
+		t.Fatalf("want %q, have %q", want, have)
+	}
+
+	again, err := Encrypt([]byte("secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again == encrypted {
+		t.Fatal("encrypting the same secret twice produced the same ciphertext")
+	}
+
+	MockKey("wrong horse")
+	if _, err := Decrypt(encrypted); err == nil {
+		t.Fatal("want error decrypting with the wrong key, have nil")
+	}
+}
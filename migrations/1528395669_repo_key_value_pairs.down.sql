@@ -0,0 +1,6 @@
+BEGIN;
+
+DROP INDEX IF EXISTS repo_key_value_pairs_gin_idx;
+ALTER TABLE repo DROP COLUMN IF EXISTS key_value_pairs;
+
+COMMIT;
@@ -0,0 +1,8 @@
+BEGIN;
+
+ALTER TABLE repo ADD COLUMN key_value_pairs jsonb NOT NULL DEFAULT '{}'::jsonb;
+ALTER TABLE repo ADD CONSTRAINT repo_key_value_pairs_check CHECK (jsonb_typeof(key_value_pairs) = 'object'::text);
+
+CREATE INDEX repo_key_value_pairs_gin_idx ON repo USING gin (key_value_pairs);
+
+COMMIT;
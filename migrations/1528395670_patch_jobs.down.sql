@@ -0,0 +1,6 @@
+BEGIN;
+
+DROP TABLE IF EXISTS patch_jobs;
+ALTER TABLE patch_sets DROP COLUMN IF EXISTS replacement;
+
+COMMIT;
@@ -0,0 +1,21 @@
+BEGIN;
+
+ALTER TABLE patch_sets ADD COLUMN replacement jsonb;
+
+CREATE TABLE patch_jobs (
+  id bigserial PRIMARY KEY,
+  patch_set_id bigint NOT NULL REFERENCES patch_sets(id) ON DELETE CASCADE DEFERRABLE,
+  repo_id integer NOT NULL REFERENCES repo(id) ON DELETE CASCADE DEFERRABLE,
+  patch_id bigint REFERENCES patches(id) ON DELETE SET NULL DEFERRABLE,
+  error text,
+  started_at timestamp with time zone,
+  finished_at timestamp with time zone,
+  created_at timestamp with time zone NOT NULL DEFAULT now(),
+  updated_at timestamp with time zone NOT NULL DEFAULT now(),
+  CONSTRAINT patch_jobs_unique UNIQUE (patch_set_id, repo_id)
+);
+
+CREATE INDEX patch_jobs_started_at ON patch_jobs(started_at);
+CREATE INDEX patch_jobs_finished_at ON patch_jobs(finished_at);
+
+COMMIT;
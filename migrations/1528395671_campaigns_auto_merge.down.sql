@@ -0,0 +1,7 @@
+BEGIN;
+
+ALTER TABLE campaigns DROP COLUMN IF EXISTS auto_merge;
+ALTER TABLE campaigns DROP COLUMN IF EXISTS merge_method;
+ALTER TABLE campaigns DROP COLUMN IF EXISTS merges_per_hour;
+
+COMMIT;
@@ -0,0 +1,7 @@
+BEGIN;
+
+ALTER TABLE campaigns ADD COLUMN auto_merge boolean NOT NULL DEFAULT false;
+ALTER TABLE campaigns ADD COLUMN merge_method text NOT NULL DEFAULT 'MERGE';
+ALTER TABLE campaigns ADD COLUMN merges_per_hour integer NOT NULL DEFAULT 0;
+
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN;
+
+ALTER TABLE changesets ADD COLUMN rebase_state text;
+ALTER TABLE changesets ADD COLUMN base_commit text;
+
+COMMIT;
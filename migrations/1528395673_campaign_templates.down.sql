@@ -0,0 +1,9 @@
+BEGIN;
+
+ALTER TABLE campaigns DROP COLUMN IF EXISTS template_version_id;
+ALTER TABLE campaigns DROP COLUMN IF EXISTS template_params;
+
+DROP TABLE IF EXISTS campaign_template_versions;
+DROP TABLE IF EXISTS campaign_templates;
+
+COMMIT;
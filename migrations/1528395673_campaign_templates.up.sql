@@ -0,0 +1,34 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS campaign_templates (
+  id bigserial PRIMARY KEY,
+  name text NOT NULL,
+  description text,
+  author_id integer NOT NULL REFERENCES users(id) ON DELETE CASCADE DEFERRABLE,
+  namespace_user_id integer REFERENCES users(id) ON DELETE CASCADE DEFERRABLE,
+  namespace_org_id integer REFERENCES orgs(id) ON DELETE CASCADE DEFERRABLE,
+  latest_version integer NOT NULL DEFAULT 1,
+  created_at timestamp with time zone NOT NULL DEFAULT now(),
+  updated_at timestamp with time zone NOT NULL DEFAULT now(),
+  deleted_at timestamp with time zone,
+  CONSTRAINT campaign_templates_name_not_blank CHECK (name <> ''),
+  CONSTRAINT campaign_templates_has_1_namespace CHECK ((namespace_user_id IS NULL) <> (namespace_org_id IS NULL))
+);
+
+CREATE INDEX IF NOT EXISTS campaign_templates_namespace_user_id ON campaign_templates(namespace_user_id);
+CREATE INDEX IF NOT EXISTS campaign_templates_namespace_org_id ON campaign_templates(namespace_org_id);
+
+CREATE TABLE IF NOT EXISTS campaign_template_versions (
+  id bigserial PRIMARY KEY,
+  template_id bigint NOT NULL REFERENCES campaign_templates(id) ON DELETE CASCADE DEFERRABLE,
+  version integer NOT NULL,
+  spec jsonb NOT NULL CHECK (jsonb_typeof(spec) = 'object'),
+  author_id integer NOT NULL REFERENCES users(id) ON DELETE CASCADE DEFERRABLE,
+  created_at timestamp with time zone NOT NULL DEFAULT now(),
+  CONSTRAINT campaign_template_versions_template_id_version_unique UNIQUE (template_id, version)
+);
+
+ALTER TABLE campaigns ADD COLUMN template_version_id bigint REFERENCES campaign_template_versions(id) ON DELETE SET NULL DEFERRABLE;
+ALTER TABLE campaigns ADD COLUMN template_params jsonb NOT NULL DEFAULT '{}' CHECK (jsonb_typeof(template_params) = 'object');
+
+COMMIT;
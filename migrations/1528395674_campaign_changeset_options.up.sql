@@ -0,0 +1,5 @@
+BEGIN;
+
+ALTER TABLE campaigns ADD COLUMN IF NOT EXISTS changeset_options jsonb NOT NULL DEFAULT '{}'::jsonb CHECK (jsonb_typeof(changeset_options) = 'object');
+
+COMMIT;
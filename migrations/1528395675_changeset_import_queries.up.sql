@@ -0,0 +1,18 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS changeset_import_queries (
+  id bigserial PRIMARY KEY,
+  campaign_id bigint NOT NULL REFERENCES campaigns(id) ON DELETE CASCADE DEFERRABLE,
+  external_service_id bigint NOT NULL REFERENCES external_services(id) ON DELETE CASCADE DEFERRABLE,
+  query text NOT NULL,
+  last_imported_at timestamp with time zone,
+  last_error text NOT NULL DEFAULT '',
+  created_at timestamp with time zone NOT NULL DEFAULT now(),
+  updated_at timestamp with time zone NOT NULL DEFAULT now(),
+  CONSTRAINT changeset_import_queries_query_not_blank CHECK (query <> ''),
+  CONSTRAINT changeset_import_queries_unique UNIQUE (campaign_id, external_service_id, query)
+);
+
+CREATE INDEX IF NOT EXISTS changeset_import_queries_external_service_id ON changeset_import_queries(external_service_id);
+
+COMMIT;
@@ -0,0 +1,32 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS changeset_actions (
+  id bigserial PRIMARY KEY,
+  campaign_id bigint NOT NULL REFERENCES campaigns(id) ON DELETE CASCADE DEFERRABLE,
+  user_id integer NOT NULL REFERENCES users(id) ON DELETE CASCADE DEFERRABLE,
+  type text NOT NULL,
+  body text NOT NULL DEFAULT '',
+  labels jsonb NOT NULL DEFAULT '[]'::jsonb,
+  reviewers jsonb NOT NULL DEFAULT '[]'::jsonb,
+  created_at timestamp with time zone NOT NULL DEFAULT now(),
+  updated_at timestamp with time zone NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS changeset_actions_campaign_id ON changeset_actions(campaign_id);
+
+CREATE TABLE IF NOT EXISTS changeset_action_jobs (
+  id bigserial PRIMARY KEY,
+  changeset_action_id bigint NOT NULL REFERENCES changeset_actions(id) ON DELETE CASCADE DEFERRABLE,
+  changeset_id bigint NOT NULL REFERENCES changesets(id) ON DELETE CASCADE DEFERRABLE,
+  error text,
+  started_at timestamp with time zone,
+  finished_at timestamp with time zone,
+  created_at timestamp with time zone NOT NULL DEFAULT now(),
+  updated_at timestamp with time zone NOT NULL DEFAULT now(),
+  CONSTRAINT changeset_action_jobs_unique UNIQUE (changeset_action_id, changeset_id)
+);
+
+CREATE INDEX IF NOT EXISTS changeset_action_jobs_started_at ON changeset_action_jobs(started_at);
+CREATE INDEX IF NOT EXISTS changeset_action_jobs_finished_at ON changeset_action_jobs(finished_at);
+
+COMMIT;
@@ -0,0 +1,32 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS campaign_notification_subscriptions (
+  id bigserial PRIMARY KEY,
+  campaign_id bigint NOT NULL REFERENCES campaigns(id) ON DELETE CASCADE DEFERRABLE,
+  user_id integer NOT NULL REFERENCES users(id) ON DELETE CASCADE DEFERRABLE,
+  channel text NOT NULL,
+  url text NOT NULL DEFAULT '',
+  secret text NOT NULL DEFAULT '',
+  transitions jsonb NOT NULL DEFAULT '[]'::jsonb,
+  created_at timestamp with time zone NOT NULL DEFAULT now(),
+  updated_at timestamp with time zone NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS campaign_notification_subscriptions_campaign_id ON campaign_notification_subscriptions(campaign_id);
+
+CREATE TABLE IF NOT EXISTS campaign_notifications (
+  id bigserial PRIMARY KEY,
+  subscription_id bigint NOT NULL REFERENCES campaign_notification_subscriptions(id) ON DELETE CASCADE DEFERRABLE,
+  changeset_id bigint NOT NULL REFERENCES changesets(id) ON DELETE CASCADE DEFERRABLE,
+  transition text NOT NULL,
+  error text,
+  started_at timestamp with time zone,
+  finished_at timestamp with time zone,
+  created_at timestamp with time zone NOT NULL DEFAULT now(),
+  updated_at timestamp with time zone NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS campaign_notifications_subscription_id ON campaign_notifications(subscription_id);
+CREATE INDEX IF NOT EXISTS campaign_notifications_started_at ON campaign_notifications(started_at);
+
+COMMIT;
@@ -0,0 +1,7 @@
+BEGIN;
+
+ALTER TABLE access_tokens DROP COLUMN IF EXISTS expires_at;
+ALTER TABLE access_tokens DROP COLUMN IF EXISTS allowed_ips;
+ALTER TABLE access_tokens DROP COLUMN IF EXISTS repo_patterns;
+
+COMMIT;
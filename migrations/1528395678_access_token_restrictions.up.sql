@@ -0,0 +1,7 @@
+BEGIN;
+
+ALTER TABLE access_tokens ADD COLUMN expires_at timestamptz;
+ALTER TABLE access_tokens ADD COLUMN allowed_ips text[] NOT NULL DEFAULT '{}';
+ALTER TABLE access_tokens ADD COLUMN repo_patterns text[] NOT NULL DEFAULT '{}';
+
+COMMIT;
@@ -0,0 +1,5 @@
+BEGIN;
+
+ALTER TABLE org_members DROP COLUMN IF EXISTS role;
+
+COMMIT;
@@ -0,0 +1,12 @@
+BEGIN;
+
+-- Existing members become members, which doesn't let them manage the
+-- organization, and the first member of each organization becomes its owner.
+-- Owners can then promote other members to admins.
+ALTER TABLE org_members ADD COLUMN role text NOT NULL DEFAULT 'MEMBER';
+ALTER TABLE org_members ADD CONSTRAINT org_members_role_check CHECK (role IN ('OWNER', 'ADMIN', 'MEMBER', 'READ_ONLY'));
+
+UPDATE org_members SET role = 'OWNER'
+WHERE id IN (SELECT DISTINCT ON (org_id) id FROM org_members ORDER BY org_id, id);
+
+COMMIT;
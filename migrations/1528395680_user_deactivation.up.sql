@@ -0,0 +1,5 @@
+BEGIN;
+
+ALTER TABLE users ADD COLUMN deactivated_at timestamp with time zone;
+
+COMMIT;
@@ -0,0 +1,14 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS user_group_permissions (
+    user_id integer NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    service_type text NOT NULL,
+    service_id text NOT NULL,
+    groups text[] NOT NULL,
+    org_ids integer[] NOT NULL,
+    repo_patterns text[] NOT NULL,
+    updated_at timestamp with time zone NOT NULL,
+    CONSTRAINT user_group_permissions_user_service_unique UNIQUE (user_id, service_type, service_id)
+);
+
+COMMIT;
@@ -0,0 +1,7 @@
+BEGIN;
+
+DROP TRIGGER IF EXISTS trig_reject_audit_logs_update ON audit_logs;
+DROP FUNCTION IF EXISTS reject_audit_logs_update();
+DROP TABLE IF EXISTS audit_logs;
+
+COMMIT;
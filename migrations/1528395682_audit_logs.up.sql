@@ -0,0 +1,33 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS audit_logs (
+    id bigserial PRIMARY KEY,
+    action text NOT NULL,
+    actor_user_id integer,
+    actor_ip text NOT NULL DEFAULT '',
+    actor_user_agent text NOT NULL DEFAULT '',
+    target_type text NOT NULL,
+    target_id text NOT NULL,
+    before jsonb,
+    after jsonb,
+    created_at timestamp with time zone NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS audit_logs_created_at ON audit_logs USING btree (created_at);
+CREATE INDEX IF NOT EXISTS audit_logs_actor_user_id ON audit_logs USING btree (actor_user_id);
+CREATE INDEX IF NOT EXISTS audit_logs_target ON audit_logs USING btree (target_type, target_id);
+
+-- Entries of the audit log must never be modified once recorded. Deletions are allowed so that
+-- the retention policy can be enforced.
+CREATE OR REPLACE FUNCTION reject_audit_logs_update() RETURNS trigger
+    LANGUAGE plpgsql
+    AS $$
+BEGIN
+  RAISE EXCEPTION 'audit_logs is append-only';
+END;
+$$;
+
+DROP TRIGGER IF EXISTS trig_reject_audit_logs_update ON audit_logs;
+CREATE TRIGGER trig_reject_audit_logs_update BEFORE UPDATE ON audit_logs FOR EACH ROW EXECUTE PROCEDURE reject_audit_logs_update();
+
+COMMIT;
@@ -0,0 +1,10 @@
+BEGIN;
+
+ALTER TABLE users DROP COLUMN IF EXISTS locked_until;
+ALTER TABLE users DROP COLUMN IF EXISTS failed_sign_in_attempts;
+
+DROP TABLE IF EXISTS user_webauthn_credentials;
+DROP TABLE IF EXISTS user_recovery_codes;
+DROP TABLE IF EXISTS user_totp;
+
+COMMIT;
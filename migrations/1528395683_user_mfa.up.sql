@@ -0,0 +1,40 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS user_totp (
+    user_id integer PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    -- The base32-encoded shared secret, encrypted with SRC_SECRET_KEY.
+    encrypted_secret text NOT NULL,
+    -- NULL until the user confirms the enrollment with a valid code.
+    enabled_at timestamp with time zone,
+    -- The time step of the last code used, to prevent codes from being reused.
+    last_used_step bigint NOT NULL DEFAULT 0,
+    created_at timestamp with time zone NOT NULL DEFAULT now()
+);
+
+CREATE TABLE IF NOT EXISTS user_recovery_codes (
+    id serial PRIMARY KEY,
+    user_id integer NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    code_sha256 bytea NOT NULL,
+    used_at timestamp with time zone,
+    created_at timestamp with time zone NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS user_recovery_codes_user_id ON user_recovery_codes USING btree (user_id);
+
+CREATE TABLE IF NOT EXISTS user_webauthn_credentials (
+    id serial PRIMARY KEY,
+    user_id integer NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name text NOT NULL,
+    credential_id bytea NOT NULL UNIQUE,
+    public_key bytea NOT NULL,
+    sign_count bigint NOT NULL DEFAULT 0,
+    created_at timestamp with time zone NOT NULL DEFAULT now(),
+    last_used_at timestamp with time zone
+);
+
+CREATE INDEX IF NOT EXISTS user_webauthn_credentials_user_id ON user_webauthn_credentials USING btree (user_id);
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS failed_sign_in_attempts integer NOT NULL DEFAULT 0;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS locked_until timestamp with time zone;
+
+COMMIT;
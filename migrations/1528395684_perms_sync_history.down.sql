@@ -0,0 +1,5 @@
+BEGIN;
+
+DROP TABLE IF EXISTS perms_sync_history;
+
+COMMIT;
@@ -0,0 +1,24 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS perms_sync_history (
+    id bigserial PRIMARY KEY,
+    -- Exactly one of user_id and repo_id is set, depending on what was synced.
+    user_id integer REFERENCES users(id) ON DELETE CASCADE,
+    repo_id integer REFERENCES repo(id) ON DELETE CASCADE,
+    -- The service IDs of the authz providers that permissions were fetched from.
+    providers text[] NOT NULL DEFAULT '{}',
+    started_at timestamp with time zone NOT NULL,
+    finished_at timestamp with time zone NOT NULL,
+    num_repos integer NOT NULL DEFAULT 0,
+    num_users integer NOT NULL DEFAULT 0,
+    num_pending_accounts integer NOT NULL DEFAULT 0,
+    -- Whether an authz provider returned an error and the permissions were saved from its partial results.
+    partial boolean NOT NULL DEFAULT false,
+    error text,
+    CONSTRAINT perms_sync_history_user_or_repo CHECK ((user_id IS NULL) <> (repo_id IS NULL))
+);
+
+CREATE INDEX IF NOT EXISTS perms_sync_history_user_id ON perms_sync_history USING btree (user_id, id) WHERE user_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS perms_sync_history_repo_id ON perms_sync_history USING btree (repo_id, id) WHERE repo_id IS NOT NULL;
+
+COMMIT;
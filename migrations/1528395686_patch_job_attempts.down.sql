@@ -0,0 +1,5 @@
+BEGIN;
+
+ALTER TABLE patch_jobs DROP COLUMN IF EXISTS attempts;
+
+COMMIT;
@@ -0,0 +1,5 @@
+BEGIN;
+
+ALTER TABLE patch_jobs ADD COLUMN attempts integer NOT NULL DEFAULT 0;
+
+COMMIT;
@@ -0,0 +1,5 @@
+BEGIN;
+
+ALTER TABLE changesets ADD COLUMN pushed_commit text;
+
+COMMIT;
@@ -0,0 +1,10 @@
+BEGIN;
+
+-- The ID of the last audit log entry exported to each destination of the audit log export.
+CREATE TABLE IF NOT EXISTS audit_log_export_cursors (
+    sink text PRIMARY KEY,
+    last_exported_id bigint NOT NULL,
+    updated_at timestamp with time zone NOT NULL DEFAULT now()
+);
+
+COMMIT;
@@ -104,6 +104,46 @@
 // 1528395667_index_boolean_fields_on_repo.up.sql (187B)
 // 1528395668_campaign_description_nullable.down.sql (144B)
 // 1528395668_campaign_description_nullable.up.sql (143B)
+// 1528395669_repo_key_value_pairs.down.sql (124B)
+// 1528395669_repo_key_value_pairs.up.sql (292B)
+// 1528395670_patch_jobs.down.sql (108B)
+// 1528395670_patch_jobs.up.sql (770B)
+// 1528395671_campaigns_auto_merge.down.sql (192B)
+// 1528395671_campaigns_auto_merge.up.sql (247B)
+// 1528395672_changesets_rebase_state.down.sql (134B)
+// 1528395672_changesets_rebase_state.up.sql (122B)
+// 1528395673_campaign_templates.down.sql (234B)
+// 1528395673_campaign_templates.up.sql (1.745kB)
+// 1528395674_campaign_changeset_options.down.sql (80B)
+// 1528395674_campaign_changeset_options.up.sql (169B)
+// 1528395675_changeset_import_queries.down.sql (64B)
+// 1528395675_changeset_import_queries.up.sql (811B)
+// 1528395676_changeset_actions.down.sql (101B)
+// 1528395676_changeset_actions.up.sql (1.394kB)
+// 1528395677_campaign_notifications.down.sql (120B)
+// 1528395677_campaign_notifications.up.sql (1.411kB)
+// 1528395678_access_token_restrictions.down.sql (201B)
+// 1528395678_access_token_restrictions.up.sql (238B)
+// 1528395679_org_member_roles.down.sql (69B)
+// 1528395679_org_member_roles.up.sql (532B)
+// 1528395680_user_deactivation.down.sql (73B)
+// 1528395680_user_deactivation.up.sql (87B)
+// 1528395681_user_group_permissions.down.sql (62B)
+// 1528395681_user_group_permissions.up.sql (449B)
+// 1528395682_audit_logs.down.sql (170B)
+// 1528395682_audit_logs.up.sql (1.171kB)
+// 1528395683_user_mfa.down.sql (259B)
+// 1528395683_user_mfa.up.sql (1.614kB)
+// 1528395684_perms_sync_history.down.sql (58B)
+// 1528395684_perms_sync_history.up.sql (1.182kB)
+// 1528395685_user_sessions.down.sql (53B)
+// 1528395685_user_sessions.up.sql (643B)
+// 1528395686_patch_job_attempts.down.sql (72B)
+// 1528395686_patch_job_attempts.up.sql (88B)
+// 1528395687_changesets_pushed_commit.down.sql (77B)
+// 1528395687_changesets_pushed_commit.up.sql (71B)
+// 1528395688_audit_log_export_cursors.down.sql (64B)
+// 1528395688_audit_log_export_cursors.up.sql (294B)
 
 package migrations
 
@@ -2252,6 +2292,806 @@ func _1528395668_campaign_description_nullableUpSql() (*asset, error) {
 	return a, nil
 }
 
+var __1528395669_repo_key_value_pairsDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x7c\x00\x83\xff\x42\x45\x47\x49\x4e\x3b\x0a\x0a\x44\x52\x4f\x50\x20\x49\x4e\x44\x45\x58\x20\x49\x46\x20\x45\x58\x49\x53\x54\x53\x20\x72\x65\x70\x6f\x5f\x6b\x65\x79\x5f\x76\x61\x6c\x75\x65\x5f\x70\x61\x69\x72\x73\x5f\x67\x69\x6e\x5f\x69\x64\x78\x3b\x0a\x41\x4c\x54\x45\x52\x20\x54\x41\x42\x4c\x45\x20\x72\x65\x70\x6f\x20\x44\x52\x4f\x50\x20\x43\x4f\x4c\x55\x4d\x4e\x20\x49\x46\x20\x45\x58\x49\x53\x54\x53\x20\x6b\x65\x79\x5f\x76\x61\x6c\x75\x65\x5f\x70\x61\x69\x72\x73\x3b\x0a\x0a\x43\x4f\x4d\x4d\x49\x54\x3b\x0a\x03\x00\xbd\x5e\x49\xce\x7c\x00\x00\x00")
+
+func _1528395669_repo_key_value_pairsDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395669_repo_key_value_pairsDownSql,
+		"1528395669_repo_key_value_pairs.down.sql",
+	)
+}
+
+func _1528395669_repo_key_value_pairsDownSql() (*asset, error) {
+	bytes, err := _1528395669_repo_key_value_pairsDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395669_repo_key_value_pairs.down.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x35, 0xcd, 0xf4, 0xe4, 0xef, 0xdc, 0x74, 0x6d, 0x17, 0x90, 0x5b, 0xd0, 0x6b, 0xd, 0xf6, 0x48, 0x12, 0x1c, 0x7e, 0xf7, 0x29, 0x48, 0xef, 0xa3, 0xa7, 0x64, 0x24, 0x79, 0x64, 0x93, 0xdd, 0x37}}
+	return a, nil
+}
+
+var __1528395669_repo_key_value_pairsUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x74\x8d\xc1\x4e\xc2\x40\x14\x45\xf7\xf3\x15\x77\x57\xf8\x85\x4e\x5c\x0c\xd3\x27\x4e\x9c\xbe\x26\xe5\x35\x71\x37\x81\x3a\x62\xc1\xb4\x0d\x8c\x06\x62\xfc\x77\x93\x59\xa2\x6c\xef\xb9\x39\x67\x45\x6b\xc7\x5a\x29\xe3\x85\x5a\x88\x59\x79\xc2\x29\xce\x13\x4c\x55\xc1\x36\xbe\xab\x19\xc7\x78\x0d\x5f\xdb\x8f\xcf\x18\xe6\xed\x70\x3a\xe3\x70\x9e\xc6\x1d\xb8\x11\x70\xe7\x3d\x2a\x7a\x34\x9d\x17\x14\xdf\x3f\x45\x59\x66\xa8\xef\xf9\x78\x23\xad\x71\x2c\x79\x0b\x37\xe2\xd0\xbf\xc7\xfe\x08\xfb\x44\xf6\x19\x8b\x2c\x0a\xe9\x3a\xc7\xe9\x6d\x71\xf3\x5c\xe2\x01\xc5\xb4\x3b\xc4\x3e\x15\x65\x99\xe2\x25\x2d\xb5\x52\xb6\x25\x23\x04\xc7\x15\xbd\xfc\x1f\xd8\x0f\x63\x18\x5e\x2f\x68\x38\x73\x74\x1b\xc7\x6b\xec\x87\x11\x7f\x0a\x5a\x29\xdb\xd4\xb5\x13\xad\x7e\x07\x00\xba\x79\xdc\xdb\x24\x01\x00\x00")
+
+func _1528395669_repo_key_value_pairsUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395669_repo_key_value_pairsUpSql,
+		"1528395669_repo_key_value_pairs.up.sql",
+	)
+}
+
+func _1528395669_repo_key_value_pairsUpSql() (*asset, error) {
+	bytes, err := _1528395669_repo_key_value_pairsUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395669_repo_key_value_pairs.up.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x75, 0x63, 0x3f, 0xf4, 0x2a, 0xfb, 0x14, 0x4, 0xd, 0x8c, 0xb6, 0xef, 0x10, 0xd1, 0x5, 0xa5, 0x2d, 0x96, 0x4a, 0xf4, 0x34, 0x7c, 0x10, 0x63, 0xc5, 0x79, 0x8d, 0x46, 0x13, 0x0, 0x50, 0x88}}
+	return a, nil
+}
+
+var __1528395670_patch_jobsDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x6c\x00\x93\xff\x42\x45\x47\x49\x4e\x3b\x0a\x0a\x44\x52\x4f\x50\x20\x54\x41\x42\x4c\x45\x20\x49\x46\x20\x45\x58\x49\x53\x54\x53\x20\x70\x61\x74\x63\x68\x5f\x6a\x6f\x62\x73\x3b\x0a\x41\x4c\x54\x45\x52\x20\x54\x41\x42\x4c\x45\x20\x70\x61\x74\x63\x68\x5f\x73\x65\x74\x73\x20\x44\x52\x4f\x50\x20\x43\x4f\x4c\x55\x4d\x4e\x20\x49\x46\x20\x45\x58\x49\x53\x54\x53\x20\x72\x65\x70\x6c\x61\x63\x65\x6d\x65\x6e\x74\x3b\x0a\x0a\x43\x4f\x4d\x4d\x49\x54\x3b\x0a\x03\x00\x5c\xcd\x18\x85\x6c\x00\x00\x00")
+
+func _1528395670_patch_jobsDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395670_patch_jobsDownSql,
+		"1528395670_patch_jobs.down.sql",
+	)
+}
+
+func _1528395670_patch_jobsDownSql() (*asset, error) {
+	bytes, err := _1528395670_patch_jobsDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395670_patch_jobs.down.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x56, 0xe2, 0x5e, 0xa, 0xec, 0xff, 0xfc, 0x51, 0x5, 0x23, 0x1e, 0xc3, 0x8f, 0x10, 0xee, 0xa4, 0xdd, 0x48, 0xe2, 0x59, 0xa, 0xf5, 0x84, 0x84, 0x52, 0x73, 0x44, 0xc6, 0x5, 0x97, 0x99, 0x16}}
+	return a, nil
+}
+
+var __1528395670_patch_jobsUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xa4\x92\x41\x8f\xda\x30\x10\x85\xef\xfe\x15\x73\x4c\x24\xfe\x41\x4e\x26\x19\xaa\xa8\x89\xd3\x3a\x8e\x54\x4e\x51\x20\x53\x30\x02\x27\xb5\x8d\xa8\xfa\xeb\x2b\x03\xab\x04\xb4\x2b\x56\xda\xa3\x35\xef\x7d\x6f\x34\xcf\x4b\xfc\x96\x8b\x84\x31\x5e\x28\x94\xa0\xf8\xb2\x40\x18\x3b\xbf\xdd\xb7\x8e\xbc\x03\x9e\x65\x90\x56\x45\x53\x0a\xb0\x34\x1e\xbb\x2d\x9d\xc8\x78\x38\xb8\xc1\x6c\x12\xc6\x52\x89\x5c\xe1\x83\xed\x30\x6c\x1c\x44\x0c\x40\xf7\xb0\xd1\x3b\x47\x56\x77\x47\xf8\x21\xf3\x92\xcb\x35\x7c\xc7\xf5\x82\xc1\x94\xd0\xde\x54\xda\x78\x10\x95\x02\xd1\x14\x05\x48\x5c\xa1\x44\x91\x62\x3d\xe9\x5c\xa4\xfb\x18\x2a\x01\x19\x16\xa8\x10\x52\x5e\xa7\x3c\x43\xc8\x82\x56\x86\xf8\x80\xb5\x34\x0e\x81\xa8\x8d\xa7\x1d\xd9\x77\x91\x41\xf3\x29\xd8\x2d\x7b\xda\xef\x79\x2d\x7a\xde\xa9\xc6\x7b\xda\x23\x87\xac\x1d\x2c\x78\xfa\xeb\xc3\xcb\xf9\xce\x7a\xea\xdb\xce\x83\xd7\x27\x72\xbe\x3b\x8d\x70\xd1\x7e\x7f\x7d\xc2\xbf\xc1\x50\x90\xfd\xd6\x46\xbb\xfd\x6b\xdd\xd6\x52\xf7\x02\x37\x5d\x21\xc3\x15\x6f\x0a\x05\x66\xb8\x44\x71\x70\x9f\xc7\xfe\x0b\xee\xb4\x12\xb5\x92\x3c\x17\xea\xde\x53\xa8\xbe\x3d\x1b\xfd\xe7\x4c\xd0\x88\xfc\x67\x83\x10\xcd\x9b\x5e\xbc\x15\x14\xb3\x78\xfa\x3c\xb9\xc8\xf0\xd7\x9c\x30\x3b\x51\x25\x66\x83\x68\x1a\xc4\xc9\x87\xee\xf9\xe5\x1e\xed\xb3\xc9\x35\xbe\x2a\xcb\x5c\x25\xec\xff\x00\xae\x31\x8e\x74\x02\x03\x00\x00")
+
+func _1528395670_patch_jobsUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395670_patch_jobsUpSql,
+		"1528395670_patch_jobs.up.sql",
+	)
+}
+
+func _1528395670_patch_jobsUpSql() (*asset, error) {
+	bytes, err := _1528395670_patch_jobsUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395670_patch_jobs.up.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x65, 0x7c, 0xa5, 0x67, 0xd4, 0x46, 0xcf, 0xe3, 0xa7, 0xc6, 0x14, 0x26, 0xb5, 0x44, 0x2d, 0xb6, 0x33, 0xc2, 0x8a, 0x66, 0xe9, 0x3, 0x40, 0xaa, 0xed, 0x52, 0xfe, 0xdd, 0x54, 0x62, 0xbb, 0x86}}
+	return a, nil
+}
+
+var __1528395671_campaigns_auto_mergeDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x72\x72\x75\xf7\xf4\xb3\xe6\xe2\x72\xf4\x09\x71\x0d\x52\x08\x71\x74\xf2\x71\x55\x48\x4e\xcc\x2d\x48\xcc\x4c\xcf\x2b\x56\x70\x09\xf2\x0f\x50\x70\xf6\xf7\x09\xf5\xf5\x53\xf0\x74\x53\x70\x8d\xf0\x0c\x0e\x09\x56\x48\x2c\x2d\xc9\x8f\xcf\x4d\x2d\x4a\x4f\xb5\x26\x49\x1f\x58\x4b\x7c\x6e\x6a\x49\x46\x7e\x0a\x19\x3a\x8b\xe3\x0b\x52\x8b\xe2\x33\xf2\x4b\x8b\xac\xb9\xb8\x9c\xfd\x7d\x7d\x3d\x43\xac\xb9\x00\x03\x00\x21\x28\x60\x16\xc0\x00\x00\x00")
+
+func _1528395671_campaigns_auto_mergeDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395671_campaigns_auto_mergeDownSql,
+		"1528395671_campaigns_auto_merge.down.sql",
+	)
+}
+
+func _1528395671_campaigns_auto_mergeDownSql() (*asset, error) {
+	bytes, err := _1528395671_campaigns_auto_mergeDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395671_campaigns_auto_merge.down.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xfe, 0xca, 0x41, 0xcd, 0xbd, 0x76, 0x4b, 0xa, 0x41, 0x7d, 0xd7, 0xad, 0xce, 0xd7, 0x44, 0x15, 0x9f, 0xd4, 0xd, 0x3d, 0x89, 0x6b, 0xb2, 0x79, 0xfb, 0xc6, 0x94, 0x93, 0x21, 0x3d, 0xbe, 0x2e}}
+	return a, nil
+}
+
+var __1528395671_campaigns_auto_mergeUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x8c\xcc\xb1\xaa\x83\x30\x14\x06\xe0\x3d\x4f\xf1\x6f\xae\x77\xcf\x14\x35\x57\x84\x93\x08\x12\xe7\x90\xb6\xa7\x2a\xa8\x91\x18\xa1\x8f\x5f\xe8\xea\xd0\xbe\xc0\x57\xea\xa6\xb5\x52\x08\x45\x4e\xf7\x70\xaa\x24\x8d\x7b\x58\xf7\x30\x8f\xdb\x01\x55\xd7\xa8\x3a\x1a\x8c\x45\x38\x73\xf4\x2b\xa7\x91\x71\x8b\x71\xe1\xb0\xc1\x76\x0e\x76\x20\x42\xad\xff\xd5\x40\x0e\xcf\xb0\x1c\x2c\xbf\x5b\x1f\xc6\xaf\x9c\xa7\xf8\x40\xe6\x57\xbe\x52\x85\xd1\x7d\xa3\x8b\x5f\xb1\xc3\xef\x9c\xfc\x14\xcf\x84\x79\xcb\x3c\x72\xba\x92\x7f\x52\x88\xaa\x33\xa6\x75\x52\xbc\x07\x00\x8a\x03\x29\x00\xf7\x00\x00\x00")
+
+func _1528395671_campaigns_auto_mergeUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395671_campaigns_auto_mergeUpSql,
+		"1528395671_campaigns_auto_merge.up.sql",
+	)
+}
+
+func _1528395671_campaigns_auto_mergeUpSql() (*asset, error) {
+	bytes, err := _1528395671_campaigns_auto_mergeUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395671_campaigns_auto_merge.up.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xf1, 0xe5, 0x94, 0xed, 0xc, 0xda, 0xa6, 0x71, 0x55, 0xd, 0x44, 0x13, 0xf2, 0x52, 0xd7, 0x4e, 0x22, 0x5b, 0xee, 0x9d, 0x5a, 0x3a, 0x2c, 0xb6, 0x55, 0xf9, 0x46, 0x5c, 0x23, 0x3d, 0x4f, 0xe1}}
+	return a, nil
+}
+
+var __1528395672_changesets_rebase_stateDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x72\x72\x75\xf7\xf4\xb3\xe6\xe2\x72\xf4\x09\x71\x0d\x52\x08\x71\x74\xf2\x71\x55\x48\xce\x48\xcc\x4b\x4f\x2d\x4e\x2d\x29\x56\x70\x09\xf2\x0f\x50\x70\xf6\xf7\x09\xf5\xf5\x53\xf0\x74\x53\x70\x8d\xf0\x0c\x0e\x09\x56\x28\x4a\x4d\x4a\x2c\x4e\x8d\x2f\x2e\x49\x2c\x49\xb5\x26\x4d\x2b\x58\x63\x72\x7e\x6e\x6e\x66\x89\x35\x17\x97\xb3\xbf\xaf\xaf\x67\x88\x35\x17\x60\x00\x5d\x97\xe1\x04\x86\x00\x00\x00")
+
+func _1528395672_changesets_rebase_stateDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395672_changesets_rebase_stateDownSql,
+		"1528395672_changesets_rebase_state.down.sql",
+	)
+}
+
+func _1528395672_changesets_rebase_stateDownSql() (*asset, error) {
+	bytes, err := _1528395672_changesets_rebase_stateDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395672_changesets_rebase_state.down.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x46, 0x4a, 0xb2, 0x27, 0xca, 0x3, 0x29, 0xac, 0xc, 0xa7, 0xc7, 0xf6, 0x6c, 0x9, 0x1e, 0xe3, 0xcf, 0x9a, 0x1f, 0x19, 0x44, 0x62, 0xf6, 0x72, 0x61, 0xeb, 0x7b, 0x9, 0x93, 0x9e, 0x46, 0x8d}}
+	return a, nil
+}
+
+var __1528395672_changesets_rebase_stateUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x7a\x00\x85\xff\x42\x45\x47\x49\x4e\x3b\x0a\x0a\x41\x4c\x54\x45\x52\x20\x54\x41\x42\x4c\x45\x20\x63\x68\x61\x6e\x67\x65\x73\x65\x74\x73\x20\x41\x44\x44\x20\x43\x4f\x4c\x55\x4d\x4e\x20\x72\x65\x62\x61\x73\x65\x5f\x73\x74\x61\x74\x65\x20\x74\x65\x78\x74\x3b\x0a\x41\x4c\x54\x45\x52\x20\x54\x41\x42\x4c\x45\x20\x63\x68\x61\x6e\x67\x65\x73\x65\x74\x73\x20\x41\x44\x44\x20\x43\x4f\x4c\x55\x4d\x4e\x20\x62\x61\x73\x65\x5f\x63\x6f\x6d\x6d\x69\x74\x20\x74\x65\x78\x74\x3b\x0a\x0a\x43\x4f\x4d\x4d\x49\x54\x3b\x0a\x03\x00\x80\x00\x69\x81\x7a\x00\x00\x00")
+
+func _1528395672_changesets_rebase_stateUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395672_changesets_rebase_stateUpSql,
+		"1528395672_changesets_rebase_state.up.sql",
+	)
+}
+
+func _1528395672_changesets_rebase_stateUpSql() (*asset, error) {
+	bytes, err := _1528395672_changesets_rebase_stateUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395672_changesets_rebase_state.up.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x80, 0xf2, 0x79, 0x60, 0x29, 0x7c, 0x11, 0x4f, 0x61, 0x51, 0x8c, 0xca, 0xae, 0x32, 0x3, 0xff, 0x6c, 0xeb, 0x5e, 0xe7, 0x4, 0xfe, 0xe2, 0x6e, 0xfc, 0x19, 0x3b, 0x41, 0xfa, 0xf6, 0x7d, 0x56}}
+	return a, nil
+}
+
+var __1528395673_campaign_templatesDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x72\x72\x75\xf7\xf4\xb3\xe6\xe2\x72\xf4\x09\x71\x0d\x52\x08\x71\x74\xf2\x71\x55\x48\x4e\xcc\x2d\x48\xcc\x4c\xcf\x2b\x56\x70\x09\xf2\x0f\x50\x70\xf6\xf7\x09\xf5\xf5\x53\xf0\x74\x53\x70\x8d\xf0\x0c\x0e\x09\x56\x28\x49\xcd\x2d\xc8\x49\x2c\x49\x8d\x2f\x4b\x2d\x2a\xce\xcc\xcf\x8b\xcf\x4c\xb1\x26\xcf\x80\x82\xc4\xa2\xc4\xdc\x62\x6b\x2e\x2e\xb0\x45\x10\xdb\x11\xca\x60\xc6\xc4\xa3\x5b\x58\x6c\x4d\xa4\x06\x90\xd1\xce\xfe\xbe\xbe\x9e\x21\xd6\x5c\x80\x01\x00\x14\x1c\x6e\xe5\xea\x00\x00\x00")
+
+func _1528395673_campaign_templatesDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395673_campaign_templatesDownSql,
+		"1528395673_campaign_templates.down.sql",
+	)
+}
+
+func _1528395673_campaign_templatesDownSql() (*asset, error) {
+	bytes, err := _1528395673_campaign_templatesDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395673_campaign_templates.down.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x3f, 0xcb, 0xd9, 0xbe, 0xc0, 0x15, 0x70, 0x33, 0xdd, 0x10, 0xa3, 0x51, 0x2f, 0xc4, 0x3, 0xb5, 0x75, 0xd4, 0x55, 0xc1, 0xfa, 0xbb, 0xf4, 0x1a, 0x96, 0x4f, 0x65, 0xa1, 0xa4, 0x0, 0x33, 0x9a}}
+	return a, nil
+}
+
+var __1528395673_campaign_templatesUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xb4\x54\x4d\x73\x9b\x30\x10\xbd\xf3\x2b\xf6\x06\xcc\xe4\x92\xb3\xdb\xce\x10\x90\x5b\x26\x58\xb4\x80\x67\x92\x93\x46\x86\xad\xad\xd4\x08\x8a\xe4\xa6\x1f\xd3\xff\xde\x11\xb6\x8c\x6b\x9c\x38\xa9\xa7\x47\x56\xef\xbd\xfd\x78\xbb\xdc\x90\xf7\x31\x9d\x38\x4e\x98\x91\xa0\x20\x50\x04\x37\x09\x81\x78\x0a\x34\x2d\x80\xdc\xc5\x79\x91\x43\xc9\xeb\x96\x8b\xa5\x64\x1a\xeb\x76\xcd\x35\x2a\xf0\x1c\x00\x51\xc1\x42\x2c\x15\x76\x82\xaf\xe1\x63\x16\xcf\x82\xec\x1e\x6e\xc9\xfd\x95\x03\x20\x79\x8d\xa0\xf1\xbb\xee\x75\xe8\x3c\x49\x4c\xb4\x42\x55\x76\xa2\xd5\xa2\x91\xfd\xa3\x89\xf1\x8d\x5e\x35\x1d\x13\x15\x08\xa9\x71\x89\xdd\x9e\x01\x19\x99\x92\x8c\xd0\x90\xe4\xb0\x51\xd8\x29\x4f\x54\x3e\xa4\x14\x22\x92\x90\x82\x40\x18\xe4\x61\x10\x11\x88\x0c\x2c\x33\x75\xdb\xcc\xaa\xe5\x25\x32\xc3\x39\xd4\xbd\x50\xae\xe9\x96\x4f\xa8\x35\xdd\xf2\x65\x62\xfd\xec\x34\xfb\x86\x9d\x32\x23\x18\x35\x1c\x91\x69\x30\x4f\x0a\xb8\x36\xe0\xb2\x43\xae\xb1\x62\x5c\x83\x16\x35\x2a\xcd\xeb\x16\x1e\x85\x5e\xf5\x9f\xf0\xb3\x91\x38\x66\xca\xe6\xd1\xf3\x0d\x7b\xd3\x56\x17\xb0\x2b\x5c\xe3\x19\xb6\x49\x12\xa6\x34\x2f\xb2\x20\xa6\xc5\x89\x1d\x61\xc6\x09\x26\x1b\xcd\x16\x6b\x2e\xbf\x40\xf8\x81\x84\xb7\xe0\x99\x28\xbc\x79\x07\xae\xeb\x9f\x97\x58\x71\xc5\xae\xd9\xde\x03\xab\xe1\x8d\x4d\x8e\xf3\xbe\x15\xdf\x48\x7b\x23\xd3\xec\xab\xef\xf8\xc3\xaa\xc7\x34\x22\x77\x67\x57\x9d\x8d\x73\xa5\xf4\x04\x6e\x5c\x93\x3f\xf9\xe7\x4c\xbb\xb2\xcf\x25\xda\xc2\xfc\x57\x5e\xaf\xdd\xbf\xf3\x57\xbc\x67\x6c\x41\x42\x0e\xe7\x7c\xb8\xff\x27\x6a\x7c\xc9\x35\x3c\x75\x06\xe6\x4d\xb5\x58\xc2\x83\x6a\xe4\x62\xc8\xb8\x33\xbf\x8f\x32\xfd\xa3\xc5\xe6\xb3\x67\x70\x3e\xbc\x05\xb7\x59\x3c\x60\xa9\x5d\xff\x7f\xfc\x52\x2e\xbb\xc4\xe7\x36\x7c\x6f\xc5\x10\x11\x95\x0d\xb2\x8d\x14\x5f\x37\x08\x73\x1a\x7f\x9a\x13\xf0\x0e\x20\x57\x76\x78\xdb\x85\x0e\x92\x82\x64\x3b\xf3\x6d\x0e\x05\x41\x14\x41\x98\x26\xf3\x19\x1d\x8c\xb4\xd2\x83\xa1\xcf\xf9\x68\xe1\xc7\x63\xca\xc9\xd0\xec\x6e\x4e\x93\x57\x14\xd1\xf2\x8e\xd7\xea\xd8\x5e\x3b\x38\xf7\xd7\x6f\xf7\xa4\xd7\x47\xfc\xbf\x6c\x9f\x38\x4e\x98\xce\x66\x71\x31\x71\xfe\x0c\x00\x07\x72\xe9\x65\xd1\x06\x00\x00")
+
+func _1528395673_campaign_templatesUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395673_campaign_templatesUpSql,
+		"1528395673_campaign_templates.up.sql",
+	)
+}
+
+func _1528395673_campaign_templatesUpSql() (*asset, error) {
+	bytes, err := _1528395673_campaign_templatesUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395673_campaign_templates.up.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x49, 0xae, 0x12, 0xe0, 0xc5, 0x1f, 0x8c, 0xd3, 0x9f, 0xf0, 0xec, 0xad, 0x4, 0x8c, 0xd8, 0xb1, 0x36, 0xce, 0x3b, 0x2a, 0xcb, 0x9e, 0xff, 0x86, 0x25, 0x20, 0x5e, 0x9c, 0x6, 0xcf, 0xf2, 0xe7}}
+	return a, nil
+}
+
+var __1528395674_campaign_changeset_optionsDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x50\x00\xaf\xff\x42\x45\x47\x49\x4e\x3b\x0a\x0a\x41\x4c\x54\x45\x52\x20\x54\x41\x42\x4c\x45\x20\x63\x61\x6d\x70\x61\x69\x67\x6e\x73\x20\x44\x52\x4f\x50\x20\x43\x4f\x4c\x55\x4d\x4e\x20\x49\x46\x20\x45\x58\x49\x53\x54\x53\x20\x63\x68\x61\x6e\x67\x65\x73\x65\x74\x5f\x6f\x70\x74\x69\x6f\x6e\x73\x3b\x0a\x0a\x43\x4f\x4d\x4d\x49\x54\x3b\x0a\x03\x00\xb8\x51\xf7\x21\x50\x00\x00\x00")
+
+func _1528395674_campaign_changeset_optionsDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395674_campaign_changeset_optionsDownSql,
+		"1528395674_campaign_changeset_options.down.sql",
+	)
+}
+
+func _1528395674_campaign_changeset_optionsDownSql() (*asset, error) {
+	bytes, err := _1528395674_campaign_changeset_optionsDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395674_campaign_changeset_options.down.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x97, 0x8a, 0x96, 0xea, 0x86, 0xf8, 0xd1, 0x57, 0xea, 0xd1, 0xc6, 0xc8, 0x15, 0xb9, 0x87, 0xbd, 0x59, 0x49, 0x60, 0xe6, 0xc3, 0x15, 0x41, 0x7a, 0xb4, 0xc0, 0x30, 0x5d, 0xf5, 0x2e, 0xd9, 0x3a}}
+	return a, nil
+}
+
+var __1528395674_campaign_changeset_optionsUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x64\x8c\xb1\xca\x83\x30\x00\x06\xf7\x3c\xc5\xb7\x45\x5f\x41\xf9\x87\x18\xe3\xdf\xd0\x18\xa1\x46\xe8\x26\x2a\xa9\x55\x68\x12\x48\x96\x52\xfa\xee\x05\x3b\x76\x3c\xee\xb8\x4a\xfc\x4b\x5d\x12\xc2\x94\x11\x17\x18\x56\x29\x81\x65\x7a\x84\x69\x5b\x5d\x04\xab\x6b\xf0\x4e\x0d\xad\x86\x6c\xa0\x3b\x03\x71\x95\xbd\xe9\xb1\xdc\x27\xb7\xda\x68\xd3\xe8\x43\xda\xbc\x8b\xd8\xa3\x77\xf3\x91\xe8\x41\x29\xd4\xa2\x61\x83\x32\xa0\xaf\x37\x2d\x8a\xaf\xe4\x27\xc1\xcf\xc8\x0e\x18\xd3\x33\x58\x7f\xcb\x7e\x46\x39\xfe\x40\xfd\xbc\xdb\x25\xd1\xbc\x24\x84\x77\x6d\x2b\x4d\x49\x3e\x03\x00\x2c\x0c\x27\xaf\xa9\x00\x00\x00")
+
+func _1528395674_campaign_changeset_optionsUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395674_campaign_changeset_optionsUpSql,
+		"1528395674_campaign_changeset_options.up.sql",
+	)
+}
+
+func _1528395674_campaign_changeset_optionsUpSql() (*asset, error) {
+	bytes, err := _1528395674_campaign_changeset_optionsUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395674_campaign_changeset_options.up.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x50, 0x12, 0x19, 0x7f, 0xb6, 0x3f, 0xc2, 0x70, 0x37, 0xc6, 0xf8, 0x99, 0xee, 0x26, 0xee, 0xc7, 0x9e, 0x2f, 0x45, 0xf9, 0x16, 0x20, 0x12, 0x1d, 0x3a, 0x48, 0xd0, 0x26, 0x2f, 0xec, 0x17, 0xe}}
+	return a, nil
+}
+
+var __1528395675_changeset_import_queriesDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x40\x00\xbf\xff\x42\x45\x47\x49\x4e\x3b\x0a\x0a\x44\x52\x4f\x50\x20\x54\x41\x42\x4c\x45\x20\x49\x46\x20\x45\x58\x49\x53\x54\x53\x20\x63\x68\x61\x6e\x67\x65\x73\x65\x74\x5f\x69\x6d\x70\x6f\x72\x74\x5f\x71\x75\x65\x72\x69\x65\x73\x3b\x0a\x0a\x43\x4f\x4d\x4d\x49\x54\x3b\x0a\x03\x00\x87\x44\x1f\x53\x40\x00\x00\x00")
+
+func _1528395675_changeset_import_queriesDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395675_changeset_import_queriesDownSql,
+		"1528395675_changeset_import_queries.down.sql",
+	)
+}
+
+func _1528395675_changeset_import_queriesDownSql() (*asset, error) {
+	bytes, err := _1528395675_changeset_import_queriesDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395675_changeset_import_queries.down.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x96, 0xf6, 0xc0, 0xa0, 0x47, 0x62, 0xa0, 0xa0, 0xf9, 0xcd, 0x48, 0xeb, 0x55, 0x37, 0xb5, 0x69, 0x5f, 0x4b, 0x9a, 0x6b, 0xf9, 0x8, 0x82, 0xf2, 0x69, 0xc4, 0x2c, 0xb1, 0x71, 0xcf, 0xc, 0x2d}}
+	return a, nil
+}
+
+var __1528395675_changeset_import_queriesUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xa4\x92\xcf\x8e\x9b\x30\x10\x87\xef\x3c\xc5\xdc\x00\x29\x6f\xb0\x55\x25\x16\x66\x5b\x6b\x89\x69\xc1\x48\xbb\x27\xcb\x0b\xa3\xc4\x2a\x18\x62\x4c\x93\xf4\xe9\x2b\x53\x35\xe9\x9f\x44\x89\xb4\x47\x46\xdf\x7c\xc3\x78\x7e\x8f\xf8\x89\xf1\x87\x20\x48\x4b\x4c\x04\x82\x48\x1e\x73\x04\xf6\x04\xbc\x10\x80\x2f\xac\x12\x15\x34\x5b\x65\x36\x34\x91\x93\xba\x1f\x07\xeb\xe4\x6e\x26\xab\x69\x82\x28\x00\xd0\x2d\xbc\xe9\xcd\x44\x56\xab\x0e\xbe\x94\x6c\x9d\x94\xaf\xf0\x8c\xaf\xab\x00\xa0\x51\xfd\xa8\xf4\xc6\xc8\x5f\x90\x36\x6e\xd1\xf2\x3a\xcf\xa1\xc4\x27\x2c\x91\xa7\x58\x9d\xb0\x29\xd2\x6d\x0c\x05\x87\x0c\x73\x14\x08\x69\x52\xa5\x49\x86\x90\x79\xb4\xf4\x3f\xe6\xa5\x74\x70\x64\x8d\xea\xe4\x44\xf6\xbb\x6e\xe8\x86\xfc\x5f\xfc\xbe\x21\x7e\xc3\x23\x38\x3a\x9c\xa5\xbe\xdc\xa9\xe9\xf7\x23\x50\x2b\x95\x03\xa7\x7b\x9a\x9c\xea\x47\xd8\x6b\xb7\x5d\x3e\xe1\xc7\x60\xe8\x04\x93\xb5\x83\xfd\x5b\xe4\xf7\x49\xea\x5c\x40\x18\x7a\xac\xb1\xa4\x6e\xd8\xfe\x6f\x35\xc3\x3e\x8a\x7d\xf7\x3c\xb6\xef\xe8\x4e\x0b\x5e\x89\x32\x61\x5c\x5c\x3d\xf2\x72\xec\xa3\x34\x83\x93\x6f\x9d\x32\xdf\x20\xfd\x8c\xe9\x33\x44\x4b\x19\x3e\x7c\x84\x30\xbc\x5b\x35\x1b\xbd\x9b\x09\x6a\xce\xbe\xd6\x08\xd1\x1f\xf1\x58\x5d\x3a\xeb\x0a\x96\x21\x71\x10\x9f\xf3\xc9\x78\x86\x2f\x77\xe6\x53\x5e\x70\xfa\x78\x5d\xe3\xa3\x0b\xfc\x32\xba\x58\xaf\x99\x78\x08\x7e\x0e\x00\x87\xba\x77\x67\x2b\x03\x00\x00")
+
+func _1528395675_changeset_import_queriesUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395675_changeset_import_queriesUpSql,
+		"1528395675_changeset_import_queries.up.sql",
+	)
+}
+
+func _1528395675_changeset_import_queriesUpSql() (*asset, error) {
+	bytes, err := _1528395675_changeset_import_queriesUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395675_changeset_import_queries.up.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x47, 0x61, 0xdf, 0xc8, 0xe6, 0xfa, 0xb0, 0x2b, 0x8d, 0x9d, 0x7f, 0x1e, 0x21, 0x93, 0xba, 0x22, 0xe, 0x6a, 0xee, 0x4e, 0xdb, 0xf3, 0x18, 0x29, 0x4c, 0x61, 0xc3, 0x8d, 0x6a, 0x94, 0x82, 0xdf}}
+	return a, nil
+}
+
+var __1528395676_changeset_actionsDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x65\x00\x9a\xff\x42\x45\x47\x49\x4e\x3b\x0a\x0a\x44\x52\x4f\x50\x20\x54\x41\x42\x4c\x45\x20\x49\x46\x20\x45\x58\x49\x53\x54\x53\x20\x63\x68\x61\x6e\x67\x65\x73\x65\x74\x5f\x61\x63\x74\x69\x6f\x6e\x5f\x6a\x6f\x62\x73\x3b\x0a\x44\x52\x4f\x50\x20\x54\x41\x42\x4c\x45\x20\x49\x46\x20\x45\x58\x49\x53\x54\x53\x20\x63\x68\x61\x6e\x67\x65\x73\x65\x74\x5f\x61\x63\x74\x69\x6f\x6e\x73\x3b\x0a\x0a\x43\x4f\x4d\x4d\x49\x54\x3b\x0a\x03\x00\x3c\xc8\x56\x98\x65\x00\x00\x00")
+
+func _1528395676_changeset_actionsDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395676_changeset_actionsDownSql,
+		"1528395676_changeset_actions.down.sql",
+	)
+}
+
+func _1528395676_changeset_actionsDownSql() (*asset, error) {
+	bytes, err := _1528395676_changeset_actionsDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395676_changeset_actions.down.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xe9, 0xd0, 0x46, 0xbc, 0xce, 0x36, 0xa7, 0x5, 0x2b, 0xba, 0xac, 0x86, 0x37, 0x3, 0xa9, 0x40, 0x52, 0xe2, 0xa8, 0x97, 0x1e, 0x40, 0x2e, 0xa6, 0x5c, 0xa7, 0xf0, 0xec, 0x8e, 0x31, 0x90, 0x2e}}
+	return a, nil
+}
+
+var __1528395676_changeset_actionsUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xc4\x92\xc1\x8e\xda\x30\x10\x86\xef\x79\x8a\xb9\x91\x48\xfb\x04\xcb\x29\x1b\x66\x2b\xab\xc1\x69\x13\x23\xed\xaa\xaa\x22\x87\x4c\x61\x56\xe0\x50\xdb\x94\x6e\x9f\xbe\x72\x2a\x95\x74\xa1\x4a\xe8\xa5\x47\x9b\x6f\x7e\x0f\xf9\xbf\x07\x7c\x27\xe4\x3c\x8a\xb2\x12\x53\x85\xa0\xd2\x87\x1c\x41\x3c\x82\x2c\x14\xe0\x93\xa8\x54\x05\xeb\xad\x36\x1b\x72\xe4\x6b\xbd\xf6\xdc\x19\x07\x71\x04\xc0\x2d\x34\xbc\x71\x64\x59\xef\xe0\x43\x29\x96\x69\xf9\x0c\xef\xf1\xf9\x2e\x02\x58\xeb\xfd\x41\xf3\xc6\xd4\xbf\x20\x36\xbe\xcf\x93\xab\x3c\x87\x12\x1f\xb1\x44\x99\x61\xf5\x1b\x73\x31\xb7\x09\x14\x12\x16\x98\xa3\x42\xc8\xd2\x2a\x4b\x17\x08\x8b\x80\x96\x61\xa3\x10\x7a\x74\x64\x43\x20\x1b\x4f\x1b\xb2\x57\x13\x03\x33\x2d\xcd\xbf\x1e\x08\x3c\x7d\x3f\x6f\x16\x6e\x9b\xae\x7d\xfd\xf3\x36\x2c\x91\xae\x72\x05\xb3\x59\x00\x76\xba\xa1\x9d\x83\x17\xd7\x99\xe6\x0a\xf3\xe9\xf3\xec\xfe\xbe\xff\x31\xc0\x96\xbe\x31\x9d\xc8\x4e\xe5\xd7\x96\xb4\xa7\xb6\xd6\x1e\x3c\xef\xc9\x79\xbd\x3f\xc0\x89\xfd\xb6\x3f\xc2\x8f\xce\xd0\x65\x86\xe9\x4e\x71\x12\xa6\x8f\x87\xf6\x1f\xa7\xa3\xe4\x2c\x80\x90\x0b\x7c\x1a\x13\xa0\x1e\x16\x5c\xc8\x4b\x20\x1e\x00\xc9\x6d\x76\xd5\x2f\x5d\x33\xc1\xb0\xb7\x53\x23\xa6\xbd\xc1\xa7\x39\x72\x5e\x6d\x62\xfa\xb4\x58\xb2\xb6\xb3\xbd\x65\xe1\xe4\xbc\xb6\x23\xb5\x05\xec\x0b\x1b\x76\xdb\x71\xee\x7f\x39\x14\xa6\xb3\x42\x56\xaa\x4c\x85\x54\x97\xfd\x84\x56\xeb\xa3\xe1\xaf\x47\x82\x95\x14\x1f\x57\x08\xf1\x05\xc4\xed\xdd\x60\x92\xdb\x9b\xcd\xec\xe5\xa9\x07\x9f\xf4\x8a\x9c\x3d\x13\x9f\x99\x64\x7e\xfb\x0b\xc3\x36\xfe\xfa\xc4\x00\xea\xff\x46\xb1\x5c\x0a\x35\x8f\x7e\x0e\x00\xd4\xae\xaa\xa2\x72\x05\x00\x00")
+
+func _1528395676_changeset_actionsUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395676_changeset_actionsUpSql,
+		"1528395676_changeset_actions.up.sql",
+	)
+}
+
+func _1528395676_changeset_actionsUpSql() (*asset, error) {
+	bytes, err := _1528395676_changeset_actionsUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395676_changeset_actions.up.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xda, 0xd7, 0x54, 0x5e, 0x1a, 0xe8, 0xbc, 0x75, 0xe6, 0x87, 0xa8, 0x2f, 0x5d, 0x50, 0xaa, 0x84, 0x42, 0x8f, 0x6a, 0x1d, 0x59, 0x3f, 0xaa, 0x97, 0x59, 0x44, 0x5f, 0x90, 0xe0, 0xfd, 0x54, 0xf6}}
+	return a, nil
+}
+
+var __1528395677_campaign_notificationsDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x78\x00\x87\xff\x42\x45\x47\x49\x4e\x3b\x0a\x0a\x44\x52\x4f\x50\x20\x54\x41\x42\x4c\x45\x20\x49\x46\x20\x45\x58\x49\x53\x54\x53\x20\x63\x61\x6d\x70\x61\x69\x67\x6e\x5f\x6e\x6f\x74\x69\x66\x69\x63\x61\x74\x69\x6f\x6e\x73\x3b\x0a\x44\x52\x4f\x50\x20\x54\x41\x42\x4c\x45\x20\x49\x46\x20\x45\x58\x49\x53\x54\x53\x20\x63\x61\x6d\x70\x61\x69\x67\x6e\x5f\x6e\x6f\x74\x69\x66\x69\x63\x61\x74\x69\x6f\x6e\x5f\x73\x75\x62\x73\x63\x72\x69\x70\x74\x69\x6f\x6e\x73\x3b\x0a\x0a\x43\x4f\x4d\x4d\x49\x54\x3b\x0a\x03\x00\xa8\xca\xa7\xb2\x78\x00\x00\x00")
+
+func _1528395677_campaign_notificationsDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395677_campaign_notificationsDownSql,
+		"1528395677_campaign_notifications.down.sql",
+	)
+}
+
+func _1528395677_campaign_notificationsDownSql() (*asset, error) {
+	bytes, err := _1528395677_campaign_notificationsDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395677_campaign_notifications.down.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x34, 0x46, 0xc5, 0xdb, 0x6c, 0x66, 0x54, 0xf5, 0x27, 0x65, 0x90, 0xb0, 0xa5, 0xf8, 0xd3, 0x94, 0xd1, 0x82, 0xd4, 0x88, 0x8b, 0x2a, 0x9d, 0x30, 0xb4, 0xca, 0xad, 0xc, 0x15, 0xec, 0xee, 0x6f}}
+	return a, nil
+}
+
+var __1528395677_campaign_notificationsUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xd4\x92\xd1\x8e\xaa\x30\x10\x86\xef\x79\x8a\xb9\x13\x92\xf3\x04\x7a\x85\x50\x4f\xc8\x41\x3c\x41\x4c\x34\x9b\x0d\xa9\x50\x71\x36\x5a\x48\xa7\xc4\xcd\x3e\xfd\xa6\x98\x15\x75\x0d\xb0\xde\xed\x65\x3b\xdf\xfc\x33\x69\xbf\x29\xfb\x1b\x44\x13\xcb\xf2\x62\xe6\x26\x0c\x12\x77\x1a\x32\x08\x66\x10\x2d\x12\x60\xeb\x60\x99\x2c\x21\xe3\xc7\x8a\x63\x21\x53\x59\x6a\xdc\x61\xc6\x35\x96\x32\xa5\x7a\x4b\x99\xc2\xca\x1c\x08\x6c\x0b\x00\x73\xd8\x62\x41\x42\x21\x3f\xc0\xff\x38\x98\xbb\xf1\x06\xfe\xb1\xcd\x1f\x0b\xda\x8c\x33\x84\x52\x37\x13\xa2\x55\x18\x42\xcc\x66\x2c\x66\x91\xc7\xda\x51\x64\x63\xee\xc0\x22\x02\x9f\x85\x2c\x61\xe0\xb9\x4b\xcf\xf5\x19\xf8\x06\x8d\xcd\x8e\x26\xb4\x26\xa1\x52\xcc\x01\xa5\x16\x85\x50\x0f\x13\x0d\x33\x2c\x2d\xdb\x73\x29\xc5\x01\xb4\x78\x6f\x97\x33\x85\x5a\xdd\x5d\x9a\x35\xdc\x55\x98\xc0\x68\x64\xea\x24\x32\x25\x74\x27\xa2\x15\x97\x84\xe7\xa7\x7a\xa3\x52\x6e\x1f\x80\x2f\xaf\xa3\xf1\xb8\x29\x9a\xd0\x4c\x09\xae\x45\x9e\x72\x0d\x1a\x8f\x82\x34\x3f\x56\x70\x42\xbd\x6f\x8e\xf0\x51\x4a\xf1\x3d\x43\x96\x27\xdb\x31\xdd\x75\x95\x3f\xd9\x6d\x39\xad\x0c\x41\xe4\xb3\xf5\xcf\x65\x48\x2f\x0c\xe6\xe6\x0f\x07\xb4\xd8\x57\x2d\xce\x13\x36\xf6\x0b\x78\x3d\x6e\xa0\x84\x5d\xfb\x0e\x15\xaa\x10\x24\x74\xdf\xbc\x2f\x6e\x58\x6c\xeb\xd2\xad\x72\xa6\x26\x94\x2a\x55\x73\x6d\x4e\xa4\xb9\xea\xb1\xc0\x60\x3b\x94\x48\xfb\x7e\xee\x57\x29\x49\x37\x1f\xd6\x65\x22\xd9\x77\xa4\x33\x79\x66\x5a\xfb\xd6\x1d\x83\x2e\x50\x23\xf9\x62\x3e\x0f\x92\x89\xf5\x39\x00\x43\xe2\xd4\xff\x83\x05\x00\x00")
+
+func _1528395677_campaign_notificationsUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395677_campaign_notificationsUpSql,
+		"1528395677_campaign_notifications.up.sql",
+	)
+}
+
+func _1528395677_campaign_notificationsUpSql() (*asset, error) {
+	bytes, err := _1528395677_campaign_notificationsUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395677_campaign_notifications.up.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x78, 0x10, 0xe0, 0x31, 0x9f, 0x4, 0x1d, 0xed, 0x71, 0x9a, 0x56, 0x2a, 0x9d, 0x25, 0x78, 0xc6, 0x68, 0xc0, 0x1b, 0x2b, 0x6b, 0xd4, 0x1e, 0xce, 0xab, 0xc4, 0x5d, 0x1c, 0x71, 0xd6, 0x41, 0x21}}
+	return a, nil
+}
+
+var __1528395678_access_token_restrictionsDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x94\xcb\x51\x0a\xc3\x20\x0c\x00\xd0\xff\x9c\x22\xf7\xf0\xab\xed\xdc\x10\xb4\x8e\xd6\xc1\xfe\x44\xba\x7c\x94\x95\x1a\x4c\x60\x3b\x7e\xcf\xe0\x01\xde\x68\x1f\x6e\x36\x00\x83\x4f\x76\xc1\x34\x8c\xde\x62\xd9\x36\x12\xc9\x5a\xbf\x74\x0a\xde\x96\xf8\xc4\x29\xfa\x57\x98\xd1\xdd\xd1\xbe\xdd\x9a\x56\xa4\x3f\xef\x8d\x24\x17\x35\xdd\xb6\x1c\x47\xfd\xd1\x27\xef\x2c\xfd\xb8\x11\xd7\xcc\x45\x95\xda\x29\x06\x60\x8a\x21\xb8\x64\xe0\x1a\x00\x9c\x0d\x4c\xcb\xc9\x00\x00\x00")
+
+func _1528395678_access_token_restrictionsDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395678_access_token_restrictionsDownSql,
+		"1528395678_access_token_restrictions.down.sql",
+	)
+}
+
+func _1528395678_access_token_restrictionsDownSql() (*asset, error) {
+	bytes, err := _1528395678_access_token_restrictionsDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395678_access_token_restrictions.down.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x56, 0x5b, 0x5b, 0xe7, 0x18, 0x27, 0x7f, 0x45, 0xe6, 0x9b, 0x44, 0x6e, 0xad, 0x70, 0x34, 0xbb, 0x61, 0x27, 0x5f, 0x92, 0x73, 0x7f, 0xe4, 0xb2, 0x7a, 0xfc, 0x4c, 0x24, 0x84, 0x9d, 0xbe, 0x66}}
+	return a, nil
+}
+
+var __1528395678_access_token_restrictionsUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x8c\xcc\x41\xaa\x83\x30\x10\x00\xd0\x7d\x4e\x31\x3b\x0f\xe1\x2a\x6a\xfe\x47\x18\x23\x94\x64\x55\x4a\x08\x76\x16\xa1\x6a\x42\x66\xa0\xd2\xd2\xbb\xf7\x08\xf5\x00\xef\x75\xe6\x7f\xb4\xad\x52\x1a\x9d\xb9\x80\xd3\x1d\x1a\x88\xcb\x42\xcc\x41\xf2\x83\x76\x06\x3d\x0c\xd0\xcf\xe8\x27\x0b\x74\x94\x54\x89\x43\x14\x90\xb4\x11\x4b\xdc\x8a\xbc\xda\x73\x38\xae\x6b\x7e\xd2\x3d\xa4\xc2\x20\x74\xc8\xf5\x06\x76\x76\x60\x3d\x22\x0c\xe6\x4f\x7b\x74\xd0\xbc\x3f\xcd\xc9\xae\x52\xc9\xa1\x44\x11\xaa\xfb\xaf\x50\xf5\xf3\x34\x8d\xae\x55\xdf\x01\x00\x6f\x51\xff\xee\xee\x00\x00\x00")
+
+func _1528395678_access_token_restrictionsUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395678_access_token_restrictionsUpSql,
+		"1528395678_access_token_restrictions.up.sql",
+	)
+}
+
+func _1528395678_access_token_restrictionsUpSql() (*asset, error) {
+	bytes, err := _1528395678_access_token_restrictionsUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395678_access_token_restrictions.up.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x8e, 0xa, 0xd, 0x38, 0x1b, 0xac, 0x70, 0x53, 0x3, 0x0, 0x5c, 0x8c, 0x43, 0x6d, 0xa5, 0x25, 0x59, 0x9, 0xad, 0xb7, 0x31, 0xb0, 0x74, 0xfc, 0xcd, 0x71, 0x7e, 0xc5, 0x1e, 0x84, 0x7f, 0x3d}}
+	return a, nil
+}
+
+var __1528395679_org_member_rolesDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x45\x00\xba\xff\x42\x45\x47\x49\x4e\x3b\x0a\x0a\x41\x4c\x54\x45\x52\x20\x54\x41\x42\x4c\x45\x20\x6f\x72\x67\x5f\x6d\x65\x6d\x62\x65\x72\x73\x20\x44\x52\x4f\x50\x20\x43\x4f\x4c\x55\x4d\x4e\x20\x49\x46\x20\x45\x58\x49\x53\x54\x53\x20\x72\x6f\x6c\x65\x3b\x0a\x0a\x43\x4f\x4d\x4d\x49\x54\x3b\x0a\x03\x00\x5b\x18\x81\x0b\x45\x00\x00\x00")
+
+func _1528395679_org_member_rolesDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395679_org_member_rolesDownSql,
+		"1528395679_org_member_roles.down.sql",
+	)
+}
+
+func _1528395679_org_member_rolesDownSql() (*asset, error) {
+	bytes, err := _1528395679_org_member_rolesDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395679_org_member_roles.down.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x85, 0x28, 0x1f, 0x4a, 0x5c, 0xed, 0x55, 0x65, 0x45, 0xf6, 0x1a, 0x8, 0x14, 0xa3, 0xff, 0x4a, 0xf5, 0xaa, 0x82, 0x4f, 0xcc, 0x1f, 0x56, 0xe4, 0x5f, 0x8d, 0xe9, 0x91, 0x38, 0x56, 0x17, 0x1a}}
+	return a, nil
+}
+
+var __1528395679_org_member_rolesUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x7c\x8e\x41\x6e\xdb\x30\x14\x44\xf7\x3a\xc5\xec\x64\x03\x8a\x2f\x60\x74\x21\x4b\x3f\x8d\x50\x8a\x2c\x68\x1a\x41\x56\x86\x2c\x7e\xdb\x44\x62\x31\x20\xd9\x26\xed\xe9\x0b\x0a\x49\x1b\x2f\x9a\x25\x87\x7f\xde\xbc\x0d\x7d\xed\xe4\xba\x28\x6e\x6e\x40\xaf\x2e\x26\x37\x9d\x70\xe1\xcb\x81\x43\xc4\x23\xf3\x33\xd2\x99\xf1\x1c\xdc\x4f\xf7\xc4\x27\x8e\xf9\xf9\x0b\xe7\xc1\xe2\xc0\x47\x1f\x18\xc1\x3f\x71\xc4\x0b\x07\x86\x9b\x52\xf0\xf6\xc7\xc8\xb6\xca\x38\xb7\xe2\xd5\x7c\x5e\x06\xc6\x60\x2f\x6e\x8a\x15\x86\xc9\xe6\x0c\x47\x17\x62\x7a\x1b\x82\x3f\x82\x87\xf1\x0c\x1f\x4e\xc3\xe4\x7e\x0f\xc9\xf9\x09\x07\x1e\xfd\x85\x23\x5c\x8a\x99\xe6\x5f\x26\x0e\xab\xa2\x16\x86\x34\x4c\xbd\x11\x94\xcf\xf7\xef\xaa\x75\xdb\xa2\x51\x62\xd7\xcb\xd9\x08\x89\x5f\x13\xa4\x32\x90\x3b\x21\xd0\xd2\x6d\xbd\x13\x06\x65\xdd\xf6\x9d\x2c\xd7\xff\xc7\xcc\xf9\x47\xd0\x96\xcc\xbf\x7a\x4f\xfd\x86\xf4\x67\xfd\x59\x43\x6e\x8d\xae\x3b\x69\x3e\x7e\xed\xb3\xd6\x7e\x3c\xf3\xf8\x88\xe6\x8e\x9a\x6f\x58\xe4\x04\x9d\xc4\xa2\x54\xf7\x92\x74\x59\xbd\xfb\x55\x7f\x97\x2a\x94\x9a\xea\x76\xaf\xa4\x78\x28\x97\xcb\x75\x51\xec\xbe\xb7\xb5\xb9\x1e\xcd\x8a\x33\xeb\x0b\xde\x48\xc5\xfd\x1d\x69\x82\xb3\x33\x7e\x4b\x82\x1a\x83\xb6\xdb\x9a\x4e\x36\x06\x4a\x62\x91\xfb\xce\x2e\xf3\xc9\xad\x56\xfd\x15\x4f\xe9\x96\x34\x36\x0f\x73\xe8\x6c\x05\x67\xf3\x72\xa3\xfa\xbe\x33\xeb\xe2\xcf\x00\x17\x23\xb4\x92\x32\x02\x00\x00")
+
+func _1528395679_org_member_rolesUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395679_org_member_rolesUpSql,
+		"1528395679_org_member_roles.up.sql",
+	)
+}
+
+func _1528395679_org_member_rolesUpSql() (*asset, error) {
+	bytes, err := _1528395679_org_member_rolesUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395679_org_member_roles.up.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xd3, 0x82, 0x94, 0x3d, 0xd7, 0x42, 0x8f, 0xee, 0x91, 0x16, 0xc9, 0xf9, 0xe9, 0xe9, 0xea, 0x46, 0xaa, 0x2c, 0x9e, 0xf, 0xa7, 0x6f, 0x21, 0x80, 0xab, 0xba, 0x35, 0xcc, 0x4e, 0x39, 0x98, 0x24}}
+	return a, nil
+}
+
+var __1528395680_user_deactivationDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x49\x00\xb6\xff\x42\x45\x47\x49\x4e\x3b\x0a\x0a\x41\x4c\x54\x45\x52\x20\x54\x41\x42\x4c\x45\x20\x75\x73\x65\x72\x73\x20\x44\x52\x4f\x50\x20\x43\x4f\x4c\x55\x4d\x4e\x20\x49\x46\x20\x45\x58\x49\x53\x54\x53\x20\x64\x65\x61\x63\x74\x69\x76\x61\x74\x65\x64\x5f\x61\x74\x3b\x0a\x0a\x43\x4f\x4d\x4d\x49\x54\x3b\x0a\x03\x00\xc1\x00\x0b\x10\x49\x00\x00\x00")
+
+func _1528395680_user_deactivationDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395680_user_deactivationDownSql,
+		"1528395680_user_deactivation.down.sql",
+	)
+}
+
+func _1528395680_user_deactivationDownSql() (*asset, error) {
+	bytes, err := _1528395680_user_deactivationDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395680_user_deactivation.down.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x8d, 0xeb, 0x49, 0x57, 0xab, 0x77, 0x2, 0x2d, 0xa9, 0xf5, 0x8a, 0x7d, 0xbb, 0xa7, 0x13, 0x8e, 0xfc, 0xd3, 0x37, 0x6c, 0x59, 0xd9, 0xe8, 0x80, 0x9a, 0xc7, 0x62, 0xf7, 0x31, 0xbc, 0x13, 0x48}}
+	return a, nil
+}
+
+var __1528395680_user_deactivationUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x57\x00\xa8\xff\x42\x45\x47\x49\x4e\x3b\x0a\x0a\x41\x4c\x54\x45\x52\x20\x54\x41\x42\x4c\x45\x20\x75\x73\x65\x72\x73\x20\x41\x44\x44\x20\x43\x4f\x4c\x55\x4d\x4e\x20\x64\x65\x61\x63\x74\x69\x76\x61\x74\x65\x64\x5f\x61\x74\x20\x74\x69\x6d\x65\x73\x74\x61\x6d\x70\x20\x77\x69\x74\x68\x20\x74\x69\x6d\x65\x20\x7a\x6f\x6e\x65\x3b\x0a\x0a\x43\x4f\x4d\x4d\x49\x54\x3b\x0a\x03\x00\x3c\xde\xf3\xc0\x57\x00\x00\x00")
+
+func _1528395680_user_deactivationUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395680_user_deactivationUpSql,
+		"1528395680_user_deactivation.up.sql",
+	)
+}
+
+func _1528395680_user_deactivationUpSql() (*asset, error) {
+	bytes, err := _1528395680_user_deactivationUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395680_user_deactivation.up.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x57, 0x6a, 0x14, 0x54, 0x6e, 0x20, 0x39, 0xb8, 0x4, 0x9c, 0xa0, 0xb6, 0x82, 0xbb, 0xa9, 0x64, 0x3d, 0x77, 0xea, 0x17, 0x64, 0xab, 0x59, 0x98, 0x8b, 0x9d, 0x90, 0x6b, 0x11, 0x54, 0x40, 0x1}}
+	return a, nil
+}
+
+var __1528395681_user_group_permissionsDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x3e\x00\xc1\xff\x42\x45\x47\x49\x4e\x3b\x0a\x0a\x44\x52\x4f\x50\x20\x54\x41\x42\x4c\x45\x20\x49\x46\x20\x45\x58\x49\x53\x54\x53\x20\x75\x73\x65\x72\x5f\x67\x72\x6f\x75\x70\x5f\x70\x65\x72\x6d\x69\x73\x73\x69\x6f\x6e\x73\x3b\x0a\x0a\x43\x4f\x4d\x4d\x49\x54\x3b\x0a\x03\x00\xa0\xeb\xd3\x44\x3e\x00\x00\x00")
+
+func _1528395681_user_group_permissionsDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395681_user_group_permissionsDownSql,
+		"1528395681_user_group_permissions.down.sql",
+	)
+}
+
+func _1528395681_user_group_permissionsDownSql() (*asset, error) {
+	bytes, err := _1528395681_user_group_permissionsDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395681_user_group_permissions.down.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xb1, 0x50, 0x3d, 0x9d, 0xd1, 0x15, 0xa4, 0xd8, 0x45, 0x74, 0x61, 0xb3, 0xaa, 0x9c, 0xb, 0x31, 0x53, 0xa8, 0x26, 0x7d, 0x65, 0xfa, 0xa7, 0xae, 0x2d, 0xd8, 0xf2, 0xf4, 0x2f, 0xb0, 0xb3, 0x12}}
+	return a, nil
+}
+
+var __1528395681_user_group_permissionsUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x74\x90\xcf\x6a\xc3\x30\x0c\xc6\xef\x7e\x0a\x1d\x5b\xe8\x1b\xe4\x94\xba\xea\x30\xa4\x0e\x4b\x1c\x18\x8c\x61\xc2\x2c\x32\x1d\x92\x78\xb6\xb3\x7f\x4f\x3f\xe6\xae\x1d\xeb\xd6\xa3\xf4\x43\x3f\xf4\x7d\x5b\xbc\x51\xba\x10\x42\x36\x58\x1a\x04\x53\x6e\x2b\x04\xb5\x07\x5d\x1b\xc0\x3b\xd5\x9a\x16\x96\x48\xc1\x0e\x61\x5e\xbc\xf5\x14\x46\x8e\x91\xe7\x29\xc2\x4a\x00\xc0\x11\xb2\x03\x9e\x12\x0d\x14\xf2\x9d\xee\xaa\x0a\x1a\xdc\x63\x83\x5a\xe2\x51\x10\x57\xec\xd6\x50\x6b\xd8\x61\x85\x06\x41\x96\xad\x2c\x77\xb8\xc9\x92\x48\xe1\x85\x1f\xc9\xa6\x77\x4f\x90\xe8\x2d\x9d\x35\xbf\x39\xbb\xff\x68\x7e\x2d\x66\x72\xff\x70\xc1\xe6\x30\x58\x76\xf1\xf4\xde\x1f\x1e\xc8\xcf\xd6\xf7\x29\x51\x98\xae\x28\x16\xef\xfa\x44\xce\xf6\x09\x12\x8f\x14\x53\x3f\x7a\x78\xe5\xf4\x94\x47\xf8\x98\x27\xba\xb8\x90\xb5\x6e\x4d\x53\x2a\x6d\xae\x74\x67\xf3\xfa\x94\x6a\x99\xf8\x79\x21\xe8\xb4\xba\xed\x10\x56\xdf\x8d\x6e\xce\xa9\xbf\x5a\xf9\x99\xd8\xad\xc5\xba\x10\x42\xd6\x87\x83\x32\x85\xf8\x1c\x00\x65\xee\xab\x52\xc1\x01\x00\x00")
+
+func _1528395681_user_group_permissionsUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395681_user_group_permissionsUpSql,
+		"1528395681_user_group_permissions.up.sql",
+	)
+}
+
+func _1528395681_user_group_permissionsUpSql() (*asset, error) {
+	bytes, err := _1528395681_user_group_permissionsUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395681_user_group_permissions.up.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xd0, 0x4f, 0xed, 0x5d, 0xb6, 0x51, 0x3e, 0x70, 0x5a, 0xe5, 0xed, 0x4b, 0xad, 0x4c, 0x34, 0x77, 0xbb, 0x4c, 0xcf, 0x6d, 0xf2, 0xa9, 0x96, 0xcb, 0x6a, 0x88, 0x93, 0x35, 0x3c, 0x54, 0x4b, 0xb0}}
+	return a, nil
+}
+
+var __1528395682_audit_logsDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x72\x72\x75\xf7\xf4\xb3\xe6\xe2\x72\x09\xf2\x0f\x50\x08\x09\xf2\x74\x77\x77\x0d\x52\xf0\x74\x53\x70\x8d\xf0\x0c\x0e\x09\x56\x28\x29\xca\x4c\x8f\x2f\x4a\xcd\x4a\x4d\x2e\x89\x4f\x2c\x4d\xc9\x2c\x89\xcf\xc9\x4f\x2f\x8e\x2f\x2d\x48\x49\x2c\x49\x55\xf0\xf7\x53\x40\x08\x5a\x43\xcc\x70\x0b\xf5\x73\x0e\xf1\xf4\xf7\x43\x32\x04\x97\x7e\x0d\x4d\xa8\x9e\x10\x47\x27\x1f\x57\x24\x0d\xc8\x86\x72\x39\xfb\xfb\xfa\x7a\x86\x58\x73\x01\x06\x00\x62\x0f\x8a\xa7\xaa\x00\x00\x00")
+
+func _1528395682_audit_logsDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395682_audit_logsDownSql,
+		"1528395682_audit_logs.down.sql",
+	)
+}
+
+func _1528395682_audit_logsDownSql() (*asset, error) {
+	bytes, err := _1528395682_audit_logsDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395682_audit_logs.down.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xb7, 0x57, 0xd0, 0x25, 0x85, 0x61, 0x1d, 0x4f, 0xff, 0x23, 0x62, 0x3f, 0xde, 0x20, 0xa, 0x54, 0x92, 0x2, 0xe5, 0x53, 0xc7, 0x9a, 0x18, 0xd6, 0x18, 0x94, 0xf7, 0xa4, 0x28, 0xbb, 0x2a, 0xe3}}
+	return a, nil
+}
+
+var __1528395682_audit_logsUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x8c\x93\x5f\x6f\x9b\x30\x14\xc5\xdf\xfd\x29\xce\x43\xa4\x24\x52\xd3\x2f\xc0\x13\x05\x27\x43\x4b\x21\x72\x40\x4b\x9f\x90\x83\x6f\xa8\x2b\x62\x33\xe3\xac\xeb\x3e\xfd\x14\xfa\x0f\xd4\x35\xeb\x23\xbe\xc7\xbf\x7b\x7d\xce\xe5\x86\xaf\x92\x34\x60\x2c\x12\x3c\xcc\x39\xf2\xf0\x66\xcd\x91\x2c\x91\x66\x39\xf8\x2e\xd9\xe6\x5b\xc8\x93\xd2\xbe\x6c\x6c\xdd\x61\xc6\x00\x40\x2b\xec\x75\xdd\x91\xd3\xb2\xc1\x46\x24\xb7\xa1\xb8\xc3\x77\x7e\x77\xd5\x57\x65\xe5\xb5\x35\xf0\xf4\xdb\xf7\x94\xb4\x58\xaf\xdf\x2a\xd6\x95\xa7\x8e\x5c\xa9\x15\xb4\xf1\x54\x93\x1b\x96\x74\x3b\xbe\x86\x98\x2f\xc3\x62\x9d\x63\x3a\xfd\x40\x90\x35\x19\xff\x1f\xb9\x97\xae\x26\x5f\xfa\xa7\x96\xc6\xca\x51\x59\xab\x7f\x15\xf7\x74\xb0\x8e\xf0\xd0\x59\xb3\x7f\x69\x7e\xf0\xe4\x86\x07\x95\x23\xe9\x49\x95\xd2\xc3\xeb\x23\x75\x5e\x1e\x5b\x3c\x6a\x7f\xdf\x7f\xe2\x8f\x35\xf4\x71\x36\x63\x1f\x67\x73\x36\x7f\xb7\x3c\x49\x63\xbe\xfb\xd4\xf2\x72\xd0\x24\x4b\x87\x59\x14\xdb\x24\x5d\x61\xef\x1d\x11\x66\xef\xaa\x79\xf0\x45\xf0\x38\x8d\x0b\xec\x91\xf0\xcb\xf8\x67\x73\x2f\x71\x07\xe9\x5c\xbd\x67\x71\x76\x66\xb1\x00\x37\xde\x69\xea\x60\x0f\xf0\xf7\xf4\x3c\x1b\x1a\x5b\xe3\x78\xea\x3c\x0c\xfd\x22\x87\x3d\xe1\x68\x95\x3e\x68\x52\xb0\xa6\x22\x38\xaa\xac\x53\xa4\xae\x11\x53\x43\xe7\x35\xec\x20\x1d\x41\x36\x8d\x7d\x24\x85\xce\xc2\xdf\x4b\xcf\x16\x8b\x1e\xea\xc8\x93\x39\xab\xd0\xda\x46\x57\x4f\xa8\xa4\x39\x43\xc9\x1c\xac\xab\x48\x5d\xbf\x3e\x35\x13\x10\x7c\xb3\x0e\x23\x8e\x65\x91\x46\x79\x92\xa5\x70\xf4\x40\x95\x2f\x07\x2f\x3e\xb5\x4a\x7a\x9a\xcd\x21\x78\x5e\x88\x74\x0b\xef\x74\x5d\x93\xeb\x97\x67\x1d\xa6\xab\x22\x5c\x71\xb4\x4d\x5b\x77\x3f\x9b\xfe\x30\xdc\x62\x32\x61\xfd\x2f\xc8\x00\x11\x26\x5b\x0e\xbe\x8b\xf8\xa6\xef\x30\x1d\x18\xa7\x3b\xc8\xb6\x25\xa3\x16\xd6\x34\x4f\xd3\x80\xf1\x34\x0e\xd8\x64\x12\x30\x16\x8b\x6c\x83\x5c\x24\xab\x15\x17\xe7\x35\x7a\x89\xe2\xdc\xbc\xfc\x6c\xc8\x71\x2c\x6f\x91\xbe\x52\x2e\xdf\xbd\xe1\xcb\x4c\x70\x14\x9b\xb8\xf7\x66\x14\xf0\x32\x13\xe0\x61\xf4\x0d\x22\xfb\x01\xbe\xe3\x51\x91\x73\x6c\x44\x16\xf1\xb8\x10\xfc\x82\x69\x01\x63\x51\x76\x7b\x9b\xe4\x01\xfb\x3b\x00\xd6\xee\xd8\x1c\x93\x04\x00\x00")
+
+func _1528395682_audit_logsUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395682_audit_logsUpSql,
+		"1528395682_audit_logs.up.sql",
+	)
+}
+
+func _1528395682_audit_logsUpSql() (*asset, error) {
+	bytes, err := _1528395682_audit_logsUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395682_audit_logs.up.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x14, 0xbb, 0xcc, 0x31, 0xde, 0x63, 0xff, 0x61, 0xa, 0xc1, 0x1c, 0xfa, 0xb4, 0x55, 0xf2, 0xdc, 0x86, 0x0, 0x7, 0xd5, 0xa9, 0x65, 0x81, 0x1e, 0x78, 0x5e, 0xbc, 0x22, 0xd9, 0x43, 0xc5, 0xf3}}
+	return a, nil
+}
+
+var __1528395683_user_mfaDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x8c\xce\x4d\xae\xc2\x20\x14\xc5\xf1\x39\xab\x60\x1f\x8c\xda\x3e\x9e\x21\xe9\x87\x69\x31\x71\x76\x83\x70\x55\x22\x42\x03\xb7\x1a\x77\x6f\xd4\x81\xb3\xc6\xf9\xef\xe4\x7f\x6a\xb9\x51\xbd\x60\xac\x6a\xb5\x1c\xb9\xae\xea\x56\xf2\xa5\x60\x2e\xfc\x6f\x1c\xb6\xbc\x19\xda\x5d\xd7\x73\xf5\xcf\xe5\x5e\x4d\x7a\xe2\x21\xd9\x0b\x3a\x58\x22\xf9\x20\x7e\x5e\x1d\x8d\x0f\xe8\xa0\xf8\x53\x04\x1f\xc1\x10\xe1\x75\xa6\x22\x18\x7b\x57\x3e\xd9\x2f\x7f\x1d\x80\x3b\x1e\xcc\x42\xe7\x08\x36\xa3\xc3\x48\xde\x84\x22\x56\x7c\x46\x9b\x6e\x98\x1f\x60\x93\xc3\x55\x49\x89\x66\xc1\x58\x33\x74\x9d\xd2\x82\x3d\x07\x00\x75\xa6\xf8\x21\x03\x01\x00\x00")
+
+func _1528395683_user_mfaDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395683_user_mfaDownSql,
+		"1528395683_user_mfa.down.sql",
+	)
+}
+
+func _1528395683_user_mfaDownSql() (*asset, error) {
+	bytes, err := _1528395683_user_mfaDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395683_user_mfa.down.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x4d, 0x30, 0x4, 0x4f, 0xc5, 0x76, 0x30, 0xdb, 0xcc, 0x26, 0xe7, 0x84, 0x1a, 0xfd, 0x8e, 0xfb, 0xb7, 0xc9, 0xcd, 0x4, 0xb5, 0x8f, 0xc0, 0x81, 0xe3, 0xdf, 0xcb, 0x36, 0x1d, 0x98, 0xfb, 0x6e}}
+	return a, nil
+}
+
+var __1528395683_user_mfaUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xbc\x54\xcd\x6e\xdb\x3c\x10\xbc\xeb\x29\xf6\xe8\x00\xce\x87\x0f\x29\xda\x8b\x4f\x8a\xcc\x04\x42\x64\xb9\xd5\x0f\x90\x9c\x08\x4a\x5a\x5b\x44\x24\x52\x20\xd7\x49\xdd\xa7\x2f\x28\x39\x4a\x13\x2b\xa9\x03\xb4\x3d\x09\x24\x76\x67\x76\x67\x46\xbc\x64\xd7\x61\xbc\xf0\xbc\x20\x61\x7e\xc6\x20\xf3\x2f\x23\x06\xe1\x15\xc4\xeb\x0c\xd8\x6d\x98\x66\x29\xec\x2c\x1a\x4e\x9a\x3a\x98\x79\x00\x30\x9c\x65\x05\x52\x11\x6e\xd1\xc0\xd7\x24\x5c\xf9\xc9\x1d\xdc\xb0\x3b\x48\xd8\x15\x4b\x58\x1c\xb0\xa1\xcd\xce\x64\x75\x06\xeb\x18\x96\x2c\x62\x19\x83\xc0\x4f\x03\x7f\xc9\xe6\x3d\xce\xf9\x39\x64\x35\x42\x21\x2c\x7e\xba\x38\x47\x55\xea\x0a\x2b\xb0\xb5\x30\xee\x83\xa5\x41\x9a\x03\xaa\xd2\xec\x3b\xc2\x0a\x1e\x25\xd5\x90\x26\x01\x4f\x59\x90\xb0\x8c\xdf\xb0\xbb\xff\x7a\x9c\xb1\x84\x0f\x4d\x40\xf8\x9d\xfa\x05\xe2\x3c\x8a\x46\x2e\x77\x80\x9d\x22\xd9\x00\xd5\xd8\x8f\x07\xa5\x56\x1b\x69\x5a\xdb\xdf\xa0\x32\xba\x69\x5a\x54\x34\x70\x09\x78\x10\x8d\xac\xc0\xcd\xf5\xc4\x24\x8a\x06\x2b\x2e\x08\x48\xb6\x68\x49\xb4\xdd\x50\xeb\x8e\xf0\x43\x2b\x7c\xb1\x5a\x7f\x6b\x09\x3b\xd0\x9b\x9e\xa2\x11\x96\x7a\x3c\x47\x5f\xcd\x81\x34\x74\x06\x1f\x1c\xa5\xbb\xb5\xb0\x31\xba\x85\x02\xa5\xda\x82\x41\x57\x33\x10\xbb\x3e\xee\x8e\xbc\x47\x2b\xe4\x56\xaa\xe7\x15\x61\xc9\xae\xfc\x3c\xca\xe0\xff\x81\xbd\x34\x28\xe8\xfd\x31\x8f\x7b\x95\x7e\x9c\x9d\x79\x67\x27\x44\xc1\x60\xa9\x1f\xd0\xec\xf9\x30\xf2\x10\x0a\xe9\x2c\x33\x52\x34\xbf\xc6\x61\x3e\x99\x97\x91\xfa\x23\x61\x71\x5c\xdc\xd6\xe2\xe2\xf3\x17\x28\xf6\x84\x62\x84\x19\x49\x4e\xf0\xe5\xcf\x28\x13\xc6\x4b\x76\xfb\x7b\x65\xf8\xd3\xe2\xeb\x78\x52\xb8\x3c\x0d\xe3\x6b\x28\xc8\x20\xc2\xec\x50\x7b\x8a\xfe\x8f\x58\x88\x1d\xd5\x8a\x97\x06\x2b\x54\x24\x45\xf3\xaf\x5c\x50\xa2\xc5\xa9\xdf\xeb\x79\x12\x67\xf4\x4b\x7f\x20\x8f\xc3\x6f\xf9\x01\xa0\xdb\x15\x8d\x2c\xf9\x3d\xee\x27\x5d\xb4\x72\xab\x78\xa9\x77\x8a\xfe\x5e\xc6\xe7\xaf\xfe\xa8\x77\x10\x4e\x32\x7d\xca\x8e\x23\xeb\x27\x3d\x7b\x2b\x00\x7e\x94\xb1\xe4\xe0\xbf\xeb\xb6\xe0\x2f\x97\x10\xac\xa3\x7c\x15\xbf\x9a\x60\x23\xa4\x7b\x90\x7a\xdd\xa4\xe2\x82\x08\xdb\x8e\xec\xb1\xcb\xa3\x7c\x8b\x8f\xc0\x37\xba\xbc\xc7\x8a\x1f\x5e\xcd\x37\x54\x5a\x78\x5e\xb0\x5e\xad\xc2\x6c\xe1\xfd\x1c\x00\xc4\xae\xc8\xe2\x4e\x06\x00\x00")
+
+func _1528395683_user_mfaUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395683_user_mfaUpSql,
+		"1528395683_user_mfa.up.sql",
+	)
+}
+
+func _1528395683_user_mfaUpSql() (*asset, error) {
+	bytes, err := _1528395683_user_mfaUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395683_user_mfa.up.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x0, 0x9c, 0xde, 0xa6, 0x6b, 0x76, 0x77, 0x4, 0xec, 0x3d, 0x38, 0x56, 0x98, 0x53, 0x76, 0x2c, 0x9e, 0x9e, 0xf, 0x9c, 0x7d, 0x29, 0xef, 0x91, 0xcc, 0x0, 0x2a, 0xb5, 0x28, 0x88, 0xa9, 0xf3}}
+	return a, nil
+}
+
+var __1528395684_perms_sync_historyDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x3a\x00\xc5\xff\x42\x45\x47\x49\x4e\x3b\x0a\x0a\x44\x52\x4f\x50\x20\x54\x41\x42\x4c\x45\x20\x49\x46\x20\x45\x58\x49\x53\x54\x53\x20\x70\x65\x72\x6d\x73\x5f\x73\x79\x6e\x63\x5f\x68\x69\x73\x74\x6f\x72\x79\x3b\x0a\x0a\x43\x4f\x4d\x4d\x49\x54\x3b\x0a\x03\x00\x5a\x2d\xbd\x2b\x3a\x00\x00\x00")
+
+func _1528395684_perms_sync_historyDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395684_perms_sync_historyDownSql,
+		"1528395684_perms_sync_history.down.sql",
+	)
+}
+
+func _1528395684_perms_sync_historyDownSql() (*asset, error) {
+	bytes, err := _1528395684_perms_sync_historyDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395684_perms_sync_history.down.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x87, 0x3a, 0x34, 0x40, 0x45, 0xb2, 0x29, 0x21, 0x5c, 0x92, 0x2a, 0xc9, 0x78, 0x58, 0x22, 0xb3, 0x8, 0xe3, 0xe5, 0x98, 0x9f, 0x4b, 0x20, 0xd1, 0xb, 0xd0, 0x34, 0x66, 0xfc, 0x3d, 0x44, 0xb0}}
+	return a, nil
+}
+
+var __1528395684_perms_sync_historyUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x94\x93\xd1\x4e\xdb\x4c\x10\x85\xef\xf3\x14\xe7\x8e\x44\x22\xbf\xfe\x7b\xaa\x4a\xc6\x59\xc0\x22\x38\x95\x6d\x04\xa8\xaa\xac\x25\x9e\xe0\x95\x92\xdd\x68\x67\x4c\x08\x55\xdf\xbd\x5a\x3b\x0e\xb4\x41\x05\xee\xec\xdd\x39\x33\x67\xbf\x99\x39\x55\xe7\x49\x7a\x32\x18\xc4\x99\x8a\x0a\x85\x22\x3a\x9d\x2a\x24\x67\x48\x67\x05\xd4\x6d\x92\x17\x39\xd6\xe4\x57\x5c\xf2\xd6\xce\xcb\xda\xb0\x38\xbf\xc5\x70\x00\x00\xa6\xc2\xbd\x79\x60\xf2\x46\x2f\xf1\x2d\x4b\xae\xa2\xec\x0e\x97\xea\xee\xb8\xbd\x1d\x8f\xa1\x9e\xf4\x5c\x96\x5b\x38\x4b\x70\x0b\x34\x4c\xbe\x34\x15\xb4\xad\xe0\x69\xed\xc2\xb7\x61\x30\xc9\x31\x2a\x5a\x93\xad\x8c\x7d\x80\xb3\xd8\xd4\x5a\xb0\xd1\x8c\x50\x94\xaa\xff\xda\x7c\xbd\xda\x58\xa1\x07\xf2\xc8\xd4\x99\xca\x54\x1a\xab\xbc\x4d\xcc\x43\x53\x8d\x30\x4b\x31\x51\x53\x55\x28\xc4\x51\x1e\x47\x13\xd5\x79\xd9\x57\x3b\xd4\x86\xab\x7f\x49\xc7\x63\x14\x35\x81\xc9\x3f\x9a\x39\x21\x99\x70\x78\x8a\xd4\x04\xdd\x48\xfd\x8c\xb5\x77\x8f\xa6\x22\xcf\x90\xe0\x3a\xc0\x32\xcc\xc6\x59\xc6\x86\x3c\x61\x41\x32\xaf\xa9\xc2\xc2\xbb\x55\xf7\x90\x57\x0a\x7a\x92\xef\x3f\x5a\xd6\xe9\xf5\x74\x8a\x89\x3a\x8b\xae\xa7\x05\x8e\x7e\xfe\x3a\xea\xaa\xb3\x68\x2f\x54\x95\x5a\x20\x66\x45\x2c\x7a\xb5\xc6\xc6\x48\xdd\xfe\xe2\x39\x90\xed\xe5\x9d\x62\x61\xac\xe1\xfa\x53\x12\xdb\xac\xca\x80\x81\xf7\x7c\x0e\x1c\xfd\xff\x12\xd9\xc2\xfe\x50\xe4\xae\xa3\xa5\x9e\xcf\x5d\x63\xe5\xfd\xf4\xe3\x31\x6e\x6a\x92\x9a\x3c\xb4\xfd\x8b\x2f\x3c\x49\xe3\x2d\x85\xf1\x01\x79\xef\x42\x4c\xd5\x36\xe2\x80\x39\xeb\xc7\x1d\x71\x18\x61\xac\xb5\x97\x30\xa2\x9e\xb8\x59\x0a\xef\xba\xb0\x3b\xbc\x77\x6e\x49\xda\x1e\x7a\x5a\xe8\x25\x53\xe7\xab\x2b\x27\xf4\x24\xdd\x7f\x3c\x4b\xf3\x22\x8b\x92\xb4\x78\x63\x39\x5a\x40\xa5\xf3\x2d\x52\xc4\x17\x2a\xbe\xc4\x70\xd8\x4f\x6f\x92\xb7\x55\x46\xf8\xf2\x15\xc3\x7e\x2c\xfb\xc3\xd1\x60\xf4\xb2\x8a\x49\x3a\x51\xb7\xef\xae\x62\xd9\x27\x9e\xa5\x6f\xdc\xe2\x3a\x4f\xd2\x73\xdc\x8b\x27\x42\xef\xe1\x18\x61\xdc\x6f\x2e\x54\xa6\xf6\x2b\x99\xe4\x7b\x00\x27\x9f\x34\xd0\x3f\xe2\x03\x06\x76\xa1\xaf\x0d\xf4\xea\x3f\x0c\x0c\xe2\xd9\xd5\x55\x52\x9c\x0c\x7e\x0f\x00\xba\x91\xf6\x93\x9e\x04\x00\x00")
+
+func _1528395684_perms_sync_historyUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395684_perms_sync_historyUpSql,
+		"1528395684_perms_sync_history.up.sql",
+	)
+}
+
+func _1528395684_perms_sync_historyUpSql() (*asset, error) {
+	bytes, err := _1528395684_perms_sync_historyUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395684_perms_sync_history.up.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x35, 0xbe, 0xf9, 0xf6, 0xe9, 0x65, 0xdc, 0x42, 0xe2, 0x7, 0xb6, 0xa6, 0xb2, 0xfa, 0xb4, 0xe, 0x5e, 0xb9, 0xca, 0xdd, 0xc, 0xd3, 0x3f, 0x6c, 0x9a, 0x8a, 0x21, 0x0, 0x15, 0xe4, 0x7c, 0xa9}}
+	return a, nil
+}
+
+var __1528395685_user_sessionsDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x35\x00\xca\xff\x42\x45\x47\x49\x4e\x3b\x0a\x0a\x44\x52\x4f\x50\x20\x54\x41\x42\x4c\x45\x20\x49\x46\x20\x45\x58\x49\x53\x54\x53\x20\x75\x73\x65\x72\x5f\x73\x65\x73\x73\x69\x6f\x6e\x73\x3b\x0a\x0a\x43\x4f\x4d\x4d\x49\x54\x3b\x0a\x03\x00\xf0\xf5\x9e\x39\x35\x00\x00\x00")
+
+func _1528395685_user_sessionsDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395685_user_sessionsDownSql,
+		"1528395685_user_sessions.down.sql",
+	)
+}
+
+func _1528395685_user_sessionsDownSql() (*asset, error) {
+	bytes, err := _1528395685_user_sessionsDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395685_user_sessions.down.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x71, 0x84, 0xe, 0x16, 0xc6, 0xbe, 0xc7, 0x8c, 0xbf, 0xa8, 0xf6, 0x76, 0x6c, 0xe2, 0x7, 0x68, 0x1b, 0x50, 0x5f, 0xeb, 0x7, 0x2d, 0xbc, 0x48, 0xbc, 0x4c, 0x13, 0xbf, 0x79, 0x6c, 0x5f, 0x5c}}
+	return a, nil
+}
+
+var __1528395685_user_sessionsUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x9c\x92\x4f\x8f\xd3\x30\x10\xc5\xef\xf9\x14\xef\xd6\x54\xda\x72\x40\x82\xcb\x9e\xb2\xc9\x74\xb1\x48\x1d\x48\x1c\x69\xf7\x14\xb9\xc9\x50\x5b\xdd\x4d\xaa\x78\xa0\x2d\x9f\x1e\x35\x2d\x94\x3f\x12\xa0\x3d\xda\x33\xef\xf7\x9e\xe5\x77\x47\xf7\x4a\xdf\x46\x51\x5a\x52\x62\x08\x26\xb9\xcb\x09\x6a\x09\x5d\x18\xd0\x83\xaa\x4c\x85\xcf\x81\xc7\x26\x70\x08\x7e\xe8\x03\xe2\x08\x00\x7c\x87\xb5\xdf\x04\x1e\xbd\x7d\xc2\x87\x52\xad\x92\xf2\x11\xef\xe9\xf1\x66\x9a\x4e\x0a\xdf\xc1\xf7\xc2\x1b\x1e\x27\x98\xae\xf3\x1c\x25\x2d\xa9\x24\x9d\xd2\x99\x1a\x62\xdf\xcd\x51\x68\x64\x94\x93\x21\xa4\x49\x95\x26\x19\x9d\x21\x8b\x05\x8c\x63\x54\xef\x92\xc5\xeb\x37\x6f\xe1\x6c\x70\x18\x3e\x41\x1c\xe3\x12\x06\x5b\x3e\xde\x60\xef\x7c\xeb\xe0\x03\x82\x0c\x23\x9f\x4c\x7f\xd9\x69\x87\x61\xeb\x79\x16\x7e\x5c\x74\x56\xec\xab\xc9\x61\xcb\xc7\x26\x38\x7b\xa2\xaf\x8f\xc2\xf6\x9a\xb3\xd6\xea\x63\x7d\xc9\xe1\x77\x10\x3e\xc8\x75\x98\xd1\x32\xa9\x73\x83\xd9\xec\xa7\xd7\xda\x0d\xf7\xf2\x8f\xc5\x76\x64\x2b\xdc\x35\x56\x20\xfe\x99\x83\xd8\xe7\x1d\xf6\x5e\xdc\x74\xc4\xd7\xa1\xe7\x3f\xc5\xfd\xb0\x8f\xe7\x67\xa3\x27\x1b\xa4\xb1\xad\xf8\x2f\xfc\x62\x06\x1f\x76\x7e\xe4\xf0\x5f\xfa\x68\x7e\x2d\x86\xd2\x19\x3d\xfc\xad\x18\xcd\xf7\x4f\x2f\xf4\x6f\x8d\xa9\x2b\xa5\xef\xb1\x96\x91\x19\xf1\x65\x6b\x22\x17\xab\x95\x32\xb7\xd1\xb7\x01\x00\xfa\x10\xca\x17\x83\x02\x00\x00")
+
+func _1528395685_user_sessionsUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395685_user_sessionsUpSql,
+		"1528395685_user_sessions.up.sql",
+	)
+}
+
+func _1528395685_user_sessionsUpSql() (*asset, error) {
+	bytes, err := _1528395685_user_sessionsUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395685_user_sessions.up.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x6, 0x30, 0xcd, 0xd9, 0x33, 0xf9, 0x78, 0x4c, 0x1e, 0x4a, 0xb0, 0xd5, 0x30, 0xed, 0xb4, 0xf9, 0xfc, 0x0, 0x37, 0xf2, 0xdc, 0x7e, 0x79, 0x60, 0x27, 0x37, 0x59, 0xe6, 0xdc, 0x2e, 0x50, 0xf4}}
+	return a, nil
+}
+
+var __1528395686_patch_job_attemptsDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x48\x00\xb7\xff\x42\x45\x47\x49\x4e\x3b\x0a\x0a\x41\x4c\x54\x45\x52\x20\x54\x41\x42\x4c\x45\x20\x70\x61\x74\x63\x68\x5f\x6a\x6f\x62\x73\x20\x44\x52\x4f\x50\x20\x43\x4f\x4c\x55\x4d\x4e\x20\x49\x46\x20\x45\x58\x49\x53\x54\x53\x20\x61\x74\x74\x65\x6d\x70\x74\x73\x3b\x0a\x0a\x43\x4f\x4d\x4d\x49\x54\x3b\x0a\x03\x00\x8b\x8e\xab\xba\x48\x00\x00\x00")
+
+func _1528395686_patch_job_attemptsDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395686_patch_job_attemptsDownSql,
+		"1528395686_patch_job_attempts.down.sql",
+	)
+}
+
+func _1528395686_patch_job_attemptsDownSql() (*asset, error) {
+	bytes, err := _1528395686_patch_job_attemptsDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395686_patch_job_attempts.down.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xf2, 0xdf, 0xb8, 0x58, 0x60, 0x5a, 0xb2, 0x43, 0x70, 0x23, 0x33, 0x54, 0x2d, 0xba, 0x5b, 0x56, 0x37, 0xc, 0xf2, 0xa7, 0x62, 0x13, 0xdc, 0x1b, 0x3, 0x66, 0x94, 0xfa, 0x7d, 0xb4, 0x1e, 0x53}}
+	return a, nil
+}
+
+var __1528395686_patch_job_attemptsUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x58\x00\xa7\xff\x42\x45\x47\x49\x4e\x3b\x0a\x0a\x41\x4c\x54\x45\x52\x20\x54\x41\x42\x4c\x45\x20\x70\x61\x74\x63\x68\x5f\x6a\x6f\x62\x73\x20\x41\x44\x44\x20\x43\x4f\x4c\x55\x4d\x4e\x20\x61\x74\x74\x65\x6d\x70\x74\x73\x20\x69\x6e\x74\x65\x67\x65\x72\x20\x4e\x4f\x54\x20\x4e\x55\x4c\x4c\x20\x44\x45\x46\x41\x55\x4c\x54\x20\x30\x3b\x0a\x0a\x43\x4f\x4d\x4d\x49\x54\x3b\x0a\x03\x00\x9b\x2f\x1b\xf3\x58\x00\x00\x00")
+
+func _1528395686_patch_job_attemptsUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395686_patch_job_attemptsUpSql,
+		"1528395686_patch_job_attempts.up.sql",
+	)
+}
+
+func _1528395686_patch_job_attemptsUpSql() (*asset, error) {
+	bytes, err := _1528395686_patch_job_attemptsUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395686_patch_job_attempts.up.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x0, 0xaa, 0x37, 0x6, 0xb0, 0x49, 0x50, 0xb5, 0x12, 0x2b, 0xe1, 0x24, 0xe8, 0x31, 0x5, 0xf4, 0x4e, 0xc2, 0xba, 0x4c, 0x76, 0x9c, 0x4f, 0xf8, 0x91, 0x98, 0xb7, 0x70, 0x60, 0xde, 0xac, 0x2f}}
+	return a, nil
+}
+
+var __1528395687_changesets_pushed_commitDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x4d\x00\xb2\xff\x42\x45\x47\x49\x4e\x3b\x0a\x0a\x41\x4c\x54\x45\x52\x20\x54\x41\x42\x4c\x45\x20\x63\x68\x61\x6e\x67\x65\x73\x65\x74\x73\x20\x44\x52\x4f\x50\x20\x43\x4f\x4c\x55\x4d\x4e\x20\x49\x46\x20\x45\x58\x49\x53\x54\x53\x20\x70\x75\x73\x68\x65\x64\x5f\x63\x6f\x6d\x6d\x69\x74\x3b\x0a\x0a\x43\x4f\x4d\x4d\x49\x54\x3b\x0a\x03\x00\x9a\x3f\xd8\xb0\x4d\x00\x00\x00")
+
+func _1528395687_changesets_pushed_commitDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395687_changesets_pushed_commitDownSql,
+		"1528395687_changesets_pushed_commit.down.sql",
+	)
+}
+
+func _1528395687_changesets_pushed_commitDownSql() (*asset, error) {
+	bytes, err := _1528395687_changesets_pushed_commitDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395687_changesets_pushed_commit.down.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x6, 0x66, 0x5b, 0x96, 0x4f, 0xcb, 0x5b, 0x99, 0x54, 0x2, 0xb2, 0x6e, 0x56, 0xc9, 0xd1, 0x17, 0x77, 0x2e, 0x59, 0xca, 0xd0, 0x93, 0x90, 0x9a, 0xba, 0xc6, 0xa8, 0xd3, 0x33, 0x3a, 0xcb, 0x7a}}
+	return a, nil
+}
+
+var __1528395687_changesets_pushed_commitUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x47\x00\xb8\xff\x42\x45\x47\x49\x4e\x3b\x0a\x0a\x41\x4c\x54\x45\x52\x20\x54\x41\x42\x4c\x45\x20\x63\x68\x61\x6e\x67\x65\x73\x65\x74\x73\x20\x41\x44\x44\x20\x43\x4f\x4c\x55\x4d\x4e\x20\x70\x75\x73\x68\x65\x64\x5f\x63\x6f\x6d\x6d\x69\x74\x20\x74\x65\x78\x74\x3b\x0a\x0a\x43\x4f\x4d\x4d\x49\x54\x3b\x0a\x03\x00\xbb\xf6\xe3\x12\x47\x00\x00\x00")
+
+func _1528395687_changesets_pushed_commitUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395687_changesets_pushed_commitUpSql,
+		"1528395687_changesets_pushed_commit.up.sql",
+	)
+}
+
+func _1528395687_changesets_pushed_commitUpSql() (*asset, error) {
+	bytes, err := _1528395687_changesets_pushed_commitUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395687_changesets_pushed_commit.up.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x25, 0x7b, 0xbd, 0xb0, 0x9a, 0x5c, 0xde, 0xd4, 0xb5, 0xe0, 0xcf, 0x1d, 0xd5, 0xd6, 0x1d, 0x42, 0x45, 0x7e, 0xd8, 0x92, 0x3e, 0x41, 0x11, 0xda, 0x77, 0xa4, 0x34, 0x49, 0xcc, 0x6b, 0xfc, 0x11}}
+	return a, nil
+}
+
+var __1528395688_audit_log_export_cursorsDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x40\x00\xbf\xff\x42\x45\x47\x49\x4e\x3b\x0a\x0a\x44\x52\x4f\x50\x20\x54\x41\x42\x4c\x45\x20\x49\x46\x20\x45\x58\x49\x53\x54\x53\x20\x61\x75\x64\x69\x74\x5f\x6c\x6f\x67\x5f\x65\x78\x70\x6f\x72\x74\x5f\x63\x75\x72\x73\x6f\x72\x73\x3b\x0a\x0a\x43\x4f\x4d\x4d\x49\x54\x3b\x0a\x03\x00\xcf\x4e\x38\xc3\x40\x00\x00\x00")
+
+func _1528395688_audit_log_export_cursorsDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395688_audit_log_export_cursorsDownSql,
+		"1528395688_audit_log_export_cursors.down.sql",
+	)
+}
+
+func _1528395688_audit_log_export_cursorsDownSql() (*asset, error) {
+	bytes, err := _1528395688_audit_log_export_cursorsDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395688_audit_log_export_cursors.down.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x9f, 0xff, 0x80, 0x8e, 0xf3, 0xc3, 0x54, 0xf, 0xfa, 0x31, 0x9d, 0x5d, 0x71, 0x61, 0xad, 0xee, 0xee, 0xf4, 0xcb, 0x68, 0x16, 0x11, 0x54, 0x4c, 0xff, 0x74, 0x85, 0x72, 0x1a, 0xfd, 0xdb, 0x78}}
+	return a, nil
+}
+
+var __1528395688_audit_log_export_cursorsUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x4c\xce\x41\x4e\xc3\x30\x10\x05\xd0\xbd\x4f\xf1\x97\xad\x44\xb9\x40\x56\x69\xeb\x22\x8b\x24\x45\xa9\x2b\xd1\x95\x65\xea\x21\xb1\x48\xed\x28\x9e\xa8\x85\xd3\x23\x12\x40\x5d\x8e\xe6\xbf\x3f\xb3\x96\x4f\xaa\xca\x84\x58\xad\xa0\x5b\x82\xda\x22\xbe\x83\x5b\x42\x67\x13\xc3\x8e\xce\x33\xba\xd8\x80\x02\x0f\x9f\xa0\x5b\x1f\x07\x26\x07\x8e\x20\x7b\x6e\xe1\x28\xb1\x0f\x96\x7d\x0c\x7f\xf0\xce\x4c\xe9\x47\xb1\xa9\x65\xae\x25\x74\xbe\x2e\x24\xd4\x0e\xd5\x5e\x43\xbe\xaa\x83\x3e\xcc\x07\x4c\x17\x1b\x33\x57\x9b\xf3\x38\xa4\x38\x24\x2c\x04\x00\x24\x1f\x3e\xc0\x74\x63\xbc\xd4\xaa\xcc\xeb\x13\x9e\xe5\xe9\x61\x5a\xfd\xfc\xf7\x8b\xc8\x19\xef\xf0\xe6\x1b\x1f\x78\x2a\xaf\x8e\x45\x31\xa7\xc6\xde\x59\x26\x67\x2c\x83\xfd\x85\x12\xdb\x4b\x8f\xab\xe7\x76\x1a\xf1\x15\x03\xfd\x0b\x6c\xe5\x2e\x3f\x16\x1a\x21\x5e\x17\x4b\xb1\xcc\x84\xd8\xec\xcb\x52\xe9\x4c\x7c\x0f\x00\xb8\x59\x8d\x8c\x26\x01\x00\x00")
+
+func _1528395688_audit_log_export_cursorsUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395688_audit_log_export_cursorsUpSql,
+		"1528395688_audit_log_export_cursors.up.sql",
+	)
+}
+
+func _1528395688_audit_log_export_cursorsUpSql() (*asset, error) {
+	bytes, err := _1528395688_audit_log_export_cursorsUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395688_audit_log_export_cursors.up.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xf1, 0xc9, 0x6c, 0x93, 0xe8, 0xa2, 0x63, 0xce, 0xb7, 0x5f, 0x4, 0xde, 0xb1, 0xb8, 0x96, 0x59, 0xf4, 0xe3, 0x58, 0xde, 0x83, 0x45, 0x16, 0xd1, 0xea, 0x59, 0xf, 0x89, 0xd1, 0x61, 0x4b, 0xc7}}
+	return a, nil
+}
+
 // Asset loads and returns the asset for the given name.
 // It returns an error if the asset could not be found or
 // could not be loaded.
@@ -2447,6 +3287,46 @@ var _bindata = map[string]func() (*asset, error){
 	"1528395667_index_boolean_fields_on_repo.up.sql":                          _1528395667_index_boolean_fields_on_repoUpSql,
 	"1528395668_campaign_description_nullable.down.sql":                       _1528395668_campaign_description_nullableDownSql,
 	"1528395668_campaign_description_nullable.up.sql":                         _1528395668_campaign_description_nullableUpSql,
+	"1528395669_repo_key_value_pairs.down.sql":                                _1528395669_repo_key_value_pairsDownSql,
+	"1528395669_repo_key_value_pairs.up.sql":                                  _1528395669_repo_key_value_pairsUpSql,
+	"1528395670_patch_jobs.down.sql":                                          _1528395670_patch_jobsDownSql,
+	"1528395670_patch_jobs.up.sql":                                            _1528395670_patch_jobsUpSql,
+	"1528395671_campaigns_auto_merge.down.sql":                                _1528395671_campaigns_auto_mergeDownSql,
+	"1528395671_campaigns_auto_merge.up.sql":                                  _1528395671_campaigns_auto_mergeUpSql,
+	"1528395672_changesets_rebase_state.down.sql":                             _1528395672_changesets_rebase_stateDownSql,
+	"1528395672_changesets_rebase_state.up.sql":                               _1528395672_changesets_rebase_stateUpSql,
+	"1528395673_campaign_templates.down.sql":                                  _1528395673_campaign_templatesDownSql,
+	"1528395673_campaign_templates.up.sql":                                    _1528395673_campaign_templatesUpSql,
+	"1528395674_campaign_changeset_options.down.sql":                          _1528395674_campaign_changeset_optionsDownSql,
+	"1528395674_campaign_changeset_options.up.sql":                            _1528395674_campaign_changeset_optionsUpSql,
+	"1528395675_changeset_import_queries.down.sql":                            _1528395675_changeset_import_queriesDownSql,
+	"1528395675_changeset_import_queries.up.sql":                              _1528395675_changeset_import_queriesUpSql,
+	"1528395676_changeset_actions.down.sql":                                   _1528395676_changeset_actionsDownSql,
+	"1528395676_changeset_actions.up.sql":                                     _1528395676_changeset_actionsUpSql,
+	"1528395677_campaign_notifications.down.sql":                              _1528395677_campaign_notificationsDownSql,
+	"1528395677_campaign_notifications.up.sql":                                _1528395677_campaign_notificationsUpSql,
+	"1528395678_access_token_restrictions.down.sql":                           _1528395678_access_token_restrictionsDownSql,
+	"1528395678_access_token_restrictions.up.sql":                             _1528395678_access_token_restrictionsUpSql,
+	"1528395679_org_member_roles.down.sql":                                    _1528395679_org_member_rolesDownSql,
+	"1528395679_org_member_roles.up.sql":                                      _1528395679_org_member_rolesUpSql,
+	"1528395680_user_deactivation.down.sql":                                   _1528395680_user_deactivationDownSql,
+	"1528395680_user_deactivation.up.sql":                                     _1528395680_user_deactivationUpSql,
+	"1528395681_user_group_permissions.down.sql":                              _1528395681_user_group_permissionsDownSql,
+	"1528395681_user_group_permissions.up.sql":                                _1528395681_user_group_permissionsUpSql,
+	"1528395682_audit_logs.down.sql":                                          _1528395682_audit_logsDownSql,
+	"1528395682_audit_logs.up.sql":                                            _1528395682_audit_logsUpSql,
+	"1528395683_user_mfa.down.sql":                                            _1528395683_user_mfaDownSql,
+	"1528395683_user_mfa.up.sql":                                              _1528395683_user_mfaUpSql,
+	"1528395684_perms_sync_history.down.sql":                                  _1528395684_perms_sync_historyDownSql,
+	"1528395684_perms_sync_history.up.sql":                                    _1528395684_perms_sync_historyUpSql,
+	"1528395685_user_sessions.down.sql":                                       _1528395685_user_sessionsDownSql,
+	"1528395685_user_sessions.up.sql":                                         _1528395685_user_sessionsUpSql,
+	"1528395686_patch_job_attempts.down.sql":                                  _1528395686_patch_job_attemptsDownSql,
+	"1528395686_patch_job_attempts.up.sql":                                    _1528395686_patch_job_attemptsUpSql,
+	"1528395687_changesets_pushed_commit.down.sql":                            _1528395687_changesets_pushed_commitDownSql,
+	"1528395687_changesets_pushed_commit.up.sql":                              _1528395687_changesets_pushed_commitUpSql,
+	"1528395688_audit_log_export_cursors.down.sql":                            _1528395688_audit_log_export_cursorsDownSql,
+	"1528395688_audit_log_export_cursors.up.sql":                              _1528395688_audit_log_export_cursorsUpSql,
 }
 
 // AssetDir returns the file names below a certain
@@ -2594,6 +3474,46 @@ var _bintree = &bintree{nil, map[string]*bintree{
 	"1528395667_index_boolean_fields_on_repo.up.sql":                          {_1528395667_index_boolean_fields_on_repoUpSql, map[string]*bintree{}},
 	"1528395668_campaign_description_nullable.down.sql":                       {_1528395668_campaign_description_nullableDownSql, map[string]*bintree{}},
 	"1528395668_campaign_description_nullable.up.sql":                         {_1528395668_campaign_description_nullableUpSql, map[string]*bintree{}},
+	"1528395669_repo_key_value_pairs.down.sql":                                {_1528395669_repo_key_value_pairsDownSql, map[string]*bintree{}},
+	"1528395669_repo_key_value_pairs.up.sql":                                  {_1528395669_repo_key_value_pairsUpSql, map[string]*bintree{}},
+	"1528395670_patch_jobs.down.sql":                                          {_1528395670_patch_jobsDownSql, map[string]*bintree{}},
+	"1528395670_patch_jobs.up.sql":                                            {_1528395670_patch_jobsUpSql, map[string]*bintree{}},
+	"1528395671_campaigns_auto_merge.down.sql":                                {_1528395671_campaigns_auto_mergeDownSql, map[string]*bintree{}},
+	"1528395671_campaigns_auto_merge.up.sql":                                  {_1528395671_campaigns_auto_mergeUpSql, map[string]*bintree{}},
+	"1528395672_changesets_rebase_state.down.sql":                             {_1528395672_changesets_rebase_stateDownSql, map[string]*bintree{}},
+	"1528395672_changesets_rebase_state.up.sql":                               {_1528395672_changesets_rebase_stateUpSql, map[string]*bintree{}},
+	"1528395673_campaign_templates.down.sql":                                  {_1528395673_campaign_templatesDownSql, map[string]*bintree{}},
+	"1528395673_campaign_templates.up.sql":                                    {_1528395673_campaign_templatesUpSql, map[string]*bintree{}},
+	"1528395674_campaign_changeset_options.down.sql":                          {_1528395674_campaign_changeset_optionsDownSql, map[string]*bintree{}},
+	"1528395674_campaign_changeset_options.up.sql":                            {_1528395674_campaign_changeset_optionsUpSql, map[string]*bintree{}},
+	"1528395675_changeset_import_queries.down.sql":                            {_1528395675_changeset_import_queriesDownSql, map[string]*bintree{}},
+	"1528395675_changeset_import_queries.up.sql":                              {_1528395675_changeset_import_queriesUpSql, map[string]*bintree{}},
+	"1528395676_changeset_actions.down.sql":                                   {_1528395676_changeset_actionsDownSql, map[string]*bintree{}},
+	"1528395676_changeset_actions.up.sql":                                     {_1528395676_changeset_actionsUpSql, map[string]*bintree{}},
+	"1528395677_campaign_notifications.down.sql":                              {_1528395677_campaign_notificationsDownSql, map[string]*bintree{}},
+	"1528395677_campaign_notifications.up.sql":                                {_1528395677_campaign_notificationsUpSql, map[string]*bintree{}},
+	"1528395678_access_token_restrictions.down.sql":                           {_1528395678_access_token_restrictionsDownSql, map[string]*bintree{}},
+	"1528395678_access_token_restrictions.up.sql":                             {_1528395678_access_token_restrictionsUpSql, map[string]*bintree{}},
+	"1528395679_org_member_roles.down.sql":                                    {_1528395679_org_member_rolesDownSql, map[string]*bintree{}},
+	"1528395679_org_member_roles.up.sql":                                      {_1528395679_org_member_rolesUpSql, map[string]*bintree{}},
+	"1528395680_user_deactivation.down.sql":                                   {_1528395680_user_deactivationDownSql, map[string]*bintree{}},
+	"1528395680_user_deactivation.up.sql":                                     {_1528395680_user_deactivationUpSql, map[string]*bintree{}},
+	"1528395681_user_group_permissions.down.sql":                              {_1528395681_user_group_permissionsDownSql, map[string]*bintree{}},
+	"1528395681_user_group_permissions.up.sql":                                {_1528395681_user_group_permissionsUpSql, map[string]*bintree{}},
+	"1528395682_audit_logs.down.sql":                                          {_1528395682_audit_logsDownSql, map[string]*bintree{}},
+	"1528395682_audit_logs.up.sql":                                            {_1528395682_audit_logsUpSql, map[string]*bintree{}},
+	"1528395683_user_mfa.down.sql":                                            {_1528395683_user_mfaDownSql, map[string]*bintree{}},
+	"1528395683_user_mfa.up.sql":                                              {_1528395683_user_mfaUpSql, map[string]*bintree{}},
+	"1528395684_perms_sync_history.down.sql":                                  {_1528395684_perms_sync_historyDownSql, map[string]*bintree{}},
+	"1528395684_perms_sync_history.up.sql":                                    {_1528395684_perms_sync_historyUpSql, map[string]*bintree{}},
+	"1528395685_user_sessions.down.sql":                                       {_1528395685_user_sessionsDownSql, map[string]*bintree{}},
+	"1528395685_user_sessions.up.sql":                                         {_1528395685_user_sessionsUpSql, map[string]*bintree{}},
+	"1528395686_patch_job_attempts.down.sql":                                  {_1528395686_patch_job_attemptsDownSql, map[string]*bintree{}},
+	"1528395686_patch_job_attempts.up.sql":                                    {_1528395686_patch_job_attemptsUpSql, map[string]*bintree{}},
+	"1528395687_changesets_pushed_commit.down.sql":                            {_1528395687_changesets_pushed_commitDownSql, map[string]*bintree{}},
+	"1528395687_changesets_pushed_commit.up.sql":                              {_1528395687_changesets_pushed_commitUpSql, map[string]*bintree{}},
+	"1528395688_audit_log_export_cursors.down.sql":                            {_1528395688_audit_log_export_cursorsDownSql, map[string]*bintree{}},
+	"1528395688_audit_log_export_cursors.up.sql":                              {_1528395688_audit_log_export_cursorsUpSql, map[string]*bintree{}},
 }}
 
 // RestoreAsset restores an asset under the given directory.
@@ -55,6 +55,77 @@ const GitoliteSchemaJSON = `{
       },
       "examples": [[{ "name": "myrepo" }]]
     },
+    "authorization": {
+      "title": "GitoliteAuthorization",
+      "description": "If non-null, enforces repository permissions for the repositories of this Gitolite instance. Gitolite repositories are treated as private when this is set.",
+      "type": "object",
+      "additionalProperties": false,
+      "required": ["ldap"],
+      "properties": {
+        "ldap": {
+          "title": "GitoliteLDAPAuthorization",
+          "description": "Grants users access to repositories based on their groups in an LDAP directory. This requires that there be an item in the ` + "`" + `auth.providers` + "`" + ` field of type \"ldap\" with the same ` + "`" + `url` + "`" + ` field as specified here; users must sign in with it.",
+          "type": "object",
+          "additionalProperties": false,
+          "required": ["url", "groupSearchBaseDN", "groups"],
+          "properties": {
+            "url": {
+              "description": "The ` + "`" + `url` + "`" + ` of the LDAP auth provider whose directory holds the groups.",
+              "type": "string",
+              "pattern": "^ldaps?://",
+              "examples": ["ldaps://ldap.example.com"]
+            },
+            "groupSearchBaseDN": {
+              "description": "The DN of the subtree of the directory that contains the entries of groups.",
+              "type": "string",
+              "examples": ["ou=groups,dc=example,dc=com"]
+            },
+            "groupNameAttribute": {
+              "description": "The attribute of group entries that holds the name of the group.",
+              "type": "string",
+              "default": "cn"
+            },
+            "groupMemberAttribute": {
+              "description": "The attribute of group entries that holds the DNs of the members of the group.",
+              "type": "string",
+              "default": "member",
+              "examples": ["member", "uniqueMember"]
+            },
+            "groups": {
+              "description": "The repositories that the members of each group can access.",
+              "type": "array",
+              "items": {
+                "title": "LDAPGroupRepositories",
+                "type": "object",
+                "additionalProperties": false,
+                "required": ["group", "repositories"],
+                "properties": {
+                  "group": {
+                    "description": "The name of the group (the value of its ` + "`" + `groupNameAttribute` + "`" + `).",
+                    "type": "string",
+                    "minLength": 1
+                  },
+                  "repositories": {
+                    "description": "Regular expressions matched against the names of Gitolite repositories (without the ` + "`" + `prefix` + "`" + `). Members of the group can access the repositories whose name matches any of them.",
+                    "type": "array",
+                    "items": {
+                      "type": "string",
+                      "format": "regex"
+                    }
+                  }
+                }
+              },
+              "examples": [[{ "group": "engineering", "repositories": ["^services/", "^tools/cli$"] }]]
+            }
+          }
+        },
+        "ttl": {
+          "description": "The TTL of how long to cache the LDAP groups of a user. This is 3 hours by default.\n\nChanges to group memberships in the directory take up to this long to be reflected in repository permissions.",
+          "type": "string",
+          "default": "3h"
+        }
+      }
+    },
     "phabricatorMetadataCommand": {
       "description": "This is DEPRECATED. Use the ` + "`" + `phabricator` + "`" + ` field instead.",
       "type": "string"
@@ -46,12 +46,40 @@ type AWSCodeCommitGitCredentials struct {
 	Username string `json:"username"`
 }
 
+// AuditLog description: Settings for the audit log, which records security-relevant actions such as site configuration changes, site admin promotions, access token and external service changes and requests made with sudo access tokens.
+type AuditLog struct {
+	// Export description: Streams new audit log entries as JSON lines (one JSON object per line) to a file and/or an HTTP endpoint, e.g. for ingestion into a SIEM.
+	Export *AuditLogExport `json:"export,omitempty"`
+	// RetentionDays description: The number of days that audit log entries are kept for. Older entries are deleted. If 0 or unset, entries are never deleted.
+	RetentionDays int `json:"retentionDays,omitempty"`
+}
+
+// AuditLogExport description: Streams new audit log entries as JSON lines (one JSON object per line) to a file and/or an HTTP endpoint, e.g. for ingestion into a SIEM.
+type AuditLogExport struct {
+	// AuthorizationHeader description: The value of the Authorization header of the requests sent to `url`, if any.
+	AuthorizationHeader string `json:"authorizationHeader,omitempty"`
+	// File description: The path of a file that new entries are appended to. The file is created if it does not exist.
+	File string `json:"file,omitempty"`
+	// Url description: The URL of an HTTP endpoint that new entries are sent to in batches, as the body of POST requests with the content type "application/x-ndjson".
+	Url string `json:"url,omitempty"`
+}
+
 // AuthAccessTokens description: Settings for access tokens, which enable external tools to access the Sourcegraph API with the privileges of the user.
 type AuthAccessTokens struct {
 	// Allow description: Allow or restrict the use of access tokens. The default is "all-users-create", which enables all users to create access tokens. Use "none" to disable access tokens entirely. Use "site-admin-create" to restrict creation of new tokens to admin users (existing tokens will still work until revoked).
 	Allow string `json:"allow,omitempty"`
 }
 
+// AuthGroupMapping description: Maps a group of the authentication provider to organization memberships and repository permissions. Users in the group are added to its organizations when they sign in. Memberships that were added this way are removed when the user signs in after leaving the group.
+type AuthGroupMapping struct {
+	// Group description: The name of the group, as listed by the authentication provider.
+	Group string `json:"group"`
+	// Organizations description: The names of the organizations that users in the group are members of.
+	Organizations []string `json:"organizations,omitempty"`
+	// Repositories description: Regular expressions matching the entire names of the repositories that users in the group can read, in addition to the repositories they can read on the code host. They must be valid both as Go and as PostgreSQL regular expressions. Only enforced when repository permissions are stored by Sourcegraph, i.e. when `permissions.backgroundSync` or `permissions.userMapping` is enabled.
+	Repositories []string `json:"repositories,omitempty"`
+}
+
 // AuthProviderCommon description: Common properties for authentication providers.
 type AuthProviderCommon struct {
 	// DisplayName description: The name to use when displaying this authentication provider in the UI. Defaults to an auto-generated name with the type of authentication provider and other relevant identifiers (such as a hostname).
@@ -62,6 +90,7 @@ type AuthProviders struct {
 	Saml          *SAMLAuthProvider
 	Openidconnect *OpenIDConnectAuthProvider
 	HttpHeader    *HTTPHeaderAuthProvider
+	Ldap          *LDAPAuthProvider
 	Github        *GitHubAuthProvider
 	Gitlab        *GitLabAuthProvider
 }
@@ -79,6 +108,9 @@ func (v AuthProviders) MarshalJSON() ([]byte, error) {
 	if v.HttpHeader != nil {
 		return json.Marshal(v.HttpHeader)
 	}
+	if v.Ldap != nil {
+		return json.Marshal(v.Ldap)
+	}
 	if v.Github != nil {
 		return json.Marshal(v.Github)
 	}
@@ -103,12 +135,14 @@ func (v *AuthProviders) UnmarshalJSON(data []byte) error {
 		return json.Unmarshal(data, &v.Gitlab)
 	case "http-header":
 		return json.Unmarshal(data, &v.HttpHeader)
+	case "ldap":
+		return json.Unmarshal(data, &v.Ldap)
 	case "openidconnect":
 		return json.Unmarshal(data, &v.Openidconnect)
 	case "saml":
 		return json.Unmarshal(data, &v.Saml)
 	}
-	return fmt.Errorf("tagged union type must have a %q property whose value is one of %s", "type", []string{"builtin", "saml", "openidconnect", "http-header", "github", "gitlab"})
+	return fmt.Errorf("tagged union type must have a %q property whose value is one of %s", "type", []string{"builtin", "saml", "openidconnect", "http-header", "ldap", "github", "gitlab"})
 }
 
 // BitbucketCloudConnection description: Configuration for a connection to Bitbucket Cloud.
@@ -300,13 +334,45 @@ type Branding struct {
 	Light   *BrandAssets `json:"light,omitempty"`
 }
 
+// BuiltinAuthLockout description: Locks accounts after too many consecutive sign-in attempts with the correct password but a wrong second factor, to prevent second factors from being guessed. Attempts with a wrong password are only rate limited, so that nobody can lock other users out. A locked account can't be signed in to until the lockout expires or the user resets their password.
+type BuiltinAuthLockout struct {
+	// Duration description: How long the account stays locked (e.g., "15m" or "1h").
+	Duration string `json:"duration,omitempty"`
+	// FailedAttempts description: The number of consecutive sign-in attempts with a wrong second factor after which the account is locked. Any value less than zero means accounts are never locked.
+	FailedAttempts int `json:"failedAttempts,omitempty"`
+}
+
 // BuiltinAuthProvider description: Configures the builtin username-password authentication provider.
 type BuiltinAuthProvider struct {
 	// AllowSignup description: Allows new visitors to sign up for accounts. The sign-up page will be enabled and accessible to all visitors.
 	//
 	// SECURITY: If the site has no users (i.e., during initial setup), it will always allow the first user to sign up and become site admin **without any approval** (first user to sign up becomes the admin).
-	AllowSignup bool   `json:"allowSignup,omitempty"`
-	Type        string `json:"type"`
+	AllowSignup bool `json:"allowSignup,omitempty"`
+	// Lockout description: Locks accounts after too many consecutive sign-in attempts with the correct password but a wrong second factor, to prevent second factors from being guessed. Attempts with a wrong password are only rate limited, so that nobody can lock other users out. A locked account can't be signed in to until the lockout expires or the user resets their password.
+	Lockout *BuiltinAuthLockout `json:"lockout,omitempty"`
+	// RequireMFA description: Requires users to sign in with a second authentication factor (an authenticator app or a security key) in addition to their password. Users who haven't set up a second factor are asked to set up an authenticator app when they next sign in.
+	//
+	// - "none": a second factor is optional
+	// - "siteAdmins": site admins must use a second factor
+	// - "everyone": all users must use a second factor
+	RequireMFA string `json:"requireMFA,omitempty"`
+	Type       string `json:"type"`
+}
+
+// CampaignsCommitter description: The committer identity of the commits of changesets of campaigns in this namespace. When commits are signed with the `campaigns.signingKey` site configuration, it should match the identity of the key.
+type CampaignsCommitter struct {
+	// Email description: The email address of the committer.
+	Email string `json:"email"`
+	// Name description: The name of the committer.
+	Name string `json:"name"`
+}
+
+// CampaignsSigningKey description: The key gitserver signs the commits of campaign changesets with, so that code hosts show them as verified. The key must not be protected with a passphrase. Its committer identity is set with the `campaigns.committer` setting of the campaign's namespace.
+type CampaignsSigningKey struct {
+	// EncryptedKey description: The ASCII-armored GPG secret key or the OpenSSH private key, encrypted with the `encryptCampaignsSigningKey` GraphQL mutation. Requires the `SRC_SECRET_KEY` environment variable to be set to the same value on frontend and gitserver.
+	EncryptedKey string `json:"encryptedKey"`
+	// Type description: The type of the key.
+	Type string `json:"type"`
 }
 
 // CloneURLToRepositoryName description: Describes a mapping from clone URL to repository name. The `from` field contains a regular expression with named capturing groups. The `to` field contains a template string that references capturing group names. For instance, if `from` is "^../(?P<name>\w+)$" and `to` is "github.com/user/{name}", the clone URL "../myRepository" would be mapped to the repository name "github.com/user/myRepository".
@@ -382,6 +448,14 @@ type ExcludedGitoliteRepo struct {
 	// Name description: The name of a Gitolite repo ("my-repo") to exclude from mirroring.
 	Name string `json:"name,omitempty"`
 }
+type ExcludedManifestRepo struct {
+	// Name description: The name of a repository listed in the manifest ("services/billing") to exclude from mirroring.
+	Name string `json:"name,omitempty"`
+}
+type ExcludedPerforceDepot struct {
+	// Path description: The depot or stream path ("//engine/dev/") to exclude from mirroring.
+	Path string `json:"path,omitempty"`
+}
 
 // ExperimentalFeatures description: Experimental features to enable or disable. Features that are now enabled by default are marked as deprecated.
 type ExperimentalFeatures struct {
@@ -611,8 +685,20 @@ type GitLabRateLimit struct {
 	RequestsPerHour float64 `json:"requestsPerHour"`
 }
 
+// GitoliteAuthorization description: If non-null, enforces repository permissions for the repositories of this Gitolite instance. Gitolite repositories are treated as private when this is set.
+type GitoliteAuthorization struct {
+	// Ldap description: Grants users access to repositories based on their groups in an LDAP directory. This requires that there be an item in the `auth.providers` field of type "ldap" with the same `url` field as specified here; users must sign in with it.
+	Ldap GitoliteLDAPAuthorization `json:"ldap"`
+	// Ttl description: The TTL of how long to cache the LDAP groups of a user. This is 3 hours by default.
+	//
+	// Changes to group memberships in the directory take up to this long to be reflected in repository permissions.
+	Ttl string `json:"ttl,omitempty"`
+}
+
 // GitoliteConnection description: Configuration for a connection to Gitolite.
 type GitoliteConnection struct {
+	// Authorization description: If non-null, enforces repository permissions for the repositories of this Gitolite instance. Gitolite repositories are treated as private when this is set.
+	Authorization *GitoliteAuthorization `json:"authorization,omitempty"`
 	// Blacklist description: Regular expression to filter repositories from auto-discovery, so they will not get cloned automatically.
 	Blacklist string `json:"blacklist,omitempty"`
 	// Exclude description: A list of repositories to never mirror from this Gitolite instance. Supports excluding by exact name ({"name": "foo"}).
@@ -629,6 +715,20 @@ type GitoliteConnection struct {
 	Prefix string `json:"prefix"`
 }
 
+// GitoliteLDAPAuthorization description: Grants users access to repositories based on their groups in an LDAP directory. This requires that there be an item in the `auth.providers` field of type "ldap" with the same `url` field as specified here; users must sign in with it.
+type GitoliteLDAPAuthorization struct {
+	// GroupMemberAttribute description: The attribute of group entries that holds the DNs of the members of the group.
+	GroupMemberAttribute string `json:"groupMemberAttribute,omitempty"`
+	// GroupNameAttribute description: The attribute of group entries that holds the name of the group.
+	GroupNameAttribute string `json:"groupNameAttribute,omitempty"`
+	// GroupSearchBaseDN description: The DN of the subtree of the directory that contains the entries of groups.
+	GroupSearchBaseDN string `json:"groupSearchBaseDN"`
+	// Groups description: The repositories that the members of each group can access.
+	Groups []*LDAPGroupRepositories `json:"groups"`
+	// Url description: The `url` of the LDAP auth provider whose directory holds the groups.
+	Url string `json:"url"`
+}
+
 // HTTPHeaderAuthProvider description: Configures the HTTP header authentication provider (which authenticates users by consulting an HTTP request header set by an authentication proxy such as https://github.com/bitly/oauth2_proxy).
 type HTTPHeaderAuthProvider struct {
 	// StripUsernameHeaderPrefix description: The prefix that precedes the username portion of the HTTP header specified in `usernameHeader`. If specified, the prefix will be stripped from the header value and the remainder will be used as the username. For example, if using Google Identity-Aware Proxy (IAP) with Google Sign-In, set this value to `accounts.google.com:`.
@@ -687,11 +787,62 @@ func (v *IdentityProvider) UnmarshalJSON(data []byte) error {
 	return fmt.Errorf("tagged union type must have a %q property whose value is one of %s", "type", []string{"oauth", "username", "external"})
 }
 
+// LDAPAttributes description: The attributes of user entries that are used for the Sourcegraph user's profile.
+type LDAPAttributes struct {
+	// DisplayName description: The attribute that holds the display name.
+	DisplayName string `json:"displayName,omitempty"`
+	// Email description: The attribute that holds the email address. The email address is considered verified.
+	Email string `json:"email,omitempty"`
+	// Username description: The attribute that holds the username.
+	Username string `json:"username,omitempty"`
+}
+
+// LDAPAuthProvider description: Configures the LDAP authentication provider, which authenticates users with their username and password in an LDAP directory (such as OpenLDAP or Active Directory). Sourcegraph binds to the directory with the configured service account, searches for the user's entry, and then binds as the user to check their password.
+type LDAPAuthProvider struct {
+	// Attributes description: The attributes of user entries that are used for the Sourcegraph user's profile.
+	Attributes *LDAPAttributes `json:"attributes,omitempty"`
+	// BindDN description: The DN of the service account that Sourcegraph binds as to search the directory. If empty, searches are performed anonymously.
+	BindDN string `json:"bindDN,omitempty"`
+	// BindPassword description: The password of the service account (`bindDN`).
+	BindPassword string `json:"bindPassword,omitempty"`
+	// Certificate description: TLS certificate of the LDAP server (or of the CA that signed it). This is only necessary if the certificate is self-signed or signed by an internal CA. To get the certificate run `openssl s_client -connect HOST:636 -showcerts < /dev/null 2> /dev/null | openssl x509 -outform PEM`. To escape the value into a JSON string, you may want to use a tool like https://json-escape-text.now.sh.
+	Certificate string `json:"certificate,omitempty"`
+	// DisplayName description: The name to use when displaying this authentication provider in the UI. Defaults to "LDAP".
+	DisplayName string `json:"displayName,omitempty"`
+	// StartTLS description: Upgrade connections to an ldap:// URL to TLS with the StartTLS operation before sending any credentials.
+	StartTLS bool   `json:"startTLS,omitempty"`
+	Type     string `json:"type"`
+	// Url description: URL of the LDAP server. Use the ldaps scheme to connect with TLS, or the ldap scheme with `startTLS` to upgrade the connection to TLS.
+	Url string `json:"url"`
+	// UserSearchBaseDN description: The DN of the subtree of the directory that contains the entries of users.
+	UserSearchBaseDN string `json:"userSearchBaseDN"`
+	// UserSearchFilter description: The filter used to find the entry of a user signing in. `%s` is replaced with the (escaped) username that the user entered. The filter must match exactly one entry; it can also restrict who may sign in (e.g., to the members of a group).
+	UserSearchFilter string `json:"userSearchFilter,omitempty"`
+}
+type LDAPGroupRepositories struct {
+	// Group description: The name of the group (the value of its `groupNameAttribute`).
+	Group string `json:"group"`
+	// Repositories description: Regular expressions matched against the names of Gitolite repositories (without the `prefix`). Members of the group can access the repositories whose name matches any of them.
+	Repositories []string `json:"repositories"`
+}
+
 // Log description: Configuration for logging and alerting, including to external services.
 type Log struct {
 	// Sentry description: Configuration for Sentry
 	Sentry *Sentry `json:"sentry,omitempty"`
 }
+
+// ManifestConnection description: Configuration for a connection to a repository manifest: a JSON or YAML document listing the repositories to sync, served over HTTP(S) or read from a local file.
+type ManifestConnection struct {
+	// Exclude description: A list of repositories listed in the manifest to never mirror. Supports excluding by exact name ({"name": "foo"}).
+	Exclude []*ExcludedManifestRepo `json:"exclude,omitempty"`
+	// Token description: A bearer token sent in the Authorization header when fetching the manifest over HTTP(S).
+	Token string `json:"token,omitempty"`
+	// Url description: Location of the manifest. Either an HTTP(S) URL, or a file:// URL or absolute path of a file readable by repo-updater. The manifest is fetched again on every repository sync.
+	//
+	// The manifest is a JSON or YAML object with a "repos" list. Each entry requires a "name" (the Sourcegraph repository name) and a "cloneURL", and may set "description", "visibility" ("public" or "private"), "fork", "archived" and string key/value "metadata".
+	Url string `json:"url"`
+}
 type Notice struct {
 	// Dismissible description: Whether this notice can be dismissed (closed) by the user.
 	Dismissible bool `json:"dismissible,omitempty"`
@@ -729,6 +880,10 @@ type OpenIDConnectAuthProvider struct {
 	// ConfigID description: An identifier that can be used to reference this authentication provider in other parts of the config. For example, in configuration for a code host, you may want to designate this authentication provider as the identity provider for the code host.
 	ConfigID    string `json:"configID,omitempty"`
 	DisplayName string `json:"displayName,omitempty"`
+	// GroupMappings description: Maps groups of the user (listed in the `groupsClaimName` claim) to organization memberships and repository permissions. Group mappings are re-evaluated each time the user signs in.
+	GroupMappings []*AuthGroupMapping `json:"groupMappings,omitempty"`
+	// GroupsClaimName description: The name of the claim of the ID token or userinfo response that lists the groups of the user, which are mapped by `groupMappings`.
+	GroupsClaimName string `json:"groupsClaimName,omitempty"`
 	// Issuer description: The URL of the OpenID Connect issuer.
 	//
 	// For Google Apps: https://accounts.google.com
@@ -755,17 +910,37 @@ type ParentSourcegraph struct {
 	Url string `json:"url,omitempty"`
 }
 
+// PerforceConnection description: Configuration for a connection to Perforce Server.
+type PerforceConnection struct {
+	// Depots description: List of depot paths to import as Git repositories. A path that names a stream depot (e.g. "//engine/") is expanded so that each of its streams becomes its own repository. If omitted, every local and stream depot visible to the user is imported.
+	Depots []string `json:"depots,omitempty"`
+	// Exclude description: A list of depot or stream paths to never mirror from this Perforce Server.
+	Exclude []*ExcludedPerforceDepot `json:"exclude,omitempty"`
+	// P4passwd description: The ticket or password to be used for p4 CLI (P4PASSWD).
+	P4passwd string `json:"p4passwd"`
+	// P4port description: The Perforce Server address to be used for p4 CLI (P4PORT).
+	P4port string `json:"p4port"`
+	// P4user description: The user to be authenticated for p4 CLI (P4USER). The user must be able to list depots, streams and changelists of the configured depots.
+	P4user string `json:"p4user"`
+	// RepositoryPathPattern description: The pattern used to generate the corresponding Sourcegraph repository name for Perforce depots and streams. In the pattern, the variable "{host}" is replaced with the host name of the Perforce Server (without the port), and "{depot}" is replaced with the depot or stream path without the leading and trailing slashes.
+	//
+	// For example, if your Perforce Server is perforce.company.com:1666 and a depot is "//engine/main/", then a repositoryPathPattern of "{host}/{depot}" would mean that the depot is available on Sourcegraph at https://sourcegraph.example.com/perforce.company.com/engine/main.
+	//
+	// It is important that the Sourcegraph repository name generated with this pattern be unique to this Perforce Server. If different code hosts generate repository names that collide, Sourcegraph's behavior is undefined.
+	RepositoryPathPattern string `json:"repositoryPathPattern,omitempty"`
+}
+
 // PermissionsBackgroundSync description: Sync code host repository and user permissions in the background.
 type PermissionsBackgroundSync struct {
 	// Enabled description: Whether syncing permissions in the background is enabled.
 	Enabled bool `json:"enabled,omitempty"`
 }
 
-// PermissionsUserMapping description: Settings for Sourcegraph permissions, which allow the site admin to explicitly manage repository permissions via the GraphQL API. This setting cannot be enabled if repository permissions for any specific external service are enabled (i.e., when the external service's `authorization` field is set).
+// PermissionsUserMapping description: Settings for Sourcegraph permissions, which allow the site admin to explicitly manage repository permissions via the GraphQL API. Explicit permissions apply to the repositories of external services without repository permissions (i.e., without an `authorization` field), such as Gitolite, Phabricator, AWS CodeCommit and other Git hosts. Repositories of external services with repository permissions keep being authorized by their code host.
 type PermissionsUserMapping struct {
 	// BindID description: The type of identifier to identify a user. The default is "email", which uses the email address to identify a user. Use "username" to identify a user by their username. Changing this setting will erase any permissions created for users that do not yet exist.
 	BindID string `json:"bindID,omitempty"`
-	// Enabled description: Whether permissions user mapping is enabled. There must be no `authorization` field in any external service configuration before enabling this.
+	// Enabled description: Whether permissions user mapping is enabled.
 	Enabled bool `json:"enabled,omitempty"`
 }
 
@@ -808,6 +983,10 @@ type SAMLAuthProvider struct {
 	// ConfigID description: An identifier that can be used to reference this authentication provider in other parts of the config. For example, in configuration for a code host, you may want to designate this authentication provider as the identity provider for the code host.
 	ConfigID    string `json:"configID,omitempty"`
 	DisplayName string `json:"displayName,omitempty"`
+	// GroupMappings description: Maps groups of the user (listed in the `groupsAttributeName` attribute) to organization memberships and repository permissions. Group mappings are re-evaluated each time the user signs in.
+	GroupMappings []*AuthGroupMapping `json:"groupMappings,omitempty"`
+	// GroupsAttributeName description: The name of the SAML assertion attribute that lists the groups of the user, which are mapped by `groupMappings`.
+	GroupsAttributeName string `json:"groupsAttributeName,omitempty"`
 	// IdentityProviderMetadata description: The SAML Identity Provider metadata XML contents (for static configuration of the SAML Service Provider). The value of this field should be an XML document whose root element is `<EntityDescriptor>` or `<EntityDescriptors>`. To escape the value into a JSON string, you may want to use a tool like https://json-escape-text.now.sh.
 	IdentityProviderMetadata string `json:"identityProviderMetadata,omitempty"`
 	// IdentityProviderMetadataURL description: The SAML Identity Provider metadata URL (for dynamic configuration of the SAML Service Provider).
@@ -879,6 +1058,8 @@ type Sentry struct {
 type Settings struct {
 	// AlertsShowPatchUpdates description: Whether to show alerts for patch version updates. Alerts for major and minor version updates will always be shown.
 	AlertsShowPatchUpdates bool `json:"alerts.showPatchUpdates,omitempty"`
+	// CampaignsCommitter description: The committer identity of the commits of changesets of campaigns in this namespace. When commits are signed with the `campaigns.signingKey` site configuration, it should match the identity of the key.
+	CampaignsCommitter *CampaignsCommitter `json:"campaigns.committer,omitempty"`
 	// CodeHostUseNativeTooltips description: Whether to use the code host's native hover tooltips when they exist (GitHub's jump-to-definition tooltips, for example).
 	CodeHostUseNativeTooltips bool `json:"codeHost.useNativeTooltips,omitempty"`
 	// ExperimentalFeatures description: Experimental features to enable or disable. Features that are now enabled by default are marked as deprecated.
@@ -927,6 +1108,8 @@ type SettingsExperimentalFeatures struct {
 
 // SiteConfiguration description: Configuration for a Sourcegraph site.
 type SiteConfiguration struct {
+	// AuditLog description: Settings for the audit log, which records security-relevant actions such as site configuration changes, site admin promotions, access token and external service changes and requests made with sudo access tokens.
+	AuditLog *AuditLog `json:"auditLog,omitempty"`
 	// AuthAccessTokens description: Settings for access tokens, which enable external tools to access the Sourcegraph API with the privileges of the user.
 	AuthAccessTokens *AuthAccessTokens `json:"auth.accessTokens,omitempty"`
 	// AuthEnableUsernameChanges description: Enables users to change their username after account creation. Warning: setting this to be true has security implications if you have enabled (or will at any point in the future enable) repository permissions with an option that relies on username equivalency between Sourcegraph and an external service or authentication provider. Do NOT set this to true if you are using non-built-in authentication OR rely on username equivalency for repository permissions.
@@ -937,11 +1120,17 @@ type SiteConfiguration struct {
 	AuthProviders []AuthProviders `json:"auth.providers,omitempty"`
 	// AuthPublic description: WARNING: This option has been removed as of 3.8.
 	AuthPublic bool `json:"auth.public,omitempty"`
-	// AuthSessionExpiry description: The duration of a user session, after which it expires and the user is required to re-authenticate. The default is 90 days. There is typically no need to set this, but some users may have specific internal security requirements.
+	// AuthSessionAbsoluteExpiry description: The maximum duration of a user session since the user signed in, after which it expires (even if the user was active) and the user is required to re-authenticate. By default, sessions only expire after a period of inactivity (see auth.sessionExpiry).
+	//
+	// The string format is that of the Duration type in the Go time package (https://golang.org/pkg/time/#ParseDuration). E.g., "720h" indicates a timespan of 30 days.
+	//
+	// Changing this field also affects existing sessions.
+	AuthSessionAbsoluteExpiry string `json:"auth.sessionAbsoluteExpiry,omitempty"`
+	// AuthSessionExpiry description: The duration of inactivity after which a user session expires and the user is required to re-authenticate. The default is 90 days. To also limit the duration of sessions of active users, see auth.sessionAbsoluteExpiry. There is typically no need to set this, but some users may have specific internal security requirements.
 	//
 	// The string format is that of the Duration type in the Go time package (https://golang.org/pkg/time/#ParseDuration). E.g., "720h", "43200m", "2592000s" all indicate a timespan of 30 days.
 	//
-	// Note: changing this field does not affect the expiration of existing sessions. If you would like to enforce this limit for existing sessions, you must log out currently signed-in users. You can force this by removing all keys beginning with "session_" from the Redis store:
+	// Note: changing this field does not affect the expiration of existing sessions. If you would like to enforce this limit for existing sessions, you must log out currently signed-in users, e.g. by revoking their sessions with the revokeAllSessions GraphQL mutation or by removing all keys beginning with "session_" from the Redis store:
 	//
 	// * For deployments using `sourcegraph/server`: `docker exec $CONTAINER_ID redis-cli --raw keys 'session_*' | xargs docker exec $CONTAINER_ID redis-cli del`
 	// * For cluster deployments:
@@ -961,6 +1150,8 @@ type SiteConfiguration struct {
 	Branding *Branding `json:"branding,omitempty"`
 	// CampaignsReadAccessEnabled description: Enables read-only access to campaigns for non-site-admin users. This is a setting for the experimental campaigns feature. These will only have an effect when campaigns is enabled with `{"experimentalFeatures": {"automation": "enabled"}}`.
 	CampaignsReadAccessEnabled *bool `json:"campaigns.readAccess.enabled,omitempty"`
+	// CampaignsSigningKey description: The key gitserver signs the commits of campaign changesets with, so that code hosts show them as verified. The key must not be protected with a passphrase. Its committer identity is set with the `campaigns.committer` setting of the campaign's namespace.
+	CampaignsSigningKey *CampaignsSigningKey `json:"campaigns.signingKey,omitempty"`
 	// CorsOrigin description: Required when using any of the native code host integrations for Phabricator, GitLab, or Bitbucket Server. It is a space-separated list of allowed origins for cross-origin HTTP requests which should be the base URL for your Phabricator, GitLab, or Bitbucket Server instance.
 	CorsOrigin string `json:"corsOrigin,omitempty"`
 	// DebugSearchSymbolsParallelism description: (debug) controls the amount of symbol search parallelism. Defaults to 20. It is not recommended to change this outside of debugging scenarios. This option will be removed in a future version.
@@ -1023,8 +1214,10 @@ type SiteConfiguration struct {
 	ParentSourcegraph *ParentSourcegraph `json:"parentSourcegraph,omitempty"`
 	// PermissionsBackgroundSync description: Sync code host repository and user permissions in the background.
 	PermissionsBackgroundSync *PermissionsBackgroundSync `json:"permissions.backgroundSync,omitempty"`
-	// PermissionsUserMapping description: Settings for Sourcegraph permissions, which allow the site admin to explicitly manage repository permissions via the GraphQL API. This setting cannot be enabled if repository permissions for any specific external service are enabled (i.e., when the external service's `authorization` field is set).
+	// PermissionsUserMapping description: Settings for Sourcegraph permissions, which allow the site admin to explicitly manage repository permissions via the GraphQL API. Explicit permissions apply to the repositories of external services without repository permissions (i.e., without an `authorization` field), such as Gitolite, Phabricator, AWS CodeCommit and other Git hosts. Repositories of external services with repository permissions keep being authorized by their code host.
 	PermissionsUserMapping *PermissionsUserMapping `json:"permissions.userMapping,omitempty"`
+	// RepoListFullSyncInterval description: Minimum interval (in minutes) between full listings of the repositories of code hosts. In between, code hosts that support it (GitHub and GitLab) are only asked for the repositories updated since the previous check, which uses much less of their API rate limits. Repositories removed from a code host are only removed from Sourcegraph by a full listing. If 0, every check is a full listing.
+	RepoListFullSyncInterval int `json:"repoListFullSyncInterval,omitempty"`
 	// RepoListUpdateInterval description: Interval (in minutes) for checking code hosts (such as GitHub, Gitolite, etc.) for new repositories.
 	RepoListUpdateInterval int `json:"repoListUpdateInterval,omitempty"`
 	// SearchIndexEnabled description: Whether indexed search is enabled. If unset Sourcegraph detects the environment to decide if indexed search is enabled. Indexed search is RAM heavy, and is disabled by default in the single docker image. All other environments will have it enabled by default. The size of all your repository working copies is the amount of additional RAM required.
@@ -1033,6 +1226,8 @@ type SiteConfiguration struct {
 	SearchIndexSymbolsEnabled *bool `json:"search.index.symbols.enabled,omitempty"`
 	// SearchLargeFiles description: A list of file glob patterns where matching files will be indexed and searched regardless of their size. The glob pattern syntax can be found here: https://golang.org/pkg/path/filepath/#Match.
 	SearchLargeFiles []string `json:"search.largeFiles,omitempty"`
+	// TrustedProxies description: The IP addresses or CIDR ranges of the load balancers and reverse proxies in front of Sourcegraph. For requests forwarded by them, the IP address of the client is taken from the X-Forwarded-For header, skipping the addresses of trusted proxies from the right. The client IP address is recorded in audit logs and sessions, and used for the IP allowlists of access tokens and for rate limiting sign-in attempts. By default, no proxies are trusted and the address of the connecting client is used.
+	TrustedProxies []string `json:"trustedProxies,omitempty"`
 	// UpdateChannel description: The channel on which to automatically check for Sourcegraph updates.
 	UpdateChannel string `json:"update.channel,omitempty"`
 	// UseJaeger description: DEPRECATED. Use `"observability.tracing": { "sampling": "all" }`, instead. Enables Jaeger tracing.
@@ -44,6 +44,26 @@ const SettingsSchemaJSON = `{
       },
       "group": "Experimental"
     },
+    "campaigns.committer": {
+      "title": "CampaignsCommitter",
+      "description": "The committer identity of the commits of changesets of campaigns in this namespace. When commits are signed with the ` + "`" + `campaigns.signingKey` + "`" + ` site configuration, it should match the identity of the key.",
+      "type": "object",
+      "additionalProperties": false,
+      "required": ["name", "email"],
+      "properties": {
+        "name": {
+          "description": "The name of the committer.",
+          "type": "string",
+          "minLength": 1
+        },
+        "email": {
+          "description": "The email address of the committer.",
+          "type": "string",
+          "format": "email"
+        }
+      },
+      "examples": [{ "name": "Campaigns Bot", "email": "campaigns@example.com" }]
+    },
     "search.savedQueries": {
       "description": "DEPRECATED: Saved search queries",
       "type": "array",
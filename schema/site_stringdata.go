@@ -192,6 +192,27 @@ const SiteSchemaJSON = `{
       "!go": { "pointer": true },
       "group": "Campaigns"
     },
+    "campaigns.signingKey": {
+      "title": "CampaignsSigningKey",
+      "description": "The key gitserver signs the commits of campaign changesets with, so that code hosts show them as verified. The key must not be protected with a passphrase. Its committer identity is set with the ` + "`" + `campaigns.committer` + "`" + ` setting of the campaign's namespace.",
+      "type": "object",
+      "additionalProperties": false,
+      "required": ["type", "encryptedKey"],
+      "properties": {
+        "type": {
+          "description": "The type of the key.",
+          "type": "string",
+          "enum": ["gpg", "ssh"]
+        },
+        "encryptedKey": {
+          "description": "The ASCII-armored GPG secret key or the OpenSSH private key, encrypted with the ` + "`" + `encryptCampaignsSigningKey` + "`" + ` GraphQL mutation. Requires the ` + "`" + `SRC_SECRET_KEY` + "`" + ` environment variable to be set to the same value on frontend and gitserver.",
+          "type": "string",
+          "minLength": 1
+        }
+      },
+      "examples": [{ "type": "gpg", "encryptedKey": "<output of encryptCampaignsSigningKey>" }],
+      "group": "Campaigns"
+    },
     "corsOrigin": {
       "description": "Required when using any of the native code host integrations for Phabricator, GitLab, or Bitbucket Server. It is a space-separated list of allowed origins for cross-origin HTTP requests which should be the base URL for your Phabricator, GitLab, or Bitbucket Server instance.",
       "type": "string",
@@ -268,6 +289,14 @@ const SiteSchemaJSON = `{
       "default": 1,
       "group": "External services"
     },
+    "repoListFullSyncInterval": {
+      "description": "Minimum interval (in minutes) between full listings of the repositories of code hosts. In between, code hosts that support it (GitHub and GitLab) are only asked for the repositories updated since the previous check, which uses much less of their API rate limits. Repositories removed from a code host are only removed from Sourcegraph by a full listing. If 0, every check is a full listing.",
+      "type": "integer",
+      "minimum": 0,
+      "default": 0,
+      "examples": [60],
+      "group": "External services"
+    },
     "maxReposToSearch": {
       "description": "The maximum number of repositories to search across. The user is prompted to narrow their query if exceeded. Any value less than or equal to zero means unlimited.",
       "type": "integer",
@@ -309,13 +338,52 @@ const SiteSchemaJSON = `{
       ],
       "group": "Security"
     },
+    "auditLog": {
+      "description": "Settings for the audit log, which records security-relevant actions such as site configuration changes, site admin promotions, access token and external service changes and requests made with sudo access tokens.",
+      "type": "object",
+      "additionalProperties": false,
+      "properties": {
+        "retentionDays": {
+          "description": "The number of days that audit log entries are kept for. Older entries are deleted. If 0 or unset, entries are never deleted.",
+          "type": "integer",
+          "minimum": 0
+        },
+        "export": {
+          "description": "Streams new audit log entries as JSON lines (one JSON object per line) to a file and/or an HTTP endpoint, e.g. for ingestion into a SIEM.",
+          "type": "object",
+          "additionalProperties": false,
+          "properties": {
+            "file": {
+              "description": "The path of a file that new entries are appended to. The file is created if it does not exist.",
+              "type": "string"
+            },
+            "url": {
+              "description": "The URL of an HTTP endpoint that new entries are sent to in batches, as the body of POST requests with the content type \"application/x-ndjson\".",
+              "type": "string",
+              "format": "uri",
+              "pattern": "^https?://"
+            },
+            "authorizationHeader": {
+              "description": "The value of the Authorization header of the requests sent to ` + "`" + `url` + "`" + `, if any.",
+              "type": "string"
+            }
+          }
+        }
+      },
+      "examples": [
+        { "retentionDays": 365 },
+        { "retentionDays": 90, "export": { "url": "https://siem.example.com/ingest", "authorizationHeader": "Bearer TOKEN" } },
+        { "export": { "file": "/var/log/sourcegraph/audit.log" } }
+      ],
+      "group": "Security"
+    },
     "permissions.userMapping": {
-      "description": "Settings for Sourcegraph permissions, which allow the site admin to explicitly manage repository permissions via the GraphQL API. This setting cannot be enabled if repository permissions for any specific external service are enabled (i.e., when the external service's ` + "`" + `authorization` + "`" + ` field is set).",
+      "description": "Settings for Sourcegraph permissions, which allow the site admin to explicitly manage repository permissions via the GraphQL API. Explicit permissions apply to the repositories of external services without repository permissions (i.e., without an ` + "`" + `authorization` + "`" + ` field), such as Gitolite, Phabricator, AWS CodeCommit and other Git hosts. Repositories of external services with repository permissions keep being authorized by their code host.",
       "type": "object",
       "additionalProperties": false,
       "properties": {
         "enabled": {
-          "description": "Whether permissions user mapping is enabled. There must be no ` + "`" + `authorization` + "`" + ` field in any external service configuration before enabling this.",
+          "description": "Whether permissions user mapping is enabled.",
           "type": "boolean",
           "default": false
         },
@@ -573,6 +641,15 @@ const SiteSchemaJSON = `{
       "type": "string",
       "examples": ["https://sourcegraph.example.com"]
     },
+    "trustedProxies": {
+      "description": "The IP addresses or CIDR ranges of the load balancers and reverse proxies in front of Sourcegraph. For requests forwarded by them, the IP address of the client is taken from the X-Forwarded-For header, skipping the addresses of trusted proxies from the right. The client IP address is recorded in audit logs and sessions, and used for the IP allowlists of access tokens and for rate limiting sign-in attempts. By default, no proxies are trusted and the address of the connecting client is used.",
+      "type": "array",
+      "items": {
+        "type": "string"
+      },
+      "examples": [["10.0.0.0/8", "172.16.0.1"]],
+      "group": "Security"
+    },
     "lightstepAccessToken": {
       "description": "DEPRECATED. Use Jaeger (` + "`" + `\"observability.tracing\": { \"sampling\": \"selective\" }` + "`" + `), instead.",
       "type": "string",
@@ -639,7 +716,7 @@ const SiteSchemaJSON = `{
         "properties": {
           "type": {
             "type": "string",
-            "enum": ["builtin", "saml", "openidconnect", "http-header", "github", "gitlab"]
+            "enum": ["builtin", "saml", "openidconnect", "http-header", "ldap", "github", "gitlab"]
           }
         },
         "oneOf": [
@@ -647,6 +724,7 @@ const SiteSchemaJSON = `{
           { "$ref": "#/definitions/SAMLAuthProvider" },
           { "$ref": "#/definitions/OpenIDConnectAuthProvider" },
           { "$ref": "#/definitions/HTTPHeaderAuthProvider" },
+          { "$ref": "#/definitions/LDAPAuthProvider" },
           { "$ref": "#/definitions/GitHubAuthProvider" },
           { "$ref": "#/definitions/GitLabAuthProvider" }
         ],
@@ -665,11 +743,17 @@ const SiteSchemaJSON = `{
     },
     "auth.sessionExpiry": {
       "type": "string",
-      "description": "The duration of a user session, after which it expires and the user is required to re-authenticate. The default is 90 days. There is typically no need to set this, but some users may have specific internal security requirements.\n\nThe string format is that of the Duration type in the Go time package (https://golang.org/pkg/time/#ParseDuration). E.g., \"720h\", \"43200m\", \"2592000s\" all indicate a timespan of 30 days.\n\nNote: changing this field does not affect the expiration of existing sessions. If you would like to enforce this limit for existing sessions, you must log out currently signed-in users. You can force this by removing all keys beginning with \"session_\" from the Redis store:\n\n* For deployments using ` + "`" + `sourcegraph/server` + "`" + `: ` + "`" + `docker exec $CONTAINER_ID redis-cli --raw keys 'session_*' | xargs docker exec $CONTAINER_ID redis-cli del` + "`" + `\n* For cluster deployments: \n  ` + "`" + `` + "`" + `` + "`" + `\n  REDIS_POD=\"$(kubectl get pods -l app=redis-store -o jsonpath={.items[0].metadata.name})\";\n  kubectl exec \"$REDIS_POD\" -- redis-cli --raw keys 'session_*' | xargs kubectl exec \"$REDIS_POD\" -- redis-cli --raw del;\n  ` + "`" + `` + "`" + `` + "`" + `\n",
+      "description": "The duration of inactivity after which a user session expires and the user is required to re-authenticate. The default is 90 days. To also limit the duration of sessions of active users, see auth.sessionAbsoluteExpiry. There is typically no need to set this, but some users may have specific internal security requirements.\n\nThe string format is that of the Duration type in the Go time package (https://golang.org/pkg/time/#ParseDuration). E.g., \"720h\", \"43200m\", \"2592000s\" all indicate a timespan of 30 days.\n\nNote: changing this field does not affect the expiration of existing sessions. If you would like to enforce this limit for existing sessions, you must log out currently signed-in users, e.g. by revoking their sessions with the revokeAllSessions GraphQL mutation or by removing all keys beginning with \"session_\" from the Redis store:\n\n* For deployments using ` + "`" + `sourcegraph/server` + "`" + `: ` + "`" + `docker exec $CONTAINER_ID redis-cli --raw keys 'session_*' | xargs docker exec $CONTAINER_ID redis-cli del` + "`" + `\n* For cluster deployments: \n  ` + "`" + `` + "`" + `` + "`" + `\n  REDIS_POD=\"$(kubectl get pods -l app=redis-store -o jsonpath={.items[0].metadata.name})\";\n  kubectl exec \"$REDIS_POD\" -- redis-cli --raw keys 'session_*' | xargs kubectl exec \"$REDIS_POD\" -- redis-cli --raw del;\n  ` + "`" + `` + "`" + `` + "`" + `\n",
       "default": "2160h",
       "examples": ["168h"],
       "group": "Authentication"
     },
+    "auth.sessionAbsoluteExpiry": {
+      "type": "string",
+      "description": "The maximum duration of a user session since the user signed in, after which it expires (even if the user was active) and the user is required to re-authenticate. By default, sessions only expire after a period of inactivity (see auth.sessionExpiry).\n\nThe string format is that of the Duration type in the Go time package (https://golang.org/pkg/time/#ParseDuration). E.g., \"720h\" indicates a timespan of 30 days.\n\nChanging this field also affects existing sessions.",
+      "examples": ["720h"],
+      "group": "Authentication"
+    },
     "auth.enableUsernameChanges": {
       "description": "Enables users to change their username after account creation. Warning: setting this to be true has security implications if you have enabled (or will at any point in the future enable) repository permissions with an option that relies on username equivalency between Sourcegraph and an external service or authentication provider. Do NOT set this to true if you are using non-built-in authentication OR rely on username equivalency for repository permissions.",
       "type": "boolean",
@@ -721,6 +805,30 @@ const SiteSchemaJSON = `{
           "description": "Allows new visitors to sign up for accounts. The sign-up page will be enabled and accessible to all visitors.\n\nSECURITY: If the site has no users (i.e., during initial setup), it will always allow the first user to sign up and become site admin **without any approval** (first user to sign up becomes the admin).",
           "type": "boolean",
           "default": false
+        },
+        "requireMFA": {
+          "description": "Requires users to sign in with a second authentication factor (an authenticator app or a security key) in addition to their password. Users who haven't set up a second factor are asked to set up an authenticator app when they next sign in.\n\n- \"none\": a second factor is optional\n- \"siteAdmins\": site admins must use a second factor\n- \"everyone\": all users must use a second factor",
+          "type": "string",
+          "enum": ["none", "siteAdmins", "everyone"],
+          "default": "none"
+        },
+        "lockout": {
+          "description": "Locks accounts after too many consecutive sign-in attempts with the correct password but a wrong second factor, to prevent second factors from being guessed. Attempts with a wrong password are only rate limited, so that nobody can lock other users out. A locked account can't be signed in to until the lockout expires or the user resets their password.",
+          "type": "object",
+          "title": "BuiltinAuthLockout",
+          "additionalProperties": false,
+          "properties": {
+            "failedAttempts": {
+              "description": "The number of consecutive sign-in attempts with a wrong second factor after which the account is locked. Any value less than zero means accounts are never locked.",
+              "type": "integer",
+              "default": 10
+            },
+            "duration": {
+              "description": "How long the account stays locked (e.g., \"15m\" or \"1h\").",
+              "type": "string",
+              "default": "15m"
+            }
+          }
         }
       }
     },
@@ -759,6 +867,17 @@ const SiteSchemaJSON = `{
           "description": "Only allow users to authenticate if their email domain is equal to this value (example: mycompany.com). Do not include a leading \"@\". If not set, all users on this OpenID Connect provider can authenticate to Sourcegraph.",
           "type": "string",
           "pattern": "^[^<@]"
+        },
+        "groupsClaimName": {
+          "description": "The name of the claim of the ID token or userinfo response that lists the groups of the user, which are mapped by ` + "`" + `groupMappings` + "`" + `.",
+          "type": "string",
+          "default": "groups",
+          "examples": ["groups", "roles"]
+        },
+        "groupMappings": {
+          "description": "Maps groups of the user (listed in the ` + "`" + `groupsClaimName` + "`" + ` claim) to organization memberships and repository permissions. Group mappings are re-evaluated each time the user signs in.",
+          "type": "array",
+          "items": { "$ref": "#/definitions/AuthGroupMapping" }
         }
       }
     },
@@ -834,6 +953,17 @@ const SiteSchemaJSON = `{
           "description": "Whether the Service Provider should (insecurely) accept assertions from the Identity Provider without a valid signature.",
           "type": "boolean",
           "default": false
+        },
+        "groupsAttributeName": {
+          "description": "The name of the SAML assertion attribute that lists the groups of the user, which are mapped by ` + "`" + `groupMappings` + "`" + `.",
+          "type": "string",
+          "default": "groups",
+          "examples": ["groups", "memberOf", "http://schemas.microsoft.com/ws/2008/06/identity/claims/groups"]
+        },
+        "groupMappings": {
+          "description": "Maps groups of the user (listed in the ` + "`" + `groupsAttributeName` + "`" + ` attribute) to organization memberships and repository permissions. Group mappings are re-evaluated each time the user signs in.",
+          "type": "array",
+          "items": { "$ref": "#/definitions/AuthGroupMapping" }
         }
       }
     },
@@ -859,6 +989,82 @@ const SiteSchemaJSON = `{
         }
       }
     },
+    "LDAPAuthProvider": {
+      "description": "Configures the LDAP authentication provider, which authenticates users with their username and password in an LDAP directory (such as OpenLDAP or Active Directory). Sourcegraph binds to the directory with the configured service account, searches for the user's entry, and then binds as the user to check their password.",
+      "type": "object",
+      "additionalProperties": false,
+      "required": ["type", "url", "userSearchBaseDN"],
+      "properties": {
+        "type": {
+          "type": "string",
+          "const": "ldap"
+        },
+        "displayName": {
+          "description": "The name to use when displaying this authentication provider in the UI. Defaults to \"LDAP\".",
+          "type": "string"
+        },
+        "url": {
+          "description": "URL of the LDAP server. Use the ldaps scheme to connect with TLS, or the ldap scheme with ` + "`" + `startTLS` + "`" + ` to upgrade the connection to TLS.",
+          "type": "string",
+          "pattern": "^ldaps?://",
+          "examples": ["ldaps://ldap.example.com", "ldap://ldap.example.com:389"]
+        },
+        "startTLS": {
+          "description": "Upgrade connections to an ldap:// URL to TLS with the StartTLS operation before sending any credentials.",
+          "type": "boolean",
+          "default": false
+        },
+        "certificate": {
+          "description": "TLS certificate of the LDAP server (or of the CA that signed it). This is only necessary if the certificate is self-signed or signed by an internal CA. To get the certificate run ` + "`" + `openssl s_client -connect HOST:636 -showcerts < /dev/null 2> /dev/null | openssl x509 -outform PEM` + "`" + `. To escape the value into a JSON string, you may want to use a tool like https://json-escape-text.now.sh.",
+          "type": "string",
+          "pattern": "^-----BEGIN CERTIFICATE-----\n",
+          "examples": ["-----BEGIN CERTIFICATE-----\n..."]
+        },
+        "bindDN": {
+          "description": "The DN of the service account that Sourcegraph binds as to search the directory. If empty, searches are performed anonymously.",
+          "type": "string",
+          "examples": ["cn=sourcegraph,ou=services,dc=example,dc=com"]
+        },
+        "bindPassword": {
+          "description": "The password of the service account (` + "`" + `bindDN` + "`" + `).",
+          "type": "string"
+        },
+        "userSearchBaseDN": {
+          "description": "The DN of the subtree of the directory that contains the entries of users.",
+          "type": "string",
+          "examples": ["ou=people,dc=example,dc=com"]
+        },
+        "userSearchFilter": {
+          "description": "The filter used to find the entry of a user signing in. ` + "`" + `%s` + "`" + ` is replaced with the (escaped) username that the user entered. The filter must match exactly one entry; it can also restrict who may sign in (e.g., to the members of a group).",
+          "type": "string",
+          "default": "(uid=%s)",
+          "examples": ["(&(objectClass=inetOrgPerson)(uid=%s))", "(sAMAccountName=%s)"]
+        },
+        "attributes": {
+          "description": "The attributes of user entries that are used for the Sourcegraph user's profile.",
+          "type": "object",
+          "title": "LDAPAttributes",
+          "additionalProperties": false,
+          "properties": {
+            "username": {
+              "description": "The attribute that holds the username.",
+              "type": "string",
+              "default": "uid"
+            },
+            "email": {
+              "description": "The attribute that holds the email address. The email address is considered verified.",
+              "type": "string",
+              "default": "mail"
+            },
+            "displayName": {
+              "description": "The attribute that holds the display name.",
+              "type": "string",
+              "default": "cn"
+            }
+          }
+        }
+      }
+    },
     "GitHubAuthProvider": {
       "description": "Configures the GitHub (or GitHub Enterprise) OAuth authentication provider for SSO. In addition to specifying this configuration object, you must also create a OAuth App on your GitHub instance: https://developer.github.com/apps/building-oauth-apps/creating-an-oauth-app/. When a user signs into Sourcegraph or links their GitHub account to their existing Sourcegraph account, GitHub will prompt the user for the repo scope.",
       "type": "object",
@@ -935,6 +1141,30 @@ const SiteSchemaJSON = `{
           "type": "string"
         }
       }
+    },
+    "AuthGroupMapping": {
+      "description": "Maps a group of the authentication provider to organization memberships and repository permissions. Users in the group are added to its organizations when they sign in. Memberships that were added this way are removed when the user signs in after leaving the group.",
+      "type": "object",
+      "additionalProperties": false,
+      "required": ["group"],
+      "properties": {
+        "group": {
+          "description": "The name of the group, as listed by the authentication provider.",
+          "type": "string",
+          "minLength": 1
+        },
+        "organizations": {
+          "description": "The names of the organizations that users in the group are members of.",
+          "type": "array",
+          "items": { "type": "string" }
+        },
+        "repositories": {
+          "description": "Regular expressions matching the entire names of the repositories that users in the group can read, in addition to the repositories they can read on the code host. They must be valid both as Go and as PostgreSQL regular expressions. Only enforced when repository permissions are stored by Sourcegraph, i.e. when ` + "`" + `permissions.backgroundSync` + "`" + ` or ` + "`" + `permissions.userMapping` + "`" + ` is enabled.",
+          "type": "array",
+          "items": { "type": "string", "format": "regex" },
+          "examples": [["github\\.com/acme/.*"]]
+        }
+      }
     }
   }
 }
@@ -35,6 +35,8 @@ export const SignInPage: React.FunctionComponent<SignInPageProps> = props => {
                             {window.context.authProviders.map((provider, i) =>
                                 provider.isBuiltin ? (
                                     <UsernamePasswordSignInForm key={i} {...props} />
+                                ) : provider.serviceType === 'ldap' ? (
+                                    <UsernamePasswordSignInForm key={i} {...props} ldapProvider={provider} />
                                 ) : (
                                     <div className="mb-2">
                                         <a key={i} href={provider.authenticationURL} className="btn btn-secondary">
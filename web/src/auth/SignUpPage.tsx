@@ -67,7 +67,13 @@ export class SignUpPage extends React.Component<SignUpPageProps> {
             if (resp.status !== 200) {
                 return resp.text().then(text => Promise.reject(new Error(text)))
             }
-            window.location.replace(getReturnTo(this.props.location))
-            return Promise.resolve()
+            return resp.text().then(text => {
+                if (text && (JSON.parse(text) as { mfaEnrollmentRequired?: boolean }).mfaEnrollmentRequired) {
+                    // The user must set up two-factor authentication while signing in.
+                    window.location.replace(`/sign-in${this.props.location.search}`)
+                    return
+                }
+                window.location.replace(getReturnTo(this.props.location))
+            })
         })
 }
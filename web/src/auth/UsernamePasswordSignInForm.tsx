@@ -7,10 +7,42 @@ import { eventLogger } from '../tracking/eventLogger'
 import { getReturnTo, PasswordInput } from './SignInSignUpCommon'
 import { ErrorAlert } from '../components/alerts'
 import { asError } from '../../../shared/src/util/errors'
+import { getAssertion, isWebAuthnSupported } from './webauthn'
 
 interface Props {
     location: H.Location
     history: H.History
+
+    /**
+     * An LDAP auth provider to sign in with. If not set, the user signs in with the builtin
+     * username-password auth provider.
+     */
+    ldapProvider?: { displayName: string; authenticationURL?: string }
+}
+
+/** The JSON response of the sign-in endpoint of the builtin auth provider. */
+interface SignInResponse {
+    /** Set if the user must also provide a second factor. */
+    mfaRequired?: boolean
+    /** Set if the user can provide a code from their authenticator app. */
+    totp?: boolean
+    /** The options for signing in with one of the user's security keys, if any. */
+    webAuthn?: object
+
+    /** Set if the user must set up an authenticator app before signing in. */
+    mfaEnrollmentRequired?: boolean
+    totpSecret?: string
+    totpURL?: string
+
+    /** The user's new recovery codes, after setting up an authenticator app while signing in. */
+    recoveryCodes?: string[]
+}
+
+/** The second authentication factor sent along with the user's credentials. */
+interface SecondFactor {
+    totpCode?: string
+    recoveryCode?: string
+    webAuthn?: object
 }
 
 interface State {
@@ -18,10 +50,18 @@ interface State {
     password: string
     error?: Error
     loading: boolean
+
+    /** The response of the previous sign-in attempt that asked for a second factor, if any. */
+    mfa?: SignInResponse
+    /** The code from the user's authenticator app or one of the user's recovery codes. */
+    code: string
+    /** The recovery codes to show to the user before continuing. */
+    recoveryCodes?: string[]
 }
 
 /**
- * The form for signing in with a username and password.
+ * The form for signing in with a username and password (of a builtin account or of an account in an
+ * LDAP directory).
  */
 export class UsernamePasswordSignInForm extends React.Component<Props, State> {
     constructor(props: Props) {
@@ -30,13 +70,22 @@ export class UsernamePasswordSignInForm extends React.Component<Props, State> {
             email: '',
             password: '',
             loading: false,
+            code: '',
         }
     }
 
     public render(): JSX.Element | null {
+        if (this.state.recoveryCodes) {
+            return this.renderRecoveryCodes(this.state.recoveryCodes)
+        }
+        if (this.state.mfa) {
+            return this.renderSecondFactor(this.state.mfa)
+        }
         return (
             <Form className="signin-signup-form signin-form e2e-signin-form" onSubmit={this.handleSubmit}>
-                {window.context.allowSignup ? (
+                {this.props.ldapProvider ? (
+                    <p>Sign in with your {this.props.ldapProvider.displayName} account.</p>
+                ) : window.context.allowSignup ? (
                     <p>
                         <Link to={`/sign-up${this.props.location.search}`}>Don't have an account? Sign up.</Link>
                     </p>
@@ -48,7 +97,7 @@ export class UsernamePasswordSignInForm extends React.Component<Props, State> {
                     <input
                         className="form-control signin-signup-form__input"
                         type="text"
-                        placeholder="Username or email"
+                        placeholder={this.props.ldapProvider ? 'Username' : 'Username or email'}
                         onChange={this.onEmailFieldChange}
                         required={true}
                         value={this.state.email}
@@ -72,7 +121,7 @@ export class UsernamePasswordSignInForm extends React.Component<Props, State> {
                     <button className="btn btn-primary btn-block" type="submit" disabled={this.state.loading}>
                         Sign in
                     </button>
-                    {window.context.resetPasswordEnabled && (
+                    {window.context.resetPasswordEnabled && !this.props.ldapProvider && (
                         <small className="form-text text-muted">
                             <Link to="/password-reset">Forgot password?</Link>
                         </small>
@@ -87,6 +136,89 @@ export class UsernamePasswordSignInForm extends React.Component<Props, State> {
         )
     }
 
+    private renderSecondFactor(mfa: SignInResponse): JSX.Element {
+        return (
+            <Form className="signin-signup-form signin-form e2e-signin-mfa-form" onSubmit={this.handleCodeSubmit}>
+                {mfa.mfaEnrollmentRequired ? (
+                    <>
+                        <p>
+                            Two-factor authentication is required. Add this secret to an authenticator app and enter
+                            the code it shows.
+                        </p>
+                        <p>
+                            <code className="user-select-all">{mfa.totpSecret}</code>
+                        </p>
+                        {mfa.totpURL && (
+                            <p>
+                                <small>
+                                    Or <a href={mfa.totpURL}>open it in your authenticator app</a>.
+                                </small>
+                            </p>
+                        )}
+                    </>
+                ) : (
+                    <p>
+                        {mfa.totp
+                            ? 'Enter the code from your authenticator app or one of your recovery codes.'
+                            : 'Use your security key or enter one of your recovery codes.'}
+                    </p>
+                )}
+                {this.state.error && <ErrorAlert className="my-2" error={this.state.error} icon={false} />}
+                <div className="form-group">
+                    <input
+                        className="form-control signin-signup-form__input"
+                        type="text"
+                        placeholder={
+                            mfa.mfaEnrollmentRequired ? 'Code' : mfa.totp ? 'Code or recovery code' : 'Recovery code'
+                        }
+                        onChange={this.onCodeFieldChange}
+                        required={true}
+                        value={this.state.code}
+                        disabled={this.state.loading}
+                        autoCapitalize="off"
+                        autoFocus={true}
+                        autoComplete="one-time-code"
+                    />
+                </div>
+                <div className="form-group">
+                    <button className="btn btn-primary btn-block" type="submit" disabled={this.state.loading}>
+                        Verify
+                    </button>
+                    {mfa.webAuthn && isWebAuthnSupported() && (
+                        <button
+                            className="btn btn-secondary btn-block"
+                            type="button"
+                            onClick={this.onSecurityKeyClick}
+                            disabled={this.state.loading}
+                        >
+                            Use security key
+                        </button>
+                    )}
+                </div>
+                {this.state.loading && (
+                    <div className="w-100 text-center mb-2">
+                        <LoadingSpinner className="icon-inline" />
+                    </div>
+                )}
+            </Form>
+        )
+    }
+
+    private renderRecoveryCodes(recoveryCodes: string[]): JSX.Element {
+        return (
+            <div className="signin-signup-form signin-form">
+                <p>
+                    Save these recovery codes somewhere safe. Each of them can be used once to sign in if you lose
+                    access to your authenticator app. They won't be shown again.
+                </p>
+                <pre className="user-select-all">{recoveryCodes.join('\n')}</pre>
+                <button className="btn btn-primary btn-block" type="button" onClick={this.redirect}>
+                    Continue
+                </button>
+            </div>
+        )
+    }
+
     private onEmailFieldChange = (e: React.ChangeEvent<HTMLInputElement>): void => {
         this.setState({ email: e.target.value })
     }
@@ -95,6 +227,10 @@ export class UsernamePasswordSignInForm extends React.Component<Props, State> {
         this.setState({ password: e.target.value })
     }
 
+    private onCodeFieldChange = (e: React.ChangeEvent<HTMLInputElement>): void => {
+        this.setState({ code: e.target.value })
+    }
+
     private handleSubmit = (event: React.FormEvent<HTMLFormElement>): void => {
         event.preventDefault()
         if (this.state.loading) {
@@ -103,7 +239,39 @@ export class UsernamePasswordSignInForm extends React.Component<Props, State> {
 
         this.setState({ loading: true })
         eventLogger.log('InitiateSignIn')
-        fetch('/-/sign-in', {
+        this.signIn()
+    }
+
+    private handleCodeSubmit = (event: React.FormEvent<HTMLFormElement>): void => {
+        event.preventDefault()
+        if (this.state.loading || !this.state.mfa) {
+            return
+        }
+
+        this.setState({ loading: true })
+        // Codes from authenticator apps are all digits, unlike recovery codes.
+        const code = this.state.code.replace(/\s/g, '')
+        this.signIn(
+            this.state.mfa.mfaEnrollmentRequired || /^\d+$/.test(code) ? { totpCode: code } : { recoveryCode: code }
+        )
+    }
+
+    private onSecurityKeyClick = (): void => {
+        const { mfa } = this.state
+        if (this.state.loading || !mfa || !mfa.webAuthn) {
+            return
+        }
+
+        this.setState({ loading: true })
+        getAssertion(mfa.webAuthn).then(
+            webAuthn => this.signIn({ webAuthn }),
+            error => this.setState({ loading: false, error: asError(error) })
+        )
+    }
+
+    private signIn(secondFactor?: SecondFactor): void {
+        const { ldapProvider } = this.props
+        fetch((ldapProvider && ldapProvider.authenticationURL) || '/-/sign-in', {
             credentials: 'same-origin',
             method: 'POST',
             headers: {
@@ -111,21 +279,30 @@ export class UsernamePasswordSignInForm extends React.Component<Props, State> {
                 Accept: 'application/json',
                 'Content-Type': 'application/json',
             },
-            body: JSON.stringify({
-                email: this.state.email,
-                password: this.state.password,
-            }),
+            body: JSON.stringify(
+                ldapProvider
+                    ? { username: this.state.email, password: this.state.password }
+                    : { email: this.state.email, password: this.state.password, ...secondFactor }
+            ),
         })
-            .then(resp => {
+            .then(async resp => {
                 if (resp.status === 200) {
-                    if (new URLSearchParams(this.props.location.search).get('close') === 'true') {
-                        window.close()
+                    // The response is empty if the user is signed in without further steps.
+                    const body = await resp.text()
+                    const response: SignInResponse = body ? JSON.parse(body) : {}
+                    if (response.mfaRequired || response.mfaEnrollmentRequired) {
+                        this.setState({ loading: false, error: undefined, mfa: response, code: '' })
+                    } else if (response.recoveryCodes) {
+                        this.setState({ loading: false, recoveryCodes: response.recoveryCodes })
                     } else {
-                        const returnTo = getReturnTo(this.props.location)
-                        window.location.replace(returnTo)
+                        this.redirect()
                     }
                 } else if (resp.status === 401) {
-                    throw new Error('User or password was incorrect')
+                    throw new Error(
+                        secondFactor ? 'The code or security key was not accepted' : 'User or password was incorrect'
+                    )
+                } else if (resp.status === 429) {
+                    throw new Error(await resp.text())
                 } else {
                     throw new Error('Unknown Error')
                 }
@@ -135,4 +312,13 @@ export class UsernamePasswordSignInForm extends React.Component<Props, State> {
                 this.setState({ loading: false, error: asError(error) })
             })
     }
+
+    private redirect = (): void => {
+        if (new URLSearchParams(this.props.location.search).get('close') === 'true') {
+            window.close()
+        } else {
+            const returnTo = getReturnTo(this.props.location)
+            window.location.replace(returnTo)
+        }
+    }
 }
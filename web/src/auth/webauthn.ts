@@ -0,0 +1,94 @@
+/**
+ * Helpers for WebAuthn security keys. The server encodes binary values (such as challenges and
+ * credential IDs) as base64 or base64url strings in JSON, but the browser's WebAuthn API uses
+ * ArrayBuffers.
+ */
+
+function decode(value: string): ArrayBuffer {
+    const base64 = value.replace(/-/g, '+').replace(/_/g, '/')
+    const binary = atob(base64 + '='.repeat((4 - (base64.length % 4)) % 4))
+    const bytes = new Uint8Array(binary.length)
+    for (let i = 0; i < binary.length; i++) {
+        bytes[i] = binary.charCodeAt(i)
+    }
+    return bytes.buffer
+}
+
+function encode(value: ArrayBuffer): string {
+    let binary = ''
+    for (const byte of new Uint8Array(value)) {
+        binary += String.fromCharCode(byte)
+    }
+    return btoa(binary).replace(/\+/g, '-').replace(/\//g, '_').replace(/=+$/, '')
+}
+
+interface CredentialDescriptorJSON {
+    type: 'public-key'
+    id: string
+}
+
+function decodeDescriptors(descriptors: CredentialDescriptorJSON[] = []): PublicKeyCredentialDescriptor[] {
+    return descriptors.map(descriptor => ({ ...descriptor, id: decode(descriptor.id) }))
+}
+
+/** Reports whether the browser supports security keys. */
+export function isWebAuthnSupported(): boolean {
+    return typeof window.PublicKeyCredential !== 'undefined' && !!navigator.credentials
+}
+
+/**
+ * Registers a new security key, given the options from the beginSecurityKeyRegistration mutation. It
+ * returns the credential to pass to the finishSecurityKeyRegistration mutation.
+ */
+export async function createCredential(options: any): Promise<object> {
+    const credential = (await navigator.credentials.create({
+        publicKey: {
+            ...options,
+            challenge: decode(options.challenge),
+            user: { ...options.user, id: decode(options.user.id) },
+            excludeCredentials: decodeDescriptors(options.excludeCredentials),
+        },
+    })) as PublicKeyCredential | null
+    if (!credential) {
+        throw new Error('No security key was registered.')
+    }
+    const response = credential.response as AuthenticatorAttestationResponse
+    return {
+        id: credential.id,
+        rawId: encode(credential.rawId),
+        type: credential.type,
+        response: {
+            clientDataJSON: encode(response.clientDataJSON),
+            attestationObject: encode(response.attestationObject),
+        },
+    }
+}
+
+/**
+ * Signs in with one of the user's security keys, given the options from the sign-in response. It
+ * returns the assertion to send as the user's second factor.
+ */
+export async function getAssertion(options: any): Promise<object> {
+    const credential = (await navigator.credentials.get({
+        publicKey: {
+            ...options,
+            challenge: decode(options.challenge),
+            allowCredentials: decodeDescriptors(options.allowCredentials),
+        },
+    })) as PublicKeyCredential | null
+    if (!credential) {
+        throw new Error('No security key was used.')
+    }
+    const response = credential.response as AuthenticatorAssertionResponse
+    return {
+        id: credential.id,
+        rawId: encode(credential.rawId),
+        type: credential.type,
+        response: {
+            clientDataJSON: encode(response.clientDataJSON),
+            authenticatorData: encode(response.authenticatorData),
+            signature: encode(response.signature),
+            userHandle: response.userHandle ? encode(response.userHandle) : undefined,
+        },
+    }
+}
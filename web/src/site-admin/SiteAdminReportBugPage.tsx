@@ -8,7 +8,9 @@ import bitbucketServerSchemaJSON from '../../../schema/bitbucket_server.schema.j
 import githubSchemaJSON from '../../../schema/github.schema.json'
 import gitlabSchemaJSON from '../../../schema/gitlab.schema.json'
 import gitoliteSchemaJSON from '../../../schema/gitolite.schema.json'
+import manifestSchemaJSON from '../../../schema/manifest.schema.json'
 import otherExternalServiceSchemaJSON from '../../../schema/other_external_service.schema.json'
+import perforceSchemaJSON from '../../../schema/perforce.schema.json'
 import phabricatorSchemaJSON from '../../../schema/phabricator.schema.json'
 import settingsSchemaJSON from '../../../schema/settings.schema.json'
 import siteSchemaJSON from '../../../schema/site.schema.json'
@@ -33,7 +35,9 @@ const externalServices: Record<ExternalServiceKind, JSONSchema> = {
     GITHUB: githubSchemaJSON,
     GITLAB: gitlabSchemaJSON,
     GITOLITE: gitoliteSchemaJSON,
+    MANIFEST: manifestSchemaJSON,
     OTHER: otherExternalServiceSchemaJSON,
+    PERFORCE: perforceSchemaJSON,
     PHABRICATOR: phabricatorSchemaJSON,
 }
 
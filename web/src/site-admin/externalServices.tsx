@@ -12,7 +12,9 @@ import bitbucketServerSchemaJSON from '../../../schema/bitbucket_server.schema.j
 import githubSchemaJSON from '../../../schema/github.schema.json'
 import gitlabSchemaJSON from '../../../schema/gitlab.schema.json'
 import gitoliteSchemaJSON from '../../../schema/gitolite.schema.json'
+import manifestSchemaJSON from '../../../schema/manifest.schema.json'
 import otherExternalServiceSchemaJSON from '../../../schema/other_external_service.schema.json'
+import perforceSchemaJSON from '../../../schema/perforce.schema.json'
 import phabricatorSchemaJSON from '../../../schema/phabricator.schema.json'
 import { PhabricatorIcon } from '../../../shared/src/components/icons'
 import * as GQL from '../../../shared/src/graphql/schema'
@@ -941,6 +943,75 @@ const GITOLITE: AddExternalServiceOptions = {
         },
     ],
 }
+const MANIFEST: AddExternalServiceOptions = {
+    kind: GQL.ExternalServiceKind.MANIFEST,
+    title: 'Repository manifest',
+    icon: GitIcon,
+    shortDescription: 'Sync the repositories listed in a JSON or YAML manifest served over HTTP(S) or read from a file.',
+    jsonSchema: manifestSchemaJSON,
+    defaultDisplayName: 'Repository manifest',
+    defaultConfig: `{
+  "url": "https://catalog.example.com/sourcegraph/manifest.yaml"
+}`,
+    instructions: (
+        <div>
+            <ol>
+                <li>
+                    In the configuration below, set <Field>url</Field> to the location of your manifest. It can be an
+                    HTTP(S) URL (use <Field>token</Field> if it requires authentication) or the path of a file on the
+                    repo-updater host.
+                </li>
+                <li>
+                    The manifest must contain a <code>repos</code> list. Each entry requires a <code>name</code> and a{' '}
+                    <code>cloneURL</code>, and may set <code>description</code>, <code>visibility</code>,{' '}
+                    <code>fork</code>, <code>archived</code> and key/value <code>metadata</code>.
+                </li>
+            </ol>
+            <p>The manifest is fetched again on every repository sync, so changes to it are picked up automatically.</p>
+        </div>
+    ),
+    editorActions: [],
+}
+const PERFORCE: AddExternalServiceOptions = {
+    kind: GQL.ExternalServiceKind.PERFORCE,
+    title: 'Perforce',
+    icon: GitIcon,
+    jsonSchema: perforceSchemaJSON,
+    defaultDisplayName: 'Perforce',
+    defaultConfig: `{
+  "p4port": "perforce.example.com:1666",
+  "p4user": "<user>",
+  "p4passwd": "<password or ticket>",
+  "depots": []
+}`,
+    instructions: (
+        <div>
+            <ol>
+                <li>
+                    In the configuration below, set <Field>p4port</Field>, <Field>p4user</Field> and{' '}
+                    <Field>p4passwd</Field> to the address of your Perforce Server and the credentials of a user that
+                    can read the depots you wish to index.
+                </li>
+                <li>
+                    Add the depot paths you wish to index to the <Field>depots</Field> field. Stream depots are
+                    expanded into one repository per stream. Leave it empty to index every depot.
+                </li>
+            </ol>
+            <p>Depots are converted into Git repositories with git p4, so the p4 CLI must be installed on gitserver.</p>
+        </div>
+    ),
+    editorActions: [
+        {
+            id: 'addDepot',
+            label: 'Add a depot',
+            run: config => {
+                const value = '//depot/'
+                const edits = setProperty(config, ['depots', -1], value, defaultFormattingOptions)
+                return { edits, selectText: value }
+            },
+        },
+    ],
+}
 const PHABRICATOR_SERVICE: AddExternalServiceOptions = {
     kind: GQL.ExternalServiceKind.PHABRICATOR,
     title: 'Phabricator connection',
@@ -1056,6 +1127,8 @@ export const codeHostExternalServices: Record<string, AddExternalServiceOptions>
     bitbucketserver: BITBUCKET_SERVER,
     aws_codecommit: AWS_CODE_COMMIT,
     gitolite: GITOLITE,
+    manifest: MANIFEST,
+    perforce: PERFORCE,
     git: GENERIC_GIT,
 }
 
@@ -1074,6 +1147,8 @@ export const defaultExternalServices: Record<GQL.ExternalServiceKind, AddExterna
     [GQL.ExternalServiceKind.BITBUCKETSERVER]: BITBUCKET_SERVER,
     [GQL.ExternalServiceKind.GITLAB]: GITLAB_DOTCOM,
     [GQL.ExternalServiceKind.GITOLITE]: GITOLITE,
+    [GQL.ExternalServiceKind.MANIFEST]: MANIFEST,
+    [GQL.ExternalServiceKind.PERFORCE]: PERFORCE,
     [GQL.ExternalServiceKind.PHABRICATOR]: PHABRICATOR_SERVICE,
     [GQL.ExternalServiceKind.OTHER]: GENERIC_GIT,
     [GQL.ExternalServiceKind.AWSCODECOMMIT]: AWS_CODE_COMMIT,
@@ -24,6 +24,12 @@ const initSite = async (args: SignUpArgs): Promise<void> => {
     if (args.requestedTrial) {
         submitTrialRequest(args.email)
     }
+    const text = await resp.text()
+    if (text && (JSON.parse(text) as { mfaEnrollmentRequired?: boolean }).mfaEnrollmentRequired) {
+        // The site admin must set up two-factor authentication while signing in.
+        window.location.replace('/sign-in?returnTo=%2Fsite-admin')
+        return
+    }
     window.location.replace('/site-admin')
 }
 
@@ -0,0 +1,379 @@
+import { LoadingSpinner } from '@sourcegraph/react-loading-spinner'
+import * as React from 'react'
+import { RouteComponentProps } from 'react-router'
+import { Observable, Subject, Subscription } from 'rxjs'
+import { catchError, concatMap, map, startWith, switchMap, tap } from 'rxjs/operators'
+import * as GQL from '../../../../../shared/src/graphql/schema'
+import { asError, ErrorLike, isErrorLike } from '../../../../../shared/src/util/errors'
+import { PasswordInput } from '../../../auth/SignInSignUpCommon'
+import { createCredential, isWebAuthnSupported } from '../../../auth/webauthn'
+import { ErrorAlert } from '../../../components/alerts'
+import { Form } from '../../../components/Form'
+import { PageTitle } from '../../../components/PageTitle'
+import { Timestamp } from '../../../components/time/Timestamp'
+import { eventLogger } from '../../../tracking/eventLogger'
+import {
+    beginSecurityKeyRegistration,
+    beginTOTPEnrollment,
+    confirmTOTPEnrollment,
+    deleteSecurityKey,
+    disableTOTP,
+    fetchUserMFA,
+    finishSecurityKeyRegistration,
+    generateRecoveryCodes,
+} from '../backend'
+
+interface Props extends RouteComponentProps<{}> {
+    user: GQL.IUser
+    authenticatedUser: GQL.IUser
+}
+
+interface State {
+    /** The user's two-factor authentication settings, undefined while loading, or an error. */
+    mfaOrError?: GQL.IUserMFA | ErrorLike
+
+    loading: boolean
+    error?: Error
+
+    /** The user's password, which is required to remove a second factor or generate recovery codes. */
+    password: string
+
+    /** The secret of the authenticator app being set up, if any. */
+    totpEnrollment?: GQL.IBeginTOTPEnrollmentResult
+    code: string
+
+    securityKeyName: string
+
+    /** New recovery codes to show to the user (only once). */
+    recoveryCodes?: string[]
+}
+
+/**
+ * A page for users to manage the second factors they sign in with (and for site admins to remove a
+ * user's second factors, e.g. if the user lost them).
+ */
+export class UserSettingsMFAPage extends React.Component<Props, State> {
+    public state: State = {
+        loading: false,
+        password: '',
+        code: '',
+        securityKeyName: '',
+    }
+
+    private refreshes = new Subject<void>()
+    private actions = new Subject<() => Observable<string[] | null | void>>()
+    private subscriptions = new Subscription()
+
+    public componentDidMount(): void {
+        eventLogger.logViewEvent('UserSettingsMFA')
+        this.subscriptions.add(
+            this.refreshes
+                .pipe(
+                    startWith(undefined),
+                    switchMap(() =>
+                        fetchUserMFA(this.props.user.id).pipe(catchError(error => [asError(error) as ErrorLike]))
+                    )
+                )
+                .subscribe(mfaOrError => this.setState({ mfaOrError }))
+        )
+        this.subscriptions.add(
+            this.actions
+                .pipe(
+                    tap(() => this.setState({ loading: true, error: undefined })),
+                    concatMap(action =>
+                        action().pipe(
+                            tap(recoveryCodes =>
+                                this.setState({
+                                    loading: false,
+                                    password: '',
+                                    recoveryCodes: recoveryCodes || this.state.recoveryCodes,
+                                })
+                            ),
+                            tap(() => this.refreshes.next()),
+                            catchError(error => {
+                                this.setState({ loading: false, error: asError(error) })
+                                return []
+                            })
+                        )
+                    )
+                )
+                .subscribe()
+        )
+    }
+
+    public componentWillUnmount(): void {
+        this.subscriptions.unsubscribe()
+    }
+
+    public render(): JSX.Element | null {
+        const { mfaOrError } = this.state
+        const isSelf = this.props.authenticatedUser.id === this.props.user.id
+        return (
+            <div className="user-settings-mfa-page">
+                <PageTitle title="Two-factor authentication" />
+                <h2>Two-factor authentication</h2>
+                <p>
+                    A second factor protects the account if its password is compromised. When signing in with a
+                    password, you must also enter a code from your authenticator app or use your security key.
+                </p>
+                {mfaOrError === undefined ? (
+                    <LoadingSpinner className="icon-inline" />
+                ) : isErrorLike(mfaOrError) ? (
+                    <ErrorAlert className="mb-3" error={mfaOrError} />
+                ) : !mfaOrError.available ? (
+                    <div className="alert alert-info">
+                        Two-factor authentication is only available when signing in with a username and password.
+                    </div>
+                ) : (
+                    <>
+                        {mfaOrError.required && !mfaOrError.totpEnabled && mfaOrError.securityKeys.length === 0 && (
+                            <div className="alert alert-warning">
+                                This site requires two-factor authentication. You will be asked to set up an
+                                authenticator app the next time you sign in.
+                            </div>
+                        )}
+                        {this.state.error && <ErrorAlert className="mb-3" error={this.state.error} />}
+                        {this.state.recoveryCodes && (
+                            <div className="alert alert-success">
+                                <p>
+                                    Save these recovery codes somewhere safe. Each of them can be used once to sign in
+                                    if you lose access to your second factors. They won't be shown again.
+                                </p>
+                                <pre className="user-select-all mb-0">{this.state.recoveryCodes.join('\n')}</pre>
+                            </div>
+                        )}
+                        {isSelf && (
+                            <div className="form-group">
+                                <label>Password</label>
+                                <PasswordInput
+                                    value={this.state.password}
+                                    onChange={this.onPasswordFieldChange}
+                                    disabled={this.state.loading}
+                                    name="password"
+                                    placeholder=" "
+                                    autoComplete="current-password"
+                                />
+                                <small className="form-text text-muted">
+                                    Required to remove a second factor or to generate new recovery codes.
+                                </small>
+                            </div>
+                        )}
+                        {this.renderTOTP(mfaOrError, isSelf)}
+                        {this.renderSecurityKeys(mfaOrError, isSelf)}
+                        {isSelf && (mfaOrError.totpEnabled || mfaOrError.securityKeys.length > 0) && (
+                            <>
+                                <h3 className="mt-4">Recovery codes</h3>
+                                <p>
+                                    {mfaOrError.recoveryCodesRemaining} unused recovery{' '}
+                                    {mfaOrError.recoveryCodesRemaining === 1 ? 'code' : 'codes'}.
+                                </p>
+                                <button
+                                    type="button"
+                                    className="btn btn-secondary"
+                                    onClick={this.generateRecoveryCodes}
+                                    disabled={this.state.loading || !this.state.password}
+                                >
+                                    Generate new recovery codes
+                                </button>
+                            </>
+                        )}
+                        {this.state.loading && (
+                            <div className="mt-2">
+                                <LoadingSpinner className="icon-inline" />
+                            </div>
+                        )}
+                    </>
+                )}
+            </div>
+        )
+    }
+
+    private renderTOTP(mfa: GQL.IUserMFA, isSelf: boolean): JSX.Element {
+        const { totpEnrollment } = this.state
+        return (
+            <>
+                <h3 className="mt-4">Authenticator app</h3>
+                {mfa.totpEnabled ? (
+                    <>
+                        <p>An authenticator app is set up.</p>
+                        <button
+                            type="button"
+                            className="btn btn-danger"
+                            onClick={this.disableTOTP}
+                            disabled={this.state.loading || (isSelf && !this.state.password)}
+                        >
+                            Remove authenticator app
+                        </button>
+                    </>
+                ) : !isSelf ? (
+                    <p>No authenticator app is set up.</p>
+                ) : totpEnrollment ? (
+                    <Form onSubmit={this.confirmTOTPEnrollment}>
+                        <p>
+                            Add this secret to your authenticator app (or{' '}
+                            <a href={totpEnrollment.url}>open it in your authenticator app</a>) and enter the code it
+                            shows.
+                        </p>
+                        <p>
+                            <code className="user-select-all">{totpEnrollment.secret}</code>
+                        </p>
+                        <div className="form-inline">
+                            <input
+                                type="text"
+                                className="form-control mr-2"
+                                placeholder="Code"
+                                value={this.state.code}
+                                onChange={this.onCodeFieldChange}
+                                required={true}
+                                disabled={this.state.loading}
+                                autoComplete="one-time-code"
+                            />
+                            <button type="submit" className="btn btn-primary" disabled={this.state.loading}>
+                                Verify
+                            </button>
+                        </div>
+                    </Form>
+                ) : (
+                    <button
+                        type="button"
+                        className="btn btn-primary"
+                        onClick={this.beginTOTPEnrollment}
+                        disabled={this.state.loading}
+                    >
+                        Set up authenticator app
+                    </button>
+                )}
+            </>
+        )
+    }
+
+    private renderSecurityKeys(mfa: GQL.IUserMFA, isSelf: boolean): JSX.Element {
+        return (
+            <>
+                <h3 className="mt-4">Security keys</h3>
+                {mfa.securityKeys.length === 0 ? (
+                    <p>No security keys are registered.</p>
+                ) : (
+                    <ul className="list-group mb-3">
+                        {mfa.securityKeys.map(securityKey => (
+                            <li
+                                key={securityKey.id}
+                                className="list-group-item py-2 d-flex align-items-center justify-content-between"
+                            >
+                                <div>
+                                    <strong>{securityKey.name}</strong>{' '}
+                                    <small className="text-muted">
+                                        added <Timestamp date={securityKey.createdAt} />
+                                        {securityKey.lastUsedAt && (
+                                            <>
+                                                , last used <Timestamp date={securityKey.lastUsedAt} />
+                                            </>
+                                        )}
+                                    </small>
+                                </div>
+                                <button
+                                    type="button"
+                                    className="btn btn-sm btn-danger"
+                                    onClick={() => this.deleteSecurityKey(securityKey)}
+                                    disabled={this.state.loading || (isSelf && !this.state.password)}
+                                >
+                                    Remove
+                                </button>
+                            </li>
+                        ))}
+                    </ul>
+                )}
+                {isSelf &&
+                    (isWebAuthnSupported() ? (
+                        <Form className="form-inline" onSubmit={this.registerSecurityKey}>
+                            <input
+                                type="text"
+                                className="form-control mr-2"
+                                placeholder="Security key name"
+                                value={this.state.securityKeyName}
+                                onChange={this.onSecurityKeyNameFieldChange}
+                                required={true}
+                                disabled={this.state.loading}
+                            />
+                            <button type="submit" className="btn btn-primary" disabled={this.state.loading}>
+                                Add security key
+                            </button>
+                        </Form>
+                    ) : (
+                        <p className="text-muted">Your browser doesn't support security keys.</p>
+                    ))}
+            </>
+        )
+    }
+
+    private onPasswordFieldChange = (e: React.ChangeEvent<HTMLInputElement>): void => {
+        this.setState({ password: e.target.value })
+    }
+
+    private onCodeFieldChange = (e: React.ChangeEvent<HTMLInputElement>): void => {
+        this.setState({ code: e.target.value })
+    }
+
+    private onSecurityKeyNameFieldChange = (e: React.ChangeEvent<HTMLInputElement>): void => {
+        this.setState({ securityKeyName: e.target.value })
+    }
+
+    private beginTOTPEnrollment = (): void => {
+        this.actions.next(() =>
+            beginTOTPEnrollment().pipe(
+                map(totpEnrollment => {
+                    this.setState({ totpEnrollment, code: '' })
+                })
+            )
+        )
+    }
+
+    private confirmTOTPEnrollment = (event: React.FormEvent<HTMLFormElement>): void => {
+        event.preventDefault()
+        this.actions.next(() =>
+            confirmTOTPEnrollment(this.state.code.replace(/\s/g, '')).pipe(
+                tap(() => {
+                    eventLogger.log('TOTPEnabled')
+                    this.setState({ totpEnrollment: undefined, code: '' })
+                })
+            )
+        )
+    }
+
+    private disableTOTP = (): void => {
+        if (!window.confirm('Remove the authenticator app?')) {
+            return
+        }
+        this.actions.next(() => disableTOTP(this.props.user.id, this.state.password || null))
+    }
+
+    private registerSecurityKey = (event: React.FormEvent<HTMLFormElement>): void => {
+        event.preventDefault()
+        const name = this.state.securityKeyName
+        this.actions.next(() =>
+            beginSecurityKeyRegistration().pipe(
+                concatMap(options => createCredential(options)),
+                concatMap(response => finishSecurityKeyRegistration(name, response)),
+                map(({ recoveryCodes }) => {
+                    eventLogger.log('SecurityKeyAdded')
+                    this.setState({ securityKeyName: '' })
+                    return recoveryCodes
+                })
+            )
+        )
+    }
+
+    private deleteSecurityKey = (securityKey: GQL.ISecurityKey): void => {
+        if (!window.confirm(`Remove the security key ${securityKey.name}?`)) {
+            return
+        }
+        this.actions.next(() => deleteSecurityKey(this.props.user.id, securityKey.id, this.state.password || null))
+    }
+
+    private generateRecoveryCodes = (): void => {
+        if (!window.confirm('Generate new recovery codes? Your current recovery codes will stop working.')) {
+            return
+        }
+        this.actions.next(() => generateRecoveryCodes(this.state.password))
+    }
+}
@@ -3,7 +3,7 @@ import { map } from 'rxjs/operators'
 import { gql, dataOrThrowErrors } from '../../../../shared/src/graphql/graphql'
 import * as GQL from '../../../../shared/src/graphql/schema'
 import { createAggregateError } from '../../../../shared/src/util/errors'
-import { mutateGraphQL } from '../../backend/graphql'
+import { mutateGraphQL, queryGraphQL } from '../../backend/graphql'
 import { eventLogger } from '../../tracking/eventLogger'
 
 interface UpdateUserOptions {
@@ -151,3 +151,163 @@ export function logEvent(event: string, eventProperties?: any): void {
         // eslint-disable-next-line rxjs/no-ignored-subscription
         .subscribe()
 }
+
+/**
+ * Fetches the two-factor authentication settings of a user.
+ */
+export function fetchUserMFA(user: GQL.ID): Observable<GQL.IUserMFA> {
+    return queryGraphQL(
+        gql`
+            query UserMFA($user: ID!) {
+                node(id: $user) {
+                    ... on User {
+                        mfa {
+                            available
+                            required
+                            totpEnabled
+                            securityKeys {
+                                id
+                                name
+                                createdAt
+                                lastUsedAt
+                            }
+                            recoveryCodesRemaining
+                        }
+                    }
+                }
+            }
+        `,
+        { user }
+    ).pipe(
+        map(dataOrThrowErrors),
+        map(data => {
+            if (!data.node) {
+                throw new Error('User not found')
+            }
+            return (data.node as GQL.IUser).mfa
+        })
+    )
+}
+
+export function beginTOTPEnrollment(): Observable<GQL.IBeginTOTPEnrollmentResult> {
+    return mutateGraphQL(
+        gql`
+            mutation BeginTOTPEnrollment {
+                beginTOTPEnrollment {
+                    secret
+                    url
+                }
+            }
+        `
+    ).pipe(
+        map(dataOrThrowErrors),
+        map(data => data.beginTOTPEnrollment)
+    )
+}
+
+/**
+ * Completes setting up an authenticator app, given a code from the app. It returns the user's new
+ * recovery codes (if the user had no other second factor).
+ */
+export function confirmTOTPEnrollment(code: string): Observable<string[] | null> {
+    return mutateGraphQL(
+        gql`
+            mutation ConfirmTOTPEnrollment($code: String!) {
+                confirmTOTPEnrollment(code: $code) {
+                    recoveryCodes
+                }
+            }
+        `,
+        { code }
+    ).pipe(
+        map(dataOrThrowErrors),
+        map(data => data.confirmTOTPEnrollment.recoveryCodes)
+    )
+}
+
+export function disableTOTP(user: GQL.ID, password: string | null): Observable<void> {
+    return mutateGraphQL(
+        gql`
+            mutation DisableTOTP($user: ID!, $password: String) {
+                disableTOTP(user: $user, password: $password) {
+                    alwaysNil
+                }
+            }
+        `,
+        { user, password }
+    ).pipe(
+        map(dataOrThrowErrors),
+        map(() => undefined)
+    )
+}
+
+export function generateRecoveryCodes(password: string): Observable<string[] | null> {
+    return mutateGraphQL(
+        gql`
+            mutation GenerateRecoveryCodes($password: String!) {
+                generateRecoveryCodes(password: $password) {
+                    recoveryCodes
+                }
+            }
+        `,
+        { password }
+    ).pipe(
+        map(dataOrThrowErrors),
+        map(data => data.generateRecoveryCodes.recoveryCodes)
+    )
+}
+
+/**
+ * Starts registering a security key. It returns the publicKey options for
+ * navigator.credentials.create (with binary values encoded as base64url strings).
+ */
+export function beginSecurityKeyRegistration(): Observable<any> {
+    return mutateGraphQL(
+        gql`
+            mutation BeginSecurityKeyRegistration {
+                beginSecurityKeyRegistration
+            }
+        `
+    ).pipe(
+        map(dataOrThrowErrors),
+        map(data => data.beginSecurityKeyRegistration)
+    )
+}
+
+export function finishSecurityKeyRegistration(
+    name: string,
+    response: object
+): Observable<GQL.IFinishSecurityKeyRegistrationResult> {
+    return mutateGraphQL(
+        gql`
+            mutation FinishSecurityKeyRegistration($name: String!, $response: JSONValue!) {
+                finishSecurityKeyRegistration(name: $name, response: $response) {
+                    securityKey {
+                        id
+                    }
+                    recoveryCodes
+                }
+            }
+        `,
+        { name, response }
+    ).pipe(
+        map(dataOrThrowErrors),
+        map(data => data.finishSecurityKeyRegistration)
+    )
+}
+
+export function deleteSecurityKey(user: GQL.ID, securityKey: GQL.ID, password: string | null): Observable<void> {
+    return mutateGraphQL(
+        gql`
+            mutation DeleteSecurityKey($user: ID!, $securityKey: ID!, $password: String) {
+                deleteSecurityKey(user: $user, securityKey: $securityKey, password: $password) {
+                    alwaysNil
+                }
+            }
+        `,
+        { user, securityKey, password }
+    ).pipe(
+        map(dataOrThrowErrors),
+        map(() => undefined)
+    )
+}
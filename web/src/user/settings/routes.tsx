@@ -37,6 +37,11 @@ export const userSettingsAreaRoutes: readonly UserSettingsAreaRoute[] = [
         exact: true,
         render: lazyComponent(() => import('./auth/UserSettingsPasswordPage'), 'UserSettingsPasswordPage'),
     },
+    {
+        path: '/two-factor',
+        exact: true,
+        render: lazyComponent(() => import('./auth/UserSettingsMFAPage'), 'UserSettingsMFAPage'),
+    },
     {
         path: '/emails',
         exact: true,